@@ -34,6 +34,26 @@ securityContext:
             - ALL
 {{- end -}}
 
+{{/* Volume holding the key encryption key of the local key management service, mounted at the path of its key ID */}}
+{{- define "osm.caKeyEncryption.localKeyVolume" -}}
+{{- if not (isAbs .Values.osm.caKeyEncryption.keyID) -}}
+{{- fail "osm.caKeyEncryption.keyID must be an absolute file path when osm.caKeyEncryption.kms is local" -}}
+{{- end -}}
+- name: ca-key-encryption-key
+  secret:
+    secretName: {{ required "osm.caKeyEncryption.localKeySecretName is required when osm.caKeyEncryption.kms is local" .Values.osm.caKeyEncryption.localKeySecretName }}
+    items:
+      - key: kek
+        path: {{ base .Values.osm.caKeyEncryption.keyID }}
+{{- end -}}
+
+{{/* Mount of the key encryption key of the local key management service */}}
+{{- define "osm.caKeyEncryption.localKeyVolumeMount" -}}
+- name: ca-key-encryption-key
+  mountPath: {{ dir .Values.osm.caKeyEncryption.keyID }}
+  readOnly: true
+{{- end -}}
+
 {{/* Resource validator webhook name */}}
 {{- define "osm.validatorWebhookConfigName" -}}
 {{- $validatorWebhookConfigName := printf "osm-validator-mesh-%s" .Values.osm.meshName -}}
@@ -102,3 +122,21 @@ securityContext:
 {{- printf "%s/%s@%s" .Values.osm.image.registry .Values.osm.image.name.osmHealthcheck .Values.osm.image.digest.osmHealthcheck -}}
 {{- end -}}
 {{- end -}}
+
+{{/* osm-workload-api image */}}
+{{- define "osmWorkloadAPI.image" -}}
+{{- if .Values.osm.image.tag -}}
+{{- printf "%s/%s:%s" .Values.osm.image.registry .Values.osm.image.name.osmWorkloadAPI .Values.osm.image.tag -}}
+{{- else -}}
+{{- printf "%s/%s@%s" .Values.osm.image.registry .Values.osm.image.name.osmWorkloadAPI .Values.osm.image.digest.osmWorkloadAPI -}}
+{{- end -}}
+{{- end -}}
+
+{{/* osm-cni image */}}
+{{- define "osmCNI.image" -}}
+{{- if .Values.osm.image.tag -}}
+{{- printf "%s/%s:%s" .Values.osm.image.registry .Values.osm.image.name.osmCNI .Values.osm.image.tag -}}
+{{- else -}}
+{{- printf "%s/%s@%s" .Values.osm.image.registry .Values.osm.image.name.osmCNI .Values.osm.image.digest.osmCNI -}}
+{{- end -}}
+{{- end -}}
@@ -0,0 +1,341 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+	kubeFake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	"github.com/openservicemesh/osm/pkg/constants"
+	osmConfigClient "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned"
+	configFake "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned/fake"
+	"github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned/scheme"
+	"github.com/openservicemesh/osm/pkg/injector"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/messaging"
+)
+
+const injectDescription = `
+This command injects the Envoy sidecar into the pods and pod templates of the
+Deployments, StatefulSets, DaemonSets, ReplicaSets, Jobs, and CronJobs of the
+given manifests, and writes the injected manifests to stdout. Other manifests
+are written as is.
+
+The sidecar is configured from the MeshConfig and SidecarProfiles of the
+cluster, or from the file given with --mesh-config. Workloads are injected
+when the mesh would inject their pods: their namespace is monitored by the
+mesh and enabled for sidecar injection, or their pods are annotated for it.
+Namespaces are assumed to be monitored and enabled for sidecar injection
+when the configuration is read from a file.
+
+The bootstrap config Secret of the sidecar holds a certificate issued to each
+pod, so it is not rendered. osm-injector creates it when the pods of the
+injected workloads are created, so their namespace must be monitored by the
+mesh.
+`
+
+const injectExample = `
+# Inject the sidecar into the workloads of deployment.yaml, with the mesh configuration of the cluster
+osm inject -f deployment.yaml
+
+# Inject the sidecar into the workloads read from stdin, with the MeshConfig and SidecarProfiles of mesh-config.yaml
+kustomize build . | osm inject -f - --mesh-config mesh-config.yaml
+`
+
+type injectCmd struct {
+	in  io.Reader
+	out io.Writer
+
+	filename       string
+	meshConfigFile string
+	meshName       string
+	namespace      string
+	enableCNI      bool
+	cniImage       string
+	pullPolicy     string
+
+	// clientSet and configClient access the cluster, they are nil when the configuration is read from a file
+	clientSet    kubernetes.Interface
+	configClient osmConfigClient.Interface
+}
+
+func newInjectCmd(in io.Reader, out io.Writer) *cobra.Command {
+	inject := &injectCmd{
+		in:  in,
+		out: out,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "inject",
+		Short: "inject the sidecar into workload manifests",
+		Long:  injectDescription,
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if !settings.Verbose() {
+				// Logs of the injection are only relevant when troubleshooting
+				_ = logger.SetLogLevel("error")
+			}
+			if inject.enableCNI && inject.cniImage == "" {
+				return fmt.Errorf("--cni-image is required with --enable-cni")
+			}
+
+			if inject.meshConfigFile == "" {
+				config, err := settings.RESTClientGetter().ToRESTConfig()
+				if err != nil {
+					return fmt.Errorf("Error fetching kubeconfig: %w", err)
+				}
+
+				clientset, err := kubernetes.NewForConfig(config)
+				if err != nil {
+					return fmt.Errorf("Could not access Kubernetes cluster, check kubeconfig: %w", err)
+				}
+				inject.clientSet = clientset
+
+				configClient, err := osmConfigClient.NewForConfig(config)
+				if err != nil {
+					return fmt.Errorf("Could not initialize OSM Config client: %w", err)
+				}
+				inject.configClient = configClient
+			}
+
+			return inject.run()
+		},
+		Example: injectExample,
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&inject.filename, "filename", "f", "", "File holding the manifests to inject, or - for stdin")
+	//nolint: errcheck
+	//#nosec G104: Errors unhandled
+	cmd.MarkFlagRequired("filename")
+	f.StringVar(&inject.meshConfigFile, "mesh-config", "", "File holding the MeshConfig and, optionally, SidecarProfiles to inject the sidecar with, instead of those of the cluster")
+	f.StringVar(&inject.meshName, "mesh-name", defaultMeshName, "Name of the service mesh")
+	f.StringVarP(&inject.namespace, "namespace", "n", metav1.NamespaceDefault, "Namespace of the manifests without a namespace")
+	f.BoolVar(&inject.enableCNI, "enable-cni", false, "Whether the traffic of pods is redirected by the OSM CNI plugin, in which case the init container only validates the redirection")
+	f.StringVar(&inject.cniImage, "cni-image", "", "osm-cni image of the init container validating the traffic redirection, required with --enable-cni")
+	f.StringVar(&inject.pullPolicy, "osm-image-pull-policy", string(corev1.PullIfNotPresent), "Pull policy of the images of the containers injected by OSM")
+
+	return cmd
+}
+
+func (cmd *injectCmd) run() error {
+	manifests, err := cmd.readManifests()
+	if err != nil {
+		return err
+	}
+	namespaces, err := manifestNamespaces(manifests, cmd.namespace)
+	if err != nil {
+		return err
+	}
+
+	// The mesh configuration is loaded into in-memory clientsets, on which the controller of osm-injector runs
+	kubeClient := kubeFake.NewSimpleClientset()
+	configClient := configFake.NewSimpleClientset()
+	var meshConfigName string
+	if cmd.configClient != nil {
+		meshConfigName, err = cmd.loadClusterConfig(namespaces, kubeClient, configClient)
+	} else {
+		meshConfigName, err = cmd.loadFileConfig(namespaces, kubeClient, configClient)
+	}
+	if err != nil {
+		return err
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	kubeController, err := k8s.NewClient(settings.Namespace(), meshConfigName, messaging.NewBroker(stop),
+		k8s.WithKubeClient(kubeClient, cmd.meshName),
+		k8s.WithConfigClient(configClient),
+	)
+	if err != nil {
+		return fmt.Errorf("Error loading the mesh configuration: %w", err)
+	}
+
+	inj := injector.NewOfflineInjector(cmd.clientSet, kubeController, cmd.meshName, settings.Namespace(), corev1.PullPolicy(cmd.pullPolicy), cmd.enableCNI, cmd.cniImage)
+	return inj.Inject(bytes.NewReader(manifests), cmd.out, cmd.namespace)
+}
+
+// readManifests returns the manifests of the file to inject
+func (cmd *injectCmd) readManifests() ([]byte, error) {
+	if cmd.filename == "-" {
+		return io.ReadAll(cmd.in)
+	}
+	manifests, err := os.ReadFile(filepath.Clean(cmd.filename))
+	if err != nil {
+		return nil, fmt.Errorf("Error reading manifests: %w", err)
+	}
+	return manifests, nil
+}
+
+// loadClusterConfig loads the MeshConfig and the SidecarProfiles of the cluster, and the given namespaces, into the
+// given clientsets, and returns the name of the MeshConfig
+func (cmd *injectCmd) loadClusterConfig(namespaces []string, kubeClient kubernetes.Interface, configClient osmConfigClient.Interface) (string, error) {
+	osmNamespace := settings.Namespace()
+
+	meshConfig, err := cmd.configClient.ConfigV1alpha2().MeshConfigs(osmNamespace).Get(context.TODO(), defaultOsmMeshConfigName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("Error fetching MeshConfig %s: %w", defaultOsmMeshConfigName, err)
+	}
+	meshConfig.ResourceVersion = ""
+	if _, err := configClient.ConfigV1alpha2().MeshConfigs(osmNamespace).Create(context.TODO(), meshConfig, metav1.CreateOptions{}); err != nil {
+		return "", err
+	}
+
+	profileNamespaces := []string{osmNamespace}
+	for _, ns := range namespaces {
+		if ns == osmNamespace {
+			// Pods are never injected in the OSM namespace
+			continue
+		}
+		profileNamespaces = append(profileNamespaces, ns)
+	}
+	for _, ns := range profileNamespaces {
+		if ns != osmNamespace {
+			namespace, err := cmd.clientSet.CoreV1().Namespaces().Get(context.TODO(), ns, metav1.GetOptions{})
+			if err != nil {
+				return "", fmt.Errorf("Error fetching namespace %s: %w", ns, err)
+			}
+			namespace.ResourceVersion = ""
+			if _, err := kubeClient.CoreV1().Namespaces().Create(context.TODO(), namespace, metav1.CreateOptions{}); err != nil {
+				return "", err
+			}
+		}
+
+		profiles, err := cmd.configClient.ConfigV1alpha2().SidecarProfiles(ns).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return "", fmt.Errorf("Error listing SidecarProfiles in namespace %s: %w", ns, err)
+		}
+		for i := range profiles.Items {
+			profiles.Items[i].ResourceVersion = ""
+			if _, err := configClient.ConfigV1alpha2().SidecarProfiles(ns).Create(context.TODO(), &profiles.Items[i], metav1.CreateOptions{}); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return meshConfig.Name, nil
+}
+
+// loadFileConfig loads the MeshConfig and the SidecarProfiles of the mesh config file into the given clientsets, along
+// with the given namespaces, monitored by the mesh and enabled for sidecar injection, and returns the name of the
+// MeshConfig
+func (cmd *injectCmd) loadFileConfig(namespaces []string, kubeClient kubernetes.Interface, configClient osmConfigClient.Interface) (string, error) {
+	osmNamespace := settings.Namespace()
+
+	f, err := os.Open(filepath.Clean(cmd.meshConfigFile))
+	if err != nil {
+		return "", fmt.Errorf("Error reading mesh config: %w", err)
+	}
+	defer f.Close() //nolint: errcheck,gosec
+
+	var meshConfig *configv1alpha2.MeshConfig
+	decoder := scheme.Codecs.UniversalDeserializer()
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(f))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("Error reading mesh config: %w", err)
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		obj, _, err := decoder.Decode(doc, nil, nil)
+		if err != nil {
+			return "", fmt.Errorf("Error decoding mesh config: %w", err)
+		}
+		switch obj := obj.(type) {
+		case *configv1alpha2.MeshConfig:
+			if meshConfig != nil {
+				return "", fmt.Errorf("Mesh config %s holds more than one MeshConfig", cmd.meshConfigFile)
+			}
+			meshConfig = obj
+			meshConfig.Namespace = osmNamespace
+			if meshConfig.Name == "" {
+				meshConfig.Name = defaultOsmMeshConfigName
+			}
+			if _, err := configClient.ConfigV1alpha2().MeshConfigs(osmNamespace).Create(context.TODO(), meshConfig, metav1.CreateOptions{}); err != nil {
+				return "", err
+			}
+		case *configv1alpha2.SidecarProfile:
+			if obj.Namespace == "" {
+				obj.Namespace = osmNamespace
+			}
+			if _, err := configClient.ConfigV1alpha2().SidecarProfiles(obj.Namespace).Create(context.TODO(), obj, metav1.CreateOptions{}); err != nil {
+				return "", err
+			}
+		default:
+			return "", fmt.Errorf("Mesh config %s holds a %s, only MeshConfig and SidecarProfile objects are supported", cmd.meshConfigFile, obj.GetObjectKind().GroupVersionKind().Kind)
+		}
+	}
+	if meshConfig == nil {
+		return "", fmt.Errorf("Mesh config %s holds no MeshConfig", cmd.meshConfigFile)
+	}
+
+	for _, ns := range namespaces {
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        ns,
+				Labels:      map[string]string{constants.OSMKubeResourceMonitorAnnotation: cmd.meshName},
+				Annotations: map[string]string{constants.SidecarInjectionAnnotation: "enabled"},
+			},
+		}
+		if _, err := kubeClient.CoreV1().Namespaces().Create(context.TODO(), namespace, metav1.CreateOptions{}); err != nil {
+			return "", err
+		}
+	}
+
+	return meshConfig.Name, nil
+}
+
+// manifestNamespaces returns the namespaces of the given manifests, the given default namespace being the namespace
+// of manifests without a namespace
+func manifestNamespaces(manifests []byte, defaultNamespace string) ([]string, error) {
+	var namespaces []string
+	seen := make(map[string]bool)
+
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(manifests)))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return namespaces, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Error reading manifests: %w", err)
+		}
+
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(doc, &obj.Object); err != nil {
+			return nil, fmt.Errorf("Error decoding manifest: %w", err)
+		}
+		if obj.Object == nil || obj.GetKind() == "Namespace" {
+			continue
+		}
+		ns := obj.GetNamespace()
+		if ns == "" {
+			ns = defaultNamespace
+		}
+		if !seen[ns] {
+			seen[ns] = true
+			namespaces = append(namespaces, ns)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	"github.com/openservicemesh/osm/pkg/constants"
+	fakeConfig "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned/fake"
+)
+
+const injectManifests = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: bookstore
+  namespace: bookstore
+spec:
+  selector:
+    matchLabels:
+      app: bookstore
+  template:
+    metadata:
+      labels:
+        app: bookstore
+    spec:
+      containers:
+      - name: bookstore
+        image: bookstore:v1
+`
+
+const injectMeshConfig = `apiVersion: config.openservicemesh.io/v1alpha2
+kind: MeshConfig
+metadata:
+  name: osm-mesh-config
+spec:
+  sidecar:
+    logLevel: error
+    envoyImage: envoy:v1
+    initContainerImage: init:v1
+---
+apiVersion: config.openservicemesh.io/v1alpha2
+kind: SidecarProfile
+metadata:
+  name: bookstore
+  namespace: bookstore
+spec:
+  sidecar:
+    logLevel: debug
+`
+
+func TestInjectRun(t *testing.T) {
+	dir := t.TempDir()
+	manifestsFile := filepath.Join(dir, "manifests.yaml")
+	meshConfigFile := filepath.Join(dir, "mesh-config.yaml")
+	tassert.NoError(t, os.WriteFile(manifestsFile, []byte(injectManifests), 0600))
+	tassert.NoError(t, os.WriteFile(meshConfigFile, []byte(injectMeshConfig), 0600))
+
+	testCases := []struct {
+		name             string
+		filename         string
+		in               string
+		meshConfigFile   string
+		clusterObjects   []runtime.Object
+		configObjects    []runtime.Object
+		expectErr        bool
+		expectInjected   bool
+		expectedLogLevel string
+	}{
+		{
+			name:             "mesh config from a file",
+			filename:         manifestsFile,
+			meshConfigFile:   meshConfigFile,
+			expectInjected:   true,
+			expectedLogLevel: "debug",
+		},
+		{
+			name:             "manifests from stdin",
+			filename:         "-",
+			in:               injectManifests,
+			meshConfigFile:   meshConfigFile,
+			expectInjected:   true,
+			expectedLogLevel: "debug",
+		},
+		{
+			name:     "mesh config from the cluster",
+			filename: manifestsFile,
+			clusterObjects: []runtime.Object{
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "bookstore",
+						Labels:      map[string]string{constants.OSMKubeResourceMonitorAnnotation: defaultMeshName},
+						Annotations: map[string]string{constants.SidecarInjectionAnnotation: "enabled"},
+					},
+				},
+			},
+			configObjects: []runtime.Object{
+				&configv1alpha2.MeshConfig{
+					ObjectMeta: metav1.ObjectMeta{Namespace: settings.Namespace(), Name: defaultOsmMeshConfigName},
+					Spec: configv1alpha2.MeshConfigSpec{
+						Sidecar: configv1alpha2.SidecarSpec{
+							LogLevel:           "warn",
+							EnvoyImage:         "envoy:v1",
+							InitContainerImage: "init:v1",
+						},
+					},
+				},
+			},
+			expectInjected:   true,
+			expectedLogLevel: "warn",
+		},
+		{
+			name:     "namespace of the cluster not monitored",
+			filename: manifestsFile,
+			clusterObjects: []runtime.Object{
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "bookstore"}},
+			},
+			configObjects: []runtime.Object{
+				&configv1alpha2.MeshConfig{
+					ObjectMeta: metav1.ObjectMeta{Namespace: settings.Namespace(), Name: defaultOsmMeshConfigName},
+				},
+			},
+			expectInjected: false,
+		},
+		{
+			name:      "namespace not found in the cluster",
+			filename:  manifestsFile,
+			expectErr: true,
+			configObjects: []runtime.Object{
+				&configv1alpha2.MeshConfig{
+					ObjectMeta: metav1.ObjectMeta{Namespace: settings.Namespace(), Name: defaultOsmMeshConfigName},
+				},
+			},
+		},
+		{
+			name:           "mesh config file without a MeshConfig",
+			filename:       manifestsFile,
+			meshConfigFile: manifestsFile,
+			expectErr:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			out := new(bytes.Buffer)
+			cmd := &injectCmd{
+				in:             strings.NewReader(tc.in),
+				out:            out,
+				filename:       tc.filename,
+				meshConfigFile: tc.meshConfigFile,
+				meshName:       defaultMeshName,
+				namespace:      metav1.NamespaceDefault,
+				pullPolicy:     string(corev1.PullIfNotPresent),
+			}
+			if tc.meshConfigFile == "" {
+				cmd.clientSet = fake.NewSimpleClientset(tc.clusterObjects...)
+				cmd.configClient = fakeConfig.NewSimpleClientset(tc.configObjects...)
+			}
+
+			err := cmd.run()
+			assert.Equal(tc.expectErr, err != nil, err)
+			if tc.expectErr {
+				return
+			}
+
+			if !tc.expectInjected {
+				assert.Equal(injectManifests, out.String())
+				return
+			}
+
+			var deployment appsv1.Deployment
+			assert.NoError(yaml.Unmarshal(out.Bytes(), &deployment))
+			containers := deployment.Spec.Template.Spec.Containers
+			assert.Len(containers, 2)
+			assert.Equal(constants.EnvoyContainerName, containers[1].Name)
+			assert.Equal("envoy:v1", containers[1].Image)
+			assert.Equal([]string{"--log-level", tc.expectedLogLevel}, containers[1].Args[:2])
+			assert.Len(deployment.Spec.Template.Spec.InitContainers, 1)
+		})
+	}
+}
@@ -39,14 +39,16 @@ func newRootCmd(config *action.Configuration, stdin io.Reader, stdout io.Writer,
 	cmd.AddCommand(
 		newMeshCmd(config, stdin, stdout),
 		newEnvCmd(stdout, stderr),
+		newInjectCmd(stdin, stdout),
 		newNamespaceCmd(stdout),
 		newMetricsCmd(stdout),
 		newVersionCmd(stdout),
-		newProxyCmd(config, stdout),
+		newProxyCmd(config, stdout, stderr),
 		newPolicyCmd(stdout, stderr),
 		newSupportCmd(config, stdout, stderr),
 		newUninstallCmd(config, stdin, stdout),
 		newVerifyCmd(stdout, stderr),
+		newVMCmd(stdout),
 		newAlphaCmd(stdout),
 	)
 
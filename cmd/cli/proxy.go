@@ -22,6 +22,7 @@ of the sidecar proxy on pods.
 
 type proxyAdminCmd struct {
 	out        io.Writer
+	errOut     io.Writer
 	config     *rest.Config
 	clientSet  kubernetes.Interface
 	query      string
@@ -32,15 +33,18 @@ type proxyAdminCmd struct {
 	sigintChan chan os.Signal
 }
 
-func newProxyCmd(config *action.Configuration, out io.Writer) *cobra.Command {
+func newProxyCmd(config *action.Configuration, out io.Writer, errOut io.Writer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "proxy",
 		Short: "sidecar proxy operations",
 		Long:  proxyCmdDescription,
 		Args:  cobra.NoArgs,
 	}
-	cmd.AddCommand(newProxyGetCmd(config, out))
+	cmd.AddCommand(newProxyGetCmd(config, out, errOut))
 	cmd.AddCommand(newProxySetCmd(config, out))
+	cmd.AddCommand(newProxyProfileCmd(out))
+	cmd.AddCommand(newProxyUpgradeCmd(out))
+	cmd.AddCommand(newProxyConfigDumpCmd(out))
 
 	return cmd
 }
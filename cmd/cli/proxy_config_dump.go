@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate/providers"
+	"github.com/openservicemesh/osm/pkg/compute/file"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/generator"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/messaging"
+	"github.com/openservicemesh/osm/pkg/models"
+)
+
+const configDumpCmdDescription = `
+This command generates the Envoy configuration of the sidecars of the pods
+described by a directory of YAML manifests, without a cluster. The manifests
+describe the Services, EndpointSlices, ServiceAccounts, Pods, SMI and OSM
+policies and MeshConfig of the mesh as they would be applied with 'kubectl'.
+The manifests of subdirectories are read as well. Objects without a namespace
+are in the 'default' namespace, and every namespace other than the OSM namespace
+is part of the mesh.
+
+The configuration is generated for the pods with the 'osm-proxy-uuid' label, as
+osm-controller generates it for their sidecars, and is written as one JSON file
+per pod, at <output-dir>/<namespace>/<pod>.json, or to stdout. It holds the
+clusters, endpoints, listeners and route configurations of the sidecar, sorted
+by name, so that the configuration generated for different manifests or
+versions of OSM can be compared, e.g. for review in CI. The secrets of the
+sidecar are left out, as the certificates are issued by a throwaway CA.
+`
+
+const configDumpCmdExample = `
+# Write the configuration of the sidecars of the pods described by the manifests of the 'manifests' directory to the 'config' directory
+osm proxy config-dump --manifests-dir ./manifests --output-dir ./config
+`
+
+// configDumpTypes are the types of the resources written to the config dump, along with the fields they are written to
+var configDumpTypes = []struct {
+	typeURI envoy.TypeURI
+	field   string
+}{
+	{envoy.TypeCDS, "clusters"},
+	{envoy.TypeEDS, "endpoints"},
+	{envoy.TypeLDS, "listeners"},
+	{envoy.TypeRDS, "routeConfigurations"},
+}
+
+type proxyConfigDumpCmd struct {
+	out          io.Writer
+	manifestsDir string
+	outputDir    string
+	trustDomain  string
+	osmNamespace string
+}
+
+func newProxyConfigDumpCmd(out io.Writer) *cobra.Command {
+	dumpCmd := &proxyConfigDumpCmd{
+		out: out,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "config-dump",
+		Short: "generate the configuration of the sidecars of the pods described by manifests",
+		Long:  configDumpCmdDescription,
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			dumpCmd.osmNamespace = settings.Namespace()
+			return dumpCmd.run()
+		},
+		Example: configDumpCmdExample,
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&dumpCmd.manifestsDir, "manifests-dir", "", "Directory of the YAML manifests describing the mesh")
+	f.StringVar(&dumpCmd.outputDir, "output-dir", "", "Directory the configuration of each pod is written to, instead of stdout")
+	f.StringVar(&dumpCmd.trustDomain, "trust-domain", "cluster.local", "Trust domain of the mesh")
+	//nolint: errcheck
+	//#nosec G104
+	cmd.MarkFlagRequired("manifests-dir")
+
+	return cmd
+}
+
+func (cmd *proxyConfigDumpCmd) run() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	msgBroker := messaging.NewBroker(ctx.Done())
+	provider, err := file.NewProvider(cmd.manifestsDir, cmd.osmNamespace, defaultOsmMeshConfigName, msgBroker)
+	if err != nil {
+		return fmt.Errorf("Error loading manifests from directory %s: %w", cmd.manifestsDir, err)
+	}
+
+	certManager, err := providers.NewInMemoryCertificateManager(ctx, cmd.osmNamespace, provider, time.Hour, cmd.trustDomain)
+	if err != nil {
+		return fmt.Errorf("Error creating certificate manager: %w", err)
+	}
+	configGenerator := generator.NewEnvoyConfigGenerator(catalog.NewMeshCatalog(provider, certManager, ctx.Done(), msgBroker), certManager)
+
+	for _, pod := range provider.ListPods() {
+		proxyUUID, ok := pod.Labels[constants.EnvoyUniqueIDLabelName]
+		if !ok {
+			continue
+		}
+		parsedUUID, err := uuid.Parse(proxyUUID)
+		if err != nil {
+			return fmt.Errorf("Invalid %s label of pod %s/%s: %w", constants.EnvoyUniqueIDLabelName, pod.Namespace, pod.Name, err)
+		}
+		serviceAccount := pod.Spec.ServiceAccountName
+		if serviceAccount == "" {
+			serviceAccount = "default"
+		}
+		var addr net.Addr
+		if ip := net.ParseIP(pod.Status.PodIP); ip != nil {
+			addr = &net.IPAddr{IP: ip}
+		}
+		proxy := models.NewProxy(models.KindSidecar, parsedUUID, identity.New(serviceAccount, pod.Namespace), addr, 0)
+
+		resources, err := configGenerator.GenerateConfig(ctx, proxy)
+		if err != nil {
+			return fmt.Errorf("Error generating the configuration of pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+		dump, err := marshalConfigDump(pod.Namespace, pod.Name, proxy, resources)
+		if err != nil {
+			return fmt.Errorf("Error writing the configuration of pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+		if err := cmd.write(pod.Namespace, pod.Name, dump); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// write writes the config dump of the given pod to its file in the output directory, or to stdout
+func (cmd *proxyConfigDumpCmd) write(namespace, name string, dump []byte) error {
+	if cmd.outputDir == "" {
+		_, err := cmd.out.Write(dump)
+		return err
+	}
+
+	dir := filepath.Join(cmd.outputDir, namespace)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("Error creating directory %s: %w", dir, err)
+	}
+	path := filepath.Join(dir, name+".json")
+	if err := os.WriteFile(path, dump, 0600); err != nil {
+		return fmt.Errorf("Error writing file %s: %w", path, err)
+	}
+	fmt.Fprintf(cmd.out, "Wrote the configuration of pod %s/%s to %s\n", namespace, name, path)
+	return nil
+}
+
+// marshalConfigDump returns the config dump of the given resources generated for the given pod, as indented JSON
+// with its resources sorted by name and its fields sorted by key, so that it is the same for the same configuration
+func marshalConfigDump(namespace, name string, proxy *models.Proxy, resources map[string][]types.Resource) ([]byte, error) {
+	dump := map[string]interface{}{
+		"pod":      fmt.Sprintf("%s/%s", namespace, name),
+		"uuid":     proxy.UUID.String(),
+		"identity": proxy.Identity.String(),
+	}
+	for _, dumpType := range configDumpTypes {
+		typeResources := append([]types.Resource(nil), resources[dumpType.typeURI.String()]...)
+		sort.Slice(typeResources, func(i, j int) bool {
+			return cachev3.GetResourceName(typeResources[i]) < cachev3.GetResourceName(typeResources[j])
+		})
+
+		items := make([]interface{}, 0, len(typeResources))
+		for _, resource := range typeResources {
+			// The output of protojson is unstable by design, it is decoded again to be marshalled deterministically
+			b, err := protojson.Marshal(resource)
+			if err != nil {
+				return nil, err
+			}
+			var item interface{}
+			if err := json.Unmarshal(b, &item); err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		dump[dumpType.field] = items
+	}
+
+	b, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
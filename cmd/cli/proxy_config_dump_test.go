@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+const configDumpManifests = `
+apiVersion: config.openservicemesh.io/v1alpha2
+kind: MeshConfig
+metadata:
+  name: osm-mesh-config
+  namespace: osm-system
+spec:
+  traffic:
+    enablePermissiveTrafficPolicyMode: true
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: bookstore
+  namespace: bookstore
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: bookstore
+  namespace: bookstore
+spec:
+  selector:
+    app: bookstore
+  ports:
+  - name: http
+    port: 14001
+    appProtocol: http
+---
+apiVersion: discovery.k8s.io/v1
+kind: EndpointSlice
+metadata:
+  name: bookstore-abcde
+  namespace: bookstore
+  labels:
+    kubernetes.io/service-name: bookstore
+addressType: IPv4
+endpoints:
+- addresses:
+  - 10.0.0.10
+ports:
+- name: http
+  port: 14001
+  protocol: TCP
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: bookstore-1
+  namespace: bookstore
+  labels:
+    app: bookstore
+    osm-proxy-uuid: 6b7e2a44-3d1d-4e2b-a3f6-1c6d2b0b1c11
+spec:
+  serviceAccountName: bookstore
+  containers:
+  - name: bookstore
+    image: bookstore
+status:
+  podIP: 10.0.0.10
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: bookbuyer-1
+  namespace: bookbuyer
+  labels:
+    app: bookbuyer
+    osm-proxy-uuid: 0c3f1a7e-8f2b-4c55-9d1e-5b7a6c4d3e21
+spec:
+  serviceAccountName: bookbuyer
+  containers:
+  - name: bookbuyer
+    image: bookbuyer
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: unmeshed
+  namespace: bookbuyer
+spec:
+  containers:
+  - name: unmeshed
+    image: unmeshed
+`
+
+func TestProxyConfigDumpRun(t *testing.T) {
+	assert := tassert.New(t)
+
+	manifestsDir := t.TempDir()
+	assert.NoError(os.MkdirAll(filepath.Join(manifestsDir, "bookstore"), 0700))
+	assert.NoError(os.WriteFile(filepath.Join(manifestsDir, "bookstore", "bookstore.yaml"), []byte(configDumpManifests), 0600))
+
+	dump := func() (map[string][]byte, string) {
+		outputDir := t.TempDir()
+		out := new(bytes.Buffer)
+		cmd := &proxyConfigDumpCmd{
+			out:          out,
+			manifestsDir: manifestsDir,
+			outputDir:    outputDir,
+			trustDomain:  "cluster.local",
+			osmNamespace: "osm-system",
+		}
+		assert.NoError(cmd.run())
+
+		files := make(map[string][]byte)
+		for _, pod := range []string{"bookstore/bookstore-1", "bookbuyer/bookbuyer-1"} {
+			b, err := os.ReadFile(filepath.Join(outputDir, pod+".json"))
+			assert.NoError(err)
+			files[pod] = b
+		}
+		_, err := os.Stat(filepath.Join(outputDir, "bookbuyer", "unmeshed.json"))
+		assert.True(os.IsNotExist(err))
+		return files, out.String()
+	}
+
+	files, out := dump()
+	assert.Contains(out, "Wrote the configuration of pod bookstore/bookstore-1")
+
+	var config struct {
+		Pod       string                   `json:"pod"`
+		Identity  string                   `json:"identity"`
+		Clusters  []map[string]interface{} `json:"clusters"`
+		Listeners []map[string]interface{} `json:"listeners"`
+		Secrets   []map[string]interface{} `json:"secrets"`
+	}
+	assert.NoError(json.Unmarshal(files["bookbuyer/bookbuyer-1"], &config))
+	assert.Equal("bookbuyer/bookbuyer-1", config.Pod)
+	assert.Equal("bookbuyer.bookbuyer", config.Identity)
+	var clusterNames []interface{}
+	for _, cluster := range config.Clusters {
+		clusterNames = append(clusterNames, cluster["name"])
+	}
+	assert.Contains(clusterNames, "bookstore/bookstore|14001")
+	assert.NotEmpty(config.Listeners)
+	assert.Empty(config.Secrets)
+
+	// The configuration generated for the same manifests is the same
+	again, _ := dump()
+	assert.Equal(files, again)
+}
@@ -1,16 +1,22 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"time"
 
 	"github.com/spf13/cobra"
 	"helm.sh/helm/v3/pkg/action"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
 )
 
 const getCmdDescription = `
@@ -18,6 +24,9 @@ This command will get the Envoy proxy configuration for the given query and pod.
 The query is forwarded as is to the Envoy proxy sidecar.
 Refer to https://www.envoyproxy.io/docs/envoy/latest/operations/admin for the
 list of supported GET queries.
+
+The configuration the proxy recently rejected, as recorded by the OSM controller
+in the pod's events, is reported on stderr.
 `
 
 const getCmdExample = `
@@ -28,9 +37,10 @@ osm proxy get config_dump bookbuyer-5ccf77f46d-rc5mg -n bookbuyer
 osm proxy get clusters bookbuyer-5ccf77f46d-rc5mg -n bookbuyer -f clusters.txt
 `
 
-func newProxyGetCmd(config *action.Configuration, out io.Writer) *cobra.Command {
+func newProxyGetCmd(config *action.Configuration, out io.Writer, errOut io.Writer) *cobra.Command {
 	adminCmd := &proxyAdminCmd{
 		out:        out,
+		errOut:     errOut,
 		sigintChan: make(chan os.Signal, 1),
 	}
 
@@ -53,7 +63,14 @@ func newProxyGetCmd(config *action.Configuration, out io.Writer) *cobra.Command
 				return fmt.Errorf("Could not access Kubernetes cluster, check kubeconfig: %w", err)
 			}
 			adminCmd.clientSet = clientset
-			return adminCmd.run("GET")
+			if err := adminCmd.run("GET"); err != nil {
+				return err
+			}
+			// The query succeeded, so that failing to list the events is only reported
+			if err := adminCmd.printNACKs(); err != nil {
+				fmt.Fprintf(adminCmd.errOut, "%s\n", err)
+			}
+			return nil
 		},
 		Example: getCmdExample,
 	}
@@ -66,3 +83,32 @@ func newProxyGetCmd(config *action.Configuration, out io.Writer) *cobra.Command
 
 	return cmd
 }
+
+// printNACKs prints the configuration the pod's proxy rejected, based on the events recorded by the OSM controller
+func (cmd *proxyAdminCmd) printNACKs() error {
+	eventList, err := cmd.clientSet.CoreV1().Events(cmd.namespace).List(context.Background(), metav1.ListOptions{
+		FieldSelector: fields.Set{"involvedObject.name": cmd.pod, "reason": events.ProxyConfigNACKed}.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("Error listing the events of pod %s/%s: %w", cmd.namespace, cmd.pod, err)
+	}
+
+	var nacks []corev1.Event
+	for _, event := range eventList.Items {
+		if event.InvolvedObject.Kind == "Pod" && event.InvolvedObject.Name == cmd.pod && event.Reason == events.ProxyConfigNACKed {
+			nacks = append(nacks, event)
+		}
+	}
+	if len(nacks) == 0 {
+		return nil
+	}
+	sort.Slice(nacks, func(i, j int) bool {
+		return nacks[i].LastTimestamp.Before(&nacks[j].LastTimestamp)
+	})
+
+	fmt.Fprintf(cmd.errOut, "WARNING: the proxy of pod %s/%s rejected its configuration:\n", cmd.namespace, cmd.pod)
+	for _, event := range nacks {
+		fmt.Fprintf(cmd.errOut, "  %s (x%d, last at %s)\n", event.Message, event.Count, event.LastTimestamp.Format(time.RFC3339))
+	}
+	return nil
+}
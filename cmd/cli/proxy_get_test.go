@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+)
+
+func TestPrintNACKs(t *testing.T) {
+	now := time.Now()
+	event := func(name string, pod string, reason string, message string, lastTimestamp time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "ns"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: pod, Namespace: "ns"},
+			Reason:         reason,
+			Message:        message,
+			Count:          1,
+			LastTimestamp:  metav1.NewTime(lastTimestamp),
+		}
+	}
+
+	testCases := []struct {
+		name           string
+		events         []*corev1.Event
+		expectedOutput string
+	}{
+		{
+			name: "no NACK",
+			events: []*corev1.Event{
+				event("e1", "pod", "Started", "Started container", now),
+				event("e2", "other", events.ProxyConfigNACKed, "Proxy rejected CDS configuration version v1: invalid", now),
+			},
+			expectedOutput: "",
+		},
+		{
+			name: "NACKs are printed oldest first",
+			events: []*corev1.Event{
+				event("e1", "pod", events.ProxyConfigNACKed, "Proxy rejected LDS configuration version v2: invalid listener", now),
+				event("e2", "pod", events.ProxyConfigNACKed, "Proxy rejected CDS configuration version v1: invalid cluster", now.Add(-time.Minute)),
+			},
+			expectedOutput: "WARNING: the proxy of pod ns/pod rejected its configuration:\n" +
+				"  Proxy rejected CDS configuration version v1: invalid cluster (x1, last at " + now.Add(-time.Minute).Format(time.RFC3339) + ")\n" +
+				"  Proxy rejected LDS configuration version v2: invalid listener (x1, last at " + now.Format(time.RFC3339) + ")\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := tassert.New(t)
+
+			fakeClient := fake.NewSimpleClientset()
+			for _, e := range tc.events {
+				a.NoError(fakeClient.Tracker().Add(e))
+			}
+			errOut := new(bytes.Buffer)
+			cmd := &proxyAdminCmd{
+				errOut:    errOut,
+				clientSet: fakeClient,
+				namespace: "ns",
+				pod:       "pod",
+			}
+
+			a.NoError(cmd.printNACKs())
+			a.Equal(tc.expectedOutput, errOut.String())
+		})
+	}
+}
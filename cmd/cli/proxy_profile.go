@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	osmConfigClient "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned"
+	"github.com/openservicemesh/osm/pkg/k8s"
+)
+
+const profileCmdDescription = `
+This command displays the SidecarProfile selecting the given pod, along with
+the effective sidecar configuration resolved from the MeshConfig and the profile.
+Changes to the sidecar image, resources, and concurrency only apply to the pod
+once it is restarted.
+`
+
+const profileCmdExample = `
+# Display the effective sidecar configuration of the pod 'bookbuyer-5ccf77f46d-rc5mg' in the 'bookbuyer' namespace
+osm proxy profile bookbuyer-5ccf77f46d-rc5mg -n bookbuyer
+`
+
+type proxyProfileCmd struct {
+	out          io.Writer
+	namespace    string
+	pod          string
+	clientSet    kubernetes.Interface
+	configClient osmConfigClient.Interface
+}
+
+func newProxyProfileCmd(out io.Writer) *cobra.Command {
+	profileCmd := &proxyProfileCmd{
+		out: out,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "profile POD",
+		Short: "display the effective sidecar configuration of a pod",
+		Long:  profileCmdDescription,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			profileCmd.pod = args[0]
+			config, err := settings.RESTClientGetter().ToRESTConfig()
+			if err != nil {
+				return fmt.Errorf("Error fetching kubeconfig: %w", err)
+			}
+
+			clientset, err := kubernetes.NewForConfig(config)
+			if err != nil {
+				return fmt.Errorf("Could not access Kubernetes cluster, check kubeconfig: %w", err)
+			}
+			profileCmd.clientSet = clientset
+
+			configClient, err := osmConfigClient.NewForConfig(config)
+			if err != nil {
+				return fmt.Errorf("Could not initialize OSM Config client: %w", err)
+			}
+			profileCmd.configClient = configClient
+
+			return profileCmd.run()
+		},
+		Example: profileCmdExample,
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&profileCmd.namespace, "namespace", "n", metav1.NamespaceDefault, "Namespace of pod")
+
+	return cmd
+}
+
+func (cmd *proxyProfileCmd) run() error {
+	osmNamespace := settings.Namespace()
+
+	pod, err := cmd.clientSet.CoreV1().Pods(cmd.namespace).Get(context.TODO(), cmd.pod, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Could not find pod %s in namespace %s: %w", cmd.pod, cmd.namespace, err)
+	}
+	namespace, err := cmd.clientSet.CoreV1().Namespaces().Get(context.TODO(), cmd.namespace, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Could not find namespace %s: %w", cmd.namespace, err)
+	}
+
+	meshConfig, err := cmd.configClient.ConfigV1alpha2().MeshConfigs(osmNamespace).Get(context.TODO(), defaultOsmMeshConfigName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Error fetching MeshConfig %s: %w", defaultOsmMeshConfigName, err)
+	}
+
+	var profiles []*configv1alpha2.SidecarProfile
+	profileNamespaces := []string{osmNamespace}
+	if cmd.namespace != osmNamespace {
+		profileNamespaces = append(profileNamespaces, cmd.namespace)
+	}
+	for _, ns := range profileNamespaces {
+		profileList, err := cmd.configClient.ConfigV1alpha2().SidecarProfiles(ns).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("Error listing SidecarProfiles in namespace %s: %w", ns, err)
+		}
+		for i := range profileList.Items {
+			profiles = append(profiles, &profileList.Items[i])
+		}
+	}
+
+	sidecarConfig, err := k8s.ResolveSidecarConfig(*meshConfig, profiles, osmNamespace, namespace, pod.Labels)
+	if err != nil {
+		return err
+	}
+
+	if sidecarConfig.Profile != nil {
+		fmt.Fprintf(cmd.out, "SidecarProfile: %s/%s\n", sidecarConfig.Profile.Namespace, sidecarConfig.Profile.Name)
+	} else {
+		fmt.Fprintf(cmd.out, "SidecarProfile: none, the MeshConfig applies as is\n")
+	}
+	if sidecarConfig.Concurrency > 0 {
+		fmt.Fprintf(cmd.out, "Concurrency: %d\n", sidecarConfig.Concurrency)
+	} else {
+		fmt.Fprintf(cmd.out, "Concurrency: number of CPU cores of the node\n")
+	}
+
+	sidecar, err := yaml.Marshal(sidecarConfig.Sidecar)
+	if err != nil {
+		return fmt.Errorf("Error marshaling the sidecar configuration: %w", err)
+	}
+	fmt.Fprintf(cmd.out, "Sidecar:\n")
+	_, err = cmd.out.Write(sidecar)
+	return err
+}
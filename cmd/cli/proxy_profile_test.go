@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	fakeConfig "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned/fake"
+)
+
+func TestProxyProfileRun(t *testing.T) {
+	meshConfig := &configv1alpha2.MeshConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: settings.Namespace(),
+			Name:      defaultOsmMeshConfigName,
+		},
+		Spec: configv1alpha2.MeshConfigSpec{
+			Sidecar: configv1alpha2.SidecarSpec{
+				LogLevel: "error",
+			},
+		},
+	}
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "app"},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "app",
+			Name:      "web-1",
+			Labels:    map[string]string{"app": "web"},
+		},
+	}
+	meshProfile := &configv1alpha2.SidecarProfile{
+		ObjectMeta: metav1.ObjectMeta{Namespace: settings.Namespace(), Name: "mesh"},
+		Spec: configv1alpha2.SidecarProfileSpec{
+			Sidecar: &runtime.RawExtension{Raw: []byte(`{"logLevel":"warn"}`)},
+		},
+	}
+	workloadProfile := &configv1alpha2.SidecarProfile{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "web"},
+		Spec: configv1alpha2.SidecarProfileSpec{
+			PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Sidecar:     &runtime.RawExtension{Raw: []byte(`{"logLevel":"debug"}`)},
+			Concurrency: pointer.Int32(2),
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		pod            string
+		profiles       []*configv1alpha2.SidecarProfile
+		expectErr      bool
+		expectedOutput []string
+	}{
+		{
+			name: "no profile",
+			pod:  "web-1",
+			expectedOutput: []string{
+				"SidecarProfile: none, the MeshConfig applies as is\n",
+				"Concurrency: number of CPU cores of the node\n",
+				"logLevel: error\n",
+			},
+		},
+		{
+			name:     "workload profile",
+			pod:      "web-1",
+			profiles: []*configv1alpha2.SidecarProfile{meshProfile, workloadProfile},
+			expectedOutput: []string{
+				"SidecarProfile: app/web\n",
+				"Concurrency: 2\n",
+				"logLevel: debug\n",
+			},
+		},
+		{
+			name:      "pod not found",
+			pod:       "web-2",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			out := new(bytes.Buffer)
+			cmd := &proxyProfileCmd{
+				out:          out,
+				namespace:    "app",
+				pod:          tc.pod,
+				clientSet:    fake.NewSimpleClientset(namespace, pod),
+				configClient: fakeConfig.NewSimpleClientset(meshConfig),
+			}
+			for _, profile := range tc.profiles {
+				_, err := cmd.configClient.ConfigV1alpha2().SidecarProfiles(profile.Namespace).Create(context.TODO(), profile, metav1.CreateOptions{})
+				assert.Nil(err)
+			}
+
+			err := cmd.run()
+			assert.Equal(tc.expectErr, err != nil)
+			for _, expected := range tc.expectedOutput {
+				assert.Contains(out.String(), expected)
+			}
+		})
+	}
+}
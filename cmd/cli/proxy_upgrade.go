@@ -0,0 +1,486 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	"github.com/openservicemesh/osm/pkg/constants"
+	osmConfigClient "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/models"
+)
+
+const upgradeCmdDescription = `
+This command restarts the workloads whose pods run stale sidecars, for the
+sidecars to be injected again by the current version of OSM with the current
+MeshConfig and SidecarProfiles.
+
+A sidecar is stale when its Envoy image differs from the configured image, or
+when its bootstrap config or its injection were made by another version of OSM.
+The stale sidecars are also reported by osm-controller in the osm_proxy_stale
+metric.
+
+The Deployments, StatefulSets and DaemonSets owning the pods with stale sidecars
+are restarted in batches. Each batch is restarted once the workloads of the
+previous batch are rolled out and ready. The upgrade is halted when a batch is
+not ready within the timeout. Pods not owned by such workloads, or owned by
+workloads with the OnDelete update strategy, are listed, and must be recreated
+to be upgraded.
+`
+
+const upgradeCmdExample = `
+# List the workloads running stale sidecars in the namespaces of the mesh, without restarting them
+osm proxy upgrade --dry-run
+
+# Restart the workloads running stale sidecars in the 'bookstore' namespace, two at a time
+osm proxy upgrade -n bookstore --batch-size 2
+`
+
+const (
+	// restartedAtAnnotation is the pod template annotation set to restart workloads, as set by 'kubectl rollout restart'
+	restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+	// defaultRolloutPollInterval is the interval at which the rollout of a batch of workloads is checked
+	defaultRolloutPollInterval = 2 * time.Second
+)
+
+type proxyUpgradeCmd struct {
+	out          io.Writer
+	namespace    string
+	meshName     string
+	batchSize    int
+	timeout      time.Duration
+	dryRun       bool
+	pollInterval time.Duration
+	clientSet    kubernetes.Interface
+	configClient osmConfigClient.Interface
+}
+
+// staleWorkload is a workload whose pods run stale sidecars
+type staleWorkload struct {
+	kind      string
+	namespace string
+	name      string
+	pods      int
+	reasons   map[models.StaleProxyReason]bool
+}
+
+func (w *staleWorkload) String() string {
+	return fmt.Sprintf("%s %s/%s", w.kind, w.namespace, w.name)
+}
+
+func newProxyUpgradeCmd(out io.Writer) *cobra.Command {
+	upgradeCmd := &proxyUpgradeCmd{
+		out:          out,
+		pollInterval: defaultRolloutPollInterval,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "restart the workloads running stale sidecars",
+		Long:  upgradeCmdDescription,
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if upgradeCmd.batchSize < 1 {
+				return fmt.Errorf("Invalid batch size %d, must be at least 1", upgradeCmd.batchSize)
+			}
+
+			config, err := settings.RESTClientGetter().ToRESTConfig()
+			if err != nil {
+				return fmt.Errorf("Error fetching kubeconfig: %w", err)
+			}
+
+			clientset, err := kubernetes.NewForConfig(config)
+			if err != nil {
+				return fmt.Errorf("Could not access Kubernetes cluster, check kubeconfig: %w", err)
+			}
+			upgradeCmd.clientSet = clientset
+
+			configClient, err := osmConfigClient.NewForConfig(config)
+			if err != nil {
+				return fmt.Errorf("Could not initialize OSM Config client: %w", err)
+			}
+			upgradeCmd.configClient = configClient
+
+			return upgradeCmd.run()
+		},
+		Example: upgradeCmdExample,
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&upgradeCmd.namespace, "namespace", "n", "", "Namespace of the workloads to restart, defaults to the namespaces monitored by the mesh")
+	f.StringVar(&upgradeCmd.meshName, "mesh-name", defaultMeshName, "Name of the service mesh")
+	f.IntVar(&upgradeCmd.batchSize, "batch-size", 1, "Number of workloads restarted at a time")
+	f.DurationVar(&upgradeCmd.timeout, "timeout", 5*time.Minute, "Time to wait for the workloads of a batch to be rolled out and ready")
+	f.BoolVar(&upgradeCmd.dryRun, "dry-run", false, "List the workloads running stale sidecars without restarting them")
+
+	return cmd
+}
+
+func (cmd *proxyUpgradeCmd) run() error {
+	ctx := context.Background()
+
+	workloads, unowned, err := cmd.findStaleWorkloads(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(workloads) == 0 && len(unowned) == 0 {
+		fmt.Fprintf(cmd.out, "No stale sidecars found\n")
+		return nil
+	}
+
+	if len(workloads) > 0 {
+		fmt.Fprintf(cmd.out, "Workloads running stale sidecars:\n")
+		for _, workload := range workloads {
+			fmt.Fprintf(cmd.out, "  %s: %d pods (%s)\n", workload, workload.pods, formatStaleProxyReasons(workload.reasons))
+		}
+	}
+	if len(unowned) > 0 {
+		fmt.Fprintf(cmd.out, "Pods running stale sidecars, to be recreated to be upgraded:\n")
+		for _, pod := range unowned {
+			fmt.Fprintf(cmd.out, "  %s/%s (%s)\n", pod.Namespace, pod.Pod, formatStaleProxyReasons(reasonSet(pod.Reasons)))
+		}
+	}
+
+	if cmd.dryRun || len(workloads) == 0 {
+		return nil
+	}
+
+	batches := (len(workloads) + cmd.batchSize - 1) / cmd.batchSize
+	for i := 0; i < batches; i++ {
+		end := (i + 1) * cmd.batchSize
+		if end > len(workloads) {
+			end = len(workloads)
+		}
+		batch := workloads[i*cmd.batchSize : end]
+
+		restartedAt := time.Now().Format(time.RFC3339)
+		for _, workload := range batch {
+			fmt.Fprintf(cmd.out, "[%d/%d] Restarting %s\n", i+1, batches, workload)
+			if err := cmd.restart(ctx, workload, restartedAt); err != nil {
+				return fmt.Errorf("Error restarting %s: %w", workload, err)
+			}
+		}
+
+		for _, workload := range batch {
+			err := wait.PollImmediate(cmd.pollInterval, cmd.timeout, func() (bool, error) {
+				return cmd.isRolledOut(ctx, workload)
+			})
+			if errors.Is(err, wait.ErrWaitTimeout) {
+				return fmt.Errorf("%s is not ready after %s, halting the upgrade", workload, cmd.timeout)
+			}
+			if err != nil {
+				return fmt.Errorf("Error checking the rollout of %s: %w", workload, err)
+			}
+		}
+		fmt.Fprintf(cmd.out, "[%d/%d] Ready\n", i+1, batches)
+	}
+
+	fmt.Fprintf(cmd.out, "Restarted %d workloads\n", len(workloads))
+	return nil
+}
+
+// findStaleWorkloads returns the workloads owning the pods with stale sidecars, sorted by namespace, kind and name,
+// and the pods with stale sidecars which are not recreated by restarting a workload
+func (cmd *proxyUpgradeCmd) findStaleWorkloads(ctx context.Context) ([]*staleWorkload, []models.StaleProxy, error) {
+	osmNamespace := settings.Namespace()
+
+	deployments, err := cmd.clientSet.AppsV1().Deployments(osmNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set{constants.AppLabel: constants.OSMControllerName}.String(),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error listing the %s deployment in namespace %s: %w", constants.OSMControllerName, osmNamespace, err)
+	}
+	if len(deployments.Items) == 0 {
+		return nil, nil, annotateErrorMessageWithOsmNamespace("Could not find the %s deployment in namespace %s", constants.OSMControllerName, osmNamespace)
+	}
+	controller := deployments.Items[0]
+	osmVersion := controller.Labels[constants.OSMAppVersionLabelKey]
+
+	// The default sidecar images are set on osm-controller, and apply when the MeshConfig sets none
+	defaultImages := make(map[string]string)
+	for _, container := range controller.Spec.Template.Spec.Containers {
+		for _, env := range container.Env {
+			defaultImages[env.Name] = env.Value
+		}
+	}
+
+	meshConfig, err := cmd.configClient.ConfigV1alpha2().MeshConfigs(osmNamespace).Get(ctx, defaultOsmMeshConfigName, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error fetching MeshConfig %s: %w", defaultOsmMeshConfigName, err)
+	}
+	meshProfiles, err := cmd.listSidecarProfiles(ctx, osmNamespace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var namespaces []corev1.Namespace
+	if cmd.namespace != "" {
+		namespace, err := cmd.clientSet.CoreV1().Namespaces().Get(ctx, cmd.namespace, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("Could not find namespace %s: %w", cmd.namespace, err)
+		}
+		namespaces = append(namespaces, *namespace)
+	} else {
+		nsList, err := selectNamespacesMonitoredByMesh(cmd.meshName, cmd.clientSet)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Error listing the namespaces of mesh %s: %w", cmd.meshName, err)
+		}
+		namespaces = nsList.Items
+	}
+
+	workloads := make(map[string]*staleWorkload)
+	var unowned []models.StaleProxy
+	for i := range namespaces {
+		namespace := &namespaces[i]
+
+		profiles := meshProfiles
+		if namespace.Name != osmNamespace {
+			namespaceProfiles, err := cmd.listSidecarProfiles(ctx, namespace.Name)
+			if err != nil {
+				return nil, nil, err
+			}
+			profiles = append(namespaceProfiles, meshProfiles...)
+		}
+
+		pods, err := cmd.clientSet.CoreV1().Pods(namespace.Name).List(ctx, metav1.ListOptions{
+			// Matches on pods which are already a part of the mesh, which contain the Envoy ID label
+			LabelSelector: constants.EnvoyUniqueIDLabelName,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("Error listing pods in namespace %s: %w", namespace.Name, err)
+		}
+
+		for j := range pods.Items {
+			pod := &pods.Items[j]
+
+			sidecarConfig, err := k8s.ResolveSidecarConfig(*meshConfig, profiles, osmNamespace, namespace, pod.Labels)
+			if err != nil {
+				return nil, nil, err
+			}
+			sidecar := sidecarConfig.Sidecar
+			if sidecar.EnvoyImage == "" {
+				sidecar.EnvoyImage = defaultImages["OSM_DEFAULT_ENVOY_IMAGE"]
+			}
+			if sidecar.EnvoyWindowsImage == "" {
+				sidecar.EnvoyWindowsImage = defaultImages["OSM_DEFAULT_ENVOY_WINDOWS_IMAGE"]
+			}
+
+			var bootstrapVersion string
+			secretName := fmt.Sprintf("envoy-bootstrap-config-%s", pod.Labels[constants.EnvoyUniqueIDLabelName])
+			secret, err := cmd.clientSet.CoreV1().Secrets(pod.Namespace).Get(ctx, secretName, metav1.GetOptions{})
+			switch {
+			case err == nil:
+				bootstrapVersion = secret.Labels[constants.OSMAppVersionLabelKey]
+			case !apierrors.IsNotFound(err):
+				return nil, nil, fmt.Errorf("Error fetching the bootstrap config of pod %s/%s: %w", pod.Namespace, pod.Name, err)
+			}
+
+			reasons := k8s.GetStaleProxyReasons(pod, sidecar, bootstrapVersion, osmVersion)
+			if len(reasons) == 0 {
+				continue
+			}
+
+			kind, name, err := cmd.getPodWorkload(ctx, pod)
+			if err != nil {
+				return nil, nil, err
+			}
+			if kind == "" {
+				unowned = append(unowned, models.StaleProxy{Namespace: pod.Namespace, Pod: pod.Name, Reasons: reasons})
+				continue
+			}
+
+			key := fmt.Sprintf("%s/%s/%s", pod.Namespace, kind, name)
+			workload, ok := workloads[key]
+			if !ok {
+				workload = &staleWorkload{
+					kind:      kind,
+					namespace: pod.Namespace,
+					name:      name,
+					reasons:   make(map[models.StaleProxyReason]bool),
+				}
+				workloads[key] = workload
+			}
+			workload.pods++
+			for _, reason := range reasons {
+				workload.reasons[reason] = true
+			}
+		}
+	}
+
+	sorted := make([]*staleWorkload, 0, len(workloads))
+	for _, workload := range workloads {
+		sorted = append(sorted, workload)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.namespace != b.namespace {
+			return a.namespace < b.namespace
+		}
+		if a.kind != b.kind {
+			return a.kind < b.kind
+		}
+		return a.name < b.name
+	})
+
+	return sorted, unowned, nil
+}
+
+func (cmd *proxyUpgradeCmd) listSidecarProfiles(ctx context.Context, namespace string) ([]*configv1alpha2.SidecarProfile, error) {
+	profileList, err := cmd.configClient.ConfigV1alpha2().SidecarProfiles(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Error listing SidecarProfiles in namespace %s: %w", namespace, err)
+	}
+	profiles := make([]*configv1alpha2.SidecarProfile, 0, len(profileList.Items))
+	for i := range profileList.Items {
+		profiles = append(profiles, &profileList.Items[i])
+	}
+	return profiles, nil
+}
+
+// getPodWorkload returns the kind and name of the Deployment, StatefulSet or DaemonSet owning the given pod, or an
+// empty kind when the pod is owned by none or is not recreated when its owner is restarted
+func (cmd *proxyUpgradeCmd) getPodWorkload(ctx context.Context, pod *corev1.Pod) (string, string, error) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return "", "", nil
+	}
+
+	switch owner.Kind {
+	case "StatefulSet":
+		statefulSet, err := cmd.clientSet.AppsV1().StatefulSets(pod.Namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", "", fmt.Errorf("Error fetching the StatefulSet of pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+		// The pods of StatefulSets with the OnDelete strategy are not recreated when the StatefulSet is restarted
+		if statefulSet.Spec.UpdateStrategy.Type != appsv1.OnDeleteStatefulSetStrategyType {
+			return owner.Kind, owner.Name, nil
+		}
+	case "DaemonSet":
+		daemonSet, err := cmd.clientSet.AppsV1().DaemonSets(pod.Namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", "", fmt.Errorf("Error fetching the DaemonSet of pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+		if daemonSet.Spec.UpdateStrategy.Type != appsv1.OnDeleteDaemonSetStrategyType {
+			return owner.Kind, owner.Name, nil
+		}
+	case "ReplicaSet":
+		replicaSet, err := cmd.clientSet.AppsV1().ReplicaSets(pod.Namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return "", "", nil
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("Error fetching the ReplicaSet of pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+		if rsOwner := metav1.GetControllerOf(replicaSet); rsOwner != nil && rsOwner.Kind == "Deployment" {
+			return rsOwner.Kind, rsOwner.Name, nil
+		}
+	}
+	return "", "", nil
+}
+
+// restart restarts the given workload the way 'kubectl rollout restart' does, by annotating its pod template
+func (cmd *proxyUpgradeCmd) restart(ctx context.Context, workload *staleWorkload, restartedAt string) error {
+	patch := []byte(fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`, restartedAtAnnotation, restartedAt))
+
+	var err error
+	switch workload.kind {
+	case "Deployment":
+		_, err = cmd.clientSet.AppsV1().Deployments(workload.namespace).Patch(ctx, workload.name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "StatefulSet":
+		_, err = cmd.clientSet.AppsV1().StatefulSets(workload.namespace).Patch(ctx, workload.name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "DaemonSet":
+		_, err = cmd.clientSet.AppsV1().DaemonSets(workload.namespace).Patch(ctx, workload.name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	}
+	return err
+}
+
+// isRolledOut returns whether all the pods of the given workload were updated and are ready
+func (cmd *proxyUpgradeCmd) isRolledOut(ctx context.Context, workload *staleWorkload) (bool, error) {
+	switch workload.kind {
+	case "Deployment":
+		deployment, err := cmd.clientSet.AppsV1().Deployments(workload.namespace).Get(ctx, workload.name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return isDeploymentRolledOut(deployment), nil
+	case "StatefulSet":
+		statefulSet, err := cmd.clientSet.AppsV1().StatefulSets(workload.namespace).Get(ctx, workload.name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return isStatefulSetRolledOut(statefulSet), nil
+	case "DaemonSet":
+		daemonSet, err := cmd.clientSet.AppsV1().DaemonSets(workload.namespace).Get(ctx, workload.name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return isDaemonSetRolledOut(daemonSet), nil
+	}
+	return true, nil
+}
+
+func isDeploymentRolledOut(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	status := deployment.Status
+	return status.ObservedGeneration >= deployment.Generation &&
+		status.UpdatedReplicas == replicas &&
+		status.Replicas == replicas &&
+		status.AvailableReplicas == replicas
+}
+
+func isStatefulSetRolledOut(statefulSet *appsv1.StatefulSet) bool {
+	replicas := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+	status := statefulSet.Status
+	return status.ObservedGeneration >= statefulSet.Generation &&
+		status.UpdatedReplicas == replicas &&
+		status.ReadyReplicas == replicas &&
+		(status.UpdateRevision == "" || status.CurrentRevision == status.UpdateRevision)
+}
+
+func isDaemonSetRolledOut(daemonSet *appsv1.DaemonSet) bool {
+	status := daemonSet.Status
+	return status.ObservedGeneration >= daemonSet.Generation &&
+		status.UpdatedNumberScheduled == status.DesiredNumberScheduled &&
+		status.NumberAvailable == status.DesiredNumberScheduled
+}
+
+func reasonSet(reasons []models.StaleProxyReason) map[models.StaleProxyReason]bool {
+	set := make(map[models.StaleProxyReason]bool, len(reasons))
+	for _, reason := range reasons {
+		set[reason] = true
+	}
+	return set
+}
+
+func formatStaleProxyReasons(reasons map[models.StaleProxyReason]bool) string {
+	formatted := make([]string, 0, len(reasons))
+	for reason := range reasons {
+		formatted = append(formatted, string(reason))
+	}
+	sort.Strings(formatted)
+	return strings.Join(formatted, ", ")
+}
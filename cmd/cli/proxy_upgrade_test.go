@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	"github.com/openservicemesh/osm/pkg/constants"
+	fakeConfig "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned/fake"
+)
+
+func TestProxyUpgradeRun(t *testing.T) {
+	const namespace = "bookstore"
+
+	controller := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: settings.Namespace(),
+			Name:      constants.OSMControllerName,
+			Labels: map[string]string{
+				constants.AppLabel:              constants.OSMControllerName,
+				constants.OSMAppVersionLabelKey: "v1.3.0",
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: constants.OSMControllerName,
+							Env:  []corev1.EnvVar{{Name: "OSM_DEFAULT_ENVOY_IMAGE", Value: "envoy:v2"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	newPod := func(name, envoyImage, injectorVersion string, owner *metav1.OwnerReference) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   namespace,
+				Name:        name,
+				Labels:      map[string]string{constants.EnvoyUniqueIDLabelName: name},
+				Annotations: map[string]string{constants.InjectorVersionAnnotation: injectorVersion},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "app", Image: "app"},
+					{Name: constants.EnvoyContainerName, Image: envoyImage},
+				},
+			},
+		}
+		if owner != nil {
+			pod.OwnerReferences = []metav1.OwnerReference{*owner}
+		}
+		return pod
+	}
+	newBootstrapSecret := func(proxyUUID, version string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      fmt.Sprintf("envoy-bootstrap-config-%s", proxyUUID),
+				Labels:    map[string]string{constants.OSMAppVersionLabelKey: version},
+			},
+		}
+	}
+	controllerRef := func(kind, name string) *metav1.OwnerReference {
+		return &metav1.OwnerReference{Kind: kind, Name: name, Controller: pointer.Bool(true)}
+	}
+	newReplicaSet := func(name, deployment string) *appsv1.ReplicaSet {
+		return &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       namespace,
+				Name:            name,
+				OwnerReferences: []metav1.OwnerReference{*controllerRef("Deployment", deployment)},
+			},
+		}
+	}
+	newDeployment := func(name string, availableReplicas int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec:       appsv1.DeploymentSpec{Replicas: pointer.Int32(1)},
+			Status: appsv1.DeploymentStatus{
+				Replicas:          1,
+				UpdatedReplicas:   1,
+				AvailableReplicas: availableReplicas,
+			},
+		}
+	}
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "db"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: pointer.Int32(1)},
+		Status: appsv1.StatefulSetStatus{
+			UpdatedReplicas: 1,
+			ReadyReplicas:   1,
+		},
+	}
+
+	objects := func(bookstoreV1Available int32) []runtime.Object {
+		return []runtime.Object{
+			controller,
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   namespace,
+				Labels: map[string]string{constants.OSMKubeResourceMonitorAnnotation: defaultMeshName},
+			}},
+			// Stale Envoy image
+			newPod("bookstore-v1-1", "envoy:v1", "v1.3.0", controllerRef("ReplicaSet", "bookstore-v1-1")),
+			newBootstrapSecret("bookstore-v1-1", "v1.3.0"),
+			newReplicaSet("bookstore-v1-1", "bookstore-v1"),
+			newDeployment("bookstore-v1", bookstoreV1Available),
+			// Up to date
+			newPod("bookstore-v2-1", "envoy:v2", "v1.3.0", controllerRef("ReplicaSet", "bookstore-v2-1")),
+			newBootstrapSecret("bookstore-v2-1", "v1.3.0"),
+			newReplicaSet("bookstore-v2-1", "bookstore-v2"),
+			newDeployment("bookstore-v2", 1),
+			// Injected by another version
+			newPod("db-0", "envoy:v2", "v1.2.0", controllerRef("StatefulSet", "db")),
+			newBootstrapSecret("db-0", "v1.2.0"),
+			statefulSet,
+			// Not owned by a workload
+			newPod("debug", "envoy:v1", "v1.3.0", nil),
+		}
+	}
+
+	testCases := []struct {
+		name                 string
+		dryRun               bool
+		bookstoreV1Available int32
+		expectErr            bool
+		expectedRestarted    []string
+		expectedOutput       string
+	}{
+		{
+			name:                 "dry run",
+			dryRun:               true,
+			bookstoreV1Available: 1,
+			expectedOutput: `Workloads running stale sidecars:
+  Deployment bookstore/bookstore-v1: 1 pods (envoy-image)
+  StatefulSet bookstore/db: 1 pods (bootstrap-version, injector-version)
+Pods running stale sidecars, to be recreated to be upgraded:
+  bookstore/debug (envoy-image)
+`,
+		},
+		{
+			name:                 "restarts the workloads in batches",
+			bookstoreV1Available: 1,
+			expectedRestarted:    []string{"Deployment/bookstore-v1", "StatefulSet/db"},
+			expectedOutput: `Workloads running stale sidecars:
+  Deployment bookstore/bookstore-v1: 1 pods (envoy-image)
+  StatefulSet bookstore/db: 1 pods (bootstrap-version, injector-version)
+Pods running stale sidecars, to be recreated to be upgraded:
+  bookstore/debug (envoy-image)
+[1/2] Restarting Deployment bookstore/bookstore-v1
+[1/2] Ready
+[2/2] Restarting StatefulSet bookstore/db
+[2/2] Ready
+Restarted 2 workloads
+`,
+		},
+		{
+			name:                 "halts when a batch is not ready",
+			bookstoreV1Available: 0,
+			expectErr:            true,
+			expectedRestarted:    []string{"Deployment/bookstore-v1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			clientSet := fake.NewSimpleClientset(objects(tc.bookstoreV1Available)...)
+			out := new(bytes.Buffer)
+			cmd := &proxyUpgradeCmd{
+				out:       out,
+				meshName:  defaultMeshName,
+				batchSize: 1,
+				timeout:   50 * time.Millisecond,
+				dryRun:    tc.dryRun,
+				clientSet: clientSet,
+				configClient: fakeConfig.NewSimpleClientset(&configv1alpha2.MeshConfig{
+					ObjectMeta: metav1.ObjectMeta{Namespace: settings.Namespace(), Name: defaultOsmMeshConfigName},
+				}),
+				pollInterval: 10 * time.Millisecond,
+			}
+
+			err := cmd.run()
+			assert.Equal(tc.expectErr, err != nil, err)
+			if tc.expectedOutput != "" {
+				assert.Equal(tc.expectedOutput, out.String())
+			}
+
+			var restarted []string
+			for _, deployment := range []string{"bookstore-v1", "bookstore-v2"} {
+				d, err := clientSet.AppsV1().Deployments(namespace).Get(context.TODO(), deployment, metav1.GetOptions{})
+				assert.NoError(err)
+				if _, ok := d.Spec.Template.Annotations[restartedAtAnnotation]; ok {
+					restarted = append(restarted, "Deployment/"+deployment)
+				}
+			}
+			s, err := clientSet.AppsV1().StatefulSets(namespace).Get(context.TODO(), "db", metav1.GetOptions{})
+			assert.NoError(err)
+			if _, ok := s.Spec.Template.Annotations[restartedAtAnnotation]; ok {
+				restarted = append(restarted, "StatefulSet/db")
+			}
+			assert.Equal(tc.expectedRestarted, restarted)
+		})
+	}
+}
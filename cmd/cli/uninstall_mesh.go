@@ -305,8 +305,11 @@ func (d *uninstallMeshCmd) uninstallCustomResourceDefinitions() error {
 		"ingressbackends.policy.openservicemesh.io",
 		"meshconfigs.config.openservicemesh.io",
 		"meshrootcertificates.config.openservicemesh.io",
+		"trustdomainfederations.config.openservicemesh.io",
+		"sidecarprofiles.config.openservicemesh.io",
 		"upstreamtrafficsettings.policy.openservicemesh.io",
 		"retries.policy.openservicemesh.io",
+		"workloadentries.policy.openservicemesh.io",
 		"httproutegroups.specs.smi-spec.io",
 		"tcproutes.specs.smi-spec.io",
 		"trafficsplits.split.smi-spec.io",
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+const vmDescription = `
+This command consists of subcommands related to the workloads outside of
+Kubernetes, such as virtual machines and bare-metal hosts, which are part of
+the mesh through a WorkloadEntry.
+`
+
+func newVMCmd(stdout io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vm",
+		Short: "manage workloads outside of Kubernetes",
+		Long:  vmDescription,
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(newVMBootstrapCmd(stdout))
+
+	return cmd
+}
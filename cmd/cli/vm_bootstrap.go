@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	xds_bootstrap "github.com/envoyproxy/go-control-plane/envoy/config/bootstrap/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy/bootstrap"
+	policyClientset "github.com/openservicemesh/osm/pkg/gen/client/policy/clientset/versioned"
+	"github.com/openservicemesh/osm/pkg/injector"
+	"github.com/openservicemesh/osm/pkg/utils"
+)
+
+const vmBootstrapDescription = `
+This command exports the bootstrap configuration of the Envoy proxy of a
+workload outside of Kubernetes, described by the given WorkloadEntry, to the
+given directory. The directory holds the bootstrap configuration, the
+certificates of the proxy, and the iptables.sh script redirecting the traffic
+of the workload to the proxy.
+
+The files must be copied to the /etc/envoy directory of the workload's host,
+where Envoy runs as the user with UID 1500 with the bootstrap.yaml file as its
+configuration. The certificates are rotated by OSM, so that the files must be
+exported again before they expire.
+
+The proxy connects to the OSM controller at the given xDS address, which must
+be reachable from the workload's host.
+`
+
+const vmBootstrapExample = `
+# Export the bootstrap configuration of the proxy of the WorkloadEntry 'inventory-vm' in the 'legacy' namespace
+osm vm bootstrap inventory-vm -n legacy --xds-address osm-controller.example.com --output-dir ./inventory-vm
+`
+
+type vmBootstrapCmd struct {
+	stdout                       io.Writer
+	kubeClient                   kubernetes.Interface
+	policyClient                 policyClientset.Interface
+	name                         string
+	namespace                    string
+	outputDir                    string
+	xdsAddress                   string
+	outboundIPRangeExclusionList []string
+	outboundPortExclusionList    []int
+	inboundPortExclusionList     []int
+}
+
+func newVMBootstrapCmd(stdout io.Writer) *cobra.Command {
+	bootstrapCmd := &vmBootstrapCmd{
+		stdout: stdout,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "bootstrap WORKLOAD_ENTRY",
+		Short: "export the proxy bootstrap configuration of a workload outside of Kubernetes",
+		Long:  vmBootstrapDescription,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			bootstrapCmd.name = args[0]
+
+			config, err := settings.RESTClientGetter().ToRESTConfig()
+			if err != nil {
+				return fmt.Errorf("Error fetching kubeconfig: %w", err)
+			}
+
+			kubeClient, err := kubernetes.NewForConfig(config)
+			if err != nil {
+				return fmt.Errorf("Could not access Kubernetes cluster, check kubeconfig: %w", err)
+			}
+			bootstrapCmd.kubeClient = kubeClient
+
+			policyClient, err := policyClientset.NewForConfig(config)
+			if err != nil {
+				return fmt.Errorf("Error initializing %s client: %w", policyv1alpha1.SchemeGroupVersion, err)
+			}
+			bootstrapCmd.policyClient = policyClient
+
+			return bootstrapCmd.run()
+		},
+		Example: vmBootstrapExample,
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&bootstrapCmd.namespace, "namespace", "n", metav1.NamespaceDefault, "Namespace of the WorkloadEntry")
+	f.StringVar(&bootstrapCmd.outputDir, "output-dir", ".", "Directory to write the bootstrap configuration to")
+	f.StringVar(&bootstrapCmd.xdsAddress, "xds-address", "", "Address of the OSM controller reachable from the workload, as HOST or HOST:PORT")
+	f.StringSliceVar(&bootstrapCmd.outboundIPRangeExclusionList, "outbound-ip-range-exclusion-list", nil, "IP ranges in CIDR notation to exclude from outbound traffic interception")
+	f.IntSliceVar(&bootstrapCmd.outboundPortExclusionList, "outbound-port-exclusion-list", nil, "Ports to exclude from outbound traffic interception")
+	f.IntSliceVar(&bootstrapCmd.inboundPortExclusionList, "inbound-port-exclusion-list", []int{22}, "Ports to exclude from inbound traffic interception")
+	//nolint: errcheck
+	//#nosec G104
+	cmd.MarkFlagRequired("xds-address")
+
+	return cmd
+}
+
+func (cmd *vmBootstrapCmd) run() error {
+	ctx := context.Background()
+
+	we, err := cmd.policyClient.PolicyV1alpha1().WorkloadEntries(cmd.namespace).Get(ctx, cmd.name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Error fetching WorkloadEntry %s/%s: %w", cmd.namespace, cmd.name, err)
+	}
+	proxyUUID, ok := we.Labels[constants.EnvoyUniqueIDLabelName]
+	if !ok {
+		return fmt.Errorf("WorkloadEntry %s/%s has no proxy yet, check that osm-injector is running and that namespace %s is monitored by the mesh",
+			cmd.namespace, cmd.name, cmd.namespace)
+	}
+
+	secretName := fmt.Sprintf("envoy-bootstrap-config-%s", proxyUUID)
+	secret, err := cmd.kubeClient.CoreV1().Secrets(cmd.namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Error fetching the proxy bootstrap config %s/%s of WorkloadEntry %s/%s: %w", cmd.namespace, secretName, cmd.namespace, cmd.name, err)
+	}
+
+	host, port, err := parseXDSAddress(cmd.xdsAddress)
+	if err != nil {
+		return err
+	}
+
+	files := make(map[string][]byte)
+	for key, value := range secret.Data {
+		// The issuer IDs are only used by OSM to rotate the certificates
+		if key == "signing_issuer_id" || key == "validating_issuer_id" {
+			continue
+		}
+		files[key] = value
+	}
+	configYAML, err := setXDSAddress(files[bootstrap.EnvoyBootstrapConfigFile], host, port)
+	if err != nil {
+		return fmt.Errorf("Error setting the xDS address in the proxy bootstrap config %s/%s: %w", cmd.namespace, secretName, err)
+	}
+	files[bootstrap.EnvoyBootstrapConfigFile] = configYAML
+	// The proxy of the workload only listens on the IPv6 wildcard address when the workload has an IPv6 address
+	ip := net.ParseIP(we.Spec.Address)
+	ipv6 := ip != nil && ip.To4() == nil
+	files["iptables.sh"] = []byte("#!/bin/sh\nset -e\n" +
+		injector.GenerateWorkloadIptablesCommands(ipv6, cmd.outboundIPRangeExclusionList, cmd.outboundPortExclusionList, cmd.inboundPortExclusionList) + "\n")
+
+	if err := os.MkdirAll(cmd.outputDir, 0700); err != nil {
+		return fmt.Errorf("Error creating directory %s: %w", cmd.outputDir, err)
+	}
+	for name, content := range files {
+		path := filepath.Join(cmd.outputDir, name)
+		if err := os.WriteFile(path, content, 0600); err != nil {
+			return fmt.Errorf("Error writing %s: %w", path, err)
+		}
+	}
+
+	fmt.Fprintf(cmd.stdout, "Proxy bootstrap config of WorkloadEntry %s/%s written to %s\n", cmd.namespace, cmd.name, cmd.outputDir)
+	fmt.Fprintf(cmd.stdout, "Copy the files to %s on the workload's host, run iptables.sh as root, and run Envoy as the user with UID %d with --config-path %s/%s\n",
+		bootstrap.EnvoyProxyConfigPath, constants.EnvoyUID, bootstrap.EnvoyProxyConfigPath, bootstrap.EnvoyBootstrapConfigFile)
+	return nil
+}
+
+// parseXDSAddress returns the host and port of the given xDS address, the port defaulting to the port of the ADS
+// server of the OSM controller
+func parseXDSAddress(address string) (string, uint32, error) {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		// The address has no port
+		return address, constants.ADSServerPort, nil
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil || port == 0 {
+		return "", 0, fmt.Errorf("Invalid port in xDS address %s", address)
+	}
+	return host, uint32(port), nil
+}
+
+// setXDSAddress sets the address of the OSM controller cluster of the given bootstrap config
+func setXDSAddress(configYAML []byte, host string, port uint32) ([]byte, error) {
+	config := &xds_bootstrap.Bootstrap{}
+	if err := utils.YAMLToProto(configYAML, config); err != nil {
+		return nil, err
+	}
+	for _, cluster := range config.GetStaticResources().GetClusters() {
+		if cluster.Name != constants.OSMControllerName {
+			continue
+		}
+		for _, localityEndpoints := range cluster.GetLoadAssignment().GetEndpoints() {
+			for _, endpoint := range localityEndpoints.GetLbEndpoints() {
+				socketAddress := endpoint.GetEndpoint().GetAddress().GetSocketAddress()
+				if socketAddress == nil {
+					continue
+				}
+				socketAddress.Address = host
+				socketAddress.PortSpecifier = &xds_core.SocketAddress_PortValue{PortValue: port}
+			}
+		}
+		return utils.ProtoToYAML(config)
+	}
+	return nil, fmt.Errorf("cluster %s not found", constants.OSMControllerName)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	xds_bootstrap "github.com/envoyproxy/go-control-plane/envoy/config/bootstrap/v3"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy/bootstrap"
+	fakePolicyClientset "github.com/openservicemesh/osm/pkg/gen/client/policy/clientset/versioned/fake"
+	"github.com/openservicemesh/osm/pkg/utils"
+)
+
+func TestVMBootstrapRun(t *testing.T) {
+	testNs := "legacy"
+	proxyUUID := "5a1e9a3c-6d39-4c8f-9a3a-2d5b0b0f1c11"
+
+	builder := bootstrap.Builder{NodeID: proxyUUID, XDSHost: "osm-controller.osm-system.svc.cluster.local"}
+	config, err := builder.Build()
+	assert.NoError(t, err)
+	configYAML, err := utils.ProtoToYAML(config)
+	assert.NoError(t, err)
+
+	bootstrapSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "envoy-bootstrap-config-" + proxyUUID,
+			Namespace: testNs,
+		},
+		Data: map[string][]byte{
+			bootstrap.EnvoyBootstrapConfigFile: configYAML,
+			"sds_cert.pem":                     []byte("cert"),
+			"signing_issuer_id":                []byte("osm-ca"),
+		},
+	}
+	workloadEntry := func(labels map[string]string) *policyv1alpha1.WorkloadEntry {
+		return &policyv1alpha1.WorkloadEntry{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "inventory-vm",
+				Namespace: testNs,
+				Labels:    labels,
+			},
+			Spec: policyv1alpha1.WorkloadEntrySpec{
+				Address:        "10.0.0.5",
+				ServiceAccount: "inventory",
+			},
+		}
+	}
+
+	testCases := []struct {
+		name            string
+		workloadEntry   *policyv1alpha1.WorkloadEntry
+		secret          *corev1.Secret
+		xdsAddress      string
+		expectErr       bool
+		expectedAddress string
+		expectedPort    uint32
+	}{
+		{
+			name:            "bootstrap config with the default xDS port",
+			workloadEntry:   workloadEntry(map[string]string{constants.EnvoyUniqueIDLabelName: proxyUUID}),
+			secret:          bootstrapSecret,
+			xdsAddress:      "osm.example.com",
+			expectedAddress: "osm.example.com",
+			expectedPort:    constants.ADSServerPort,
+		},
+		{
+			name:            "bootstrap config with an xDS port",
+			workloadEntry:   workloadEntry(map[string]string{constants.EnvoyUniqueIDLabelName: proxyUUID}),
+			secret:          bootstrapSecret,
+			xdsAddress:      "10.1.2.3:30128",
+			expectedAddress: "10.1.2.3",
+			expectedPort:    30128,
+		},
+		{
+			name:          "invalid xDS port",
+			workloadEntry: workloadEntry(map[string]string{constants.EnvoyUniqueIDLabelName: proxyUUID}),
+			secret:        bootstrapSecret,
+			xdsAddress:    "10.1.2.3:port",
+			expectErr:     true,
+		},
+		{
+			name:          "WorkloadEntry without a proxy",
+			workloadEntry: workloadEntry(nil),
+			secret:        bootstrapSecret,
+			xdsAddress:    "osm.example.com",
+			expectErr:     true,
+		},
+		{
+			name:          "WorkloadEntry without a bootstrap config",
+			workloadEntry: workloadEntry(map[string]string{constants.EnvoyUniqueIDLabelName: proxyUUID}),
+			secret:        &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: testNs}},
+			xdsAddress:    "osm.example.com",
+			expectErr:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+			stdout := new(bytes.Buffer)
+			outputDir := t.TempDir()
+
+			cmd := &vmBootstrapCmd{
+				stdout:                   stdout,
+				kubeClient:               fake.NewSimpleClientset(tc.secret),
+				policyClient:             fakePolicyClientset.NewSimpleClientset(tc.workloadEntry),
+				name:                     "inventory-vm",
+				namespace:                testNs,
+				outputDir:                outputDir,
+				xdsAddress:               tc.xdsAddress,
+				inboundPortExclusionList: []int{22},
+			}
+
+			err := cmd.run()
+			a.Equal(tc.expectErr, err != nil, err)
+			if err != nil {
+				return
+			}
+
+			a.NoFileExists(filepath.Join(outputDir, "signing_issuer_id"))
+			cert, err := os.ReadFile(filepath.Clean(filepath.Join(outputDir, "sds_cert.pem")))
+			a.NoError(err)
+			a.Equal("cert", string(cert))
+
+			iptables, err := os.ReadFile(filepath.Clean(filepath.Join(outputDir, "iptables.sh")))
+			a.NoError(err)
+			a.Contains(string(iptables), "-I OSM_PROXY_INBOUND -p tcp --match multiport --dports 22 -j RETURN")
+
+			configYAML, err := os.ReadFile(filepath.Clean(filepath.Join(outputDir, bootstrap.EnvoyBootstrapConfigFile)))
+			a.NoError(err)
+			config := &xds_bootstrap.Bootstrap{}
+			a.NoError(utils.YAMLToProto(configYAML, config))
+			a.Equal(proxyUUID, config.Node.Id)
+			socketAddress := config.StaticResources.Clusters[0].LoadAssignment.Endpoints[0].LbEndpoints[0].GetEndpoint().Address.GetSocketAddress()
+			a.Equal(tc.expectedAddress, socketAddress.Address)
+			a.Equal(tc.expectedPort, socketAddress.GetPortValue())
+		})
+	}
+}
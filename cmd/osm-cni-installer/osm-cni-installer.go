@@ -0,0 +1,105 @@
+// Package main implements the main entrypoint for osm-cni-installer.
+// osm-cni-installer runs on every node as a DaemonSet, and installs the osm-cni plugin in the CNI binary and network
+// configuration directories of the node, chained after the plugin setting up the pod network.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/openservicemesh/osm/pkg/cni"
+	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/signals"
+	"github.com/openservicemesh/osm/pkg/version"
+)
+
+var (
+	verbosity         string
+	osmNamespace      string
+	osmMeshConfigName string
+	pluginLogLevel    string
+	excludeNamespaces []string
+	reinstallInterval time.Duration
+
+	installer cni.Installer
+)
+
+var (
+	flags = pflag.NewFlagSet(`osm-cni-installer`, pflag.ExitOnError)
+	log   = logger.New("osm-cni-installer/main")
+)
+
+func init() {
+	flags.StringVarP(&verbosity, "verbosity", "v", "info", "Set log verbosity level")
+	flags.StringVar(&osmNamespace, "osm-namespace", "", "Namespace to which OSM belongs to.")
+	flags.StringVar(&osmMeshConfigName, "osm-config-name", "osm-mesh-config", "Name of the OSM MeshConfig")
+	flags.StringVar(&pluginLogLevel, "plugin-log-level", "info", "Log verbosity level of the CNI plugin")
+	flags.StringSliceVar(&excludeNamespaces, "exclude-namespaces", []string{"kube-system"}, "Namespaces whose pods are skipped by the CNI plugin")
+	flags.DurationVar(&reinstallInterval, "reinstall-interval", time.Minute, "Interval at which the CNI plugin is reinstalled to refresh its credentials and chaining")
+
+	flags.StringVar(&installer.PluginBinary, "plugin-binary", "/osm-cni", "Path of the CNI plugin binary to install")
+	flags.StringVar(&installer.BinDir, "cni-bin-dir", "/host/opt/cni/bin", "Mount path of the node's CNI binary directory")
+	flags.StringVar(&installer.ConfDir, "cni-conf-dir", "/host/etc/cni/net.d", "Mount path of the node's CNI network configuration directory")
+	flags.StringVar(&installer.KubeconfigDir, "host-cni-conf-dir", "/etc/cni/net.d", "Path of the CNI network configuration directory on the node")
+}
+
+func main() {
+	log.Info().Msgf("Starting osm-cni-installer %s; %s; %s", version.Version, version.GitCommit, version.BuildDate)
+	if err := parseFlags(); err != nil {
+		log.Fatal().Err(err).Msg("Error parsing cmd line arguments")
+	}
+	if err := logger.SetLogLevel(verbosity); err != nil {
+		log.Fatal().Err(err).Msg("Error setting log level")
+	}
+
+	// This ensures CLI parameters (and dependent values) are correct.
+	if err := validateCLIParams(); err != nil {
+		log.Fatal().Err(err).Msg("Error validating CLI parameters")
+	}
+
+	// The plugin reaches the API server from the node through the same address as the pods
+	installer.APIServer = "https://" + net.JoinHostPort(os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT"))
+	installer.NetConf = cni.NetConf{
+		OSMNamespace:      osmNamespace,
+		MeshConfigName:    osmMeshConfigName,
+		ExcludeNamespaces: excludeNamespaces,
+		LogLevel:          pluginLogLevel,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_ = signals.RegisterExitHandlers(cancel)
+
+	if err := installer.Run(ctx, reinstallInterval); err != nil {
+		log.Fatal().Err(err).Msg("Error installing the OSM CNI plugin")
+	}
+
+	log.Info().Msgf("Stopping osm-cni-installer %s; %s; %s", version.Version, version.GitCommit, version.BuildDate)
+}
+
+func parseFlags() error {
+	if err := flags.Parse(os.Args); err != nil {
+		return err
+	}
+	_ = flag.CommandLine.Parse([]string{})
+	return nil
+}
+
+// validateCLIParams contains all checks necessary that various permutations of the CLI flags are consistent
+func validateCLIParams() error {
+	if osmNamespace == "" {
+		return fmt.Errorf("Please specify the OSM namespace using --osm-namespace")
+	}
+
+	if os.Getenv("KUBERNETES_SERVICE_HOST") == "" {
+		return fmt.Errorf("KUBERNETES_SERVICE_HOST is not set, osm-cni-installer must run in a pod")
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+// Package main implements the main entrypoint for osm-cni.
+// osm-cni is a chained CNI plugin executed by the container runtime when it sets up the network of a pod. It
+// redirects the traffic of the pods injected with an Envoy sidecar to their sidecar, in place of the init container.
+// Run with the validate command, it is the init container of those pods verifying that their traffic was redirected.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/openservicemesh/osm/pkg/cni"
+	"github.com/openservicemesh/osm/pkg/constants"
+	configClientset "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned"
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+const (
+	// apiTimeout is the timeout of the requests to the Kubernetes API server, after which the container runtime retries
+	apiTimeout = 30 * time.Second
+
+	// validateCommand is the command validating the traffic redirection of a pod, run by its init container. The
+	// container runtime runs the plugin without arguments.
+	validateCommand = "validate"
+)
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == validateCommand {
+		validate(os.Args[2:])
+		return
+	}
+
+	stdin, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		exitWithError("", cni.NewError(cni.ErrCodeIOFailure, "error reading network configuration", err))
+	}
+
+	args, err := cni.ArgsFromEnv(os.Getenv)
+	if err != nil {
+		exitWithError("", err)
+	}
+
+	if args.Command == cni.CmdVersion {
+		var conf struct {
+			CNIVersion string `json:"cniVersion"`
+		}
+		_ = json.Unmarshal(stdin, &conf)
+		fmt.Printf("%s", cni.VersionResult(conf.CNIVersion))
+		return
+	}
+
+	conf, err := cni.ParseNetConf(stdin)
+	if err != nil {
+		exitWithError("", err)
+	}
+	if conf.LogLevel != "" {
+		_ = logger.SetLogLevel(conf.LogLevel)
+	}
+
+	switch args.Command {
+	case cni.CmdAdd:
+		kubeConfig, err := clientcmd.BuildConfigFromFlags("", conf.Kubeconfig)
+		if err != nil {
+			exitWithError(conf.CNIVersion, cni.NewError(cni.ErrCodeInvalidConfig, "error loading kubeconfig "+conf.Kubeconfig, err))
+		}
+		kubeConfig.Timeout = apiTimeout
+		plugin := cni.NewPlugin(kubernetes.NewForConfigOrDie(kubeConfig), configClientset.NewForConfigOrDie(kubeConfig))
+
+		ctx, cancel := context.WithTimeout(context.Background(), apiTimeout)
+		defer cancel()
+		if err := plugin.Add(ctx, conf, args); err != nil {
+			exitWithError(conf.CNIVersion, err)
+		}
+		fmt.Printf("%s", cni.Result(conf))
+	case cni.CmdDel, cni.CmdCheck:
+		// The rules are removed along with the network namespace of the pod, and are not checked
+	default:
+		exitWithError(conf.CNIVersion, cni.NewError(cni.ErrCodeInvalidEnv, "unsupported CNI_COMMAND "+args.Command, nil))
+	}
+}
+
+// exitWithError writes the given error to the standard output, as expected by the container runtime, and exits
+func exitWithError(cniVersion string, err error) {
+	cniErr, ok := err.(*cni.Error)
+	if !ok {
+		cniErr = &cni.Error{Code: 999, Msg: err.Error()}
+	}
+	cniErr.CNIVersion = cniVersion
+	_ = json.NewEncoder(os.Stdout).Encode(cniErr)
+	os.Exit(1)
+}
+
+// validate exits with an error when the outbound traffic of the pod is not redirected to its sidecar's outbound
+// listener, which is not started yet
+func validate(args []string) {
+	var addresses []string
+	var timeout time.Duration
+
+	flags := pflag.NewFlagSet(validateCommand, pflag.ExitOnError)
+	flags.StringSliceVar(&addresses, "address", nil, "Addresses only reachable when the outbound traffic is redirected to the sidecar")
+	flags.DurationVar(&timeout, "timeout", 5*time.Second, "Timeout of the connection to each address")
+	if err := flags.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	listenAddress := net.JoinHostPort("", strconv.Itoa(constants.EnvoyOutboundListenerPort))
+	if err := cni.ValidateRedirection(listenAddress, addresses, timeout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
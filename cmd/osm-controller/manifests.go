@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/openservicemesh/osm/pkg/certificate/providers"
+	"github.com/openservicemesh/osm/pkg/compute/file"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/messaging"
+	"github.com/openservicemesh/osm/pkg/signals"
+	"github.com/openservicemesh/osm/pkg/spiffe"
+	"github.com/openservicemesh/osm/pkg/tracing"
+	"github.com/openservicemesh/osm/pkg/version"
+)
+
+// runWithManifests runs osm-controller against the mesh described by the YAML manifests of manifestsDir instead of the
+// Kubernetes API server, for local development and testing. The changes to the manifests are published through the
+// message broker like Kubernetes events. As it runs as a single replica without a cluster, it performs the duties of
+// the leader itself, its CA is held in memory, and the validating webhook, the reconciler and the ingress gateway
+// certificate are not run.
+func runWithManifests() {
+	if err := validateCLIParams(); err != nil {
+		log.Fatal().Err(err).Msg("Error validating CLI parameters")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stop := signals.RegisterExitHandlers(cancel)
+
+	// Start the default metrics store
+	startMetricsStore()
+
+	shutdownTracing, err := tracing.Setup(ctx, "osm-controller", tracingOptions)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error setting up tracing")
+	}
+
+	msgBroker := messaging.NewBroker(stop)
+
+	provider, err := file.NewProvider(manifestsDir, osmNamespace, osmMeshConfigName, msgBroker)
+	if err != nil {
+		log.Fatal().Err(err).Msgf("Error loading manifests from directory %s", manifestsDir)
+	}
+	// Apply the changes to the manifests as they are made
+	if err := provider.Watch(stop); err != nil {
+		log.Fatal().Err(err).Msgf("Error watching manifests in directory %s", manifestsDir)
+	}
+
+	certManager, err := providers.NewInMemoryCertificateManager(ctx, osmNamespace, provider, 5*time.Second, trustDomain)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error creating in-memory certificate manager")
+	}
+
+	meshCatalog := startADSServer(ctx, cancel, stop, provider, certManager, msgBroker, nil, nil)
+
+	// Start the global log level watcher that updates the log level dynamically
+	go k8s.WatchAndUpdateLogLevel(msgBroker, stop)
+
+	go reportStaleProxies(ctx, provider, staleProxyReportInterval)
+	go reconcileStatuses(ctx, meshCatalog, statusResyncInterval)
+
+	// SPIFFE trust bundle of the mesh, fetched by federated trust domains over HTTPS
+	if err := spiffe.ServeBundleEndpoint(ctx, osmNamespace, certManager); err != nil {
+		log.Fatal().Err(err).Msg("Error starting the SPIFFE bundle endpoint")
+	}
+
+	startHTTPServer()
+
+	<-stop
+	shutdown(cancel, shutdownTracing)
+	log.Info().Msgf("Stopping osm-controller %s; %s; %s", version.Version, version.GitCommit, version.BuildDate)
+}
@@ -25,15 +25,20 @@ import (
 	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	mcsClientset "sigs.k8s.io/mcs-api/pkg/client/clientset/versioned"
 
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
 	configClientset "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned"
 	policyClientset "github.com/openservicemesh/osm/pkg/gen/client/policy/clientset/versioned"
 
 	"github.com/openservicemesh/osm/pkg/catalog"
 	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/certificate/castorage/kms"
 	"github.com/openservicemesh/osm/pkg/certificate/providers"
+	"github.com/openservicemesh/osm/pkg/certificate/sharedcache"
+	"github.com/openservicemesh/osm/pkg/compute"
 	"github.com/openservicemesh/osm/pkg/compute/kube"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/debugger"
@@ -46,17 +51,37 @@ import (
 	"github.com/openservicemesh/osm/pkg/ingress"
 	"github.com/openservicemesh/osm/pkg/k8s"
 	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/leader"
 	"github.com/openservicemesh/osm/pkg/logger"
 	"github.com/openservicemesh/osm/pkg/messaging"
 	"github.com/openservicemesh/osm/pkg/metricsstore"
+	"github.com/openservicemesh/osm/pkg/models"
 	"github.com/openservicemesh/osm/pkg/osm"
+	proxylessxds "github.com/openservicemesh/osm/pkg/proxyless/xds"
 	"github.com/openservicemesh/osm/pkg/reconciler"
 	"github.com/openservicemesh/osm/pkg/signals"
 	"github.com/openservicemesh/osm/pkg/smi"
+	"github.com/openservicemesh/osm/pkg/spiffe"
+	"github.com/openservicemesh/osm/pkg/tracing"
 	"github.com/openservicemesh/osm/pkg/validator"
 	"github.com/openservicemesh/osm/pkg/version"
 )
 
+const (
+	// leaderElectionLeaseName is the name of the Lease through which the replicas of osm-controller elect a leader
+	leaderElectionLeaseName = "osm-controller-leader"
+
+	// sharedCertPruneInterval is the interval at which the leader deletes the expired shared certificates
+	sharedCertPruneInterval = time.Hour
+
+	// staleProxyReportInterval is the interval at which the leader reports the stale sidecars
+	staleProxyReportInterval = time.Minute
+
+	// statusResyncInterval is the interval at which the leader writes the statuses of the resources reconciled by the
+	// control plane
+	statusResyncInterval = 5 * time.Minute
+)
+
 var (
 	verbosity                  string
 	meshName                   string // An ID that uniquely identifies an OSM instance
@@ -77,6 +102,12 @@ var (
 	enableReconciler      bool
 	validateTrafficTarget bool
 
+	tracingOptions tracing.Options
+
+	configHistorySize int
+
+	manifestsDir string
+
 	scheme = runtime.NewScheme()
 )
 
@@ -98,6 +129,11 @@ func init() {
 	flags.StringVar(&certProviderKind, "certificate-manager", providers.TresorKind.String(), fmt.Sprintf("Certificate manager, one of [%v]", providers.ValidCertificateProviders))
 	flags.StringVar(&caBundleSecretName, "ca-bundle-secret-name", "", "Name of the Kubernetes Secret for the OSM CA bundle")
 
+	// Tresor certificate manager/provider options
+	flags.StringVar(&tresorOptions.KMS, "ca-key-kms", "", fmt.Sprintf("Key management service used to encrypt the CA private key, one of %v", kms.Registered()))
+	flags.StringVar(&tresorOptions.KMSKeyID, "ca-key-kms-key-id", "", "ID of the key encryption key in the key management service")
+	flags.StringToStringVar(&tresorOptions.KMSConfig, "ca-key-kms-config", nil, "Non-secret key management service specific parameters, e.g. address=https://vault:8200")
+
 	// TODO (#4502): Remove when we add full MRC support
 	flags.StringVar(&trustDomain, "trust-domain", "cluster.local", "The trust domain to use as part of the common name when requesting new certificates")
 
@@ -119,6 +155,17 @@ func init() {
 	flags.BoolVar(&enableReconciler, "enable-reconciler", false, "Enable reconciler for CDRs, mutating webhook and validating webhook")
 	flags.BoolVar(&validateTrafficTarget, "validate-traffic-target", true, "Enable traffic target validation")
 
+	// Tracing options
+	flags.StringVar(&tracingOptions.Endpoint, "tracing-endpoint", "", "Address of the OTLP gRPC collector the spans of the processing of events are exported to, tracing is disabled when empty")
+	flags.BoolVar(&tracingOptions.Insecure, "tracing-insecure", false, "Disable transport security for the connection to the OTLP collector")
+	flags.Float64Var(&tracingOptions.SamplingRatio, "tracing-sampling-ratio", 1, "Ratio of the events whose processing is traced, between 0 and 1")
+
+	// Debugging
+	flags.IntVar(&configHistorySize, "config-history-size", 0, "Number of versions of the configuration of every proxy kept for debugging, the history is disabled when 0")
+
+	// Compute provider
+	flags.StringVar(&manifestsDir, "manifests-dir", "", "Directory of YAML manifests the mesh is read from instead of the Kubernetes API server, for local development and testing")
+
 	_ = clientgoscheme.AddToScheme(scheme)
 	_ = admissionv1.AddToScheme(scheme)
 }
@@ -148,6 +195,11 @@ func main() {
 		log.Fatal().Err(err).Msg("Error setting log level")
 	}
 
+	if manifestsDir != "" {
+		runWithManifests()
+		return
+	}
+
 	// Initialize kube config and client
 	kubeConfig, err := clientcmd.BuildConfigFromFlags("", "")
 	if err != nil {
@@ -179,14 +231,26 @@ func main() {
 	// Start the default metrics store
 	startMetricsStore()
 
+	// Export the spans of the processing of events, from their arrival to the proxies acknowledging the resulting
+	// configuration, when enabled
+	shutdownTracing, err := tracing.Setup(ctx, "osm-controller", tracingOptions)
+	if err != nil {
+		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error setting up tracing")
+	}
+
 	msgBroker := messaging.NewBroker(stop)
 
 	smiTrafficSplitClientSet := smiTrafficSplitClient.NewForConfigOrDie(kubeConfig)
 	smiTrafficSpecClientSet := smiTrafficSpecClient.NewForConfigOrDie(kubeConfig)
 	smiTrafficTargetClientSet := smiAccessClient.NewForConfigOrDie(kubeConfig)
 
+	// The replicas of osm-controller elect a leader to perform the duties that must only be performed once, such as
+	// writing resource statuses
+	elector := leader.NewElector(kubeClient, osmNamespace, leaderElectionLeaseName, controllerPod.Name)
+
 	k8sClient, err := k8s.NewClient(osmNamespace, osmMeshConfigName, msgBroker,
 		k8s.WithKubeClient(kubeClient, meshName),
+		k8s.WithLeaderElection(elector.IsLeader),
 		k8s.WithSMIClients(smiTrafficSplitClientSet, smiTrafficSpecClientSet, smiTrafficTargetClientSet),
 		k8s.WithConfigClient(configClient),
 		k8s.WithPolicyClient(policyClient),
@@ -223,8 +287,85 @@ func main() {
 		}
 	}
 
-	ingress.Initialize(kubeClient, k8sClient, stop, certManager, msgBroker)
+	// Service certificates are shared among the replicas, so that a proxy reconnecting to another replica is served
+	// the certificate already issued for its identity. As they hold private keys, they are only shared when a key
+	// management service is configured to encrypt them.
+	if tresorOptions.KMS != "" {
+		keyWrapper, err := kms.New(tresorOptions.KMS, tresorOptions.KMSKeyID, tresorOptions.KMSConfig)
+		if err != nil {
+			events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error initializing the key management service encrypting shared certificates")
+		}
+		sharedCertCache, err := sharedcache.NewSecretCache(ctx, kubeClient, osmNamespace, keyWrapper)
+		if err != nil {
+			events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error initializing the shared certificate cache")
+		}
+		certManager.SetSharedCache(sharedCertCache)
+		elector.AddDuty(func(ctx context.Context) {
+			sharedCertCache.RunPruner(ctx, sharedCertPruneInterval)
+		})
+	} else {
+		log.Info().Msg("Service certificates are not shared among replicas, as no key management service is configured to encrypt them")
+	}
+
+	elector.AddDuty(func(ctx context.Context) {
+		reportStaleProxies(ctx, computeClient, staleProxyReportInterval)
+	})
+
+	elector.AddDuty(func(ctx context.Context) {
+		ingress.Initialize(kubeClient, k8sClient, ctx.Done(), certManager, msgBroker)
+	})
+
+	meshCatalog := startADSServer(ctx, cancel, stop, computeClient, certManager, msgBroker, kubeConfig, kubeClient)
+
+	if err := validator.NewValidatingWebhook(ctx, validatorWebhookConfigName, osmNamespace, osmVersion, meshName, enableReconciler, validateTrafficTarget, certManager, kubeClient, computeClient); err != nil {
+		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, fmt.Sprintf("Error starting the validating webhook server: %s", err))
+	}
+
+	// Start the k8s pod watcher that updates corresponding k8s secrets
+	go k8s.WatchAndUpdateProxyBootstrapSecret(kubeClient, msgBroker, stop)
+	// Start the global log level watcher that updates the log level dynamically
+	go k8s.WatchAndUpdateLogLevel(msgBroker, stop)
+
+	if enableReconciler {
+		log.Info().Msgf("OSM reconciler enabled for validating webhook")
+		// Only the leader reconciles the validating webhook, to avoid conflicting writes from multiple replicas
+		elector.AddDuty(func(ctx context.Context) {
+			err := reconciler.NewReconcilerClient(kubeClient, nil, meshName, osmVersion, ctx.Done(), reconciler.ValidatingWebhookInformerKey)
+			if err != nil {
+				events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating reconciler client to reconcile validating webhook")
+			}
+		})
+	}
+
+	// The leader writes the statuses skipped while it was not leading once it is elected, and periodically those of
+	// the IngressBackends whose proxies are connected to other replicas and of the UpstreamTrafficSettings
+	elector.AddDuty(func(ctx context.Context) {
+		reconcileStatuses(ctx, meshCatalog, statusResyncInterval)
+	})
+
+	go func() {
+		if err := elector.Run(ctx); err != nil {
+			events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error running leader election")
+		}
+	}()
+
+	// SPIFFE trust bundle of the mesh, fetched by federated trust domains over HTTPS
+	if err := spiffe.ServeBundleEndpoint(ctx, osmNamespace, certManager); err != nil {
+		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error starting the SPIFFE bundle endpoint")
+	}
+
+	startHTTPServer()
 
+	<-stop
+	shutdown(cancel, shutdownTracing)
+	log.Info().Msgf("Stopping osm-controller %s; %s; %s", version.Version, version.GitCommit, version.BuildDate)
+}
+
+// startADSServer creates the mesh catalog and serves the configuration generated from it to the proxies over ADS,
+// along with the debug server. The NACKs of the proxies are recorded as events on their pods when a Kubernetes client
+// is given.
+func startADSServer(ctx context.Context, cancel context.CancelFunc, stop chan struct{}, computeClient compute.Interface,
+	certManager *certificate.Manager, msgBroker *messaging.Broker, kubeConfig *rest.Config, kubeClient kubernetes.Interface) *catalog.MeshCatalog {
 	meshCatalog := catalog.NewMeshCatalog(
 		computeClient,
 		certManager,
@@ -234,40 +375,43 @@ func main() {
 
 	proxyRegistry := registry.NewProxyRegistry()
 	// Create and start the ADS gRPC service
-	xdsServer := server.NewADSServer()
-	xdsGenerator := generator.NewEnvoyConfigGenerator(meshCatalog, certManager)
+	xdsServerOpts := []server.Option{
+		server.WithStagedRollout(func() configv1alpha2.ConfigRolloutSpec {
+			return meshCatalog.GetMeshConfig().Spec.Sidecar.ConfigRollout
+		}),
+		server.WithConfigHistory(configHistorySize),
+	}
+	if kubeClient != nil {
+		xdsServerOpts = append(xdsServerOpts, server.WithNACKEvents(kubeClient))
+	}
+	xdsServer := server.NewADSServer(xdsServerOpts...)
+	// The config generators share the results of the catalog queries among the proxies with the same identity
+	cachedCatalog := catalog.NewCachedMeshCatalog(meshCatalog, msgBroker)
+	xdsGenerator := generator.NewEnvoyConfigGenerator(cachedCatalog, certManager)
+	configGenerator := osm.KindConfigGenerator[map[string][]types.Resource]{
+		models.KindSidecar:       xdsGenerator,
+		models.KindProxylessGRPC: proxylessxds.NewGenerator(cachedCatalog, certManager),
+	}
 
-	cp := osm.NewControlPlane[map[string][]types.Resource](xdsServer, xdsGenerator, meshCatalog, proxyRegistry, certManager, msgBroker)
+	cp := osm.NewControlPlane[map[string][]types.Resource](xdsServer, configGenerator, meshCatalog, proxyRegistry, certManager, msgBroker)
 	xdsServer.SetCallbacks(cp)
 
 	if err := xdsServer.Start(ctx, certManager, cancel, constants.ADSServerPort); err != nil {
 		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error initializing ADS server")
 	}
 
-	if err := validator.NewValidatingWebhook(ctx, validatorWebhookConfigName, osmNamespace, osmVersion, meshName, enableReconciler, validateTrafficTarget, certManager, kubeClient, computeClient); err != nil {
-		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, fmt.Sprintf("Error starting the validating webhook server: %s", err))
-	}
-
 	version.SetMetric()
 
 	// Create DebugServer and start its config event listener.
 	// Listener takes care to start and stop the debug server as appropriate
-	debugConfig := debugger.NewDebugConfig(certManager, xdsGenerator, proxyRegistry, kubeConfig, kubeClient, computeClient, msgBroker)
+	debugConfig := debugger.NewDebugConfig(certManager, xdsGenerator, xdsServer, xdsServer, proxyRegistry, kubeConfig, kubeClient, computeClient, msgBroker)
 	go debugConfig.StartDebugServerConfigListener(stop)
 
-	// Start the k8s pod watcher that updates corresponding k8s secrets
-	go k8s.WatchAndUpdateProxyBootstrapSecret(kubeClient, msgBroker, stop)
-	// Start the global log level watcher that updates the log level dynamically
-	go k8s.WatchAndUpdateLogLevel(msgBroker, stop)
-
-	if enableReconciler {
-		log.Info().Msgf("OSM reconciler enabled for validating webhook")
-		err = reconciler.NewReconcilerClient(kubeClient, nil, meshName, osmVersion, stop, reconciler.ValidatingWebhookInformerKey)
-		if err != nil {
-			events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating reconciler client to reconcile validating webhook")
-		}
-	}
+	return meshCatalog
+}
 
+// startHTTPServer starts OSM's HTTP server, serving the probes, metrics and version of osm-controller
+func startHTTPServer() {
 	// Initialize OSM's http service server
 	httpServer := httpserver.NewHTTPServer(constants.OSMHTTPServerPort)
 	// Health/Liveness probes
@@ -281,14 +425,19 @@ func main() {
 	httpServer.AddHandler(constants.OSMControllerSMIVersionPath, smi.GetSmiClientVersionHTTPHandler())
 
 	// Start HTTP server
-	err = httpServer.Start()
-	if err != nil {
+	if err := httpServer.Start(); err != nil {
 		log.Fatal().Err(err).Msgf("Failed to start OSM metrics/probes HTTP server")
 	}
+}
 
-	<-stop
+// shutdown cancels the context of osm-controller and flushes the pending spans
+func shutdown(cancel context.CancelFunc, shutdownTracing func(context.Context) error) {
 	cancel()
-	log.Info().Msgf("Stopping osm-controller %s; %s; %s", version.Version, version.GitCommit, version.BuildDate)
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelShutdown()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.Error().Err(err).Msg("Error flushing the pending spans")
+	}
 }
 
 // Start the metric store, register the metrics OSM will expose
@@ -308,13 +457,54 @@ func startMetricsStore() {
 		metricsstore.DefaultMetricsStore.FeatureFlagEnabled,
 		metricsstore.DefaultMetricsStore.VersionInfo,
 		metricsstore.DefaultMetricsStore.ProxyXDSRequestCount,
+		metricsstore.DefaultMetricsStore.ProxyXDSNACKCount,
 		metricsstore.DefaultMetricsStore.ProxyMaxConnectionsRejected,
+		metricsstore.DefaultMetricsStore.ProxyConfigRolloutCount,
+		metricsstore.DefaultMetricsStore.ProxyStale,
 		metricsstore.DefaultMetricsStore.AdmissionWebhookResponseTotal,
 		metricsstore.DefaultMetricsStore.EventsQueued,
 		metricsstore.DefaultMetricsStore.ReconciliationTotal,
 	)
 }
 
+// reconcileStatuses writes the statuses of the resources reconciled by the control plane at the given interval until
+// the given context is canceled
+func reconcileStatuses(ctx context.Context, meshCatalog *catalog.MeshCatalog, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		meshCatalog.ReconcileIngressBackendStatuses()
+		meshCatalog.ReconcileUpstreamTrafficSettingStatuses()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reportStaleProxies reports the stale sidecars of the mesh in the ProxyStale metric at the given interval, until the
+// given context is done
+func reportStaleProxies(ctx context.Context, computeClient compute.Interface, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	// Only the leader reports the stale sidecars
+	defer metricsstore.DefaultMetricsStore.ProxyStale.Reset()
+	for {
+		metricsstore.DefaultMetricsStore.ProxyStale.Reset()
+		for _, proxy := range computeClient.ListStaleProxies() {
+			for _, reason := range proxy.Reasons {
+				metricsstore.DefaultMetricsStore.ProxyStale.WithLabelValues(proxy.Namespace, proxy.Pod, string(reason)).Set(1)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 func parseFlags() error {
 	if err := flags.Parse(os.Args); err != nil {
 		return err
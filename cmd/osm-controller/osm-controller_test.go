@@ -1,9 +1,17 @@
 package main
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/compute"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+	"github.com/openservicemesh/osm/pkg/models"
 )
 
 func TestJoinURL(t *testing.T) {
@@ -25,3 +33,35 @@ func TestJoinURL(t *testing.T) {
 		assert.Equal(result, ju.expectedOutput)
 	}
 }
+
+func TestReportStaleProxies(t *testing.T) {
+	assert := tassert.New(t)
+
+	mockCtrl := gomock.NewController(t)
+	computeClient := compute.NewMockInterface(mockCtrl)
+	computeClient.EXPECT().ListStaleProxies().Return([]models.StaleProxy{
+		{
+			Namespace: "test",
+			Pod:       "pod-1",
+			Reasons:   []models.StaleProxyReason{models.StaleProxyReasonEnvoyImage, models.StaleProxyReasonInjectorVersion},
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		reportStaleProxies(ctx, computeClient, time.Hour)
+		close(done)
+	}()
+
+	staleProxies := metricsstore.DefaultMetricsStore.ProxyStale
+	assert.Eventually(func() bool {
+		return testutil.CollectAndCount(staleProxies) == 2
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(float64(1), testutil.ToFloat64(staleProxies.WithLabelValues("test", "pod-1", string(models.StaleProxyReasonEnvoyImage))))
+
+	// The stale proxies are no longer reported once the replica is no longer the leader
+	cancel()
+	<-done
+	assert.Zero(testutil.CollectAndCount(staleProxies))
+}
@@ -14,7 +14,8 @@ func validateCLIParams() error {
 		return fmt.Errorf("Please specify the OSM namespace using --osm-namespace")
 	}
 
-	if validatorWebhookConfigName == "" {
+	// The validating webhook is not run without a cluster
+	if validatorWebhookConfigName == "" && manifestsDir == "" {
 		return fmt.Errorf("Please specify the webhook configuration name using --validator-webhook-config")
 	}
 
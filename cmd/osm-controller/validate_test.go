@@ -12,6 +12,7 @@ func TestValidateCLIParams(t *testing.T) {
 		meshName                   string
 		osmNamespace               string
 		validatorWebhookConfigName string
+		manifestsDir               string
 		expectError                bool
 	}{
 		{
@@ -42,6 +43,14 @@ func TestValidateCLIParams(t *testing.T) {
 			validatorWebhookConfigName: "",
 			expectError:                true,
 		},
+		{
+			name:                       "validator webhook is empty when reading the mesh from manifests",
+			meshName:                   "test-mesh",
+			osmNamespace:               "test-ns",
+			validatorWebhookConfigName: "",
+			manifestsDir:               "./manifests",
+			expectError:                false,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -50,6 +59,7 @@ func TestValidateCLIParams(t *testing.T) {
 			meshName = tc.meshName
 			osmNamespace = tc.osmNamespace
 			validatorWebhookConfigName = tc.validatorWebhookConfigName
+			manifestsDir = tc.manifestsDir
 			err := validateCLIParams()
 			assert.Equal(err != nil, tc.expectError)
 		})
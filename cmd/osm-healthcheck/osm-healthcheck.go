@@ -1,5 +1,5 @@
 // Package main implements the main entrypoint for osm-healthcheck.
-// osm-healthcheck provides TCPSocket probe support for pods in the mesh.
+// osm-healthcheck provides TCPSocket and GRPC probe support for pods in the mesh.
 package main
 
 import (
@@ -11,6 +11,9 @@ import (
 	"time"
 
 	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
 
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/logger"
@@ -43,6 +46,7 @@ func main() {
 
 	serverMux := http.NewServeMux()
 	serverMux.HandleFunc("/osm-healthcheck", healthcheckHandler)
+	serverMux.HandleFunc(constants.GRPCHealthcheckPath, grpcHealthcheckHandler)
 
 	// Initialize osm-healthcheck HTTP server
 	server := &http.Server{
@@ -80,7 +84,7 @@ func healthcheckHandler(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	address := fmt.Sprintf("%s:%s", constants.LocalhostIPAddress, port)
+	address := net.JoinHostPort(constants.LocalhostIPAddress, port)
 	conn, err := net.Dial("tcp", address)
 	if err != nil {
 		msg := fmt.Sprintf("Failed to establish connection to %s", address)
@@ -98,6 +102,50 @@ func healthcheckHandler(w http.ResponseWriter, req *http.Request) {
 	setHealthcheckResponse(w, http.StatusOK, msg)
 }
 
+// grpcHealthcheckHandler handles HTTP requests and checks the health of the gRPC server of a container on the port
+// specified in the request's header, with the gRPC health checking protocol as the kubelet does for GRPC probes.
+// The service checked is specified in the request's header, the health of the server is checked otherwise.
+// If the server is serving, the response status code will be 200.
+func grpcHealthcheckHandler(w http.ResponseWriter, req *http.Request) {
+	port := req.Header.Get("Original-Grpc-Port")
+	if port == "" {
+		msg := "Header Original-Grpc-Port not found in request"
+		log.Error().Msg(msg)
+		setHealthcheckResponse(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	address := net.JoinHostPort(constants.LocalhostIPAddress, port)
+	conn, err := grpc.DialContext(req.Context(), address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		msg := fmt.Sprintf("Failed to establish connection to %s", address)
+		log.Error().Err(err).Msg(msg)
+		setHealthcheckResponse(w, http.StatusNotFound, msg)
+		return
+	}
+	defer conn.Close() //nolint: errcheck
+
+	service := req.Header.Get("Original-Grpc-Service")
+	resp, err := grpc_health_v1.NewHealthClient(conn).Check(req.Context(), &grpc_health_v1.HealthCheckRequest{Service: service})
+	if err != nil {
+		msg := fmt.Sprintf("Failed to check the health of service '%s' at %s", service, address)
+		log.Error().Err(err).Msg(msg)
+		setHealthcheckResponse(w, http.StatusServiceUnavailable, msg)
+		return
+	}
+
+	if resp.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING {
+		msg := fmt.Sprintf("Service '%s' at %s is %s", service, address, resp.GetStatus())
+		log.Debug().Msg(msg)
+		setHealthcheckResponse(w, http.StatusServiceUnavailable, msg)
+		return
+	}
+
+	msg := fmt.Sprintf("Service '%s' at %s is %s", service, address, resp.GetStatus())
+	log.Debug().Msg(msg)
+	setHealthcheckResponse(w, http.StatusOK, msg)
+}
+
 func setHealthcheckResponse(w http.ResponseWriter, responseCode int, msg string) {
 	w.WriteHeader(responseCode)
 	if _, err := w.Write([]byte(msg)); err != nil {
@@ -8,6 +8,9 @@ import (
 	"testing"
 
 	tassert "github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
 
 	"github.com/openservicemesh/osm/pkg/constants"
 )
@@ -80,3 +83,74 @@ func TestGetHealthcheckHander(t *testing.T) {
 		})
 	}
 }
+
+func TestGRPCHealthcheckHandler(t *testing.T) {
+	assert := tassert.New(t)
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(constants.LocalhostIPAddress, "0"))
+	assert.Nil(err)
+	grpcServer := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("serving-service", grpc_health_v1.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("not-serving-service", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	grpc_health_v1.RegisterHealthServer(grpcServer, healthServer)
+	go grpcServer.Serve(listener) //nolint: errcheck
+	defer grpcServer.Stop()
+
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	assert.Nil(err)
+
+	testCases := []struct {
+		name               string
+		port               string
+		service            string
+		expectedStatusCode int
+	}{
+		{
+			name:               "Bad request response when Original-Grpc-Port header is missing from request",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "OK response when the server is serving",
+			port:               port,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "OK response when the service is serving",
+			port:               port,
+			service:            "serving-service",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Service unavailable response when the service is not serving",
+			port:               port,
+			service:            "not-serving-service",
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:               "Service unavailable response when the service is unknown",
+			port:               port,
+			service:            "unknown-service",
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, constants.GRPCHealthcheckPath, nil)
+			if test.port != "" {
+				req.Header.Add("Original-Grpc-Port", test.port)
+			}
+			if test.service != "" {
+				req.Header.Add("Original-Grpc-Service", test.service)
+			}
+
+			w := httptest.NewRecorder()
+
+			grpcHealthcheckHandler(w, req)
+
+			res := w.Result()
+			assert.Equal(test.expectedStatusCode, res.StatusCode)
+		})
+	}
+}
@@ -31,6 +31,7 @@ import (
 	policyClientset "github.com/openservicemesh/osm/pkg/gen/client/policy/clientset/versioned"
 	"github.com/openservicemesh/osm/pkg/health"
 
+	"github.com/openservicemesh/osm/pkg/certificate/castorage/kms"
 	"github.com/openservicemesh/osm/pkg/certificate/providers"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/errcode"
@@ -68,6 +69,8 @@ var (
 
 	osmContainerPullPolicy string
 
+	enableCNI bool
+
 	scheme = runtime.NewScheme()
 )
 
@@ -90,6 +93,11 @@ func init() {
 	flags.StringVar(&certProviderKind, "certificate-manager", providers.TresorKind.String(), fmt.Sprintf("Certificate manager, one of [%v]", providers.ValidCertificateProviders))
 	flags.StringVar(&caBundleSecretName, "ca-bundle-secret-name", "", "Name of the Kubernetes Secret for the OSM CA bundle")
 
+	// Tresor certificate manager/provider options
+	flags.StringVar(&tresorOptions.KMS, "ca-key-kms", "", fmt.Sprintf("Key management service used to encrypt the CA private key, one of %v", kms.Registered()))
+	flags.StringVar(&tresorOptions.KMSKeyID, "ca-key-kms-key-id", "", "ID of the key encryption key in the key management service")
+	flags.StringToStringVar(&tresorOptions.KMSConfig, "ca-key-kms-config", nil, "Non-secret key management service specific parameters, e.g. address=https://vault:8200")
+
 	// TODO (#4502): Remove when we add full MRC support
 	flags.StringVar(&trustDomain, "trust-domain", "cluster.local", "The trust domain to use as part of the common name when requesting new certificates")
 
@@ -112,6 +120,8 @@ func init() {
 
 	flags.StringVar(&osmContainerPullPolicy, "osm-container-pull-policy", "", "The pullPolicy to use for injected init and healthcheck containers")
 
+	flags.BoolVar(&enableCNI, "enable-cni", false, "Redirect the traffic of pods to their sidecar with the OSM CNI plugin instead of an init container")
+
 	_ = clientgoscheme.AddToScheme(scheme)
 	_ = admissionv1.AddToScheme(scheme)
 }
@@ -226,7 +236,7 @@ func main() {
 	}
 
 	// Initialize the sidecar injector webhook
-	if err := injector.NewMutatingWebhook(ctx, kubeClient, certManager, kubeController, meshName, osmNamespace, webhookConfigName, osmVersion, webhookTimeout, enableReconciler, corev1.PullPolicy(osmContainerPullPolicy)); err != nil {
+	if err := injector.NewMutatingWebhook(ctx, kubeClient, certManager, kubeController, meshName, osmNamespace, webhookConfigName, osmVersion, webhookTimeout, enableReconciler, corev1.PullPolicy(osmContainerPullPolicy), enableCNI); err != nil {
 		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, fmt.Sprintf("Error creating sidecar injector webhook: %s", err))
 	}
 
@@ -234,6 +244,9 @@ func main() {
 	bootstrapSecretRotator := injector.NewBootstrapSecretRotator(computeClient, certManager, constants.CertCheckInterval)
 	bootstrapSecretRotator.StartBootstrapSecretRotationTicker(ctx)
 
+	// Create the bootstrap config of the proxies of workloads outside of Kubernetes
+	go injector.WatchWorkloadEntries(kubeClient, policyClient, certManager, kubeController, meshName, osmNamespace, msgBroker, stop)
+
 	version.SetMetric()
 	/*
 	 * Initialize osm-injector's HTTP server
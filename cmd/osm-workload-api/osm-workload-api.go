@@ -0,0 +1,240 @@
+// Package main implements the main entrypoint for osm-workload-api.
+// osm-workload-api is a node agent serving the SPIFFE Workload API, which issues mesh identity certificates
+// as X.509-SVIDs to workloads that consume them directly rather than through an Envoy sidecar.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/kubernetes"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/certificate/castorage/kms"
+	"github.com/openservicemesh/osm/pkg/certificate/providers"
+	"github.com/openservicemesh/osm/pkg/compute/kube"
+	"github.com/openservicemesh/osm/pkg/constants"
+	configClientset "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned"
+	"github.com/openservicemesh/osm/pkg/health"
+	"github.com/openservicemesh/osm/pkg/httpserver"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/messaging"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+	"github.com/openservicemesh/osm/pkg/signals"
+	"github.com/openservicemesh/osm/pkg/spiffe/workloadapi"
+	"github.com/openservicemesh/osm/pkg/version"
+)
+
+var (
+	verbosity          string
+	meshName           string // An ID that uniquely identifies an OSM instance
+	kubeConfigFile     string
+	osmNamespace       string
+	caBundleSecretName string
+	osmMeshConfigName  string
+	trustDomain        string
+	nodeName           string
+	socketPath         string
+
+	certProviderKind string
+
+	tresorOptions      providers.TresorOptions
+	vaultOptions       providers.VaultOptions
+	certManagerOptions providers.CertManagerOptions
+)
+
+var (
+	flags = pflag.NewFlagSet(`osm-workload-api`, pflag.ExitOnError)
+	log   = logger.New("osm-workload-api/main")
+)
+
+func init() {
+	flags.StringVarP(&verbosity, "verbosity", "v", "info", "Set log verbosity level")
+	flags.StringVar(&meshName, "mesh-name", "", "OSM mesh name")
+	flags.StringVar(&kubeConfigFile, "kubeconfig", "", "Path to Kubernetes config file.")
+	flags.StringVar(&osmNamespace, "osm-namespace", "", "Namespace to which OSM belongs to.")
+	flags.StringVar(&osmMeshConfigName, "osm-config-name", "osm-mesh-config", "Name of the OSM MeshConfig")
+	flags.StringVar(&nodeName, "node-name", os.Getenv("NODE_NAME"), "Name of the node on which the agent runs, defaults to the NODE_NAME env variable")
+	flags.StringVar(&socketPath, "socket-path", constants.WorkloadAPISocketPath, "Path of the unix domain socket on which the Workload API is served")
+
+	// Generic certificate manager/provider options
+	flags.StringVar(&certProviderKind, "certificate-manager", providers.TresorKind.String(), fmt.Sprintf("Certificate manager, one of [%v]", providers.ValidCertificateProviders))
+	flags.StringVar(&caBundleSecretName, "ca-bundle-secret-name", "", "Name of the Kubernetes Secret for the OSM CA bundle")
+
+	// Tresor certificate manager/provider options
+	flags.StringVar(&tresorOptions.KMS, "ca-key-kms", "", fmt.Sprintf("Key management service used to encrypt the CA private key, one of %v", kms.Registered()))
+	flags.StringVar(&tresorOptions.KMSKeyID, "ca-key-kms-key-id", "", "ID of the key encryption key in the key management service")
+	flags.StringToStringVar(&tresorOptions.KMSConfig, "ca-key-kms-config", nil, "Non-secret key management service specific parameters, e.g. address=https://vault:8200")
+
+	// TODO (#4502): Remove when we add full MRC support
+	flags.StringVar(&trustDomain, "trust-domain", "cluster.local", "The trust domain to use as part of the common name when requesting new certificates")
+
+	// Vault certificate manager/provider options
+	flags.StringVar(&vaultOptions.VaultProtocol, "vault-protocol", "http", "Host name of the Hashi Vault")
+	flags.StringVar(&vaultOptions.VaultHost, "vault-host", "vault.default.svc.cluster.local", "Host name of the Hashi Vault")
+	flags.StringVar(&vaultOptions.VaultToken, "vault-token", "", "Secret token for the the Hashi Vault")
+	flags.StringVar(&vaultOptions.VaultRole, "vault-role", "openservicemesh", "Name of the Vault role dedicated to Open Service Mesh")
+	flags.IntVar(&vaultOptions.VaultPort, "vault-port", 8200, "Port of the Hashi Vault")
+	flags.StringVar(&vaultOptions.VaultTokenSecretName, "vault-token-secret-name", "", "Name of the secret storing the Vault token used in OSM")
+	flags.StringVar(&vaultOptions.VaultTokenSecretKey, "vault-token-secret-key", "", "Key for the vault token used in OSM")
+
+	// Cert-manager certificate manager/provider options
+	flags.StringVar(&certManagerOptions.IssuerName, "cert-manager-issuer-name", "osm-ca", "cert-manager issuer name")
+	flags.StringVar(&certManagerOptions.IssuerKind, "cert-manager-issuer-kind", "Issuer", "cert-manager issuer kind")
+	flags.StringVar(&certManagerOptions.IssuerGroup, "cert-manager-issuer-group", "cert-manager.io", "cert-manager issuer group")
+}
+
+// TODO(#4502): This function can be deleted once we get rid of cert options.
+func getCertOptions() (providers.Options, error) {
+	switch providers.Kind(certProviderKind) {
+	case providers.TresorKind:
+		tresorOptions.SecretName = caBundleSecretName
+		return tresorOptions, nil
+	case providers.VaultKind:
+		vaultOptions.VaultTokenSecretNamespace = osmNamespace
+		return vaultOptions, nil
+	case providers.CertManagerKind:
+		return certManagerOptions, nil
+	}
+	return nil, fmt.Errorf("unknown certificate provider kind: %s", certProviderKind)
+}
+
+func main() {
+	log.Info().Msgf("Starting osm-workload-api %s; %s; %s", version.Version, version.GitCommit, version.BuildDate)
+	if err := parseFlags(); err != nil {
+		log.Fatal().Err(err).Msg("Error parsing cmd line arguments")
+	}
+	if err := logger.SetLogLevel(verbosity); err != nil {
+		log.Fatal().Err(err).Msg("Error setting log level")
+	}
+
+	// This ensures CLI parameters (and dependent values) are correct.
+	if err := validateCLIParams(); err != nil {
+		log.Fatal().Err(err).Msg("Error validating CLI parameters")
+	}
+
+	// Initialize kube config and client
+	kubeConfig, err := clientcmd.BuildConfigFromFlags("", kubeConfigFile)
+	if err != nil {
+		log.Fatal().Err(err).Msgf("Error creating kube config (kubeconfig=%s)", kubeConfigFile)
+	}
+	kubeClient := kubernetes.NewForConfigOrDie(kubeConfig)
+	configClient := configClientset.NewForConfigOrDie(kubeConfig)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stop := signals.RegisterExitHandlers(cancel)
+
+	// Start the default metrics store
+	metricsstore.DefaultMetricsStore.Start(
+		metricsstore.DefaultMetricsStore.CertIssuedCount,
+		metricsstore.DefaultMetricsStore.CertIssuedTime,
+		metricsstore.DefaultMetricsStore.ErrCodeCounter,
+		metricsstore.DefaultMetricsStore.HTTPResponseTotal,
+		metricsstore.DefaultMetricsStore.HTTPResponseDuration,
+	)
+
+	msgBroker := messaging.NewBroker(stop)
+
+	// Only the OSM config resources are watched cluster wide, pods are watched by the attestor for this node only
+	k8sClient, err := k8s.NewClient(osmNamespace, osmMeshConfigName, msgBroker,
+		k8s.WithConfigClient(configClient),
+	)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error creating kubernetes client")
+	}
+
+	computeClient := kube.NewClient(k8sClient)
+
+	certOpts, err := getCertOptions()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error getting certificate options")
+	}
+
+	// Intitialize certificate manager/provider
+	var certManager *certificate.Manager
+	enableMeshRootCertificate := computeClient.GetMeshConfig().Spec.FeatureFlags.EnableMeshRootCertificate
+	if enableMeshRootCertificate {
+		certManager, err = providers.NewCertificateManagerFromMRC(ctx, kubeClient, kubeConfig, osmNamespace,
+			certOpts, computeClient, 5*time.Second)
+		if err != nil {
+			log.Fatal().Err(err).Msgf("Error initializing certificate manager of kind %s from MRC", certProviderKind)
+		}
+	} else {
+		certManager, err = providers.NewCertificateManager(ctx, kubeClient, kubeConfig, osmNamespace,
+			certOpts, computeClient, constants.CertCheckInterval, trustDomain)
+		if err != nil {
+			log.Fatal().Err(err).Msgf("Error initializing certificate manager of kind %s", certProviderKind)
+		}
+	}
+
+	attestor, err := workloadapi.NewKubernetesAttestor(kubeClient, nodeName, meshName, stop)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error initializing workload attestor")
+	}
+
+	version.SetMetric()
+	/*
+	 * Initialize osm-workload-api's HTTP server
+	 */
+	httpServer := httpserver.NewHTTPServer(constants.OSMHTTPServerPort)
+	// Metrics
+	httpServer.AddHandler(constants.MetricsPath, metricsstore.DefaultMetricsStore.Handler())
+	// Version
+	httpServer.AddHandler(constants.VersionPath, version.GetVersionHandler())
+	// Health checks
+	httpServer.AddHandler(constants.WebhookHealthPath, http.HandlerFunc(health.SimpleHandler))
+
+	// Start HTTP server
+	err = httpServer.Start()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("Failed to start OSM metrics/probes HTTP server")
+	}
+
+	// Start the global log level watcher that updates the log level dynamically
+	go k8s.WatchAndUpdateLogLevel(msgBroker, stop)
+
+	server := workloadapi.NewServer(certManager, attestor, k8sClient)
+	if err := server.Serve(ctx, socketPath); err != nil {
+		log.Fatal().Err(err).Msg("Error serving the SPIFFE Workload API")
+	}
+
+	log.Info().Msgf("Stopping osm-workload-api %s; %s; %s", version.Version, version.GitCommit, version.BuildDate)
+}
+
+func parseFlags() error {
+	if err := flags.Parse(os.Args); err != nil {
+		return err
+	}
+	_ = flag.CommandLine.Parse([]string{})
+	return nil
+}
+
+// validateCLIParams contains all checks necessary that various permutations of the CLI flags are consistent
+func validateCLIParams() error {
+	if meshName == "" {
+		return fmt.Errorf("Please specify the mesh name using --mesh-name")
+	}
+
+	if osmNamespace == "" {
+		return fmt.Errorf("Please specify the OSM namespace using --osm-namespace")
+	}
+
+	if nodeName == "" {
+		return fmt.Errorf("Please specify the node name using --node-name or the NODE_NAME env variable")
+	}
+
+	if caBundleSecretName == "" {
+		return fmt.Errorf("Please specify the CA bundle secret name using --ca-bundle-secret-name")
+	}
+
+	return nil
+}
@@ -50,6 +50,31 @@ const (
 	LocalProxyModePodIP LocalProxyMode = "PodIP"
 )
 
+// TrafficRedirectionBackend is a type alias representing the packet filtering framework used to redirect the traffic of
+// a pod to its envoy sidecar
+type TrafficRedirectionBackend string
+
+const (
+	// TrafficRedirectionBackendIptables indicates that the traffic should be redirected with iptables rules
+	TrafficRedirectionBackendIptables TrafficRedirectionBackend = "iptables"
+	// TrafficRedirectionBackendNftables indicates that the traffic should be redirected with nftables rules
+	TrafficRedirectionBackendNftables TrafficRedirectionBackend = "nftables"
+)
+
+// SidecarContainerMode is a type alias representing how the envoy sidecar is declared in the pod spec
+type SidecarContainerMode string
+
+const (
+	// SidecarContainerModeAuto indicates that the sidecar should be injected as a native sidecar container when the
+	// Kubernetes API server enables native sidecar containers by default, and as a regular container otherwise
+	SidecarContainerModeAuto SidecarContainerMode = "Auto"
+	// SidecarContainerModeNative indicates that the sidecar should be injected as a native sidecar container, i.e. an
+	// init container with the `Always` restart policy
+	SidecarContainerModeNative SidecarContainerMode = "Native"
+	// SidecarContainerModeRegular indicates that the sidecar should be injected as a regular container
+	SidecarContainerModeRegular SidecarContainerMode = "Regular"
+)
+
 // SidecarSpec is the type used to represent the specifications for the proxy sidecar.
 type SidecarSpec struct {
 	// EnablePrivilegedInitContainer defines a boolean indicating whether the init container for a meshed pod should run as privileged.
@@ -73,6 +98,9 @@ type SidecarSpec struct {
 	// ConfigResyncInterval defines the resync interval for regular proxy broadcast updates.
 	ConfigResyncInterval string `json:"configResyncInterval,omitempty"`
 
+	// ConfigRollout defines the staged rollout of configuration changes to the proxies of each service identity.
+	ConfigRollout ConfigRolloutSpec `json:"configRollout,omitempty"`
+
 	// Resources defines the compute resources for the sidecar.
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 
@@ -90,6 +118,45 @@ type SidecarSpec struct {
 
 	// LocalProxyMode defines the network interface the envoy proxy will use to send traffic to the backend service application. Acceptable values are [`Localhost`, `PodIP`]. The default is `Localhost`
 	LocalProxyMode LocalProxyMode `json:"localProxyMode,omitempty"`
+
+	// TrafficRedirectionBackend defines the packet filtering framework used to redirect the traffic of pods to their envoy sidecar. Acceptable values are [`iptables`, `nftables`]. The default is `iptables`
+	TrafficRedirectionBackend TrafficRedirectionBackend `json:"trafficRedirectionBackend,omitempty"`
+
+	// ContainerMode defines how the envoy sidecar is declared in the pod spec. Acceptable values are [`Auto`, `Native`, `Regular`]. The default is `Auto`, which injects native sidecar containers on Kubernetes 1.29+
+	ContainerMode SidecarContainerMode `json:"containerMode,omitempty"`
+
+	// HoldApplicationUntilProxyStarts defines a boolean indicating whether the application containers of a pod are started once its sidecar received its initial configuration. The start of the application containers is always held when the sidecar is injected as a native sidecar container.
+	HoldApplicationUntilProxyStarts bool `json:"holdApplicationUntilProxyStarts,omitempty"`
+
+	// Drain defines the draining of the sidecar's connections when its pod terminates.
+	Drain ProxyDrainSpec `json:"drain,omitempty"`
+}
+
+// ProxyDrainSpec is the type used to represent the draining of the connections of the proxy sidecar when its pod terminates.
+type ProxyDrainSpec struct {
+	// Enable defines a boolean indicating if the sidecar fails its health checks and drains its connections before it is stopped.
+	Enable bool `json:"enable"`
+
+	// Timeout defines the maximum duration to wait for the active connections of the sidecar to drop to MaxActiveConnections. Defaults to 30s.
+	Timeout string `json:"timeout,omitempty"`
+
+	// MaxActiveConnections defines the number of active downstream connections of the sidecar at or below which it is stopped. Defaults to 0.
+	MaxActiveConnections int `json:"maxActiveConnections,omitempty"`
+}
+
+// ConfigRolloutSpec is the type used to represent the staged rollout of configuration changes to the proxies.
+type ConfigRolloutSpec struct {
+	// Enable defines a boolean indicating if configuration changes are rolled out to a percentage of the proxies of each service identity first, and to the remaining proxies after a bake period.
+	Enable bool `json:"enable"`
+
+	// CanaryPercentage defines the percentage of the proxies of a service identity that configuration changes are rolled out to first. Defaults to 10.
+	CanaryPercentage int `json:"canaryPercentage,omitempty"`
+
+	// BakePeriod defines the duration for which the canary proxies are watched before a configuration change is rolled out to the remaining proxies. Defaults to 2m.
+	BakePeriod string `json:"bakePeriod,omitempty"`
+
+	// ErrorRateThreshold defines the maximum increase, in percentage points, of the upstream 5xx error rate of the canary proxies over the remaining proxies during the bake period, above which the rollout is halted. Defaults to 5.
+	ErrorRateThreshold int `json:"errorRateThreshold,omitempty"`
 }
 
 // TrafficSpec is the type used to represent OSM's traffic management configuration.
@@ -238,4 +305,8 @@ type FeatureFlags struct {
 	// EnableMeshRootCertificate defines if MRCs are used for certificate management.
 	// If enabled after install, the control plane must be restarted to pick up on the update.
 	EnableMeshRootCertificate bool `json:"enableMeshRootCertificate"`
+
+	// EnableDeltaXDS defines if proxies use the incremental (delta) xDS protocol, so that only changed resources
+	// are sent to them. Only applies to proxies injected after it is enabled.
+	EnableDeltaXDS bool `json:"enableDeltaXDS"`
 }
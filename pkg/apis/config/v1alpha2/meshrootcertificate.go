@@ -116,6 +116,26 @@ type TresorProviderSpec struct {
 type TresorCASpec struct {
 	// SecretRef specifies the secret in which the root certificate is stored
 	SecretRef corev1.SecretReference `json:"secretRef"`
+
+	// KeyEncryption specifies how the root certificate's private key is encrypted at rest.
+	// If unset, the private key is stored unencrypted in the secret.
+	// +optional
+	KeyEncryption *KeyEncryptionSpec `json:"keyEncryption,omitempty"`
+}
+
+// KeyEncryptionSpec defines the envelope encryption of a private key, where the data encryption key
+// is wrapped by a key management service
+type KeyEncryptionSpec struct {
+	// KMS specifies the name of the key management service used to wrap the data encryption key,
+	// one of local, vault-transit or aws-kms
+	KMS string `json:"kms"`
+
+	// KeyID identifies the key encryption key within the key management service
+	KeyID string `json:"keyID"`
+
+	// Config specifies parameters specific to the key management service
+	// +optional
+	Config map[string]string `json:"config,omitempty"`
 }
 
 // MeshRootCertificateRole specifies the role of the MeshRootCertificate
@@ -43,6 +43,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&MeshRootCertificateList{},
 		&ExtensionService{},
 		&ExtensionServiceList{},
+		&TrustDomainFederation{},
+		&TrustDomainFederationList{},
+		&SidecarProfile{},
+		&SidecarProfileList{},
 	)
 
 	metav1.AddToGroupVersion(
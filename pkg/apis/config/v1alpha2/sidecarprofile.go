@@ -0,0 +1,64 @@
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// SidecarProfile defines settings of the proxy sidecar overriding the MeshConfig for the
+// pods of the namespaces and workloads it selects.
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type SidecarProfile struct {
+	// Object's type metadata.
+	metav1.TypeMeta `json:",inline"`
+
+	// Object's metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the specification of the sidecar profile.
+	// +optional
+	Spec SidecarProfileSpec `json:"spec,omitempty"`
+}
+
+// SidecarProfileSpec defines the specification of a sidecar profile.
+type SidecarProfileSpec struct {
+	// NamespaceSelector selects the namespaces of the pods the profile applies to by their labels.
+	// It is only honored for profiles in the OSM control plane namespace, which apply to the pods
+	// of all the namespaces it selects. Profiles in other namespaces only apply to the pods of
+	// their own namespace.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// PodSelector selects the pods the profile applies to by their labels. Profiles without a
+	// PodSelector apply to all the pods of the namespaces they select.
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+
+	// Priority defines the precedence of the profile over the other profiles matching a pod at
+	// the same level of specificity. Profiles with a higher priority take precedence.
+	// +optional
+	Priority int `json:"priority,omitempty"`
+
+	// Sidecar defines the MeshConfig sidecar settings overridden by the profile, with the same
+	// fields as the sidecar section of the MeshConfig. Fields that are not set keep the value of
+	// the MeshConfig.
+	// +optional
+	Sidecar *runtime.RawExtension `json:"sidecar,omitempty"`
+
+	// Concurrency defines the number of worker threads of the sidecar. Defaults to the number of
+	// CPU cores of the node.
+	// +optional
+	Concurrency *int32 `json:"concurrency,omitempty"`
+}
+
+// SidecarProfileList defines the list of SidecarProfile objects.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type SidecarProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []SidecarProfile `json:"items"`
+}
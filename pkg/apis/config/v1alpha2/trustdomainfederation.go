@@ -0,0 +1,44 @@
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TrustDomainFederation defines a foreign SPIFFE trust domain whose workloads
+// are trusted by the mesh, along with the trust bundle used to verify them.
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TrustDomainFederation struct {
+	// Object's type metadata.
+	metav1.TypeMeta `json:",inline"`
+
+	// Object's metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the specification of the federated trust domain.
+	// +optional
+	Spec TrustDomainFederationSpec `json:"spec,omitempty"`
+}
+
+// TrustDomainFederationSpec defines the specification of a federated trust domain.
+type TrustDomainFederationSpec struct {
+	// TrustDomain is the name of the foreign trust domain, e.g. "other-domain".
+	// Identities of the form spiffe://<TrustDomain>/... are verified using TrustBundle.
+	TrustDomain string `json:"trustDomain"`
+
+	// TrustBundle is the trust bundle of the foreign trust domain. It is either a
+	// set of PEM encoded X.509 CA certificates, or a SPIFFE bundle in JWK Set format
+	// as served by the foreign trust domain's bundle endpoint.
+	TrustBundle string `json:"trustBundle"`
+}
+
+// TrustDomainFederationList defines the list of TrustDomainFederation objects.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TrustDomainFederationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []TrustDomainFederation `json:"items"`
+}
@@ -60,6 +60,22 @@ func (in *CertificateSpec) DeepCopy() *CertificateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigRolloutSpec) DeepCopyInto(out *ConfigRolloutSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigRolloutSpec.
+func (in *ConfigRolloutSpec) DeepCopy() *ConfigRolloutSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigRolloutSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExtensionService) DeepCopyInto(out *ExtensionService) {
 	*out = *in
@@ -195,6 +211,29 @@ func (in *IngressGatewayCertSpec) DeepCopy() *IngressGatewayCertSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyEncryptionSpec) DeepCopyInto(out *KeyEncryptionSpec) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyEncryptionSpec.
+func (in *KeyEncryptionSpec) DeepCopy() *KeyEncryptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyEncryptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MeshConfig) DeepCopyInto(out *MeshConfig) {
 	*out = *in
@@ -451,7 +490,7 @@ func (in *ProviderSpec) DeepCopyInto(out *ProviderSpec) {
 	if in.Tresor != nil {
 		in, out := &in.Tresor, &out.Tresor
 		*out = new(TresorProviderSpec)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
@@ -466,6 +505,22 @@ func (in *ProviderSpec) DeepCopy() *ProviderSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyDrainSpec) DeepCopyInto(out *ProxyDrainSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyDrainSpec.
+func (in *ProxyDrainSpec) DeepCopy() *ProxyDrainSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyDrainSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretKeyReferenceSpec) DeepCopyInto(out *SecretKeyReferenceSpec) {
 	*out = *in
@@ -482,9 +537,106 @@ func (in *SecretKeyReferenceSpec) DeepCopy() *SecretKeyReferenceSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarProfile) DeepCopyInto(out *SidecarProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarProfile.
+func (in *SidecarProfile) DeepCopy() *SidecarProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SidecarProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarProfileList) DeepCopyInto(out *SidecarProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SidecarProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarProfileList.
+func (in *SidecarProfileList) DeepCopy() *SidecarProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SidecarProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarProfileSpec) DeepCopyInto(out *SidecarProfileSpec) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sidecar != nil {
+		in, out := &in.Sidecar, &out.Sidecar
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Concurrency != nil {
+		in, out := &in.Concurrency, &out.Concurrency
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarProfileSpec.
+func (in *SidecarProfileSpec) DeepCopy() *SidecarProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SidecarSpec) DeepCopyInto(out *SidecarSpec) {
 	*out = *in
+	out.ConfigRollout = in.ConfigRollout
 	in.Resources.DeepCopyInto(&out.Resources)
 	if in.CipherSuites != nil {
 		in, out := &in.CipherSuites, &out.CipherSuites
@@ -496,6 +648,7 @@ func (in *SidecarSpec) DeepCopyInto(out *SidecarSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	out.Drain = in.Drain
 	return
 }
 
@@ -571,6 +724,11 @@ func (in *TrafficSpec) DeepCopy() *TrafficSpec {
 func (in *TresorCASpec) DeepCopyInto(out *TresorCASpec) {
 	*out = *in
 	out.SecretRef = in.SecretRef
+	if in.KeyEncryption != nil {
+		in, out := &in.KeyEncryption, &out.KeyEncryption
+		*out = new(KeyEncryptionSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -587,7 +745,7 @@ func (in *TresorCASpec) DeepCopy() *TresorCASpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TresorProviderSpec) DeepCopyInto(out *TresorProviderSpec) {
 	*out = *in
-	out.CA = in.CA
+	in.CA.DeepCopyInto(&out.CA)
 	return
 }
 
@@ -601,6 +759,82 @@ func (in *TresorProviderSpec) DeepCopy() *TresorProviderSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustDomainFederation) DeepCopyInto(out *TrustDomainFederation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustDomainFederation.
+func (in *TrustDomainFederation) DeepCopy() *TrustDomainFederation {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustDomainFederation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TrustDomainFederation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustDomainFederationList) DeepCopyInto(out *TrustDomainFederationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TrustDomainFederation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustDomainFederationList.
+func (in *TrustDomainFederationList) DeepCopy() *TrustDomainFederationList {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustDomainFederationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TrustDomainFederationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustDomainFederationSpec) DeepCopyInto(out *TrustDomainFederationSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustDomainFederationSpec.
+func (in *TrustDomainFederationSpec) DeepCopy() *TrustDomainFederationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustDomainFederationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VaultProviderSpec) DeepCopyInto(out *VaultProviderSpec) {
 	*out = *in
@@ -47,6 +47,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&UpstreamTrafficSettingList{},
 		&Telemetry{},
 		&TelemetryList{},
+		&WorkloadEntry{},
+		&WorkloadEntryList{},
 	)
 
 	metav1.AddToGroupVersion(
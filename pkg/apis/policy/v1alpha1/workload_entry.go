@@ -0,0 +1,51 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkloadEntry is the type used to represent a workload running outside of Kubernetes, such as on a virtual
+// machine or a bare-metal host, that is part of the mesh. Its address is an endpoint of the Kubernetes services whose
+// selector matches its labels, and its proxy is identified by its service account.
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type WorkloadEntry struct {
+	// Object's type metadata
+	metav1.TypeMeta `json:",inline"`
+
+	// Object's metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the WorkloadEntry specification
+	// +optional
+	Spec WorkloadEntrySpec `json:"spec,omitempty"`
+}
+
+// WorkloadEntrySpec is the type used to represent the WorkloadEntry specification.
+type WorkloadEntrySpec struct {
+	// Address defines the IP address of the workload.
+	Address string `json:"address"`
+
+	// Ports defines the ports the workload listens on, keyed by the name of the service port they back. A service
+	// port not listed is backed by the port of its targetPort on the workload.
+	// +optional
+	Ports map[string]uint32 `json:"ports,omitempty"`
+
+	// ServiceAccount defines the name of the service account, in the namespace of the WorkloadEntry, whose
+	// identity the workload's proxy has.
+	ServiceAccount string `json:"serviceAccount"`
+
+	// Labels defines the labels of the workload, which the selectors of services are matched against.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// WorkloadEntryList defines the list of WorkloadEntry objects.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type WorkloadEntryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []WorkloadEntry `json:"items"`
+}
@@ -0,0 +1,182 @@
+package catalog
+
+import (
+	"fmt"
+	"sync"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/openservicemesh/osm/pkg/endpoint"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/messaging"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// CachedMeshCatalog is a MeshCataloger that memoizes the results of the queries scoped to a service identity or a
+// set of services, so that they are computed once for all the proxies sharing them, e.g. the replicas of a deployment.
+// The cached results are invalidated whenever the generation of the mesh changes, i.e. on every event processed by
+// the message broker.
+// The cached results are shared by all callers, and must not be modified.
+type CachedMeshCatalog struct {
+	MeshCataloger
+
+	generation func() uint64
+
+	mu              sync.Mutex
+	cacheGeneration uint64
+	entries         map[string]interface{}
+
+	// group ensures concurrent callers computing the same entry only compute it once
+	group singleflight.Group
+}
+
+// NewCachedMeshCatalog returns a CachedMeshCatalog wrapping the given MeshCataloger, whose results are invalidated
+// on every event processed by the given message broker.
+func NewCachedMeshCatalog(mc MeshCataloger, msgBroker *messaging.Broker) *CachedMeshCatalog {
+	return &CachedMeshCatalog{
+		MeshCataloger: mc,
+		generation:    msgBroker.GetTotalQEventCount,
+		entries:       make(map[string]interface{}),
+	}
+}
+
+// cached returns the entry for the given key at the current generation, computing it if it isn't cached.
+// Errors are not cached.
+func cached[T any](c *CachedMeshCatalog, key string, compute func() (T, error)) (T, error) {
+	// The generation is read before computing the entry, so that an entry computed from a state older than the
+	// current generation is never cached.
+	generation := c.generation()
+
+	c.mu.Lock()
+	if generation > c.cacheGeneration {
+		c.entries = make(map[string]interface{})
+		c.cacheGeneration = generation
+	}
+	if v, ok := c.entries[key]; ok {
+		c.mu.Unlock()
+		return v.(T), nil
+	}
+	c.mu.Unlock()
+
+	v, err, _ := c.group.Do(fmt.Sprintf("%d/%s", generation, key), func() (interface{}, error) {
+		v, err := compute()
+		if err != nil {
+			return v, err
+		}
+		c.mu.Lock()
+		if c.cacheGeneration == generation {
+			c.entries[key] = v
+		}
+		c.mu.Unlock()
+		return v, nil
+	})
+	return v.(T), err
+}
+
+// cachedNoErr is the same as cached, for queries that don't return errors
+func cachedNoErr[T any](c *CachedMeshCatalog, key string, compute func() T) T {
+	v, _ := cached(c, key, func() (T, error) {
+		return compute(), nil
+	})
+	return v
+}
+
+// ListOutboundServicesForIdentity list the services the given service identity is allowed to initiate outbound connections to
+func (c *CachedMeshCatalog) ListOutboundServicesForIdentity(si identity.ServiceIdentity) []service.MeshService {
+	return cachedNoErr(c, fmt.Sprintf("ListOutboundServicesForIdentity/%s", si), func() []service.MeshService {
+		return c.MeshCataloger.ListOutboundServicesForIdentity(si)
+	})
+}
+
+// ListInboundServiceIdentities lists the downstream service identities that are allowed to connect to the given service identity
+func (c *CachedMeshCatalog) ListInboundServiceIdentities(si identity.ServiceIdentity) []identity.ServiceIdentity {
+	return cachedNoErr(c, fmt.Sprintf("ListInboundServiceIdentities/%s", si), func() []identity.ServiceIdentity {
+		return c.MeshCataloger.ListInboundServiceIdentities(si)
+	})
+}
+
+// ListOutboundServiceIdentities lists the upstream service identities the given service identity are allowed to connect to
+func (c *CachedMeshCatalog) ListOutboundServiceIdentities(si identity.ServiceIdentity) []identity.ServiceIdentity {
+	return cachedNoErr(c, fmt.Sprintf("ListOutboundServiceIdentities/%s", si), func() []identity.ServiceIdentity {
+		return c.MeshCataloger.ListOutboundServiceIdentities(si)
+	})
+}
+
+// ListAllowedUpstreamEndpointsForService returns the list of endpoints over which the downstream client identity
+// is allowed access the upstream service
+func (c *CachedMeshCatalog) ListAllowedUpstreamEndpointsForService(si identity.ServiceIdentity, svc service.MeshService) []endpoint.Endpoint {
+	return cachedNoErr(c, fmt.Sprintf("ListAllowedUpstreamEndpointsForService/%s/%v", si, svc), func() []endpoint.Endpoint {
+		return c.MeshCataloger.ListAllowedUpstreamEndpointsForService(si, svc)
+	})
+}
+
+// ListInboundTrafficTargetsWithRoutes returns a list traffic target objects composed of its routes for the given destination service identity
+func (c *CachedMeshCatalog) ListInboundTrafficTargetsWithRoutes(si identity.ServiceIdentity) ([]trafficpolicy.TrafficTargetWithRoutes, error) {
+	return cached(c, fmt.Sprintf("ListInboundTrafficTargetsWithRoutes/%s", si), func() ([]trafficpolicy.TrafficTargetWithRoutes, error) {
+		return c.MeshCataloger.ListInboundTrafficTargetsWithRoutes(si)
+	})
+}
+
+// GetInboundMeshClusterConfigs returns the cluster configs for the inbound mesh traffic policy for the given upstream services
+func (c *CachedMeshCatalog) GetInboundMeshClusterConfigs(services []service.MeshService) []*trafficpolicy.MeshClusterConfig {
+	return cachedNoErr(c, fmt.Sprintf("GetInboundMeshClusterConfigs/%v", services), func() []*trafficpolicy.MeshClusterConfig {
+		return c.MeshCataloger.GetInboundMeshClusterConfigs(services)
+	})
+}
+
+// GetInboundMeshTrafficMatches returns the traffic matches for the inbound mesh traffic policy for the given upstream services
+func (c *CachedMeshCatalog) GetInboundMeshTrafficMatches(services []service.MeshService) []*trafficpolicy.TrafficMatch {
+	return cachedNoErr(c, fmt.Sprintf("GetInboundMeshTrafficMatches/%v", services), func() []*trafficpolicy.TrafficMatch {
+		return c.MeshCataloger.GetInboundMeshTrafficMatches(services)
+	})
+}
+
+// GetInboundMeshHTTPRouteConfigsPerPort returns a map of the given inbound traffic policy per port for the given upstream identity and services
+func (c *CachedMeshCatalog) GetInboundMeshHTTPRouteConfigsPerPort(si identity.ServiceIdentity, services []service.MeshService) map[int][]*trafficpolicy.InboundTrafficPolicy {
+	return cachedNoErr(c, fmt.Sprintf("GetInboundMeshHTTPRouteConfigsPerPort/%s/%v", si, services), func() map[int][]*trafficpolicy.InboundTrafficPolicy {
+		return c.MeshCataloger.GetInboundMeshHTTPRouteConfigsPerPort(si, services)
+	})
+}
+
+// GetOutboundMeshClusterConfigs returns the cluster configs for the outbound mesh traffic policy for the given downstream identity
+func (c *CachedMeshCatalog) GetOutboundMeshClusterConfigs(si identity.ServiceIdentity) []*trafficpolicy.MeshClusterConfig {
+	return cachedNoErr(c, fmt.Sprintf("GetOutboundMeshClusterConfigs/%s", si), func() []*trafficpolicy.MeshClusterConfig {
+		return c.MeshCataloger.GetOutboundMeshClusterConfigs(si)
+	})
+}
+
+// GetOutboundMeshTrafficMatches returns the traffic matches for the outbound mesh traffic policy for the given downstream identity
+func (c *CachedMeshCatalog) GetOutboundMeshTrafficMatches(si identity.ServiceIdentity) []*trafficpolicy.TrafficMatch {
+	return cachedNoErr(c, fmt.Sprintf("GetOutboundMeshTrafficMatches/%s", si), func() []*trafficpolicy.TrafficMatch {
+		return c.MeshCataloger.GetOutboundMeshTrafficMatches(si)
+	})
+}
+
+// GetOutboundMeshHTTPRouteConfigsPerPort returns a map of the given outbound traffic policy per port for the given downstream identity
+func (c *CachedMeshCatalog) GetOutboundMeshHTTPRouteConfigsPerPort(si identity.ServiceIdentity) map[int][]*trafficpolicy.OutboundTrafficPolicy {
+	return cachedNoErr(c, fmt.Sprintf("GetOutboundMeshHTTPRouteConfigsPerPort/%s", si), func() map[int][]*trafficpolicy.OutboundTrafficPolicy {
+		return c.MeshCataloger.GetOutboundMeshHTTPRouteConfigsPerPort(si)
+	})
+}
+
+// GetEgressClusterConfigs returns the cluster configs for the egress traffic policy associated with the given service identity.
+func (c *CachedMeshCatalog) GetEgressClusterConfigs(si identity.ServiceIdentity) ([]*trafficpolicy.EgressClusterConfig, error) {
+	return cached(c, fmt.Sprintf("GetEgressClusterConfigs/%s", si), func() ([]*trafficpolicy.EgressClusterConfig, error) {
+		return c.MeshCataloger.GetEgressClusterConfigs(si)
+	})
+}
+
+// GetEgressTrafficMatches returns the traffic matches for the egress traffic policy associated with the given service identity.
+func (c *CachedMeshCatalog) GetEgressTrafficMatches(si identity.ServiceIdentity) ([]*trafficpolicy.TrafficMatch, error) {
+	return cached(c, fmt.Sprintf("GetEgressTrafficMatches/%s", si), func() ([]*trafficpolicy.TrafficMatch, error) {
+		return c.MeshCataloger.GetEgressTrafficMatches(si)
+	})
+}
+
+// GetEgressHTTPRouteConfigsPerPort returns a map of the given egress http route config per port for the egress traffic policy associated with the given service identity.
+func (c *CachedMeshCatalog) GetEgressHTTPRouteConfigsPerPort(si identity.ServiceIdentity) map[int][]*trafficpolicy.EgressHTTPRouteConfig {
+	return cachedNoErr(c, fmt.Sprintf("GetEgressHTTPRouteConfigsPerPort/%s", si), func() map[int][]*trafficpolicy.EgressHTTPRouteConfig {
+		return c.MeshCataloger.GetEgressHTTPRouteConfigsPerPort(si)
+	})
+}
@@ -0,0 +1,71 @@
+package catalog
+
+import (
+	"errors"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// countingCataloger counts the calls made to the queries it implements
+type countingCataloger struct {
+	MeshCataloger
+	calls map[string]int
+	err   error
+}
+
+func (c *countingCataloger) ListOutboundServicesForIdentity(si identity.ServiceIdentity) []service.MeshService {
+	c.calls["ListOutboundServicesForIdentity/"+si.String()]++
+	return []service.MeshService{{Namespace: si.ToK8sServiceAccount().Namespace, Name: "svc"}}
+}
+
+func (c *countingCataloger) GetEgressClusterConfigs(si identity.ServiceIdentity) ([]*trafficpolicy.EgressClusterConfig, error) {
+	c.calls["GetEgressClusterConfigs/"+si.String()]++
+	return nil, c.err
+}
+
+func TestCachedMeshCatalog(t *testing.T) {
+	assert := tassert.New(t)
+
+	var generation uint64
+	mc := &countingCataloger{calls: make(map[string]int)}
+	c := &CachedMeshCatalog{
+		MeshCataloger: mc,
+		generation:    func() uint64 { return generation },
+		entries:       make(map[string]interface{}),
+	}
+
+	id1 := identity.New("sa1", "ns1")
+	id2 := identity.New("sa2", "ns2")
+
+	// Replicas with the same identity share the result
+	expected := []service.MeshService{{Namespace: "ns1", Name: "svc"}}
+	assert.Equal(expected, c.ListOutboundServicesForIdentity(id1))
+	assert.Equal(expected, c.ListOutboundServicesForIdentity(id1))
+	assert.Equal(1, mc.calls["ListOutboundServicesForIdentity/sa1.ns1"])
+
+	// Results are cached per identity
+	assert.Equal([]service.MeshService{{Namespace: "ns2", Name: "svc"}}, c.ListOutboundServicesForIdentity(id2))
+	assert.Equal(1, mc.calls["ListOutboundServicesForIdentity/sa2.ns2"])
+
+	// A new generation invalidates the cached results
+	generation++
+	assert.Equal(expected, c.ListOutboundServicesForIdentity(id1))
+	assert.Equal(2, mc.calls["ListOutboundServicesForIdentity/sa1.ns1"])
+	assert.Len(c.entries, 1)
+
+	// Errors are not cached
+	mc.err = errors.New("error")
+	_, err := c.GetEgressClusterConfigs(id1)
+	assert.Error(err)
+	mc.err = nil
+	_, err = c.GetEgressClusterConfigs(id1)
+	assert.NoError(err)
+	_, err = c.GetEgressClusterConfigs(id1)
+	assert.NoError(err)
+	assert.Equal(2, mc.calls["GetEgressClusterConfigs/sa1.ns1"])
+}
@@ -198,10 +198,10 @@ func (mc *MeshCatalog) getRoutingRulesFromTrafficTarget(trafficTarget access.Tra
 	issuers := mc.certManager.GetIssuersInfo()
 	allowedDownstreamPrincipals := mapset.NewSet()
 	for _, source := range trafficTarget.Spec.Sources {
-		allowedDownstreamPrincipals.Add(trafficTargetIdentityToSvcAccount(source).AsPrincipal(issuers.Signing.TrustDomain, issuers.Signing.SpiffeEnabled))
+		allowedDownstreamPrincipals.Add(trafficTargetIdentityToServiceIdentity(source).AsPrincipal(issuers.Signing.TrustDomain, issuers.Signing.SpiffeEnabled))
 
 		if issuers.AreDifferent() {
-			allowedDownstreamPrincipals.Add(trafficTargetIdentityToSvcAccount(source).AsPrincipal(issuers.Validating.TrustDomain, issuers.Validating.SpiffeEnabled))
+			allowedDownstreamPrincipals.Add(trafficTargetIdentityToServiceIdentity(source).AsPrincipal(issuers.Validating.TrustDomain, issuers.Validating.SpiffeEnabled))
 		}
 	}
 
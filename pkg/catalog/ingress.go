@@ -16,10 +16,21 @@ import (
 )
 
 const (
-	// singeIPPrefixLen is the IP prefix length for a single IP address
-	singeIPPrefixLen = "/32"
+	// singleIPv4PrefixLen is the IP prefix length for a single IPv4 address
+	singleIPv4PrefixLen = "/32"
+
+	// singleIPv6PrefixLen is the IP prefix length for a single IPv6 address
+	singleIPv6PrefixLen = "/128"
 )
 
+// getSingleIPCIDR returns the CIDR of the given IP address alone
+func getSingleIPCIDR(ip net.IP) string {
+	if ip.To4() != nil {
+		return ip.String() + singleIPv4PrefixLen
+	}
+	return ip.String() + singleIPv6PrefixLen
+}
+
 // GetIngressTrafficMatches returns all the ingress traffic matches for the given MeshService list
 func (mc *MeshCatalog) GetIngressTrafficMatches(meshServices []service.MeshService) [][]*trafficpolicy.IngressTrafficMatch {
 	var allTrafficMatches [][]*trafficpolicy.IngressTrafficMatch
@@ -53,6 +64,19 @@ func (mc *MeshCatalog) GetIngressHTTPRoutePolicies(meshServices []service.MeshSe
 	return allHTTPRoutePolicies
 }
 
+// ReconcileIngressBackendStatuses writes the status of every IngressBackend. The statuses are otherwise only written
+// while generating the configuration of the backends' proxies, which the replica writing statuses may not serve.
+func (mc *MeshCatalog) ReconcileIngressBackendStatuses() {
+	for _, ingressBackend := range mc.ListIngressBackendPolicies() {
+		for _, backend := range ingressBackend.Spec.Backends {
+			svc := service.MeshService{Name: backend.Name, Namespace: ingressBackend.Namespace, TargetPort: uint16(backend.Port.Number)}
+			if _, err := mc.GetIngressTrafficMatchesForSvc(svc); err != nil {
+				log.Debug().Err(err).Msgf("Error reconciling the status of IngressBackend %s/%s", ingressBackend.Namespace, ingressBackend.Name)
+			}
+		}
+	}
+}
+
 // GetIngressTrafficMatchesForSvc returns the ingress traffic matches for ingress backend for the given MeshService
 func (mc *MeshCatalog) GetIngressTrafficMatchesForSvc(svc service.MeshService) ([]*trafficpolicy.IngressTrafficMatch, error) {
 	ingressBackendPolicy := mc.GetIngressBackendPolicyForService(svc)
@@ -102,7 +126,7 @@ func (mc *MeshCatalog) GetIngressTrafficMatchesForSvc(svc service.MeshService) (
 				}
 
 				for _, ep := range endpoints {
-					sourceCIDR := ep.IP.String() + singeIPPrefixLen
+					sourceCIDR := getSingleIPCIDR(ep.IP)
 					if sourceIPSet.Add(sourceCIDR) {
 						sourceIPRanges = append(sourceIPRanges, sourceCIDR)
 					}
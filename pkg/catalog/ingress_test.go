@@ -433,3 +433,50 @@ func TestGetIngressTrafficPolicy(t *testing.T) {
 		})
 	}
 }
+
+func TestGetSingleIPCIDR(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Equal("10.0.0.10/32", getSingleIPCIDR(net.ParseIP("10.0.0.10")))
+	assert.Equal("fd00::a/128", getSingleIPCIDR(net.ParseIP("fd00::a")))
+}
+
+func TestReconcileIngressBackendStatuses(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockProvider := compute.NewMockInterface(mockCtrl)
+	meshCatalog := &MeshCatalog{
+		Interface: mockProvider,
+	}
+
+	ingressBackend := &policyV1alpha1.IngressBackend{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ingress-backend-1",
+			Namespace: "testns",
+		},
+		Spec: policyV1alpha1.IngressBackendSpec{
+			Backends: []policyV1alpha1.BackendSpec{
+				{Name: "foo", Port: policyV1alpha1.PortSpec{Number: 80, Protocol: "http"}},
+				{Name: "bar", Port: policyV1alpha1.PortSpec{Number: 90, Protocol: "http"}},
+			},
+			Sources: []policyV1alpha1.IngressSourceSpec{
+				{Kind: policyV1alpha1.KindIPRange, Name: "10.0.0.0/10"},
+			},
+		},
+	}
+
+	mockProvider.EXPECT().ListIngressBackendPolicies().Return([]*policyV1alpha1.IngressBackend{ingressBackend})
+	mockProvider.EXPECT().GetIngressBackendPolicyForService(service.MeshService{Name: "foo", Namespace: "testns", TargetPort: 80}).Return(ingressBackend)
+	mockProvider.EXPECT().GetIngressBackendPolicyForService(service.MeshService{Name: "bar", Namespace: "testns", TargetPort: 90}).Return(ingressBackend)
+
+	// The status is written for every backend, whether or not its proxies are served by this replica
+	var statuses []string
+	mockProvider.EXPECT().UpdateIngressBackendStatus(gomock.Any()).DoAndReturn(
+		func(ib *policyV1alpha1.IngressBackend) (*policyV1alpha1.IngressBackend, error) {
+			statuses = append(statuses, ib.Status.CurrentStatus)
+			return ib, nil
+		}).Times(2)
+
+	meshCatalog.ReconcileIngressBackendStatuses()
+	assert.Equal([]string{"committed", "committed"}, statuses)
+}
@@ -21,7 +21,7 @@ func (mc *MeshCatalog) GetOutboundMeshTrafficMatches(downstreamIdentity identity
 		var destinationIPRanges []string
 		destinationIPSet := mapset.NewSet()
 		for _, endp := range mc.GetResolvableEndpointsForService(meshSvc) {
-			ipCIDR := endp.IP.String() + "/32"
+			ipCIDR := getSingleIPCIDR(endp.IP)
 			if added := destinationIPSet.Add(ipCIDR); added {
 				destinationIPRanges = append(destinationIPRanges, ipCIDR)
 			}
@@ -99,6 +99,11 @@ func (mc *MeshCatalog) getAllowedDirectionalServiceAccounts(svcIdentity identity
 					continue
 				}
 
+				if trafficTargetIdentityToServiceIdentity(source).IsSpiffeID() {
+					// Sources in federated trust domains are not service accounts within the mesh
+					continue
+				}
+
 				allowed.Add(trafficTargetIdentityToSvcAccount(source))
 			}
 		}
@@ -138,8 +143,12 @@ func trafficTargetIdentityToSvcAccount(identitySubject smiAccess.IdentityBinding
 	}
 }
 
-// trafficTargetIdentityToServiceIdentity returns an identity of the form <namespace>/<service-account>
+// trafficTargetIdentityToServiceIdentity returns an identity of the form <namespace>/<service-account>, or the
+// SPIFFE ID of a workload in a federated trust domain when the subject's name is one, e.g. spiffe://other-domain/ns/sa
 func trafficTargetIdentityToServiceIdentity(identitySubject smiAccess.IdentityBindingSubject) identity.ServiceIdentity {
+	if si := identity.ServiceIdentity(identitySubject.Name); si.IsSpiffeID() {
+		return si
+	}
 	return trafficTargetIdentityToSvcAccount(identitySubject).ToServiceIdentity()
 }
 
@@ -438,6 +438,39 @@ func TestTrafficTargetIdentityToSvcAccount(t *testing.T) {
 	}
 }
 
+func TestTrafficTargetIdentityToServiceIdentity(t *testing.T) {
+	testCases := []struct {
+		name             string
+		identity         smiAccess.IdentityBindingSubject
+		expectedIdentity identity.ServiceIdentity
+	}{
+		{
+			name: "service account in the mesh",
+			identity: smiAccess.IdentityBindingSubject{
+				Kind:      "ServiceAccount",
+				Name:      "sa-1",
+				Namespace: "ns-1",
+			},
+			expectedIdentity: identity.New("sa-1", "ns-1"),
+		},
+		{
+			name: "workload in a federated trust domain",
+			identity: smiAccess.IdentityBindingSubject{
+				Kind: "ServiceAccount",
+				Name: "spiffe://other-domain/ns-1/sa-1",
+			},
+			expectedIdentity: identity.ServiceIdentity("spiffe://other-domain/ns-1/sa-1"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expectedIdentity, trafficTargetIdentityToServiceIdentity(tc.identity))
+		})
+	}
+}
+
 func TestTrafficTargetIdentitiesToSvcAccounts(t *testing.T) {
 	assert := tassert.New(t)
 	input := []smiAccess.IdentityBindingSubject{
@@ -0,0 +1,58 @@
+package catalog
+
+import (
+	"fmt"
+
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+)
+
+// ReconcileUpstreamTrafficSettingStatuses writes the status of every UpstreamTrafficSetting, committed when it applies
+// to a service of the mesh or is matched by an Egress policy, and errored otherwise. Statuses which did not change are
+// not written again.
+func (mc *MeshCatalog) ReconcileUpstreamTrafficSettingStatuses() {
+	for _, upstreamTrafficSetting := range mc.ListUpstreamTrafficSettings() {
+		status := mc.getUpstreamTrafficSettingStatus(upstreamTrafficSetting)
+		if upstreamTrafficSetting.Status == status {
+			continue
+		}
+
+		// Note: The original pointer returned by cache.Store must not be modified for thread safety.
+		upstreamTrafficSettingWithStatus := *upstreamTrafficSetting
+		upstreamTrafficSettingWithStatus.Status = status
+		if _, err := mc.UpdateUpstreamTrafficSettingStatus(&upstreamTrafficSettingWithStatus); err != nil {
+			log.Error().Err(err).Msgf("Error updating status for UpstreamTrafficSetting %s/%s",
+				upstreamTrafficSetting.Namespace, upstreamTrafficSetting.Name)
+		}
+	}
+}
+
+// getUpstreamTrafficSettingStatus returns the status of the given UpstreamTrafficSetting
+func (mc *MeshCatalog) getUpstreamTrafficSettingStatus(upstreamTrafficSetting *policyv1alpha1.UpstreamTrafficSetting) policyv1alpha1.UpstreamTrafficSettingStatus {
+	committed := policyv1alpha1.UpstreamTrafficSettingStatus{
+		CurrentStatus: "committed",
+		Reason:        "successfully committed by the system",
+	}
+
+	for _, meshSvc := range mc.ListServices() {
+		if meshSvc.Namespace == upstreamTrafficSetting.Namespace && meshSvc.FQDN() == upstreamTrafficSetting.Spec.Host {
+			return committed
+		}
+	}
+
+	for _, egressPolicy := range mc.ListEgressPolicies() {
+		if egressPolicy.Namespace != upstreamTrafficSetting.Namespace {
+			continue
+		}
+		for _, match := range egressPolicy.Spec.Matches {
+			if match.APIGroup != nil && *match.APIGroup == policyv1alpha1.SchemeGroupVersion.String() &&
+				match.Kind == upstreamTrafficSettingKind && match.Name == upstreamTrafficSetting.Name {
+				return committed
+			}
+		}
+	}
+
+	return policyv1alpha1.UpstreamTrafficSettingStatus{
+		CurrentStatus: "error",
+		Reason:        fmt.Sprintf("host %s matches no service of the mesh and no Egress policy", upstreamTrafficSetting.Spec.Host),
+	}
+}
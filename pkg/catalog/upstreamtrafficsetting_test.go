@@ -0,0 +1,81 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/compute"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+func TestReconcileUpstreamTrafficSettingStatuses(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockProvider := compute.NewMockInterface(mockCtrl)
+	meshCatalog := &MeshCatalog{
+		Interface: mockProvider,
+	}
+
+	newSetting := func(name, host string, status policyv1alpha1.UpstreamTrafficSettingStatus) *policyv1alpha1.UpstreamTrafficSetting {
+		return &policyv1alpha1.UpstreamTrafficSetting{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "testns",
+			},
+			Spec: policyv1alpha1.UpstreamTrafficSettingSpec{
+				Host: host,
+			},
+			Status: status,
+		}
+	}
+	committed := policyv1alpha1.UpstreamTrafficSettingStatus{
+		CurrentStatus: "committed",
+		Reason:        "successfully committed by the system",
+	}
+	apiGroup := policyv1alpha1.SchemeGroupVersion.String()
+
+	mockProvider.EXPECT().ListUpstreamTrafficSettings().Return([]*policyv1alpha1.UpstreamTrafficSetting{
+		newSetting("service", "foo.testns.svc.cluster.local", policyv1alpha1.UpstreamTrafficSettingStatus{}),
+		newSetting("egress", "httpbin.org", policyv1alpha1.UpstreamTrafficSettingStatus{}),
+		newSetting("unmatched", "bar.testns.svc.cluster.local", policyv1alpha1.UpstreamTrafficSettingStatus{}),
+		newSetting("unchanged", "foo.testns.svc.cluster.local", committed),
+	})
+	mockProvider.EXPECT().ListServices().Return([]service.MeshService{
+		{Name: "foo", Namespace: "testns"},
+		{Name: "bar", Namespace: "otherns"},
+	}).AnyTimes()
+	mockProvider.EXPECT().ListEgressPolicies().Return([]*policyv1alpha1.Egress{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "egress",
+				Namespace: "testns",
+			},
+			Spec: policyv1alpha1.EgressSpec{
+				Matches: []corev1.TypedLocalObjectReference{
+					{APIGroup: &apiGroup, Kind: upstreamTrafficSettingKind, Name: "egress"},
+				},
+			},
+		},
+	}).AnyTimes()
+
+	// The statuses are written whether or not the proxies of the services are served by this replica, and only
+	// when they changed
+	statuses := make(map[string]string)
+	mockProvider.EXPECT().UpdateUpstreamTrafficSettingStatus(gomock.Any()).DoAndReturn(
+		func(setting *policyv1alpha1.UpstreamTrafficSetting) (*policyv1alpha1.UpstreamTrafficSetting, error) {
+			statuses[setting.Name] = setting.Status.CurrentStatus
+			return setting, nil
+		}).Times(3)
+
+	meshCatalog.ReconcileUpstreamTrafficSettingStatuses()
+	assert.Equal(map[string]string{
+		"service":   "committed",
+		"egress":    "committed",
+		"unmatched": "error",
+	}, statuses)
+}
@@ -12,14 +12,49 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/certificate/castorage"
+	"github.com/openservicemesh/osm/pkg/certificate/castorage/kms"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/errcode"
 	"github.com/openservicemesh/osm/pkg/version"
 )
 
+// SecretStorage stores root certificates in Kubernetes secrets. When a KeyWrapper is configured, the private key
+// is envelope encrypted before it is written to the secret, and only the wrapped data encryption key is stored
+// alongside it.
+type SecretStorage struct {
+	kubeClient kubernetes.Interface
+	namespace  string
+	keyWrapper kms.KeyWrapper
+}
+
+var _ castorage.Storage = (*SecretStorage)(nil)
+
+// NewSecretStorage returns a SecretStorage for secrets in the given namespace. A nil keyWrapper stores
+// private keys unencrypted.
+func NewSecretStorage(kubeClient kubernetes.Interface, namespace string, keyWrapper kms.KeyWrapper) *SecretStorage {
+	return &SecretStorage{
+		kubeClient: kubeClient,
+		namespace:  namespace,
+		keyWrapper: keyWrapper,
+	}
+}
+
 // GetCertFromKubernetes is a helper function that loads a certificate from a Kubernetes secret
 func GetCertFromKubernetes(ns string, secretName string, kubeClient kubernetes.Interface) (*certificate.Certificate, error) {
-	certSecret, err := kubeClient.CoreV1().Secrets(ns).Get(context.Background(), secretName, metav1.GetOptions{})
+	return NewSecretStorage(kubeClient, ns, nil).Get(secretName)
+}
+
+// GetCertificateFromSecret is a helper function that ensures creation and synchronization of a certificate
+// using Kubernetes Secrets backend and API atomicity.
+func GetCertificateFromSecret(ns string, secretName string, cert *certificate.Certificate, kubeClient kubernetes.Interface) (*certificate.Certificate, error) {
+	return NewSecretStorage(kubeClient, ns, nil).GetOrCreate(secretName, cert)
+}
+
+// Get loads the certificate stored in the secret with the given name
+func (s *SecretStorage) Get(secretName string) (*certificate.Certificate, error) {
+	ns := s.namespace
+	certSecret, err := s.kubeClient.CoreV1().Secrets(ns).Get(context.Background(), secretName, metav1.GetOptions{})
 	if err != nil {
 		// TODO(#3962): metric might not be scraped before process restart resulting from this error
 		log.Error().Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrFetchingCertSecret)).
@@ -35,11 +70,11 @@ func GetCertFromKubernetes(ns string, secretName string, kubeClient kubernetes.I
 		return nil, certificate.ErrInvalidCertSecret
 	}
 
-	pemKey, ok := certSecret.Data[constants.KubernetesOpaqueSecretRootPrivateKeyKey]
-	if !ok {
+	pemKey, err := s.getPrivateKey(certSecret)
+	if err != nil {
 		// TODO(#3962): metric might not be scraped before process restart resulting from this error
-		log.Error().Err(certificate.ErrInvalidCertSecret).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrObtainingPrivateKeyFromSecret)).
-			Msgf("Opaque k8s secret %s/%s does not have required field %q", ns, secretName, constants.KubernetesOpaqueSecretRootPrivateKeyKey)
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrObtainingPrivateKeyFromSecret)).
+			Msgf("Could not obtain private key from opaque k8s secret %s/%s", ns, secretName)
 		return nil, certificate.ErrInvalidCertSecret
 	}
 
@@ -52,14 +87,59 @@ func GetCertFromKubernetes(ns string, secretName string, kubeClient kubernetes.I
 	return cert, nil
 }
 
-// GetCertificateFromSecret is a helper function that ensures creation and synchronization of a certificate
-// using Kubernetes Secrets backend and API atomicity.
-func GetCertificateFromSecret(ns string, secretName string, cert *certificate.Certificate, kubeClient kubernetes.Interface) (*certificate.Certificate, error) {
+// getPrivateKey returns the PEM encoded private key stored in the secret, decrypting it if required.
+// A plaintext key is accepted when a KeyWrapper is configured so existing secrets keep working; such
+// secrets are encrypted in place by GetOrCreate.
+func (s *SecretStorage) getPrivateKey(secret *corev1.Secret) ([]byte, error) {
+	if sealed, ok := secret.Data[constants.KubernetesOpaqueSecretEncryptedRootPrivateKeyKey]; ok {
+		if s.keyWrapper == nil {
+			log.Error().Msgf("Secret %s/%s holds an encrypted private key but no key management service is configured",
+				secret.Namespace, secret.Name)
+			return nil, certificate.ErrInvalidCertSecret
+		}
+		return kms.Open(context.Background(), s.keyWrapper, sealed)
+	}
+
+	pemKey, ok := secret.Data[constants.KubernetesOpaqueSecretRootPrivateKeyKey]
+	if !ok {
+		log.Error().Msgf("Opaque k8s secret %s/%s does not have required field %q or %q", secret.Namespace, secret.Name,
+			constants.KubernetesOpaqueSecretRootPrivateKeyKey, constants.KubernetesOpaqueSecretEncryptedRootPrivateKeyKey)
+		return nil, certificate.ErrInvalidCertSecret
+	}
+
+	return pemKey, nil
+}
+
+// secretData returns the secret data for the given certificate, encrypting the private key if required
+func (s *SecretStorage) secretData(cert *certificate.Certificate) (map[string][]byte, error) {
+	if s.keyWrapper == nil {
+		return map[string][]byte{
+			constants.KubernetesOpaqueSecretCAKey:             cert.GetCertificateChain(),
+			constants.KubernetesOpaqueSecretRootPrivateKeyKey: cert.GetPrivateKey(),
+		}, nil
+	}
+
+	sealed, err := kms.Seal(context.Background(), s.keyWrapper, cert.GetPrivateKey())
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string][]byte{
+		constants.KubernetesOpaqueSecretCAKey:                      cert.GetCertificateChain(),
+		constants.KubernetesOpaqueSecretEncryptedRootPrivateKeyKey: sealed,
+	}, nil
+}
+
+// GetOrCreate ensures creation and synchronization of a certificate using Kubernetes Secrets backend and API atomicity
+func (s *SecretStorage) GetOrCreate(secretName string, cert *certificate.Certificate) (*certificate.Certificate, error) {
+	ns := s.namespace
+
 	// Attempt to create it in Kubernetes. When multiple agents attempt to create, only one of them will succeed.
 	// All others will get "AlreadyExists" error back.
-	secretData := map[string][]byte{
-		constants.KubernetesOpaqueSecretCAKey:             cert.GetCertificateChain(),
-		constants.KubernetesOpaqueSecretRootPrivateKeyKey: cert.GetPrivateKey(),
+	secretData, err := s.secretData(cert)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error encrypting private key for certificate secret %s/%s", ns, secretName)
+		return nil, err
 	}
 
 	secret := &corev1.Secret{
@@ -74,7 +154,7 @@ func GetCertificateFromSecret(ns string, secretName string, cert *certificate.Ce
 		Data: secretData,
 	}
 
-	if _, err := kubeClient.CoreV1().Secrets(ns).Create(context.TODO(), secret, metav1.CreateOptions{}); err == nil {
+	if _, err := s.kubeClient.CoreV1().Secrets(ns).Create(context.TODO(), secret, metav1.CreateOptions{}); err == nil {
 		log.Info().Msgf("Secret %s/%s created in kubernetes", ns, secretName)
 	} else if apierrors.IsAlreadyExists(err) {
 		log.Info().Msgf("Secret %s/%s already exists in kubernetes, loading.", ns, secretName)
@@ -87,11 +167,43 @@ func GetCertificateFromSecret(ns string, secretName string, cert *certificate.Ce
 
 	// For simplicity, we will load the certificate for all of them, this way the instance which created it
 	// and the ones that didn't share the same code.
-	cert, err := GetCertFromKubernetes(ns, secretName, kubeClient)
+	cert, err = s.Get(secretName)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to fetch certificate from Kubernetes")
 		return nil, err
 	}
 
+	if s.keyWrapper != nil {
+		s.encryptPlaintextKey(secretName, cert)
+	}
+
 	return cert, nil
 }
+
+// encryptPlaintextKey replaces a plaintext private key, stored before a key management service was configured,
+// with its encrypted form. Failures are logged and otherwise ignored since the certificate was loaded
+// successfully, and another replica may have raced to encrypt the same secret.
+func (s *SecretStorage) encryptPlaintextKey(secretName string, cert *certificate.Certificate) {
+	ns := s.namespace
+	secret, err := s.kubeClient.CoreV1().Secrets(ns).Get(context.TODO(), secretName, metav1.GetOptions{})
+	if err != nil {
+		log.Warn().Err(err).Msgf("Error fetching certificate secret %s/%s to encrypt its private key", ns, secretName)
+		return
+	}
+	if _, ok := secret.Data[constants.KubernetesOpaqueSecretRootPrivateKeyKey]; !ok {
+		return
+	}
+
+	secretData, err := s.secretData(cert)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Error encrypting private key of certificate secret %s/%s", ns, secretName)
+		return
+	}
+	secret.Data = secretData
+
+	if _, err := s.kubeClient.CoreV1().Secrets(ns).Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+		log.Warn().Err(err).Msgf("Error updating certificate secret %s/%s with encrypted private key", ns, secretName)
+		return
+	}
+	log.Info().Msgf("Encrypted plaintext private key of certificate secret %s/%s with %s", ns, secretName, s.keyWrapper.Name())
+}
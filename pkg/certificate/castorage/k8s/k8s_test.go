@@ -2,6 +2,7 @@ package k8s
 
 import (
 	"context"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"k8s.io/client-go/kubernetes/fake"
 
 	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/certificate/castorage/kms"
 	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/tests"
@@ -130,3 +132,44 @@ func TestSynchronizeCertificate(t *testing.T) {
 		assert.Equal(certResults[i], certResults[i+1])
 	}
 }
+
+func TestSecretStorageWithKeyEncryption(t *testing.T) {
+	assert := tassert.New(t)
+
+	keyFile := filepath.Join(t.TempDir(), "kek")
+	assert.NoError(kms.GenerateLocalKey(keyFile))
+	keyWrapper, err := kms.NewLocal(keyFile)
+	assert.NoError(err)
+
+	cert, err := tresor.NewCA("common-name", time.Hour, "test-country", "test-locality", "test-org")
+	assert.NoError(err)
+
+	kubeClient := fake.NewSimpleClientset()
+	storage := NewSecretStorage(kubeClient, "test", keyWrapper)
+
+	stored, err := storage.GetOrCreate("encrypted", cert)
+	assert.NoError(err)
+	assert.Equal(cert.GetPrivateKey(), stored.GetPrivateKey())
+
+	secret, err := kubeClient.CoreV1().Secrets("test").Get(context.Background(), "encrypted", metav1.GetOptions{})
+	assert.NoError(err)
+	assert.NotContains(secret.Data, constants.KubernetesOpaqueSecretRootPrivateKeyKey)
+	assert.Contains(secret.Data, constants.KubernetesOpaqueSecretEncryptedRootPrivateKeyKey)
+
+	// The encrypted private key cannot be loaded without the key management service
+	_, err = GetCertFromKubernetes("test", "encrypted", kubeClient)
+	assert.ErrorIs(err, certificate.ErrInvalidCertSecret)
+
+	// A plaintext private key stored before encryption was enabled is loaded and encrypted in place
+	_, err = GetCertificateFromSecret("test", "plaintext", cert, kubeClient)
+	assert.NoError(err)
+
+	stored, err = storage.GetOrCreate("plaintext", cert)
+	assert.NoError(err)
+	assert.Equal(cert.GetPrivateKey(), stored.GetPrivateKey())
+
+	secret, err = kubeClient.CoreV1().Secrets("test").Get(context.Background(), "plaintext", metav1.GetOptions{})
+	assert.NoError(err)
+	assert.NotContains(secret.Data, constants.KubernetesOpaqueSecretRootPrivateKeyKey)
+	assert.Contains(secret.Data, constants.KubernetesOpaqueSecretEncryptedRootPrivateKeyKey)
+}
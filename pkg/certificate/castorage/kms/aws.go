@@ -0,0 +1,93 @@
+package kms
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	awskms "github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+)
+
+const (
+	// AWSKMS is the name of the AWS Key Management Service
+	AWSKMS = "aws-kms"
+
+	awsRegionConfigKey   = "region"
+	awsEndpointConfigKey = "endpoint"
+)
+
+// awsCredentialsConfigKeys are the config keys rejected as they would carry credentials
+var awsCredentialsConfigKeys = []string{"accessKeyID", "secretAccessKey", "sessionToken"}
+
+func init() {
+	RegisterRemote(AWSKMS, func(config map[string]string) (RemoteClient, error) {
+		// The config is stored in the MeshRootCertificate, so it must not carry credentials
+		for _, key := range awsCredentialsConfigKeys {
+			if _, ok := config[key]; ok {
+				return nil, fmt.Errorf("AWS credentials must not be set in the %s config, set the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables from a secret or use an IAM role for the service account instead", AWSKMS)
+			}
+		}
+		return NewAWSClient(config[awsRegionConfigKey], config[awsEndpointConfigKey])
+	})
+}
+
+// AWSClient is a RemoteClient backed by the AWS Key Management Service
+type AWSClient struct {
+	kms kmsiface.KMSAPI
+}
+
+// NewAWSClient returns an AWSClient for the given region, and the given endpoint when not empty. The credentials are
+// resolved by the default AWS credential chain, e.g. from the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment
+// variables or from the IAM role of the service account. When the region is empty, the AWS_REGION environment
+// variable is used.
+func NewAWSClient(region, endpoint string) (*AWSClient, error) {
+	config := aws.Config{}
+	if region != "" {
+		config.Region = aws.String(region)
+	}
+	if endpoint != "" {
+		config.Endpoint = aws.String(endpoint)
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            config,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating AWS session: %w", err)
+	}
+	if aws.StringValue(sess.Config.Region) == "" {
+		return nil, fmt.Errorf("AWS region must be set in the %s config or with the AWS_REGION environment variable", AWSKMS)
+	}
+
+	return &AWSClient{
+		kms: awskms.New(sess),
+	}, nil
+}
+
+// Encrypt encrypts the plaintext with the AWS KMS key identified by keyID, which is a key ID, key ARN, alias name or
+// alias ARN
+func (c *AWSClient) Encrypt(ctx context.Context, keyID string, plaintext []byte) ([]byte, error) {
+	out, err := c.kms.EncryptWithContext(ctx, &awskms.EncryptInput{
+		KeyId:     aws.String(keyID),
+		Plaintext: plaintext,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting with AWS KMS key %s: %w", keyID, err)
+	}
+	return out.CiphertextBlob, nil
+}
+
+// Decrypt decrypts a ciphertext previously returned by Encrypt for the AWS KMS key identified by keyID
+func (c *AWSClient) Decrypt(ctx context.Context, keyID string, ciphertext []byte) ([]byte, error) {
+	out, err := c.kms.DecryptWithContext(ctx, &awskms.DecryptInput{
+		KeyId:          aws.String(keyID),
+		CiphertextBlob: ciphertext,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting with AWS KMS key %s: %w", keyID, err)
+	}
+	return out.Plaintext, nil
+}
@@ -0,0 +1,103 @@
+package kms
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// dekSize is the size in bytes of the AES-256 data encryption key generated for every sealed value
+const dekSize = 32
+
+// Envelope is the serialized form of an encrypted value. The value is encrypted with a random data encryption
+// key (DEK), and the DEK itself is stored wrapped by the key management service.
+type Envelope struct {
+	// KMS is the name of the key management service that wrapped the DEK
+	KMS string `json:"kms"`
+
+	// KeyID identifies the key encryption key that wrapped the DEK
+	KeyID string `json:"keyID"`
+
+	// WrappedDEK is the data encryption key, encrypted by the key management service
+	WrappedDEK []byte `json:"wrappedDEK"`
+
+	// Nonce is the AES-GCM nonce used to encrypt the value
+	Nonce []byte `json:"nonce"`
+
+	// Ciphertext is the AES-GCM encrypted value
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Seal encrypts the plaintext with a freshly generated data encryption key, wraps that key with the given
+// KeyWrapper and returns the JSON encoded Envelope.
+func Seal(ctx context.Context, wrapper KeyWrapper, plaintext []byte) ([]byte, error) {
+	dek := make([]byte, dekSize)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return nil, fmt.Errorf("error generating data encryption key: %w", err)
+	}
+
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("error generating nonce: %w", err)
+	}
+
+	wrappedDEK, err := wrapper.WrapKey(ctx, dek)
+	if err != nil {
+		return nil, fmt.Errorf("error wrapping data encryption key with %s: %w", wrapper.Name(), err)
+	}
+
+	return json.Marshal(Envelope{
+		KMS:        wrapper.Name(),
+		KeyID:      wrapper.KeyID(),
+		WrappedDEK: wrappedDEK,
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, plaintext, nil),
+	})
+}
+
+// Open decodes a JSON encoded Envelope, unwraps its data encryption key with the given KeyWrapper and returns the
+// decrypted value.
+func Open(ctx context.Context, wrapper KeyWrapper, sealed []byte) ([]byte, error) {
+	var env Envelope
+	if err := json.Unmarshal(sealed, &env); err != nil {
+		return nil, fmt.Errorf("error decoding envelope: %w", err)
+	}
+
+	if env.KMS != wrapper.Name() {
+		return nil, fmt.Errorf("%w: sealed by %q, opening with %q", ErrKMSMismatch, env.KMS, wrapper.Name())
+	}
+
+	dek, err := wrapper.UnwrapKey(ctx, env.WrappedDEK)
+	if err != nil {
+		return nil, fmt.Errorf("error unwrapping data encryption key with %s key %s: %w", env.KMS, env.KeyID, err)
+	}
+
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, env.Nonce, env.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting envelope: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,86 @@
+// Package kms implements envelope encryption of root certificate private keys, where the data encryption key
+// is wrapped by a pluggable key management service (KMS).
+package kms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var log = logger.New("kms")
+
+// KeyWrapper wraps and unwraps data encryption keys using a key encryption key held by a key management service.
+// The key encryption key never leaves the key management service.
+type KeyWrapper interface {
+	// Name returns the name of the key management service, e.g. "local" or "vault-transit"
+	Name() string
+
+	// KeyID returns the identifier of the key encryption key within the key management service
+	KeyID() string
+
+	// WrapKey encrypts the given data encryption key
+	WrapKey(ctx context.Context, dek []byte) ([]byte, error)
+
+	// UnwrapKey decrypts a data encryption key previously returned by WrapKey
+	UnwrapKey(ctx context.Context, wrappedDEK []byte) ([]byte, error)
+}
+
+// Factory creates a KeyWrapper for the given key encryption key. The config map holds parameters
+// specific to the key management service.
+type Factory func(keyID string, config map[string]string) (KeyWrapper, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// ErrUnknownKMS is returned when no factory is registered for the requested key management service
+var ErrUnknownKMS = errors.New("unknown key management service")
+
+// ErrKMSMismatch is returned when an encrypted key was sealed by a different key management service than the one
+// used to open it
+var ErrKMSMismatch = errors.New("key was encrypted by a different key management service")
+
+// Register makes a key management service available by the given name. Registering the same name twice replaces
+// the previous factory, which allows custom builds to swap in their own implementation.
+func Register(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	factories[name] = factory
+}
+
+// Registered returns the sorted names of all registered key management services
+func Registered() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New returns a KeyWrapper for the named key management service
+func New(name string, keyID string, config map[string]string) (KeyWrapper, error) {
+	factoriesMu.RLock()
+	factory, ok := factories[name]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w %q, must be one of %v", ErrUnknownKMS, name, Registered())
+	}
+
+	wrapper, err := factory(keyID, config)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing key management service %q: %w", name, err)
+	}
+	log.Info().Msgf("Using key management service %s with key %s", name, keyID)
+
+	return wrapper, nil
+}
@@ -0,0 +1,108 @@
+package kms
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LocalKMS is the name of the file backed key management service
+const LocalKMS = "local"
+
+// ErrInvalidKeyFile is returned when the local key encryption key file does not hold a base64 encoded AES-256 key
+var ErrInvalidKeyFile = errors.New("invalid key encryption key file")
+
+func init() {
+	// The key file is never generated, as every component using the key management service must read the same key,
+	// e.g. from a mounted secret
+	Register(LocalKMS, func(keyID string, _ map[string]string) (KeyWrapper, error) {
+		return NewLocal(keyID)
+	})
+}
+
+// LocalKeyWrapper is a KeyWrapper backed by an AES-256 key encryption key stored in a local file, such as a
+// mounted Kubernetes secret or a token exported from a software HSM. It is intended for testing and for
+// environments without access to a cloud key management service.
+type LocalKeyWrapper struct {
+	path string
+	kek  []byte
+}
+
+// NewLocal returns a LocalKeyWrapper using the base64 encoded key encryption key stored at the given path
+func NewLocal(path string) (*LocalKeyWrapper, error) {
+	encoded, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("error reading key encryption key file %s: %w", path, err)
+	}
+
+	kek, err := base64.StdEncoding.DecodeString(string(encoded))
+	if err != nil || len(kek) != dekSize {
+		return nil, fmt.Errorf("%w %s: expected a base64 encoded %d byte key", ErrInvalidKeyFile, path, dekSize)
+	}
+
+	return &LocalKeyWrapper{
+		path: path,
+		kek:  kek,
+	}, nil
+}
+
+// GenerateLocalKey writes a new random base64 encoded key encryption key to the given path
+func GenerateLocalKey(path string) error {
+	kek := make([]byte, dekSize)
+	if _, err := io.ReadFull(rand.Reader, kek); err != nil {
+		return fmt.Errorf("error generating key encryption key: %w", err)
+	}
+
+	if err := os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(kek)), 0600); err != nil {
+		return fmt.Errorf("error writing key encryption key file %s: %w", path, err)
+	}
+	log.Info().Msgf("Generated key encryption key file %s", path)
+
+	return nil
+}
+
+// Name returns the name of the key management service
+func (w *LocalKeyWrapper) Name() string {
+	return LocalKMS
+}
+
+// KeyID returns the path of the key encryption key file
+func (w *LocalKeyWrapper) KeyID() string {
+	return w.path
+}
+
+// WrapKey encrypts the data encryption key with the local key encryption key
+func (w *LocalKeyWrapper) WrapKey(_ context.Context, dek []byte) ([]byte, error) {
+	aead, err := newAEAD(w.kek)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("error generating nonce: %w", err)
+	}
+
+	// The nonce is prepended to the ciphertext so UnwrapKey can recover it.
+	return aead.Seal(nonce, nonce, dek, nil), nil
+}
+
+// UnwrapKey decrypts a data encryption key previously wrapped by WrapKey
+func (w *LocalKeyWrapper) UnwrapKey(_ context.Context, wrappedDEK []byte) ([]byte, error) {
+	aead, err := newAEAD(w.kek)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(wrappedDEK) < aead.NonceSize() {
+		return nil, errors.New("wrapped data encryption key is too short")
+	}
+
+	nonce, ciphertext := wrappedDEK[:aead.NonceSize()], wrappedDEK[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
@@ -0,0 +1,75 @@
+package kms
+
+import (
+	"context"
+	"fmt"
+)
+
+// RemoteClient is the minimal encrypt/decrypt API exposed by remote key management services, such as AWSClient. Other
+// key management services are added by providing a RemoteClient backed by the corresponding SDK and registering it
+// under its own name with RegisterRemote.
+type RemoteClient interface {
+	// Encrypt encrypts the plaintext with the key identified by keyID
+	Encrypt(ctx context.Context, keyID string, plaintext []byte) ([]byte, error)
+
+	// Decrypt decrypts a ciphertext previously returned by Encrypt for the key identified by keyID
+	Decrypt(ctx context.Context, keyID string, ciphertext []byte) ([]byte, error)
+}
+
+// RemoteClientFactory creates a RemoteClient from key management service specific parameters, such as a region
+// or a credentials file.
+type RemoteClientFactory func(config map[string]string) (RemoteClient, error)
+
+// RegisterRemote registers a remote key management service adapter by name
+func RegisterRemote(name string, newClient RemoteClientFactory) {
+	Register(name, func(keyID string, config map[string]string) (KeyWrapper, error) {
+		client, err := newClient(config)
+		if err != nil {
+			return nil, err
+		}
+		return NewRemote(name, keyID, client)
+	})
+}
+
+// RemoteKeyWrapper is a KeyWrapper that delegates wrapping to a remote key management service
+type RemoteKeyWrapper struct {
+	name   string
+	keyID  string
+	client RemoteClient
+}
+
+// NewRemote returns a RemoteKeyWrapper using the given client
+func NewRemote(name string, keyID string, client RemoteClient) (*RemoteKeyWrapper, error) {
+	if keyID == "" {
+		return nil, fmt.Errorf("key ID for key management service %s must not be empty", name)
+	}
+	if client == nil {
+		return nil, fmt.Errorf("client for key management service %s must not be nil", name)
+	}
+
+	return &RemoteKeyWrapper{
+		name:   name,
+		keyID:  keyID,
+		client: client,
+	}, nil
+}
+
+// Name returns the name of the key management service
+func (w *RemoteKeyWrapper) Name() string {
+	return w.name
+}
+
+// KeyID returns the identifier of the key in the remote key management service
+func (w *RemoteKeyWrapper) KeyID() string {
+	return w.keyID
+}
+
+// WrapKey encrypts the data encryption key with the remote key management service
+func (w *RemoteKeyWrapper) WrapKey(ctx context.Context, dek []byte) ([]byte, error) {
+	return w.client.Encrypt(ctx, w.keyID, dek)
+}
+
+// UnwrapKey decrypts a data encryption key with the remote key management service
+func (w *RemoteKeyWrapper) UnwrapKey(ctx context.Context, wrappedDEK []byte) ([]byte, error) {
+	return w.client.Decrypt(ctx, w.keyID, wrappedDEK)
+}
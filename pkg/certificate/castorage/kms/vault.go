@@ -0,0 +1,130 @@
+package kms
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/hashicorp/vault/api"
+)
+
+const (
+	// VaultTransitKMS is the name of the Hashicorp Vault transit secrets engine key management service
+	VaultTransitKMS = "vault-transit"
+
+	vaultAddressConfigKey = "address"
+	vaultTokenConfigKey   = "token"
+	vaultMountConfigKey   = "mount"
+
+	defaultVaultTransitMount = "transit"
+
+	// See: https://developer.hashicorp.com/vault/api-docs/secret/transit
+	vaultPlaintextField  = "plaintext"
+	vaultCiphertextField = "ciphertext"
+)
+
+func init() {
+	Register(VaultTransitKMS, func(keyID string, config map[string]string) (KeyWrapper, error) {
+		// The config is stored in the MeshRootCertificate, so it must not carry credentials
+		if _, ok := config[vaultTokenConfigKey]; ok {
+			return nil, fmt.Errorf("the Vault token must not be set in the %s config, set the VAULT_TOKEN environment variable from a secret instead", VaultTransitKMS)
+		}
+		return NewVaultTransit(config[vaultAddressConfigKey], config[vaultMountConfigKey], keyID)
+	})
+}
+
+// vaultLogical is the subset of the Vault logical API used by the transit key wrapper
+type vaultLogical interface {
+	Write(path string, data map[string]interface{}) (*api.Secret, error)
+}
+
+// VaultTransitKeyWrapper is a KeyWrapper backed by a named key in Vault's transit secrets engine
+type VaultTransitKeyWrapper struct {
+	logical vaultLogical
+	mount   string
+	keyName string
+}
+
+// NewVaultTransit returns a VaultTransitKeyWrapper for the transit key with the given name. The token is read from the
+// VAULT_TOKEN environment variable. When the address is empty, the VAULT_ADDR environment variable is used.
+func NewVaultTransit(address, mount, keyName string) (*VaultTransitKeyWrapper, error) {
+	if keyName == "" {
+		return nil, fmt.Errorf("vault transit key name must not be empty")
+	}
+	if mount == "" {
+		mount = defaultVaultTransitMount
+	}
+
+	config := api.DefaultConfig()
+	if address != "" {
+		config.Address = address
+	}
+
+	client, err := api.NewClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Vault client for %s: %w", config.Address, err)
+	}
+
+	return &VaultTransitKeyWrapper{
+		logical: client.Logical(),
+		mount:   mount,
+		keyName: keyName,
+	}, nil
+}
+
+// Name returns the name of the key management service
+func (w *VaultTransitKeyWrapper) Name() string {
+	return VaultTransitKMS
+}
+
+// KeyID returns the name of the transit key
+func (w *VaultTransitKeyWrapper) KeyID() string {
+	return w.keyName
+}
+
+// WrapKey encrypts the data encryption key with the transit key
+func (w *VaultTransitKeyWrapper) WrapKey(_ context.Context, dek []byte) ([]byte, error) {
+	secret, err := w.logical.Write(fmt.Sprintf("%s/encrypt/%s", w.mount, w.keyName), map[string]interface{}{
+		vaultPlaintextField: base64.StdEncoding.EncodeToString(dek),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := getSecretString(secret, vaultCiphertextField)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(ciphertext), nil
+}
+
+// UnwrapKey decrypts a data encryption key previously wrapped by WrapKey
+func (w *VaultTransitKeyWrapper) UnwrapKey(_ context.Context, wrappedDEK []byte) ([]byte, error) {
+	secret, err := w.logical.Write(fmt.Sprintf("%s/decrypt/%s", w.mount, w.keyName), map[string]interface{}{
+		vaultCiphertextField: string(wrappedDEK),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := getSecretString(secret, vaultPlaintextField)
+	if err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.DecodeString(plaintext)
+}
+
+func getSecretString(secret *api.Secret, field string) (string, error) {
+	if secret == nil || secret.Data == nil {
+		return "", fmt.Errorf("empty response from Vault transit secrets engine")
+	}
+
+	value, ok := secret.Data[field].(string)
+	if !ok {
+		return "", fmt.Errorf("field %q missing from Vault transit response", field)
+	}
+
+	return value, nil
+}
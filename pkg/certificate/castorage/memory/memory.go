@@ -0,0 +1,38 @@
+// Package memory implements a storage holding root certificates in memory, for control planes running without a
+// Kubernetes cluster. The stored certificates do not outlive the process.
+package memory
+
+import (
+	"sync"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/certificate/castorage"
+)
+
+// Storage stores root certificates in memory
+type Storage struct {
+	mu    sync.Mutex
+	certs map[string]*certificate.Certificate
+}
+
+var _ castorage.Storage = (*Storage)(nil)
+
+// NewStorage returns an empty Storage
+func NewStorage() *Storage {
+	return &Storage{
+		certs: make(map[string]*certificate.Certificate),
+	}
+}
+
+// GetOrCreate stores the given certificate under the given name unless a certificate is already stored under that
+// name, and returns the stored certificate
+func (s *Storage) GetOrCreate(name string, cert *certificate.Certificate) (*certificate.Certificate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stored, ok := s.certs[name]; ok {
+		return stored, nil
+	}
+	s.certs[name] = cert
+	return cert, nil
+}
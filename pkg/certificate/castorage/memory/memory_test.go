@@ -0,0 +1,33 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
+)
+
+func TestGetOrCreate(t *testing.T) {
+	assert := tassert.New(t)
+
+	first, err := tresor.NewCA("first", time.Hour, "US", "CA", "Open Service Mesh")
+	assert.NoError(err)
+	second, err := tresor.NewCA("second", time.Hour, "US", "CA", "Open Service Mesh")
+	assert.NoError(err)
+
+	s := NewStorage()
+	stored, err := s.GetOrCreate("osm-ca-bundle", first)
+	assert.NoError(err)
+	assert.Equal(first, stored)
+
+	// The certificate stored first is returned to later callers
+	stored, err = s.GetOrCreate("osm-ca-bundle", second)
+	assert.NoError(err)
+	assert.Equal(first, stored)
+
+	stored, err = s.GetOrCreate("other", second)
+	assert.NoError(err)
+	assert.Equal(second, stored)
+}
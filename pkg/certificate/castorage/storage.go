@@ -0,0 +1,14 @@
+// Package castorage defines how certificate providers persist their root certificate and private key.
+package castorage
+
+import (
+	"github.com/openservicemesh/osm/pkg/certificate"
+)
+
+// Storage persists root certificates along with their private keys
+type Storage interface {
+	// GetOrCreate stores the given certificate under the given name unless a certificate is already stored
+	// under that name, and returns the stored certificate. When multiple callers race to create the same
+	// certificate, all of them must receive the same stored certificate.
+	GetOrCreate(name string, cert *certificate.Certificate) (*certificate.Certificate, error)
+}
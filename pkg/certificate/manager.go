@@ -9,6 +9,7 @@ import (
 
 	"github.com/cskr/pubsub"
 
+	"github.com/openservicemesh/osm/pkg/certificate/pem"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/errcode"
 	"github.com/openservicemesh/osm/pkg/logger"
@@ -148,6 +149,21 @@ func (m *Manager) GetIssuersInfo() IssuerInfo {
 	}
 }
 
+// GetTrustBundle returns the trust domain of the signing issuer along with the root certificates of the signing
+// and validating issuers. Together they form the trust bundle that foreign trust domains use to verify the mesh's
+// workloads, which remains valid across a root certificate rotation.
+func (m *Manager) GetTrustBundle() (string, pem.RootCertificate) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bundle := make(pem.RootCertificate, 0, len(m.signingIssuer.CertificateAuthority)+len(m.validatingIssuer.CertificateAuthority))
+	bundle = append(bundle, m.signingIssuer.CertificateAuthority...)
+	if m.validatingIssuer.ID != m.signingIssuer.ID {
+		bundle = append(bundle, m.validatingIssuer.CertificateAuthority...)
+	}
+	return m.signingIssuer.TrustDomain, bundle
+}
+
 // ShouldRotate determines whether a certificate should be rotated.
 func (m *Manager) ShouldRotate(c *Certificate) bool {
 	// The certificate is going to expire at a timestamp T
@@ -279,6 +295,16 @@ func (m *Manager) issueCertificate(options IssueOptions) (*Certificate, error) {
 		}
 	}
 
+	// Another replica may have already issued or rotated the certificate
+	if shared := m.getFromSharedCache(options); shared != nil && (cert == nil || shared.SerialNumber != cert.SerialNumber) && !m.ShouldRotate(shared) {
+		m.cache.Store(shared.cacheKey, shared)
+		if rotate {
+			m.pubsub.Pub(shared, cert.cacheKey)
+			log.Debug().Msgf("Rotated certificate (old SerialNumber=%s) with shared SerialNumber=%s", cert.SerialNumber, shared.SerialNumber)
+		}
+		return shared, nil
+	}
+
 	m.mu.Lock()
 	validatingIssuer := m.validatingIssuer
 	signingIssuer := m.signingIssuer
@@ -307,6 +333,7 @@ func (m *Manager) issueCertificate(options IssueOptions) (*Certificate, error) {
 	newCert.cacheKey = options.cacheKey()
 
 	m.cache.Store(newCert.cacheKey, newCert)
+	m.putInSharedCache(options, newCert)
 
 	log.Trace().Msgf("It took %s to issue certificate with SerialNumber=%s", time.Since(start), newCert.GetSerialNumber())
 
@@ -469,3 +469,40 @@ func TestManager_GetIssuerInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestManager_GetTrustBundle(t *testing.T) {
+	tests := []struct {
+		name                string
+		signingIssuer       *issuer
+		validatingIssuer    *issuer
+		expectedTrustDomain string
+		expectedBundle      pem.RootCertificate
+	}{
+		{
+			name:                "single issuer",
+			signingIssuer:       &issuer{ID: "id1", TrustDomain: "cluster.local", CertificateAuthority: pem.RootCertificate("id1")},
+			validatingIssuer:    &issuer{ID: "id1", TrustDomain: "cluster.local", CertificateAuthority: pem.RootCertificate("id1")},
+			expectedTrustDomain: "cluster.local",
+			expectedBundle:      pem.RootCertificate("id1"),
+		},
+		{
+			name:                "root certificate rotation in progress",
+			signingIssuer:       &issuer{ID: "id2", TrustDomain: "new.local", CertificateAuthority: pem.RootCertificate("id2")},
+			validatingIssuer:    &issuer{ID: "id1", TrustDomain: "cluster.local", CertificateAuthority: pem.RootCertificate("id1")},
+			expectedTrustDomain: "new.local",
+			expectedBundle:      pem.RootCertificate("id2id1"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			m := &Manager{
+				signingIssuer:    tt.signingIssuer,
+				validatingIssuer: tt.validatingIssuer,
+			}
+			trustDomain, bundle := m.GetTrustBundle()
+			assert.Equal(tt.expectedTrustDomain, trustDomain)
+			assert.Equal(tt.expectedBundle, bundle)
+		})
+	}
+}
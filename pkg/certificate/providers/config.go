@@ -15,7 +15,10 @@ import (
 
 	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
 	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/certificate/castorage"
 	k8storage "github.com/openservicemesh/osm/pkg/certificate/castorage/k8s"
+	"github.com/openservicemesh/osm/pkg/certificate/castorage/kms"
+	"github.com/openservicemesh/osm/pkg/certificate/castorage/memory"
 	"github.com/openservicemesh/osm/pkg/certificate/pem"
 	"github.com/openservicemesh/osm/pkg/certificate/providers/certmanager"
 	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
@@ -82,6 +85,39 @@ func NewCertificateManager(ctx context.Context, kubeClient kubernetes.Interface,
 	)
 }
 
+// NewInMemoryCertificateManager returns a new certificate manager with a MRC compat client, issuing certificates from
+// a Tresor root certificate held in memory rather than in a Kubernetes secret, for control planes running without a
+// cluster. The root certificate is created anew every time the control plane starts.
+func NewInMemoryCertificateManager(ctx context.Context, providerNamespace string, computeClient compute.Interface,
+	checkInterval time.Duration, trustDomain string) (*certificate.Manager, error) {
+	mrcClient := &MRCCompatClient{
+		MRCProviderGenerator: MRCProviderGenerator{
+			caStorage:       memory.NewStorage(),
+			KeyBitSize:      utils.GetCertKeyBitSize(computeClient.GetMeshConfig()),
+			caExtractorFunc: getCA,
+		},
+		mrc: &v1alpha2.MeshRootCertificate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "legacy-compat",
+				Namespace: providerNamespace,
+			},
+			Spec: v1alpha2.MeshRootCertificateSpec{
+				Provider:    TresorOptions{SecretName: constants.DefaultCABundleSecretName}.AsProviderSpec(),
+				TrustDomain: trustDomain,
+				Role:        v1alpha2.ActiveRole,
+			},
+		},
+	}
+
+	return certificate.NewManager(
+		ctx,
+		mrcClient,
+		func() time.Duration { return utils.GetServiceCertValidityPeriod(computeClient.GetMeshConfig()) },
+		func() time.Duration { return utils.GetIngressGatewayCertValidityPeriod(computeClient.GetMeshConfig()) },
+		checkInterval,
+	)
+}
+
 // NewCertificateManagerFromMRC returns a new certificate manager.
 func NewCertificateManagerFromMRC(ctx context.Context, kubeClient kubernetes.Interface, kubeConfig *rest.Config,
 	providerNamespace string, option Options, computeClient compute.Interface, checkInterval time.Duration) (*certificate.Manager, error) {
@@ -158,7 +194,14 @@ func (c *MRCProviderGenerator) getTresorOSMCertificateManager(mrc *v1alpha2.Mesh
 		return nil, errors.New("root cert does not have a private key")
 	}
 
-	rootCert, err = k8storage.GetCertificateFromSecret(mrc.Namespace, mrc.Spec.Provider.Tresor.CA.SecretRef.Name, rootCert, c.kubeClient)
+	storage := c.caStorage
+	if storage == nil {
+		if storage, err = getTresorCAStorage(mrc, c.kubeClient); err != nil {
+			return nil, err
+		}
+	}
+
+	rootCert, err = storage.GetOrCreate(mrc.Spec.Provider.Tresor.CA.SecretRef.Name, rootCert)
 	if err != nil {
 		return nil, fmt.Errorf("failed to synchronize certificate on Secrets API : %w", err)
 	}
@@ -179,6 +222,22 @@ func (c *MRCProviderGenerator) getTresorOSMCertificateManager(mrc *v1alpha2.Mesh
 	return tresorClient, nil
 }
 
+// getTresorCAStorage returns the storage for Tresor's root certificate. When the MRC specifies key encryption,
+// the private key is envelope encrypted with a data encryption key wrapped by the configured key management service.
+func getTresorCAStorage(mrc *v1alpha2.MeshRootCertificate, kubeClient kubernetes.Interface) (castorage.Storage, error) {
+	keyEncryption := mrc.Spec.Provider.Tresor.CA.KeyEncryption
+	if keyEncryption == nil {
+		return k8storage.NewSecretStorage(kubeClient, mrc.Namespace, nil), nil
+	}
+
+	keyWrapper, err := kms.New(keyEncryption.KMS, keyEncryption.KeyID, keyEncryption.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize key encryption for Tresor root certificate: %w", err)
+	}
+
+	return k8storage.NewSecretStorage(kubeClient, mrc.Namespace, keyWrapper), nil
+}
+
 // getHashiVaultOSMCertificateManager returns a certificate manager instance with Hashi Vault as the certificate provider
 func (c *MRCProviderGenerator) getHashiVaultOSMCertificateManager(mrc *v1alpha2.MeshRootCertificate) (certificate.Issuer, error) {
 	provider := mrc.Spec.Provider.Vault
@@ -3,6 +3,7 @@ package providers
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -16,10 +17,13 @@ import (
 	"k8s.io/client-go/rest"
 
 	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	"github.com/openservicemesh/osm/pkg/certificate/castorage/kms"
 	"github.com/openservicemesh/osm/pkg/compute"
 	"github.com/openservicemesh/osm/pkg/compute/kube"
+	"github.com/openservicemesh/osm/pkg/constants"
 	configClientset "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned"
 	fakeConfigClientset "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned/fake"
+	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/k8s"
 	"github.com/openservicemesh/osm/pkg/messaging"
 
@@ -32,6 +36,9 @@ func TestGetCertificateManager(t *testing.T) {
 	computeMock := compute.NewMockInterface(mockCtrl)
 	computeMock.EXPECT().GetMeshConfig().AnyTimes()
 
+	localKeyFile := filepath.Join(t.TempDir(), "kek")
+	tassert.NoError(t, kms.GenerateLocalKey(localKeyFile))
+
 	type testCase struct {
 		name        string
 		expectError bool
@@ -56,6 +63,26 @@ func TestGetCertificateManager(t *testing.T) {
 			kubeClient:        fake.NewSimpleClientset(),
 			expectError:       true,
 		},
+		{
+			name:              "tresor with encrypted CA key",
+			options:           TresorOptions{SecretName: "osm-ca-bundle", KMS: kms.LocalKMS, KMSKeyID: localKeyFile},
+			providerNamespace: "osm-system",
+			kubeClient:        fake.NewSimpleClientset(),
+		},
+		{
+			name:              "tresor with unknown key management service",
+			options:           TresorOptions{SecretName: "osm-ca-bundle", KMS: "unknown", KMSKeyID: "key"},
+			providerNamespace: "osm-system",
+			kubeClient:        fake.NewSimpleClientset(),
+			expectError:       true,
+		},
+		{
+			name:              "tresor with key management service and no key ID",
+			options:           TresorOptions{SecretName: "osm-ca-bundle", KMS: kms.LocalKMS},
+			providerNamespace: "osm-system",
+			kubeClient:        fake.NewSimpleClientset(),
+			expectError:       true,
+		},
 		{
 			name:              "certManager as the certificate manager",
 			kubeClient:        fake.NewSimpleClientset(),
@@ -143,13 +170,35 @@ func TestGetCertificateManager(t *testing.T) {
 			}
 
 			if opt, ok := tc.options.(TresorOptions); ok && !tc.expectError {
-				_, err := tc.kubeClient.CoreV1().Secrets(tc.providerNamespace).Get(context.TODO(), opt.SecretName, metav1.GetOptions{})
+				secret, err := tc.kubeClient.CoreV1().Secrets(tc.providerNamespace).Get(context.TODO(), opt.SecretName, metav1.GetOptions{})
 				assert.NoError(err)
+
+				_, encrypted := secret.Data[constants.KubernetesOpaqueSecretEncryptedRootPrivateKeyKey]
+				_, plaintext := secret.Data[constants.KubernetesOpaqueSecretRootPrivateKeyKey]
+				assert.Equal(opt.KMS != "", encrypted)
+				assert.Equal(opt.KMS == "", plaintext)
 			}
 		})
 	}
 }
 
+func TestNewInMemoryCertificateManager(t *testing.T) {
+	assert := tassert.New(t)
+
+	mockCtrl := gomock.NewController(t)
+	computeMock := compute.NewMockInterface(mockCtrl)
+	computeMock.EXPECT().GetMeshConfig().AnyTimes()
+
+	manager, err := NewInMemoryCertificateManager(context.Background(), "osm-system", computeMock, 1*time.Hour, "cluster.local")
+	assert.NoError(err)
+
+	// Certificates are issued without a Kubernetes secret holding the root certificate
+	cert, err := manager.IssueCertificate(certificate.ForServiceIdentity(identity.New("bookstore", "bookstore")))
+	assert.NoError(err)
+	assert.NotEmpty(cert.GetCertificateChain())
+	assert.NotEmpty(cert.GetTrustedCAs())
+}
+
 func TestGetCertificateManagerFromMRC(t *testing.T) {
 	type testCase struct {
 		name        string
@@ -14,12 +14,15 @@ func (options TresorOptions) Validate() error {
 	if options.SecretName == "" {
 		return errors.New("tresor CA bundle secret name must be set")
 	}
+	if options.KMS != "" && options.KMSKeyID == "" {
+		return fmt.Errorf("tresor CA key encryption with key management service %s requires a key ID", options.KMS)
+	}
 	return nil
 }
 
 // AsProviderSpec returns the provider spec generated from the tresor options
 func (options TresorOptions) AsProviderSpec() v1alpha2.ProviderSpec {
-	return v1alpha2.ProviderSpec{
+	spec := v1alpha2.ProviderSpec{
 		Tresor: &v1alpha2.TresorProviderSpec{
 			CA: v1alpha2.TresorCASpec{
 				SecretRef: corev1.SecretReference{
@@ -28,6 +31,14 @@ func (options TresorOptions) AsProviderSpec() v1alpha2.ProviderSpec {
 			},
 		},
 	}
+	if options.KMS != "" {
+		spec.Tresor.CA.KeyEncryption = &v1alpha2.KeyEncryptionSpec{
+			KMS:    options.KMS,
+			KeyID:  options.KMSKeyID,
+			Config: options.KMSConfig,
+		}
+	}
+	return spec
 }
 
 // Validate validates the options for Hashi Vault certificate provider
@@ -7,6 +7,7 @@ import (
 
 	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
 	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/certificate/castorage"
 	"github.com/openservicemesh/osm/pkg/certificate/pem"
 	"github.com/openservicemesh/osm/pkg/logger"
 )
@@ -48,8 +49,13 @@ type Options interface {
 
 // TresorOptions is a type that specifies 'Tresor' certificate provider options
 type TresorOptions struct {
-	// No options at the moment
 	SecretName string
+
+	// KMS is the name of the key management service used to encrypt the root certificate's private key.
+	// The private key is stored unencrypted when KMS is empty.
+	KMS       string
+	KMSKeyID  string
+	KMSConfig map[string]string
 }
 
 // VaultOptions is a type that specifies 'Hashicorp Vault' certificate provider options
@@ -84,6 +90,9 @@ type MRCProviderGenerator struct {
 	kubeClient kubernetes.Interface
 	kubeConfig *rest.Config // used to generate a CertificateManager client.
 
+	// caStorage stores Tresor's root certificate instead of the Kubernetes secret referenced by the MRC when set
+	caStorage castorage.Storage
+
 	// TODO(#4711): move these to the compat client once we have added these fields to the MRC.
 	KeyBitSize int
 
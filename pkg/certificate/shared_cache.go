@@ -0,0 +1,117 @@
+package certificate
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/openservicemesh/osm/pkg/certificate/pem"
+)
+
+// SharedCache shares the service certificates issued by the Manager among the replicas of the control plane, so that
+// a proxy reconnecting to another replica is served the certificate already issued for its identity.
+type SharedCache interface {
+	// Get returns the encoded certificate stored under the given key, or nil if no certificate is stored under it.
+	Get(key string) ([]byte, error)
+
+	// Put stores the encoded certificate expiring at the given time under the given key, replacing any certificate
+	// stored under it.
+	Put(key string, data []byte, expiration time.Time) error
+}
+
+// sharedCertificate is the encoding of a Certificate in the SharedCache
+type sharedCertificate struct {
+	CommonName         CommonName          `json:"commonName"`
+	SerialNumber       SerialNumber        `json:"serialNumber"`
+	Expiration         time.Time           `json:"expiration"`
+	CertChain          pem.Certificate     `json:"certChain"`
+	PrivateKey         pem.PrivateKey      `json:"privateKey"`
+	IssuingCA          pem.RootCertificate `json:"issuingCA"`
+	TrustedCAs         pem.RootCertificate `json:"trustedCAs"`
+	SigningIssuerID    string              `json:"signingIssuerID"`
+	ValidatingIssuerID string              `json:"validatingIssuerID"`
+	CertType           certType            `json:"certType"`
+}
+
+// SetSharedCache sets the cache through which the service certificates issued by the Manager are shared with the
+// other replicas of the control plane.
+func (m *Manager) SetSharedCache(sharedCache SharedCache) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sharedCache = sharedCache
+}
+
+func (m *Manager) getSharedCache(options IssueOptions) SharedCache {
+	if options.certType != service {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sharedCache
+}
+
+// getFromSharedCache returns the certificate for the given options from the shared cache if it exists.
+// Note: getFromSharedCache might return an expired or invalid certificate.
+func (m *Manager) getFromSharedCache(options IssueOptions) *Certificate {
+	sharedCache := m.getSharedCache(options)
+	if sharedCache == nil {
+		return nil
+	}
+
+	key := options.cacheKey()
+	data, err := sharedCache.Get(key)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error getting certificate %s from the shared cache", key)
+		return nil
+	}
+	if data == nil {
+		return nil
+	}
+
+	var shared sharedCertificate
+	if err := json.Unmarshal(data, &shared); err != nil {
+		log.Error().Err(err).Msgf("Error decoding certificate %s from the shared cache", key)
+		return nil
+	}
+	log.Trace().Msgf("Certificate %s found in shared cache SerialNumber=%s", key, shared.SerialNumber)
+	return &Certificate{
+		CommonName:         shared.CommonName,
+		cacheKey:           key,
+		SerialNumber:       shared.SerialNumber,
+		Expiration:         shared.Expiration,
+		CertChain:          shared.CertChain,
+		PrivateKey:         shared.PrivateKey,
+		IssuingCA:          shared.IssuingCA,
+		TrustedCAs:         shared.TrustedCAs,
+		signingIssuerID:    shared.SigningIssuerID,
+		validatingIssuerID: shared.ValidatingIssuerID,
+		certType:           shared.CertType,
+	}
+}
+
+// putInSharedCache stores the given certificate in the shared cache
+func (m *Manager) putInSharedCache(options IssueOptions, cert *Certificate) {
+	sharedCache := m.getSharedCache(options)
+	if sharedCache == nil {
+		return
+	}
+
+	data, err := json.Marshal(sharedCertificate{
+		CommonName:         cert.CommonName,
+		SerialNumber:       cert.SerialNumber,
+		Expiration:         cert.Expiration,
+		CertChain:          cert.CertChain,
+		PrivateKey:         cert.PrivateKey,
+		IssuingCA:          cert.IssuingCA,
+		TrustedCAs:         cert.TrustedCAs,
+		SigningIssuerID:    cert.signingIssuerID,
+		ValidatingIssuerID: cert.validatingIssuerID,
+		CertType:           cert.certType,
+	})
+	if err != nil {
+		log.Error().Err(err).Msgf("Error encoding certificate %s for the shared cache", cert.cacheKey)
+		return
+	}
+	if err := sharedCache.Put(cert.cacheKey, data, cert.GetExpiration()); err != nil {
+		log.Error().Err(err).Msgf("Error putting certificate %s in the shared cache", cert.cacheKey)
+	}
+}
@@ -0,0 +1,115 @@
+package certificate
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cskr/pubsub"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/certificate/pem"
+	"github.com/openservicemesh/osm/pkg/identity"
+)
+
+type fakeSharedCache struct {
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func (c *fakeSharedCache) Get(key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.data[key], nil
+}
+
+func (c *fakeSharedCache) Put(key string, data []byte, _ time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data[key] = data
+	return nil
+}
+
+// serialIssuer issues certificates with increasing serial numbers
+type serialIssuer struct {
+	fakeIssuer
+	mu     sync.Mutex
+	issued int
+}
+
+func (i *serialIssuer) IssueCertificate(options IssueOptions) (*Certificate, error) {
+	cert, err := i.fakeIssuer.IssueCertificate(options)
+	if err != nil {
+		return nil, err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.issued++
+	cert.SerialNumber = SerialNumber(strconv.Itoa(i.issued))
+	return cert, nil
+}
+
+func TestSharedCache(t *testing.T) {
+	assert := tassert.New(t)
+
+	sharedCache := &fakeSharedCache{data: make(map[string][]byte)}
+	issuerFor := func(id string, serials *serialIssuer) *issuer {
+		return &issuer{ID: id, Issuer: serials, CertificateAuthority: pem.RootCertificate(id), TrustDomain: "cluster.local"}
+	}
+	newManager := func(serials *serialIssuer) *Manager {
+		m := &Manager{
+			serviceCertValidityDuration: func() time.Duration { return time.Hour },
+			signingIssuer:               issuerFor("id1", serials),
+			validatingIssuer:            issuerFor("id1", serials),
+			pubsub:                      pubsub.New(1),
+		}
+		m.SetSharedCache(sharedCache)
+		return m
+	}
+
+	// Each replica has its own issuer, so that certificates issued by different replicas have the same serial number
+	// only if they are shared
+	serials1 := &serialIssuer{fakeIssuer: fakeIssuer{id: "id1"}}
+	serials2 := &serialIssuer{fakeIssuer: fakeIssuer{id: "id1"}, issued: 100}
+	m1 := newManager(serials1)
+	m2 := newManager(serials2)
+
+	si := identity.New("sa", "ns")
+	cert1, err := m1.IssueCertificate(ForServiceIdentity(si))
+	assert.NoError(err)
+	assert.Equal(SerialNumber("1"), cert1.GetSerialNumber())
+
+	// The second replica serves the certificate issued by the first one
+	cert2, err := m2.IssueCertificate(ForServiceIdentity(si))
+	assert.NoError(err)
+	assert.Equal(cert1.GetSerialNumber(), cert2.GetSerialNumber())
+	assert.Equal(cert1.GetPrivateKey(), cert2.GetPrivateKey())
+	assert.Equal(cert1.signingIssuerID, cert2.signingIssuerID)
+	assert.Equal(cert1.certType, cert2.certType)
+	assert.Equal(0, serials2.issued-100)
+
+	// Certificates other than service certificates are not shared
+	_, err = m1.IssueCertificate(ForCommonNamePrefix("ads"))
+	assert.NoError(err)
+	_, err = m2.IssueCertificate(ForCommonNamePrefix("ads"))
+	assert.NoError(err)
+	assert.Equal(1, serials2.issued-100)
+	assert.Len(sharedCache.data, 1)
+
+	// A certificate rotated by the first replica is adopted by the second one, which notifies its subscribers
+	rotations, unsub := m2.SubscribeRotations(si.String())
+	defer unsub()
+	m1.signingIssuer, m1.validatingIssuer = issuerFor("id2", serials1), issuerFor("id2", serials1)
+	m2.signingIssuer, m2.validatingIssuer = issuerFor("id2", serials2), issuerFor("id2", serials2)
+
+	rotated1, err := m1.IssueCertificate(ForServiceIdentity(si))
+	assert.NoError(err)
+	assert.NotEqual(cert1.GetSerialNumber(), rotated1.GetSerialNumber())
+
+	rotated2, err := m2.IssueCertificate(ForServiceIdentity(si))
+	assert.NoError(err)
+	assert.Equal(rotated1.GetSerialNumber(), rotated2.GetSerialNumber())
+	assert.Equal(1, serials2.issued-100)
+	assert.Equal(rotated2, <-rotations)
+}
@@ -0,0 +1,185 @@
+// Package sharedcache implements a certificate.SharedCache backed by Kubernetes secrets, through which the replicas
+// of osm-controller share the certificates they issue to proxies. The certificates, which hold private keys, are
+// envelope encrypted with a key wrapped by a key management service.
+package sharedcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/certificate/castorage/kms"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var log = logger.New("certificate-shared-cache")
+
+const (
+	// secretNamePrefix is the prefix of the names of the secrets storing shared certificates
+	secretNamePrefix = "osm-shared-cert-"
+
+	// sharedCertificateLabel labels the secrets storing shared certificates
+	sharedCertificateLabel = "openservicemesh.io/shared-certificate"
+
+	// keyAnnotation is the annotation holding the cache key of the certificate stored in a secret
+	keyAnnotation = "openservicemesh.io/shared-certificate-key"
+
+	// expirationAnnotation is the annotation holding the expiration of the certificate stored in a secret
+	expirationAnnotation = "openservicemesh.io/shared-certificate-expiration"
+
+	// dataKey is the key of the sealed certificate in the data of a secret
+	dataKey = "certificate"
+
+	// requestTimeout bounds the calls to the Kubernetes API and to the key management service made while issuing a
+	// certificate
+	requestTimeout = 5 * time.Second
+)
+
+// errCacheNotSynced is returned when the secrets storing shared certificates could not be listed
+var errCacheNotSynced = errors.New("failed to sync the secrets storing shared certificates")
+
+// SecretCache stores shared certificates in Kubernetes secrets, one per cache key. The secrets are read from an
+// informer's cache, so getting a certificate does not call the Kubernetes API.
+type SecretCache struct {
+	kubeClient kubernetes.Interface
+	namespace  string
+	lister     listers.SecretNamespaceLister
+	keyWrapper kms.KeyWrapper
+}
+
+var _ certificate.SharedCache = (*SecretCache)(nil)
+
+// NewSecretCache returns a SecretCache storing the shared certificates in secrets in the given namespace, encrypted
+// with the given KeyWrapper. It watches the secrets until the given context is canceled.
+func NewSecretCache(ctx context.Context, kubeClient kubernetes.Interface, namespace string, keyWrapper kms.KeyWrapper) (*SecretCache, error) {
+	if keyWrapper == nil {
+		return nil, errors.New("a key management service is required to encrypt shared certificates")
+	}
+
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(kubeClient, 0, informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = labels.SelectorFromSet(labels.Set{sharedCertificateLabel: "true"}).String()
+		}))
+	secretInformer := informerFactory.Core().V1().Secrets()
+	lister := secretInformer.Lister().Secrets(namespace)
+	informerFactory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), secretInformer.Informer().HasSynced) {
+		return nil, errCacheNotSynced
+	}
+
+	return &SecretCache{
+		kubeClient: kubeClient,
+		namespace:  namespace,
+		lister:     lister,
+		keyWrapper: keyWrapper,
+	}, nil
+}
+
+// secretName returns the name of the secret storing the certificate with the given key. Keys are hashed as they
+// are not valid secret names.
+func secretName(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return secretNamePrefix + hex.EncodeToString(hash[:16])
+}
+
+// Get returns the encoded certificate stored under the given key, or nil if no certificate is stored under it
+func (c *SecretCache) Get(key string) ([]byte, error) {
+	secret, err := c.lister.Get(secretName(key))
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	return kms.Open(ctx, c.keyWrapper, secret.Data[dataKey])
+}
+
+// Put stores the encoded certificate expiring at the given time under the given key, replacing any certificate
+// stored under it
+func (c *SecretCache) Put(key string, data []byte, expiration time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	sealed, err := kms.Seal(ctx, c.keyWrapper, data)
+	if err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName(key),
+			Namespace: c.namespace,
+			Labels: map[string]string{
+				constants.OSMAppNameLabelKey: constants.OSMAppNameLabelValue,
+				sharedCertificateLabel:       "true",
+			},
+			Annotations: map[string]string{
+				keyAnnotation:        key,
+				expirationAnnotation: expiration.UTC().Format(time.RFC3339),
+			},
+		},
+		Data: map[string][]byte{
+			dataKey: sealed,
+		},
+	}
+
+	secrets := c.kubeClient.CoreV1().Secrets(c.namespace)
+	_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = secrets.Create(ctx, secret, metav1.CreateOptions{})
+	}
+	return err
+}
+
+// Prune deletes the secrets storing expired certificates, which are left behind by the identities no longer in the mesh
+func (c *SecretCache) Prune(ctx context.Context) error {
+	list, err := c.lister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	secrets := c.kubeClient.CoreV1().Secrets(c.namespace)
+	for _, secret := range list {
+		expiration, err := time.Parse(time.RFC3339, secret.Annotations[expirationAnnotation])
+		if err == nil && time.Now().Before(expiration) {
+			continue
+		}
+		log.Debug().Msgf("Deleting shared certificate %s stored in secret %s/%s", secret.Annotations[keyAnnotation], c.namespace, secret.Name)
+		if err := secrets.Delete(ctx, secret.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunPruner prunes the secrets storing expired certificates at the given interval until the given context is canceled
+func (c *SecretCache) RunPruner(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := c.Prune(ctx); err != nil {
+			log.Error().Err(err).Msg("Error pruning shared certificates")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package sharedcache
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/certificate/castorage/kms"
+)
+
+func newTestCache(t *testing.T) (*SecretCache, *fake.Clientset) {
+	t.Helper()
+	keyFile := filepath.Join(t.TempDir(), "kek")
+	tassert.NoError(t, kms.GenerateLocalKey(keyFile))
+	keyWrapper, err := kms.NewLocal(keyFile)
+	tassert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	kubeClient := fake.NewSimpleClientset()
+	c, err := NewSecretCache(ctx, kubeClient, "osm-system", keyWrapper)
+	tassert.NoError(t, err)
+	return c, kubeClient
+}
+
+// getEventually returns the data stored under the given key once it matches the expected data, as the informer
+// catches up with the changes made through the API asynchronously
+func getEventually(t *testing.T, c *SecretCache, key string, expected []byte) {
+	t.Helper()
+	tassert.Eventually(t, func() bool {
+		data, err := c.Get(key)
+		return err == nil && string(data) == string(expected)
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestSecretCache(t *testing.T) {
+	assert := tassert.New(t)
+	c, kubeClient := newTestCache(t)
+
+	data, err := c.Get("sa.ns")
+	assert.NoError(err)
+	assert.Nil(data)
+
+	// Put creates the secret
+	assert.NoError(c.Put("sa.ns", []byte("cert1"), time.Now().Add(time.Hour)))
+	getEventually(t, c, "sa.ns", []byte("cert1"))
+
+	// Put replaces the stored certificate
+	assert.NoError(c.Put("sa.ns", []byte("cert2"), time.Now().Add(time.Hour)))
+	getEventually(t, c, "sa.ns", []byte("cert2"))
+
+	secret, err := kubeClient.CoreV1().Secrets("osm-system").Get(context.Background(), secretName("sa.ns"), metav1.GetOptions{})
+	assert.NoError(err)
+	assert.Equal("sa.ns", secret.Annotations[keyAnnotation])
+	assert.Equal("true", secret.Labels[sharedCertificateLabel])
+	// The certificate is not stored in plaintext
+	assert.NotContains(string(secret.Data[dataKey]), "cert2")
+}
+
+func TestNewSecretCacheRequiresKeyWrapper(t *testing.T) {
+	_, err := NewSecretCache(context.Background(), fake.NewSimpleClientset(), "osm-system", nil)
+	tassert.Error(t, err)
+}
+
+func TestPrune(t *testing.T) {
+	assert := tassert.New(t)
+	c, _ := newTestCache(t)
+
+	assert.NoError(c.Put("valid.ns", []byte("valid"), time.Now().Add(time.Hour)))
+	assert.NoError(c.Put("expired.ns", []byte("expired"), time.Now().Add(-time.Hour)))
+	getEventually(t, c, "valid.ns", []byte("valid"))
+	getEventually(t, c, "expired.ns", []byte("expired"))
+
+	assert.NoError(c.Prune(context.Background()))
+
+	getEventually(t, c, "valid.ns", []byte("valid"))
+	getEventually(t, c, "expired.ns", nil)
+}
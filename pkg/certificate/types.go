@@ -117,6 +117,8 @@ type Manager struct {
 	signingIssuer *issuer
 	// equal to signingIssuer if there is no additional public cert issuer.
 	validatingIssuer *issuer
+	// shares the issued service certificates with other replicas, nil if not shared.
+	sharedCache SharedCache
 
 	group singleflight.Group
 
@@ -6,11 +6,13 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
 
 	"github.com/openservicemesh/osm/pkg/constants"
 )
@@ -91,18 +93,15 @@ func TestRun(t *testing.T) {
 						ClusterIP: "10.96.15.1",
 					},
 				},
-				&corev1.Endpoints{
+				&discoveryv1.EndpointSlice{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "httpbin",
+						Name:      "httpbin-1",
 						Namespace: "httpbin",
+						Labels:    map[string]string{discoveryv1.LabelServiceName: "httpbin"},
 					},
-					Subsets: []corev1.EndpointSubset{
+					Ports: []discoveryv1.EndpointPort{
 						{
-							Ports: []corev1.EndpointPort{
-								{
-									Port: 14001,
-								},
-							},
+							Port: pointer.Int32(14001),
 						},
 					},
 				},
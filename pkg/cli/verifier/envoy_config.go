@@ -12,6 +12,7 @@ import (
 	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	xds_secret "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/utils/pointer"
@@ -258,14 +259,12 @@ func (v *EnvoyConfigVerifier) findOutboundFilterChainForService(svc *corev1.Serv
 	// to a list of pod IP ranges backing the service
 	dstIPRanges := mapset.NewSet()
 	if len(svc.Spec.ClusterIP) == 0 || svc.Spec.ClusterIP == corev1.ClusterIPNone {
-		endpoints, err := v.kubeClient.CoreV1().Endpoints(svc.Namespace).Get(context.Background(), svc.Name, metav1.GetOptions{})
+		slices, err := v.listEndpointSlices(svc.Namespace, svc.Name)
 		if err != nil {
 			return fmt.Errorf("Endpoints not found for service %q", svcNamespacedName)
 		}
-		for _, sub := range endpoints.Subsets {
-			for _, ip := range sub.Addresses {
-				dstIPRanges.Add(ip.IP)
-			}
+		for _, ip := range getReadyAddresses(slices) {
+			dstIPRanges.Add(ip)
 		}
 	} else {
 		dstIPRanges.Add(svc.Spec.ClusterIP)
@@ -462,8 +461,8 @@ func (v *EnvoyConfigVerifier) verifyDestination() Result {
 }
 
 func (v *EnvoyConfigVerifier) getDstMeshServicesForK8sSvc(svc corev1.Service) ([]service.MeshService, error) {
-	endpoints, err := v.kubeClient.CoreV1().Endpoints(svc.Namespace).Get(context.Background(), svc.Name, metav1.GetOptions{})
-	if err != nil || endpoints == nil {
+	slices, err := v.listEndpointSlices(svc.Namespace, svc.Name)
+	if err != nil || len(slices) == 0 {
 		return nil, err
 	}
 
@@ -478,7 +477,7 @@ func (v *EnvoyConfigVerifier) getDstMeshServicesForK8sSvc(svc corev1.Service) ([
 
 		// The endpoints for the kubernetes service carry information that allows
 		// us to retrieve the TargetPort for the MeshService.
-		meshSvc.TargetPort = kube.GetTargetPortFromEndpoints(portSpec.Name, *endpoints)
+		meshSvc.TargetPort = kube.GetTargetPortFromEndpointSlices(portSpec.Name, slices)
 
 		// Even if the service is headless, add it so it can be targeted
 
@@ -490,15 +489,15 @@ func (v *EnvoyConfigVerifier) getDstMeshServicesForK8sSvc(svc corev1.Service) ([
 		// If there's not at least 1 subdomain-ed MeshService added,
 		// add the entire headless service
 		var added bool
-		for _, subset := range endpoints.Subsets {
-			for _, address := range subset.Addresses {
-				if address.Hostname == "" {
+		for _, slice := range slices {
+			for _, ep := range slice.Endpoints {
+				if pointer.StringDeref(ep.Hostname, "") == "" || !pointer.BoolDeref(ep.Conditions.Ready, true) {
 					continue
 				}
 				mSvc := service.MeshService{
 					Namespace:  svc.Namespace,
 					Name:       svc.Name,
-					Subdomain:  address.Hostname,
+					Subdomain:  *ep.Hostname,
 					Port:       meshSvc.Port,
 					TargetPort: meshSvc.TargetPort,
 					Protocol:   meshSvc.Protocol,
@@ -522,7 +521,7 @@ func (v *EnvoyConfigVerifier) findIngressFilterChainForService(svc *corev1.Servi
 	}
 	src := v.configAttr.trafficAttr.SrcService
 	// grab endpoints for ingress service
-	endpoints, err := v.kubeClient.CoreV1().Endpoints(src.Namespace).Get(context.Background(), src.Name, metav1.GetOptions{})
+	slices, err := v.listEndpointSlices(src.Namespace, src.Name)
 	if err != nil {
 		return fmt.Errorf("ingress source service %q not found: %w", src, err)
 	}
@@ -548,23 +547,50 @@ func (v *EnvoyConfigVerifier) findIngressFilterChainForService(svc *corev1.Servi
 		}
 	}
 
-	for _, sub := range endpoints.Subsets {
-		for _, ip := range sub.Addresses {
-			matched, ok := sourceIPs[ip.IP]
-			// Filter chain is missing a service endpoint
-			if !ok {
-				return fmt.Errorf("service endpoint %s was not found in the ingress inbound filter chain", ip.IP)
-			}
-			if ok && !matched {
-				// ingress endpoint found in filter chain match
-				sourceIPs[ip.IP] = true
-			}
+	for _, ip := range getReadyAddresses(slices) {
+		matched, ok := sourceIPs[ip]
+		// Filter chain is missing a service endpoint
+		if !ok {
+			return fmt.Errorf("service endpoint %s was not found in the ingress inbound filter chain", ip)
+		}
+		if ok && !matched {
+			// ingress endpoint found in filter chain match
+			sourceIPs[ip] = true
 		}
 	}
 
 	return nil
 }
 
+// listEndpointSlices returns the EndpointSlices of the given service
+func (v *EnvoyConfigVerifier) listEndpointSlices(namespace, name string) ([]*discoveryv1.EndpointSlice, error) {
+	sliceList, err := v.kubeClient.DiscoveryV1().EndpointSlices(namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", discoveryv1.LabelServiceName, name),
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices := make([]*discoveryv1.EndpointSlice, 0, len(sliceList.Items))
+	for i := range sliceList.Items {
+		slices = append(slices, &sliceList.Items[i])
+	}
+	return slices, nil
+}
+
+// getReadyAddresses returns the addresses of the ready endpoints of the given EndpointSlices
+func getReadyAddresses(slices []*discoveryv1.EndpointSlice) []string {
+	var addresses []string
+	for _, slice := range slices {
+		for _, ep := range slice.Endpoints {
+			if !pointer.BoolDeref(ep.Conditions.Ready, true) {
+				continue
+			}
+			addresses = append(addresses, ep.Addresses...)
+		}
+	}
+	return addresses
+}
+
 func (v *EnvoyConfigVerifier) findInboundFilterChainForService(svc *corev1.Service, filterChains []*xds_listener.FilterChain) error {
 	if svc == nil {
 		return nil
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
@@ -54,9 +55,10 @@ func (v *SidecarVerifier) Run() Result {
 		return result
 	}
 
-	// Check if the Envoy sidecar is present
+	// Check if the Envoy sidecar is present, as a regular container or a native sidecar init container
 	foundEnvoy := false
-	for _, container := range p.Spec.Containers {
+	containers := append(append([]corev1.Container{}, p.Spec.InitContainers...), p.Spec.Containers...)
+	for _, container := range containers {
 		if container.Name == constants.EnvoyContainerName {
 			foundEnvoy = true
 			break
@@ -0,0 +1,308 @@
+package cni
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	// KubeconfigFileName is the name of the kubeconfig written by the installer in the CNI network configuration
+	// directory. It does not have a network configuration file extension, so that container runtimes ignore it.
+	KubeconfigFileName = "osm-cni.kubeconfig"
+
+	// serviceAccountDir is the directory of the service account token and CA of the installer's pod
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+)
+
+// Installer installs the OSM CNI plugin on a node, from the DaemonSet pod mounting the CNI binary and network
+// configuration directories of the node
+type Installer struct {
+	// PluginBinary is the path of the plugin binary to copy to BinDir
+	PluginBinary string
+
+	// BinDir is the directory from which the container runtime executes the CNI plugins
+	BinDir string
+
+	// ConfDir is the directory of the network configurations read by the container runtime
+	ConfDir string
+
+	// KubeconfigDir is the directory of the kubeconfig used by the plugin as seen from the node, which is ConfDir
+	// as mounted on the node
+	KubeconfigDir string
+
+	// APIServer is the URL of the Kubernetes API server reachable from the node
+	APIServer string
+
+	// NetConf holds the OSM settings of the plugin's network configuration
+	NetConf NetConf
+
+	// serviceAccountDir is the directory of the service account credentials written to the kubeconfig
+	serviceAccountDir string
+}
+
+// Run installs the plugin, and reinstalls it every interval to refresh the service account token of the kubeconfig
+// and to chain the plugin again when the network configuration is rewritten by the pod network's plugin. The plugin
+// is uninstalled when the context is canceled, so that pods do not fail to start once the DaemonSet is removed.
+func (i *Installer) Run(ctx context.Context, interval time.Duration) error {
+	if err := i.Install(); err != nil {
+		return err
+	}
+	log.Info().Msgf("Installed the OSM CNI plugin in %s and %s", i.BinDir, i.ConfDir)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return i.Uninstall()
+		case <-ticker.C:
+			if err := i.Install(); err != nil {
+				log.Error().Err(err).Msg("Error reinstalling the OSM CNI plugin")
+			}
+		}
+	}
+}
+
+// Install copies the plugin binary, writes the kubeconfig of the plugin, and chains the plugin in the network
+// configuration of the node
+func (i *Installer) Install() error {
+	if err := copyFile(i.PluginBinary, filepath.Join(i.BinDir, PluginType), 0755); err != nil {
+		return fmt.Errorf("error copying the plugin binary: %w", err)
+	}
+	if err := i.writeKubeconfig(); err != nil {
+		return fmt.Errorf("error writing the plugin kubeconfig: %w", err)
+	}
+	confFile, err := findNetConfFile(i.ConfDir)
+	if err != nil {
+		return err
+	}
+	return i.chainPlugin(confFile)
+}
+
+// Uninstall removes the plugin from the network configuration of the node, then removes its kubeconfig and binary
+func (i *Installer) Uninstall() error {
+	confFile, err := findNetConfFile(i.ConfDir)
+	if err != nil {
+		return err
+	}
+	if err := unchainPlugin(confFile); err != nil {
+		return err
+	}
+	for _, file := range []string{filepath.Join(i.ConfDir, KubeconfigFileName), filepath.Join(i.BinDir, PluginType)} {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	log.Info().Msgf("Uninstalled the OSM CNI plugin from %s and %s", i.BinDir, i.ConfDir)
+	return nil
+}
+
+// writeKubeconfig writes the kubeconfig of the plugin, authenticating with the service account of the installer
+func (i *Installer) writeKubeconfig() error {
+	saDir := i.serviceAccountDir
+	if saDir == "" {
+		saDir = serviceAccountDir
+	}
+	token, err := os.ReadFile(filepath.Join(saDir, "token")) // #nosec G304: service account token
+	if err != nil {
+		return err
+	}
+	ca, err := os.ReadFile(filepath.Join(saDir, "ca.crt")) // #nosec G304: service account CA
+	if err != nil {
+		return err
+	}
+
+	kubeconfig := clientcmdapi.NewConfig()
+	kubeconfig.Clusters[PluginType] = &clientcmdapi.Cluster{
+		Server:                   i.APIServer,
+		CertificateAuthorityData: ca,
+	}
+	kubeconfig.AuthInfos[PluginType] = &clientcmdapi.AuthInfo{
+		Token: strings.TrimSpace(string(token)),
+	}
+	kubeconfig.Contexts[PluginType] = &clientcmdapi.Context{
+		Cluster:  PluginType,
+		AuthInfo: PluginType,
+	}
+	kubeconfig.CurrentContext = PluginType
+
+	data, err := clientcmd.Write(*kubeconfig)
+	if err != nil {
+		return err
+	}
+	return writeFileIfChanged(filepath.Join(i.ConfDir, KubeconfigFileName), data, 0600)
+}
+
+// findNetConfFile returns the network configuration file used by the container runtime, which is the first one in
+// lexicographic order of the network configuration directory
+func findNetConfFile(confDir string) (string, error) {
+	entries, err := os.ReadDir(confDir)
+	if err != nil {
+		return "", err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch filepath.Ext(entry.Name()) {
+		case ".conflist", ".conf", ".json":
+			files = append(files, entry.Name())
+		}
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("no network configuration found in %s", confDir)
+	}
+	sort.Strings(files)
+	return filepath.Join(confDir, files[0]), nil
+}
+
+// chainPlugin appends the plugin to the list of plugins of the given network configuration file, replacing a
+// previous configuration of the plugin. A single plugin configuration is converted to a configuration list, as
+// only lists can be chained.
+func (i *Installer) chainPlugin(confFile string) error {
+	conf, err := readNetConfList(confFile)
+	if err != nil {
+		return err
+	}
+
+	netConf := i.NetConf
+	netConf.Type = PluginType
+	netConf.Kubeconfig = filepath.Join(i.KubeconfigDir, KubeconfigFileName)
+	pluginConf, err := toMap(netConf)
+	if err != nil {
+		return err
+	}
+	// The version and name of the plugins are the ones of the list
+	delete(pluginConf, "cniVersion")
+	delete(pluginConf, "name")
+	conf["plugins"] = append(removePlugin(conf["plugins"]), pluginConf)
+
+	data, err := json.MarshalIndent(conf, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	confListFile := confFile
+	if filepath.Ext(confFile) != ".conflist" {
+		confListFile = strings.TrimSuffix(confFile, filepath.Ext(confFile)) + ".conflist"
+	}
+	if err := writeFileIfChanged(confListFile, data, 0644); err != nil {
+		return err
+	}
+	if confListFile != confFile {
+		return os.Remove(confFile)
+	}
+	return nil
+}
+
+// unchainPlugin removes the plugin from the list of plugins of the given network configuration file
+func unchainPlugin(confFile string) error {
+	conf, err := readNetConfList(confFile)
+	if err != nil {
+		return err
+	}
+	conf["plugins"] = removePlugin(conf["plugins"])
+
+	data, err := json.MarshalIndent(conf, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileIfChanged(confFile, data, 0644)
+}
+
+// readNetConfList reads the given network configuration file as a configuration list
+func readNetConfList(confFile string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(confFile) // #nosec G304: network configuration of the node
+	if err != nil {
+		return nil, err
+	}
+	conf := make(map[string]interface{})
+	if err := json.Unmarshal(data, &conf); err != nil {
+		return nil, fmt.Errorf("error decoding network configuration %s: %w", confFile, err)
+	}
+	if _, ok := conf["plugins"]; ok {
+		return conf, nil
+	}
+
+	// Convert the single plugin configuration to a configuration list
+	return map[string]interface{}{
+		"cniVersion": conf["cniVersion"],
+		"name":       conf["name"],
+		"plugins":    []interface{}{conf},
+	}, nil
+}
+
+// removePlugin returns the given list of plugin configurations without the OSM CNI plugin
+func removePlugin(plugins interface{}) []interface{} {
+	list, _ := plugins.([]interface{})
+	var filtered []interface{}
+	for _, plugin := range list {
+		if conf, ok := plugin.(map[string]interface{}); ok && conf["type"] == PluginType {
+			continue
+		}
+		filtered = append(filtered, plugin)
+	}
+	return filtered
+}
+
+func toMap(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]interface{})
+	return m, json.Unmarshal(data, &m)
+}
+
+// writeFileIfChanged atomically writes the given data to the given file, unless the file already has this content
+func writeFileIfChanged(file string, data []byte, perm os.FileMode) error {
+	if existing, err := os.ReadFile(file); err == nil && bytes.Equal(existing, data) { // #nosec G304: file managed by the installer
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) //nolint: errcheck
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close() //nolint: errcheck,gosec
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// copyFile atomically copies the given source file to the given destination
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src) // #nosec G304: plugin binary of the installer image
+	if err != nil {
+		return err
+	}
+	defer in.Close() //nolint: errcheck,gosec
+
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return err
+	}
+	return writeFileIfChanged(dst, data, perm)
+}
@@ -0,0 +1,165 @@
+package cni
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const bridgeConf = `{
+  "cniVersion": "0.4.0",
+  "name": "bridge",
+  "type": "bridge",
+  "ipam": {"type": "host-local"}
+}`
+
+const calicoConfList = `{
+  "cniVersion": "0.3.1",
+  "name": "k8s-pod-network",
+  "plugins": [
+    {"type": "calico"},
+    {"type": "portmap", "capabilities": {"portMappings": true}}
+  ]
+}`
+
+func newTestInstaller(t *testing.T) *Installer {
+	t.Helper()
+	dir := t.TempDir()
+
+	installer := &Installer{
+		PluginBinary:      filepath.Join(dir, "osm-cni"),
+		BinDir:            filepath.Join(dir, "bin"),
+		ConfDir:           filepath.Join(dir, "net.d"),
+		KubeconfigDir:     "/etc/cni/net.d",
+		APIServer:         "https://10.96.0.1:443",
+		serviceAccountDir: filepath.Join(dir, "serviceaccount"),
+		NetConf: NetConf{
+			OSMNamespace:      "osm-system",
+			MeshConfigName:    "osm-mesh-config",
+			ExcludeNamespaces: []string{"kube-system"},
+		},
+	}
+	for _, d := range []string{installer.BinDir, installer.ConfDir, installer.serviceAccountDir} {
+		tassert.NoError(t, os.MkdirAll(d, 0755))
+	}
+	tassert.NoError(t, os.WriteFile(installer.PluginBinary, []byte("binary"), 0600))
+	tassert.NoError(t, os.WriteFile(filepath.Join(installer.serviceAccountDir, "token"), []byte("token\n"), 0600))
+	tassert.NoError(t, os.WriteFile(filepath.Join(installer.serviceAccountDir, "ca.crt"), []byte("ca"), 0600))
+	return installer
+}
+
+func TestInstall(t *testing.T) {
+	testCases := []struct {
+		name             string
+		confFiles        map[string]string
+		expectedConfFile string
+		expectedConf     string
+	}{
+		{
+			name:             "configuration list",
+			confFiles:        map[string]string{"10-calico.conflist": calicoConfList, "99-loopback.conf": bridgeConf},
+			expectedConfFile: "10-calico.conflist",
+			expectedConf: `{
+  "cniVersion": "0.3.1",
+  "name": "k8s-pod-network",
+  "plugins": [
+    {"type": "calico"},
+    {"type": "portmap", "capabilities": {"portMappings": true}},
+    {"type": "osm-cni", "kubeconfig": "/etc/cni/net.d/osm-cni.kubeconfig", "osmNamespace": "osm-system", "meshConfigName": "osm-mesh-config", "excludeNamespaces": ["kube-system"]}
+  ]
+}`,
+		},
+		{
+			name:             "single plugin configuration",
+			confFiles:        map[string]string{"10-bridge.conf": bridgeConf},
+			expectedConfFile: "10-bridge.conflist",
+			expectedConf: `{
+  "cniVersion": "0.4.0",
+  "name": "bridge",
+  "plugins": [
+    {"cniVersion": "0.4.0", "name": "bridge", "type": "bridge", "ipam": {"type": "host-local"}},
+    {"type": "osm-cni", "kubeconfig": "/etc/cni/net.d/osm-cni.kubeconfig", "osmNamespace": "osm-system", "meshConfigName": "osm-mesh-config", "excludeNamespaces": ["kube-system"]}
+  ]
+}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			installer := newTestInstaller(t)
+			for name, content := range tc.confFiles {
+				assert.NoError(os.WriteFile(filepath.Join(installer.ConfDir, name), []byte(content), 0600))
+			}
+
+			// Installing twice must not chain the plugin twice
+			assert.NoError(installer.Install())
+			assert.NoError(installer.Install())
+
+			conf, err := os.ReadFile(filepath.Join(installer.ConfDir, tc.expectedConfFile))
+			assert.NoError(err)
+			assert.JSONEq(tc.expectedConf, string(conf))
+
+			binary, err := os.ReadFile(filepath.Join(installer.BinDir, PluginType))
+			assert.NoError(err)
+			assert.Equal("binary", string(binary))
+
+			kubeconfig, err := clientcmd.LoadFromFile(filepath.Join(installer.ConfDir, KubeconfigFileName))
+			assert.NoError(err)
+			assert.Equal("https://10.96.0.1:443", kubeconfig.Clusters[PluginType].Server)
+			assert.Equal([]byte("ca"), kubeconfig.Clusters[PluginType].CertificateAuthorityData)
+			assert.Equal("token", kubeconfig.AuthInfos[PluginType].Token)
+
+			assert.NoError(installer.Uninstall())
+			_, err = os.Stat(filepath.Join(installer.BinDir, PluginType))
+			assert.True(os.IsNotExist(err))
+			_, err = os.Stat(filepath.Join(installer.ConfDir, KubeconfigFileName))
+			assert.True(os.IsNotExist(err))
+			conf, err = os.ReadFile(filepath.Join(installer.ConfDir, tc.expectedConfFile))
+			assert.NoError(err)
+			assert.NotContains(string(conf), PluginType)
+		})
+	}
+}
+
+func TestInstallWithoutNetConf(t *testing.T) {
+	assert := tassert.New(t)
+	installer := newTestInstaller(t)
+
+	assert.Error(installer.Install())
+}
+
+func TestRun(t *testing.T) {
+	assert := tassert.New(t)
+	installer := newTestInstaller(t)
+	confFile := filepath.Join(installer.ConfDir, "10-calico.conflist")
+	assert.NoError(os.WriteFile(confFile, []byte(calicoConfList), 0600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- installer.Run(ctx, 10*time.Millisecond)
+	}()
+
+	// The plugin is chained when installed, and chained again after the network configuration is rewritten
+	assert.Eventually(func() bool {
+		conf, err := os.ReadFile(confFile)
+		return err == nil && string(conf) != calicoConfList
+	}, time.Second, 10*time.Millisecond)
+	assert.NoError(os.WriteFile(confFile, []byte(calicoConfList), 0600))
+	assert.Eventually(func() bool {
+		conf, err := os.ReadFile(confFile)
+		return err == nil && string(conf) != calicoConfList
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.NoError(<-done)
+	conf, err := os.ReadFile(confFile)
+	assert.NoError(err)
+	assert.NotContains(string(conf), PluginType)
+}
@@ -0,0 +1,40 @@
+package cni
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// runInNetns runs the given shell script with the given environment in the given network namespace. The script is
+// run from a locked OS thread moved to the network namespace, as child processes inherit the namespaces of the
+// thread forking them. The thread is never unlocked so that it is terminated with its goroutine, rather than reused
+// by other goroutines in the network namespace of the pod.
+func runInNetns(netns string, script string, env []string) error {
+	errCh := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		errCh <- func() error {
+			f, err := os.Open(netns) // #nosec G304: path of the network namespace passed by the container runtime
+			if err != nil {
+				return err
+			}
+			defer f.Close() //nolint: errcheck,gosec
+
+			if err := unix.Setns(int(f.Fd()), unix.CLONE_NEWNET); err != nil {
+				return fmt.Errorf("error entering network namespace %s: %w", netns, err)
+			}
+
+			cmd := exec.Command("sh", "-c", script) // #nosec G204: script generated by the injector
+			cmd.Env = append(os.Environ(), env...)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				return fmt.Errorf("%w: %s", err, out)
+			}
+			return nil
+		}()
+	}()
+	return <-errCh
+}
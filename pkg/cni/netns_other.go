@@ -0,0 +1,10 @@
+//go:build !linux
+
+package cni
+
+import "errors"
+
+// runInNetns is only supported on Linux, where the network namespaces of pods are set up by the CNI plugins
+func runInNetns(_ string, _ string, _ []string) error {
+	return errors.New("network namespaces are only supported on linux")
+}
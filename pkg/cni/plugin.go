@@ -0,0 +1,176 @@
+// Package cni implements the OSM CNI plugin, which redirects the traffic of meshed pods to their Envoy sidecar when
+// the container runtime sets up the pod network, instead of an init container requiring the NET_ADMIN capability.
+// It also implements the installation of the plugin on the nodes, chained after the plugin of the pod network.
+package cni
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	configClientset "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned"
+	"github.com/openservicemesh/osm/pkg/injector"
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var log = logger.New("osm-cni")
+
+var errNoPodIP = errors.New("the previous plugin did not assign an IP address to the pod")
+
+// Plugin redirects the traffic of the pods injected with an Envoy sidecar to their sidecar
+type Plugin struct {
+	kubeClient   kubernetes.Interface
+	configClient configClientset.Interface
+
+	// runInNetns runs the given shell script with the given environment in the given network namespace
+	runInNetns func(netns string, script string, env []string) error
+}
+
+// NewPlugin returns a new Plugin fetching pods and the MeshConfig with the given clients
+func NewPlugin(kubeClient kubernetes.Interface, configClient configClientset.Interface) *Plugin {
+	return &Plugin{
+		kubeClient:   kubeClient,
+		configClient: configClient,
+		runInNetns:   runInNetns,
+	}
+}
+
+// ParseNetConf parses the network configuration passed by the container runtime on the standard input of the plugin
+func ParseNetConf(stdin []byte) (*NetConf, error) {
+	conf := &NetConf{}
+	if err := json.Unmarshal(stdin, conf); err != nil {
+		return nil, NewError(ErrCodeDecodingFailed, "error decoding network configuration", err)
+	}
+	switch {
+	case conf.Kubeconfig == "":
+		return nil, NewError(ErrCodeInvalidConfig, "kubeconfig is not set in the network configuration", nil)
+	case conf.OSMNamespace == "":
+		return nil, NewError(ErrCodeInvalidConfig, "osmNamespace is not set in the network configuration", nil)
+	case conf.MeshConfigName == "":
+		return nil, NewError(ErrCodeInvalidConfig, "meshConfigName is not set in the network configuration", nil)
+	}
+	return conf, nil
+}
+
+// ArgsFromEnv returns the arguments passed by the container runtime in the environment of the plugin
+func ArgsFromEnv(getenv func(string) string) (*Args, error) {
+	args := &Args{
+		Command:     getenv("CNI_COMMAND"),
+		ContainerID: getenv("CNI_CONTAINERID"),
+		Netns:       getenv("CNI_NETNS"),
+		IfName:      getenv("CNI_IFNAME"),
+	}
+	if args.Command == "" {
+		return nil, NewError(ErrCodeInvalidEnv, "CNI_COMMAND is not set", nil)
+	}
+	if args.Command == CmdAdd && args.Netns == "" {
+		return nil, NewError(ErrCodeInvalidEnv, "CNI_NETNS is not set", nil)
+	}
+
+	// CNI_ARGS is a list of semicolon separated key=value pairs
+	for _, pair := range strings.Split(getenv("CNI_ARGS"), ";") {
+		key, value, _ := strings.Cut(pair, "=")
+		switch key {
+		case "K8S_POD_NAME":
+			args.PodName = value
+		case "K8S_POD_NAMESPACE":
+			args.PodNamespace = value
+		}
+	}
+	return args, nil
+}
+
+// Add sets up the redirection of the traffic of the pod to its sidecar in the network namespace of the pod, when the
+// pod was injected with a sidecar and its traffic is not already redirected by the init container
+func (p *Plugin) Add(ctx context.Context, conf *NetConf, args *Args) error {
+	if args.PodName == "" || args.PodNamespace == "" {
+		log.Debug().Msgf("Skipping container %s not belonging to a Kubernetes pod", args.ContainerID)
+		return nil
+	}
+	for _, ns := range conf.ExcludeNamespaces {
+		if ns == args.PodNamespace {
+			log.Debug().Msgf("Skipping pod %s/%s in excluded namespace", args.PodNamespace, args.PodName)
+			return nil
+		}
+	}
+
+	pod, err := p.kubeClient.CoreV1().Pods(args.PodNamespace).Get(ctx, args.PodName, metav1.GetOptions{})
+	if err != nil {
+		return NewError(ErrCodeTryAgainLater, "error fetching pod "+args.PodNamespace+"/"+args.PodName, err)
+	}
+	if !injector.IsTrafficRedirectedByCNI(pod) {
+		log.Debug().Msgf("Skipping pod %s/%s not requiring traffic redirection", args.PodNamespace, args.PodName)
+		return nil
+	}
+
+	meshConfig, err := p.configClient.ConfigV1alpha2().MeshConfigs(conf.OSMNamespace).Get(ctx, conf.MeshConfigName, metav1.GetOptions{})
+	if err != nil {
+		return NewError(ErrCodeTryAgainLater, "error fetching MeshConfig "+conf.OSMNamespace+"/"+conf.MeshConfigName, err)
+	}
+
+	script, err := injector.GenerateTrafficRedirectionCommandsForPod(pod, *meshConfig)
+	if err != nil {
+		return NewError(ErrCodeRedirectFailed, "error generating traffic redirection rules for pod "+args.PodNamespace+"/"+args.PodName, err)
+	}
+
+	podIPs, err := getPodIPs(conf.PrevResult)
+	if err != nil {
+		return NewError(ErrCodeDecodingFailed, "error reading pod IP addresses from the previous result", err)
+	}
+
+	// The commands expect the pod IP addresses in the POD_IPS environment variable, as in the init container
+	if err := p.runInNetns(args.Netns, script, []string{"POD_IPS=" + strings.Join(podIPs, ",")}); err != nil {
+		return NewError(ErrCodeRedirectFailed, "error setting up traffic redirection rules for pod "+args.PodNamespace+"/"+args.PodName, err)
+	}
+
+	log.Info().Msgf("Redirected traffic of pod %s/%s with IPs %v to its sidecar", args.PodNamespace, args.PodName, podIPs)
+	return nil
+}
+
+// getPodIPs returns the IP addresses assigned to the pod by the previous plugins in the chain
+func getPodIPs(prevResult json.RawMessage) ([]string, error) {
+	if len(prevResult) == 0 {
+		return nil, errNoPodIP
+	}
+
+	var res result
+	if err := json.Unmarshal(prevResult, &res); err != nil {
+		return nil, err
+	}
+
+	var ips []string
+	for _, ipConfig := range res.IPs {
+		ip, _, err := net.ParseCIDR(ipConfig.Address)
+		if err != nil {
+			return nil, err
+		}
+		ips = append(ips, ip.String())
+	}
+	if len(ips) == 0 {
+		return nil, errNoPodIP
+	}
+	return ips, nil
+}
+
+// Result returns the result of the plugin for the given network configuration, which is the result of the previous
+// plugin in the chain as the plugin does not change the pod network
+func Result(conf *NetConf) []byte {
+	if len(conf.PrevResult) > 0 {
+		return conf.PrevResult
+	}
+	res, _ := json.Marshal(map[string]string{"cniVersion": conf.CNIVersion})
+	return res
+}
+
+// VersionResult returns the result of the VERSION command, listing the supported versions of the CNI specification
+func VersionResult(cniVersion string) []byte {
+	if cniVersion == "" {
+		cniVersion = "1.0.0"
+	}
+	return []byte(`{"cniVersion":"` + cniVersion + `","supportedVersions":` + supportedVersions + `}`)
+}
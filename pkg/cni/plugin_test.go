@@ -0,0 +1,233 @@
+package cni
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	"github.com/openservicemesh/osm/pkg/constants"
+	configFake "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned/fake"
+)
+
+func TestParseNetConf(t *testing.T) {
+	testCases := []struct {
+		name         string
+		stdin        string
+		expectedCode int
+	}{
+		{
+			name:  "valid configuration",
+			stdin: `{"cniVersion":"1.0.0","name":"net","type":"osm-cni","kubeconfig":"/etc/cni/net.d/osm-cni.kubeconfig","osmNamespace":"osm-system","meshConfigName":"osm-mesh-config","prevResult":{"ips":[]}}`,
+		},
+		{
+			name:         "invalid JSON",
+			stdin:        `{`,
+			expectedCode: ErrCodeDecodingFailed,
+		},
+		{
+			name:         "missing kubeconfig",
+			stdin:        `{"cniVersion":"1.0.0","osmNamespace":"osm-system","meshConfigName":"osm-mesh-config"}`,
+			expectedCode: ErrCodeInvalidConfig,
+		},
+		{
+			name:         "missing OSM namespace",
+			stdin:        `{"cniVersion":"1.0.0","kubeconfig":"/kubeconfig","meshConfigName":"osm-mesh-config"}`,
+			expectedCode: ErrCodeInvalidConfig,
+		},
+		{
+			name:         "missing MeshConfig name",
+			stdin:        `{"cniVersion":"1.0.0","kubeconfig":"/kubeconfig","osmNamespace":"osm-system"}`,
+			expectedCode: ErrCodeInvalidConfig,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			conf, err := ParseNetConf([]byte(tc.stdin))
+			if tc.expectedCode != 0 {
+				var cniErr *Error
+				assert.True(errors.As(err, &cniErr))
+				assert.Equal(tc.expectedCode, cniErr.Code)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal("osm-system", conf.OSMNamespace)
+			assert.Equal("osm-mesh-config", conf.MeshConfigName)
+			assert.JSONEq(`{"ips":[]}`, string(conf.PrevResult))
+		})
+	}
+}
+
+func TestArgsFromEnv(t *testing.T) {
+	assert := tassert.New(t)
+
+	env := map[string]string{
+		"CNI_COMMAND":     "ADD",
+		"CNI_CONTAINERID": "container",
+		"CNI_NETNS":       "/var/run/netns/pod",
+		"CNI_IFNAME":      "eth0",
+		"CNI_ARGS":        "IgnoreUnknown=1;K8S_POD_NAMESPACE=ns;K8S_POD_NAME=pod;K8S_POD_INFRA_CONTAINER_ID=container",
+	}
+	args, err := ArgsFromEnv(func(key string) string { return env[key] })
+	assert.NoError(err)
+	assert.Equal(&Args{
+		Command:      CmdAdd,
+		ContainerID:  "container",
+		Netns:        "/var/run/netns/pod",
+		IfName:       "eth0",
+		PodName:      "pod",
+		PodNamespace: "ns",
+	}, args)
+
+	delete(env, "CNI_NETNS")
+	_, err = ArgsFromEnv(func(key string) string { return env[key] })
+	assert.Error(err)
+
+	_, err = ArgsFromEnv(func(key string) string { return "" })
+	assert.Error(err)
+}
+
+func TestAdd(t *testing.T) {
+	meshConfig := &configv1alpha2.MeshConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "osm-mesh-config",
+			Namespace: "osm-system",
+		},
+	}
+	injectedPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod",
+			Namespace: "ns",
+			Labels:    map[string]string{constants.EnvoyUniqueIDLabelName: "uuid"},
+		},
+	}
+	notInjectedPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other",
+			Namespace: "ns",
+		},
+	}
+	prevResult := json.RawMessage(`{"cniVersion":"1.0.0","ips":[{"address":"10.0.0.5/24"},{"address":"fd00::5/64"}]}`)
+
+	testCases := []struct {
+		name              string
+		args              *Args
+		prevResult        json.RawMessage
+		meshConfig        *configv1alpha2.MeshConfig
+		excludeNamespaces []string
+		runErr            error
+		expectedEnv       []string
+		expectedCode      int
+	}{
+		{
+			name:        "injected pod",
+			args:        &Args{Netns: "/netns", PodName: "pod", PodNamespace: "ns"},
+			prevResult:  prevResult,
+			meshConfig:  meshConfig,
+			expectedEnv: []string{"POD_IPS=10.0.0.5,fd00::5"},
+		},
+		{
+			name:       "pod not injected",
+			args:       &Args{Netns: "/netns", PodName: "other", PodNamespace: "ns"},
+			prevResult: prevResult,
+			meshConfig: meshConfig,
+		},
+		{
+			name:              "pod in excluded namespace",
+			args:              &Args{Netns: "/netns", PodName: "pod", PodNamespace: "ns"},
+			prevResult:        prevResult,
+			excludeNamespaces: []string{"kube-system", "ns"},
+		},
+		{
+			name:       "container not belonging to a pod",
+			args:       &Args{Netns: "/netns"},
+			prevResult: prevResult,
+		},
+		{
+			name:         "pod not found",
+			args:         &Args{Netns: "/netns", PodName: "unknown", PodNamespace: "ns"},
+			prevResult:   prevResult,
+			meshConfig:   meshConfig,
+			expectedCode: ErrCodeTryAgainLater,
+		},
+		{
+			name:         "MeshConfig not found",
+			args:         &Args{Netns: "/netns", PodName: "pod", PodNamespace: "ns"},
+			prevResult:   prevResult,
+			expectedCode: ErrCodeTryAgainLater,
+		},
+		{
+			name:         "no pod IP",
+			args:         &Args{Netns: "/netns", PodName: "pod", PodNamespace: "ns"},
+			prevResult:   json.RawMessage(`{"cniVersion":"1.0.0","ips":[]}`),
+			meshConfig:   meshConfig,
+			expectedCode: ErrCodeDecodingFailed,
+		},
+		{
+			name:         "iptables error",
+			args:         &Args{Netns: "/netns", PodName: "pod", PodNamespace: "ns"},
+			prevResult:   prevResult,
+			meshConfig:   meshConfig,
+			runErr:       errors.New("iptables-restore: not found"),
+			expectedEnv:  []string{"POD_IPS=10.0.0.5,fd00::5"},
+			expectedCode: ErrCodeRedirectFailed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			var configObjects []runtime.Object
+			if tc.meshConfig != nil {
+				configObjects = append(configObjects, tc.meshConfig)
+			}
+			plugin := NewPlugin(fake.NewSimpleClientset(injectedPod, notInjectedPod), configFake.NewSimpleClientset(configObjects...))
+
+			var actualEnv []string
+			plugin.runInNetns = func(netns string, script string, env []string) error {
+				assert.Equal(tc.args.Netns, netns)
+				assert.Contains(script, "iptables-restore")
+				actualEnv = env
+				return tc.runErr
+			}
+
+			conf := &NetConf{
+				OSMNamespace:      "osm-system",
+				MeshConfigName:    "osm-mesh-config",
+				ExcludeNamespaces: tc.excludeNamespaces,
+				PrevResult:        tc.prevResult,
+			}
+			err := plugin.Add(context.Background(), conf, tc.args)
+			if tc.expectedCode != 0 {
+				var cniErr *Error
+				assert.True(errors.As(err, &cniErr))
+				assert.Equal(tc.expectedCode, cniErr.Code)
+			} else {
+				assert.NoError(err)
+			}
+			assert.Equal(tc.expectedEnv, actualEnv)
+		})
+	}
+}
+
+func TestResult(t *testing.T) {
+	assert := tassert.New(t)
+
+	prevResult := `{"cniVersion":"1.0.0","ips":[{"address":"10.0.0.5/24"}]}`
+	assert.JSONEq(prevResult, string(Result(&NetConf{CNIVersion: "1.0.0", PrevResult: json.RawMessage(prevResult)})))
+	assert.JSONEq(`{"cniVersion":"1.0.0"}`, string(Result(&NetConf{CNIVersion: "1.0.0"})))
+
+	assert.JSONEq(`{"cniVersion":"0.4.0","supportedVersions":["0.3.0","0.3.1","0.4.0","1.0.0"]}`, string(VersionResult("0.4.0")))
+	assert.JSONEq(`{"cniVersion":"1.0.0","supportedVersions":["0.3.0","0.3.1","0.4.0","1.0.0"]}`, string(VersionResult("")))
+}
@@ -0,0 +1,103 @@
+package cni
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// PluginType is the type of the OSM CNI plugin in the network configuration, matching its binary name
+	PluginType = "osm-cni"
+
+	// supportedVersions are the CNI specification versions supported by the plugin. Chaining requires 0.3.0 or later.
+	supportedVersions = `["0.3.0","0.3.1","0.4.0","1.0.0"]`
+)
+
+// CNI commands, set by the container runtime in the CNI_COMMAND environment variable
+const (
+	CmdAdd     = "ADD"
+	CmdDel     = "DEL"
+	CmdCheck   = "CHECK"
+	CmdVersion = "VERSION"
+)
+
+// Error codes of the CNI specification, and codes specific to the plugin starting at 100
+const (
+	ErrCodeInvalidEnv     = 4
+	ErrCodeIOFailure      = 5
+	ErrCodeDecodingFailed = 6
+	ErrCodeInvalidConfig  = 7
+	ErrCodeTryAgainLater  = 11
+	ErrCodeRedirectFailed = 100
+)
+
+// NetConf is the network configuration of the OSM CNI plugin, chained after the plugin setting up the pod network
+type NetConf struct {
+	CNIVersion string `json:"cniVersion"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+
+	// PrevResult is the result of the previous plugin in the chain, which is returned unchanged
+	PrevResult json.RawMessage `json:"prevResult,omitempty"`
+
+	// Kubeconfig is the path of the kubeconfig used to fetch pods and the MeshConfig, written by the installer
+	Kubeconfig string `json:"kubeconfig"`
+
+	// OSMNamespace is the namespace of the mesh control plane
+	OSMNamespace string `json:"osmNamespace"`
+
+	// MeshConfigName is the name of the MeshConfig holding the global exclusion lists
+	MeshConfigName string `json:"meshConfigName"`
+
+	// ExcludeNamespaces are the namespaces whose pods are skipped without querying the API server, so that
+	// system pods can start while the API server is unavailable
+	ExcludeNamespaces []string `json:"excludeNamespaces,omitempty"`
+
+	// LogLevel is the verbosity of the logs of the plugin, written to the container runtime's logs
+	LogLevel string `json:"logLevel,omitempty"`
+}
+
+// Args are the arguments passed by the container runtime to the plugin for a pod
+type Args struct {
+	Command     string
+	ContainerID string
+	Netns       string
+	IfName      string
+
+	// PodName and PodNamespace are set from the K8S_POD_NAME and K8S_POD_NAMESPACE keys of CNI_ARGS
+	PodName      string
+	PodNamespace string
+}
+
+// result is the subset of a CNI result read by the plugin
+type result struct {
+	IPs []struct {
+		// Address is the IP address of the interface, in CIDR notation
+		Address string `json:"address"`
+	} `json:"ips"`
+}
+
+// Error is an error returned to the container runtime, as defined by the CNI specification
+type Error struct {
+	CNIVersion string `json:"cniVersion,omitempty"`
+	Code       int    `json:"code"`
+	Msg        string `json:"msg"`
+	Details    string `json:"details,omitempty"`
+}
+
+// Error returns the message of the error
+func (e *Error) Error() string {
+	if e.Details == "" {
+		return e.Msg
+	}
+	return fmt.Sprintf("%s: %s", e.Msg, e.Details)
+}
+
+// NewError returns a new Error with the given code and message, detailed by the given error when not nil
+func NewError(code int, msg string, err error) *Error {
+	e := &Error{Code: code, Msg: msg}
+	if err != nil {
+		e.Details = err.Error()
+	}
+	return e
+}
@@ -0,0 +1,83 @@
+package cni
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// tokenSize is the size of the random token written by the validation listener to the connections it accepts
+const tokenSize = 16
+
+var errNoValidationAddress = errors.New("no address to validate the traffic redirection with")
+
+// ValidateRedirection verifies that the outbound traffic of the pod is redirected to its sidecar, so that pods whose
+// traffic was not redirected by the plugin, e.g. because it is not installed on the node, fail to start rather than
+// bypass their sidecar. It listens on the given address, in place of the sidecar's outbound listener, and connects to
+// the given addresses, which are only reachable through the redirection. It succeeds once a connection reaches the
+// listener, which is told apart from any other server by a random token written to the connections it accepts.
+func ValidateRedirection(listenAddress string, addresses []string, timeout time.Duration) error {
+	if len(addresses) == 0 {
+		return errNoValidationAddress
+	}
+
+	tokenBytes := make([]byte, tokenSize)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return fmt.Errorf("error generating validation token: %w", err)
+	}
+	token := []byte(hex.EncodeToString(tokenBytes))
+
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return fmt.Errorf("error listening on %s: %w", listenAddress, err)
+	}
+	defer listener.Close() //nolint: errcheck,gosec
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = conn.Write(token)
+			_ = conn.Close()
+		}
+	}()
+
+	var errs []error
+	for _, address := range addresses {
+		err := connect(address, token, timeout)
+		if err == nil {
+			log.Info().Msgf("Connection to %s was redirected to %s", address, listenAddress)
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	return fmt.Errorf("outbound traffic is not redirected to the sidecar, check that the OSM CNI plugin is installed on the node: %v", errs)
+}
+
+// connect connects to the given address and verifies that the connection was accepted by the validation listener
+func connect(address string, token []byte, timeout time.Duration) error {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close() //nolint: errcheck,gosec
+
+	if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+	received := make([]byte, len(token))
+	if _, err := io.ReadFull(conn, received); err != nil {
+		return fmt.Errorf("error reading from %s: %w", address, err)
+	}
+	if !bytes.Equal(received, token) {
+		return fmt.Errorf("connection to %s was not redirected", address)
+	}
+	return nil
+}
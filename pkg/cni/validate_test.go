@@ -0,0 +1,48 @@
+package cni
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+// freeAddress returns a local address no server listens on
+func freeAddress(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	tassert.NoError(t, err)
+	address := l.Addr().String()
+	tassert.NoError(t, l.Close())
+	return address
+}
+
+func TestValidateRedirection(t *testing.T) {
+	assert := tassert.New(t)
+
+	// Connecting to the listen address stands for a connection redirected to the listener
+	listenAddress := freeAddress(t)
+	assert.NoError(ValidateRedirection(listenAddress, []string{freeAddress(t), listenAddress}, time.Second))
+
+	// A connection reaching no server is not redirected
+	assert.Error(ValidateRedirection(freeAddress(t), []string{freeAddress(t)}, time.Second))
+
+	// A connection reaching another server is not redirected
+	other, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(err)
+	defer other.Close() //nolint: errcheck
+	go func() {
+		for {
+			conn, err := other.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = conn.Write([]byte("HTTP/1.1 400 Bad Request\r\n\r\n"))
+			_ = conn.Close()
+		}
+	}()
+	assert.Error(ValidateRedirection(freeAddress(t), []string{other.Addr().String()}, time.Second))
+
+	assert.ErrorIs(ValidateRedirection(freeAddress(t), nil, time.Second), errNoValidationAddress)
+}
@@ -0,0 +1,588 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	smiAccess "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	smiSpecs "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
+	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	mcs "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/messaging"
+	"github.com/openservicemesh/osm/pkg/models"
+)
+
+// supportedTypes are the types of the objects read by the provider. The objects of other types described by the
+// manifests, such as Deployments, are ignored.
+var supportedTypes = map[reflect.Type]bool{
+	reflect.TypeOf(&corev1.Namespace{}):                      true,
+	reflect.TypeOf(&corev1.Service{}):                        true,
+	reflect.TypeOf(&corev1.ServiceAccount{}):                 true,
+	reflect.TypeOf(&corev1.Pod{}):                            true,
+	reflect.TypeOf(&corev1.Secret{}):                         true,
+	reflect.TypeOf(&discoveryv1.EndpointSlice{}):             true,
+	reflect.TypeOf(&smiSplit.TrafficSplit{}):                 true,
+	reflect.TypeOf(&smiSpecs.HTTPRouteGroup{}):               true,
+	reflect.TypeOf(&smiSpecs.TCPRoute{}):                     true,
+	reflect.TypeOf(&smiAccess.TrafficTarget{}):               true,
+	reflect.TypeOf(&configv1alpha2.MeshConfig{}):             true,
+	reflect.TypeOf(&configv1alpha2.MeshRootCertificate{}):    true,
+	reflect.TypeOf(&configv1alpha2.TrustDomainFederation{}):  true,
+	reflect.TypeOf(&configv1alpha2.ExtensionService{}):       true,
+	reflect.TypeOf(&configv1alpha2.SidecarProfile{}):         true,
+	reflect.TypeOf(&policyv1alpha1.Egress{}):                 true,
+	reflect.TypeOf(&policyv1alpha1.IngressBackend{}):         true,
+	reflect.TypeOf(&policyv1alpha1.UpstreamTrafficSetting{}): true,
+	reflect.TypeOf(&policyv1alpha1.Retry{}):                  true,
+	reflect.TypeOf(&policyv1alpha1.Telemetry{}):              true,
+	reflect.TypeOf(&policyv1alpha1.WorkloadEntry{}):          true,
+	reflect.TypeOf(&mcs.ServiceImport{}):                     true,
+	reflect.TypeOf(&mcs.ServiceExport{}):                     true,
+}
+
+// controller implements k8s.Controller over the objects described by the manifests. It reads them the way the
+// Kubernetes client reads the objects of its informers, and publishes their changes through the message broker the
+// way the Kubernetes client publishes the events of its informers.
+type controller struct {
+	osmNamespace   string
+	meshConfigName string
+	msgBroker      *messaging.Broker
+
+	mu sync.RWMutex
+
+	// loaded are the objects last loaded from the manifests, keyed by objectKey
+	loaded map[objectKey]runtime.Object
+
+	// objects are the current objects, which differ from the objects loaded from the manifests once updated, e.g.
+	// when their status is written, keyed by objectKey
+	objects map[objectKey]runtime.Object
+
+	// byType are the current objects of each type, sorted by key
+	byType map[reflect.Type][]runtime.Object
+
+	mrcHandlers []cache.ResourceEventHandler
+}
+
+// change is a change to an object, published as an event once applied
+type change struct {
+	eventType events.EventType
+	oldObj    runtime.Object
+	newObj    runtime.Object
+}
+
+func newController(osmNamespace, meshConfigName string, msgBroker *messaging.Broker) *controller {
+	return &controller{
+		osmNamespace:   osmNamespace,
+		meshConfigName: meshConfigName,
+		msgBroker:      msgBroker,
+		loaded:         make(map[objectKey]runtime.Object),
+		objects:        make(map[objectKey]runtime.Object),
+		byType:         make(map[reflect.Type][]runtime.Object),
+	}
+}
+
+// load applies the changes to the objects described by the manifests since they were last loaded, and publishes them.
+// The objects described by unchanged manifests keep their updates.
+func (c *controller) load(objects map[objectKey]runtime.Object) {
+	keys := make([]objectKey, 0, len(objects))
+	for key := range objects {
+		keys = append(keys, key)
+	}
+	sortKeys(keys)
+
+	c.mu.Lock()
+	var changes []change
+	for _, key := range keys {
+		obj := objects[key]
+		previous, ok := c.loaded[key]
+		switch {
+		case !ok:
+			changes = append(changes, change{eventType: events.Added, newObj: obj})
+			c.objects[key] = obj
+		case !equality.Semantic.DeepEqual(previous, obj):
+			changes = append(changes, change{eventType: events.Updated, oldObj: c.objects[key], newObj: obj})
+			c.objects[key] = obj
+		}
+	}
+
+	var removed []objectKey
+	for key := range c.loaded {
+		if _, ok := objects[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	sortKeys(removed)
+	// The namespaces are deleted after the objects they hold
+	for i := len(removed) - 1; i >= 0; i-- {
+		changes = append(changes, change{eventType: events.Deleted, oldObj: c.objects[removed[i]]})
+		delete(c.objects, removed[i])
+	}
+
+	c.loaded = objects
+	c.index()
+	c.mu.Unlock()
+
+	for _, ch := range changes {
+		c.publish(ch.eventType, ch.oldObj, ch.newObj)
+	}
+}
+
+// index sorts the current objects by type, c.mu must be held for writing
+func (c *controller) index() {
+	keys := make([]objectKey, 0, len(c.objects))
+	for key := range c.objects {
+		keys = append(keys, key)
+	}
+	sortKeys(keys)
+
+	c.byType = make(map[reflect.Type][]runtime.Object)
+	for _, key := range keys {
+		obj := c.objects[key]
+		t := reflect.TypeOf(obj)
+		c.byType[t] = append(c.byType[t], obj)
+	}
+}
+
+// update replaces the current object with the type, namespace and name of the given object by it, and publishes the
+// update
+func (c *controller) update(obj runtime.Object) error {
+	accessor, ok := obj.(metav1.Object)
+	if !ok {
+		return fmt.Errorf("unsupported object %T", obj)
+	}
+
+	c.mu.Lock()
+	var oldObj runtime.Object
+	for key, current := range c.objects {
+		if reflect.TypeOf(current) == reflect.TypeOf(obj) && key.namespace == accessor.GetNamespace() && key.name == accessor.GetName() {
+			oldObj = current
+			c.objects[key] = obj
+			break
+		}
+	}
+	if oldObj == nil {
+		c.mu.Unlock()
+		return fmt.Errorf("%T %s/%s not found", obj, accessor.GetNamespace(), accessor.GetName())
+	}
+	c.index()
+	c.mu.Unlock()
+
+	c.publish(events.Updated, oldObj, obj)
+	return nil
+}
+
+// shouldObserve returns whether the events of the given object are published, as k8s.Client does
+func (c *controller) shouldObserve(obj runtime.Object) bool {
+	switch v := obj.(type) {
+	case *mcs.ServiceImport, *mcs.ServiceExport:
+		// There are no events for multicluster services
+		return false
+	case *corev1.Namespace, *configv1alpha2.MeshConfig, *configv1alpha2.MeshRootCertificate, *configv1alpha2.ExtensionService,
+		*configv1alpha2.TrustDomainFederation, *configv1alpha2.SidecarProfile:
+		return true
+	case metav1.Object:
+		return c.IsMonitoredNamespace(v.GetNamespace())
+	}
+	return false
+}
+
+// publish publishes the given event through the message broker, and notifies the MeshRootCertificate event handlers
+func (c *controller) publish(eventType events.EventType, oldObj, newObj runtime.Object) {
+	obj := newObj
+	if eventType == events.Deleted {
+		obj = oldObj
+	}
+
+	if _, ok := obj.(*configv1alpha2.MeshRootCertificate); ok {
+		c.mu.RLock()
+		handlers := c.mrcHandlers
+		c.mu.RUnlock()
+		for _, handler := range handlers {
+			switch eventType {
+			case events.Added:
+				handler.OnAdd(newObj)
+			case events.Updated:
+				handler.OnUpdate(oldObj, newObj)
+			case events.Deleted:
+				handler.OnDelete(oldObj)
+			}
+		}
+	}
+
+	if !c.shouldObserve(obj) {
+		return
+	}
+
+	msg := events.PubSubMessage{
+		Kind:   events.GetKind(obj),
+		Type:   eventType,
+		NewObj: newObj,
+		OldObj: oldObj,
+	}
+	log.Debug().Str("event", msg.Topic()).Msgf("Publishing event for %T", obj)
+	c.msgBroker.GetQueue().AddRateLimited(msg)
+}
+
+// list returns the current objects of type T, sorted by namespace and name
+func list[T runtime.Object](c *controller) []T {
+	var zero T
+	c.mu.RLock()
+	objects := c.byType[reflect.TypeOf(zero)]
+	c.mu.RUnlock()
+
+	items := make([]T, 0, len(objects))
+	for _, obj := range objects {
+		items = append(items, obj.(T))
+	}
+	return items
+}
+
+// listMonitored returns the current objects of type T in the namespaces monitored by the mesh
+func listMonitored[T runtime.Object](c *controller) []T {
+	var items []T
+	for _, item := range list[T](c) {
+		if c.IsMonitoredNamespace(namespaceOf(item)) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// listInNamespace returns the current objects of type T in the given namespace
+func listInNamespace[T runtime.Object](c *controller, namespace string) []T {
+	var items []T
+	for _, item := range list[T](c) {
+		if namespaceOf(item) == namespace {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// get returns the current object of type T with the given namespace and name, and whether it exists
+func get[T runtime.Object](c *controller, namespace, name string) (T, bool) {
+	for _, item := range list[T](c) {
+		if accessor, ok := runtime.Object(item).(metav1.Object); ok && accessor.GetNamespace() == namespace && accessor.GetName() == name {
+			return item, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// namespaceOf returns the namespace of the given object
+func namespaceOf(obj runtime.Object) string {
+	if accessor, ok := obj.(metav1.Object); ok {
+		return accessor.GetNamespace()
+	}
+	return ""
+}
+
+// IsMonitoredNamespace returns whether the namespace with the given name is monitored by the mesh, which all the
+// namespaces except the OSM namespace are
+func (c *controller) IsMonitoredNamespace(namespace string) bool {
+	if namespace == c.osmNamespace {
+		return false
+	}
+	_, ok := get[*corev1.Namespace](c, "", namespace)
+	return ok
+}
+
+// ListNamespaces returns the namespaces monitored by the mesh
+func (c *controller) ListNamespaces() ([]*corev1.Namespace, error) {
+	var namespaces []*corev1.Namespace
+	for _, ns := range list[*corev1.Namespace](c) {
+		if ns.Name != c.osmNamespace {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces, nil
+}
+
+// GetNamespace returns the monitored namespace with the given name, nil otherwise
+func (c *controller) GetNamespace(name string) *corev1.Namespace {
+	if !c.IsMonitoredNamespace(name) {
+		return nil
+	}
+	ns, _ := get[*corev1.Namespace](c, "", name)
+	return ns
+}
+
+// GetService returns the service with the given name and namespace, nil otherwise
+func (c *controller) GetService(name, namespace string) *corev1.Service {
+	svc, _ := get[*corev1.Service](c, namespace, name)
+	return svc
+}
+
+// ListServices returns the services in the monitored namespaces
+func (c *controller) ListServices() []*corev1.Service {
+	return listMonitored[*corev1.Service](c)
+}
+
+// ListServiceAccounts returns the service accounts in the monitored namespaces
+func (c *controller) ListServiceAccounts() []*corev1.ServiceAccount {
+	return listMonitored[*corev1.ServiceAccount](c)
+}
+
+// ListPods returns the pods in the monitored namespaces
+func (c *controller) ListPods() []*corev1.Pod {
+	return listMonitored[*corev1.Pod](c)
+}
+
+// ListEndpointSlicesForService returns the EndpointSlices of the given service, sorted by name
+func (c *controller) ListEndpointSlicesForService(name, namespace string) []*discoveryv1.EndpointSlice {
+	var slices []*discoveryv1.EndpointSlice
+	for _, slice := range listInNamespace[*discoveryv1.EndpointSlice](c, namespace) {
+		if slice.Labels[discoveryv1.LabelServiceName] == name {
+			slices = append(slices, slice)
+		}
+	}
+	return slices
+}
+
+// isOSMSecret returns whether the given secret is read by OSM, which only reads the secrets labeled as such
+func isOSMSecret(secret *corev1.Secret) bool {
+	return secret.Labels[constants.OSMAppNameLabelKey] == constants.OSMAppNameLabelValue
+}
+
+// GetSecret returns the secret with the given name and namespace, nil otherwise
+func (c *controller) GetSecret(name, namespace string) *models.Secret {
+	secret, ok := get[*corev1.Secret](c, namespace, name)
+	if !ok || !isOSMSecret(secret) {
+		return nil
+	}
+	return &models.Secret{
+		Name:      secret.Name,
+		Namespace: secret.Namespace,
+		Data:      secret.Data,
+		Labels:    secret.Labels,
+	}
+}
+
+// ListSecrets returns the secrets
+func (c *controller) ListSecrets() []*models.Secret {
+	var secrets []*models.Secret
+	for _, secret := range list[*corev1.Secret](c) {
+		if !isOSMSecret(secret) {
+			continue
+		}
+		secrets = append(secrets, &models.Secret{
+			Name:      secret.Name,
+			Namespace: secret.Namespace,
+			Data:      secret.Data,
+			Labels:    secret.Labels,
+		})
+	}
+	return secrets
+}
+
+// UpdateSecret updates the data of the given secret
+func (c *controller) UpdateSecret(_ context.Context, secret *models.Secret) error {
+	corev1Secret, ok := get[*corev1.Secret](c, secret.Namespace, secret.Name)
+	if !ok || !isOSMSecret(corev1Secret) {
+		return fmt.Errorf("secret %s/%s not found", secret.Namespace, secret.Name)
+	}
+	corev1Secret = corev1Secret.DeepCopy()
+	corev1Secret.Data = secret.Data
+	return c.update(corev1Secret)
+}
+
+// GetMeshConfig returns the MeshConfig of the mesh, or the default configuration if it is not described
+func (c *controller) GetMeshConfig() configv1alpha2.MeshConfig {
+	meshConfig, ok := get[*configv1alpha2.MeshConfig](c, c.osmNamespace, c.meshConfigName)
+	if !ok {
+		log.Warn().Msgf("MeshConfig %s/%s does not exist. Default config values will be used.", c.osmNamespace, c.meshConfigName)
+		return configv1alpha2.MeshConfig{}
+	}
+	return *meshConfig
+}
+
+// GetOSMNamespace returns the OSM namespace
+func (c *controller) GetOSMNamespace() string {
+	return c.osmNamespace
+}
+
+// GetMeshRootCertificate returns the MeshRootCertificate with the given name, nil otherwise
+func (c *controller) GetMeshRootCertificate(mrcName string) *configv1alpha2.MeshRootCertificate {
+	mrc, _ := get[*configv1alpha2.MeshRootCertificate](c, c.osmNamespace, mrcName)
+	return mrc
+}
+
+// ListMeshRootCertificates returns the MeshRootCertificates
+func (c *controller) ListMeshRootCertificates() ([]*configv1alpha2.MeshRootCertificate, error) {
+	return listInNamespace[*configv1alpha2.MeshRootCertificate](c, c.osmNamespace), nil
+}
+
+// UpdateMeshRootCertificate updates the given MeshRootCertificate
+func (c *controller) UpdateMeshRootCertificate(obj *configv1alpha2.MeshRootCertificate) (*configv1alpha2.MeshRootCertificate, error) {
+	if err := c.update(obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// UpdateMeshRootCertificateStatus updates the status of the given MeshRootCertificate
+func (c *controller) UpdateMeshRootCertificateStatus(obj *configv1alpha2.MeshRootCertificate) (*configv1alpha2.MeshRootCertificate, error) {
+	return c.UpdateMeshRootCertificate(obj)
+}
+
+// AddMeshRootCertificateEventHandler adds an event handler of the changes to MeshRootCertificates, which is notified
+// of the existing MeshRootCertificates as they are added, as informers do
+func (c *controller) AddMeshRootCertificateEventHandler(handler cache.ResourceEventHandler) error {
+	c.mu.Lock()
+	c.mrcHandlers = append(c.mrcHandlers, handler)
+	c.mu.Unlock()
+
+	mrcs, _ := c.ListMeshRootCertificates()
+	for _, mrc := range mrcs {
+		handler.OnAdd(mrc)
+	}
+	return nil
+}
+
+// ListTrustDomainFederations returns the foreign trust domains federated with the mesh
+func (c *controller) ListTrustDomainFederations() []*configv1alpha2.TrustDomainFederation {
+	return listInNamespace[*configv1alpha2.TrustDomainFederation](c, c.osmNamespace)
+}
+
+// GetExtensionService returns the extension service for the given service ref, nil otherwise
+func (c *controller) GetExtensionService(svc policyv1alpha1.ExtensionServiceRef) *configv1alpha2.ExtensionService {
+	extensionService, _ := get[*configv1alpha2.ExtensionService](c, svc.Namespace, svc.Name)
+	return extensionService
+}
+
+// ListSidecarProfiles returns the SidecarProfiles of the OSM namespace and of the monitored namespaces
+func (c *controller) ListSidecarProfiles() []*configv1alpha2.SidecarProfile {
+	var profiles []*configv1alpha2.SidecarProfile
+	for _, profile := range list[*configv1alpha2.SidecarProfile](c) {
+		if profile.Namespace == c.osmNamespace || c.IsMonitoredNamespace(profile.Namespace) {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles
+}
+
+// GetSidecarConfig returns the effective sidecar configuration of the pods with the given labels in the given
+// namespace, resolved from the MeshConfig and the SidecarProfiles selecting them
+func (c *controller) GetSidecarConfig(namespace string, podLabels map[string]string) (models.SidecarConfig, error) {
+	return k8s.ResolveSidecarConfig(c.GetMeshConfig(), c.ListSidecarProfiles(), c.osmNamespace, c.GetNamespace(namespace), podLabels)
+}
+
+// ListEgressPolicies returns the Egress policies in the monitored namespaces
+func (c *controller) ListEgressPolicies() []*policyv1alpha1.Egress {
+	return listMonitored[*policyv1alpha1.Egress](c)
+}
+
+// ListIngressBackendPolicies returns the IngressBackend policies in the monitored namespaces
+func (c *controller) ListIngressBackendPolicies() []*policyv1alpha1.IngressBackend {
+	return listMonitored[*policyv1alpha1.IngressBackend](c)
+}
+
+// UpdateIngressBackendStatus updates the status of the given IngressBackend
+func (c *controller) UpdateIngressBackendStatus(obj *policyv1alpha1.IngressBackend) (*policyv1alpha1.IngressBackend, error) {
+	if err := c.update(obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// ListRetryPolicies returns the Retry policies in the monitored namespaces
+func (c *controller) ListRetryPolicies() []*policyv1alpha1.Retry {
+	return listMonitored[*policyv1alpha1.Retry](c)
+}
+
+// ListTelemetryPolicies returns the Telemetry policies in the monitored namespaces
+func (c *controller) ListTelemetryPolicies() []*policyv1alpha1.Telemetry {
+	return listMonitored[*policyv1alpha1.Telemetry](c)
+}
+
+// ListWorkloadEntries returns the WorkloadEntries in the monitored namespaces
+func (c *controller) ListWorkloadEntries() []*policyv1alpha1.WorkloadEntry {
+	return listMonitored[*policyv1alpha1.WorkloadEntry](c)
+}
+
+// ListUpstreamTrafficSettings returns the UpstreamTrafficSettings in the monitored namespaces
+func (c *controller) ListUpstreamTrafficSettings() []*policyv1alpha1.UpstreamTrafficSetting {
+	return listMonitored[*policyv1alpha1.UpstreamTrafficSetting](c)
+}
+
+// GetUpstreamTrafficSetting returns the UpstreamTrafficSetting with the given namespaced name, nil otherwise
+func (c *controller) GetUpstreamTrafficSetting(namespacedName *types.NamespacedName) *policyv1alpha1.UpstreamTrafficSetting {
+	setting, _ := get[*policyv1alpha1.UpstreamTrafficSetting](c, namespacedName.Namespace, namespacedName.Name)
+	return setting
+}
+
+// UpdateUpstreamTrafficSettingStatus updates the status of the given UpstreamTrafficSetting
+func (c *controller) UpdateUpstreamTrafficSettingStatus(obj *policyv1alpha1.UpstreamTrafficSetting) (*policyv1alpha1.UpstreamTrafficSetting, error) {
+	if err := c.update(obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// ListTrafficSplits returns the SMI TrafficSplits in the monitored namespaces
+func (c *controller) ListTrafficSplits() []*smiSplit.TrafficSplit {
+	return listMonitored[*smiSplit.TrafficSplit](c)
+}
+
+// ListHTTPTrafficSpecs returns the SMI HTTPRouteGroups in the monitored namespaces
+func (c *controller) ListHTTPTrafficSpecs() []*smiSpecs.HTTPRouteGroup {
+	return listMonitored[*smiSpecs.HTTPRouteGroup](c)
+}
+
+// GetHTTPRouteGroup returns the SMI HTTPRouteGroup with the given name of the form <namespace>/<name> in a monitored
+// namespace, nil otherwise
+func (c *controller) GetHTTPRouteGroup(namespacedName string) *smiSpecs.HTTPRouteGroup {
+	namespace, name, err := cache.SplitMetaNamespaceKey(namespacedName)
+	if err != nil || !c.IsMonitoredNamespace(namespace) {
+		return nil
+	}
+	route, _ := get[*smiSpecs.HTTPRouteGroup](c, namespace, name)
+	return route
+}
+
+// ListTCPTrafficSpecs returns the SMI TCPRoutes in the monitored namespaces
+func (c *controller) ListTCPTrafficSpecs() []*smiSpecs.TCPRoute {
+	return listMonitored[*smiSpecs.TCPRoute](c)
+}
+
+// GetTCPRoute returns the SMI TCPRoute with the given name of the form <namespace>/<name> in a monitored namespace,
+// nil otherwise
+func (c *controller) GetTCPRoute(namespacedName string) *smiSpecs.TCPRoute {
+	namespace, name, err := cache.SplitMetaNamespaceKey(namespacedName)
+	if err != nil || !c.IsMonitoredNamespace(namespace) {
+		return nil
+	}
+	route, _ := get[*smiSpecs.TCPRoute](c, namespace, name)
+	return route
+}
+
+// ListTrafficTargets returns the SMI TrafficTargets in the monitored namespaces
+func (c *controller) ListTrafficTargets() []*smiAccess.TrafficTarget {
+	return listMonitored[*smiAccess.TrafficTarget](c)
+}
+
+// ListServiceImports returns the ServiceImports in the monitored namespaces
+func (c *controller) ListServiceImports() []*mcs.ServiceImport {
+	return listMonitored[*mcs.ServiceImport](c)
+}
+
+// ListServiceExports returns the ServiceExports in the monitored namespaces
+func (c *controller) ListServiceExports() []*mcs.ServiceExport {
+	return listMonitored[*mcs.ServiceExport](c)
+}
@@ -0,0 +1,165 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// manifestExtensions are the extensions of the files holding manifests
+var manifestExtensions = map[string]bool{
+	".yaml": true,
+	".yml":  true,
+	".json": true,
+}
+
+// objectKey identifies an object
+type objectKey struct {
+	schema.GroupKind
+	namespace string
+	name      string
+}
+
+func (k objectKey) String() string {
+	if k.namespace == "" {
+		return fmt.Sprintf("%s %s", k.Kind, k.name)
+	}
+	return fmt.Sprintf("%s %s/%s", k.Kind, k.namespace, k.name)
+}
+
+// loadManifests returns the objects of the supported types described by the manifests of the files of the given
+// directory and its subdirectories, keyed by objectKey. Files are read in lexical order, hidden files and directories
+// are skipped, and an object described more than once is an error.
+func loadManifests(dir string, decoder runtime.Decoder) (map[objectKey]runtime.Object, error) {
+	objects := make(map[objectKey]runtime.Object)
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("error reading manifests directory %s: %w", dir, err)
+		}
+		if entry.IsDir() {
+			if path != dir && isHidden(entry.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isManifest(entry.Name()) {
+			return nil
+		}
+
+		fileObjects, err := decodeFile(path, decoder)
+		if err != nil {
+			return fmt.Errorf("error decoding manifest %s: %w", path, err)
+		}
+		for _, obj := range fileObjects {
+			if !supportedTypes[reflect.TypeOf(obj)] {
+				log.Debug().Msgf("Ignoring %s of unsupported kind in manifest %s", obj.GetObjectKind().GroupVersionKind().Kind, path)
+				continue
+			}
+			setDefaultNamespace(obj)
+			key, err := getObjectKey(obj)
+			if err != nil {
+				return fmt.Errorf("error decoding manifest %s: %w", path, err)
+			}
+			if _, ok := objects[key]; ok {
+				return fmt.Errorf("%s is described more than once, last in manifest %s", key, path)
+			}
+			objects[key] = obj
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// isHidden returns whether the file or directory with the given name is hidden
+func isHidden(name string) bool {
+	return strings.HasPrefix(name, ".")
+}
+
+// isManifest returns whether the file with the given name holds manifests, ignoring hidden and temporary files
+func isManifest(name string) bool {
+	if isHidden(name) {
+		return false
+	}
+	return manifestExtensions[filepath.Ext(name)]
+}
+
+// decodeFile returns the objects described by the YAML documents of the given file
+func decodeFile(path string, decoder runtime.Decoder) ([]runtime.Object, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() //nolint: errcheck,gosec
+
+	var objects []runtime.Object
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(f))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return objects, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if isEmptyDocument(doc) {
+			continue
+		}
+		obj, _, err := decoder.Decode(doc, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, obj)
+	}
+}
+
+// isEmptyDocument returns whether the given YAML document describes nothing, e.g. only holds comments
+func isEmptyDocument(doc []byte) bool {
+	if len(bytes.TrimSpace(doc)) == 0 {
+		return true
+	}
+	var content map[string]interface{}
+	return yaml.Unmarshal(doc, &content) == nil && len(content) == 0
+}
+
+// setDefaultNamespace sets the namespace of the given object to the default namespace if it has none, unless it is a
+// Namespace, as kubectl does
+func setDefaultNamespace(obj runtime.Object) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil || accessor.GetNamespace() != "" || obj.GetObjectKind().GroupVersionKind().Kind == namespaceKind {
+		return
+	}
+	accessor.SetNamespace(metav1.NamespaceDefault)
+}
+
+// getObjectKey returns the key of the given object
+func getObjectKey(obj runtime.Object) (objectKey, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return objectKey{}, err
+	}
+	if accessor.GetName() == "" {
+		return objectKey{}, fmt.Errorf("%s without a name", obj.GetObjectKind().GroupVersionKind().Kind)
+	}
+	return objectKey{
+		GroupKind: obj.GetObjectKind().GroupVersionKind().GroupKind(),
+		namespace: accessor.GetNamespace(),
+		name:      accessor.GetName(),
+	}, nil
+}
@@ -0,0 +1,213 @@
+// Package file implements a compute provider reading the mesh from a directory of YAML manifests rather than from the
+// Kubernetes API server, to generate the configuration of proxies without a cluster, e.g. for local development, in
+// deterministic integration tests, or for review in CI.
+//
+// The objects described by the manifests are parsed and held in memory, and read the way the Kubernetes client reads
+// the objects of its informers. Changes to the manifests are applied as the files are written, and published through
+// the message broker like Kubernetes events are.
+package file
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	smiAccessScheme "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/access/clientset/versioned/scheme"
+	smiSpecsScheme "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/specs/clientset/versioned/scheme"
+	smiSplitScheme "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/split/clientset/versioned/scheme"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	mcsScheme "sigs.k8s.io/mcs-api/pkg/client/clientset/versioned/scheme"
+
+	"github.com/openservicemesh/osm/pkg/compute"
+	"github.com/openservicemesh/osm/pkg/compute/kube"
+	configScheme "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned/scheme"
+	policyScheme "github.com/openservicemesh/osm/pkg/gen/client/policy/clientset/versioned/scheme"
+	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/messaging"
+	"github.com/openservicemesh/osm/pkg/models"
+)
+
+var log = logger.New("file-provider")
+
+const (
+	// namespaceKind is the kind of Namespace objects
+	namespaceKind = "Namespace"
+
+	// reloadDelay is the delay after a change to the manifests before they are reloaded, so that the changes made
+	// to several files at once are applied together
+	reloadDelay = 100 * time.Millisecond
+)
+
+// Provider is a compute.Interface over the objects described by the manifests of a directory and its subdirectories
+type Provider struct {
+	compute.Interface
+
+	dir          string
+	osmNamespace string
+	decoder      runtime.Decoder
+	controller   *controller
+}
+
+// NewProvider returns a provider over the objects described by the manifests of the given directory, publishing
+// their changes through the given message broker. The namespaces of the objects, whether described by the manifests
+// or not, are monitored by the mesh, except the given OSM namespace, which holds the MeshConfig with the given name.
+func NewProvider(dir, osmNamespace, meshConfigName string, msgBroker *messaging.Broker) (*Provider, error) {
+	scheme := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{
+		clientgoscheme.AddToScheme,
+		configScheme.AddToScheme,
+		policyScheme.AddToScheme,
+		smiAccessScheme.AddToScheme,
+		smiSpecsScheme.AddToScheme,
+		smiSplitScheme.AddToScheme,
+		mcsScheme.AddToScheme,
+	} {
+		utilruntime.Must(addToScheme(scheme))
+	}
+
+	p := &Provider{
+		dir:          dir,
+		osmNamespace: osmNamespace,
+		decoder:      serializer.NewCodecFactory(scheme).UniversalDeserializer(),
+		controller:   newController(osmNamespace, meshConfigName, msgBroker),
+	}
+	p.Interface = kube.NewClient(p.controller)
+
+	if err := p.reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ListPods returns the pods described by the manifests in the monitored namespaces, sorted by namespace and name, e.g.
+// to generate the configuration of their proxies
+func (p *Provider) ListPods() []*corev1.Pod {
+	return p.controller.ListPods()
+}
+
+// GetProxyConfig is not supported, as the proxies of the pods described by the manifests can not be reached through
+// the Kubernetes API server
+func (p *Provider) GetProxyConfig(proxy *models.Proxy, _ string, _ *rest.Config) (string, error) {
+	return "", fmt.Errorf("the configuration of proxy %s can not be read without a cluster", proxy)
+}
+
+// Watch applies the changes to the manifests of the directory and its subdirectories, including the subdirectories
+// created later on, until the given channel is closed. Manifests which fail to load are logged, and the objects last
+// loaded are kept.
+func (p *Provider) Watch(stop <-chan struct{}) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("error creating manifests watcher: %w", err)
+	}
+	if err := watchDirs(watcher, p.dir); err != nil {
+		_ = watcher.Close()
+		return err
+	}
+
+	go func() {
+		defer watcher.Close() //nolint: errcheck
+
+		var reload <-chan time.Time
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Has(fsnotify.Create) {
+					if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+						// The manifests of a new subdirectory are loaded, and its changes watched
+						if err := watchDirs(watcher, event.Name); err != nil {
+							log.Error().Err(err).Msgf("Error watching manifests directory %s", event.Name)
+						}
+						reload = time.After(reloadDelay)
+						continue
+					}
+				}
+				if isManifest(filepath.Base(event.Name)) || event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+					reload = time.After(reloadDelay)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Error().Err(err).Msgf("Error watching manifests directory %s", p.dir)
+			case <-reload:
+				if err := p.reload(); err != nil {
+					log.Error().Err(err).Msgf("Error loading manifests, keeping the objects last loaded")
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// watchDirs adds the given directory and its subdirectories to the given watcher
+func watchDirs(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if path != dir && isHidden(entry.Name()) {
+			return filepath.SkipDir
+		}
+		if err := watcher.Add(path); err != nil {
+			return fmt.Errorf("error watching manifests directory %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// reload loads the manifests and applies the changes since they were last loaded
+func (p *Provider) reload() error {
+	objects, err := loadManifests(p.dir, p.decoder)
+	if err != nil {
+		return err
+	}
+	p.addNamespaces(objects)
+	p.controller.load(objects)
+	log.Debug().Msgf("Loaded %d objects from manifests directory %s", len(objects), p.dir)
+	return nil
+}
+
+// addNamespaces adds the namespaces of the given objects to them, except the OSM namespace
+func (p *Provider) addNamespaces(objects map[objectKey]runtime.Object) {
+	for key := range objects {
+		if key.namespace == "" || key.namespace == p.osmNamespace {
+			continue
+		}
+		nsKey := objectKey{GroupKind: corev1.SchemeGroupVersion.WithKind(namespaceKind).GroupKind(), name: key.namespace}
+		if _, ok := objects[nsKey]; ok {
+			continue
+		}
+		objects[nsKey] = &corev1.Namespace{
+			TypeMeta:   metav1.TypeMeta{APIVersion: corev1.SchemeGroupVersion.String(), Kind: namespaceKind},
+			ObjectMeta: metav1.ObjectMeta{Name: key.namespace},
+		}
+	}
+}
+
+// sortKeys sorts the given keys, namespaces first
+func sortKeys(keys []objectKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		if iNs, jNs := keys[i].Kind == namespaceKind, keys[j].Kind == namespaceKind; iNs != jNs {
+			return iNs
+		}
+		return keys[i].String() < keys[j].String()
+	})
+}
@@ -0,0 +1,245 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/messaging"
+	"github.com/openservicemesh/osm/pkg/models"
+)
+
+const (
+	testOSMNamespace = "osm-system"
+
+	bookstoreManifest = `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: bookstore
+---
+# The service account of the bookstore
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: bookstore
+  namespace: bookstore
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: bookstore
+  namespace: bookstore
+spec:
+  ports:
+  - name: http
+    port: 80
+`
+
+	bookbuyerManifest = `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: bookbuyer
+  namespace: bookbuyer
+---
+apiVersion: policy.openservicemesh.io/v1alpha1
+kind: Retry
+metadata:
+  name: retry
+  namespace: bookbuyer
+spec:
+  source:
+    kind: ServiceAccount
+    name: bookbuyer
+    namespace: bookbuyer
+  destinations:
+  - kind: Service
+    name: bookstore
+    namespace: bookstore
+  retryPolicy:
+    retryOn: 5xx
+    numRetries: 3
+`
+)
+
+func writeManifest(t *testing.T, dir, name, content string) {
+	t.Helper()
+	tassert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+}
+
+func newTestProvider(t *testing.T, dir string) (*Provider, *messaging.Broker) {
+	t.Helper()
+	stop := make(chan struct{})
+	t.Cleanup(func() { close(stop) })
+	broker := messaging.NewBroker(stop)
+	p, err := NewProvider(dir, testOSMNamespace, constants.OSMMeshConfig, broker)
+	tassert.NoError(t, err)
+	return p, broker
+}
+
+func TestLoadManifests(t *testing.T) {
+	a := tassert.New(t)
+	dir := t.TempDir()
+	writeManifest(t, dir, "bookstore.yaml", bookstoreManifest)
+	writeManifest(t, dir, "default.yml", "---\n# empty\n---\napiVersion: v1\nkind: ServiceAccount\nmetadata:\n  name: sa\n")
+	writeManifest(t, dir, "README.md", "not a manifest")
+	writeManifest(t, dir, ".bookstore.yaml.swp", "not a manifest")
+	a.NoError(os.MkdirAll(filepath.Join(dir, "bookbuyer", "policies"), 0700))
+	writeManifest(t, filepath.Join(dir, "bookbuyer", "policies"), "retry.yaml", bookbuyerManifest)
+	a.NoError(os.Mkdir(filepath.Join(dir, ".git"), 0700))
+	writeManifest(t, filepath.Join(dir, ".git"), "hidden.yaml", bookstoreManifest)
+	writeManifest(t, dir, "deployment.yaml", "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: bookstore\n  namespace: bookstore\n")
+	writeManifest(t, dir, "meshconfig.yaml", "apiVersion: config.openservicemesh.io/v1alpha2\nkind: MeshConfig\nmetadata:\n  name: osm-mesh-config\n  namespace: osm-system\nspec:\n  traffic:\n    enablePermissiveTrafficPolicyMode: true\n")
+
+	p, _ := newTestProvider(t, dir)
+	// The manifests of subdirectories are loaded, while hidden directories and unsupported kinds are skipped
+	a.Len(p.controller.objects, 9)
+
+	// Objects without a namespace are in the default namespace, which is monitored like the other namespaces
+	a.True(p.IsMonitoredNamespace("default"))
+	a.True(p.IsMonitoredNamespace("bookbuyer"))
+	a.False(p.IsMonitoredNamespace(testOSMNamespace))
+	a.Len(p.controller.ListServiceAccounts(), 3)
+	a.Len(p.ListServices(), 1)
+	a.Len(p.ListRetryPoliciesForServiceAccount(identity.K8sServiceAccount{Name: "bookbuyer", Namespace: "bookbuyer"}), 1)
+	a.True(p.GetMeshConfig().Spec.Traffic.EnablePermissiveTrafficPolicyMode)
+
+	// The proxies can not be reached without a cluster
+	_, err := p.GetProxyConfig(models.NewProxy(models.KindSidecar, uuid.New(), identity.New("bookstore", "bookstore"), nil, 1), "config_dump", nil)
+	a.Error(err)
+
+	writeManifest(t, dir, "duplicate.yaml", bookstoreManifest)
+	_, err = loadManifests(dir, p.decoder)
+	a.ErrorContains(err, "Namespace bookstore is described more than once")
+	a.NoError(os.Remove(filepath.Join(dir, "duplicate.yaml")))
+
+	writeManifest(t, dir, "unnamed.yaml", "apiVersion: v1\nkind: ServiceAccount\nmetadata:\n  namespace: bookstore\n")
+	_, err = loadManifests(dir, p.decoder)
+	a.ErrorContains(err, "ServiceAccount without a name")
+	a.NoError(os.Remove(filepath.Join(dir, "unnamed.yaml")))
+
+	writeManifest(t, dir, "unknown.yaml", "apiVersion: v1\nkind: Unknown\nmetadata:\n  name: unknown\n")
+	_, err = loadManifests(dir, p.decoder)
+	a.ErrorContains(err, "unknown.yaml")
+
+	_, err = NewProvider(dir, testOSMNamespace, constants.OSMMeshConfig, messaging.NewBroker(nil))
+	a.Error(err)
+}
+
+func TestUpdate(t *testing.T) {
+	a := tassert.New(t)
+	dir := t.TempDir()
+	writeManifest(t, dir, "bookstore.yaml", bookstoreManifest)
+	writeManifest(t, dir, "backend.yaml", `
+apiVersion: policy.openservicemesh.io/v1alpha1
+kind: IngressBackend
+metadata:
+  name: bookstore
+  namespace: bookstore
+spec:
+  backends:
+  - name: bookstore
+    port:
+      number: 80
+      protocol: http
+  sources:
+  - kind: Service
+    name: ingress
+    namespace: ingress
+`)
+
+	p, _ := newTestProvider(t, dir)
+	backends := p.ListIngressBackendPolicies()
+	a.Len(backends, 1)
+	backend := backends[0].DeepCopy()
+	backend.Status.CurrentStatus = "committed"
+	_, err := p.UpdateIngressBackendStatus(backend)
+	a.NoError(err)
+
+	// Updates are kept until the manifests describing the objects change
+	a.NoError(p.reload())
+	a.Equal("committed", p.ListIngressBackendPolicies()[0].Status.CurrentStatus)
+
+	backend.Name = "unknown"
+	_, err = p.UpdateIngressBackendStatus(backend)
+	a.Error(err)
+}
+
+func TestWatch(t *testing.T) {
+	a := tassert.New(t)
+	stop := make(chan struct{})
+	defer close(stop)
+
+	dir := t.TempDir()
+	writeManifest(t, dir, "bookstore.yaml", bookstoreManifest)
+
+	broker := messaging.NewBroker(stop)
+	serviceEvents, unsub := broker.SubscribeKubeEvents(events.Service.Added(), events.Service.Updated(), events.Service.Deleted())
+	defer unsub()
+	retryEvents, unsubRetry := broker.SubscribeKubeEvents(events.RetryPolicy.Added())
+	defer unsubRetry()
+
+	expectEvent := func(ch chan interface{}, topic string) events.PubSubMessage {
+		t.Helper()
+		select {
+		case msg := <-ch:
+			event, ok := msg.(events.PubSubMessage)
+			a.True(ok)
+			a.Equal(topic, event.Topic())
+			return event
+		case <-time.After(5 * time.Second):
+			a.Failf("event not published", "expected %s", topic)
+			return events.PubSubMessage{}
+		}
+	}
+
+	// The objects first loaded are added
+	p, err := NewProvider(dir, testOSMNamespace, constants.OSMMeshConfig, broker)
+	a.NoError(err)
+	expectEvent(serviceEvents, events.Service.Added())
+	a.Len(p.ListServices(), 1)
+	a.Empty(p.ListRetryPoliciesForServiceAccount(identity.K8sServiceAccount{Name: "bookbuyer", Namespace: "bookbuyer"}))
+
+	a.NoError(p.Watch(stop))
+
+	// Adding a manifest to a new subdirectory creates its objects
+	subdir := filepath.Join(dir, "bookbuyer")
+	a.NoError(os.Mkdir(subdir, 0700))
+	writeManifest(t, subdir, "bookbuyer.yaml", bookbuyerManifest)
+	expectEvent(retryEvents, events.RetryPolicy.Added())
+	a.Len(p.ListRetryPoliciesForServiceAccount(identity.K8sServiceAccount{Name: "bookbuyer", Namespace: "bookbuyer"}), 1)
+
+	// Changing an object updates it
+	writeManifest(t, dir, "bookstore.yaml", bookstoreManifest+"  - name: grpc\n    port: 90\n")
+	event := expectEvent(serviceEvents, events.Service.Updated())
+	if svc, ok := event.NewObj.(*corev1.Service); a.True(ok) {
+		a.Len(svc.Spec.Ports, 2)
+	}
+
+	// An invalid manifest keeps the objects last loaded
+	writeManifest(t, subdir, "invalid.yaml", "kind: [")
+	time.Sleep(5 * reloadDelay)
+	// The service is represented by a MeshService per port
+	a.Len(p.ListServices(), 2)
+	a.NoError(os.Remove(filepath.Join(subdir, "invalid.yaml")))
+
+	// Removing an object deletes it
+	writeManifest(t, dir, "bookstore.yaml", "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: bookstore\n")
+	expectEvent(serviceEvents, events.Service.Deleted())
+	a.Empty(p.ListServices())
+
+	// Removing a subdirectory deletes its objects
+	a.NoError(os.RemoveAll(subdir))
+	a.Eventually(func() bool {
+		return len(p.controller.ListServiceAccounts()) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -5,12 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"sort"
 	"strconv"
 	"strings"
 
 	mapset "github.com/deckarep/golang-set"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
@@ -28,6 +30,8 @@ import (
 	"github.com/openservicemesh/osm/pkg/k8s"
 	"github.com/openservicemesh/osm/pkg/models"
 	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/utils"
+	"github.com/openservicemesh/osm/pkg/version"
 )
 
 var (
@@ -56,40 +60,60 @@ func NewClient(kubeController k8s.Controller) *client { //nolint: revive // unex
 func (c *client) ListEndpointsForService(svc service.MeshService) []endpoint.Endpoint {
 	log.Trace().Msgf("Getting Endpoints for MeshService %s on Kubernetes", svc)
 
-	kubernetesEndpoints, err := c.kubeController.GetEndpoints(svc.Name, svc.Namespace)
-	if err != nil || kubernetesEndpoints == nil {
-		log.Info().Msgf("No k8s endpoints found for MeshService %s", svc)
-		return nil
-	}
-
 	var endpoints []endpoint.Endpoint
-	for _, kubernetesEndpoint := range kubernetesEndpoints.Subsets {
-		for _, port := range kubernetesEndpoint.Ports {
+	// The same endpoint may be part of several slices while it is moved from one to the other
+	seen := mapset.NewSet()
+	for _, slice := range c.kubeController.ListEndpointSlicesForService(svc.Name, svc.Namespace) {
+		if slice.AddressType == discoveryv1.AddressTypeFQDN {
+			continue
+		}
+		for _, port := range slice.Ports {
+			if port.Port == nil {
+				continue
+			}
 			// If a TargetPort is specified for the service, filter the endpoint by this port.
 			// This is required to ensure we do not attempt to filter the endpoints when the endpoints
 			// are being listed for a MeshService whose TargetPort is not known.
-			if svc.TargetPort != 0 && port.Port != int32(svc.TargetPort) {
+			if svc.TargetPort != 0 && *port.Port != int32(svc.TargetPort) {
 				// k8s service's port does not match MeshService port, ignore this port
 				continue
 			}
-			for _, address := range kubernetesEndpoint.Addresses {
-				if svc.Subdomain != "" && svc.Subdomain != address.Hostname {
+			for _, sliceEndpoint := range slice.Endpoints {
+				if svc.Subdomain != "" && svc.Subdomain != pointer.StringDeref(sliceEndpoint.Hostname, "") {
 					// if there's a subdomain on this meshservice, make sure it matches the endpoint's hostname
 					continue
 				}
-				ip := net.ParseIP(address.IP)
-				if ip == nil {
-					log.Error().Msgf("Error parsing endpoint IP address %s for MeshService %s", address.IP, svc)
+				usable, terminating := getEndpointConditions(sliceEndpoint.Conditions)
+				if !usable {
 					continue
 				}
-				ept := endpoint.Endpoint{
-					IP:   ip,
-					Port: endpoint.Port(port.Port),
+				for _, address := range sliceEndpoint.Addresses {
+					ip := net.ParseIP(address)
+					if ip == nil {
+						log.Error().Msgf("Error parsing endpoint IP address %s for MeshService %s", address, svc)
+						continue
+					}
+					ept := endpoint.Endpoint{
+						IP:          ip,
+						Port:        endpoint.Port(*port.Port),
+						Zone:        pointer.StringDeref(sliceEndpoint.Zone, ""),
+						Terminating: terminating,
+						ZoneHints:   getEndpointZoneHints(sliceEndpoint),
+					}
+					if !seen.Add(ept.String()) {
+						continue
+					}
+					endpoints = append(endpoints, ept)
 				}
-				endpoints = append(endpoints, ept)
 			}
 		}
 	}
+	if len(endpoints) == 0 {
+		log.Info().Msgf("No k8s endpoints found for MeshService %s", svc)
+	}
+
+	// Workloads outside of Kubernetes are not part of the service's EndpointSlices
+	endpoints = append(endpoints, c.listWorkloadEntryEndpointsForService(svc)...)
 
 	log.Trace().Msgf("Endpoints for MeshService %s: %v", svc, endpoints)
 
@@ -122,6 +146,8 @@ func (c *client) ListEndpointsForIdentity(serviceIdentity identity.ServiceIdenti
 		}
 	}
 
+	endpoints = append(endpoints, c.listWorkloadEntryEndpointsForIdentity(sa)...)
+
 	log.Trace().Msgf("[ListEndpointsForIdentity] Endpoints for service identity (serviceAccount=%s) %s: %+v", serviceIdentity, sa, endpoints)
 
 	return endpoints
@@ -149,12 +175,32 @@ func (c *client) GetServicesForServiceIdentity(svcIdentity identity.ServiceIdent
 		}
 	}
 
+	for _, we := range c.kubeController.ListWorkloadEntries() {
+		if we.Namespace != svcAccount.Namespace || we.Spec.ServiceAccount != svcAccount.Name {
+			continue
+		}
+
+		for _, svc := range c.listServicesForWorkloadEntry(we) {
+			if added := svcSet.Add(svc); added {
+				meshServices = append(meshServices, svc)
+			}
+		}
+	}
+
 	log.Trace().Msgf("Services for service account %s: %v", svcAccount, meshServices)
 	return meshServices
 }
 
 // ListServicesForProxy maps an Envoy instance to a number of Kubernetes services.
 func (c *client) ListServicesForProxy(p *models.Proxy) ([]service.MeshService, error) {
+	we, err := c.getWorkloadEntryForProxy(p)
+	if err != nil {
+		return nil, err
+	}
+	if we != nil {
+		return c.listServicesForWorkloadEntry(we), nil
+	}
+
 	pod, err := c.getPodForProxy(p)
 	if err != nil {
 		return nil, err
@@ -162,6 +208,65 @@ func (c *client) ListServicesForProxy(p *models.Proxy) ([]service.MeshService, e
 	return c.listServicesForPod(pod), nil
 }
 
+// GetZoneForProxy returns the zone of the given proxy, from the endpoint of its pod in the EndpointSlices of its
+// services. The zone of proxies which don't back any service, and of workloads outside of Kubernetes, isn't known.
+func (c *client) GetZoneForProxy(p *models.Proxy) string {
+	pod, err := c.getPodForProxy(p)
+	if err != nil {
+		return ""
+	}
+	visited := mapset.NewSet()
+	for _, svc := range c.listServicesForPod(pod) {
+		if !visited.Add(svc.Name) {
+			// The service is represented by a MeshService per port
+			continue
+		}
+		for _, slice := range c.kubeController.ListEndpointSlicesForService(svc.Name, svc.Namespace) {
+			for _, sliceEndpoint := range slice.Endpoints {
+				if sliceEndpoint.TargetRef != nil && sliceEndpoint.TargetRef.Kind == "Pod" &&
+					sliceEndpoint.TargetRef.Name == pod.Name && sliceEndpoint.Zone != nil {
+					return *sliceEndpoint.Zone
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// ListIPsForProxy returns the IP addresses of the pod of the given proxy, or the address of its WorkloadEntry when the
+// proxy runs outside of Kubernetes. Dual-stack pods have an IP address of each family.
+func (c *client) ListIPsForProxy(p *models.Proxy) []net.IP {
+	we, err := c.getWorkloadEntryForProxy(p)
+	if err != nil {
+		return nil
+	}
+	if we != nil {
+		if ip := net.ParseIP(we.Spec.Address); ip != nil {
+			return []net.IP{ip}
+		}
+		return nil
+	}
+
+	pod, err := c.getPodForProxy(p)
+	if err != nil {
+		return nil
+	}
+	podIPs := pod.Status.PodIPs
+	if len(podIPs) == 0 && pod.Status.PodIP != "" {
+		podIPs = []corev1.PodIP{{IP: pod.Status.PodIP}}
+	}
+	var ips []net.IP
+	for _, podIP := range podIPs {
+		ip := net.ParseIP(podIP.IP)
+		if ip == nil {
+			log.Error().Msgf("Error parsing IP address %s of pod %s/%s", podIP.IP, pod.Namespace, pod.Name)
+			continue
+		}
+		ips = append(ips, ip)
+	}
+	return ips
+}
+
 func (c *client) listServicesForPod(pod *corev1.Pod) []service.MeshService {
 	var meshServices []service.MeshService
 	for _, svc := range c.getServicesByLabels(pod.ObjectMeta.Labels, pod.Namespace) {
@@ -225,18 +330,24 @@ func (c *client) GetResolvableEndpointsForService(svc service.MeshService) []end
 		return c.ListEndpointsForService(svc)
 	}
 
-	// Cluster IP is present
-	ip := net.ParseIP(kubeService.Spec.ClusterIP)
-	if ip == nil {
-		log.Error().Msgf("Could not parse Cluster IP %s", kubeService.Spec.ClusterIP)
-		return nil
+	// Cluster IP is present. Dual-stack services have a Cluster IP of each family, the first one being the Cluster IP.
+	clusterIPs := kubeService.Spec.ClusterIPs
+	if len(clusterIPs) == 0 {
+		clusterIPs = []string{kubeService.Spec.ClusterIP}
 	}
+	for _, clusterIP := range clusterIPs {
+		ip := net.ParseIP(clusterIP)
+		if ip == nil {
+			log.Error().Msgf("Could not parse Cluster IP %s", clusterIP)
+			return nil
+		}
 
-	for _, svcPort := range kubeService.Spec.Ports {
-		endpoints = append(endpoints, endpoint.Endpoint{
-			IP:   ip,
-			Port: endpoint.Port(svcPort.Port),
-		})
+		for _, svcPort := range kubeService.Spec.Ports {
+			endpoints = append(endpoints, endpoint.Endpoint{
+				IP:   ip,
+				Port: endpoint.Port(svcPort.Port),
+			})
+		}
 	}
 
 	return endpoints
@@ -272,11 +383,11 @@ func (c *client) ListServices() []service.MeshService {
 
 // IsMetricsEnabled checks if prometheus metrics scraping are enabled on this pod.
 func (c *client) IsMetricsEnabled(proxy *models.Proxy) (bool, error) {
-	pod, err := c.getPodForProxy(proxy)
+	annotations, err := c.getWorkloadAnnotations(proxy)
 	if err != nil {
 		return false, err
 	}
-	val, ok := pod.Annotations[constants.PrometheusScrapeAnnotation]
+	val, ok := annotations[constants.PrometheusScrapeAnnotation]
 	if !ok {
 		return false, nil
 	}
@@ -434,6 +545,20 @@ func DetectIngressBackendConflicts(x policyv1alpha1.IngressBackend, y policyv1al
 
 // GetProxyStatsHeaders returns stats headers for the given proxy.
 func (c *client) GetProxyStatsHeaders(p *models.Proxy) (map[string]string, error) {
+	we, err := c.getWorkloadEntryForProxy(p)
+	if err != nil {
+		log.Warn().Str("proxy", p.String()).Msg("Could not find WorkloadEntry for connecting proxy. No metadata was recorded.")
+		return nil, err
+	}
+	if we != nil {
+		return map[string]string{
+			"osm-stats-pod":       we.Name,
+			"osm-stats-namespace": we.Namespace,
+			"osm-stats-kind":      "WorkloadEntry",
+			"osm-stats-name":      we.Name,
+		}, nil
+	}
+
 	pod, err := c.getPodForProxy(p)
 	if err != nil {
 		log.Warn().Str("proxy", p.String()).Msg("Could not find pod for connecting proxy. No metadata was recorded.")
@@ -466,12 +591,48 @@ func (c *client) GetProxyStatsHeaders(p *models.Proxy) (map[string]string, error
 	}, nil
 }
 
-// VerifyProxy attempts to lookup a pod that matches the given proxy instance by service identity, namespace, and UUID.
+// VerifyProxy attempts to lookup a pod, or a WorkloadEntry for proxies of workloads outside of Kubernetes, that
+// matches the given proxy instance by service identity, namespace, and UUID.
 func (c *client) VerifyProxy(proxy *models.Proxy) error {
+	if we, err := c.getWorkloadEntryForProxy(proxy); we != nil || err != nil {
+		return err
+	}
 	_, err := c.getPodForProxy(proxy)
 	return err
 }
 
+// getWorkloadAnnotations returns the annotations of the pod or WorkloadEntry the given proxy runs on
+func (c *client) getWorkloadAnnotations(proxy *models.Proxy) (map[string]string, error) {
+	we, err := c.getWorkloadEntryForProxy(proxy)
+	if err != nil {
+		return nil, err
+	}
+	if we != nil {
+		return we.Annotations, nil
+	}
+	pod, err := c.getPodForProxy(proxy)
+	if err != nil {
+		return nil, err
+	}
+	return pod.Annotations, nil
+}
+
+// getWorkloadLabels returns the namespace and labels of the pod or WorkloadEntry the given proxy runs on
+func (c *client) getWorkloadLabels(proxy *models.Proxy) (string, map[string]string, error) {
+	we, err := c.getWorkloadEntryForProxy(proxy)
+	if err != nil {
+		return "", nil, err
+	}
+	if we != nil {
+		return we.Namespace, we.Spec.Labels, nil
+	}
+	pod, err := c.getPodForProxy(proxy)
+	if err != nil {
+		return "", nil, err
+	}
+	return pod.Namespace, pod.Labels, nil
+}
+
 // GetPodForProxy returns the pod that the given proxy is attached to, based on the UUID and service identity.
 func (c *client) getPodForProxy(proxy *models.Proxy) (*v1.Pod, error) {
 	proxyUUID, svcAccount := proxy.UUID.String(), proxy.Identity.ToK8sServiceAccount()
@@ -543,8 +704,8 @@ func (c *client) GetProxyConfig(proxy *models.Proxy, configType string, kubeConf
 // It returns the most specific match if multiple matching policies exist, in the following
 // order of preference: 1. selector match, 2. namespace match, 3. global match
 func (c *client) getTelemetryPolicy(proxy *models.Proxy) *policyv1alpha1.Telemetry {
-	pod, _ := c.getPodForProxy(proxy)
-	if pod == nil {
+	namespace, workloadLabels, err := c.getWorkloadLabels(proxy)
+	if err != nil {
 		return nil
 	}
 
@@ -562,7 +723,7 @@ func (c *client) getTelemetryPolicy(proxy *models.Proxy) *policyv1alpha1.Telemet
 		// consider this policy to be a candidate, but continue
 		// to look for a more specific policy that matches the pod
 		// based on a selector
-		if t.Namespace == pod.Namespace {
+		if t.Namespace == namespace {
 			policy = t
 		}
 
@@ -574,7 +735,7 @@ func (c *client) getTelemetryPolicy(proxy *models.Proxy) *policyv1alpha1.Telemet
 			continue
 		}
 		sel := labels.Set(selector).AsSelector()
-		if sel.Matches(labels.Set(pod.Labels)) {
+		if sel.Matches(labels.Set(workloadLabels)) {
 			return t
 		}
 	}
@@ -597,6 +758,67 @@ func (c *client) GetTelemetryConfig(proxy *models.Proxy) models.TelemetryConfig
 	return config
 }
 
+// GetSidecarConfig returns the effective sidecar configuration of the given proxy instance, resolved from the
+// MeshConfig and the SidecarProfile selecting its workload. The MeshConfig applies as is when the workload is not
+// found or the profile is invalid.
+func (c *client) GetSidecarConfig(proxy *models.Proxy) models.SidecarConfig {
+	namespace, workloadLabels, err := c.getWorkloadLabels(proxy)
+	if err != nil {
+		return models.SidecarConfig{Sidecar: c.kubeController.GetMeshConfig().Spec.Sidecar}
+	}
+
+	config, err := c.kubeController.GetSidecarConfig(namespace, workloadLabels)
+	if err != nil {
+		log.Error().Err(err).Str("proxy", proxy.String()).Msg("Error resolving the sidecar profile of proxy, using the MeshConfig")
+	}
+	return config
+}
+
+// ListStaleProxies returns the meshed pods whose Envoy sidecar differs from the sidecar the mesh currently injects,
+// sorted by namespace and name. Sidecars are compared with the sidecar configuration resolved for their pod, and with
+// the version of OSM of the controller.
+func (c *client) ListStaleProxies() []models.StaleProxy {
+	var stale []models.StaleProxy
+	for _, pod := range c.kubeController.ListPods() {
+		proxyUUID, ok := pod.Labels[constants.EnvoyUniqueIDLabelName]
+		if !ok {
+			continue
+		}
+
+		sidecarConfig, err := c.kubeController.GetSidecarConfig(pod.Namespace, pod.Labels)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error resolving the sidecar profile of pod %s/%s, using the MeshConfig", pod.Namespace, pod.Name)
+		}
+		meshConfig := c.kubeController.GetMeshConfig()
+		meshConfig.Spec.Sidecar = sidecarConfig.Sidecar
+		meshConfig.Spec.Sidecar.EnvoyImage = utils.GetEnvoyImage(meshConfig)
+		meshConfig.Spec.Sidecar.EnvoyWindowsImage = utils.GetEnvoyWindowsImage(meshConfig)
+
+		var bootstrapVersion string
+		if secret := c.kubeController.GetSecret(fmt.Sprintf("envoy-bootstrap-config-%s", proxyUUID), pod.Namespace); secret != nil {
+			bootstrapVersion = secret.Labels[constants.OSMAppVersionLabelKey]
+		}
+
+		reasons := k8s.GetStaleProxyReasons(pod, meshConfig.Spec.Sidecar, bootstrapVersion, version.Version)
+		if len(reasons) == 0 {
+			continue
+		}
+		stale = append(stale, models.StaleProxy{
+			Namespace: pod.Namespace,
+			Pod:       pod.Name,
+			Reasons:   reasons,
+		})
+	}
+
+	sort.Slice(stale, func(i, j int) bool {
+		if stale[i].Namespace != stale[j].Namespace {
+			return stale[i].Namespace < stale[j].Namespace
+		}
+		return stale[i].Pod < stale[j].Pod
+	})
+	return stale
+}
+
 // ListServiceIdentitiesForService lists ServiceAccounts associated with the given service
 func (c *client) ListServiceIdentitiesForService(name, namespace string) ([]identity.ServiceIdentity, error) {
 	var identities []identity.ServiceIdentity
@@ -624,6 +846,13 @@ func (c *client) ListServiceIdentitiesForService(name, namespace string) ([]iden
 		}
 	}
 
+	for _, we := range c.listWorkloadEntriesForService(k8sSvc) {
+		svcAccountsSet.Add(identity.K8sServiceAccount{
+			Name:      we.Spec.ServiceAccount,
+			Namespace: we.Namespace,
+		})
+	}
+
 	for svcAcc := range svcAccountsSet.Iter() {
 		identities = append(identities, svcAcc.(identity.K8sServiceAccount).ToServiceIdentity())
 	}
@@ -649,6 +878,7 @@ func (c *client) GetMeshService(name, namespace string, port uint16) (service.Me
 // MeshService objects per port.
 func (c *client) serviceToMeshServices(svc corev1.Service) []service.MeshService {
 	var meshServices []service.MeshService
+	slices := c.kubeController.ListEndpointSlicesForService(svc.Name, svc.Namespace)
 
 	for _, portSpec := range svc.Spec.Ports {
 		meshSvc := service.MeshService{
@@ -691,29 +921,40 @@ func (c *client) serviceToMeshServices(svc corev1.Service) []service.MeshService
 
 		// The endpoints for the kubernetes service carry information that allows
 		// us to retrieve the TargetPort for the MeshService.
-		endpoints, _ := c.kubeController.GetEndpoints(svc.Name, svc.Namespace)
-		if endpoints != nil {
-			meshSvc.TargetPort = GetTargetPortFromEndpoints(portSpec.Name, *endpoints)
+		if len(slices) > 0 {
+			meshSvc.TargetPort = GetTargetPortFromEndpointSlices(portSpec.Name, slices)
 		} else {
 			log.Warn().Msgf("k8s service %s/%s does not have endpoints but is being represented as a MeshService", svc.Namespace, svc.Name)
 		}
+		// Workloads outside of Kubernetes are not part of the service's EndpointSlices, so the TargetPort of services
+		// only backed by them is the port of their WorkloadEntries
+		if meshSvc.TargetPort == 0 {
+			if workloadEntries := c.listWorkloadEntriesForService(&svc); len(workloadEntries) > 0 {
+				meshSvc.TargetPort = getWorkloadEntryPort(workloadEntries[0], portSpec)
+			}
+		}
 
-		if !k8s.IsHeadlessService(svc) || endpoints == nil {
+		if !k8s.IsHeadlessService(svc) || len(slices) == 0 {
 			meshServices = append(meshServices, meshSvc)
 			continue
 		}
 		// If there's not at least 1 subdomain-ed MeshService added,
 		// add the entire headless service
 		var added bool
-		for _, subset := range endpoints.Subsets {
-			for _, address := range subset.Addresses {
-				if address.Hostname == "" {
+		hostnames := mapset.NewSet()
+		for _, slice := range slices {
+			for _, sliceEndpoint := range slice.Endpoints {
+				hostname := pointer.StringDeref(sliceEndpoint.Hostname, "")
+				if usable, _ := getEndpointConditions(sliceEndpoint.Conditions); hostname == "" || !usable {
+					continue
+				}
+				if !hostnames.Add(hostname) {
 					continue
 				}
 				meshServices = append(meshServices, service.MeshService{
 					Namespace:  svc.Namespace,
 					Name:       svc.Name,
-					Subdomain:  address.Hostname,
+					Subdomain:  hostname,
 					Port:       meshSvc.Port,
 					TargetPort: meshSvc.TargetPort,
 					Protocol:   meshSvc.Protocol,
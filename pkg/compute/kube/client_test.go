@@ -2,6 +2,7 @@ package kube
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"testing"
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	tassert "github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -35,6 +37,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/messaging"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/version"
 )
 
 var (
@@ -52,6 +55,7 @@ var _ = Describe("Test Kube client Provider (w/o kubecontroller)", func() {
 	mockKubeController = k8s.NewMockController(mockCtrl)
 
 	mockKubeController.EXPECT().IsMonitoredNamespace(tests.BookbuyerService.Namespace).Return(true).AnyTimes()
+	mockKubeController.EXPECT().ListWorkloadEntries().Return(nil).AnyTimes()
 
 	BeforeEach(func() {
 		c = NewClient(mockKubeController)
@@ -65,28 +69,28 @@ var _ = Describe("Test Kube client Provider (w/o kubecontroller)", func() {
 
 	It("should correctly return a list of endpoints for a service", func() {
 		// Should be empty for now
-		mockKubeController.EXPECT().GetEndpoints(meshSvc.Name, meshSvc.Namespace).Return(&corev1.Endpoints{
-			ObjectMeta: metav1.ObjectMeta{
-				Namespace: meshSvc.Namespace,
-			},
-			Subsets: []corev1.EndpointSubset{
-				{
-					Addresses: []corev1.EndpointAddress{
-						{
-							IP: "8.8.8.8",
-						},
+		mockKubeController.EXPECT().ListEndpointSlicesForService(meshSvc.Name, meshSvc.Namespace).Return([]*discoveryv1.EndpointSlice{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: meshSvc.Namespace,
+					Labels:    map[string]string{discoveryv1.LabelServiceName: meshSvc.Name},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Endpoints: []discoveryv1.Endpoint{
+					{
+						Addresses: []string{"8.8.8.8"},
 					},
-					Ports: []corev1.EndpointPort{
-						{
-							Port: int32(meshSvc.TargetPort), // Must match meshSvc.TargetPort
-						},
-						{
-							Port: 8888, // Does not match meshSvc.TargetPort, should be ignored
-						},
+				},
+				Ports: []discoveryv1.EndpointPort{
+					{
+						Port: pointer.Int32(int32(meshSvc.TargetPort)), // Must match meshSvc.TargetPort
+					},
+					{
+						Port: pointer.Int32(8888), // Does not match meshSvc.TargetPort, should be ignored
 					},
 				},
 			},
-		}, nil)
+		})
 
 		Expect(c.ListEndpointsForService(meshSvc)).To(Equal([]endpoint.Endpoint{
 			{
@@ -104,33 +108,33 @@ var _ = Describe("Test Kube client Provider (w/o kubecontroller)", func() {
 			TargetPort: 90,
 		}
 		// Should be empty for now
-		mockKubeController.EXPECT().GetEndpoints(subdomainedSvc.Name, subdomainedSvc.Namespace).Return(&corev1.Endpoints{
-			ObjectMeta: metav1.ObjectMeta{
-				Namespace: subdomainedSvc.Namespace,
-			},
-			Subsets: []corev1.EndpointSubset{
-				{
-					Addresses: []corev1.EndpointAddress{
-						{
-							IP:       "1.1.1.1",
-							Hostname: "subdomain-0",
-						},
-						{
-							IP:       "8.8.8.8",
-							Hostname: "subdomain-1",
-						},
+		mockKubeController.EXPECT().ListEndpointSlicesForService(subdomainedSvc.Name, subdomainedSvc.Namespace).Return([]*discoveryv1.EndpointSlice{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: subdomainedSvc.Namespace,
+					Labels:    map[string]string{discoveryv1.LabelServiceName: subdomainedSvc.Name},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Endpoints: []discoveryv1.Endpoint{
+					{
+						Addresses: []string{"1.1.1.1"},
+						Hostname:  pointer.String("subdomain-0"),
 					},
-					Ports: []corev1.EndpointPort{
-						{
-							Port: int32(subdomainedSvc.TargetPort), // Must match subdomainedSvc.TargetPort
-						},
-						{
-							Port: 8888, // Does not match subdomainedSvc.TargetPort, should be ignored
-						},
+					{
+						Addresses: []string{"8.8.8.8"},
+						Hostname:  pointer.String("subdomain-1"),
+					},
+				},
+				Ports: []discoveryv1.EndpointPort{
+					{
+						Port: pointer.Int32(int32(subdomainedSvc.TargetPort)), // Must match subdomainedSvc.TargetPort
+					},
+					{
+						Port: pointer.Int32(8888), // Does not match subdomainedSvc.TargetPort, should be ignored
 					},
 				},
 			},
-		}, nil)
+		})
 
 		Expect(c.ListEndpointsForService(subdomainedSvc)).To(Equal([]endpoint.Endpoint{
 			{
@@ -147,28 +151,28 @@ var _ = Describe("Test Kube client Provider (w/o kubecontroller)", func() {
 			// No TargetPort
 		}
 
-		mockKubeController.EXPECT().GetEndpoints(svc.Name, svc.Namespace).Return(&corev1.Endpoints{
-			ObjectMeta: metav1.ObjectMeta{
-				Namespace: svc.Namespace,
-			},
-			Subsets: []corev1.EndpointSubset{
-				{
-					Addresses: []corev1.EndpointAddress{
-						{
-							IP: "8.8.8.8",
-						},
+		mockKubeController.EXPECT().ListEndpointSlicesForService(svc.Name, svc.Namespace).Return([]*discoveryv1.EndpointSlice{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: svc.Namespace,
+					Labels:    map[string]string{discoveryv1.LabelServiceName: svc.Name},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Endpoints: []discoveryv1.Endpoint{
+					{
+						Addresses: []string{"8.8.8.8"},
 					},
-					Ports: []corev1.EndpointPort{
-						{
-							Port: 80,
-						},
-						{
-							Port: 90,
-						},
+				},
+				Ports: []discoveryv1.EndpointPort{
+					{
+						Port: pointer.Int32(80),
+					},
+					{
+						Port: pointer.Int32(90),
 					},
 				},
 			},
-		}, nil)
+		})
 
 		Expect(c.ListEndpointsForService(svc)).To(Equal([]endpoint.Endpoint{
 			{
@@ -182,6 +186,105 @@ var _ = Describe("Test Kube client Provider (w/o kubecontroller)", func() {
 		}))
 	})
 
+	It("should filter endpoints by their conditions and carry their topology across slices", func() {
+		ports := []discoveryv1.EndpointPort{
+			{
+				Port: pointer.Int32(int32(meshSvc.TargetPort)),
+			},
+		}
+		mockKubeController.EXPECT().ListEndpointSlicesForService(meshSvc.Name, meshSvc.Namespace).Return([]*discoveryv1.EndpointSlice{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-1",
+					Namespace: meshSvc.Namespace,
+					Labels:    map[string]string{discoveryv1.LabelServiceName: meshSvc.Name},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Endpoints: []discoveryv1.Endpoint{
+					{
+						// Ready, with topology
+						Addresses: []string{"1.1.1.1"},
+						Zone:      pointer.String("zone-a"),
+						Hints: &discoveryv1.EndpointHints{
+							ForZones: []discoveryv1.ForZone{{Name: "zone-a"}},
+						},
+					},
+					{
+						// Not ready, should be ignored
+						Addresses:  []string{"2.2.2.2"},
+						Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(false)},
+					},
+					{
+						// Terminating but still serving, should be drained
+						Addresses: []string{"3.3.3.3"},
+						Conditions: discoveryv1.EndpointConditions{
+							Ready:       pointer.Bool(false),
+							Serving:     pointer.Bool(true),
+							Terminating: pointer.Bool(true),
+						},
+					},
+					{
+						// Terminating and no longer serving, should be ignored
+						Addresses: []string{"4.4.4.4"},
+						Conditions: discoveryv1.EndpointConditions{
+							Ready:       pointer.Bool(false),
+							Serving:     pointer.Bool(false),
+							Terminating: pointer.Bool(true),
+						},
+					},
+				},
+				Ports: ports,
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-2",
+					Namespace: meshSvc.Namespace,
+					Labels:    map[string]string{discoveryv1.LabelServiceName: meshSvc.Name},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Endpoints: []discoveryv1.Endpoint{
+					{
+						// Being moved from test-1, should not be duplicated
+						Addresses: []string{"1.1.1.1"},
+						Zone:      pointer.String("zone-a"),
+						Hints: &discoveryv1.EndpointHints{
+							ForZones: []discoveryv1.ForZone{{Name: "zone-a"}},
+						},
+					},
+				},
+				Ports: ports,
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-3",
+					Namespace: meshSvc.Namespace,
+					Labels:    map[string]string{discoveryv1.LabelServiceName: meshSvc.Name},
+				},
+				AddressType: discoveryv1.AddressTypeFQDN,
+				Endpoints: []discoveryv1.Endpoint{
+					{
+						Addresses: []string{"foo.example.com"},
+					},
+				},
+				Ports: ports,
+			},
+		})
+
+		Expect(c.ListEndpointsForService(meshSvc)).To(Equal([]endpoint.Endpoint{
+			{
+				IP:        net.ParseIP("1.1.1.1"),
+				Port:      endpoint.Port(meshSvc.TargetPort),
+				Zone:      "zone-a",
+				ZoneHints: []string{"zone-a"},
+			},
+			{
+				IP:          net.ParseIP("3.3.3.3"),
+				Port:        endpoint.Port(meshSvc.TargetPort),
+				Terminating: true,
+			},
+		}))
+	})
+
 	It("GetResolvableEndpoints should properly return endpoints based on ClusterIP when set", func() {
 		// If the service has cluster IP, expect the cluster IP + port
 		mockKubeController.EXPECT().GetService(tests.BookbuyerService.Name, tests.BookbuyerService.Namespace).Return(&corev1.Service{
@@ -210,6 +313,35 @@ var _ = Describe("Test Kube client Provider (w/o kubecontroller)", func() {
 		}))
 	})
 
+	It("GetResolvableEndpoints should properly return endpoints based on the ClusterIPs of both families of a dual-stack service", func() {
+		mockKubeController.EXPECT().GetService(tests.BookbuyerService.Name, tests.BookbuyerService.Namespace).Return(&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      tests.BookbuyerService.Name,
+				Namespace: tests.BookbuyerService.Namespace,
+			},
+			Spec: corev1.ServiceSpec{
+				ClusterIP:  "192.168.0.1",
+				ClusterIPs: []string{"192.168.0.1", "fd00::1"},
+				Ports: []corev1.ServicePort{{
+					Name:     "servicePort",
+					Protocol: corev1.ProtocolTCP,
+					Port:     tests.ServicePort,
+				}},
+			},
+		})
+
+		Expect(c.GetResolvableEndpointsForService(tests.BookbuyerService)).To(Equal([]endpoint.Endpoint{
+			{
+				IP:   net.IPv4(192, 168, 0, 1),
+				Port: tests.ServicePort,
+			},
+			{
+				IP:   net.ParseIP("fd00::1"),
+				Port: tests.ServicePort,
+			},
+		}))
+	})
+
 	It("GetResolvableEndpoints should properly return actual endpoints without ClusterIP when ClusterIP is not set", func() {
 		// Expect the individual pod endpoints, when no cluster IP is assigned to the service
 		mockKubeController.EXPECT().GetService(meshSvc.Name, meshSvc.Namespace).Return(&corev1.Service{
@@ -229,27 +361,26 @@ var _ = Describe("Test Kube client Provider (w/o kubecontroller)", func() {
 			},
 		})
 
-		mockKubeController.EXPECT().GetEndpoints(meshSvc.Name, meshSvc.Namespace).Return(&corev1.Endpoints{
-			ObjectMeta: metav1.ObjectMeta{
-				Namespace: meshSvc.Namespace,
-			},
-			Subsets: []corev1.EndpointSubset{
-				{
-					Addresses: []corev1.EndpointAddress{
-						{
-							IP: "8.8.8.8",
-						},
+		mockKubeController.EXPECT().ListEndpointSlicesForService(meshSvc.Name, meshSvc.Namespace).Return([]*discoveryv1.EndpointSlice{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: meshSvc.Namespace,
+					Labels:    map[string]string{discoveryv1.LabelServiceName: meshSvc.Name},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Endpoints: []discoveryv1.Endpoint{
+					{
+						Addresses: []string{"8.8.8.8"},
 					},
-					Ports: []corev1.EndpointPort{
-						{
-							Name:     "port",
-							Port:     int32(meshSvc.TargetPort),
-							Protocol: corev1.ProtocolTCP,
-						},
+				},
+				Ports: []discoveryv1.EndpointPort{
+					{
+						Name: pointer.String("port"),
+						Port: pointer.Int32(int32(meshSvc.TargetPort)),
 					},
 				},
 			},
-		}, nil)
+		})
 
 		Expect(c.GetResolvableEndpointsForService(meshSvc)).To(Equal([]endpoint.Endpoint{
 			{
@@ -281,27 +412,26 @@ var _ = Describe("Test Kube client Provider (w/o kubecontroller)", func() {
 			},
 		})
 
-		mockKubeController.EXPECT().GetEndpoints(meshSvc.Name, meshSvc.Namespace).Return(&corev1.Endpoints{
-			ObjectMeta: metav1.ObjectMeta{
-				Namespace: meshSvc.Namespace,
-			},
-			Subsets: []corev1.EndpointSubset{
-				{
-					Addresses: []corev1.EndpointAddress{
-						{
-							IP: "8.8.8.8",
-						},
+		mockKubeController.EXPECT().ListEndpointSlicesForService(meshSvc.Name, meshSvc.Namespace).Return([]*discoveryv1.EndpointSlice{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: meshSvc.Namespace,
+					Labels:    map[string]string{discoveryv1.LabelServiceName: meshSvc.Name},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Endpoints: []discoveryv1.Endpoint{
+					{
+						Addresses: []string{"8.8.8.8"},
 					},
-					Ports: []corev1.EndpointPort{
-						{
-							Name:     "port",
-							Port:     int32(meshSvc.TargetPort),
-							Protocol: corev1.ProtocolTCP,
-						},
+				},
+				Ports: []discoveryv1.EndpointPort{
+					{
+						Name: pointer.String("port"),
+						Port: pointer.Int32(int32(meshSvc.TargetPort)),
 					},
 				},
 			},
-		}, nil)
+		})
 
 		Expect(c.GetResolvableEndpointsForService(meshSvc)).To(Equal([]endpoint.Endpoint{
 			{
@@ -362,6 +492,7 @@ func TestListEndpointsForIdentity(t *testing.T) {
 			defer mockCtrl.Finish()
 
 			mockKubeController := k8s.NewMockController(mockCtrl)
+			mockKubeController.EXPECT().ListWorkloadEntries().Return(nil).AnyTimes()
 
 			provider := NewClient(mockKubeController)
 
@@ -645,6 +776,7 @@ func TestIsMetricsEnabled(t *testing.T) {
 			k := k8s.NewMockController(mockCtrl)
 			podArr := []*corev1.Pod{tc.pod}
 			k.EXPECT().ListPods().Return(podArr).AnyTimes()
+			k.EXPECT().ListWorkloadEntries().Return(nil).AnyTimes()
 			c := NewClient(k)
 
 			actual, err := c.IsMetricsEnabled(&models.Proxy{UUID: proxyUUID, Identity: tests.BookstoreServiceIdentity})
@@ -746,6 +878,78 @@ func TestGetPodForProxy(t *testing.T) {
 	}
 }
 
+func TestGetZoneForProxy(t *testing.T) {
+	assert := tassert.New(t)
+	stop := make(chan struct{})
+	defer close(stop)
+
+	proxyUUID := uuid.New()
+	namespace := tests.BookstoreServiceAccount.Namespace
+
+	podlabels := map[string]string{
+		constants.AppLabel:               tests.SelectorValue,
+		constants.EnvoyUniqueIDLabelName: proxyUUID.String(),
+	}
+	pod := tests.NewPodFixture(namespace, "pod-1", tests.BookstoreServiceAccountName, podlabels)
+	svc := tests.NewServiceFixture(tests.BookstoreV1ServiceName, namespace, map[string]string{constants.AppLabel: tests.SelectorValue})
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      svc.Name + "-1",
+			Namespace: namespace,
+			Labels:    map[string]string{discoveryv1.LabelServiceName: svc.Name},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints: []discoveryv1.Endpoint{
+			{
+				Addresses: []string{"8.8.8.8"},
+				Zone:      pointer.String("zone-b"),
+				TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: namespace, Name: "pod-0"},
+			},
+			{
+				Addresses: []string{"9.9.9.9"},
+				Zone:      pointer.String("zone-a"),
+				TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: namespace, Name: pod.Name},
+			},
+		},
+	}
+
+	kubeClient := fake.NewSimpleClientset(monitoredNS(namespace), pod, svc, slice)
+	broker := messaging.NewBroker(stop)
+	k8sClient, err := k8s.NewClient(tests.OsmNamespace, tests.OsmMeshConfigName, broker, k8s.WithKubeClient(kubeClient, testMeshName))
+	assert.NoError(err)
+
+	c := NewClient(k8sClient)
+	assert.Equal("zone-a", c.GetZoneForProxy(models.NewProxy(models.KindSidecar, proxyUUID, tests.BookstoreServiceIdentity, nil, 1)))
+	// The zone of an unknown proxy isn't known
+	assert.Empty(c.GetZoneForProxy(models.NewProxy(models.KindSidecar, uuid.New(), tests.BookstoreServiceIdentity, nil, 1)))
+}
+
+func TestListIPsForProxy(t *testing.T) {
+	assert := tassert.New(t)
+	stop := make(chan struct{})
+	defer close(stop)
+
+	proxyUUID := uuid.New()
+	namespace := tests.BookstoreServiceAccount.Namespace
+
+	pod := tests.NewPodFixture(namespace, "pod-1", tests.BookstoreServiceAccountName, map[string]string{
+		constants.EnvoyUniqueIDLabelName: proxyUUID.String(),
+	})
+	pod.Status.PodIP = "10.0.0.1"
+	pod.Status.PodIPs = []corev1.PodIP{{IP: "10.0.0.1"}, {IP: "fd00::1"}}
+
+	kubeClient := fake.NewSimpleClientset(monitoredNS(namespace), pod)
+	broker := messaging.NewBroker(stop)
+	k8sClient, err := k8s.NewClient(tests.OsmNamespace, tests.OsmMeshConfigName, broker, k8s.WithKubeClient(kubeClient, testMeshName))
+	assert.NoError(err)
+
+	c := NewClient(k8sClient)
+	assert.Equal([]net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("fd00::1")},
+		c.ListIPsForProxy(models.NewProxy(models.KindSidecar, proxyUUID, tests.BookstoreServiceIdentity, nil, 1)))
+	// The IP addresses of an unknown proxy aren't known
+	assert.Empty(c.ListIPsForProxy(models.NewProxy(models.KindSidecar, uuid.New(), tests.BookstoreServiceIdentity, nil, 1)))
+}
+
 func TestGetTelemetryConfig(t *testing.T) {
 	proxyUUID := uuid.New()
 	appNamespace := "test"
@@ -901,6 +1105,7 @@ func TestGetTelemetryConfig(t *testing.T) {
 			podArr := []*corev1.Pod{tc.pod}
 			k.EXPECT().ListPods().Return(podArr).AnyTimes()
 			k.EXPECT().ListTelemetryPolicies().Return(tc.telemetryPolicies).AnyTimes()
+			k.EXPECT().ListWorkloadEntries().Return(nil).AnyTimes()
 			k.EXPECT().GetOSMNamespace().Return(osmNamespace).AnyTimes()
 			k.EXPECT().GetExtensionService(otelPolicy.Spec.AccessLog.OpenTelemetry.ExtensionService).Return(otelExtSvc).AnyTimes()
 			c := NewClient(k)
@@ -911,6 +1116,138 @@ func TestGetTelemetryConfig(t *testing.T) {
 	}
 }
 
+func TestGetSidecarConfig(t *testing.T) {
+	proxyUUID := uuid.New()
+	meshConfig := configv1alpha2.MeshConfig{
+		Spec: configv1alpha2.MeshConfigSpec{
+			Sidecar: configv1alpha2.SidecarSpec{
+				LogLevel: "error",
+			},
+		},
+	}
+	podLabels := map[string]string{
+		constants.EnvoyUniqueIDLabelName: proxyUUID.String(),
+		"app":                            "foo",
+	}
+
+	testCases := []struct {
+		name       string
+		proxy      *models.Proxy
+		resolveErr error
+		expected   models.SidecarConfig
+	}{
+		{
+			name:  "resolves the sidecar config of the proxy's pod",
+			proxy: models.NewProxy(models.KindSidecar, proxyUUID, "sa-1.test", nil, 1),
+			expected: models.SidecarConfig{
+				Sidecar:     configv1alpha2.SidecarSpec{LogLevel: "debug"},
+				Concurrency: 2,
+			},
+		},
+		{
+			name:       "uses the MeshConfig when the profile is invalid",
+			proxy:      models.NewProxy(models.KindSidecar, proxyUUID, "sa-1.test", nil, 1),
+			resolveErr: errors.New("invalid"),
+			expected:   models.SidecarConfig{Sidecar: meshConfig.Spec.Sidecar},
+		},
+		{
+			name:     "uses the MeshConfig when the pod is not found",
+			proxy:    models.NewProxy(models.KindSidecar, uuid.New(), "sa-1.test", nil, 1),
+			expected: models.SidecarConfig{Sidecar: meshConfig.Spec.Sidecar},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+
+			mockCtrl := gomock.NewController(t)
+			k := k8s.NewMockController(mockCtrl)
+			k.EXPECT().ListPods().Return([]*corev1.Pod{tests.NewPodFixture("test", "pod-1", "sa-1", podLabels)}).AnyTimes()
+			k.EXPECT().ListWorkloadEntries().Return(nil).AnyTimes()
+			k.EXPECT().GetMeshConfig().Return(meshConfig).AnyTimes()
+			if tc.resolveErr != nil {
+				k.EXPECT().GetSidecarConfig("test", podLabels).Return(models.SidecarConfig{Sidecar: meshConfig.Spec.Sidecar}, tc.resolveErr).AnyTimes()
+			} else {
+				k.EXPECT().GetSidecarConfig("test", podLabels).Return(tc.expected, nil).AnyTimes()
+			}
+			c := NewClient(k)
+
+			a.Equal(tc.expected, c.GetSidecarConfig(tc.proxy))
+		})
+	}
+}
+
+func TestListStaleProxies(t *testing.T) {
+	a := assert.New(t)
+
+	currentVersion := version.Version
+	version.Version = "v1.3.0"
+	defer func() {
+		version.Version = currentVersion
+	}()
+
+	newPod := func(name, envoyImage, injectorVersion string) *corev1.Pod {
+		pod := tests.NewPodFixture("test", name, "sa-1", map[string]string{
+			constants.EnvoyUniqueIDLabelName: name,
+			"app":                            name,
+		})
+		pod.Annotations = map[string]string{constants.InjectorVersionAnnotation: injectorVersion}
+		pod.Spec.Containers = []corev1.Container{
+			{Name: name, Image: name},
+			{Name: constants.EnvoyContainerName, Image: envoyImage},
+		}
+		return pod
+	}
+	upToDate := newPod("up-to-date", "envoy:v2", "v1.3.0")
+	oldImage := newPod("old-image", "envoy:v1", "v1.3.0")
+	oldInjector := newPod("old-injector", "envoy:v2", "v1.2.0")
+	profileImage := newPod("profile-image", "envoy:v3", "v1.3.0")
+	notMeshed := tests.NewPodFixture("test", "not-meshed", "sa-1", nil)
+
+	meshConfig := configv1alpha2.MeshConfig{
+		Spec: configv1alpha2.MeshConfigSpec{
+			Sidecar: configv1alpha2.SidecarSpec{EnvoyImage: "envoy:v2"},
+		},
+	}
+
+	mockCtrl := gomock.NewController(t)
+	k := k8s.NewMockController(mockCtrl)
+	k.EXPECT().ListPods().Return([]*corev1.Pod{upToDate, oldImage, oldInjector, profileImage, notMeshed})
+	k.EXPECT().GetMeshConfig().Return(meshConfig).AnyTimes()
+	k.EXPECT().GetSidecarConfig("test", gomock.Any()).DoAndReturn(func(_ string, podLabels map[string]string) (models.SidecarConfig, error) {
+		if podLabels["app"] == "profile-image" {
+			return models.SidecarConfig{Sidecar: configv1alpha2.SidecarSpec{EnvoyImage: "envoy:v3"}}, nil
+		}
+		return models.SidecarConfig{Sidecar: meshConfig.Spec.Sidecar}, nil
+	}).AnyTimes()
+	k.EXPECT().GetSecret(gomock.Any(), "test").DoAndReturn(func(name, namespace string) *models.Secret {
+		bootstrapVersion := "v1.3.0"
+		if name == "envoy-bootstrap-config-old-injector" {
+			bootstrapVersion = "v1.2.0"
+		}
+		return &models.Secret{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{constants.OSMAppVersionLabelKey: bootstrapVersion},
+		}
+	}).AnyTimes()
+	c := NewClient(k)
+
+	a.Equal([]models.StaleProxy{
+		{
+			Namespace: "test",
+			Pod:       "old-image",
+			Reasons:   []models.StaleProxyReason{models.StaleProxyReasonEnvoyImage},
+		},
+		{
+			Namespace: "test",
+			Pod:       "old-injector",
+			Reasons:   []models.StaleProxyReason{models.StaleProxyReasonBootstrapVersion, models.StaleProxyReasonInjectorVersion},
+		},
+	}, c.ListStaleProxies())
+}
+
 func TestListServicesForProxy(t *testing.T) {
 	goodUUID := uuid.New()
 	badUUID := uuid.New()
@@ -2196,6 +2533,7 @@ func TestListServiceIdentitiesForService(t *testing.T) {
 			mockCtrl := gomock.NewController(t)
 			controller := k8s.NewMockController(mockCtrl)
 			controller.EXPECT().ListPods().Return(tc.pods).AnyTimes()
+			controller.EXPECT().ListWorkloadEntries().Return(nil).AnyTimes()
 			if tc.svc.Name == tc.service.Name && tc.svc.Namespace == tc.service.Namespace {
 				controller.EXPECT().GetService(tc.svc.Name, tc.svc.Namespace).Return(tc.service).AnyTimes()
 			} else {
@@ -2238,20 +2576,17 @@ func TestServiceToMeshServices(t *testing.T) {
 				},
 			},
 			svcEndpoints: []runtime.Object{
-				&corev1.Endpoints{
+				&discoveryv1.EndpointSlice{
 					ObjectMeta: metav1.ObjectMeta{
-						// Should match svc.Name and svc.Namespace
 						Namespace: "ns1",
-						Name:      "s1",
+						Name:      "s1-1",
+						Labels:    map[string]string{discoveryv1.LabelServiceName: "s1"},
 					},
-					Subsets: []corev1.EndpointSubset{
+					AddressType: discoveryv1.AddressTypeIPv4,
+					Ports: []discoveryv1.EndpointPort{
 						{
-							Ports: []corev1.EndpointPort{
-								{
-									// Must match the port of 'svc.Spec.Ports[0]'
-									Port: 8080, // TargetPort
-								},
-							},
+							// Must match the port of 'svc.Spec.Ports[0]'
+							Port: pointer.Int32(8080), // TargetPort
 						},
 					},
 				},
@@ -2287,20 +2622,17 @@ func TestServiceToMeshServices(t *testing.T) {
 				},
 			},
 			svcEndpoints: []runtime.Object{
-				&corev1.Endpoints{
+				&discoveryv1.EndpointSlice{
 					ObjectMeta: metav1.ObjectMeta{
-						// Should match svc.Name and svc.Namespace
 						Namespace: "ns1",
-						Name:      "s1",
+						Name:      "s1-1",
+						Labels:    map[string]string{discoveryv1.LabelServiceName: "s1"},
 					},
-					Subsets: []corev1.EndpointSubset{
+					AddressType: discoveryv1.AddressTypeIPv4,
+					Ports: []discoveryv1.EndpointPort{
 						{
-							Ports: []corev1.EndpointPort{
-								{
-									// Must match the port of 'svc.Spec.Ports[0]'
-									Port: 8080, // TargetPort
-								},
-							},
+							// Must match the port of 'svc.Spec.Ports[0]'
+							Port: pointer.Int32(8080), // TargetPort
 						},
 					},
 				},
@@ -2337,26 +2669,23 @@ func TestServiceToMeshServices(t *testing.T) {
 				},
 			},
 			svcEndpoints: []runtime.Object{
-				&corev1.Endpoints{
+				&discoveryv1.EndpointSlice{
 					ObjectMeta: metav1.ObjectMeta{
-						// Should match svc.Name and svc.Namespace
 						Namespace: "ns1",
-						Name:      "s1",
+						Name:      "s1-1",
+						Labels:    map[string]string{discoveryv1.LabelServiceName: "s1"},
 					},
-					Subsets: []corev1.EndpointSubset{
+					AddressType: discoveryv1.AddressTypeIPv4,
+					Endpoints: []discoveryv1.Endpoint{
 						{
-							Addresses: []corev1.EndpointAddress{
-								{
-									IP:       "10.1.0.1",
-									Hostname: "pod-0",
-								},
-							},
-							Ports: []corev1.EndpointPort{
-								{
-									// Must match the port of 'svc.Spec.Ports[0]'
-									Port: 8080, // TargetPort
-								},
-							},
+							Addresses: []string{"10.1.0.1"},
+							Hostname:  pointer.String("pod-0"),
+						},
+					},
+					Ports: []discoveryv1.EndpointPort{
+						{
+							// Must match the port of 'svc.Spec.Ports[0]'
+							Port: pointer.Int32(8080), // TargetPort
 						},
 					},
 				},
@@ -2394,25 +2723,22 @@ func TestServiceToMeshServices(t *testing.T) {
 				},
 			},
 			svcEndpoints: []runtime.Object{
-				&corev1.Endpoints{
+				&discoveryv1.EndpointSlice{
 					ObjectMeta: metav1.ObjectMeta{
-						// Should match svc.Name and svc.Namespace
 						Namespace: "ns1",
-						Name:      "s1",
+						Name:      "s1-1",
+						Labels:    map[string]string{discoveryv1.LabelServiceName: "s1"},
 					},
-					Subsets: []corev1.EndpointSubset{
+					AddressType: discoveryv1.AddressTypeIPv4,
+					Endpoints: []discoveryv1.Endpoint{
 						{
-							Addresses: []corev1.EndpointAddress{
-								{
-									IP: "10.1.0.1",
-								},
-							},
-							Ports: []corev1.EndpointPort{
-								{
-									// Must match the port of 'svc.Spec.Ports[0]'
-									Port: 8080, // TargetPort
-								},
-							},
+							Addresses: []string{"10.1.0.1"},
+						},
+					},
+					Ports: []discoveryv1.EndpointPort{
+						{
+							// Must match the port of 'svc.Spec.Ports[0]'
+							Port: pointer.Int32(8080), // TargetPort
 						},
 					},
 				},
@@ -2451,28 +2777,23 @@ func TestServiceToMeshServices(t *testing.T) {
 				},
 			},
 			svcEndpoints: []runtime.Object{
-				&corev1.Endpoints{
+				&discoveryv1.EndpointSlice{
 					ObjectMeta: metav1.ObjectMeta{
-						// Should match svc.Name and svc.Namespace
 						Namespace: "ns1",
-						Name:      "s1",
+						Name:      "s1-1",
+						Labels:    map[string]string{discoveryv1.LabelServiceName: "s1"},
 					},
-					Subsets: []corev1.EndpointSubset{
+					AddressType: discoveryv1.AddressTypeIPv4,
+					Ports: []discoveryv1.EndpointPort{
 						{
-							Ports: []corev1.EndpointPort{
-								{
-									// Must match the port of 'svc.Spec.Ports[0]'
-									Name:        "p1",
-									Port:        8080, // TargetPort
-									AppProtocol: pointer.StringPtr("http"),
-								},
-								{
-									// Must match the port of 'svc.Spec.Ports[1]'
-									Name:        "p2",
-									Port:        9090, // TargetPort
-									AppProtocol: pointer.StringPtr("tcp"),
-								},
-							},
+							// Must match the port of 'svc.Spec.Ports[0]'
+							Name: pointer.String("p1"),
+							Port: pointer.Int32(8080), // TargetPort
+						},
+						{
+							// Must match the port of 'svc.Spec.Ports[1]'
+							Name: pointer.String("p2"),
+							Port: pointer.Int32(9090), // TargetPort
 						},
 					},
 				},
@@ -2518,34 +2839,29 @@ func TestServiceToMeshServices(t *testing.T) {
 				},
 			},
 			svcEndpoints: []runtime.Object{
-				&corev1.Endpoints{
+				&discoveryv1.EndpointSlice{
 					ObjectMeta: metav1.ObjectMeta{
-						// Should match svc.Name and svc.Namespace
 						Namespace: "ns1",
-						Name:      "s1",
+						Name:      "s1-1",
+						Labels:    map[string]string{discoveryv1.LabelServiceName: "s1"},
 					},
-					Subsets: []corev1.EndpointSubset{
+					AddressType: discoveryv1.AddressTypeIPv4,
+					Endpoints: []discoveryv1.Endpoint{
 						{
-							Addresses: []corev1.EndpointAddress{
-								{
-									IP:       "10.1.0.1",
-									Hostname: "pod-0",
-								},
-							},
-							Ports: []corev1.EndpointPort{
-								{
-									// Must match the port of 'svc.Spec.Ports[0]'
-									Name:        "p1",
-									Port:        8080, // TargetPort
-									AppProtocol: pointer.StringPtr("http"),
-								},
-								{
-									// Must match the port of 'svc.Spec.Ports[1]'
-									Name:        "p2",
-									Port:        9090, // TargetPort
-									AppProtocol: pointer.StringPtr("tcp"),
-								},
-							},
+							Addresses: []string{"10.1.0.1"},
+							Hostname:  pointer.String("pod-0"),
+						},
+					},
+					Ports: []discoveryv1.EndpointPort{
+						{
+							// Must match the port of 'svc.Spec.Ports[0]'
+							Name: pointer.String("p1"),
+							Port: pointer.Int32(8080), // TargetPort
+						},
+						{
+							// Must match the port of 'svc.Spec.Ports[1]'
+							Name: pointer.String("p2"),
+							Port: pointer.Int32(9090), // TargetPort
 						},
 					},
 				},
@@ -2593,33 +2909,28 @@ func TestServiceToMeshServices(t *testing.T) {
 				},
 			},
 			svcEndpoints: []runtime.Object{
-				&corev1.Endpoints{
+				&discoveryv1.EndpointSlice{
 					ObjectMeta: metav1.ObjectMeta{
-						// Should match svc.Name and svc.Namespace
 						Namespace: "ns1",
-						Name:      "s1",
+						Name:      "s1-1",
+						Labels:    map[string]string{discoveryv1.LabelServiceName: "s1"},
 					},
-					Subsets: []corev1.EndpointSubset{
+					AddressType: discoveryv1.AddressTypeIPv4,
+					Endpoints: []discoveryv1.Endpoint{
 						{
-							Addresses: []corev1.EndpointAddress{
-								{
-									IP: "10.1.0.1",
-								},
-							},
-							Ports: []corev1.EndpointPort{
-								{
-									// Must match the port of 'svc.Spec.Ports[0]'
-									Name:        "p1",
-									Port:        8080, // TargetPort
-									AppProtocol: pointer.StringPtr("http"),
-								},
-								{
-									// Must match the port of 'svc.Spec.Ports[1]'
-									Name:        "p2",
-									Port:        9090, // TargetPort
-									AppProtocol: pointer.StringPtr("tcp"),
-								},
-							},
+							Addresses: []string{"10.1.0.1"},
+						},
+					},
+					Ports: []discoveryv1.EndpointPort{
+						{
+							// Must match the port of 'svc.Spec.Ports[0]'
+							Name: pointer.String("p1"),
+							Port: pointer.Int32(8080), // TargetPort
+						},
+						{
+							// Must match the port of 'svc.Spec.Ports[1]'
+							Name: pointer.String("p2"),
+							Port: pointer.Int32(9090), // TargetPort
 						},
 					},
 				},
@@ -2665,26 +2976,23 @@ func TestServiceToMeshServices(t *testing.T) {
 				},
 			},
 			svcEndpoints: []runtime.Object{
-				&corev1.Endpoints{
+				&discoveryv1.EndpointSlice{
 					ObjectMeta: metav1.ObjectMeta{
-						// Should match svc.Name and svc.Namespace
 						Namespace: "ns1",
-						Name:      "s1",
+						Name:      "s1-1",
+						Labels:    map[string]string{discoveryv1.LabelServiceName: "s1"},
 					},
-					Subsets: []corev1.EndpointSubset{
+					AddressType: discoveryv1.AddressTypeIPv4,
+					Ports: []discoveryv1.EndpointPort{
 						{
-							Ports: []corev1.EndpointPort{
-								{
-									// Must match the port of 'svc.Spec.Ports[0]'
-									Name: "p1",
-									Port: 8080, // TargetPort
-								},
-								{
-									// Must match the port of 'svc.Spec.Ports[1]'
-									Name: "p2",
-									Port: 8080, // TargetPort
-								},
-							},
+							// Must match the port of 'svc.Spec.Ports[0]'
+							Name: pointer.String("p1"),
+							Port: pointer.Int32(8080), // TargetPort
+						},
+						{
+							// Must match the port of 'svc.Spec.Ports[1]'
+							Name: pointer.String("p2"),
+							Port: pointer.Int32(8080), // TargetPort
 						},
 					},
 				},
@@ -2731,7 +3039,7 @@ func TestGetMeshService(t *testing.T) {
 	testCases := []struct {
 		name               string
 		svc                *corev1.Service
-		endpoints          *corev1.Endpoints
+		endpoints          *discoveryv1.EndpointSlice
 		namespacedSvc      types.NamespacedName
 		port               uint16
 		expectedTargetPort uint16
@@ -2752,19 +3060,17 @@ func TestGetMeshService(t *testing.T) {
 					}},
 				},
 			},
-			endpoints: &corev1.Endpoints{
+			endpoints: &discoveryv1.EndpointSlice{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      "s1",
 					Namespace: "ns1",
+					Name:      "s1-1",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "s1"},
 				},
-				Subsets: []corev1.EndpointSubset{
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports: []discoveryv1.EndpointPort{
 					{
-						Ports: []corev1.EndpointPort{
-							{
-								Name: "p1",
-								Port: 8080,
-							},
-						},
+						Name: pointer.String("p1"),
+						Port: pointer.Int32(8080),
 					},
 				},
 			},
@@ -2787,19 +3093,17 @@ func TestGetMeshService(t *testing.T) {
 					}},
 				},
 			},
-			endpoints: &corev1.Endpoints{
+			endpoints: &discoveryv1.EndpointSlice{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      "s1",
 					Namespace: "ns1",
+					Name:      "s1-1",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "s1"},
 				},
-				Subsets: []corev1.EndpointSubset{
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports: []discoveryv1.EndpointPort{
 					{
-						Ports: []corev1.EndpointPort{
-							{
-								Name: "p1",
-								Port: 8080,
-							},
-						},
+						Name: pointer.String("p1"),
+						Port: pointer.Int32(8080),
 					},
 				},
 			},
@@ -2823,23 +3127,21 @@ func TestGetMeshService(t *testing.T) {
 					ClusterIP: corev1.ClusterIPNone,
 				},
 			},
-			endpoints: &corev1.Endpoints{
+			endpoints: &discoveryv1.EndpointSlice{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      "s1",
 					Namespace: "ns1",
+					Name:      "s1-1",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "s1"},
 				},
-				Subsets: []corev1.EndpointSubset{
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports: []discoveryv1.EndpointPort{
 					{
-						Ports: []corev1.EndpointPort{
-							{
-								Name: "invalid", // does not match svc port
-								Port: 8080,
-							},
-							{
-								Name: "invalid2",
-								Port: 8081, // also does not match svc port; having 2 ports triggers the name filter logic
-							},
-						},
+						Name: pointer.String("invalid"), // does not match svc port
+						Port: pointer.Int32(8080),
+					},
+					{
+						Name: pointer.String("invalid2"),
+						Port: pointer.Int32(8081), // also does not match svc port; having 2 ports triggers the name filter logic
 					},
 				},
 			},
@@ -1,37 +1,68 @@
 package kube
 
 import (
-	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/utils/pointer"
 )
 
-// GetTargetPortFromEndpoints returns the endpoint port corresponding to the given endpoint name and endpoints
+// GetTargetPortFromEndpointSlices returns the endpoint port corresponding to the given endpoint name and endpoint slices
 // TODO(4863): unexport this method, it should not be used outside of this package.
-func GetTargetPortFromEndpoints(endpointName string, endpoints corev1.Endpoints) (endpointPort uint16) {
+func GetTargetPortFromEndpointSlices(endpointName string, slices []*discoveryv1.EndpointSlice) (endpointPort uint16) {
 	// Per https://pkg.go.dev/k8s.io/api/core/v1#ServicePort and
-	// https://pkg.go.dev/k8s.io/api/core/v1#EndpointPort, if a service has multiple
+	// https://pkg.go.dev/k8s.io/api/discovery/v1#EndpointPort, if a service has multiple
 	// ports, then ServicePort.Name must match EndpointPort.Name when considering
 	// matching endpoints for the service's port. ServicePort.Name and EndpointPort.Name
 	// can be unset when the service has a single port exposed, in which case we are
-	// guaranteed to have the same port specified in the list of EndpointPort.Subsets.
+	// guaranteed to have the same port specified in the list of EndpointSlice.Ports.
 	//
 	// The logic below works as follows:
 	// If the service has multiple ports, retrieve the matching endpoint port using
 	// the given ServicePort.Name specified by `endpointName`.
-	// Otherwise, simply return the only port referenced in EndpointPort.Subsets.
-	for _, subset := range endpoints.Subsets {
-		if endpointName == "" || len(subset.Ports) == 1 {
+	// Otherwise, simply return the only port referenced in EndpointSlice.Ports.
+	// Slices without endpoints may have no ports, and are skipped.
+	for _, slice := range slices {
+		if len(slice.Ports) == 0 {
+			continue
+		}
+		if endpointName == "" || len(slice.Ports) == 1 {
 			// ServicePort.Name is not passed or a single port exists on the service.
 			// Both imply that this service has a single ServicePort and EndpointPort.
-			endpointPort = uint16(subset.Ports[0].Port)
+			endpointPort = uint16(pointer.Int32Deref(slice.Ports[0].Port, 0))
 			return
 		}
-		for _, port := range subset.Ports {
+		for _, port := range slice.Ports {
 			// If more than 1 port is specified
-			if port.Name == endpointName {
-				endpointPort = uint16(port.Port)
+			if pointer.StringDeref(port.Name, "") == endpointName {
+				endpointPort = uint16(pointer.Int32Deref(port.Port, 0))
 				return
 			}
 		}
 	}
 	return
 }
+
+// getEndpointConditions returns whether the given endpoint of an EndpointSlice should be sent traffic, and whether it
+// is terminating. Ready endpoints are sent traffic, as well as terminating endpoints which are still serving, so that
+// the connections established to them are drained rather than reset.
+func getEndpointConditions(conditions discoveryv1.EndpointConditions) (usable bool, terminating bool) {
+	// Per https://pkg.go.dev/k8s.io/api/discovery/v1#EndpointConditions, unknown readiness is interpreted as ready,
+	// and unknown serving as the readiness, which is always false for terminating endpoints.
+	ready := pointer.BoolDeref(conditions.Ready, true)
+	terminating = pointer.BoolDeref(conditions.Terminating, false)
+	if !terminating {
+		return ready, false
+	}
+	return pointer.BoolDeref(conditions.Serving, ready), true
+}
+
+// getEndpointZoneHints returns the zones of the topology hints of the given endpoint of an EndpointSlice
+func getEndpointZoneHints(ep discoveryv1.Endpoint) []string {
+	if ep.Hints == nil {
+		return nil
+	}
+	zones := make([]string, 0, len(ep.Hints.ForZones))
+	for _, zone := range ep.Hints.ForZones {
+		zones = append(zones, zone.Name)
+	}
+	return zones
+}
@@ -0,0 +1,172 @@
+package kube
+
+import (
+	"errors"
+	"net"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/endpoint"
+	"github.com/openservicemesh/osm/pkg/errcode"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/models"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// errMoreThanOneWorkloadEntryForUUID is an error for when OSM finds more than one WorkloadEntry for a given xDS certificate.
+var errMoreThanOneWorkloadEntryForUUID = errors.New("found more than one workload entry for xDS uuid")
+
+// getWorkloadEntryForProxy returns the WorkloadEntry of the workload outside of Kubernetes that the given proxy runs
+// on, based on the UUID and service identity. A nil WorkloadEntry and error are returned when no WorkloadEntry has the
+// proxy's UUID, i.e. when the proxy is not expected to run outside of Kubernetes.
+func (c *client) getWorkloadEntryForProxy(proxy *models.Proxy) (*policyv1alpha1.WorkloadEntry, error) {
+	proxyUUID, svcAccount := proxy.UUID.String(), proxy.Identity.ToK8sServiceAccount()
+
+	var workloadEntry *policyv1alpha1.WorkloadEntry
+	for _, we := range c.kubeController.ListWorkloadEntries() {
+		if we.Labels[constants.EnvoyUniqueIDLabelName] != proxyUUID {
+			continue
+		}
+		if workloadEntry != nil {
+			log.Error().Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrPodBelongsToMultipleServices)).
+				Msgf("Found more than one WorkloadEntry with label %s = %s. There can be only one!",
+					constants.EnvoyUniqueIDLabelName, proxyUUID)
+			return nil, errMoreThanOneWorkloadEntryForUUID
+		}
+		workloadEntry = we
+	}
+	if workloadEntry == nil {
+		return nil, nil
+	}
+
+	if workloadEntry.Namespace != svcAccount.Namespace {
+		log.Warn().Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrFetchingPodFromCert)).
+			Msgf("WorkloadEntry %s/%s belongs to Namespace %s. The proxy's xDS certificate was issued for Namespace %s",
+				workloadEntry.Namespace, workloadEntry.Name, workloadEntry.Namespace, svcAccount.Namespace)
+		return nil, errNamespaceDoesNotMatchProxy
+	}
+
+	if workloadEntry.Spec.ServiceAccount != svcAccount.Name {
+		log.Warn().Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrFetchingPodFromCert)).
+			Msgf("WorkloadEntry %s/%s belongs to ServiceAccount=%s. The proxy's xDS certificate was issued for ServiceAccount=%s",
+				workloadEntry.Namespace, workloadEntry.Name, workloadEntry.Spec.ServiceAccount, svcAccount)
+		return nil, errServiceAccountDoesNotMatchProxy
+	}
+
+	return workloadEntry, nil
+}
+
+// listServicesForWorkloadEntry returns the services whose selector matches the labels of the given WorkloadEntry
+func (c *client) listServicesForWorkloadEntry(we *policyv1alpha1.WorkloadEntry) []service.MeshService {
+	var meshServices []service.MeshService
+	for _, svc := range c.getServicesByLabels(we.Spec.Labels, we.Namespace) {
+		// Headless services only point to workloads outside of Kubernetes as a whole, as they have no hostname
+		if svc.Subdomain == "" {
+			meshServices = append(meshServices, svc)
+		}
+	}
+	return meshServices
+}
+
+// listWorkloadEntriesForService returns the WorkloadEntries backing the given Kubernetes service
+func (c *client) listWorkloadEntriesForService(svc *corev1.Service) []*policyv1alpha1.WorkloadEntry {
+	// Services without a selector do not select workloads
+	if len(svc.Spec.Selector) == 0 {
+		return nil
+	}
+	return filterWorkloadEntriesForService(c.kubeController.ListWorkloadEntries(), svc)
+}
+
+// filterWorkloadEntriesForService returns the given WorkloadEntries whose labels match the selector of the given
+// Kubernetes service
+func filterWorkloadEntriesForService(workloadEntries []*policyv1alpha1.WorkloadEntry, svc *corev1.Service) []*policyv1alpha1.WorkloadEntry {
+	if len(svc.Spec.Selector) == 0 {
+		return nil
+	}
+	selector := labels.Set(svc.Spec.Selector).AsSelector()
+
+	var selected []*policyv1alpha1.WorkloadEntry
+	for _, we := range workloadEntries {
+		if we.Namespace == svc.Namespace && selector.Matches(labels.Set(we.Spec.Labels)) {
+			selected = append(selected, we)
+		}
+	}
+	return selected
+}
+
+// listWorkloadEntryEndpointsForService returns the endpoints of the WorkloadEntries backing the given MeshService
+func (c *client) listWorkloadEntryEndpointsForService(svc service.MeshService) []endpoint.Endpoint {
+	// Workloads outside of Kubernetes have no hostname to match the subdomain of a headless service against
+	if svc.Subdomain != "" {
+		return nil
+	}
+	workloadEntries := c.kubeController.ListWorkloadEntries()
+	if len(workloadEntries) == 0 {
+		return nil
+	}
+	k8sSvc := c.kubeController.GetService(svc.Name, svc.Namespace)
+	if k8sSvc == nil {
+		return nil
+	}
+	portSpec, ok := getServicePort(k8sSvc, svc.Port)
+	if !ok {
+		return nil
+	}
+
+	var endpoints []endpoint.Endpoint
+	for _, we := range filterWorkloadEntriesForService(workloadEntries, k8sSvc) {
+		ip := net.ParseIP(we.Spec.Address)
+		if ip == nil {
+			log.Error().Msgf("Error parsing address %s of WorkloadEntry %s/%s for MeshService %s", we.Spec.Address, we.Namespace, we.Name, svc)
+			continue
+		}
+		endpoints = append(endpoints, endpoint.Endpoint{
+			IP:   ip,
+			Port: endpoint.Port(getWorkloadEntryPort(we, portSpec)),
+		})
+	}
+	return endpoints
+}
+
+// listWorkloadEntryEndpointsForIdentity returns the endpoints of the WorkloadEntries with the given service account
+func (c *client) listWorkloadEntryEndpointsForIdentity(sa identity.K8sServiceAccount) []endpoint.Endpoint {
+	var endpoints []endpoint.Endpoint
+	for _, we := range c.kubeController.ListWorkloadEntries() {
+		if we.Namespace != sa.Namespace || we.Spec.ServiceAccount != sa.Name {
+			continue
+		}
+		ip := net.ParseIP(we.Spec.Address)
+		if ip == nil {
+			log.Error().Msgf("Error parsing address %s of WorkloadEntry %s/%s", we.Spec.Address, we.Namespace, we.Name)
+			continue
+		}
+		endpoints = append(endpoints, endpoint.Endpoint{IP: ip})
+	}
+	return endpoints
+}
+
+// getServicePort returns the port of the given service that clients connect to on the given port number
+func getServicePort(svc *corev1.Service, port uint16) (corev1.ServicePort, bool) {
+	for _, portSpec := range svc.Spec.Ports {
+		if uint16(portSpec.Port) == port {
+			return portSpec, true
+		}
+	}
+	return corev1.ServicePort{}, false
+}
+
+// getWorkloadEntryPort returns the port of the given WorkloadEntry backing the given service port: the port listed
+// under the name of the service port, else its numeric targetPort, else the service port itself.
+func getWorkloadEntryPort(we *policyv1alpha1.WorkloadEntry, portSpec corev1.ServicePort) uint16 {
+	if port, ok := we.Spec.Ports[portSpec.Name]; ok {
+		return uint16(port)
+	}
+	if portSpec.TargetPort.Type == intstr.Int && portSpec.TargetPort.IntVal != 0 {
+		return uint16(portSpec.TargetPort.IntVal)
+	}
+	return uint16(portSpec.Port)
+}
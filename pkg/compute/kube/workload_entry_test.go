@@ -0,0 +1,138 @@
+package kube
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/endpoint"
+	policyFake "github.com/openservicemesh/osm/pkg/gen/client/policy/clientset/versioned/fake"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/messaging"
+	"github.com/openservicemesh/osm/pkg/models"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+func TestWorkloadEntries(t *testing.T) {
+	a := tassert.New(t)
+	stop := make(chan struct{})
+	defer close(stop)
+
+	namespace := "legacy"
+	proxyUUID := uuid.New()
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "inventory", Namespace: namespace},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "inventory"},
+			Ports: []corev1.ServicePort{
+				{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080), Protocol: corev1.ProtocolTCP},
+				{Name: "tcp-admin", Port: 9000, TargetPort: intstr.FromInt(9001), Protocol: corev1.ProtocolTCP},
+			},
+		},
+	}
+	we := &policyv1alpha1.WorkloadEntry{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "vm-1",
+			Namespace:   namespace,
+			Labels:      map[string]string{constants.EnvoyUniqueIDLabelName: proxyUUID.String()},
+			Annotations: map[string]string{constants.PrometheusScrapeAnnotation: "true"},
+		},
+		Spec: policyv1alpha1.WorkloadEntrySpec{
+			Address:        "10.0.0.5",
+			Ports:          map[string]uint32{"http": 8081},
+			ServiceAccount: "inventory",
+			Labels:         map[string]string{"app": "inventory"},
+		},
+	}
+
+	k8sClient, err := k8s.NewClient(tests.OsmNamespace, tests.OsmMeshConfigName, messaging.NewBroker(stop),
+		k8s.WithKubeClient(fake.NewSimpleClientset(monitoredNS(namespace), svc), testMeshName),
+		k8s.WithPolicyClient(policyFake.NewSimpleClientset(we)))
+	a.NoError(err)
+	c := NewClient(k8sClient)
+	si := identity.New("inventory", namespace)
+
+	// The ports of the services only backed by workloads outside of Kubernetes are the ports of their WorkloadEntries
+	meshServices := c.ListServices()
+	a.ElementsMatch([]service.MeshService{
+		{Name: "inventory", Namespace: namespace, Port: 80, TargetPort: 8081, Protocol: constants.ProtocolHTTP},
+		{Name: "inventory", Namespace: namespace, Port: 9000, TargetPort: 9001, Protocol: constants.ProtocolTCP},
+	}, meshServices)
+
+	a.Equal([]endpoint.Endpoint{{IP: net.ParseIP("10.0.0.5"), Port: 8081}},
+		c.ListEndpointsForService(service.MeshService{Name: "inventory", Namespace: namespace, Port: 80}))
+	a.Equal([]endpoint.Endpoint{{IP: net.ParseIP("10.0.0.5"), Port: 9001}},
+		c.ListEndpointsForService(service.MeshService{Name: "inventory", Namespace: namespace, Port: 9000}))
+	a.Equal([]endpoint.Endpoint{{IP: net.ParseIP("10.0.0.5")}}, c.ListEndpointsForIdentity(si))
+	a.ElementsMatch(meshServices, c.GetServicesForServiceIdentity(si))
+
+	identities, err := c.ListServiceIdentitiesForService("inventory", namespace)
+	a.NoError(err)
+	a.Equal([]identity.ServiceIdentity{si}, identities)
+
+	// The proxy of the workload is verified against its WorkloadEntry
+	proxy := models.NewProxy(models.KindSidecar, proxyUUID, si, nil, 1)
+	a.NoError(c.VerifyProxy(proxy))
+	a.ErrorIs(c.VerifyProxy(models.NewProxy(models.KindSidecar, proxyUUID, identity.New("other", namespace), nil, 1)), errServiceAccountDoesNotMatchProxy)
+	a.ErrorIs(c.VerifyProxy(models.NewProxy(models.KindSidecar, proxyUUID, identity.New("inventory", "other"), nil, 1)), errNamespaceDoesNotMatchProxy)
+	a.ErrorIs(c.VerifyProxy(models.NewProxy(models.KindSidecar, uuid.New(), si, nil, 1)), errDidNotFindPodForUUID)
+
+	services, err := c.ListServicesForProxy(proxy)
+	a.NoError(err)
+	a.ElementsMatch(meshServices, services)
+
+	enabled, err := c.IsMetricsEnabled(proxy)
+	a.NoError(err)
+	a.True(enabled)
+
+	headers, err := c.GetProxyStatsHeaders(proxy)
+	a.NoError(err)
+	a.Equal(map[string]string{
+		"osm-stats-pod":       "vm-1",
+		"osm-stats-namespace": namespace,
+		"osm-stats-kind":      "WorkloadEntry",
+		"osm-stats-name":      "vm-1",
+	}, headers)
+}
+
+func TestGetWorkloadEntryPort(t *testing.T) {
+	we := &policyv1alpha1.WorkloadEntry{Spec: policyv1alpha1.WorkloadEntrySpec{Ports: map[string]uint32{"http": 8081}}}
+
+	testCases := []struct {
+		name     string
+		portSpec corev1.ServicePort
+		expected uint16
+	}{
+		{
+			name:     "port listed under the name of the service port",
+			portSpec: corev1.ServicePort{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080)},
+			expected: 8081,
+		},
+		{
+			name:     "numeric target port",
+			portSpec: corev1.ServicePort{Name: "grpc", Port: 90, TargetPort: intstr.FromInt(9090)},
+			expected: 9090,
+		},
+		{
+			name:     "named target port",
+			portSpec: corev1.ServicePort{Name: "grpc", Port: 90, TargetPort: intstr.FromString("grpc")},
+			expected: 90,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tassert.Equal(t, tc.expected, getWorkloadEntryPort(we, tc.portSpec))
+		})
+	}
+}
@@ -6,6 +6,7 @@ package compute
 
 import (
 	context "context"
+	net "net"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -232,6 +233,20 @@ func (mr *MockInterfaceMockRecorder) GetServicesForServiceIdentity(arg0 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServicesForServiceIdentity", reflect.TypeOf((*MockInterface)(nil).GetServicesForServiceIdentity), arg0)
 }
 
+// GetSidecarConfig mocks base method.
+func (m *MockInterface) GetSidecarConfig(arg0 *models.Proxy) models.SidecarConfig {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSidecarConfig", arg0)
+	ret0, _ := ret[0].(models.SidecarConfig)
+	return ret0
+}
+
+// GetSidecarConfig indicates an expected call of GetSidecarConfig.
+func (mr *MockInterfaceMockRecorder) GetSidecarConfig(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSidecarConfig", reflect.TypeOf((*MockInterface)(nil).GetSidecarConfig), arg0)
+}
+
 // GetTCPRoute mocks base method.
 func (m *MockInterface) GetTCPRoute(arg0 string) *v1alpha4.TCPRoute {
 	m.ctrl.T.Helper()
@@ -316,6 +331,20 @@ func (mr *MockInterfaceMockRecorder) GetUpstreamTrafficSettingByService(arg0 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUpstreamTrafficSettingByService", reflect.TypeOf((*MockInterface)(nil).GetUpstreamTrafficSettingByService), arg0)
 }
 
+// GetZoneForProxy mocks base method.
+func (m *MockInterface) GetZoneForProxy(arg0 *models.Proxy) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetZoneForProxy", arg0)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetZoneForProxy indicates an expected call of GetZoneForProxy.
+func (mr *MockInterfaceMockRecorder) GetZoneForProxy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetZoneForProxy", reflect.TypeOf((*MockInterface)(nil).GetZoneForProxy), arg0)
+}
+
 // IsMetricsEnabled mocks base method.
 func (m *MockInterface) IsMetricsEnabled(arg0 *models.Proxy) (bool, error) {
 	m.ctrl.T.Helper()
@@ -415,6 +444,20 @@ func (mr *MockInterfaceMockRecorder) ListHTTPTrafficSpecs() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHTTPTrafficSpecs", reflect.TypeOf((*MockInterface)(nil).ListHTTPTrafficSpecs))
 }
 
+// ListIPsForProxy mocks base method.
+func (m *MockInterface) ListIPsForProxy(arg0 *models.Proxy) []net.IP {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIPsForProxy", arg0)
+	ret0, _ := ret[0].([]net.IP)
+	return ret0
+}
+
+// ListIPsForProxy indicates an expected call of ListIPsForProxy.
+func (mr *MockInterfaceMockRecorder) ListIPsForProxy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIPsForProxy", reflect.TypeOf((*MockInterface)(nil).ListIPsForProxy), arg0)
+}
+
 // ListIngressBackendPolicies mocks base method.
 func (m *MockInterface) ListIngressBackendPolicies() []*v1alpha1.IngressBackend {
 	m.ctrl.T.Helper()
@@ -573,6 +616,20 @@ func (mr *MockInterfaceMockRecorder) ListServicesForProxy(arg0 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServicesForProxy", reflect.TypeOf((*MockInterface)(nil).ListServicesForProxy), arg0)
 }
 
+// ListStaleProxies mocks base method.
+func (m *MockInterface) ListStaleProxies() []models.StaleProxy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStaleProxies")
+	ret0, _ := ret[0].([]models.StaleProxy)
+	return ret0
+}
+
+// ListStaleProxies indicates an expected call of ListStaleProxies.
+func (mr *MockInterfaceMockRecorder) ListStaleProxies() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStaleProxies", reflect.TypeOf((*MockInterface)(nil).ListStaleProxies))
+}
+
 // ListTCPTrafficSpecs mocks base method.
 func (m *MockInterface) ListTCPTrafficSpecs() []*v1alpha4.TCPRoute {
 	m.ctrl.T.Helper()
@@ -615,6 +672,20 @@ func (mr *MockInterfaceMockRecorder) ListTrafficTargets() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrafficTargets", reflect.TypeOf((*MockInterface)(nil).ListTrafficTargets))
 }
 
+// ListTrustDomainFederations mocks base method.
+func (m *MockInterface) ListTrustDomainFederations() []*v1alpha2.TrustDomainFederation {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrustDomainFederations")
+	ret0, _ := ret[0].([]*v1alpha2.TrustDomainFederation)
+	return ret0
+}
+
+// ListTrustDomainFederations indicates an expected call of ListTrustDomainFederations.
+func (mr *MockInterfaceMockRecorder) ListTrustDomainFederations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrustDomainFederations", reflect.TypeOf((*MockInterface)(nil).ListTrustDomainFederations))
+}
+
 // ListUpstreamTrafficSettings mocks base method.
 func (m *MockInterface) ListUpstreamTrafficSettings() []*v1alpha1.UpstreamTrafficSetting {
 	m.ctrl.T.Helper()
@@ -2,6 +2,7 @@ package compute
 
 import (
 	"context"
+	"net"
 
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
@@ -58,6 +59,12 @@ type Interface interface {
 	// ListServicesForProxy gets the services that map to the given proxy.
 	ListServicesForProxy(p *models.Proxy) ([]service.MeshService, error)
 
+	// GetZoneForProxy returns the zone of the given proxy, or an empty string if it isn't known
+	GetZoneForProxy(p *models.Proxy) string
+
+	// ListIPsForProxy returns the IP addresses of the workload of the given proxy
+	ListIPsForProxy(p *models.Proxy) []net.IP
+
 	// ListEgressPoliciesForServiceAccount lists the Egress policies for the given source identity based on service accounts
 	ListEgressPoliciesForServiceAccount(sa identity.K8sServiceAccount) []*policyv1alpha1.Egress
 
@@ -92,6 +99,13 @@ type Interface interface {
 	// order of preference: 1. selector match, 2. namespace match, 3. global match
 	GetTelemetryConfig(*models.Proxy) models.TelemetryConfig
 
+	// GetSidecarConfig returns the effective sidecar configuration of the given proxy instance, resolved from the
+	// MeshConfig and the SidecarProfile selecting its workload
+	GetSidecarConfig(*models.Proxy) models.SidecarConfig
+
 	// GetMeshConfig returns the current MeshConfig
 	GetMeshConfig() configv1alpha2.MeshConfig
+
+	// ListStaleProxies returns the meshed pods whose Envoy sidecar differs from the sidecar the mesh currently injects
+	ListStaleProxies() []models.StaleProxy
 }
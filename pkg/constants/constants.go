@@ -7,6 +7,9 @@ const (
 	// WildcardIPAddr is a string constant.
 	WildcardIPAddr = "0.0.0.0"
 
+	// WildcardIPv6Addr is the IPv6 wildcard address.
+	WildcardIPv6Addr = "::"
+
 	// EnvoyAdminPort is Envoy's admin port
 	EnvoyAdminPort = 15000
 
@@ -73,6 +76,9 @@ const (
 	// ValidatorWebhookPort is the port on which the resource validator webhook listens
 	ValidatorWebhookPort = 9093
 
+	// SpiffeBundleEndpointPort is the port on which osm-controller serves the mesh's SPIFFE trust bundle over HTTPS
+	SpiffeBundleEndpointPort = 9094
+
 	// OSMControllerName is the name of the OSM Controller (formerly ADS service).
 	OSMControllerName = "osm-controller"
 
@@ -118,6 +124,9 @@ const (
 	// KubernetesOpaqueSecretRootPrivateKeyKey is the key which holds the CA's private key in a Kubernetes secret.
 	KubernetesOpaqueSecretRootPrivateKeyKey = "private.key"
 
+	// KubernetesOpaqueSecretEncryptedRootPrivateKeyKey is the key which holds the CA's envelope encrypted private key in a Kubernetes secret.
+	KubernetesOpaqueSecretEncryptedRootPrivateKeyKey = "private.key.enc"
+
 	// EnvoyUniqueIDLabelName is the label applied to pods with the unique ID of the Envoy sidecar.
 	EnvoyUniqueIDLabelName = "osm-proxy-uuid"
 
@@ -144,6 +153,10 @@ const (
 	// InitContainerName is the name of the init container
 	InitContainerName = "osm-init"
 
+	// CNIValidationContainerName is the name of the init container verifying that the traffic of the pod was
+	// redirected by the OSM CNI plugin
+	CNIValidationContainerName = "osm-cni-validation"
+
 	// EnvoyServiceNodeSeparator is the character separating the strings used to create an Envoy service node parameter.
 	// Example use: envoy --service-node 52883c80-6e0d-4c64-b901-cbcb75134949/bookstore/10.144.2.91/bookstore-v1/bookstore-v1
 	EnvoyServiceNodeSeparator = "/"
@@ -169,6 +182,9 @@ const (
 	// HealthcheckPort is the port to use for healthcheck probe
 	HealthcheckPort = int32(15904)
 
+	// EnvoyReadyPort is the port on which the proxy serves the readiness of its admin interface to the kubelet
+	EnvoyReadyPort = int32(15905)
+
 	// LivenessProbePath is the path to use for liveness probe
 	LivenessProbePath = "/osm-liveness-probe"
 
@@ -180,6 +196,12 @@ const (
 
 	// HealthcheckPath is the path to use for healthcheck probe
 	HealthcheckPath = "/osm-healthcheck"
+
+	// GRPCHealthcheckPath is the path to use for gRPC healthcheck probe
+	GRPCHealthcheckPath = "/osm-grpc-healthcheck"
+
+	// EnvoyReadyPath is the path of the readiness endpoint of Envoy's admin interface
+	EnvoyReadyPath = "/ready"
 )
 
 // Annotations used by the control plane
@@ -189,6 +211,17 @@ const (
 
 	// MetricsAnnotation is the annotation used for enabling/disabling metrics
 	MetricsAnnotation = "openservicemesh.io/metrics"
+
+	// ProxyKindAnnotation is the annotation used to select the kind of proxy injected into a pod, ex. 'grpc' for
+	// proxyless gRPC applications
+	ProxyKindAnnotation = "openservicemesh.io/proxy-kind"
+
+	// OriginalHealthProbesAnnotation is the annotation recording the health probes of pods injected offline, e.g. by
+	// `osm inject`, as defined before they were rewritten, from which their bootstrap config is created when admitted
+	OriginalHealthProbesAnnotation = "openservicemesh.io/original-health-probes"
+
+	// InjectorVersionAnnotation is the annotation recording the version of OSM the sidecar of a pod was injected by
+	InjectorVersionAnnotation = "openservicemesh.io/injector-version"
 )
 
 // Labels used by the control plane
@@ -287,6 +320,12 @@ const (
 	// VersionPath is the path at which OSM controller serves version info
 	VersionPath = "/version"
 
+	// OSMControllerSpiffeBundlePath is the path at which OSM controller serves the mesh's SPIFFE trust bundle
+	OSMControllerSpiffeBundlePath = "/spiffe/bundle"
+
+	// WorkloadAPISocketPath is the default path of the unix domain socket on which the node agent serves the SPIFFE Workload API
+	WorkloadAPISocketPath = "/run/osm/workload-api/agent.sock"
+
 	// WebhookHealthPath is the path at which the webooks serve health probes
 	WebhookHealthPath = "/healthz"
 )
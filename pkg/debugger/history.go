@@ -0,0 +1,70 @@
+package debugger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/openservicemesh/osm/pkg/envoy/history"
+)
+
+const (
+	proxyUUIDQueryKey   = "proxy"
+	fromVersionQueryKey = "from"
+	toVersionQueryKey   = "to"
+)
+
+// getConfigHistory returns the versions of the configuration of the proxy with the given UUID, or the difference
+// between two of them when either the 'from' or 'to' version is given. The 'to' version defaults to the latest
+// version, and the 'from' version to the version preceding the 'to' version.
+func (ds DebugConfig) getConfigHistory() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		proxyUUID := query.Get(proxyUUIDQueryKey)
+		if proxyUUID == "" {
+			http.Error(w, fmt.Sprintf("missing %q query parameter", proxyUUIDQueryKey), http.StatusBadRequest)
+			return
+		}
+
+		var from, to uint64
+		for key, version := range map[string]*uint64{fromVersionQueryKey: &from, toVersionQueryKey: &to} {
+			if value := query.Get(key); value != "" {
+				parsed, err := strconv.ParseUint(value, 10, 64)
+				if err != nil {
+					http.Error(w, fmt.Sprintf("couldn't parse %s version %s", key, value), http.StatusBadRequest)
+					return
+				}
+				*version = parsed
+			}
+		}
+
+		var result interface{}
+		var err error
+		if query.Has(fromVersionQueryKey) || query.Has(toVersionQueryKey) {
+			result, err = ds.configHistory.DiffConfigHistory(proxyUUID, from, to)
+		} else {
+			result, err = ds.configHistory.GetConfigHistory(proxyUUID)
+		}
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, history.ErrNotFound) || errors.Is(err, history.ErrDisabled) {
+				status = http.StatusNotFound
+			}
+			log.Error().Err(err).Msgf("Error getting the configuration history of proxy %s", proxyUUID)
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		marshaled, err := json.MarshalIndent(result, "", "    ")
+		if err != nil {
+			msg := fmt.Sprintf("Error marshaling the configuration history of proxy %s", proxyUUID)
+			log.Error().Err(err).Msg(msg)
+			http.Error(w, msg, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, "%s", marshaled)
+	})
+}
@@ -0,0 +1,81 @@
+package debugger
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/history"
+)
+
+func TestGetConfigHistory(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockConfigHistory := NewMockConfigHistoryGetter(mockCtrl)
+
+	entries := []history.Entry{{Version: 1}, {Version: 2}}
+	diff := &history.Diff{
+		From: entries[0],
+		To:   entries[1],
+		Changes: []history.ResourceDiff{{
+			ResourceChange: history.ResourceChange{TypeURL: envoy.TypeRDS, Name: "rds-inbound", Change: history.Modified},
+			Fields:         []history.FieldDiff{{Path: "virtualHosts[0].routes[0].match.prefix", From: "/a", To: "/b"}},
+		}},
+	}
+	mockConfigHistory.EXPECT().GetConfigHistory("p1").Return(entries, nil).AnyTimes()
+	mockConfigHistory.EXPECT().DiffConfigHistory("p1", uint64(0), uint64(0)).Return(diff, nil).AnyTimes()
+	mockConfigHistory.EXPECT().DiffConfigHistory("p1", uint64(1), uint64(0)).Return(diff, nil).AnyTimes()
+	mockConfigHistory.EXPECT().DiffConfigHistory("p1", uint64(1), uint64(3)).
+		Return(nil, fmt.Errorf("version 3 of proxy p1 %w", history.ErrNotFound)).AnyTimes()
+
+	ds := DebugConfig{configHistory: mockConfigHistory}
+
+	testCases := []struct {
+		query            string
+		expectedStatus   int
+		expectedContains string
+	}{
+		{
+			query:          "",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			query:            "proxy=p1",
+			expectedStatus:   http.StatusOK,
+			expectedContains: `"version": 2`,
+		},
+		{
+			query:            "proxy=p1&to=",
+			expectedStatus:   http.StatusOK,
+			expectedContains: `"path": "virtualHosts[0].routes[0].match.prefix"`,
+		},
+		{
+			query:            "proxy=p1&from=1",
+			expectedStatus:   http.StatusOK,
+			expectedContains: `"change": "modified"`,
+		},
+		{
+			query:          "proxy=p1&from=1&to=3",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			query:          "proxy=p1&from=x",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			responseRecorder := httptest.NewRecorder()
+			ds.getConfigHistory().ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/debug/proxy/history?"+tc.query, nil))
+			assert.Equal(tc.expectedStatus, responseRecorder.Code)
+			assert.Contains(responseRecorder.Body.String(), tc.expectedContains)
+		})
+	}
+}
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/openservicemesh/osm/pkg/debugger (interfaces: XDSDebugger)
+// Source: github.com/openservicemesh/osm/pkg/debugger (interfaces: XDSDebugger,XDSStatusGetter,ConfigHistoryGetter)
 
 // Package debugger is a generated GoMock package.
 package debugger
@@ -10,6 +10,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	envoy "github.com/openservicemesh/osm/pkg/envoy"
+	history "github.com/openservicemesh/osm/pkg/envoy/history"
 )
 
 // MockXDSDebugger is a mock of XDSDebugger interface.
@@ -48,3 +49,93 @@ func (mr *MockXDSDebuggerMockRecorder) GetXDSLog() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetXDSLog", reflect.TypeOf((*MockXDSDebugger)(nil).GetXDSLog))
 }
+
+// MockXDSStatusGetter is a mock of XDSStatusGetter interface.
+type MockXDSStatusGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockXDSStatusGetterMockRecorder
+}
+
+// MockXDSStatusGetterMockRecorder is the mock recorder for MockXDSStatusGetter.
+type MockXDSStatusGetterMockRecorder struct {
+	mock *MockXDSStatusGetter
+}
+
+// NewMockXDSStatusGetter creates a new mock instance.
+func NewMockXDSStatusGetter(ctrl *gomock.Controller) *MockXDSStatusGetter {
+	mock := &MockXDSStatusGetter{ctrl: ctrl}
+	mock.recorder = &MockXDSStatusGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockXDSStatusGetter) EXPECT() *MockXDSStatusGetterMockRecorder {
+	return m.recorder
+}
+
+// GetXDSStatus mocks base method.
+func (m *MockXDSStatusGetter) GetXDSStatus(arg0 string) []envoy.XDSStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetXDSStatus", arg0)
+	ret0, _ := ret[0].([]envoy.XDSStatus)
+	return ret0
+}
+
+// GetXDSStatus indicates an expected call of GetXDSStatus.
+func (mr *MockXDSStatusGetterMockRecorder) GetXDSStatus(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetXDSStatus", reflect.TypeOf((*MockXDSStatusGetter)(nil).GetXDSStatus), arg0)
+}
+
+// MockConfigHistoryGetter is a mock of ConfigHistoryGetter interface.
+type MockConfigHistoryGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfigHistoryGetterMockRecorder
+}
+
+// MockConfigHistoryGetterMockRecorder is the mock recorder for MockConfigHistoryGetter.
+type MockConfigHistoryGetterMockRecorder struct {
+	mock *MockConfigHistoryGetter
+}
+
+// NewMockConfigHistoryGetter creates a new mock instance.
+func NewMockConfigHistoryGetter(ctrl *gomock.Controller) *MockConfigHistoryGetter {
+	mock := &MockConfigHistoryGetter{ctrl: ctrl}
+	mock.recorder = &MockConfigHistoryGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConfigHistoryGetter) EXPECT() *MockConfigHistoryGetterMockRecorder {
+	return m.recorder
+}
+
+// DiffConfigHistory mocks base method.
+func (m *MockConfigHistoryGetter) DiffConfigHistory(arg0 string, arg1, arg2 uint64) (*history.Diff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffConfigHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*history.Diff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffConfigHistory indicates an expected call of DiffConfigHistory.
+func (mr *MockConfigHistoryGetterMockRecorder) DiffConfigHistory(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffConfigHistory", reflect.TypeOf((*MockConfigHistoryGetter)(nil).DiffConfigHistory), arg0, arg1, arg2)
+}
+
+// GetConfigHistory mocks base method.
+func (m *MockConfigHistoryGetter) GetConfigHistory(arg0 string) ([]history.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigHistory", arg0)
+	ret0, _ := ret[0].([]history.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigHistory indicates an expected call of GetConfigHistory.
+func (mr *MockConfigHistoryGetterMockRecorder) GetConfigHistory(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigHistory", reflect.TypeOf((*MockConfigHistoryGetter)(nil).GetConfigHistory), arg0)
+}
@@ -1,10 +1,12 @@
 package debugger
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/openservicemesh/osm/pkg/models"
@@ -13,12 +15,14 @@ import (
 const (
 	streamIDQueryKey    = "stream-id"
 	proxyConfigQueryKey = "cfg"
+	xdsStatusQueryKey   = "xds-status"
 )
 
 func (ds DebugConfig) getProxies() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/html")
 		proxyConfigDump := r.URL.Query()[proxyConfigQueryKey]
+		xdsStatus := r.URL.Query()[xdsStatusQueryKey]
 		streamIDQ := r.URL.Query()[streamIDQueryKey]
 
 		if len(streamIDQ) == 0 {
@@ -35,6 +39,8 @@ func (ds DebugConfig) getProxies() http.Handler {
 
 		if len(proxyConfigDump) > 0 {
 			ds.getConfigDump(streamID, w)
+		} else if len(xdsStatus) > 0 {
+			ds.getXDSStatus(streamID, w)
 		} else {
 			ds.getProxy(streamID, w)
 		}
@@ -55,13 +61,15 @@ func (ds DebugConfig) printProxies(w http.ResponseWriter) {
 
 	_, _ = fmt.Fprintf(w, "<h1>Connected Proxies (%d):</h1>", len(proxies))
 	_, _ = fmt.Fprint(w, `<table>`)
-	_, _ = fmt.Fprint(w, "<tr><td>#</td><td>Envoy's Service Identity</td><td>Envoy's UUID</td><td>Connected At</td><td>How long ago</td><td>tools</td></tr>")
+	_, _ = fmt.Fprint(w, "<tr><td>#</td><td>Envoy's Service Identity</td><td>Envoy's UUID</td><td>Connected At</td><td>How long ago</td><td>xDS status</td><td>tools</td></tr>")
 	for idx, proxy := range proxies {
 		ts := proxy.GetConnectedAt()
 		proxyURL := fmt.Sprintf("/debug/proxy?%s=%d", streamIDQueryKey, proxy.GetConnectionID())
 		configDumpURL := fmt.Sprintf("%s&%s=%t", proxyURL, proxyConfigQueryKey, true)
-		_, _ = fmt.Fprintf(w, `<tr><td>%d:</td><td>%s</td><td>%s</td><td>%+v</td><td>(%+v ago)</td><td><a href="%s">certs</a></td><td><a href="%s">cfg</a></td></tr>`,
-			idx+1, proxy.Identity, proxy.UUID, ts, time.Since(ts), proxyURL, configDumpURL)
+		xdsStatusURL := fmt.Sprintf("%s&%s=%t", proxyURL, xdsStatusQueryKey, true)
+		historyURL := fmt.Sprintf("/debug/proxy/history?%s=%s", proxyUUIDQueryKey, proxy.UUID)
+		_, _ = fmt.Fprintf(w, `<tr><td>%d:</td><td>%s</td><td>%s</td><td>%+v</td><td>(%+v ago)</td><td><a href="%s">%s</a></td><td><a href="%s">certs</a></td><td><a href="%s">cfg</a></td><td><a href="%s">history</a></td></tr>`,
+			idx+1, proxy.Identity, proxy.UUID, ts, time.Since(ts), xdsStatusURL, ds.xdsStatusSummary(proxy), proxyURL, configDumpURL, historyURL)
 	}
 	_, _ = fmt.Fprint(w, `</table>`)
 }
@@ -103,3 +111,36 @@ func (ds DebugConfig) getProxy(streamID int64, w http.ResponseWriter) {
 	w.Header().Set("Content-Type", "application/json")
 	_, _ = fmt.Fprintf(w, "%s", envoyConfig)
 }
+
+// xdsStatusSummary returns the resource types whose last response the given proxy rejected, or OK
+func (ds DebugConfig) xdsStatusSummary(proxy *models.Proxy) string {
+	var nacked []string
+	for _, status := range ds.xdsStatus.GetXDSStatus(proxy.UUID.String()) {
+		if status.IsNACKed() {
+			nacked = append(nacked, status.TypeURL.Short())
+		}
+	}
+	if len(nacked) == 0 {
+		return "OK"
+	}
+	return "NACK: " + strings.Join(nacked, ", ")
+}
+
+func (ds DebugConfig) getXDSStatus(streamID int64, w http.ResponseWriter) {
+	proxy := ds.proxyRegistry.GetConnectedProxy(streamID)
+	if proxy == nil {
+		msg := fmt.Sprintf("Proxy for Stream ID %d not found, may have been disconnected", streamID)
+		log.Error().Msg(msg)
+		http.Error(w, msg, http.StatusNotFound)
+		return
+	}
+	statuses, err := json.MarshalIndent(ds.xdsStatus.GetXDSStatus(proxy.UUID.String()), "", "    ")
+	if err != nil {
+		msg := fmt.Sprintf("Error marshaling the xDS status of proxy %s", proxy)
+		log.Error().Err(err).Msg(msg)
+		http.Error(w, msg, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = fmt.Fprintf(w, "%s", statuses)
+}
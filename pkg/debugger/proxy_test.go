@@ -0,0 +1,51 @@
+package debugger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/registry"
+	"github.com/openservicemesh/osm/pkg/models"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+func TestGetXDSStatus(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockXDSStatus := NewMockXDSStatusGetter(mockCtrl)
+
+	proxy := models.NewProxy(models.KindSidecar, uuid.New(), tests.BookbuyerServiceIdentity, nil, 1)
+	proxyRegistry := registry.NewProxyRegistry()
+	proxyRegistry.RegisterProxy(proxy)
+
+	now := time.Now()
+	statuses := []envoy.XDSStatus{
+		{TypeURL: envoy.TypeCDS, LastACKedVersion: "v1", LastACKedAt: now},
+		{TypeURL: envoy.TypeLDS, LastACKedVersion: "v1", LastACKedAt: now.Add(-time.Minute), LastNACKedVersion: "v2", LastNACKedAt: now, NACKMessage: "invalid listener"},
+	}
+	mockXDSStatus.EXPECT().GetXDSStatus(proxy.UUID.String()).Return(statuses).AnyTimes()
+
+	ds := DebugConfig{
+		xdsStatus:     mockXDSStatus,
+		proxyRegistry: proxyRegistry,
+	}
+
+	assert.Equal("NACK: LDS", ds.xdsStatusSummary(proxy))
+
+	responseRecorder := httptest.NewRecorder()
+	ds.getProxies().ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/debug/proxy?stream-id=1&xds-status=true", nil))
+	assert.Equal(http.StatusOK, responseRecorder.Code)
+	assert.Contains(responseRecorder.Body.String(), `"lastNACKedVersion": "v2"`)
+	assert.Contains(responseRecorder.Body.String(), `"nackMessage": "invalid listener"`)
+
+	responseRecorder = httptest.NewRecorder()
+	ds.getProxies().ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/debug/proxy?stream-id=2&xds-status=true", nil))
+	assert.Equal(http.StatusNotFound, responseRecorder.Code)
+}
@@ -19,6 +19,8 @@ func (ds DebugConfig) GetHandlers() map[string]http.Handler {
 		"/debug/certs":         ds.getCertHandler(),
 		"/debug/xds":           ds.getXDSHandler(),
 		"/debug/proxy":         ds.getProxies(),
+		"/debug/proxy/history": ds.getConfigHistory(),
+		"/debug/proxy/stale":   ds.getStaleProxies(),
 		"/debug/namespaces":    ds.getMonitoredNamespacesHandler(),
 		"/debug/feature-flags": ds.getFeatureFlags(),
 
@@ -37,12 +39,14 @@ func (ds DebugConfig) GetHandlers() map[string]http.Handler {
 }
 
 // NewDebugConfig returns an implementation of DebugConfig interface.
-func NewDebugConfig(certDebugger *certificate.Manager, xdsDebugger XDSDebugger,
-	proxyRegistry *registry.ProxyRegistry, kubeConfig *rest.Config, kubeClient kubernetes.Interface,
+func NewDebugConfig(certDebugger *certificate.Manager, xdsDebugger XDSDebugger, xdsStatus XDSStatusGetter,
+	configHistory ConfigHistoryGetter, proxyRegistry *registry.ProxyRegistry, kubeConfig *rest.Config, kubeClient kubernetes.Interface,
 	computeClient compute.Interface, msgBroker *messaging.Broker) DebugConfig {
 	return DebugConfig{
 		certDebugger:  certDebugger,
 		xdsDebugger:   xdsDebugger,
+		xdsStatus:     xdsStatus,
+		configHistory: configHistory,
 		proxyRegistry: proxyRegistry,
 		kubeClient:    kubeClient,
 		computeClient: computeClient,
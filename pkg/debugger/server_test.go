@@ -26,6 +26,8 @@ func TestGetHandlers(t *testing.T) {
 
 	ds := NewDebugConfig(cm,
 		mockXdsDebugger,
+		NewMockXDSStatusGetter(mockCtrl),
+		NewMockConfigHistoryGetter(mockCtrl),
 		proxyRegistry,
 		nil,
 		client,
@@ -38,6 +40,7 @@ func TestGetHandlers(t *testing.T) {
 		"/debug/certs",
 		"/debug/xds",
 		"/debug/proxy",
+		"/debug/proxy/history",
 		"/debug/namespaces",
 		// Pprof handlers
 		"/debug/pprof/",
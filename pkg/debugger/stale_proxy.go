@@ -0,0 +1,35 @@
+package debugger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/openservicemesh/osm/pkg/models"
+)
+
+type staleProxies struct {
+	StaleProxies []models.StaleProxy `json:"staleProxies"`
+}
+
+// getStaleProxies returns the meshed pods whose sidecar differs from the sidecar the mesh currently injects
+func (ds DebugConfig) getStaleProxies() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stale := staleProxies{
+			StaleProxies: ds.computeClient.ListStaleProxies(),
+		}
+		if stale.StaleProxies == nil {
+			stale.StaleProxies = []models.StaleProxy{}
+		}
+
+		marshaled, err := json.MarshalIndent(stale, "", "    ")
+		if err != nil {
+			msg := "Error marshaling the stale proxies"
+			log.Error().Err(err).Msg(msg)
+			http.Error(w, msg, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, "%s", marshaled)
+	})
+}
@@ -0,0 +1,54 @@
+package debugger
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/compute"
+	"github.com/openservicemesh/osm/pkg/models"
+)
+
+func TestStaleProxiesHandler(t *testing.T) {
+	testCases := []struct {
+		name         string
+		staleProxies []models.StaleProxy
+		expectedBody string
+	}{
+		{
+			name:         "no stale proxies",
+			expectedBody: `{"staleProxies":[]}`,
+		},
+		{
+			name: "stale proxies",
+			staleProxies: []models.StaleProxy{
+				{
+					Namespace: "bookstore",
+					Pod:       "bookstore-v1",
+					Reasons:   []models.StaleProxyReason{models.StaleProxyReasonEnvoyImage, models.StaleProxyReasonInjectorVersion},
+				},
+			},
+			expectedBody: `{"staleProxies":[{"namespace":"bookstore","pod":"bookstore-v1","reasons":["envoy-image","injector-version"]}]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			mockComputeInterface := compute.NewMockInterface(mockCtrl)
+			mockComputeInterface.EXPECT().ListStaleProxies().Return(tc.staleProxies)
+
+			ds := DebugConfig{
+				computeClient: mockComputeInterface,
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			ds.getStaleProxies().ServeHTTP(responseRecorder, nil)
+			assert.Equal("application/json", responseRecorder.Header().Get("Content-Type"))
+			assert.JSONEq(tc.expectedBody, responseRecorder.Body.String())
+		})
+	}
+}
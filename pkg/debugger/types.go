@@ -10,6 +10,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/compute"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/history"
 	"github.com/openservicemesh/osm/pkg/envoy/registry"
 	"github.com/openservicemesh/osm/pkg/logger"
 	"github.com/openservicemesh/osm/pkg/messaging"
@@ -21,6 +22,8 @@ var log = logger.New("debugger")
 type DebugConfig struct {
 	certDebugger  *certificate.Manager
 	xdsDebugger   XDSDebugger
+	xdsStatus     XDSStatusGetter
+	configHistory ConfigHistoryGetter
 	proxyRegistry *registry.ProxyRegistry
 	kubeConfig    *rest.Config
 	kubeClient    kubernetes.Interface
@@ -34,3 +37,19 @@ type XDSDebugger interface {
 	// of the form <identity>:<uuid>.
 	GetXDSLog() map[string]map[envoy.TypeURI][]time.Time
 }
+
+// XDSStatusGetter is an interface providing debugging server with the ACK and NACK status of the XDS responses.
+type XDSStatusGetter interface {
+	// GetXDSStatus returns the last versions of each resource type the proxy with the given UUID accepted and rejected
+	GetXDSStatus(proxyUUID string) []envoy.XDSStatus
+}
+
+// ConfigHistoryGetter is an interface providing debugging server with the history of the configuration of the proxies.
+type ConfigHistoryGetter interface {
+	// GetConfigHistory returns the last versions of the configuration generated for the proxy with the given UUID
+	GetConfigHistory(proxyUUID string) ([]history.Entry, error)
+
+	// DiffConfigHistory returns the difference between the given versions of the configuration generated for the
+	// proxy with the given UUID
+	DiffConfigHistory(proxyUUID string, from, to uint64) (*history.Diff, error)
+}
@@ -15,3 +15,49 @@ func Test(t *testing.T) {
 	}
 	assert.Equal(ept.String(), "(ip=9.9.9.9, port=1234)")
 }
+
+func TestFilterByZoneHints(t *testing.T) {
+	zoneA := Endpoint{IP: net.ParseIP("10.0.0.1"), Port: 80, Zone: "a", ZoneHints: []string{"a"}}
+	zoneB := Endpoint{IP: net.ParseIP("10.0.0.2"), Port: 80, Zone: "b", ZoneHints: []string{"b", "c"}}
+	unhinted := Endpoint{IP: net.ParseIP("10.0.0.3"), Port: 80, Zone: "a"}
+	remote := Endpoint{IP: net.ParseIP("10.1.0.1"), Port: 80, Weight: 100}
+
+	testCases := []struct {
+		name      string
+		endpoints []Endpoint
+		zone      string
+		expected  []Endpoint
+	}{
+		{
+			name:      "unknown zone",
+			endpoints: []Endpoint{zoneA, zoneB},
+			zone:      "",
+			expected:  []Endpoint{zoneA, zoneB},
+		},
+		{
+			name:      "endpoints hinted for the zone",
+			endpoints: []Endpoint{zoneA, zoneB, remote},
+			zone:      "c",
+			expected:  []Endpoint{zoneB, remote},
+		},
+		{
+			name:      "endpoint without hints",
+			endpoints: []Endpoint{zoneA, zoneB, unhinted},
+			zone:      "a",
+			expected:  []Endpoint{zoneA, zoneB, unhinted},
+		},
+		{
+			name:      "no endpoint hinted for the zone",
+			endpoints: []Endpoint{zoneA, zoneB},
+			zone:      "d",
+			expected:  []Endpoint{zoneA, zoneB},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expected, FilterByZoneHints(tc.endpoints, tc.zone))
+		})
+	}
+}
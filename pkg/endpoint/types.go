@@ -18,6 +18,14 @@ type Endpoint struct {
 
 	// Zone is the zone the endpoint resides in.
 	Zone string `json:"name"`
+
+	// Terminating indicates the endpoint is terminating, in which case it keeps serving the connections established
+	// to it but doesn't receive new ones.
+	Terminating bool `json:"terminating,omitempty"`
+
+	// ZoneHints are the zones of the clients which should consume the endpoint, from the topology hints of the
+	// endpoint's service.
+	ZoneHints []string `json:"zoneHints,omitempty"`
 }
 
 func (ep Endpoint) String() string {
@@ -32,3 +40,43 @@ type Weight uint32
 
 // Priority is the priority of the remote cluster in locality based load balancing
 type Priority uint32
+
+// FilterByZoneHints returns the endpoints to be consumed by clients in the given zone, per the topology hints of the
+// endpoints. As with kube-proxy, hints are only honored when the zone is known, all the local endpoints have hints,
+// and some endpoint is hinted for the zone; otherwise all the endpoints are returned. Endpoints of remote clusters,
+// which have a weight, don't have hints and are always returned.
+func FilterByZoneHints(endpoints []Endpoint, zone string) []Endpoint {
+	if zone == "" {
+		return endpoints
+	}
+	var hinted bool
+	for _, ep := range endpoints {
+		if ep.Weight != 0 {
+			continue
+		}
+		if len(ep.ZoneHints) == 0 {
+			return endpoints
+		}
+		for _, hint := range ep.ZoneHints {
+			hinted = hinted || hint == zone
+		}
+	}
+	if !hinted {
+		return endpoints
+	}
+
+	filtered := make([]Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		if ep.Weight != 0 {
+			filtered = append(filtered, ep)
+			continue
+		}
+		for _, hint := range ep.ZoneHints {
+			if hint == zone {
+				filtered = append(filtered, ep)
+				break
+			}
+		}
+	}
+	return filtered
+}
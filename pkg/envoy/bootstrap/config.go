@@ -3,6 +3,7 @@ package bootstrap
 import (
 	"fmt"
 	"path/filepath"
+	"sort"
 
 	xds_accesslog_config "github.com/envoyproxy/go-control-plane/envoy/config/accesslog/v3"
 	xds_bootstrap "github.com/envoyproxy/go-control-plane/envoy/config/bootstrap/v3"
@@ -10,6 +11,7 @@ import (
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
 	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_metrics "github.com/envoyproxy/go-control-plane/envoy/config/metrics/v3"
 	xds_accesslog_stream "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/stream/v3"
 	xds_transport_sockets "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	xds_upstream_http "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3"
@@ -211,7 +213,7 @@ func (b *Builder) Build() (*xds_bootstrap.Bootstrap, error) {
 		},
 		DynamicResources: &xds_bootstrap.Bootstrap_DynamicResources{
 			AdsConfig: &xds_core.ApiConfigSource{
-				ApiType:             xds_core.ApiConfigSource_GRPC,
+				ApiType:             b.adsAPIType(),
 				TransportApiVersion: xds_core.ApiVersion_V3,
 				GrpcServices: []*xds_core.GrpcService{
 					{
@@ -298,9 +300,54 @@ func (b *Builder) Build() (*xds_bootstrap.Bootstrap, error) {
 	bootstrap.StaticResources.Listeners = append(bootstrap.StaticResources.Listeners, probeListeners...)
 	bootstrap.StaticResources.Clusters = append(bootstrap.StaticResources.Clusters, probeClusters...)
 
+	if b.EnableReadyListener {
+		readyListener, adminCluster, err := getReadyResources()
+		if err != nil {
+			return nil, err
+		}
+		bootstrap.StaticResources.Listeners = append(bootstrap.StaticResources.Listeners, readyListener)
+		bootstrap.StaticResources.Clusters = append(bootstrap.StaticResources.Clusters, adminCluster)
+	}
+
+	bootstrap.StatsConfig = b.getStatsConfig()
+
 	return bootstrap, nil
 }
 
+// getStatsConfig returns the stats config adding the stats tags of the proxy to its stats, or nil when the proxy
+// has no stats tags
+func (b *Builder) getStatsConfig() *xds_metrics.StatsConfig {
+	if len(b.StatsTags) == 0 {
+		return nil
+	}
+
+	// The tags are sorted for the bootstrap config to be deterministic
+	tagNames := make([]string, 0, len(b.StatsTags))
+	for name := range b.StatsTags {
+		tagNames = append(tagNames, name)
+	}
+	sort.Strings(tagNames)
+
+	statsConfig := &xds_metrics.StatsConfig{}
+	for _, name := range tagNames {
+		statsConfig.StatsTags = append(statsConfig.StatsTags, &xds_metrics.TagSpecifier{
+			TagName: name,
+			TagValue: &xds_metrics.TagSpecifier_FixedValue{
+				FixedValue: b.StatsTags[name],
+			},
+		})
+	}
+	return statsConfig
+}
+
+// adsAPIType returns the variant of ADS the proxy uses to fetch its dynamic resources
+func (b *Builder) adsAPIType() xds_core.ApiConfigSource_ApiType {
+	if b.EnableDeltaXDS {
+		return xds_core.ApiConfigSource_DELTA_GRPC
+	}
+	return xds_core.ApiConfigSource_GRPC
+}
+
 // GetTLSSDSConfigYAML returns the statically used TLS SDS config YAML.
 func GetTLSSDSConfigYAML() ([]byte, error) {
 	tlsSDSConfig, err := BuildTLSSecret()
@@ -358,7 +405,16 @@ func (b *Builder) getProbeResources() ([]*xds_listener.Listener, []*xds_cluster.
 		inboundPort:  constants.StartupProbePort,
 	}
 
-	for containerName, probes := range b.OriginalHealthProbes {
+	// The probe routes match the path prefix of each container. Containers are sorted in reverse order so that the
+	// route of a container is never shadowed by the route of a container whose name is a prefix of its name.
+	containerNames := make([]string, 0, len(b.OriginalHealthProbes))
+	for containerName := range b.OriginalHealthProbes {
+		containerNames = append(containerNames, containerName)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(containerNames)))
+
+	for _, containerName := range containerNames {
+		probes := b.OriginalHealthProbes[containerName]
 		// Liveness probe listener + cluster
 		livenessClusterName := fmt.Sprintf("%s_%s", containerName, livenessCluster)
 		livenessListenerBuilder.AddProbe(containerName, livenessClusterName, constants.LivenessProbePath, probes.Liveness)
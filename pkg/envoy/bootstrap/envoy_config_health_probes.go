@@ -11,7 +11,6 @@ import (
 	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	xds_http_connection_manager "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
-	xds_tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
 	"github.com/golang/protobuf/ptypes/any"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/durationpb"
@@ -31,14 +30,17 @@ const (
 	livenessListener  = "liveness_listener"
 	readinessListener = "readiness_listener"
 	startupListener   = "startup_listener"
+
+	proxyAdminCluster  = "proxy_admin_cluster"
+	proxyReadyListener = "proxy_ready_listener"
 )
 
 func buildProbeCluster(clusterName string, originalProbe *models.HealthProbe) *xds_cluster.Cluster {
-	if originalProbe == nil || originalProbe.IsTCPSocket {
+	if originalProbe == nil || originalProbe.IsTCPSocket || originalProbe.IsGRPC {
 		return nil
 	}
 
-	return &xds_cluster.Cluster{
+	cluster := &xds_cluster.Cluster{
 		Name: clusterName,
 		ClusterDiscoveryType: &xds_cluster.Cluster_Type{
 			Type: xds_cluster.Cluster_STATIC,
@@ -70,6 +72,20 @@ func buildProbeCluster(clusterName string, originalProbe *models.HealthProbe) *x
 			},
 		},
 	}
+
+	if !originalProbe.IsHTTP {
+		// HTTPS probes are sent to the container over TLS without verifying its certificate, as the kubelet does
+		cluster.TransportSocket = &xds_core.TransportSocket{
+			Name: "envoy.transport_sockets.tls",
+			ConfigType: &xds_core.TransportSocket_TypedConfig{
+				TypedConfig: &anypb.Any{
+					TypeUrl: envoy.TypeUpstreamTLSContext.String(),
+				},
+			},
+		}
+	}
+
+	return cluster
 }
 
 type probeListenerRoute struct {
@@ -83,26 +99,19 @@ type probeListenerBuilder struct {
 	listenerName      string
 	inboundPort       int32
 	virtualHostRoutes []probeListenerRoute
-	httpsClusterName  string
 }
 
 func (plb *probeListenerBuilder) AddProbe(containerName, clusterName, newProbePath string, probe *models.HealthProbe) {
-	if probe == nil || probe.IsTCPSocket {
+	if probe == nil || probe.IsTCPSocket || probe.IsGRPC {
 		return
 	}
 
-	if probe.IsHTTP {
-		plb.virtualHostRoutes = append(plb.virtualHostRoutes, probeListenerRoute{
-			pathPrefixMatch:   fmt.Sprintf("%s/%s", newProbePath, containerName),
-			clusterName:       clusterName,
-			pathPrefixRewrite: probe.Path,
-		})
-	} else {
-		// NOTE: Only 1 HTTPS probe is supported per type (liveness, readiness, startup) across all containers
-		// This is due to the fact that we passthrough HTTPS and cannot do any kind of filtering on path
-		// Therefore, the last declared HTTPS probe will be the only one receiving traffic
-		plb.httpsClusterName = clusterName
-	}
+	// HTTPS probes are rewritten into HTTP probes, and sent to the container over TLS by the probe cluster
+	plb.virtualHostRoutes = append(plb.virtualHostRoutes, probeListenerRoute{
+		pathPrefixMatch:   fmt.Sprintf("%s/%s", newProbePath, containerName),
+		clusterName:       clusterName,
+		pathPrefixRewrite: probe.Path,
+	})
 }
 
 func getHTTPAccessLogs() ([]*xds_accesslog.AccessLog, error) {
@@ -110,102 +119,45 @@ func getHTTPAccessLogs() ([]*xds_accesslog.AccessLog, error) {
 	return ab.Build()
 }
 
-func getTCPAccessLogs() ([]*xds_accesslog.AccessLog, error) {
-	format := `{"start_time":"%START_TIME%","bytes_received":"%BYTES_RECEIVED%","bytes_sent":"%BYTES_SENT%","duration":"%DURATION%","requested_server_name":"%REQUESTED_SERVER_NAME%","response_flags":"%RESPONSE_FLAGS%","upstream_cluster":"%UPSTREAM_CLUSTER%","upstream_host":"%UPSTREAM_HOST%"}`
-	ab := lds.NewAccessLogBuilder().Name(envoy.StreamAccessLoggerName).Format(format)
-	return ab.Build()
-}
-
 func (plb *probeListenerBuilder) Build() (*xds_listener.Listener, error) {
-	// listenerName should be populated and one of (httpsClusterName, []virtualHostRoutes) should be set
-	if plb.listenerName == "" || (plb.httpsClusterName == "" && len(plb.virtualHostRoutes) == 0) {
+	// listenerName and virtualHostRoutes should be populated
+	if plb.listenerName == "" || len(plb.virtualHostRoutes) == 0 {
 		return nil, nil
 	}
 
-	var filterChains []*xds_listener.FilterChain
 	httpAccessLogs, err := getHTTPAccessLogs()
 	if err != nil {
 		return nil, err
 	}
 
-	// Add the TCPProxy filter first because it has a filter chain match
-	if plb.httpsClusterName != "" {
-		// NOTE: Only 1 HTTPS probe is supported per type (liveness, readiness, startup)
-		// This is due to the fact that we passthrough HTTPS and cannot do any kind of filtering on path
-		// Therefore, the last declared HTTPS probe will be the only one receiving traffic
-		tcpAccessLogs, err := getTCPAccessLogs()
-		if err != nil {
-			return nil, err
-		}
-		tcpProxy := &xds_tcp_proxy.TcpProxy{
-			StatPrefix: "health_probes_https",
-			AccessLog:  tcpAccessLogs,
-			ClusterSpecifier: &xds_tcp_proxy.TcpProxy_Cluster{
-				Cluster: plb.httpsClusterName,
-			},
-		}
-		pbTCPProxy, err := anypb.New(tcpProxy)
-		if err != nil {
-			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
-				Msgf("Error marshaling TcpProxy struct into an anypb.Any message")
-			return nil, err
-		}
-		filterChains = append(filterChains, &xds_listener.FilterChain{
-			Filters: []*xds_listener.Filter{
-				{
-					Name: envoy.TCPProxyFilterName,
-					ConfigType: &xds_listener.Filter_TypedConfig{
-						TypedConfig: pbTCPProxy,
-					},
-				},
-			},
-			// this filter chain match allows for TCPProxy and HTTPConnManager to be used on the same listener
-			FilterChainMatch: &xds_listener.FilterChainMatch{
-				TransportProtocol: envoy.TransportProtocolTLS,
-			},
-		})
-	}
-
-	if len(plb.virtualHostRoutes) > 0 {
-		httpConnectionManager := &xds_http_connection_manager.HttpConnectionManager{
-			CodecType:  xds_http_connection_manager.HttpConnectionManager_AUTO,
-			StatPrefix: "health_probes_http",
-			AccessLog:  httpAccessLogs,
-			RouteSpecifier: &xds_http_connection_manager.HttpConnectionManager_RouteConfig{
-				RouteConfig: &xds_route.RouteConfiguration{
-					Name: "local_route",
-					VirtualHosts: []*xds_route.VirtualHost{
-						getVirtualHost(plb.virtualHostRoutes),
-					},
+	httpConnectionManager := &xds_http_connection_manager.HttpConnectionManager{
+		CodecType:  xds_http_connection_manager.HttpConnectionManager_AUTO,
+		StatPrefix: "health_probes_http",
+		AccessLog:  httpAccessLogs,
+		RouteSpecifier: &xds_http_connection_manager.HttpConnectionManager_RouteConfig{
+			RouteConfig: &xds_route.RouteConfiguration{
+				Name: "local_route",
+				VirtualHosts: []*xds_route.VirtualHost{
+					getVirtualHost(plb.virtualHostRoutes),
 				},
 			},
-			HttpFilters: []*xds_http_connection_manager.HttpFilter{
-				{
-					Name: envoy.HTTPRouterFilterName,
-					ConfigType: &xds_http_connection_manager.HttpFilter_TypedConfig{
-						TypedConfig: &any.Any{
-							TypeUrl: envoy.HTTPRouterFilterTypeURL,
-						},
-					},
-				},
-			},
-		}
-		pbHTTPConnectionManager, err := anypb.New(httpConnectionManager)
-		if err != nil {
-			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
-				Msgf("Error marshaling HttpConnectionManager struct into an anypb.Any message")
-			return nil, err
-		}
-		filterChains = append(filterChains, &xds_listener.FilterChain{
-			Filters: []*xds_listener.Filter{
-				{
-					Name: envoy.HTTPConnectionManagerFilterName,
-					ConfigType: &xds_listener.Filter_TypedConfig{
-						TypedConfig: pbHTTPConnectionManager,
+		},
+		HttpFilters: []*xds_http_connection_manager.HttpFilter{
+			{
+				Name: envoy.HTTPRouterFilterName,
+				ConfigType: &xds_http_connection_manager.HttpFilter_TypedConfig{
+					TypedConfig: &any.Any{
+						TypeUrl: envoy.HTTPRouterFilterTypeURL,
 					},
 				},
 			},
-		})
+		},
+	}
+	pbHTTPConnectionManager, err := anypb.New(httpConnectionManager)
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
+			Msgf("Error marshaling HttpConnectionManager struct into an anypb.Any message")
+		return nil, err
 	}
 
 	return &xds_listener.Listener{
@@ -220,17 +172,18 @@ func (plb *probeListenerBuilder) Build() (*xds_listener.Listener, error) {
 				},
 			},
 		},
-		ListenerFilters: []*xds_listener.ListenerFilter{
+		FilterChains: []*xds_listener.FilterChain{
 			{
-				Name: envoy.TLSInspectorFilterName,
-				ConfigType: &xds_listener.ListenerFilter_TypedConfig{
-					TypedConfig: &anypb.Any{
-						TypeUrl: envoy.TLSInspectorFilterTypeURL, // Use TLSInspector to look for HTTPS traffic
+				Filters: []*xds_listener.Filter{
+					{
+						Name: envoy.HTTPConnectionManagerFilterName,
+						ConfigType: &xds_listener.Filter_TypedConfig{
+							TypedConfig: pbHTTPConnectionManager,
+						},
 					},
 				},
 			},
 		},
-		FilterChains: filterChains,
 	}, nil
 }
 
@@ -270,3 +223,32 @@ func getVirtualHost(routes []probeListenerRoute) *xds_route.VirtualHost {
 		Routes: xdsRoutes,
 	}
 }
+
+// getReadyResources returns the listener and cluster objects that are statically configured to serve the readiness
+// endpoint of the proxy's admin interface, which only listens on localhost, on constants.EnvoyReadyPort
+func getReadyResources() (*xds_listener.Listener, *xds_cluster.Cluster, error) {
+	readyListenerBuilder := probeListenerBuilder{
+		listenerName: proxyReadyListener,
+		inboundPort:  constants.EnvoyReadyPort,
+	}
+	adminProbe := &models.HealthProbe{
+		Path:   constants.EnvoyReadyPath,
+		Port:   constants.EnvoyAdminPort,
+		IsHTTP: true,
+	}
+
+	// Only the readiness endpoint is served, the other endpoints of the admin interface are not exposed
+	readyListenerBuilder.virtualHostRoutes = append(readyListenerBuilder.virtualHostRoutes, probeListenerRoute{
+		pathPrefixMatch:   constants.EnvoyReadyPath,
+		clusterName:       proxyAdminCluster,
+		pathPrefixRewrite: constants.EnvoyReadyPath,
+	})
+
+	readyListener, err := readyListenerBuilder.Build()
+	if err != nil {
+		log.Error().Err(err).Msgf("Error building proxy ready listener")
+		return nil, nil, err
+	}
+
+	return readyListener, buildProbeCluster(proxyAdminCluster, adminProbe), nil
+}
@@ -10,7 +10,6 @@ import (
 	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	xds_http_connection_manager "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
-	xds_tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
 	"github.com/golang/protobuf/ptypes/any"
 	"github.com/onsi/ginkgo"
 	"github.com/stretchr/testify/assert"
@@ -31,6 +30,7 @@ var _ = ginkgo.Describe("Test functions creating Envoy config and rewriting the
 	liveness := &models.HealthProbe{Path: "/liveness", Port: 81, IsHTTP: true, IsTCPSocket: false, Timeout: timeout}
 	readiness := &models.HealthProbe{Path: "/readiness", Port: 82, IsHTTP: true, IsTCPSocket: false, Timeout: timeout}
 	startup := &models.HealthProbe{Path: "/startup", Port: 83, IsHTTP: true, IsTCPSocket: false, Timeout: timeout}
+	httpsProbe := &models.HealthProbe{Path: "/readiness", Port: 84, Timeout: timeout}
 
 	var routes []probeListenerRoute
 	var defaultTimeoutRoutes []probeListenerRoute
@@ -64,9 +64,10 @@ var _ = ginkgo.Describe("Test functions creating Envoy config and rewriting the
 		"getVirtualHostsMultiple": func() protoreflect.ProtoMessage {
 			return getVirtualHost(allRoutes)
 		},
-		"getLivenessCluster":  func() protoreflect.ProtoMessage { return buildProbeCluster("my-container", liveness) },
-		"getReadinessCluster": func() protoreflect.ProtoMessage { return buildProbeCluster("my-container", readiness) },
-		"getStartupCluster":   func() protoreflect.ProtoMessage { return buildProbeCluster("my-container", startup) },
+		"getLivenessCluster":   func() protoreflect.ProtoMessage { return buildProbeCluster("my-container", liveness) },
+		"getReadinessCluster":  func() protoreflect.ProtoMessage { return buildProbeCluster("my-container", readiness) },
+		"getStartupCluster":    func() protoreflect.ProtoMessage { return buildProbeCluster("my-container", startup) },
+		"getHTTPSProbeCluster": func() protoreflect.ProtoMessage { return buildProbeCluster("my-container", httpsProbe) },
 	}
 
 	for fnName, fn := range clusterFunctionsToTest {
@@ -135,11 +136,6 @@ func Test_probeListenerBuilder_Build(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	tcpAccessLogs, err := getTCPAccessLogs()
-	if err != nil {
-		t.Fatal(err)
-	}
-
 	testLivenessListenerHTTPConnManager := &xds_http_connection_manager.HttpConnectionManager{
 		CodecType:  xds_http_connection_manager.HttpConnectionManager_AUTO,
 		StatPrefix: "health_probes_http",
@@ -203,16 +199,6 @@ func Test_probeListenerBuilder_Build(t *testing.T) {
 				},
 			},
 		},
-		ListenerFilters: []*xds_listener.ListenerFilter{
-			{
-				Name: envoy.TLSInspectorFilterName,
-				ConfigType: &xds_listener.ListenerFilter_TypedConfig{
-					TypedConfig: &anypb.Any{
-						TypeUrl: envoy.TLSInspectorFilterTypeURL,
-					},
-				},
-			},
-		},
 		FilterChains: []*xds_listener.FilterChain{
 			{
 				Filters: []*xds_listener.Filter{
@@ -306,16 +292,6 @@ func Test_probeListenerBuilder_Build(t *testing.T) {
 				},
 			},
 		},
-		ListenerFilters: []*xds_listener.ListenerFilter{
-			{
-				Name: envoy.TLSInspectorFilterName,
-				ConfigType: &xds_listener.ListenerFilter_TypedConfig{
-					TypedConfig: &anypb.Any{
-						TypeUrl: envoy.TLSInspectorFilterTypeURL,
-					},
-				},
-			},
-		},
 		FilterChains: []*xds_listener.FilterChain{
 			{
 				Filters: []*xds_listener.Filter{
@@ -330,63 +306,11 @@ func Test_probeListenerBuilder_Build(t *testing.T) {
 		},
 	}
 
-	httpsProbeTCPProxy := &xds_tcp_proxy.TcpProxy{
-		StatPrefix: "health_probes_https",
-		AccessLog:  tcpAccessLogs,
-		ClusterSpecifier: &xds_tcp_proxy.TcpProxy_Cluster{
-			Cluster: "my-sidecar_startup_cluster",
-		},
-	}
-	pbTCPProxy, err := anypb.New(httpsProbeTCPProxy)
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	httpsListener := &xds_listener.Listener{
-		Name: "startup_listener",
-		Address: &xds_core.Address{
-			Address: &xds_core.Address_SocketAddress{
-				SocketAddress: &xds_core.SocketAddress{
-					Address: "0.0.0.0",
-					PortSpecifier: &xds_core.SocketAddress_PortValue{
-						PortValue: 15903,
-					},
-				},
-			},
-		},
-		ListenerFilters: []*xds_listener.ListenerFilter{
-			{
-				Name: envoy.TLSInspectorFilterName,
-				ConfigType: &xds_listener.ListenerFilter_TypedConfig{
-					TypedConfig: &anypb.Any{
-						TypeUrl: envoy.TLSInspectorFilterTypeURL,
-					},
-				},
-			},
-		},
-		FilterChains: []*xds_listener.FilterChain{
-			{
-				FilterChainMatch: &xds_listener.FilterChainMatch{
-					TransportProtocol: envoy.TransportProtocolTLS,
-				},
-				Filters: []*xds_listener.Filter{
-					{
-						Name: envoy.TCPProxyFilterName,
-						ConfigType: &xds_listener.Filter_TypedConfig{
-							TypedConfig: pbTCPProxy,
-						},
-					},
-				},
-			},
-		},
-	}
-
 	type fields struct {
 		listenerName      string
 		inboundPort       int32
 		virtualHostRoutes []probeListenerRoute
 		isHTTP            bool
-		httpsClusterName  string
 	}
 	tests := []struct {
 		name    string
@@ -434,16 +358,6 @@ func Test_probeListenerBuilder_Build(t *testing.T) {
 			},
 			want: testReadinessListener,
 		},
-
-		{
-			name: "HTTPS probe",
-			fields: fields{
-				listenerName:     "startup_listener",
-				httpsClusterName: fmt.Sprintf("my-sidecar_%s", startupCluster),
-				inboundPort:      constants.StartupProbePort,
-			},
-			want: httpsListener,
-		},
 		{
 			name: "http: no virtualHosts (should return nil and no error)",
 			fields: fields{
@@ -466,7 +380,6 @@ func Test_probeListenerBuilder_Build(t *testing.T) {
 				listenerName:      tt.fields.listenerName,
 				inboundPort:       tt.fields.inboundPort,
 				virtualHostRoutes: tt.fields.virtualHostRoutes,
-				httpsClusterName:  tt.fields.httpsClusterName,
 			}
 			marshalOptions := protojson.MarshalOptions{
 				UseProtoNames: true,
@@ -30,4 +30,14 @@ type Builder struct {
 
 	// A map of container -> health probe structs
 	OriginalHealthProbes map[string]models.HealthProbes
+
+	// EnableDeltaXDS configures the proxy to use the incremental (delta) variant of ADS
+	EnableDeltaXDS bool
+
+	// EnableReadyListener configures a listener serving the readiness endpoint of the proxy's admin interface, which
+	// only listens on localhost, to the kubelet probing the proxy
+	EnableReadyListener bool
+
+	// StatsTags are the tags with fixed values added to all the stats of the proxy, keyed by tag name
+	StatsTags map[string]string
 }
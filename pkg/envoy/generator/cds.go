@@ -15,7 +15,9 @@ import (
 // generateRDS creates a new Cluster Discovery Response.
 func (g *EnvoyConfigGenerator) generateCDS(ctx context.Context, proxy *models.Proxy) ([]types.Resource, error) {
 	meshConfig := g.catalog.GetMeshConfig()
-	cb := cds.NewClusterBuilder().SetProxyIdentity(proxy.Identity).SetSidecarSpec(meshConfig.Spec.Sidecar).SetEgressEnabled(meshConfig.Spec.Traffic.EnableEgress)
+	// The TLS parameters of the upstream clusters are set by the SidecarProfile of the proxy, if any
+	sidecarConfig := g.catalog.GetSidecarConfig(proxy)
+	cb := cds.NewClusterBuilder().SetProxyIdentity(proxy.Identity).SetSidecarSpec(sidecarConfig.Sidecar).SetEgressEnabled(meshConfig.Spec.Traffic.EnableEgress)
 
 	outboundMeshClusterConfigs := g.catalog.GetOutboundMeshClusterConfigs(proxy.Identity)
 	cb.SetOutboundMeshTrafficClusterConfigs(outboundMeshClusterConfigs)
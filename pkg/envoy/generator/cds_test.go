@@ -77,6 +77,7 @@ func TestGenerateCDS(t *testing.T) {
 
 	mockComputeInterface.EXPECT().IsMetricsEnabled(proxy).Return(true, nil).AnyTimes()
 	mockComputeInterface.EXPECT().GetMeshConfig().Return(meshConfig).AnyTimes()
+	mockComputeInterface.EXPECT().GetSidecarConfig(proxy).Return(models.SidecarConfig{Sidecar: meshConfig.Spec.Sidecar}).AnyTimes()
 	mockComputeInterface.EXPECT().ListServicesForProxy(proxy).Return([]service.MeshService{testMeshSvc}, nil).AnyTimes()
 	mockComputeInterface.EXPECT().ListTrafficSplits().Return(
 		[]*split.TrafficSplit{
@@ -418,6 +419,7 @@ func TestNewResponseListServicesError(t *testing.T) {
 		},
 	}
 	mockComputeInterface.EXPECT().GetMeshConfig().Return(meshConfig).AnyTimes()
+	mockComputeInterface.EXPECT().GetSidecarConfig(proxy).Return(models.SidecarConfig{Sidecar: meshConfig.Spec.Sidecar}).AnyTimes()
 	mockComputeInterface.EXPECT().ListTrafficTargets().Return([]*access.TrafficTarget{&tests.TrafficTarget, &tests.BookstoreV2TrafficTarget}).AnyTimes()
 	mockComputeInterface.EXPECT().GetServicesForServiceIdentity(gomock.Any()).Return([]service.MeshService{
 		tests.BookstoreV1Service, tests.BookstoreV2Service,
@@ -442,6 +444,7 @@ func TestNewResponseGetEgressClusterConfigsError(t *testing.T) {
 	meshCatalog := catalogFake.NewFakeMeshCatalog(mockComputeInterface)
 
 	mockComputeInterface.EXPECT().GetMeshConfig().AnyTimes()
+	mockComputeInterface.EXPECT().GetSidecarConfig(proxy).Return(models.SidecarConfig{}).AnyTimes()
 	mockComputeInterface.EXPECT().ListTrafficTargets().Return([]*access.TrafficTarget{&tests.TrafficTarget, &tests.BookstoreV2TrafficTarget}).AnyTimes()
 	mockComputeInterface.EXPECT().ListEgressPoliciesForServiceAccount(proxyIdentity.ToK8sServiceAccount()).Return(nil).AnyTimes()
 	mockComputeInterface.EXPECT().IsMetricsEnabled(proxy).Return(false, nil).AnyTimes()
@@ -522,6 +525,7 @@ func TestNewResponseGetEgressTrafficPolicyNotEmpty(t *testing.T) {
 		},
 	}
 	mockComputeInterface.EXPECT().GetMeshConfig().AnyTimes()
+	mockComputeInterface.EXPECT().GetSidecarConfig(proxy).Return(models.SidecarConfig{}).AnyTimes()
 	mockComputeInterface.EXPECT().ListTrafficTargets().Return(nil).AnyTimes()
 	mockComputeInterface.EXPECT().ListServicesForProxy(proxy).Return(nil, nil).AnyTimes()
 	mockComputeInterface.EXPECT().ListEgressPoliciesForServiceAccount(proxyIdentity.ToK8sServiceAccount()).Return(egressPolicies).AnyTimes()
@@ -15,11 +15,12 @@ import (
 func (g *EnvoyConfigGenerator) generateEDS(ctx context.Context, proxy *models.Proxy) ([]types.Resource, error) {
 	meshSvcEndpoints := make(map[service.MeshService][]endpoint.Endpoint)
 	builder := eds.NewEndpointsBuilder()
+	zone := g.catalog.GetZoneForProxy(proxy)
 
 	for _, dstSvc := range g.catalog.ListOutboundServicesForIdentity(proxy.Identity) {
 		builder.AddEndpoints(
 			dstSvc,
-			g.catalog.ListAllowedUpstreamEndpointsForService(proxy.Identity, dstSvc),
+			endpoint.FilterByZoneHints(g.catalog.ListAllowedUpstreamEndpointsForService(proxy.Identity, dstSvc), zone),
 		)
 
 		log.Trace().Msgf("Allowed outbound service endpoints for proxy with identity %s: %v", proxy.Identity, meshSvcEndpoints)
@@ -77,6 +77,10 @@ func newClusterLoadAssignment(svc service.MeshService, serviceEndpoints []endpoi
 				},
 			},
 		}
+		// Terminating endpoints don't receive new requests, but keep serving the connections established to them
+		if meshEndpoint.Terminating {
+			lbEpt.HealthStatus = xds_core.HealthStatus_DRAINING
+		}
 
 		// Endpoint without a weight set implies it belongs to the local cluster
 		if meshEndpoint.Weight == 0 {
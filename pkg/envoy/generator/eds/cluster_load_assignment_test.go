@@ -56,6 +56,41 @@ func TestNewClusterLoadAssignment(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "terminating endpoints are drained",
+			svc:  service.MeshService{Namespace: "ns1", Name: "bookstore-1", TargetPort: 80},
+			endpoints: []endpoint.Endpoint{
+				{IP: net.ParseIP("1.1.1.1"), Port: 80},
+				{IP: net.ParseIP("2.2.2.2"), Port: 80, Terminating: true},
+			},
+			expected: &xds_endpoint.ClusterLoadAssignment{
+				ClusterName: "ns1/bookstore-1|80",
+				Endpoints: []*xds_endpoint.LocalityLbEndpoints{
+					{
+						Locality: &xds_core.Locality{
+							Zone: localZone,
+						},
+						LbEndpoints: []*xds_endpoint.LbEndpoint{
+							{
+								HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
+									Endpoint: &xds_endpoint.Endpoint{
+										Address: envoy.GetAddress("1.1.1.1", 80),
+									},
+								},
+							},
+							{
+								HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
+									Endpoint: &xds_endpoint.Endpoint{
+										Address: envoy.GetAddress("2.2.2.2", 80),
+									},
+								},
+								HealthStatus: xds_core.HealthStatus_DRAINING,
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name:      "no endpoints for cluster",
 			svc:       service.MeshService{Namespace: "ns1", Name: "bookstore-1", TargetPort: 80},
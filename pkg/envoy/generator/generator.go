@@ -6,6 +6,9 @@ import (
 	"time"
 
 	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/openservicemesh/osm/pkg/catalog"
 	"github.com/openservicemesh/osm/pkg/certificate"
@@ -13,6 +16,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/errcode"
 	"github.com/openservicemesh/osm/pkg/logger"
 	"github.com/openservicemesh/osm/pkg/models"
+	"github.com/openservicemesh/osm/pkg/tracing"
 )
 
 var (
@@ -56,7 +60,17 @@ func (g *EnvoyConfigGenerator) GenerateConfig(ctx context.Context, proxy *models
 		}
 
 		startedAt := time.Now()
-		resources, err := handler(ctx, proxy)
+		typeCtx, span := tracing.Start(ctx, "generate"+typeURI.Short(), trace.WithAttributes(
+			tracing.ProxyUUIDKey.String(proxy.UUID.String()),
+			tracing.TypeURLKey.String(typeURI.String()),
+		))
+		resources, err := handler(typeCtx, proxy)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "error generating the resources")
+		}
+		span.SetAttributes(attribute.Int("osm.xds.resource_count", len(resources)))
+		span.End()
 		xdsPathTimeTrack(startedAt, typeURI, proxy, err == nil)
 		if err != nil {
 			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGeneratingReqResource)).Str("proxy", proxy.String()).
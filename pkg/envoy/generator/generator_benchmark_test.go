@@ -8,14 +8,17 @@ import (
 
 	"github.com/google/uuid"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sClientFake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
 
 	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
 	"github.com/openservicemesh/osm/pkg/catalog"
 	tresorFake "github.com/openservicemesh/osm/pkg/certificate/providers/tresor/fake"
 	"github.com/openservicemesh/osm/pkg/compute/kube"
 	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy/server"
 	configFake "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned/fake"
 	policyFake "github.com/openservicemesh/osm/pkg/gen/client/policy/clientset/versioned/fake"
 	"github.com/openservicemesh/osm/pkg/k8s"
@@ -73,8 +76,19 @@ func setupTestGenerator(b *testing.B) (*models.Proxy, *EnvoyConfigGenerator) {
 	}
 
 	svc := tests.NewServiceFixture(tests.BookstoreV1ServiceName, namespace, labels)
+	// The target port of the service is resolved from its endpoints
+	endpointSlice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      svc.Name + "-1",
+			Namespace: namespace,
+			Labels:    map[string]string{discoveryv1.LabelServiceName: svc.Name},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints:   []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.2"}}},
+		Ports:       []discoveryv1.EndpointPort{{Name: pointer.String(svc.Spec.Ports[0].Name), Port: pointer.Int32(tests.ServicePort)}},
+	}
 
-	kubeClient := k8sClientFake.NewSimpleClientset(nsObj, pod, svc)
+	kubeClient := k8sClientFake.NewSimpleClientset(nsObj, pod, svc, endpointSlice)
 	configClient := configFake.NewSimpleClientset(&meshConfig)
 	policyClient := policyFake.NewSimpleClientset()
 
@@ -121,3 +135,24 @@ func BenchmarkSendXDSResponse(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkUpdateProxy(b *testing.B) {
+	if err := logger.SetLogLevel("error"); err != nil {
+		b.Logf("Failed to set log level to error: %s", err)
+	}
+
+	proxy, g := setupTestGenerator(b)
+	resources, err := g.GenerateConfig(context.Background(), proxy)
+	if err != nil {
+		b.Fatalf("Failed to generate config: %s", err)
+	}
+	xdsServer := server.NewADSServer()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Every update versions all resources by their content
+		if err := xdsServer.UpdateProxy(context.Background(), proxy, resources); err != nil {
+			b.Fatalf("Failed to update proxy: %s", err)
+		}
+	}
+}
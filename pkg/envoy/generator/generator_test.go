@@ -37,6 +37,8 @@ func TestGenerateConfig(t *testing.T) {
 	provider.EXPECT().GetIngressBackendPolicyForService(gomock.Any()).Return(nil).AnyTimes()
 	provider.EXPECT().GetUpstreamTrafficSettingByService(gomock.Any()).Return(nil).AnyTimes()
 	provider.EXPECT().GetUpstreamTrafficSettingByNamespace(gomock.Any()).Return(nil).AnyTimes()
+	provider.EXPECT().GetZoneForProxy(gomock.Any()).Return("").AnyTimes()
+	provider.EXPECT().ListIPsForProxy(gomock.Any()).Return(nil).AnyTimes()
 	provider.EXPECT().GetMeshConfig().Return(configv1alpha2.MeshConfig{
 		Spec: configv1alpha2.MeshConfigSpec{
 			Traffic: configv1alpha2.TrafficSpec{
@@ -76,6 +78,8 @@ func TestGenerateConfig(t *testing.T) {
 	}).AnyTimes()
 	provider.EXPECT().ListTrafficSplits().Return(nil).AnyTimes()
 	provider.EXPECT().GetTelemetryConfig(gomock.Any()).Return(models.TelemetryConfig{}).AnyTimes()
+	provider.EXPECT().GetSidecarConfig(gomock.Any()).Return(models.SidecarConfig{}).AnyTimes()
+	provider.EXPECT().ListTrustDomainFederations().Return(nil).AnyTimes()
 
 	certManager := tresorFake.NewFake(time.Hour)
 	stop := make(chan struct{})
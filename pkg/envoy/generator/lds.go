@@ -3,6 +3,7 @@ package generator
 import (
 	"context"
 	"fmt"
+	"net"
 
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
@@ -19,6 +20,8 @@ import (
 // 1. Inbound listener to handle incoming traffic
 // 2. Outbound listener to handle outgoing traffic
 // 3. Prometheus listener for metrics
+// Each listener is bound to the IPv4 wildcard address, and to the IPv6 one as well when the proxy's workload has an
+// IPv6 address.
 func (g *EnvoyConfigGenerator) generateLDS(ctx context.Context, proxy *models.Proxy) ([]types.Resource, error) {
 	var ldsResources []types.Resource
 
@@ -39,6 +42,8 @@ func (g *EnvoyConfigGenerator) generateLDS(ctx context.Context, proxy *models.Pr
 		}
 	}
 
+	ipv6 := hasIPv6Address(g.catalog.ListIPsForProxy(proxy))
+
 	accessLogs, err := lds.BuildAccessLogs(proxy.String(), g.catalog.GetTelemetryConfig(proxy))
 	if err != nil {
 		log.Error().Err(err).Msgf("Error building access log config for proxy %s", proxy)
@@ -82,6 +87,9 @@ func (g *EnvoyConfigGenerator) generateLDS(ctx context.Context, proxy *models.Pr
 		log.Debug().Str("proxy", proxy.String()).Msg("Not programming nil outbound listener")
 	} else {
 		ldsResources = append(ldsResources, outboundListener)
+		if ipv6 {
+			ldsResources = append(ldsResources, lds.BuildIPv6Listener(outboundListener))
+		}
 	}
 
 	// --- INBOUND -------------------
@@ -95,7 +103,7 @@ func (g *EnvoyConfigGenerator) generateLDS(ctx context.Context, proxy *models.Pr
 		PermissiveMesh(meshConfig.Spec.Traffic.EnablePermissiveTrafficPolicyMode).
 		InboundMeshTrafficMatches(g.catalog.GetInboundMeshTrafficMatches(svcList)).
 		ActiveHealthCheck(meshConfig.Spec.FeatureFlags.EnableEnvoyActiveHealthChecks).
-		SidecarSpec(meshConfig.Spec.Sidecar).
+		SidecarSpec(g.catalog.GetSidecarConfig(proxy).Sidecar).
 		AccessLogs(accessLogs)
 
 	trafficTargets, err := g.catalog.ListInboundTrafficTargetsWithRoutes(proxy.Identity)
@@ -123,6 +131,9 @@ func (g *EnvoyConfigGenerator) generateLDS(ctx context.Context, proxy *models.Pr
 	}
 	if inboundListener != nil {
 		ldsResources = append(ldsResources, inboundListener)
+		if ipv6 {
+			ldsResources = append(ldsResources, lds.BuildIPv6Listener(inboundListener))
+		}
 	}
 
 	if enabled, err := g.catalog.IsMetricsEnabled(proxy); err != nil {
@@ -133,8 +144,21 @@ func (g *EnvoyConfigGenerator) generateLDS(ctx context.Context, proxy *models.Pr
 			log.Error().Err(err).Str("proxy", proxy.String()).Msgf("Error building Prometheus listener")
 		} else {
 			ldsResources = append(ldsResources, prometheusListener)
+			if ipv6 {
+				ldsResources = append(ldsResources, lds.BuildIPv6Listener(prometheusListener))
+			}
 		}
 	}
 
 	return ldsResources, nil
 }
+
+// hasIPv6Address returns whether one of the given IP addresses is an IPv6 address
+func hasIPv6Address(ips []net.IP) bool {
+	for _, ip := range ips {
+		if ip.To4() == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -9,6 +9,7 @@ import (
 	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	xds_tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
 	xds_type "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 
 	"github.com/openservicemesh/osm/pkg/constants"
@@ -32,8 +33,21 @@ const (
 	OutboundEgressFilterChainName = "outbound-egress-filter-chain"
 
 	egressTCPProxyStatPrefix = "egress-tcp-proxy"
+
+	// ipv6ListenerNameSuffix is the suffix of the name of a listener bound to the IPv6 wildcard address
+	ipv6ListenerNameSuffix = "-ipv6"
 )
 
+// BuildIPv6Listener returns a copy of the given listener bound to the IPv6 wildcard address on the same port, to
+// handle the IPv6 traffic of dual-stack and IPv6 workloads. Both listeners can be bound to the same port as the IPv6
+// listener doesn't accept IPv4 connections.
+func BuildIPv6Listener(listener *xds_listener.Listener) *xds_listener.Listener {
+	ipv6Listener := proto.Clone(listener).(*xds_listener.Listener)
+	ipv6Listener.Name += ipv6ListenerNameSuffix
+	ipv6Listener.Address = envoy.GetAddress(constants.WildcardIPv6Addr, listener.GetAddress().GetSocketAddress().GetPortValue())
+	return ipv6Listener
+}
+
 // BuildPrometheusListener builds the envoy configuration for the Prometheus listener.
 func BuildPrometheusListener(accessLogs []*xds_accesslog.AccessLog) (*xds_listener.Listener, error) {
 	marshalledConnManager, err := anypb.New(getPrometheusConnectionManager(accessLogs))
@@ -7,6 +7,8 @@ import (
 	xds_type "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
@@ -22,6 +24,22 @@ func TestBuildPrometheusListener(t *testing.T) {
 	a.Nil(err)
 }
 
+func TestBuildIPv6Listener(t *testing.T) {
+	a := assert.New(t)
+
+	listener, err := BuildPrometheusListener(nil)
+	a.Nil(err)
+
+	ipv6Listener := BuildIPv6Listener(listener)
+	a.Equal(PrometheusListenerName+"-ipv6", ipv6Listener.Name)
+	a.Equal(envoy.GetAddress(constants.WildcardIPv6Addr, constants.EnvoyPrometheusInboundListenerPort), ipv6Listener.Address)
+	a.Equal(listener.FilterChains, ipv6Listener.FilterChains)
+
+	// The given listener is left unchanged
+	a.Equal(PrometheusListenerName, listener.Name)
+	a.Equal(envoy.GetAddress(constants.WildcardIPAddr, constants.EnvoyPrometheusInboundListenerPort), listener.Address)
+}
+
 func TestGetFilterMatchPredicateForPorts(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -59,7 +59,7 @@ func (fb *filterBuilder) buildRBACPolicyFromTrafficTarget(trafficTarget trafficp
 	// Create the list of identities for this policy
 	for _, downstreamIdentity := range trafficTarget.Sources {
 		pb.AddPrincipal(downstreamIdentity.AsPrincipal(fb.issuers.Signing.TrustDomain, fb.issuers.Signing.SpiffeEnabled))
-		if fb.issuers.AreDifferent() {
+		if fb.issuers.AreDifferent() && !downstreamIdentity.IsSpiffeID() {
 			pb.AddPrincipal(downstreamIdentity.AsPrincipal(fb.issuers.Validating.TrustDomain, fb.issuers.Validating.SpiffeEnabled))
 		}
 	}
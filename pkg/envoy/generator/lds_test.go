@@ -2,11 +2,13 @@ package generator
 
 import (
 	"context"
+	"net"
 	"testing"
 	"time"
 
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	tassert "github.com/stretchr/testify/assert"
@@ -52,6 +54,11 @@ func TestGenerateLDS(t *testing.T) {
 	provider.EXPECT().GetUpstreamTrafficSettingByService(gomock.Any()).Return(nil).AnyTimes()
 	provider.EXPECT().GetUpstreamTrafficSettingByNamespace(gomock.Any()).Return(nil).AnyTimes()
 	provider.EXPECT().GetTelemetryConfig(gomock.Any()).Return(models.TelemetryConfig{}).AnyTimes()
+	provider.EXPECT().GetSidecarConfig(gomock.Any()).Return(models.SidecarConfig{
+		Sidecar: configv1alpha2.SidecarSpec{
+			TLSMinProtocolVersion: "TLSv1_3",
+		},
+	}).AnyTimes()
 	provider.EXPECT().GetMeshService(gomock.Any(), gomock.Any(), gomock.Any()).
 		DoAndReturn(
 			func(name, ns string, port uint16) (service.MeshService, error) {
@@ -74,6 +81,7 @@ func TestGenerateLDS(t *testing.T) {
 	provider.EXPECT().GetResolvableEndpointsForService(gomock.Any()).Return([]endpoint.Endpoint{tests.Endpoint}).AnyTimes()
 	provider.EXPECT().GetHostnamesForService(gomock.Any(), gomock.Any()).Return([]string{"dummy-hostname"}).AnyTimes()
 	provider.EXPECT().IsMetricsEnabled(gomock.Any()).Return(true, nil).AnyTimes()
+	provider.EXPECT().ListIPsForProxy(gomock.Any()).Return([]net.IP{net.ParseIP("10.0.0.1")}).AnyTimes()
 	provider.EXPECT().GetMeshConfig().Return(configv1alpha2.MeshConfig{
 		Spec: configv1alpha2.MeshConfigSpec{
 			Traffic: configv1alpha2.TrafficSpec{
@@ -146,6 +154,10 @@ func TestGenerateLDS(t *testing.T) {
 	// There is 1 filter chains configured on the inbound-listner based on the configuration:
 	// 1. Filter chanin for bookbuyer
 	assert.Len(listener.FilterChains, 1)
+	// The TLS parameters of the filter chain are set by the sidecar configuration of the proxy
+	downstreamTLSContext := &xds_auth.DownstreamTlsContext{}
+	assert.NoError(listener.FilterChains[0].TransportSocket.GetTypedConfig().UnmarshalTo(downstreamTLSContext))
+	assert.Equal(xds_auth.TlsParameters_TLSv1_3, downstreamTLSContext.CommonTlsContext.TlsParams.TlsMinimumProtocolVersion)
 
 	// validating prometheus listener
 	listener, ok = resources[2].(*xds_listener.Listener)
@@ -155,3 +167,12 @@ func TestGenerateLDS(t *testing.T) {
 	assert.NotNil(listener.FilterChains)
 	assert.Len(listener.FilterChains, 1)
 }
+
+func TestHasIPv6Address(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.False(hasIPv6Address(nil))
+	assert.False(hasIPv6Address([]net.IP{net.ParseIP("10.0.0.1")}))
+	assert.True(hasIPv6Address([]net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("fd00::1")}))
+	assert.True(hasIPv6Address([]net.IP{net.ParseIP("fd00::1")}))
+}
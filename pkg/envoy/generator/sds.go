@@ -10,6 +10,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/models"
 	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/spiffe"
 )
 
 // NewResponse creates a new Secrets Discovery Response.
@@ -17,7 +18,8 @@ func (g *EnvoyConfigGenerator) generateSDS(ctx context.Context, proxy *models.Pr
 	log.Info().Str("proxy", proxy.String()).Msg("Composing SDS Discovery Response")
 
 	// sdsBuilder: builds the Secret Discovery Response
-	builder := sds.NewBuilder().SetProxy(proxy).SetIssuers(g.certManager.GetIssuersInfo())
+	builder := sds.NewBuilder().SetProxy(proxy).SetIssuers(g.certManager.GetIssuersInfo()).
+		SetFederatedTrustBundles(spiffe.GetFederatedTrustBundles(g.catalog.ListTrustDomainFederations()))
 
 	// 1. Issue a service certificate for this proxy
 	cert, err := g.certManager.IssueCertificate(certificate.ForServiceIdentity(proxy.Identity))
@@ -1,11 +1,15 @@
 package sds
 
 import (
+	"sort"
+
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	xds_matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"google.golang.org/protobuf/types/known/anypb"
 
 	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/certificate/pem"
 	"github.com/openservicemesh/osm/pkg/envoy/secrets"
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/models"
@@ -21,6 +25,9 @@ type SecretsBuilder struct {
 
 	issuers certificate.IssuerInfo
 
+	// PEM encoded CAs of the foreign trust domains federated with the mesh, keyed by trust domain
+	federatedTrustBundles map[string]pem.RootCertificate
+
 	// identities, used for SAN matches, mapped to the name of the secret. Currently only used for outbound secrets.
 	identitiesForSecrets map[string][]identity.ServiceIdentity
 }
@@ -48,6 +55,13 @@ func (b *SecretsBuilder) SetIssuers(issuers certificate.IssuerInfo) *SecretsBuil
 	return b
 }
 
+// SetFederatedTrustBundles sets the CAs, keyed by trust domain, of the foreign trust domains whose workloads are
+// allowed to connect to the proxy.
+func (b *SecretsBuilder) SetFederatedTrustBundles(trustBundles map[string]pem.RootCertificate) *SecretsBuilder {
+	b.federatedTrustBundles = trustBundles
+	return b
+}
+
 // SetServiceIdentitiesForService setes the list of identities for each service, to be used for SAN validation.
 func (b *SecretsBuilder) SetServiceIdentitiesForService(serviceIdentitiesForServices map[service.MeshService][]identity.ServiceIdentity) *SecretsBuilder {
 	b.identitiesForSecrets = make(map[string][]identity.ServiceIdentity)
@@ -69,10 +83,12 @@ func (b *SecretsBuilder) Build() []*xds_auth.Secret {
 	// 2. The same root validation certificate is used to validate both in-mesh and ingress downstreams.
 	// For these reasons, we only perform SAN validation of peer certificates on downstream clients (ie. outbound SAN
 	// validation).
-	sdsResources = append(sdsResources, b.buildSecret(secrets.NameForMTLSInbound, nil))
+	// Downstream clients from federated trust domains present certificates issued by their own CAs, so those CAs
+	// are only trusted by the inbound validation context.
+	sdsResources = append(sdsResources, b.buildInboundSecret())
 
 	for name, identities := range b.identitiesForSecrets {
-		sdsResources = append(sdsResources, b.buildSecret(name, identities))
+		sdsResources = append(sdsResources, b.buildSecret(name, b.serviceCert.GetTrustedCAs(), identities))
 	}
 	return sdsResources
 }
@@ -100,7 +116,67 @@ func (b *SecretsBuilder) buildServiceSecret() *xds_auth.Secret {
 	}
 }
 
-func (b *SecretsBuilder) buildSecret(name string, allowedIdentities []identity.ServiceIdentity) *xds_auth.Secret {
+// buildInboundSecret creates the validation context of downstream clients. When trust domains are federated with the
+// mesh, the SPIFFE certificate validator binds the CAs of each trust domain to the SPIFFE IDs of that trust domain, so
+// the CA of a federated trust domain cannot authenticate an identity of the mesh or of another federated trust domain.
+func (b *SecretsBuilder) buildInboundSecret() *xds_auth.Secret {
+	secret := b.buildSecret(secrets.NameForMTLSInbound, b.serviceCert.GetTrustedCAs(), nil)
+	if len(b.federatedTrustBundles) == 0 {
+		return secret
+	}
+
+	// The SPIFFE certificate validator only looks at URI SANs, which mesh certificates only have when SPIFFE is enabled
+	if !b.issuers.Signing.SpiffeEnabled || !b.issuers.Validating.SpiffeEnabled {
+		log.Warn().Str("proxy", b.proxy.String()).
+			Msg("Federated trust domains require SPIFFE to be enabled on the MeshRootCertificates, not trusting their CAs")
+		return secret
+	}
+
+	meshTrustDomains := map[string]bool{b.issuers.Signing.TrustDomain: true, b.issuers.Validating.TrustDomain: true}
+	var trustDomains []*xds_auth.SPIFFECertValidatorConfig_TrustDomain
+	for trustDomain := range meshTrustDomains {
+		trustDomains = append(trustDomains, newSpiffeTrustDomain(trustDomain, b.serviceCert.GetTrustedCAs()))
+	}
+	for trustDomain, cas := range b.federatedTrustBundles {
+		if meshTrustDomains[trustDomain] {
+			log.Warn().Str("proxy", b.proxy.String()).
+				Msgf("Federated trust domain %s is a trust domain of the mesh, not trusting its CAs", trustDomain)
+			continue
+		}
+		trustDomains = append(trustDomains, newSpiffeTrustDomain(trustDomain, cas))
+	}
+	sort.Slice(trustDomains, func(i, j int) bool {
+		return trustDomains[i].Name < trustDomains[j].Name
+	})
+
+	validatorConfig, err := anypb.New(&xds_auth.SPIFFECertValidatorConfig{TrustDomains: trustDomains})
+	if err != nil {
+		log.Error().Err(err).Str("proxy", b.proxy.String()).
+			Msg("Error marshalling SPIFFE certificate validator config, not trusting the CAs of federated trust domains")
+		return secret
+	}
+
+	validationContext := secret.GetValidationContext()
+	validationContext.TrustedCa = nil
+	validationContext.CustomValidatorConfig = &xds_core.TypedExtensionConfig{
+		Name:        spiffeCertValidatorName,
+		TypedConfig: validatorConfig,
+	}
+	return secret
+}
+
+func newSpiffeTrustDomain(trustDomain string, cas []byte) *xds_auth.SPIFFECertValidatorConfig_TrustDomain {
+	return &xds_auth.SPIFFECertValidatorConfig_TrustDomain{
+		Name: trustDomain,
+		TrustBundle: &xds_core.DataSource{
+			Specifier: &xds_core.DataSource_InlineBytes{
+				InlineBytes: cas,
+			},
+		},
+	}
+}
+
+func (b *SecretsBuilder) buildSecret(name string, trustedCAs []byte, allowedIdentities []identity.ServiceIdentity) *xds_auth.Secret {
 	secret := &xds_auth.Secret{
 		// The Name field must match the tls_context.common_tls_context.tls_certificate_sds_secret_configs.name
 		Name: name,
@@ -108,7 +184,7 @@ func (b *SecretsBuilder) buildSecret(name string, allowedIdentities []identity.S
 			ValidationContext: &xds_auth.CertificateValidationContext{
 				TrustedCa: &xds_core.DataSource{
 					Specifier: &xds_core.DataSource_InlineBytes{
-						InlineBytes: b.serviceCert.GetTrustedCAs(),
+						InlineBytes: trustedCAs,
 					},
 				},
 			},
@@ -1,15 +1,22 @@
 package sds
 
 import (
+	"crypto/x509"
 	"fmt"
 	"testing"
+	"time"
 
 	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	xds_matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
 	"github.com/google/uuid"
+	"github.com/spiffe/go-spiffe/v2/bundle/x509bundle"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
 	tassert "github.com/stretchr/testify/assert"
 
 	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/certificate/pem"
+	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
 	"github.com/openservicemesh/osm/pkg/envoy/secrets"
 	"github.com/openservicemesh/osm/pkg/models"
 
@@ -179,6 +186,89 @@ func TestSecretsBuilder(t *testing.T) {
 	}
 }
 
+func TestSecretsBuilderFederatedTrustDomains(t *testing.T) {
+	assert := tassert.New(t)
+
+	newIssuer := func(cn certificate.CommonName) (*tresor.CertManager, pem.RootCertificate) {
+		ca, err := tresor.NewCA(cn, time.Hour, "US", "Seattle", "Open Service Mesh")
+		assert.NoError(err)
+		issuer, err := tresor.New(ca, "Open Service Mesh", 2048)
+		assert.NoError(err)
+		return issuer, pem.RootCertificate(ca.GetCertificateChain())
+	}
+	meshIssuer, meshCA := newIssuer("osm-ca.openservicemesh.io")
+	foreignIssuer, foreignCA := newIssuer("other-domain-ca")
+
+	issuers := certificate.IssuerInfo{
+		Signing:    certificate.PrincipalInfo{TrustDomain: "cluster.local", SpiffeEnabled: true},
+		Validating: certificate.PrincipalInfo{TrustDomain: "cluster.local", SpiffeEnabled: true},
+	}
+	cert := &certificate.Certificate{
+		CertChain:  []byte("foo"),
+		PrivateKey: []byte("foo"),
+		IssuingCA:  meshCA,
+		TrustedCAs: meshCA,
+	}
+	proxy := models.NewProxy(models.KindSidecar, uuid.New(), identity.New("sa-1", "ns-1"), nil, 1)
+
+	build := func(issuers certificate.IssuerInfo, trustBundles map[string]pem.RootCertificate) []*xds_auth.Secret {
+		return NewBuilder().SetProxy(proxy).SetProxyCert(cert).SetIssuers(issuers).SetFederatedTrustBundles(trustBundles).
+			SetServiceIdentitiesForService(map[service.MeshService][]identity.ServiceIdentity{
+				{Name: "service-2", Namespace: "ns-2"}: {identity.New("sa-2", "ns-2")},
+			}).Build()
+	}
+
+	sdsSecrets := build(issuers, map[string]pem.RootCertificate{"other-domain": foreignCA, "cluster.local": foreignCA})
+	assert.Len(sdsSecrets, 3)
+
+	// Only downstream clients may belong to a federated trust domain
+	outboundSecret := sdsSecrets[2]
+	assert.Equal([]byte(meshCA), outboundSecret.GetValidationContext().GetTrustedCa().GetInlineBytes())
+
+	inboundValidationSecret := sdsSecrets[1]
+	assert.Equal(secrets.NameForMTLSInbound, inboundValidationSecret.GetName())
+	validationContext := inboundValidationSecret.GetValidationContext()
+	assert.Nil(validationContext.GetTrustedCa())
+	assert.Equal(spiffeCertValidatorName, validationContext.GetCustomValidatorConfig().GetName())
+
+	validatorConfig := &xds_auth.SPIFFECertValidatorConfig{}
+	assert.NoError(validationContext.GetCustomValidatorConfig().GetTypedConfig().UnmarshalTo(validatorConfig))
+	// A federated trust domain cannot take over the trust domain of the mesh
+	trustBundles := x509bundle.NewSet()
+	var trustDomains []string
+	for _, trustDomain := range validatorConfig.GetTrustDomains() {
+		trustDomains = append(trustDomains, trustDomain.GetName())
+		bundle, err := x509bundle.Parse(spiffeid.RequireTrustDomainFromString(trustDomain.GetName()),
+			trustDomain.GetTrustBundle().GetInlineBytes())
+		assert.NoError(err)
+		trustBundles.Add(bundle)
+	}
+	assert.Equal([]string{"cluster.local", "other-domain"}, trustDomains)
+
+	// Validate peer certificates the way the SPIFFE certificate validator does, against the trust bundle of the
+	// trust domain of their SPIFFE ID
+	verify := func(issuer *tresor.CertManager, trustDomain string) error {
+		peerCert, err := issuer.IssueCertificate(certificate.NewCertOptionsWithTrustDomain("sa-3.ns-3", trustDomain, time.Hour, true))
+		assert.NoError(err)
+		x509Cert, err := certificate.DecodePEMCertificate(peerCert.GetCertificateChain())
+		assert.NoError(err)
+		_, _, err = x509svid.Verify([]*x509.Certificate{x509Cert}, trustBundles)
+		return err
+	}
+	assert.NoError(verify(meshIssuer, "cluster.local"))
+	assert.NoError(verify(foreignIssuer, "other-domain"))
+	// The CA of a federated trust domain cannot authenticate a principal of the mesh
+	assert.Error(verify(foreignIssuer, "cluster.local"))
+	assert.Error(verify(meshIssuer, "other-domain"))
+
+	// Without SPIFFE certificates, the CAs of federated trust domains are not trusted
+	issuers.Validating.SpiffeEnabled = false
+	sdsSecrets = build(issuers, map[string]pem.RootCertificate{"other-domain": foreignCA})
+	validationContext = sdsSecrets[1].GetValidationContext()
+	assert.Nil(validationContext.GetCustomValidatorConfig())
+	assert.Equal([]byte(meshCA), validationContext.GetTrustedCa().GetInlineBytes())
+}
+
 func TestGetSubjectAltNamesFromSvcAccount(t *testing.T) {
 	type testCase struct {
 		serviceIdentities   []identity.ServiceIdentity
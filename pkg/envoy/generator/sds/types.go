@@ -0,0 +1,14 @@
+// Package sds implements Envoy's Secret Discovery Service (SDS).
+package sds
+
+import (
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var (
+	log = logger.New("envoy/sds")
+)
+
+// spiffeCertValidatorName is the name of Envoy's SPIFFE certificate validator, which validates a peer certificate
+// against the trust bundle of the trust domain of its SPIFFE ID.
+const spiffeCertValidatorName = "envoy.tls.cert_validator.spiffe"
@@ -94,6 +94,7 @@ func TestGenerateSDS(t *testing.T) {
 				},
 			})
 			mockComputeInterface.EXPECT().ListServices().Return(services)
+			mockComputeInterface.EXPECT().ListTrustDomainFederations().Return(nil)
 
 			g := NewEnvoyConfigGenerator(meshCatalog, certManager)
 
@@ -0,0 +1,75 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// diffJSON returns the fields that differ between the given JSON documents
+func diffJSON(from, to json.RawMessage) ([]FieldDiff, error) {
+	var fromValue, toValue interface{}
+	if err := json.Unmarshal(from, &fromValue); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(to, &toValue); err != nil {
+		return nil, err
+	}
+	return diffValues("", fromValue, toValue, nil), nil
+}
+
+// diffValues appends the fields that differ between the given decoded JSON values at the given path to diffs.
+// Objects are compared field by field and arrays element by element, other values as a whole.
+func diffValues(path string, from, to interface{}, diffs []FieldDiff) []FieldDiff {
+	switch fromValue := from.(type) {
+	case map[string]interface{}:
+		toValue, ok := to.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make(map[string]struct{}, len(fromValue)+len(toValue))
+		for key := range fromValue {
+			keys[key] = struct{}{}
+		}
+		for key := range toValue {
+			keys[key] = struct{}{}
+		}
+		sortedKeys := make([]string, 0, len(keys))
+		for key := range keys {
+			sortedKeys = append(sortedKeys, key)
+		}
+		sort.Strings(sortedKeys)
+
+		for _, key := range sortedKeys {
+			keyPath := key
+			if path != "" {
+				keyPath = path + "." + key
+			}
+			diffs = diffValues(keyPath, fromValue[key], toValue[key], diffs)
+		}
+		return diffs
+
+	case []interface{}:
+		toValue, ok := to.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < len(fromValue) || i < len(toValue); i++ {
+			var fromElem, toElem interface{}
+			if i < len(fromValue) {
+				fromElem = fromValue[i]
+			}
+			if i < len(toValue) {
+				toElem = toValue[i]
+			}
+			diffs = diffValues(fmt.Sprintf("%s[%d]", path, i), fromElem, toElem, diffs)
+		}
+		return diffs
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		diffs = append(diffs, FieldDiff{Path: path, From: from, To: to})
+	}
+	return diffs
+}
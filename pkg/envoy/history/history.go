@@ -0,0 +1,234 @@
+package history
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/messaging"
+)
+
+// NewStore returns a store keeping the last size versions of the configuration of every proxy
+func NewStore(size int) *Store {
+	return &Store{
+		size:    size,
+		proxies: make(map[string]*proxyHistory),
+	}
+}
+
+// Record records a version of the configuration of the proxy with the given UUID, given its resources and their
+// versions keyed by type URL and name, unless it doesn't differ from the previous version. The content of the
+// secrets is never kept.
+func (s *Store) Record(proxyUUID string, resources map[string][]types.Resource, versions map[string]map[string]string, triggers []messaging.UpdateTrigger) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, ok := s.proxies[proxyUUID]
+	if !ok {
+		h = &proxyHistory{
+			nextVersion: 1,
+			contents:    make(map[resourceKey]*content),
+		}
+		s.proxies[proxyUUID] = h
+	}
+
+	var previous map[string]map[string]string
+	if len(h.entries) > 0 {
+		previous = h.entries[len(h.entries)-1].versions
+	}
+	changes := getChanges(previous, versions)
+	if len(h.entries) > 0 && len(changes) == 0 {
+		return
+	}
+
+	e := &entry{
+		Entry: Entry{
+			Version:  h.nextVersion,
+			Time:     time.Now(),
+			Triggers: triggers,
+			Changes:  changes,
+		},
+		versions: versions,
+	}
+	h.nextVersion++
+
+	for typeURL, typeResources := range resources {
+		for _, resource := range typeResources {
+			name := cachev3.GetResourceName(resource)
+			key := resourceKey{typeURL: typeURL, name: name, version: versions[typeURL][name]}
+			if c, ok := h.contents[key]; ok {
+				c.refs++
+				continue
+			}
+			c := &content{refs: 1}
+			if envoy.TypeURI(typeURL) != envoy.TypeSDS {
+				marshaled, err := protojson.Marshal(resource)
+				if err != nil {
+					log.Error().Err(err).Msgf("Error marshaling %s resource %s of proxy %s", typeURL, name, proxyUUID)
+				}
+				c.json = marshaled
+			}
+			h.contents[key] = c
+		}
+	}
+
+	h.entries = append(h.entries, e)
+	if len(h.entries) > s.size {
+		h.release(h.entries[0])
+		h.entries = h.entries[1:]
+	}
+}
+
+// release releases the contents referenced by the given entry
+func (h *proxyHistory) release(e *entry) {
+	for typeURL, typeVersions := range e.versions {
+		for name, version := range typeVersions {
+			key := resourceKey{typeURL: typeURL, name: name, version: version}
+			if c, ok := h.contents[key]; ok {
+				c.refs--
+				if c.refs <= 0 {
+					delete(h.contents, key)
+				}
+			}
+		}
+	}
+}
+
+// Remove removes the history of the proxy with the given UUID
+func (s *Store) Remove(proxyUUID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.proxies, proxyUUID)
+}
+
+// List returns the versions of the configuration of the proxy with the given UUID, oldest first
+func (s *Store) List(proxyUUID string) ([]Entry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	h, ok := s.proxies[proxyUUID]
+	if !ok {
+		return nil, fmt.Errorf("history of proxy %s %w", proxyUUID, ErrNotFound)
+	}
+	entries := make([]Entry, 0, len(h.entries))
+	for _, e := range h.entries {
+		entries = append(entries, e.Entry)
+	}
+	return entries, nil
+}
+
+// Diff returns the difference between the given versions of the configuration of the proxy with the given UUID. A
+// zero to version is the latest version, and a zero from version is the version preceding the to version.
+func (s *Store) Diff(proxyUUID string, from, to uint64) (*Diff, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	h, ok := s.proxies[proxyUUID]
+	if !ok || len(h.entries) == 0 {
+		return nil, fmt.Errorf("history of proxy %s %w", proxyUUID, ErrNotFound)
+	}
+
+	toIdx := len(h.entries) - 1
+	if to != 0 {
+		toIdx = h.index(to)
+		if toIdx < 0 {
+			return nil, fmt.Errorf("version %d of proxy %s %w", to, proxyUUID, ErrNotFound)
+		}
+	}
+	fromIdx := toIdx - 1
+	if from != 0 {
+		fromIdx = h.index(from)
+		if fromIdx < 0 {
+			return nil, fmt.Errorf("version %d of proxy %s %w", from, proxyUUID, ErrNotFound)
+		}
+	}
+
+	toEntry := h.entries[toIdx]
+	diff := &Diff{To: toEntry.Entry}
+	var fromVersions map[string]map[string]string
+	if fromIdx >= 0 {
+		diff.From = h.entries[fromIdx].Entry
+		fromVersions = h.entries[fromIdx].versions
+	}
+
+	diff.Changes = []ResourceDiff{}
+	for _, change := range getChanges(fromVersions, toEntry.versions) {
+		typeURL := string(change.TypeURL)
+		resourceDiff := ResourceDiff{ResourceChange: change}
+
+		var fromContent, toContent *content
+		if change.Change != Added {
+			fromContent = h.contents[resourceKey{typeURL: typeURL, name: change.Name, version: fromVersions[typeURL][change.Name]}]
+		}
+		if change.Change != Removed {
+			toContent = h.contents[resourceKey{typeURL: typeURL, name: change.Name, version: toEntry.versions[typeURL][change.Name]}]
+		}
+		if (fromContent != nil && fromContent.json == nil) || (toContent != nil && toContent.json == nil) {
+			resourceDiff.Redacted = true
+			diff.Changes = append(diff.Changes, resourceDiff)
+			continue
+		}
+
+		switch change.Change {
+		case Added:
+			resourceDiff.To = toContent.json
+		case Removed:
+			resourceDiff.From = fromContent.json
+		case Modified:
+			fields, err := diffJSON(fromContent.json, toContent.json)
+			if err != nil {
+				return nil, fmt.Errorf("error computing the difference of %s resource %s: %w", typeURL, change.Name, err)
+			}
+			resourceDiff.Fields = fields
+		}
+		diff.Changes = append(diff.Changes, resourceDiff)
+	}
+	return diff, nil
+}
+
+// index returns the index of the entry with the given version, or -1 if not found
+func (h *proxyHistory) index(version uint64) int {
+	for i, e := range h.entries {
+		if e.Version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// getChanges returns the resources that changed between the given resource versions, keyed by type URL and name,
+// sorted by type URL and name
+func getChanges(from, to map[string]map[string]string) []ResourceChange {
+	var changes []ResourceChange
+	for typeURL, toVersions := range to {
+		for name, version := range toVersions {
+			fromVersion, ok := from[typeURL][name]
+			switch {
+			case !ok:
+				changes = append(changes, ResourceChange{TypeURL: envoy.TypeURI(typeURL), Name: name, Change: Added})
+			case fromVersion != version:
+				changes = append(changes, ResourceChange{TypeURL: envoy.TypeURI(typeURL), Name: name, Change: Modified})
+			}
+		}
+	}
+	for typeURL, fromVersions := range from {
+		for name := range fromVersions {
+			if _, ok := to[typeURL][name]; !ok {
+				changes = append(changes, ResourceChange{TypeURL: envoy.TypeURI(typeURL), Name: name, Change: Removed})
+			}
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].TypeURL != changes[j].TypeURL {
+			return changes[i].TypeURL < changes[j].TypeURL
+		}
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
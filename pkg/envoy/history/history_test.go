@@ -0,0 +1,182 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/messaging"
+)
+
+// config returns the resources of a configuration along with their versions, which are given by the caller
+func config(clusters map[string]string, secret string) (map[string][]types.Resource, map[string]map[string]string) {
+	resources := map[string][]types.Resource{}
+	versions := map[string]map[string]string{
+		envoy.TypeCDS.String(): {},
+		envoy.TypeSDS.String(): {"secret": secret},
+	}
+	for name, lbPolicy := range clusters {
+		resources[envoy.TypeCDS.String()] = append(resources[envoy.TypeCDS.String()], &xds_cluster.Cluster{
+			Name:     name,
+			LbPolicy: xds_cluster.Cluster_LbPolicy(xds_cluster.Cluster_LbPolicy_value[lbPolicy]),
+		})
+		versions[envoy.TypeCDS.String()][name] = lbPolicy
+	}
+	resources[envoy.TypeSDS.String()] = []types.Resource{&xds_auth.Secret{Name: "secret"}}
+	return resources, versions
+}
+
+func TestRecord(t *testing.T) {
+	a := tassert.New(t)
+	s := NewStore(2)
+
+	_, err := s.List("p1")
+	a.True(errors.Is(err, ErrNotFound))
+
+	connected := []messaging.UpdateTrigger{{Kind: messaging.TriggerProxyConnected}}
+	resources, versions := config(map[string]string{"a": "ROUND_ROBIN"}, "s1")
+	s.Record("p1", resources, versions, connected)
+
+	// An unchanged configuration is not recorded
+	resources, versions = config(map[string]string{"a": "ROUND_ROBIN"}, "s1")
+	s.Record("p1", resources, versions, nil)
+
+	entries, err := s.List("p1")
+	a.NoError(err)
+	a.Len(entries, 1)
+	a.EqualValues(1, entries[0].Version)
+	a.Equal(connected, entries[0].Triggers)
+	a.Equal([]ResourceChange{
+		{TypeURL: envoy.TypeCDS, Name: "a", Change: Added},
+		{TypeURL: envoy.TypeSDS, Name: "secret", Change: Added},
+	}, entries[0].Changes)
+
+	endpoints := []messaging.UpdateTrigger{{Kind: "Endpoints", Type: "updated", Namespace: "ns", Name: "svc"}}
+	resources, versions = config(map[string]string{"a": "LEAST_REQUEST", "b": "ROUND_ROBIN"}, "s1")
+	s.Record("p1", resources, versions, endpoints)
+	resources, versions = config(map[string]string{"b": "ROUND_ROBIN"}, "s2")
+	s.Record("p1", resources, versions, nil)
+
+	// The oldest version is evicted, along with the contents only it references
+	entries, err = s.List("p1")
+	a.NoError(err)
+	a.Len(entries, 2)
+	a.EqualValues(2, entries[0].Version)
+	a.Equal(endpoints, entries[0].Triggers)
+	a.EqualValues(3, entries[1].Version)
+	a.Len(s.proxies["p1"].contents, 4)
+	a.NotContains(s.proxies["p1"].contents, resourceKey{typeURL: envoy.TypeCDS.String(), name: "a", version: "ROUND_ROBIN"})
+
+	s.Remove("p1")
+	_, err = s.List("p1")
+	a.True(errors.Is(err, ErrNotFound))
+}
+
+func TestDiff(t *testing.T) {
+	a := tassert.New(t)
+	s := NewStore(10)
+
+	_, err := s.Diff("p1", 0, 0)
+	a.True(errors.Is(err, ErrNotFound))
+
+	resources, versions := config(map[string]string{"a": "ROUND_ROBIN", "b": "ROUND_ROBIN"}, "s1")
+	s.Record("p1", resources, versions, nil)
+	resources, versions = config(map[string]string{"a": "LEAST_REQUEST", "c": "ROUND_ROBIN"}, "s2")
+	s.Record("p1", resources, versions, nil)
+
+	// Defaults to the difference between the last two versions
+	diff, err := s.Diff("p1", 0, 0)
+	a.NoError(err)
+	a.EqualValues(1, diff.From.Version)
+	a.EqualValues(2, diff.To.Version)
+	a.Len(diff.Changes, 4)
+
+	a.Equal(ResourceChange{TypeURL: envoy.TypeCDS, Name: "a", Change: Modified}, diff.Changes[0].ResourceChange)
+	a.Equal([]FieldDiff{{Path: "lbPolicy", To: "LEAST_REQUEST"}}, diff.Changes[0].Fields)
+
+	a.Equal(ResourceChange{TypeURL: envoy.TypeCDS, Name: "b", Change: Removed}, diff.Changes[1].ResourceChange)
+	a.JSONEq(`{"name": "b"}`, string(diff.Changes[1].From))
+
+	a.Equal(ResourceChange{TypeURL: envoy.TypeCDS, Name: "c", Change: Added}, diff.Changes[2].ResourceChange)
+	a.JSONEq(`{"name": "c"}`, string(diff.Changes[2].To))
+
+	// The content of the secrets is not kept
+	a.Equal(ResourceChange{TypeURL: envoy.TypeSDS, Name: "secret", Change: Modified}, diff.Changes[3].ResourceChange)
+	a.True(diff.Changes[3].Redacted)
+	a.Nil(diff.Changes[3].Fields)
+
+	// The first version is compared to an empty configuration
+	diff, err = s.Diff("p1", 0, 1)
+	a.NoError(err)
+	a.EqualValues(0, diff.From.Version)
+	a.Len(diff.Changes, 3)
+	for _, change := range diff.Changes {
+		a.Equal(Added, change.Change)
+	}
+
+	// Versions can be compared in any order
+	diff, err = s.Diff("p1", 2, 1)
+	a.NoError(err)
+	a.Equal([]FieldDiff{{Path: "lbPolicy", From: "LEAST_REQUEST"}}, diff.Changes[0].Fields)
+
+	_, err = s.Diff("p1", 5, 0)
+	a.True(errors.Is(err, ErrNotFound))
+}
+
+func TestDiffJSON(t *testing.T) {
+	testCases := []struct {
+		name     string
+		from     string
+		to       string
+		expected []FieldDiff
+	}{
+		{
+			name:     "equal",
+			from:     `{"a": {"b": [1, 2]}}`,
+			to:       `{"a": {"b": [1, 2]}}`,
+			expected: nil,
+		},
+		{
+			name: "nested fields",
+			from: `{"a": {"b": "x", "c": "y"}, "d": 1}`,
+			to:   `{"a": {"b": "z", "c": "y"}, "e": true}`,
+			expected: []FieldDiff{
+				{Path: "a.b", From: "x", To: "z"},
+				{Path: "d", From: float64(1)},
+				{Path: "e", To: true},
+			},
+		},
+		{
+			name: "array elements",
+			from: `{"routes": [{"prefix": "/a"}, {"prefix": "/b"}]}`,
+			to:   `{"routes": [{"prefix": "/a"}, {"prefix": "/c"}, {"prefix": "/d"}]}`,
+			expected: []FieldDiff{
+				{Path: "routes[1].prefix", From: "/b", To: "/c"},
+				{Path: "routes[2]", To: map[string]interface{}{"prefix": "/d"}},
+			},
+		},
+		{
+			name: "type change",
+			from: `{"a": [1]}`,
+			to:   `{"a": {"b": 1}}`,
+			expected: []FieldDiff{
+				{Path: "a", From: []interface{}{float64(1)}, To: map[string]interface{}{"b": float64(1)}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := tassert.New(t)
+			diffs, err := diffJSON(json.RawMessage(tc.from), json.RawMessage(tc.to))
+			a.NoError(err)
+			a.Equal(tc.expected, diffs)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// Package history keeps a bounded history of the configuration generated for every proxy, along with the events
+// that triggered each change, and computes the differences between any two versions of it.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/messaging"
+)
+
+var log = logger.New("envoy/history")
+
+var (
+	// ErrNotFound is returned when the history of a proxy, or a version of it, is not found
+	ErrNotFound = errors.New("not found")
+
+	// ErrDisabled is returned when the history is not kept
+	ErrDisabled = errors.New("configuration history is disabled")
+)
+
+// ChangeType is the type of change of a resource between two versions of a proxy's configuration
+type ChangeType string
+
+const (
+	// Added signifies that the resource was added
+	Added ChangeType = "added"
+
+	// Removed signifies that the resource was removed
+	Removed ChangeType = "removed"
+
+	// Modified signifies that the resource was modified
+	Modified ChangeType = "modified"
+)
+
+// Store keeps the last versions of the configuration of every proxy
+type Store struct {
+	mu      sync.RWMutex
+	size    int
+	proxies map[string]*proxyHistory
+}
+
+// proxyHistory is the history of the configuration of a proxy
+type proxyHistory struct {
+	// entries are the versions of the configuration, oldest first
+	entries []*entry
+
+	// nextVersion is the version of the next entry
+	nextVersion uint64
+
+	// contents are the contents of the resources referenced by the entries, keyed by resourceKey
+	contents map[resourceKey]*content
+}
+
+// entry is a version of the configuration of a proxy
+type entry struct {
+	Entry
+
+	// versions are the versions of the resources, keyed by type URL and name
+	versions map[string]map[string]string
+}
+
+// resourceKey identifies a version of a resource
+type resourceKey struct {
+	typeURL string
+	name    string
+	version string
+}
+
+// content is the content of a version of a resource, shared by the entries referencing it
+type content struct {
+	// json is the JSON representation of the resource, which is nil for redacted resources
+	json json.RawMessage
+	refs int
+}
+
+// Entry describes a version of the configuration of a proxy
+type Entry struct {
+	// Version identifies the entry in the history of the proxy
+	Version uint64 `json:"version"`
+
+	// Time is when the configuration was generated
+	Time time.Time `json:"time"`
+
+	// Triggers are the events that triggered the generation of the configuration
+	Triggers []messaging.UpdateTrigger `json:"triggers,omitempty"`
+
+	// Changes are the resources that changed since the previous version
+	Changes []ResourceChange `json:"changes,omitempty"`
+}
+
+// ResourceChange is a resource that changed between two versions
+type ResourceChange struct {
+	TypeURL envoy.TypeURI `json:"typeURL"`
+	Name    string        `json:"name"`
+	Change  ChangeType    `json:"change"`
+}
+
+// Diff is the difference between two versions of the configuration of a proxy
+type Diff struct {
+	From    Entry          `json:"from"`
+	To      Entry          `json:"to"`
+	Changes []ResourceDiff `json:"changes"`
+}
+
+// ResourceDiff is the difference between two versions of a resource
+type ResourceDiff struct {
+	ResourceChange
+
+	// Redacted is set for the resources whose content is not kept, such as the secrets
+	Redacted bool `json:"redacted,omitempty"`
+
+	// From is the removed resource
+	From json.RawMessage `json:"from,omitempty"`
+
+	// To is the added resource
+	To json.RawMessage `json:"to,omitempty"`
+
+	// Fields are the fields of the modified resource that differ
+	Fields []FieldDiff `json:"fields,omitempty"`
+}
+
+// FieldDiff is a field of a resource that differs between two versions
+type FieldDiff struct {
+	// Path is the path of the field, ex. 'virtualHosts[0].routes[1].match.prefix'
+	Path string      `json:"path"`
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
@@ -17,18 +17,28 @@ func (s *Server) OnStreamOpen(ctx context.Context, streamID int64, typ string) e
 // OnStreamClosed is called on stream closed
 func (s *Server) OnStreamClosed(streamID int64) {
 	log.Debug().Msgf("OnStreamClosed id: %d", streamID)
+	if proxyUUID := s.xdsStatus.removeStream(streamID); proxyUUID != "" && s.history != nil {
+		s.history.Remove(proxyUUID)
+	}
+	if s.rollout != nil {
+		s.rollout.removeProxy(streamID)
+	}
 	s.callbacks.ProxyDisconnected(streamID)
 }
 
 // OnStreamRequest is called when a request happens on an open connection
 func (s *Server) OnStreamRequest(streamID int64, req *discovery.DiscoveryRequest) error {
-	log.Debug().Msgf("OnStreamRequest node: %s, type: %s, v: %s, nonce: %s, resNames: %s", req.Node.Id, req.TypeUrl, req.VersionInfo, req.ResponseNonce, req.ResourceNames)
+	log.Debug().Msgf("OnStreamRequest node: %s, type: %s, v: %s, nonce: %s, resNames: %s", req.Node.GetId(), req.TypeUrl, req.VersionInfo, req.ResponseNonce, req.ResourceNames)
+	if nack := s.xdsStatus.requestReceived(streamID, req.Node.GetId(), req.TypeUrl, req.ResponseNonce, req.ErrorDetail.GetMessage(), req.ErrorDetail != nil); nack != nil {
+		s.onNACK(nack)
+	}
 	return nil
 }
 
 // OnStreamResponse is called when a response is being sent to a request
 func (s *Server) OnStreamResponse(_ context.Context, streamID int64, req *discovery.DiscoveryRequest, resp *discovery.DiscoveryResponse) {
-	log.Debug().Msgf("OnStreamDeltaResponse node: %s type: %s, v: %s, nonce: %s, NumResources: %d", req.Node.Id, resp.TypeUrl, resp.VersionInfo, resp.Nonce, len(resp.Resources))
+	log.Debug().Msgf("OnStreamResponse node: %s type: %s, v: %s, nonce: %s, NumResources: %d", req.Node.GetId(), resp.TypeUrl, resp.VersionInfo, resp.Nonce, len(resp.Resources))
+	s.xdsStatus.responseSent(streamID, resp.TypeUrl, resp.Nonce, resp.VersionInfo)
 }
 
 // --- Fetch request types. Callback interfaces still requires these to be defined
@@ -55,18 +65,28 @@ func (s *Server) OnDeltaStreamOpen(ctx context.Context, streamID int64, typ stri
 // OnDeltaStreamClosed is called when a Delta stream is being closed
 func (s *Server) OnDeltaStreamClosed(streamID int64) {
 	log.Debug().Msgf("OnDeltaStreamClosed id: %d", streamID)
+	if proxyUUID := s.xdsStatus.removeStream(streamID); proxyUUID != "" && s.history != nil {
+		s.history.Remove(proxyUUID)
+	}
+	if s.rollout != nil {
+		s.rollout.removeProxy(streamID)
+	}
 	s.callbacks.ProxyDisconnected(streamID)
 }
 
 // OnStreamDeltaRequest is called when a Delta request comes on an open Delta stream
 func (s *Server) OnStreamDeltaRequest(streamID int64, req *discovery.DeltaDiscoveryRequest) error {
-	log.Debug().Msgf("OnStreamDeltaRequest node: %s, type: %s, nonce: %s, resNames: %s", req.Node.Id, req.TypeUrl, req.ResponseNonce, req.GetResourceNamesSubscribe())
+	log.Debug().Msgf("OnStreamDeltaRequest node: %s, type: %s, nonce: %s, resNames: %s", req.Node.GetId(), req.TypeUrl, req.ResponseNonce, req.GetResourceNamesSubscribe())
+	if nack := s.xdsStatus.requestReceived(streamID, req.Node.GetId(), req.TypeUrl, req.ResponseNonce, req.ErrorDetail.GetMessage(), req.ErrorDetail != nil); nack != nil {
+		s.onNACK(nack)
+	}
 	return nil
 }
 
 // OnStreamDeltaResponse is called when a Delta request is getting responded to
 func (s *Server) OnStreamDeltaResponse(streamID int64, req *discovery.DeltaDiscoveryRequest, resp *discovery.DeltaDiscoveryResponse) {
-	log.Debug().Msgf("OnStreamDeltaResponse node: %s type: %s, v: %s, nonce: %s, NumResources: %d", req.Node.Id, resp.TypeUrl, resp.SystemVersionInfo, resp.Nonce, len(resp.Resources))
+	log.Debug().Msgf("OnStreamDeltaResponse node: %s type: %s, v: %s, nonce: %s, NumResources: %d", req.Node.GetId(), resp.TypeUrl, resp.SystemVersionInfo, resp.Nonce, len(resp.Resources))
+	s.xdsStatus.responseSent(streamID, resp.TypeUrl, resp.Nonce, resp.SystemVersionInfo)
 }
 
 // scLogger implements envoy control plane's log.Logger and delegates calls to the `log` variable defined in
@@ -0,0 +1,391 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+	"github.com/openservicemesh/osm/pkg/models"
+)
+
+const (
+	defaultCanaryPercentage   = 10
+	defaultBakePeriod         = 2 * time.Minute
+	defaultErrorRateThreshold = 5
+
+	rolloutPromoted = "promoted"
+	rolloutHalted   = "halted"
+)
+
+// stagedRollout holds back the configuration changes of the proxies of a service identity, until the change has
+// been applied by a percentage of them, the canaries, for a bake period without being rejected, nor increasing their
+// upstream error rate.
+// The certificates of the proxies are not part of the rollout, so that they are always kept up to date.
+type stagedRollout struct {
+	getSpec       func() configv1alpha2.ConfigRolloutSpec
+	getStats      func(context.Context, *models.Proxy) (upstreamStats, error)
+	snapshotCache cachev3.SnapshotCache
+
+	mu         sync.Mutex
+	proxies    map[string]*rolloutProxy
+	identities map[identity.ServiceIdentity]*identityRollout
+}
+
+// rolloutProxy is the state of the rollout for a proxy
+type rolloutProxy struct {
+	proxy *models.Proxy
+
+	// fingerprint is the fingerprint of the config applied to the proxy, excluding its certificates
+	fingerprint string
+
+	// lastNACK is the time the proxy last rejected a config, and nackMessage the reason
+	lastNACK    time.Time
+	nackMessage string
+}
+
+// identityRollout is the rollout of a configuration change to the proxies of a service identity
+type identityRollout struct {
+	identity  identity.ServiceIdentity
+	startedAt time.Time
+	bakeUntil time.Time
+
+	// canaries are the UUIDs of the proxies the change is rolled out to first
+	canaries map[string]struct{}
+
+	// pending is the config held back for each of the remaining proxies, keyed by their UUID
+	pending map[string]pendingConfig
+
+	// halted is set when the canaries regressed, in which case the pending configs are not applied
+	halted bool
+}
+
+type pendingConfig struct {
+	fingerprint string
+	resources   map[string][]types.Resource
+}
+
+func newStagedRollout(getSpec func() configv1alpha2.ConfigRolloutSpec, snapshotCache cachev3.SnapshotCache) *stagedRollout {
+	return &stagedRollout{
+		getSpec:       getSpec,
+		getStats:      getUpstreamStats,
+		snapshotCache: snapshotCache,
+		proxies:       make(map[string]*rolloutProxy),
+		identities:    make(map[identity.ServiceIdentity]*identityRollout),
+	}
+}
+
+// updateProxy applies the given snapshot to the given proxy if it is not held back by a rollout. When it is held back,
+// only the certificates of the proxy's current snapshot are updated.
+func (r *stagedRollout) updateProxy(ctx context.Context, proxy *models.Proxy, resources map[string][]types.Resource, snapshot *cachev3.Snapshot) error {
+	spec := r.getSpec()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !spec.Enable {
+		// Configuration changes held back when the rollout gets disabled are applied on the next update
+		r.proxies = make(map[string]*rolloutProxy)
+		r.identities = make(map[identity.ServiceIdentity]*identityRollout)
+		return r.snapshotCache.SetSnapshot(ctx, proxy.UUID.String(), snapshot)
+	}
+
+	uuid := proxy.UUID.String()
+	fingerprint := configFingerprint(snapshot)
+
+	p, ok := r.proxies[uuid]
+	if !ok {
+		// There is no previous config to keep for a proxy that just connected
+		r.proxies[uuid] = &rolloutProxy{proxy: proxy, fingerprint: fingerprint}
+		return r.snapshotCache.SetSnapshot(ctx, uuid, snapshot)
+	}
+	p.proxy = proxy
+
+	rollout := r.identities[proxy.Identity]
+	if fingerprint == p.fingerprint {
+		if rollout != nil {
+			delete(rollout.pending, uuid)
+		}
+		return r.snapshotCache.SetSnapshot(ctx, uuid, snapshot)
+	}
+
+	// A change that differs from the one a halted rollout holds back for this proxy is a new change
+	if rollout == nil || (rollout.halted && rollout.pending[uuid].fingerprint != fingerprint) {
+		rollout = r.startRollout(proxy, spec)
+	}
+
+	if _, ok := rollout.canaries[uuid]; ok {
+		// Further changes applied to the canaries restart the bake period
+		rollout.bakeUntil = time.Now().Add(getBakePeriod(spec))
+		p.fingerprint = fingerprint
+		return r.snapshotCache.SetSnapshot(ctx, uuid, snapshot)
+	}
+
+	rollout.pending[uuid] = pendingConfig{fingerprint: fingerprint, resources: resources}
+	log.Debug().Str("proxy", proxy.String()).Msg("Holding back configuration change until rolled out to canaries")
+
+	current, err := r.snapshotCache.GetSnapshot(uuid)
+	if err != nil {
+		return err
+	}
+	held, err := withCertificates(current, resources)
+	if err != nil {
+		return err
+	}
+	return r.snapshotCache.SetSnapshot(ctx, uuid, held)
+}
+
+// withCertificates returns the given snapshot with the certificates in the given resources. The secrets of the
+// given snapshot missing from the given resources are kept, since the held back clusters and listeners may still
+// reference them until the change is promoted.
+func withCertificates(current cachev3.ResourceSnapshot, resources map[string][]types.Resource) (*cachev3.Snapshot, error) {
+	secretType := envoy.TypeSDS.String()
+	held := make(map[string][]types.Resource, len(resources))
+	for typeURL := range resources {
+		if typeURL == secretType {
+			continue
+		}
+		for _, resource := range current.GetResources(typeURL) {
+			held[typeURL] = append(held[typeURL], resource)
+		}
+	}
+
+	secrets := make(map[string]struct{}, len(resources[secretType]))
+	for _, secret := range resources[secretType] {
+		held[secretType] = append(held[secretType], secret)
+		secrets[cachev3.GetResourceName(secret)] = struct{}{}
+	}
+	for name, secret := range current.GetResources(secretType) {
+		if _, ok := secrets[name]; !ok {
+			held[secretType] = append(held[secretType], secret)
+		}
+	}
+
+	return newVersionedSnapshot(held)
+}
+
+// startRollout starts the rollout of a configuration change to the proxies of the given proxy's identity, with the
+// given proxy as the first canary. It replaces the identity's previous rollout, if any.
+func (r *stagedRollout) startRollout(proxy *models.Proxy, spec configv1alpha2.ConfigRolloutSpec) *identityRollout {
+	var others []string
+	for uuid, p := range r.proxies {
+		if p.proxy.Identity == proxy.Identity && uuid != proxy.UUID.String() {
+			others = append(others, uuid)
+		}
+	}
+	sort.Strings(others)
+
+	percentage := spec.CanaryPercentage
+	if percentage <= 0 {
+		percentage = defaultCanaryPercentage
+	}
+	count := int(math.Ceil(float64((len(others)+1)*percentage) / 100))
+
+	now := time.Now()
+	rollout := &identityRollout{
+		identity:  proxy.Identity,
+		startedAt: now,
+		bakeUntil: now.Add(getBakePeriod(spec)),
+		canaries:  map[string]struct{}{proxy.UUID.String(): {}},
+		pending:   make(map[string]pendingConfig),
+	}
+	for _, uuid := range others {
+		if len(rollout.canaries) >= count {
+			break
+		}
+		rollout.canaries[uuid] = struct{}{}
+	}
+	r.identities[proxy.Identity] = rollout
+
+	log.Info().Str("identity", proxy.Identity.String()).Msgf("Rolling out configuration change to %d of %d proxies", len(rollout.canaries), len(others)+1)
+	go r.bake(rollout, spec)
+
+	return rollout
+}
+
+// bake watches the canaries of the given rollout until the end of its bake period, and then either applies the
+// pending configs to the remaining proxies or halts the rollout
+func (r *stagedRollout) bake(rollout *identityRollout, spec configv1alpha2.ConfigRolloutSpec) {
+	baseline := r.sampleStats(rollout)
+	for {
+		r.mu.Lock()
+		if r.identities[rollout.identity] != rollout {
+			// Superseded by another rollout
+			r.mu.Unlock()
+			return
+		}
+		wait := time.Until(rollout.bakeUntil)
+		r.mu.Unlock()
+
+		if wait <= 0 {
+			break
+		}
+		time.Sleep(wait)
+	}
+	final := r.sampleStats(rollout)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.identities[rollout.identity] != rollout {
+		return
+	}
+
+	if reason := r.regression(rollout, baseline, final, spec); reason != "" {
+		rollout.halted = true
+		metricsstore.DefaultMetricsStore.ProxyConfigRolloutCount.WithLabelValues(rolloutHalted).Inc()
+		events.GenericEventRecorder().WarnEvent(events.ProxyConfigRolloutHalted,
+			"Halted the rollout of a configuration change to the proxies of service identity %s, %d proxies keep their previous configuration: %s",
+			rollout.identity, len(rollout.pending), reason)
+		return
+	}
+
+	for uuid, pending := range rollout.pending {
+		p, ok := r.proxies[uuid]
+		if !ok {
+			continue
+		}
+		snapshot, err := newVersionedSnapshot(pending.resources)
+		if err == nil {
+			err = r.snapshotCache.SetSnapshot(context.Background(), uuid, snapshot)
+		}
+		if err != nil {
+			log.Error().Err(err).Str("proxy", p.proxy.String()).Msg("Error applying configuration change held back by rollout")
+			continue
+		}
+		p.fingerprint = pending.fingerprint
+	}
+	delete(r.identities, rollout.identity)
+	metricsstore.DefaultMetricsStore.ProxyConfigRolloutCount.WithLabelValues(rolloutPromoted).Inc()
+	log.Info().Str("identity", rollout.identity.String()).Msgf("Rolled out configuration change to the remaining %d proxies", len(rollout.pending))
+}
+
+// regression returns why the canaries of the given rollout regressed, or an empty string if they didn't
+func (r *stagedRollout) regression(rollout *identityRollout, baseline, final map[string]upstreamStats, spec configv1alpha2.ConfigRolloutSpec) string {
+	var canaries, others upstreamStats
+	for uuid, stats := range final {
+		delta, ok := stats.since(baseline[uuid])
+		if !ok {
+			continue
+		}
+		if _, ok := rollout.canaries[uuid]; ok {
+			canaries = canaries.add(delta)
+		} else {
+			others = others.add(delta)
+		}
+	}
+
+	var nacks []string
+	for uuid := range rollout.canaries {
+		if p, ok := r.proxies[uuid]; ok && p.lastNACK.After(rollout.startedAt) {
+			nacks = append(nacks, fmt.Sprintf("proxy %s rejected the configuration: %s", uuid, p.nackMessage))
+		}
+	}
+	if len(nacks) > 0 {
+		sort.Strings(nacks)
+		return strings.Join(nacks, "; ")
+	}
+
+	threshold := spec.ErrorRateThreshold
+	if threshold <= 0 {
+		threshold = defaultErrorRateThreshold
+	}
+	if increase := (canaries.errorRate() - others.errorRate()) * 100; canaries.requests > 0 && increase > float64(threshold) {
+		return fmt.Sprintf("the upstream error rate of the canaries increased by %.1f percentage points over the remaining proxies", increase)
+	}
+	return ""
+}
+
+// sampleStats returns the upstream stats of the proxies of the given rollout's identity, keyed by their UUID. The
+// proxies whose stats can't be retrieved, ex. when metrics aren't enabled for their namespace, are left out.
+func (r *stagedRollout) sampleStats(rollout *identityRollout) map[string]upstreamStats {
+	r.mu.Lock()
+	var proxies []*models.Proxy
+	for _, p := range r.proxies {
+		if p.proxy.Identity == rollout.identity {
+			proxies = append(proxies, p.proxy)
+		}
+	}
+	r.mu.Unlock()
+
+	samples := make(map[string]upstreamStats, len(proxies))
+	for _, proxy := range proxies {
+		stats, err := r.getStats(context.Background(), proxy)
+		if err != nil {
+			log.Debug().Err(err).Str("proxy", proxy.String()).Msg("Error getting upstream stats of proxy, skipping it in the rollout's error rate")
+			continue
+		}
+		samples[proxy.UUID.String()] = stats
+	}
+	return samples
+}
+
+// recordNACK records that the proxy with the given UUID rejected the config of the given type
+func (r *stagedRollout) recordNACK(uuid string, typeURL string, message string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if p, ok := r.proxies[uuid]; ok {
+		p.lastNACK = time.Now()
+		p.nackMessage = fmt.Sprintf("%s: %s", envoy.TypeURI(typeURL).Short(), message)
+	}
+}
+
+// removeProxy removes the proxy with the given connection ID from the rollouts
+func (r *stagedRollout) removeProxy(connectionID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for uuid, p := range r.proxies {
+		if p.proxy.GetConnectionID() != connectionID {
+			continue
+		}
+		delete(r.proxies, uuid)
+		if rollout, ok := r.identities[p.proxy.Identity]; ok {
+			delete(rollout.pending, uuid)
+		}
+		return
+	}
+}
+
+// configFingerprint returns the fingerprint of the given snapshot's config, excluding the certificates which differ
+// between the proxies of a service identity
+func configFingerprint(snapshot *cachev3.Snapshot) string {
+	secretType := envoy.TypeSDS.String()
+	typeURLs := make([]string, 0, len(snapshot.VersionMap))
+	for typeURL := range snapshot.VersionMap {
+		if typeURL != secretType {
+			typeURLs = append(typeURLs, typeURL)
+		}
+	}
+	sort.Strings(typeURLs)
+
+	hasher := sha256.New()
+	for _, typeURL := range typeURLs {
+		hasher.Write([]byte(typeURL))
+		hasher.Write([]byte{0})
+		hasher.Write([]byte(typeVersion(snapshot.VersionMap[typeURL])))
+		hasher.Write([]byte{0})
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+func getBakePeriod(spec configv1alpha2.ConfigRolloutSpec) time.Duration {
+	bakePeriod, err := time.ParseDuration(spec.BakePeriod)
+	if err != nil || bakePeriod <= 0 {
+		return defaultBakePeriod
+	}
+	return bakePeriod
+}
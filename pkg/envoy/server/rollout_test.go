@@ -0,0 +1,294 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/status"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/models"
+)
+
+func rolloutResources(cluster string, secret string) map[string][]types.Resource {
+	return map[string][]types.Resource{
+		envoy.TypeCDS.String(): {&xds_cluster.Cluster{Name: cluster}},
+		envoy.TypeSDS.String(): {&xds_auth.Secret{Name: "service-cert", Type: &xds_auth.Secret_TlsCertificate{
+			TlsCertificate: &xds_auth.TlsCertificate{},
+		}}, &xds_auth.Secret{Name: secret}},
+	}
+}
+
+// getClusterAndSecrets returns the cluster of the given proxy, and its secrets other than its service certificate
+func getClusterAndSecrets(t *testing.T, s *Server, proxy *models.Proxy) (string, []string) {
+	t.Helper()
+	snapshot, err := s.snapshotCache.GetSnapshot(proxy.UUID.String())
+	tassert.NoError(t, err)
+
+	var cluster string
+	var secrets []string
+	for name := range snapshot.GetResources(envoy.TypeCDS.String()) {
+		cluster = name
+	}
+	for name := range snapshot.GetResources(envoy.TypeSDS.String()) {
+		if name != "service-cert" {
+			secrets = append(secrets, name)
+		}
+	}
+	sort.Strings(secrets)
+	return cluster, secrets
+}
+
+// nack makes the given proxy connected over the given stream reject a CDS response sent to it
+func nack(t *testing.T, s *Server, streamID int64, proxy *models.Proxy) {
+	t.Helper()
+	s.OnStreamResponse(context.Background(), streamID, &xds_discovery.DiscoveryRequest{
+		Node:    &xds_core.Node{Id: proxy.UUID.String()},
+		TypeUrl: envoy.TypeCDS.String(),
+	}, &xds_discovery.DiscoveryResponse{
+		TypeUrl:     envoy.TypeCDS.String(),
+		VersionInfo: "rejected",
+		Nonce:       "1",
+	})
+	tassert.NoError(t, s.OnStreamRequest(streamID, &xds_discovery.DiscoveryRequest{
+		Node:          &xds_core.Node{Id: proxy.UUID.String()},
+		TypeUrl:       envoy.TypeCDS.String(),
+		ResponseNonce: "1",
+		ErrorDetail:   &status.Status{Message: "invalid cluster"},
+	}))
+}
+
+type fakeStats struct {
+	mu    sync.Mutex
+	stats map[string]upstreamStats
+	calls int
+}
+
+func (f *fakeStats) get(_ context.Context, proxy *models.Proxy) (upstreamStats, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	stats, ok := f.stats[proxy.UUID.String()]
+	if !ok {
+		return upstreamStats{}, errors.New("no stats")
+	}
+	return stats, nil
+}
+
+func (f *fakeStats) getCalls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+func (f *fakeStats) set(uuid string, stats upstreamStats) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stats[uuid] = stats
+}
+
+func TestStagedRollout(t *testing.T) {
+	const bakePeriod = 200 * time.Millisecond
+
+	testCases := []struct {
+		name string
+		// regress makes the canaries regress during the bake period
+		regress        func(s *Server, stats *fakeStats, canaries []*models.Proxy)
+		expectPromoted bool
+	}{
+		{
+			name:           "change is rolled out to the remaining proxies after the bake period",
+			regress:        func(*Server, *fakeStats, []*models.Proxy) {},
+			expectPromoted: true,
+		},
+		{
+			name: "rollout is halted when a canary rejects the change",
+			regress: func(s *Server, _ *fakeStats, canaries []*models.Proxy) {
+				nack(t, s, canaries[0].GetConnectionID(), canaries[0])
+			},
+			expectPromoted: false,
+		},
+		{
+			name: "rollout is halted when a canary rejects the change over delta ADS",
+			regress: func(s *Server, _ *fakeStats, canaries []*models.Proxy) {
+				// The node is only set on the first request of the stream
+				streamID := canaries[0].GetConnectionID()
+				tassert.NoError(t, s.OnStreamDeltaRequest(streamID, &xds_discovery.DeltaDiscoveryRequest{
+					Node:    &xds_core.Node{Id: canaries[0].UUID.String()},
+					TypeUrl: envoy.TypeCDS.String(),
+				}))
+				s.OnStreamDeltaResponse(streamID, &xds_discovery.DeltaDiscoveryRequest{
+					TypeUrl: envoy.TypeCDS.String(),
+				}, &xds_discovery.DeltaDiscoveryResponse{
+					TypeUrl:           envoy.TypeCDS.String(),
+					SystemVersionInfo: "rejected",
+					Nonce:             "1",
+				})
+				tassert.NoError(t, s.OnStreamDeltaRequest(streamID, &xds_discovery.DeltaDiscoveryRequest{
+					TypeUrl:       envoy.TypeCDS.String(),
+					ResponseNonce: "1",
+					ErrorDetail:   &status.Status{Message: "invalid cluster"},
+				}))
+			},
+			expectPromoted: false,
+		},
+		{
+			name: "rollout is halted when the upstream error rate of the canaries increases",
+			regress: func(_ *Server, stats *fakeStats, canaries []*models.Proxy) {
+				for _, canary := range canaries {
+					stats.set(canary.UUID.String(), upstreamStats{requests: 200, errors: 50})
+				}
+			},
+			expectPromoted: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			ctx := context.Background()
+
+			spec := configv1alpha2.ConfigRolloutSpec{
+				Enable:           true,
+				CanaryPercentage: 50,
+				BakePeriod:       bakePeriod.String(),
+			}
+			s := NewADSServer(WithStagedRollout(func() configv1alpha2.ConfigRolloutSpec { return spec }))
+			stats := &fakeStats{stats: map[string]upstreamStats{}}
+			s.rollout.getStats = stats.get
+
+			si := identity.New("sa", "ns")
+			var proxies []*models.Proxy
+			for i := 0; i < 4; i++ {
+				proxy := models.NewProxy(models.KindSidecar, uuid.New(), si, nil, int64(i))
+				proxies = append(proxies, proxy)
+				stats.set(proxy.UUID.String(), upstreamStats{requests: 100})
+				assert.NoError(s.UpdateProxy(ctx, proxy, rolloutResources("a", "cert-1")))
+			}
+
+			// A proxy of another identity is not part of the rollout
+			other := models.NewProxy(models.KindSidecar, uuid.New(), identity.New("other", "ns"), nil, 10)
+			assert.NoError(s.UpdateProxy(ctx, other, rolloutResources("a", "cert-1")))
+
+			for _, proxy := range proxies {
+				assert.NoError(s.UpdateProxy(ctx, proxy, rolloutResources("b", "cert-1")))
+			}
+
+			var canaries, held []*models.Proxy
+			for _, proxy := range proxies {
+				if cluster, _ := getClusterAndSecrets(t, s, proxy); cluster == "b" {
+					canaries = append(canaries, proxy)
+				} else {
+					held = append(held, proxy)
+				}
+			}
+			assert.Len(canaries, 2)
+			assert.Len(held, 2)
+
+			// The certificates of the held proxies are updated, and the secrets the held back clusters may reference
+			// are kept
+			assert.NoError(s.UpdateProxy(ctx, held[0], rolloutResources("b", "cert-2")))
+			cluster, secrets := getClusterAndSecrets(t, s, held[0])
+			assert.Equal("a", cluster)
+			assert.Equal([]string{"cert-1", "cert-2"}, secrets)
+
+			// Wait for the baseline stats of the rollout
+			assert.Eventually(func() bool { return stats.getCalls() >= len(proxies) }, bakePeriod, time.Millisecond)
+			for _, canary := range canaries {
+				stats.set(canary.UUID.String(), upstreamStats{requests: 200, errors: 1})
+			}
+			for _, proxy := range held {
+				stats.set(proxy.UUID.String(), upstreamStats{requests: 200})
+			}
+			tc.regress(s, stats, canaries)
+
+			expectedCluster := "a"
+			if tc.expectPromoted {
+				expectedCluster = "b"
+			}
+			assert.Eventually(func() bool {
+				for _, proxy := range held {
+					if cluster, _ := getClusterAndSecrets(t, s, proxy); cluster != expectedCluster {
+						return false
+					}
+				}
+				s.rollout.mu.Lock()
+				defer s.rollout.mu.Unlock()
+				rollout, ok := s.rollout.identities[si]
+				return tc.expectPromoted == !ok && (tc.expectPromoted || rollout.halted)
+			}, 5*bakePeriod, 10*time.Millisecond)
+
+			// The secrets of the held back config are removed once the change is promoted
+			_, secrets = getClusterAndSecrets(t, s, held[0])
+			if tc.expectPromoted {
+				assert.Equal([]string{"cert-2"}, secrets)
+			} else {
+				assert.Equal([]string{"cert-1", "cert-2"}, secrets)
+			}
+
+			if tc.expectPromoted {
+				return
+			}
+
+			// The change held back by the halted rollout is not applied on resync
+			assert.NoError(s.UpdateProxy(ctx, held[1], rolloutResources("b", "cert-1")))
+			cluster, _ = getClusterAndSecrets(t, s, held[1])
+			assert.Equal("a", cluster)
+
+			// A new change starts a new rollout
+			assert.NoError(s.UpdateProxy(ctx, held[1], rolloutResources("c", "cert-1")))
+			cluster, _ = getClusterAndSecrets(t, s, held[1])
+			assert.Equal("c", cluster)
+		})
+	}
+}
+
+func TestStagedRolloutDisabled(t *testing.T) {
+	assert := tassert.New(t)
+	ctx := context.Background()
+
+	s := NewADSServer(WithStagedRollout(func() configv1alpha2.ConfigRolloutSpec {
+		return configv1alpha2.ConfigRolloutSpec{Enable: false}
+	}))
+	si := identity.New("sa", "ns")
+	var proxies []*models.Proxy
+	for i := 0; i < 2; i++ {
+		proxy := models.NewProxy(models.KindSidecar, uuid.New(), si, nil, int64(i))
+		proxies = append(proxies, proxy)
+		assert.NoError(s.UpdateProxy(ctx, proxy, rolloutResources("a", "cert-1")))
+	}
+	for _, proxy := range proxies {
+		assert.NoError(s.UpdateProxy(ctx, proxy, rolloutResources("b", "cert-1")))
+		cluster, _ := getClusterAndSecrets(t, s, proxy)
+		assert.Equal("b", cluster)
+	}
+	assert.Empty(s.rollout.proxies)
+}
+
+func TestConfigFingerprint(t *testing.T) {
+	assert := tassert.New(t)
+
+	a1, err := newVersionedSnapshot(rolloutResources("a", "cert-1"))
+	assert.NoError(err)
+	a2, err := newVersionedSnapshot(rolloutResources("a", "cert-2"))
+	assert.NoError(err)
+	b1, err := newVersionedSnapshot(rolloutResources("b", "cert-1"))
+	assert.NoError(err)
+
+	// Certificates are not part of the fingerprint
+	assert.Equal(configFingerprint(a1), configFingerprint(a2))
+	assert.NotEqual(configFingerprint(a1), configFingerprint(b1))
+}
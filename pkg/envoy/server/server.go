@@ -2,17 +2,24 @@ package server
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"sort"
 
 	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
-	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
 	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
 	serverv3 "github.com/envoyproxy/go-control-plane/pkg/server/v3"
+	"go.opentelemetry.io/otel/trace"
 
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
 	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/envoy/history"
 	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/messaging"
 	"github.com/openservicemesh/osm/pkg/models"
+	"github.com/openservicemesh/osm/pkg/tracing"
 )
 
 const (
@@ -20,20 +27,46 @@ const (
 	ServerType = "ADS"
 	// xdsServerCertificateCommonName is the common name of the certificate for the ADS server
 	xdsServerCertificateCommonName = "ads"
+
+	// typeVersionLength is the number of hex characters of the hash used as the version of a resource type
+	typeVersionLength = 16
 )
 
 // NewADSServer creates a new Aggregated Discovery Service server
-func NewADSServer() *Server {
+func NewADSServer(opts ...Option) *Server {
 	server := Server{
 		snapshotCache: cachev3.NewSnapshotCache(false, cachev3.IDHash{}, &scLogger{
 			log: logger.New("envoy/snapshot-cache"),
 		}),
-		configVersion: make(map[string]uint64),
+		xdsStatus: newXDSStatusTracker(),
+	}
+	for _, opt := range opts {
+		opt(&server)
 	}
 
 	return &server
 }
 
+// WithStagedRollout enables the staged rollout of configuration changes, when enabled by the ConfigRolloutSpec
+// returned by the given function. Changes to the configuration of the proxies of a service identity are then applied
+// to a percentage of them first, and to the remaining proxies after a bake period, unless the first proxies reject
+// the configuration or their upstream error rate increases.
+func WithStagedRollout(getSpec func() configv1alpha2.ConfigRolloutSpec) Option {
+	return func(s *Server) {
+		s.rollout = newStagedRollout(getSpec, s.snapshotCache)
+	}
+}
+
+// WithConfigHistory keeps the last size versions of the configuration of every connected proxy, served by
+// GetConfigHistory and DiffConfigHistory. The history is disabled when size is not positive.
+func WithConfigHistory(size int) Option {
+	return func(s *Server) {
+		if size > 0 {
+			s.history = history.NewStore(size)
+		}
+	}
+}
+
 // SetCallbacks is a method used to set the callbacks that notify the rest of the system that a proxy, with the given
 // unique connection id, has either connected or disconnected.
 func (s *Server) SetCallbacks(cb streamCallback) {
@@ -57,25 +90,97 @@ func (s *Server) Start(ctx context.Context, certManager *certificate.Manager, ca
 	return nil
 }
 
-// UpdateProxy stores a group of resources as a new Snapshot with a new version in the cache.
+// UpdateProxy stores a group of resources as a new Snapshot in the cache.
+// Every resource is versioned by the hash of its content, and every resource type by the hash of its resources'
+// versions, so that only the resources that changed since the previous snapshot are sent to the proxy: changed
+// resource types over the state of the world protocol, and changed resources over the incremental (delta) protocol.
 // It also runs a consistency check on the snapshot (will warn if there are missing resources referenced in
 // the snapshot)
 func (s *Server) UpdateProxy(ctx context.Context, proxy *models.Proxy, snapshotResources map[string][]types.Resource) error {
-	uuid := proxy.UUID.String()
-
-	s.configVerMutex.Lock()
-	s.configVersion[uuid]++
-	configVersion := s.configVersion[uuid]
-	s.configVerMutex.Unlock()
+	ctx, span := tracing.Start(ctx, "ADS.UpdateProxy", trace.WithAttributes(tracing.ProxyUUIDKey.String(proxy.UUID.String())))
+	defer span.End()
 
-	snapshot, err := cache.NewSnapshot(fmt.Sprintf("%d", configVersion), snapshotResources)
+	snapshot, err := newVersionedSnapshot(snapshotResources)
 	if err != nil {
+		span.RecordError(err)
 		return err
 	}
 
 	if err := snapshot.Consistent(); err != nil {
+		span.RecordError(err)
 		return err
 	}
 
-	return s.snapshotCache.SetSnapshot(ctx, uuid, snapshot)
+	s.xdsStatus.setProxy(proxy, span.SpanContext())
+	if s.history != nil {
+		s.history.Record(proxy.UUID.String(), snapshotResources, snapshot.VersionMap, messaging.UpdateTriggersFromContext(ctx))
+	}
+	if s.rollout != nil {
+		return s.rollout.updateProxy(ctx, proxy, snapshotResources, snapshot)
+	}
+	return s.snapshotCache.SetSnapshot(ctx, proxy.UUID.String(), snapshot)
+}
+
+// newVersionedSnapshot returns a snapshot of the given resources along with their content based versions
+func newVersionedSnapshot(snapshotResources map[string][]types.Resource) (*cachev3.Snapshot, error) {
+	snapshot := &cachev3.Snapshot{
+		VersionMap: make(map[string]map[string]string, len(snapshotResources)),
+	}
+
+	for typeURL, resources := range snapshotResources {
+		index := cachev3.GetResponseType(typeURL)
+		if index == types.UnknownType {
+			return nil, fmt.Errorf("unknown resource type: %s", typeURL)
+		}
+
+		versions := make(map[string]string, len(resources))
+		for _, resource := range resources {
+			marshaled, err := cachev3.MarshalResource(resource)
+			if err != nil {
+				return nil, fmt.Errorf("error marshaling %s resource %s: %w", typeURL, cachev3.GetResourceName(resource), err)
+			}
+			versions[cachev3.GetResourceName(resource)] = cachev3.HashResource(marshaled)
+		}
+
+		snapshot.Resources[index] = cachev3.NewResources(typeVersion(versions), resources)
+		snapshot.VersionMap[typeURL] = versions
+	}
+
+	return snapshot, nil
+}
+
+// typeVersion returns the version of a resource type, given the versions of its resources keyed by name
+func typeVersion(versions map[string]string) string {
+	names := make([]string, 0, len(versions))
+	for name := range versions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hasher := sha256.New()
+	for _, name := range names {
+		// Names and versions never contain a NUL byte, which separates them unambiguously
+		hasher.Write([]byte(name))
+		hasher.Write([]byte{0})
+		hasher.Write([]byte(versions[name]))
+		hasher.Write([]byte{0})
+	}
+	return hex.EncodeToString(hasher.Sum(nil))[:typeVersionLength]
+}
+
+// GetConfigHistory returns the last versions of the configuration generated for the proxy with the given UUID
+func (s *Server) GetConfigHistory(proxyUUID string) ([]history.Entry, error) {
+	if s.history == nil {
+		return nil, history.ErrDisabled
+	}
+	return s.history.List(proxyUUID)
+}
+
+// DiffConfigHistory returns the difference between the given versions of the configuration generated for the proxy
+// with the given UUID
+func (s *Server) DiffConfigHistory(proxyUUID string, from, to uint64) (*history.Diff, error) {
+	if s.history == nil {
+		return nil, history.ErrDisabled
+	}
+	return s.history.Diff(proxyUUID, from, to)
 }
@@ -5,9 +5,16 @@ import (
 	"testing"
 	"time"
 
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/server/stream/v3"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
 	catalogFake "github.com/openservicemesh/osm/pkg/catalog/fake"
@@ -15,7 +22,9 @@ import (
 	"github.com/openservicemesh/osm/pkg/compute"
 	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/envoy/generator"
+	"github.com/openservicemesh/osm/pkg/envoy/history"
 	"github.com/openservicemesh/osm/pkg/envoy/secrets"
+	"github.com/openservicemesh/osm/pkg/messaging"
 	"github.com/openservicemesh/osm/pkg/metricsstore"
 	"github.com/openservicemesh/osm/pkg/models"
 	"github.com/openservicemesh/osm/pkg/tests"
@@ -37,8 +46,12 @@ func TestADSResponse(t *testing.T) {
 	provider.EXPECT().GetIngressBackendPolicyForService(gomock.Any()).Return(nil).AnyTimes()
 	provider.EXPECT().GetUpstreamTrafficSettingByService(gomock.Any()).Return(nil).AnyTimes()
 	provider.EXPECT().GetUpstreamTrafficSettingByNamespace(gomock.Any()).Return(nil).AnyTimes()
+	provider.EXPECT().GetZoneForProxy(gomock.Any()).Return("").AnyTimes()
+	provider.EXPECT().ListIPsForProxy(gomock.Any()).Return(nil).AnyTimes()
 	provider.EXPECT().ListTrafficTargets().Return(nil).AnyTimes()
 	provider.EXPECT().GetTelemetryConfig(gomock.Any()).Return(models.TelemetryConfig{}).AnyTimes()
+	provider.EXPECT().GetSidecarConfig(gomock.Any()).Return(models.SidecarConfig{}).AnyTimes()
+	provider.EXPECT().ListTrustDomainFederations().Return(nil).AnyTimes()
 
 	mc := catalogFake.NewFakeMeshCatalog(provider)
 
@@ -75,20 +88,10 @@ func TestADSResponse(t *testing.T) {
 	a.Nil(err)
 	a.NotNil(snapshot)
 
-	a.Equal(snapshot.GetVersion(string(envoy.TypeCDS)), "1")
-	a.NotNil(snapshot.GetResources(string(envoy.TypeCDS)))
-
-	a.Equal(snapshot.GetVersion(string(envoy.TypeEDS)), "1")
-	a.NotNil(snapshot.GetResources(string(envoy.TypeEDS)))
-
-	a.Equal(snapshot.GetVersion(string(envoy.TypeLDS)), "1")
-	a.NotNil(snapshot.GetResources(string(envoy.TypeLDS)))
-
-	a.Equal(snapshot.GetVersion(string(envoy.TypeRDS)), "1")
-	a.NotNil(snapshot.GetResources(string(envoy.TypeRDS)))
-
-	a.Equal(snapshot.GetVersion(string(envoy.TypeSDS)), "1")
-	a.NotNil(snapshot.GetResources(string(envoy.TypeSDS)))
+	for _, typeURL := range []envoy.TypeURI{envoy.TypeCDS, envoy.TypeEDS, envoy.TypeLDS, envoy.TypeRDS, envoy.TypeSDS} {
+		a.Len(snapshot.GetVersion(string(typeURL)), typeVersionLength)
+		a.NotNil(snapshot.GetResources(string(typeURL)))
+	}
 
 	// Expect 2 SDS certs:
 	// 1. Proxy's own cert to present to peer during mTLS/TLS handshake
@@ -106,3 +109,128 @@ func TestADSResponse(t *testing.T) {
 	a.True(ok)
 	a.NotNil(resource)
 }
+
+func TestUpdateProxyVersions(t *testing.T) {
+	a := assert.New(t)
+
+	proxy := models.NewProxy(models.KindSidecar, uuid.New(), tests.BookstoreServiceIdentity, nil, 1)
+	cluster := func(name string, timeout time.Duration) *xds_cluster.Cluster {
+		return &xds_cluster.Cluster{
+			Name:           name,
+			ConnectTimeout: durationpb.New(timeout),
+		}
+	}
+	resources := func(timeoutB time.Duration) map[string][]types.Resource {
+		return map[string][]types.Resource{
+			string(envoy.TypeCDS): {cluster("a", time.Second), cluster("b", timeoutB)},
+			string(envoy.TypeLDS): {},
+		}
+	}
+
+	s := NewADSServer()
+	ctx := context.Background()
+
+	a.Nil(s.UpdateProxy(ctx, proxy, resources(time.Second)))
+	snapshot, err := s.snapshotCache.GetSnapshot(proxy.UUID.String())
+	a.Nil(err)
+	cdsVersion := snapshot.GetVersion(string(envoy.TypeCDS))
+	ldsVersion := snapshot.GetVersion(string(envoy.TypeLDS))
+	clusterVersions := snapshot.(*cachev3.Snapshot).GetVersionMap(string(envoy.TypeCDS))
+	a.Len(clusterVersions, 2)
+
+	// Unchanged resources keep their versions
+	a.Nil(s.UpdateProxy(ctx, proxy, resources(time.Second)))
+	snapshot, err = s.snapshotCache.GetSnapshot(proxy.UUID.String())
+	a.Nil(err)
+	a.Equal(cdsVersion, snapshot.GetVersion(string(envoy.TypeCDS)))
+	a.Equal(ldsVersion, snapshot.GetVersion(string(envoy.TypeLDS)))
+	a.Equal(clusterVersions, snapshot.(*cachev3.Snapshot).GetVersionMap(string(envoy.TypeCDS)))
+
+	// Open a delta watch subscribed to the current clusters, which the proxy already has, so nothing is sent to it
+	responses := make(chan cachev3.DeltaResponse, 1)
+	cancel := s.snapshotCache.CreateDeltaWatch(&xds_discovery.DeltaDiscoveryRequest{
+		Node:    &xds_core.Node{Id: proxy.UUID.String()},
+		TypeUrl: string(envoy.TypeCDS),
+	}, stream.NewStreamState(false, clusterVersions), responses)
+	defer cancel()
+	a.Len(responses, 0)
+
+	// Only the changed cluster and the type it belongs to get a new version
+	a.Nil(s.UpdateProxy(ctx, proxy, resources(2*time.Second)))
+	snapshot, err = s.snapshotCache.GetSnapshot(proxy.UUID.String())
+	a.Nil(err)
+	a.NotEqual(cdsVersion, snapshot.GetVersion(string(envoy.TypeCDS)))
+	a.Equal(ldsVersion, snapshot.GetVersion(string(envoy.TypeLDS)))
+	newClusterVersions := snapshot.(*cachev3.Snapshot).GetVersionMap(string(envoy.TypeCDS))
+	a.Equal(clusterVersions["a"], newClusterVersions["a"])
+	a.NotEqual(clusterVersions["b"], newClusterVersions["b"])
+
+	// The delta watch is sent the changed cluster only
+	a.Len(responses, 1)
+	resp, err := (<-responses).GetDeltaDiscoveryResponse()
+	a.Nil(err)
+	a.Len(resp.Resources, 1)
+	a.Equal("b", resp.Resources[0].Name)
+	a.Equal(newClusterVersions["b"], resp.Resources[0].Version)
+	a.Empty(resp.RemovedResources)
+}
+
+func TestUpdateProxyUnknownType(t *testing.T) {
+	a := assert.New(t)
+
+	proxy := models.NewProxy(models.KindSidecar, uuid.New(), tests.BookstoreServiceIdentity, nil, 1)
+	s := NewADSServer()
+
+	err := s.UpdateProxy(context.Background(), proxy, map[string][]types.Resource{"unknown": nil})
+	a.Error(err)
+}
+
+func TestUpdateProxyHistory(t *testing.T) {
+	a := assert.New(t)
+
+	proxy := models.NewProxy(models.KindSidecar, uuid.New(), tests.BookstoreServiceIdentity, nil, 1)
+	s := NewADSServer(WithConfigHistory(10))
+	s.SetCallbacks(noopCallbacks{})
+
+	trigger := messaging.UpdateTrigger{Kind: "endpoint", Type: "updated", Namespace: "ns", Name: "svc"}
+	ctx := messaging.WithUpdateTriggers(context.Background(), trigger)
+	a.Nil(s.UpdateProxy(ctx, proxy, map[string][]types.Resource{
+		string(envoy.TypeCDS): {&xds_cluster.Cluster{Name: "a"}},
+	}))
+
+	entries, err := s.GetConfigHistory(proxy.UUID.String())
+	a.Nil(err)
+	a.Len(entries, 1)
+	a.Equal([]messaging.UpdateTrigger{trigger}, entries[0].Triggers)
+
+	diff, err := s.DiffConfigHistory(proxy.UUID.String(), 0, 0)
+	a.Nil(err)
+	a.Len(diff.Changes, 1)
+	a.Equal("a", diff.Changes[0].Name)
+
+	// The history is removed once the proxy disconnects
+	a.Nil(s.OnStreamRequest(proxy.GetConnectionID(), &xds_discovery.DiscoveryRequest{
+		Node:    &xds_core.Node{Id: proxy.UUID.String()},
+		TypeUrl: string(envoy.TypeCDS),
+	}))
+	s.OnStreamClosed(proxy.GetConnectionID())
+	_, err = s.GetConfigHistory(proxy.UUID.String())
+	a.ErrorIs(err, history.ErrNotFound)
+}
+
+func TestConfigHistoryDisabled(t *testing.T) {
+	a := assert.New(t)
+
+	proxy := models.NewProxy(models.KindSidecar, uuid.New(), tests.BookstoreServiceIdentity, nil, 1)
+	s := NewADSServer()
+	s.SetCallbacks(noopCallbacks{})
+
+	a.Nil(s.UpdateProxy(context.Background(), proxy, map[string][]types.Resource{
+		string(envoy.TypeCDS): {&xds_cluster.Cluster{Name: "a"}},
+	}))
+
+	_, err := s.GetConfigHistory(proxy.UUID.String())
+	a.ErrorIs(err, history.ErrDisabled)
+	_, err = s.DiffConfigHistory(proxy.UUID.String(), 0, 0)
+	a.ErrorIs(err, history.ErrDisabled)
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/models"
+)
+
+const (
+	statsRequestTimeout = 5 * time.Second
+
+	upstreamRequestsMetric = "envoy_cluster_upstream_rq_xx"
+	responseCodeClassLabel = "envoy_response_code_class"
+	serverErrorClass       = "5"
+)
+
+// upstreamStats are the cumulative upstream request counts of a proxy
+type upstreamStats struct {
+	requests uint64
+	errors   uint64
+}
+
+// since returns the stats accumulated since the given stats, and false if the counters were reset in between
+func (s upstreamStats) since(previous upstreamStats) (upstreamStats, bool) {
+	if s.requests < previous.requests || s.errors < previous.errors {
+		return upstreamStats{}, false
+	}
+	return upstreamStats{requests: s.requests - previous.requests, errors: s.errors - previous.errors}, true
+}
+
+func (s upstreamStats) add(other upstreamStats) upstreamStats {
+	return upstreamStats{requests: s.requests + other.requests, errors: s.errors + other.errors}
+}
+
+// errorRate returns the ratio of the requests that failed with a 5xx response code
+func (s upstreamStats) errorRate() float64 {
+	if s.requests == 0 {
+		return 0
+	}
+	return float64(s.errors) / float64(s.requests)
+}
+
+// getUpstreamStats returns the upstream stats of the given proxy, scraped from the Prometheus listener of the
+// proxy, which is only configured when metrics are enabled for the proxy's namespace
+func getUpstreamStats(ctx context.Context, proxy *models.Proxy) (upstreamStats, error) {
+	if proxy.GetIP() == nil {
+		return upstreamStats{}, fmt.Errorf("proxy %s has no known address", proxy.UUID)
+	}
+	host, _, err := net.SplitHostPort(proxy.GetIP().String())
+	if err != nil {
+		host = proxy.GetIP().String()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, statsRequestTimeout)
+	defer cancel()
+
+	url := fmt.Sprintf("http://%s%s", net.JoinHostPort(host, strconv.Itoa(constants.EnvoyPrometheusInboundListenerPort)), constants.PrometheusScrapePath)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return upstreamStats{}, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return upstreamStats{}, err
+	}
+	//nolint: errcheck
+	//#nosec G307
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return upstreamStats{}, fmt.Errorf("unexpected status code %d scraping %s", resp.StatusCode, url)
+	}
+
+	return parseUpstreamStats(resp.Body)
+}
+
+// parseUpstreamStats returns the upstream stats in the given Envoy stats, in the Prometheus text format
+func parseUpstreamStats(r io.Reader) (upstreamStats, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(r)
+	if err != nil {
+		return upstreamStats{}, err
+	}
+
+	var stats upstreamStats
+	family, ok := families[upstreamRequestsMetric]
+	if !ok {
+		return stats, nil
+	}
+	for _, metric := range family.GetMetric() {
+		count := uint64(metric.GetCounter().GetValue())
+		stats.requests += count
+		for _, label := range metric.GetLabel() {
+			if label.GetName() == responseCodeClassLabel && label.GetValue() == serverErrorClass {
+				stats.errors += count
+			}
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestParseUpstreamStats(t *testing.T) {
+	assert := tassert.New(t)
+
+	stats, err := parseUpstreamStats(strings.NewReader(`# TYPE envoy_cluster_upstream_rq_xx counter
+envoy_cluster_upstream_rq_xx{envoy_response_code_class="2",envoy_cluster_name="bookstore/bookstore|14001"} 90
+envoy_cluster_upstream_rq_xx{envoy_response_code_class="4",envoy_cluster_name="bookstore/bookstore|14001"} 4
+envoy_cluster_upstream_rq_xx{envoy_response_code_class="5",envoy_cluster_name="bookstore/bookstore|14001"} 6
+envoy_cluster_upstream_rq_xx{envoy_response_code_class="5",envoy_cluster_name="bookwarehouse/bookwarehouse|14001"} 10
+# TYPE envoy_cluster_upstream_cx_total counter
+envoy_cluster_upstream_cx_total{envoy_cluster_name="bookstore/bookstore|14001"} 3
+`))
+	assert.NoError(err)
+	assert.Equal(upstreamStats{requests: 110, errors: 16}, stats)
+
+	stats, err = parseUpstreamStats(strings.NewReader(""))
+	assert.NoError(err)
+	assert.Equal(upstreamStats{}, stats)
+
+	delta, ok := upstreamStats{requests: 110, errors: 16}.since(upstreamStats{requests: 10, errors: 6})
+	assert.True(ok)
+	assert.InDelta(0.1, delta.errorRate(), 0.0001)
+
+	_, ok = upstreamStats{requests: 10}.since(upstreamStats{requests: 110})
+	assert.False(ok)
+}
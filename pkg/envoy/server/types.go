@@ -3,10 +3,12 @@ package server
 
 import (
 	"context"
-	"sync"
 
 	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 
+	"github.com/openservicemesh/osm/pkg/envoy/history"
 	"github.com/openservicemesh/osm/pkg/logger"
 )
 
@@ -25,13 +27,23 @@ type streamCallback interface {
 type Server struct {
 	callbacks streamCallback
 
-	// ---
-	// SnapshotCache implementation structrues below
-	// Used to maintain a unique ID per stream. Must be accessed with the atomic package.
+	// snapshotCache holds the latest snapshot of every proxy's resources, and serves both the state of the world
+	// and the incremental (delta) variants of ADS
 	snapshotCache cachev3.SnapshotCache
-	// When snapshot cache is enabled, we (currently) don't keep track of proxy information, however different
-	// config versions have to be provided to the cache as we keep adding snapshots. The following map
-	// tracks at which version we are at given a proxy UUID
-	configVerMutex sync.Mutex
-	configVersion  map[string]uint64
+
+	// rollout holds back configuration changes until rolled out to a percentage of the proxies, if enabled
+	rollout *stagedRollout
+
+	// xdsStatus tracks the ACKs and NACKs of the responses sent to the proxies
+	xdsStatus *xdsStatusTracker
+
+	// history keeps the last versions of the configuration generated for every connected proxy
+	history *history.Store
+
+	// kubeClient and eventRecorder record the NACKs as events on the proxies' pods, if set
+	kubeClient    kubernetes.Interface
+	eventRecorder record.EventRecorder
 }
+
+// Option is an option of the ADS server
+type Option func(*Server)
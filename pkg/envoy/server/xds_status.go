@@ -0,0 +1,241 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+	"github.com/openservicemesh/osm/pkg/models"
+	"github.com/openservicemesh/osm/pkg/tracing"
+)
+
+// xdsStatusTracker tracks the ACKs and NACKs of the xDS responses sent on every stream
+type xdsStatusTracker struct {
+	mu      sync.RWMutex
+	streams map[int64]*streamStatus
+
+	// proxies are the proxies the config was last updated for, keyed by UUID
+	proxies map[string]*models.Proxy
+
+	// spanContexts are the contexts of the spans of the last config updates of the proxies, keyed by UUID, which the
+	// spans of the responses sent to them descend from
+	spanContexts map[string]trace.SpanContext
+}
+
+// streamStatus is the xDS status of a stream
+type streamStatus struct {
+	// nodeID is the ID of the node of the stream, which is only guaranteed to be set on the first request
+	nodeID string
+
+	// sent is the last response sent for each type URL, which is the only one the proxy's requests are matched
+	// against. Requests referring to older responses are stale.
+	sent map[string]sentResponse
+
+	statuses map[string]*envoy.XDSStatus
+}
+
+// sentResponse is a response sent on a stream
+type sentResponse struct {
+	nonce       string
+	version     string
+	sentAt      time.Time
+	spanContext trace.SpanContext
+}
+
+// nackEvent is a NACK of a response by a proxy
+type nackEvent struct {
+	proxy  *models.Proxy
+	status envoy.XDSStatus
+}
+
+func newXDSStatusTracker() *xdsStatusTracker {
+	return &xdsStatusTracker{
+		streams:      make(map[int64]*streamStatus),
+		proxies:      make(map[string]*models.Proxy),
+		spanContexts: make(map[string]trace.SpanContext),
+	}
+}
+
+// setProxy records the proxy the config was updated for, along with the context of the span of the update
+func (t *xdsStatusTracker) setProxy(proxy *models.Proxy, spanContext trace.SpanContext) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.proxies[proxy.UUID.String()] = proxy
+	t.spanContexts[proxy.UUID.String()] = spanContext
+}
+
+// responseSent records the response with the given nonce and version sent on the given stream
+func (t *xdsStatusTracker) responseSent(streamID int64, typeURL, nonce, version string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stream := t.getStream(streamID)
+	stream.sent[typeURL] = sentResponse{
+		nonce:       nonce,
+		version:     version,
+		sentAt:      time.Now(),
+		spanContext: t.spanContexts[stream.nodeID],
+	}
+}
+
+// requestReceived records the ACK or NACK carried by the given request received on the given stream. A NACK of the
+// last response sent is returned, or nil.
+func (t *xdsStatusTracker) requestReceived(streamID int64, nodeID, typeURL, responseNonce, errorMessage string, nack bool) *nackEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stream := t.getStream(streamID)
+	if nodeID != "" {
+		stream.nodeID = nodeID
+	}
+
+	// Initial requests don't refer to any response, and stale ones to a response which has been superseded
+	sent, ok := stream.sent[typeURL]
+	if responseNonce == "" || !ok || sent.nonce != responseNonce {
+		return nil
+	}
+
+	// Records the time the proxy took to accept or reject the response
+	_, span := tracing.Start(trace.ContextWithSpanContext(context.Background(), sent.spanContext), "xds.response",
+		trace.WithTimestamp(sent.sentAt),
+		trace.WithAttributes(
+			tracing.ProxyUUIDKey.String(stream.nodeID),
+			tracing.TypeURLKey.String(typeURL),
+			attribute.String("osm.xds.version", sent.version),
+			attribute.Bool("osm.xds.nack", nack),
+		))
+	if nack {
+		span.SetStatus(codes.Error, errorMessage)
+	}
+	span.End()
+
+	status, ok := stream.statuses[typeURL]
+	if !ok {
+		status = &envoy.XDSStatus{TypeURL: envoy.TypeURI(typeURL)}
+		stream.statuses[typeURL] = status
+	}
+
+	now := time.Now()
+	if !nack {
+		status.LastACKedVersion = sent.version
+		status.LastACKedAt = now
+		return nil
+	}
+
+	status.LastNACKedVersion = sent.version
+	status.LastNACKedAt = now
+	status.NACKMessage = errorMessage
+
+	proxy := t.proxies[stream.nodeID]
+	if proxy == nil {
+		return nil
+	}
+	return &nackEvent{proxy: proxy, status: *status}
+}
+
+// getStream returns the status of the given stream, which is created if unknown. The lock must be held.
+func (t *xdsStatusTracker) getStream(streamID int64) *streamStatus {
+	stream, ok := t.streams[streamID]
+	if !ok {
+		stream = &streamStatus{
+			sent:     make(map[string]sentResponse),
+			statuses: make(map[string]*envoy.XDSStatus),
+		}
+		t.streams[streamID] = stream
+	}
+	return stream
+}
+
+// removeStream removes the status of the given stream, and its proxy if connected over it. The UUID of the removed
+// proxy is returned, or an empty string.
+func (t *xdsStatusTracker) removeStream(streamID int64) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var removed string
+	if stream, ok := t.streams[streamID]; ok {
+		if proxy, ok := t.proxies[stream.nodeID]; ok && proxy.GetConnectionID() == streamID {
+			delete(t.proxies, stream.nodeID)
+			delete(t.spanContexts, stream.nodeID)
+			removed = stream.nodeID
+		}
+	}
+	delete(t.streams, streamID)
+	return removed
+}
+
+// getXDSStatus returns the xDS status of the proxy with the given UUID, sorted by type URL
+func (t *xdsStatusTracker) getXDSStatus(proxyUUID string) []envoy.XDSStatus {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var statuses []envoy.XDSStatus
+	for _, stream := range t.streams {
+		if stream.nodeID != proxyUUID {
+			continue
+		}
+		for _, status := range stream.statuses {
+			statuses = append(statuses, *status)
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].TypeURL < statuses[j].TypeURL
+	})
+	return statuses
+}
+
+// GetXDSStatus returns the last versions of each resource type the proxy with the given UUID accepted and rejected
+func (s *Server) GetXDSStatus(proxyUUID string) []envoy.XDSStatus {
+	return s.xdsStatus.getXDSStatus(proxyUUID)
+}
+
+// WithNACKEvents records a Kubernetes event on the pod of a proxy which rejects its configuration
+func WithNACKEvents(kubeClient kubernetes.Interface) Option {
+	return func(s *Server) {
+		s.kubeClient = kubeClient
+		s.eventRecorder = events.NewRecorder(kubeClient, metav1.NamespaceAll)
+	}
+}
+
+// onNACK reports the rejection of a response by a proxy
+func (s *Server) onNACK(nack *nackEvent) {
+	typ := nack.status.TypeURL.Short()
+	log.Warn().Str("proxy", nack.proxy.String()).Str("type", typ).Str("version", nack.status.LastNACKedVersion).
+		Msgf("Proxy rejected its configuration: %s", nack.status.NACKMessage)
+	metricsstore.DefaultMetricsStore.ProxyXDSNACKCount.
+		WithLabelValues(nack.proxy.UUID.String(), nack.proxy.Identity.String(), typ).Inc()
+
+	if s.rollout != nil {
+		s.rollout.recordNACK(nack.proxy.UUID.String(), string(nack.status.TypeURL), nack.status.NACKMessage)
+	}
+	if s.eventRecorder != nil {
+		go s.recordNACKEvent(nack)
+	}
+}
+
+// recordNACKEvent records a Kubernetes event on the pod of the proxy which rejected its configuration
+func (s *Server) recordNACKEvent(nack *nackEvent) {
+	pods, err := s.kubeClient.CoreV1().Pods(nack.proxy.Identity.ToK8sServiceAccount().Namespace).List(context.Background(),
+		metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", constants.EnvoyUniqueIDLabelName, nack.proxy.UUID)})
+	if err != nil {
+		log.Error().Err(err).Str("proxy", nack.proxy.String()).Msg("Error listing the pod of the proxy to record its NACK")
+		return
+	}
+	for i := range pods.Items {
+		s.eventRecorder.Eventf(&pods.Items[i], corev1.EventTypeWarning, events.ProxyConfigNACKed,
+			"Proxy rejected %s configuration version %s: %s",
+			nack.status.TypeURL.Short(), nack.status.LastNACKedVersion, nack.status.NACKMessage)
+	}
+}
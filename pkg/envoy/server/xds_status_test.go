@@ -0,0 +1,175 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/models"
+	"github.com/openservicemesh/osm/pkg/tracing"
+)
+
+type noopCallbacks struct{}
+
+func (noopCallbacks) ProxyConnected(context.Context, int64) error { return nil }
+func (noopCallbacks) ProxyDisconnected(int64)                     {}
+
+func TestXDSStatus(t *testing.T) {
+	a := tassert.New(t)
+
+	proxy := models.NewProxy(models.KindSidecar, uuid.New(), identity.New("sa", "ns"), nil, 1)
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "pod",
+		Namespace: "ns",
+		Labels:    map[string]string{constants.EnvoyUniqueIDLabelName: proxy.UUID.String()},
+	}}
+	recorder := record.NewFakeRecorder(10)
+
+	s := NewADSServer()
+	s.SetCallbacks(noopCallbacks{})
+	s.kubeClient = fake.NewSimpleClientset(pod)
+	s.eventRecorder = recorder
+	s.xdsStatus.setProxy(proxy, trace.SpanContext{})
+
+	node := &xds_core.Node{Id: proxy.UUID.String()}
+	request := func(nonce string, errorMessage string) {
+		req := &xds_discovery.DiscoveryRequest{Node: node, TypeUrl: envoy.TypeLDS.String(), ResponseNonce: nonce}
+		if errorMessage != "" {
+			req.ErrorDetail = &status.Status{Message: errorMessage}
+		}
+		a.NoError(s.OnStreamRequest(1, req))
+	}
+	respond := func(nonce string, version string) {
+		s.OnStreamResponse(context.Background(), 1, &xds_discovery.DiscoveryRequest{Node: node},
+			&xds_discovery.DiscoveryResponse{TypeUrl: envoy.TypeLDS.String(), Nonce: nonce, VersionInfo: version})
+	}
+
+	// The initial request doesn't ACK anything
+	request("", "")
+	a.Empty(s.GetXDSStatus(proxy.UUID.String()))
+
+	respond("1", "v1")
+	request("1", "")
+	statuses := s.GetXDSStatus(proxy.UUID.String())
+	a.Len(statuses, 1)
+	a.Equal(envoy.TypeLDS, statuses[0].TypeURL)
+	a.Equal("v1", statuses[0].LastACKedVersion)
+	a.False(statuses[0].IsNACKed())
+
+	// A request referring to a superseded response is ignored
+	respond("2", "v2")
+	request("1", "stale")
+	statuses = s.GetXDSStatus(proxy.UUID.String())
+	a.Equal("v1", statuses[0].LastACKedVersion)
+	a.Empty(statuses[0].LastNACKedVersion)
+
+	request("2", "invalid listener")
+	statuses = s.GetXDSStatus(proxy.UUID.String())
+	a.Equal("v1", statuses[0].LastACKedVersion)
+	a.Equal("v2", statuses[0].LastNACKedVersion)
+	a.Equal("invalid listener", statuses[0].NACKMessage)
+	a.True(statuses[0].IsNACKed())
+
+	// The NACK is recorded as an event on the proxy's pod
+	select {
+	case event := <-recorder.Events:
+		a.Equal("Warning ProxyConfigNACKed Proxy rejected LDS configuration version v2: invalid listener", event)
+	case <-time.After(5 * time.Second):
+		a.Fail("NACK event not recorded")
+	}
+
+	// A later ACK clears the NACK
+	respond("3", "v3")
+	request("3", "")
+	statuses = s.GetXDSStatus(proxy.UUID.String())
+	a.Equal("v3", statuses[0].LastACKedVersion)
+	a.Equal("v2", statuses[0].LastNACKedVersion)
+	a.False(statuses[0].IsNACKed())
+
+	s.OnStreamClosed(1)
+	a.Empty(s.GetXDSStatus(proxy.UUID.String()))
+}
+
+func TestXDSStatusDelta(t *testing.T) {
+	a := tassert.New(t)
+
+	proxy := models.NewProxy(models.KindSidecar, uuid.New(), identity.New("sa", "ns"), nil, 1)
+	s := NewADSServer()
+	s.SetCallbacks(noopCallbacks{})
+	s.xdsStatus.setProxy(proxy, trace.SpanContext{})
+
+	// The node is only set on the first request of a delta stream
+	a.NoError(s.OnStreamDeltaRequest(1, &xds_discovery.DeltaDiscoveryRequest{
+		Node:    &xds_core.Node{Id: proxy.UUID.String()},
+		TypeUrl: envoy.TypeCDS.String(),
+	}))
+	s.OnStreamDeltaResponse(1, &xds_discovery.DeltaDiscoveryRequest{},
+		&xds_discovery.DeltaDiscoveryResponse{TypeUrl: envoy.TypeCDS.String(), Nonce: "1", SystemVersionInfo: "v1"})
+	a.NoError(s.OnStreamDeltaRequest(1, &xds_discovery.DeltaDiscoveryRequest{
+		TypeUrl:       envoy.TypeCDS.String(),
+		ResponseNonce: "1",
+		ErrorDetail:   &status.Status{Message: "invalid cluster"},
+	}))
+
+	statuses := s.GetXDSStatus(proxy.UUID.String())
+	a.Len(statuses, 1)
+	a.Equal("v1", statuses[0].LastNACKedVersion)
+	a.Equal("invalid cluster", statuses[0].NACKMessage)
+	a.True(statuses[0].IsNACKed())
+
+	s.OnDeltaStreamClosed(1)
+	a.Empty(s.GetXDSStatus(proxy.UUID.String()))
+}
+
+func TestXDSResponseTracing(t *testing.T) {
+	a := tassert.New(t)
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	proxy := models.NewProxy(models.KindSidecar, uuid.New(), identity.New("sa", "ns"), nil, 1)
+	s := NewADSServer()
+	s.SetCallbacks(noopCallbacks{})
+
+	ctx, span := tracing.Tracer().Start(context.Background(), "ControlPlane.update")
+	a.NoError(s.UpdateProxy(ctx, proxy, map[string][]types.Resource{}))
+	span.End()
+
+	node := &xds_core.Node{Id: proxy.UUID.String()}
+	a.NoError(s.OnStreamRequest(1, &xds_discovery.DiscoveryRequest{Node: node, TypeUrl: envoy.TypeLDS.String()}))
+	s.OnStreamResponse(context.Background(), 1, &xds_discovery.DiscoveryRequest{Node: node},
+		&xds_discovery.DiscoveryResponse{TypeUrl: envoy.TypeLDS.String(), Nonce: "1", VersionInfo: "v1"})
+	a.NoError(s.OnStreamRequest(1, &xds_discovery.DiscoveryRequest{Node: node, TypeUrl: envoy.TypeLDS.String(), ResponseNonce: "1"}))
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	a.Len(spans, 3)
+	a.Equal(span.SpanContext().SpanID(), spans["ADS.UpdateProxy"].Parent().SpanID())
+
+	// The ACK of the response is traced as part of the update
+	response := spans["xds.response"]
+	a.Equal(spans["ADS.UpdateProxy"].SpanContext().SpanID(), response.Parent().SpanID())
+	a.Contains(response.Attributes(), tracing.TypeURLKey.String(envoy.TypeLDS.String()))
+	a.Contains(response.Attributes(), attribute.Bool("osm.xds.nack", false))
+}
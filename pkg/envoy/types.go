@@ -3,6 +3,8 @@
 package envoy
 
 import (
+	"time"
+
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 )
 
@@ -134,3 +136,30 @@ const (
 	// active health check traffic.
 	EnvoyActiveHealthCheckHeaderKey = "x-osm-envoy-healthcheck"
 )
+
+// XDSStatus is the status of the configuration of a resource type on a proxy, as reported by the proxy's ACKs and
+// NACKs of the xDS responses sent to it
+type XDSStatus struct {
+	// TypeURL is the resource type
+	TypeURL TypeURI `json:"typeURL"`
+
+	// LastACKedVersion is the version of the last response the proxy accepted
+	LastACKedVersion string `json:"lastACKedVersion,omitempty"`
+
+	// LastACKedAt is the time the proxy accepted LastACKedVersion
+	LastACKedAt time.Time `json:"lastACKedAt,omitempty"`
+
+	// LastNACKedVersion is the version of the last response the proxy rejected
+	LastNACKedVersion string `json:"lastNACKedVersion,omitempty"`
+
+	// LastNACKedAt is the time the proxy rejected LastNACKedVersion
+	LastNACKedAt time.Time `json:"lastNACKedAt,omitempty"`
+
+	// NACKMessage is the error the proxy rejected LastNACKedVersion with
+	NACKMessage string `json:"nackMessage,omitempty"`
+}
+
+// IsNACKed returns whether the last response of the resource type was rejected by the proxy
+func (s XDSStatus) IsNACKed() bool {
+	return s.LastNACKedAt.After(s.LastACKedAt)
+}
@@ -28,6 +28,8 @@ type ConfigV1alpha2Interface interface {
 	ExtensionServicesGetter
 	MeshConfigsGetter
 	MeshRootCertificatesGetter
+	SidecarProfilesGetter
+	TrustDomainFederationsGetter
 }
 
 // ConfigV1alpha2Client is used to interact with features provided by the config.openservicemesh.io group.
@@ -47,6 +49,14 @@ func (c *ConfigV1alpha2Client) MeshRootCertificates(namespace string) MeshRootCe
 	return newMeshRootCertificates(c, namespace)
 }
 
+func (c *ConfigV1alpha2Client) SidecarProfiles(namespace string) SidecarProfileInterface {
+	return newSidecarProfiles(c, namespace)
+}
+
+func (c *ConfigV1alpha2Client) TrustDomainFederations(namespace string) TrustDomainFederationInterface {
+	return newTrustDomainFederations(c, namespace)
+}
+
 // NewForConfig creates a new ConfigV1alpha2Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
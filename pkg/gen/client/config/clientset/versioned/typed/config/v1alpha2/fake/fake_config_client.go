@@ -37,6 +37,14 @@ func (c *FakeConfigV1alpha2) MeshRootCertificates(namespace string) v1alpha2.Mes
 	return &FakeMeshRootCertificates{c, namespace}
 }
 
+func (c *FakeConfigV1alpha2) SidecarProfiles(namespace string) v1alpha2.SidecarProfileInterface {
+	return &FakeSidecarProfiles{c, namespace}
+}
+
+func (c *FakeConfigV1alpha2) TrustDomainFederations(namespace string) v1alpha2.TrustDomainFederationInterface {
+	return &FakeTrustDomainFederations{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeConfigV1alpha2) RESTClient() rest.Interface {
@@ -20,3 +20,7 @@ type ExtensionServiceExpansion interface{}
 type MeshConfigExpansion interface{}
 
 type MeshRootCertificateExpansion interface{}
+
+type SidecarProfileExpansion interface{}
+
+type TrustDomainFederationExpansion interface{}
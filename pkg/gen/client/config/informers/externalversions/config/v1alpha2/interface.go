@@ -27,6 +27,10 @@ type Interface interface {
 	MeshConfigs() MeshConfigInformer
 	// MeshRootCertificates returns a MeshRootCertificateInformer.
 	MeshRootCertificates() MeshRootCertificateInformer
+	// SidecarProfiles returns a SidecarProfileInformer.
+	SidecarProfiles() SidecarProfileInformer
+	// TrustDomainFederations returns a TrustDomainFederationInformer.
+	TrustDomainFederations() TrustDomainFederationInformer
 }
 
 type version struct {
@@ -54,3 +58,13 @@ func (v *version) MeshConfigs() MeshConfigInformer {
 func (v *version) MeshRootCertificates() MeshRootCertificateInformer {
 	return &meshRootCertificateInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// SidecarProfiles returns a SidecarProfileInformer.
+func (v *version) SidecarProfiles() SidecarProfileInformer {
+	return &sidecarProfileInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// TrustDomainFederations returns a TrustDomainFederationInformer.
+func (v *version) TrustDomainFederations() TrustDomainFederationInformer {
+	return &trustDomainFederationInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -61,6 +61,10 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Config().V1alpha2().MeshConfigs().Informer()}, nil
 	case v1alpha2.SchemeGroupVersion.WithResource("meshrootcertificates"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Config().V1alpha2().MeshRootCertificates().Informer()}, nil
+	case v1alpha2.SchemeGroupVersion.WithResource("sidecarprofiles"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Config().V1alpha2().SidecarProfiles().Informer()}, nil
+	case v1alpha2.SchemeGroupVersion.WithResource("trustdomainfederations"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Config().V1alpha2().TrustDomainFederations().Informer()}, nil
 
 	}
 
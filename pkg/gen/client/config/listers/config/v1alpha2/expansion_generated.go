@@ -38,3 +38,19 @@ type MeshRootCertificateListerExpansion interface{}
 // MeshRootCertificateNamespaceListerExpansion allows custom methods to be added to
 // MeshRootCertificateNamespaceLister.
 type MeshRootCertificateNamespaceListerExpansion interface{}
+
+// SidecarProfileListerExpansion allows custom methods to be added to
+// SidecarProfileLister.
+type SidecarProfileListerExpansion interface{}
+
+// SidecarProfileNamespaceListerExpansion allows custom methods to be added to
+// SidecarProfileNamespaceLister.
+type SidecarProfileNamespaceListerExpansion interface{}
+
+// TrustDomainFederationListerExpansion allows custom methods to be added to
+// TrustDomainFederationLister.
+type TrustDomainFederationListerExpansion interface{}
+
+// TrustDomainFederationNamespaceListerExpansion allows custom methods to be added to
+// TrustDomainFederationNamespaceLister.
+type TrustDomainFederationNamespaceListerExpansion interface{}
@@ -45,6 +45,10 @@ func (c *FakePolicyV1alpha1) UpstreamTrafficSettings(namespace string) v1alpha1.
 	return &FakeUpstreamTrafficSettings{c, namespace}
 }
 
+func (c *FakePolicyV1alpha1) WorkloadEntries(namespace string) v1alpha1.WorkloadEntryInterface {
+	return &FakeWorkloadEntries{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakePolicyV1alpha1) RESTClient() rest.Interface {
@@ -24,3 +24,5 @@ type RetryExpansion interface{}
 type TelemetryExpansion interface{}
 
 type UpstreamTrafficSettingExpansion interface{}
+
+type WorkloadEntryExpansion interface{}
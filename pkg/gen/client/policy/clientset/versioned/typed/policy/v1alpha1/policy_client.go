@@ -30,6 +30,7 @@ type PolicyV1alpha1Interface interface {
 	RetriesGetter
 	TelemetriesGetter
 	UpstreamTrafficSettingsGetter
+	WorkloadEntriesGetter
 }
 
 // PolicyV1alpha1Client is used to interact with features provided by the policy.openservicemesh.io group.
@@ -57,6 +58,10 @@ func (c *PolicyV1alpha1Client) UpstreamTrafficSettings(namespace string) Upstrea
 	return newUpstreamTrafficSettings(c, namespace)
 }
 
+func (c *PolicyV1alpha1Client) WorkloadEntries(namespace string) WorkloadEntryInterface {
+	return newWorkloadEntries(c, namespace)
+}
+
 // NewForConfig creates a new PolicyV1alpha1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
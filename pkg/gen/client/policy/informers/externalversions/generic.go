@@ -60,6 +60,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Policy().V1alpha1().Telemetries().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("upstreamtrafficsettings"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Policy().V1alpha1().UpstreamTrafficSettings().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("workloadentries"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Policy().V1alpha1().WorkloadEntries().Informer()}, nil
 
 	}
 
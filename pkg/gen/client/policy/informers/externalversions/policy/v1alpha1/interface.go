@@ -31,6 +31,8 @@ type Interface interface {
 	Telemetries() TelemetryInformer
 	// UpstreamTrafficSettings returns a UpstreamTrafficSettingInformer.
 	UpstreamTrafficSettings() UpstreamTrafficSettingInformer
+	// WorkloadEntries returns a WorkloadEntryInformer.
+	WorkloadEntries() WorkloadEntryInformer
 }
 
 type version struct {
@@ -68,3 +70,8 @@ func (v *version) Telemetries() TelemetryInformer {
 func (v *version) UpstreamTrafficSettings() UpstreamTrafficSettingInformer {
 	return &upstreamTrafficSettingInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// WorkloadEntries returns a WorkloadEntryInformer.
+func (v *version) WorkloadEntries() WorkloadEntryInformer {
+	return &workloadEntryInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
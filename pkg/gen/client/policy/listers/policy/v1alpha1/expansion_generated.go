@@ -54,3 +54,11 @@ type UpstreamTrafficSettingListerExpansion interface{}
 // UpstreamTrafficSettingNamespaceListerExpansion allows custom methods to be added to
 // UpstreamTrafficSettingNamespaceLister.
 type UpstreamTrafficSettingNamespaceListerExpansion interface{}
+
+// WorkloadEntryListerExpansion allows custom methods to be added to
+// WorkloadEntryLister.
+type WorkloadEntryListerExpansion interface{}
+
+// WorkloadEntryNamespaceListerExpansion allows custom methods to be added to
+// WorkloadEntryNamespaceLister.
+type WorkloadEntryNamespaceListerExpansion interface{}
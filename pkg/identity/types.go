@@ -9,6 +9,9 @@ import (
 const (
 	// namespaceNameSeparator used for marshalling/unmarshalling MeshService to a string or vice versa
 	namespaceNameSeparator = "/"
+
+	// spiffeIDPrefix is the URI scheme prefix of a SPIFFE ID
+	spiffeIDPrefix = "spiffe://"
 )
 
 // ServiceIdentity is the type used to represent the identity for a service
@@ -36,18 +39,29 @@ func (si ServiceIdentity) IsWildcard() bool {
 	return si == WildcardServiceIdentity
 }
 
+// IsSpiffeID determines if the ServiceIdentity is a fully qualified SPIFFE ID, such as the identity of a workload
+// in a federated trust domain, rather than a <ServiceAccount>.<Namespace> identity within the mesh.
+func (si ServiceIdentity) IsSpiffeID() bool {
+	return strings.HasPrefix(si.String(), spiffeIDPrefix)
+}
+
 // AsPrincipal converts the ServiceIdentity to a Principal with the given trust domain.
-// If identity is Spiffe ID is enabled then it will return the value in Spiffe format
+// If identity is Spiffe ID is enabled then it will return the value in Spiffe format.
+// A ServiceIdentity that is already a SPIFFE ID carries its own trust domain and is returned as is.
 func (si ServiceIdentity) AsPrincipal(trustDomain string, spiffeEnabled bool) string {
+	if si.IsSpiffeID() {
+		return si.String()
+	}
+
 	if si.IsWildcard() {
 		if spiffeEnabled {
-			return fmt.Sprintf("spiffe://%s", trustDomain)
+			return fmt.Sprintf("%s%s", spiffeIDPrefix, trustDomain)
 		}
 		return si.String()
 	}
 
 	if spiffeEnabled {
-		return fmt.Sprintf("spiffe://%s/%s", trustDomain, strings.Replace(si.String(), ".", "/", -1))
+		return fmt.Sprintf("%s%s/%s", spiffeIDPrefix, trustDomain, strings.Replace(si.String(), ".", "/", -1))
 	}
 
 	return fmt.Sprintf("%s.%s", si.String(), trustDomain)
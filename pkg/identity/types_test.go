@@ -19,6 +19,10 @@ func TestServiceIdentityType(t *testing.T) {
 	notWildcard := ServiceIdentity("foo.bar")
 	assert.False(notWildcard.IsWildcard())
 
+	// Test IsSpiffeID()
+	assert.True(ServiceIdentity("spiffe://other-domain/ns/sa").IsSpiffeID())
+	assert.False(notWildcard.IsSpiffeID())
+
 	// Test ToK8sServiceAccount()
 	assert.Equal(K8sServiceAccount{Name: "foo", Namespace: "bar"}, si.ToK8sServiceAccount())
 }
@@ -93,6 +97,13 @@ func TestServiceIdentity_AsPrincipal(t *testing.T) {
 			spiffeEnabled:     true,
 			expectedPrincipal: "spiffe://cluster.local",
 		},
+		{
+			name:              "SPIFFE ID of a federated trust domain is used as is",
+			si:                ServiceIdentity("spiffe://other-domain/ns/sa"),
+			trustDomain:       "cluster.local",
+			spiffeEnabled:     false,
+			expectedPrincipal: "spiffe://other-domain/ns/sa",
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -9,7 +9,7 @@ import (
 )
 
 // Initialize initializes the client and starts the ingress gateway certificate manager routine
-func Initialize(kubeClient kubernetes.Interface, kubeController k8s.Controller, stop chan struct{},
+func Initialize(kubeClient kubernetes.Interface, kubeController k8s.Controller, stop <-chan struct{},
 	certProvider *certificate.Manager, msgBroker *messaging.Broker) {
 	c := &client{
 		kubeClient:     kubeClient,
@@ -17,7 +17,9 @@ import (
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/envoy/bootstrap"
 	"github.com/openservicemesh/osm/pkg/errcode"
+	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/models"
+	"github.com/openservicemesh/osm/pkg/proxyless"
 	"github.com/openservicemesh/osm/pkg/utils"
 	"github.com/openservicemesh/osm/pkg/version"
 )
@@ -45,7 +47,7 @@ func (wh *mutatingWebhook) createEnvoyBootstrapFromExisting(proxyUUID uuid.UUID,
 	return wh.marshalAndSaveBootstrap(bootstrapConfigName(proxyUUID), namespace, config, cert)
 }
 
-func (wh *mutatingWebhook) createEnvoyBootstrapConfig(proxyUUID uuid.UUID, namespace string, cert *certificate.Certificate, originalHealthProbes map[string]models.HealthProbes) (*corev1.Secret, error) {
+func (wh *mutatingWebhook) createEnvoyBootstrapConfig(proxyUUID uuid.UUID, namespace string, cert *certificate.Certificate, originalHealthProbes map[string]models.HealthProbes, enableReadyListener bool, statsTags map[string]string) (*corev1.Secret, error) {
 	builder := bootstrap.Builder{
 		NodeID: proxyUUID.String(),
 
@@ -59,6 +61,13 @@ func (wh *mutatingWebhook) createEnvoyBootstrapConfig(proxyUUID uuid.UUID, names
 		TLSMaxProtocolVersion: wh.kubeController.GetMeshConfig().Spec.Sidecar.TLSMaxProtocolVersion,
 		CipherSuites:          wh.kubeController.GetMeshConfig().Spec.Sidecar.CipherSuites,
 		ECDHCurves:            wh.kubeController.GetMeshConfig().Spec.Sidecar.ECDHCurves,
+
+		EnableDeltaXDS: wh.kubeController.GetMeshConfig().Spec.FeatureFlags.EnableDeltaXDS,
+
+		// The readiness of the proxy is probed by the kubelet when the proxy is a native sidecar container
+		EnableReadyListener: enableReadyListener,
+
+		StatsTags: statsTags,
 	}
 	bootstrapConfig, err := builder.Build()
 	if err != nil {
@@ -150,24 +159,39 @@ func (b *BootstrapSecretRotator) rotateBootstrapSecrets(ctx context.Context) {
 					Msgf("Error getting cert from bootstrap secret %s/%s", secret.Namespace, secret.Name)
 				return err
 			}
-			if !b.certManager.ShouldRotate(cert) {
-				return nil
+			var updated bool
+			if b.certManager.ShouldRotate(cert) {
+				issuedCert, err := b.certManager.IssueCertificate(certificate.ForCommonName(cert.CommonName.String()))
+				if err != nil {
+					log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrRotatingCert)).Msgf("Error rotating cert for bootstrap secret %s/%s", secret.Namespace, secret.Name)
+					return err
+				}
+
+				secret.Data[bootstrap.EnvoyXDSCACertFile] = issuedCert.GetTrustedCAs()
+				secret.Data[bootstrap.EnvoyXDSCertFile] = issuedCert.GetCertificateChain()
+				secret.Data[bootstrap.EnvoyXDSKeyFile] = issuedCert.GetPrivateKey()
+				secret.Data[signingIssuerIDKey] = []byte(issuedCert.GetSigningIssuerID())
+				secret.Data[validatingIssuerIDKey] = []byte(issuedCert.GetValidatingIssuerID())
+				updated = true
 			}
 
-			issuedCert, err := b.certManager.IssueCertificate(certificate.ForCommonName(cert.CommonName.String()))
-			if err != nil {
-				log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrRotatingCert)).Msgf("Error rotating cert for bootstrap secret %s/%s", secret.Namespace, secret.Name)
-				return err
+			// The bootstrap secrets of proxyless gRPC pods also hold the service certificate, which gRPC reloads
+			// from the mounted files when it is rotated
+			if si, ok := secret.Data[proxyless.ServiceIdentityKey]; ok {
+				serviceCert, err := b.certManager.IssueCertificate(certificate.ForServiceIdentity(identity.ServiceIdentity(si)))
+				if err != nil {
+					log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrRotatingCert)).Msgf("Error rotating service cert for bootstrap secret %s/%s", secret.Namespace, secret.Name)
+					return err
+				}
+				if setProxylessServiceCertificate(secret.Data, serviceCert) {
+					updated = true
+				}
 			}
 
-			secret.Data[bootstrap.EnvoyXDSCACertFile] = issuedCert.GetTrustedCAs()
-			secret.Data[bootstrap.EnvoyXDSCertFile] = issuedCert.GetCertificateChain()
-			secret.Data[bootstrap.EnvoyXDSKeyFile] = issuedCert.GetPrivateKey()
-			secret.Data[signingIssuerIDKey] = []byte(issuedCert.GetSigningIssuerID())
-			secret.Data[validatingIssuerIDKey] = []byte(issuedCert.GetValidatingIssuerID())
-
-			err = b.computeInterface.UpdateSecret(ctx, secret)
-			return err
+			if !updated {
+				return nil
+			}
+			return b.computeInterface.UpdateSecret(ctx, secret)
 		})
 		if err != nil {
 			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrUpdatingBootstrapSecret)).
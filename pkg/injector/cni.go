@@ -0,0 +1,158 @@
+package injector
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/models"
+)
+
+const (
+	// cniValidationUID is the user ID of the CNI validation container, which must not be Envoy's as the traffic of
+	// Envoy is not redirected
+	cniValidationUID int64 = 1501
+
+	// cniValidationPort is the first port the CNI validation container tries to connect to, unless excluded from
+	// the redirection
+	cniValidationPort = 80
+)
+
+// cniValidationIPs are the addresses the CNI validation container connects to, unless the pod restricts the
+// redirection to other IP ranges. They belong to the documentation ranges, which are not routed, so that the
+// connections only succeed when redirected to the sidecar.
+var cniValidationIPs = []string{"192.0.2.1", "2001:db8::1"}
+
+// IsTrafficRedirectedByCNI returns whether the traffic of the given pod must be redirected to its sidecar by the
+// OSM CNI plugin, i.e. whether an Envoy sidecar was injected into the Linux pod without the init container
+// redirecting its traffic.
+func IsTrafficRedirectedByCNI(pod *corev1.Pod) bool {
+	if _, ok := pod.Labels[constants.EnvoyUniqueIDLabelName]; !ok {
+		return false
+	}
+	if kind, err := getProxyKind(pod); err != nil || kind != models.KindSidecar {
+		return false
+	}
+	if strings.EqualFold(pod.Spec.NodeSelector["kubernetes.io/os"], constants.OSWindows) {
+		return false
+	}
+	for _, container := range pod.Spec.InitContainers {
+		if container.Name == constants.InitContainerName {
+			return false
+		}
+	}
+	return true
+}
+
+// GenerateTrafficRedirectionCommandsForPod generates the iptables or nftables commands to set up the interception and
+// redirection of the traffic of the given pod to its sidecar, from the exclusion lists and redirection backend of the
+// pod annotations and the MeshConfig. The commands are the ones run by the init container, and expect the POD_IPS
+// environment variable to hold the comma separated list of pod IP addresses.
+func GenerateTrafficRedirectionCommandsForPod(pod *corev1.Pod, meshConfig configv1alpha2.MeshConfig) (string, error) {
+	lists, err := getInterceptionLists(pod, pod.Namespace, meshConfig)
+	if err != nil {
+		return "", err
+	}
+	backend, err := getTrafficRedirectionBackend(pod, meshConfig)
+	if err != nil {
+		return "", err
+	}
+
+	return generateTrafficRedirectionCommands(backend, meshConfig.Spec.Sidecar.LocalProxyMode, lists.outboundIPRangeExclusionList, lists.outboundIPRangeInclusionList, lists.outboundPortExclusionList, lists.inboundPortExclusionList, lists.networkInterfaceExclusionList), nil
+}
+
+// getCNIValidationContainerSpec returns the init container failing until the outbound traffic of the pod is
+// redirected to its sidecar, so that pods whose traffic was not redirected by the OSM CNI plugin, e.g. because it is
+// not installed on the node, don't start without their traffic going through the sidecar.
+func getCNIValidationContainerSpec(image string, pullPolicy corev1.PullPolicy, lists *interceptionLists) (corev1.Container, error) {
+	addresses := getCNIValidationAddresses(lists)
+	if len(addresses) == 0 {
+		return corev1.Container{}, fmt.Errorf("no outbound traffic is redirected to the sidecar to validate the OSM CNI plugin with")
+	}
+
+	return corev1.Container{
+		Name:            constants.CNIValidationContainerName,
+		Image:           image,
+		ImagePullPolicy: pullPolicy,
+		Command:         []string{"/osm-cni"},
+		Args:            []string{"validate", "--address", strings.Join(addresses, ",")},
+		SecurityContext: &corev1.SecurityContext{
+			RunAsNonRoot:             pointer.BoolPtr(true),
+			RunAsUser:                pointer.Int64Ptr(cniValidationUID),
+			AllowPrivilegeEscalation: pointer.BoolPtr(false),
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+			SeccompProfile: &corev1.SeccompProfile{
+				Type: corev1.SeccompProfileTypeRuntimeDefault,
+			},
+		},
+	}, nil
+}
+
+// getCNIValidationAddresses returns the addresses whose traffic is redirected to the sidecar given the interception
+// lists of the pod: an address of each IP range the redirection is restricted to, or of the documentation ranges
+// otherwise, on a port not excluded from the redirection
+func getCNIValidationAddresses(lists *interceptionLists) []string {
+	port := cniValidationPort
+	for isPortExcluded(port, lists.outboundPortExclusionList) {
+		port++
+	}
+
+	ips := cniValidationIPs
+	if len(lists.outboundIPRangeInclusionList) > 0 {
+		ips = nil
+		for _, cidr := range lists.outboundIPRangeInclusionList {
+			if ip := getHostIP(cidr); ip != nil {
+				ips = append(ips, ip.String())
+			}
+		}
+	}
+
+	var addresses []string
+	for _, ip := range ips {
+		if !isIPExcluded(net.ParseIP(ip), lists.outboundIPRangeExclusionList) {
+			addresses = append(addresses, net.JoinHostPort(ip, strconv.Itoa(port)))
+		}
+	}
+	return addresses
+}
+
+// getHostIP returns the first host address of the given IP range, skipping the network address of ranges holding
+// more than one address
+func getHostIP(cidr string) net.IP {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil
+	}
+	ip := make(net.IP, len(ipNet.IP))
+	copy(ip, ipNet.IP)
+	if ones, bits := ipNet.Mask.Size(); ones < bits {
+		ip[len(ip)-1]++
+	}
+	return ip
+}
+
+func isPortExcluded(port int, exclusionList []int) bool {
+	for _, excluded := range exclusionList {
+		if port == excluded {
+			return true
+		}
+	}
+	return false
+}
+
+func isIPExcluded(ip net.IP, exclusionList []string) bool {
+	for _, cidr := range exclusionList {
+		if _, ipNet, err := net.ParseCIDR(cidr); err == nil && ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
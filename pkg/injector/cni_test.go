@@ -0,0 +1,227 @@
+package injector
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func TestIsTrafficRedirectedByCNI(t *testing.T) {
+	injectedLabels := map[string]string{constants.EnvoyUniqueIDLabelName: "uuid"}
+
+	testCases := []struct {
+		name     string
+		pod      *corev1.Pod
+		expected bool
+	}{
+		{
+			name: "sidecar injected without init container",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Labels: injectedLabels},
+			},
+			expected: true,
+		},
+		{
+			name:     "sidecar not injected",
+			pod:      &corev1.Pod{},
+			expected: false,
+		},
+		{
+			name: "sidecar injected with init container",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Labels: injectedLabels},
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{{Name: constants.InitContainerName}},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "proxyless gRPC pod",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      injectedLabels,
+					Annotations: map[string]string{constants.ProxyKindAnnotation: "grpc"},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "windows pod",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Labels: injectedLabels},
+				Spec: corev1.PodSpec{
+					NodeSelector: map[string]string{"kubernetes.io/os": constants.OSWindows},
+				},
+			},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expected, IsTrafficRedirectedByCNI(tc.pod))
+		})
+	}
+}
+
+func TestGenerateTrafficRedirectionCommandsForPod(t *testing.T) {
+	meshConfig := v1alpha2.MeshConfig{
+		Spec: v1alpha2.MeshConfigSpec{
+			Sidecar: v1alpha2.SidecarSpec{
+				LocalProxyMode: v1alpha2.LocalProxyModePodIP,
+			},
+			Traffic: v1alpha2.TrafficSpec{
+				OutboundPortExclusionList:     []int{6060},
+				InboundPortExclusionList:      []int{7070},
+				OutboundIPRangeExclusionList:  []string{"10.0.0.0/8"},
+				NetworkInterfaceExclusionList: []string{"eth1"},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		expected    string
+		expectErr   bool
+	}{
+		{
+			name:     "MeshConfig lists",
+			expected: generateIptablesCommands(v1alpha2.LocalProxyModePodIP, []string{"10.0.0.0/8"}, nil, []int{6060}, []int{7070}, []string{"eth1"}),
+		},
+		{
+			name: "pod annotations merged with MeshConfig lists",
+			annotations: map[string]string{
+				outboundPortExclusionListAnnotation:    "6060",
+				inboundPortExclusionListAnnotation:     "7070",
+				outboundIPRangeExclusionListAnnotation: "192.168.0.0/16",
+				outboundIPRangeInclusionListAnnotation: "172.16.0.0/12",
+			},
+			expected: generateIptablesCommands(v1alpha2.LocalProxyModePodIP, []string{"192.168.0.0/16", "10.0.0.0/8"}, []string{"172.16.0.0/12"}, []int{6060}, []int{7070}, []string{"eth1"}),
+		},
+		{
+			name: "nftables backend selected by pod annotation",
+			annotations: map[string]string{
+				trafficRedirectionBackendAnnotation: "nftables",
+			},
+			expected: generateNftablesCommands(v1alpha2.LocalProxyModePodIP, []string{"10.0.0.0/8"}, nil, []int{6060}, []int{7070}, []string{"eth1"}),
+		},
+		{
+			name: "invalid traffic redirection backend annotation",
+			annotations: map[string]string{
+				trafficRedirectionBackendAnnotation: "ebpf",
+			},
+			expectErr: true,
+		},
+		{
+			name: "invalid pod annotation",
+			annotations: map[string]string{
+				outboundPortExclusionListAnnotation: "invalid",
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pod",
+					Namespace:   "ns",
+					Annotations: tc.annotations,
+				},
+			}
+
+			actual, err := GenerateTrafficRedirectionCommandsForPod(pod, meshConfig)
+			assert.Equal(tc.expectErr, err != nil)
+			assert.Equal(tc.expected, actual)
+		})
+	}
+}
+
+func TestConfigurePodInitWithCNI(t *testing.T) {
+	assert := tassert.New(t)
+
+	wh := &mutatingWebhook{
+		enableCNI:              true,
+		cniValidationImage:     "osm-cni",
+		osmContainerPullPolicy: corev1.PullIfNotPresent,
+	}
+
+	pod := &corev1.Pod{}
+	err := wh.configurePodInit(constants.OSLinux, pod, "ns", v1alpha2.MeshConfig{})
+	assert.NoError(err)
+	assert.Len(pod.Spec.InitContainers, 1)
+
+	validation := pod.Spec.InitContainers[0]
+	assert.Equal(constants.CNIValidationContainerName, validation.Name)
+	assert.Equal("osm-cni", validation.Image)
+	assert.Equal([]string{"validate", "--address", "192.0.2.1:80,[2001:db8::1]:80"}, validation.Args)
+	assert.True(*validation.SecurityContext.RunAsNonRoot)
+	assert.NotEqual(constants.EnvoyUID, *validation.SecurityContext.RunAsUser)
+	assert.Nil(validation.SecurityContext.Capabilities.Add)
+
+	// The validation container does not prevent the plugin from redirecting the traffic of the pod
+	pod.Labels = map[string]string{constants.EnvoyUniqueIDLabelName: "uuid"}
+	assert.True(IsTrafficRedirectedByCNI(pod))
+}
+
+func TestGetCNIValidationAddresses(t *testing.T) {
+	testCases := []struct {
+		name     string
+		lists    *interceptionLists
+		expected []string
+	}{
+		{
+			name:     "no interception lists",
+			lists:    &interceptionLists{},
+			expected: []string{"192.0.2.1:80", "[2001:db8::1]:80"},
+		},
+		{
+			name: "excluded ports are skipped",
+			lists: &interceptionLists{
+				outboundPortExclusionList: []int{80, 81},
+			},
+			expected: []string{"192.0.2.1:82", "[2001:db8::1]:82"},
+		},
+		{
+			name: "excluded IP ranges are skipped",
+			lists: &interceptionLists{
+				outboundIPRangeExclusionList: []string{"192.0.2.0/24"},
+			},
+			expected: []string{"[2001:db8::1]:80"},
+		},
+		{
+			name: "addresses of the included IP ranges",
+			lists: &interceptionLists{
+				outboundIPRangeInclusionList: []string{"10.0.0.0/8", "10.1.2.3/32", "fd00::/64"},
+				outboundIPRangeExclusionList: []string{"10.0.0.0/24"},
+			},
+			expected: []string{"10.1.2.3:80", "[fd00::1]:80"},
+		},
+		{
+			name: "all included IP ranges excluded",
+			lists: &interceptionLists{
+				outboundIPRangeInclusionList: []string{"10.0.0.0/24"},
+				outboundIPRangeExclusionList: []string{"10.0.0.0/8"},
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expected, getCNIValidationAddresses(tc.lists))
+		})
+	}
+}
@@ -2,6 +2,7 @@ package injector
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -38,7 +39,7 @@ func getPlatformSpecificSpecComponents(meshConfig v1alpha2.MeshConfig, podOS str
 	return
 }
 
-func getEnvoySidecarContainerSpec(pod *corev1.Pod, namespace string, meshConfig v1alpha2.MeshConfig, originalHealthProbes map[string]models.HealthProbes, podOS string) corev1.Container {
+func getEnvoySidecarContainerSpec(pod *corev1.Pod, namespace string, meshConfig v1alpha2.MeshConfig, sidecarSettings podSidecarSettings, originalHealthProbes map[string]models.HealthProbes, podOS string) corev1.Container {
 	// cluster ID will be used as an identifier to the tracing sink
 	// pod.Namespace is unset in the API request to the webhook so namespace is derived from req.Namespace
 	clusterID := fmt.Sprintf("%s.%s", pod.Spec.ServiceAccountName, namespace)
@@ -48,6 +49,19 @@ func getEnvoySidecarContainerSpec(pod *corev1.Pod, namespace string, meshConfig
 	if logLevel == "" {
 		logLevel = constants.DefaultEnvoyLogLevel
 	}
+	args := []string{
+		"--log-level", logLevel,
+		"--config-path", strings.Join([]string{bootstrap.EnvoyProxyConfigPath, bootstrap.EnvoyBootstrapConfigFile}, "/"),
+		"--service-cluster", clusterID,
+	}
+	if sidecarSettings.concurrency > 0 {
+		// Envoy runs a worker thread per CPU core of the node by default
+		args = append(args, "--concurrency", strconv.Itoa(int(sidecarSettings.concurrency)))
+	}
+	if sidecarSettings.componentLogLevels != "" {
+		args = append(args, "--component-log-level", sidecarSettings.componentLogLevels)
+	}
+
 	return corev1.Container{
 		Name:            constants.EnvoyContainerName,
 		Image:           containerImage,
@@ -60,12 +74,8 @@ func getEnvoySidecarContainerSpec(pod *corev1.Pod, namespace string, meshConfig
 			MountPath: bootstrap.EnvoyProxyConfigPath,
 		}},
 		Command:   []string{"envoy"},
-		Resources: meshConfig.Spec.Sidecar.Resources,
-		Args: []string{
-			"--log-level", logLevel,
-			"--config-path", strings.Join([]string{bootstrap.EnvoyProxyConfigPath, bootstrap.EnvoyBootstrapConfigFile}, "/"),
-			"--service-cluster", clusterID,
-		},
+		Resources: sidecarSettings.resources,
+		Args:      args,
 		Env: []corev1.EnvVar{
 			{
 				Name: "POD_UID",
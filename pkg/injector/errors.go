@@ -3,6 +3,7 @@ package injector
 import "fmt"
 
 var (
-	errNamespaceNotFound   = fmt.Errorf("namespace not found")
-	errNilAdmissionRequest = fmt.Errorf("nil admission request")
+	errNamespaceNotFound    = fmt.Errorf("namespace not found")
+	errNilAdmissionRequest  = fmt.Errorf("nil admission request")
+	errUnsupportedProxyKind = fmt.Errorf("unsupported proxy kind")
 )
@@ -8,6 +8,8 @@ import (
 
 	mapset "github.com/deckarep/golang-set"
 	corev1 "k8s.io/api/core/v1"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
 )
 
 const (
@@ -22,6 +24,9 @@ const (
 
 	// outboundIPRangeInclusionListAnnotation is the annotation used for outbound IP range inclusions
 	outboundIPRangeInclusionListAnnotation = "openservicemesh.io/outbound-ip-range-inclusion-list"
+
+	// trafficRedirectionBackendAnnotation is the annotation used to select the backend redirecting the traffic of a pod
+	trafficRedirectionBackendAnnotation = "openservicemesh.io/traffic-redirection-backend"
 )
 
 // getPortExclusionListForPod gets a list of ports to exclude from sidecar traffic interception for the given
@@ -127,3 +132,70 @@ func mergeIPRangeLists(podSpecific, global []string) []string {
 
 	return ipRanges
 }
+
+// interceptionLists are the lists of IP ranges, ports and network interfaces configuring the interception of the
+// traffic of a pod by its sidecar
+type interceptionLists struct {
+	outboundIPRangeExclusionList  []string
+	outboundIPRangeInclusionList  []string
+	outboundPortExclusionList     []int
+	inboundPortExclusionList      []int
+	networkInterfaceExclusionList []string
+}
+
+// getInterceptionLists returns the interception lists of the given pod, merging the lists of the pod annotations
+// with the global lists of the MeshConfig
+func getInterceptionLists(pod *corev1.Pod, namespace string, meshConfig configv1alpha2.MeshConfig) (*interceptionLists, error) {
+	traffic := meshConfig.Spec.Traffic
+
+	// Build outbound port exclusion list
+	podOutboundPortExclusionList, err := getPortExclusionListForPod(pod, namespace, outboundPortExclusionListAnnotation)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build inbound port exclusion list
+	podInboundPortExclusionList, err := getPortExclusionListForPod(pod, namespace, inboundPortExclusionListAnnotation)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the outbound IP range exclusion list
+	podOutboundIPRangeExclusionList, err := getOutboundIPRangeListForPod(pod, namespace, outboundIPRangeExclusionListAnnotation)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the outbound IP range inclusion list
+	podOutboundIPRangeInclusionList, err := getOutboundIPRangeListForPod(pod, namespace, outboundIPRangeInclusionListAnnotation)
+	if err != nil {
+		return nil, err
+	}
+
+	return &interceptionLists{
+		outboundIPRangeExclusionList:  mergeIPRangeLists(podOutboundIPRangeExclusionList, traffic.OutboundIPRangeExclusionList),
+		outboundIPRangeInclusionList:  mergeIPRangeLists(podOutboundIPRangeInclusionList, traffic.OutboundIPRangeInclusionList),
+		outboundPortExclusionList:     mergePortExclusionLists(podOutboundPortExclusionList, traffic.OutboundPortExclusionList),
+		inboundPortExclusionList:      mergePortExclusionLists(podInboundPortExclusionList, traffic.InboundPortExclusionList),
+		networkInterfaceExclusionList: traffic.NetworkInterfaceExclusionList,
+	}, nil
+}
+
+// getTrafficRedirectionBackend returns the backend redirecting the traffic of the given pod to its sidecar, set by the
+// pod annotation or else by the MeshConfig. iptables is used when neither sets it.
+func getTrafficRedirectionBackend(pod *corev1.Pod, meshConfig configv1alpha2.MeshConfig) (configv1alpha2.TrafficRedirectionBackend, error) {
+	backend := meshConfig.Spec.Sidecar.TrafficRedirectionBackend
+	if backendStr, ok := pod.Annotations[trafficRedirectionBackendAnnotation]; ok {
+		backend = configv1alpha2.TrafficRedirectionBackend(strings.TrimSpace(backendStr))
+	}
+
+	switch backend {
+	case "":
+		return configv1alpha2.TrafficRedirectionBackendIptables, nil
+	case configv1alpha2.TrafficRedirectionBackendIptables, configv1alpha2.TrafficRedirectionBackendNftables:
+		return backend, nil
+	default:
+		return "", fmt.Errorf("Invalid traffic redirection backend '%s', acceptable values are [%s, %s]", backend,
+			configv1alpha2.TrafficRedirectionBackendIptables, configv1alpha2.TrafficRedirectionBackendNftables)
+	}
+}
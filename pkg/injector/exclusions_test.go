@@ -7,6 +7,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
 )
 
 func TestGetPortExclusionListForPod(t *testing.T) {
@@ -222,3 +224,51 @@ func TestMergeIPRangeLists(t *testing.T) {
 		})
 	}
 }
+
+func TestGetTrafficRedirectionBackend(t *testing.T) {
+	testCases := []struct {
+		name            string
+		annotations     map[string]string
+		meshConfig      configv1alpha2.TrafficRedirectionBackend
+		expectedBackend configv1alpha2.TrafficRedirectionBackend
+		expectErr       bool
+	}{
+		{
+			name:            "defaults to iptables",
+			expectedBackend: configv1alpha2.TrafficRedirectionBackendIptables,
+		},
+		{
+			name:            "set by MeshConfig",
+			meshConfig:      configv1alpha2.TrafficRedirectionBackendNftables,
+			expectedBackend: configv1alpha2.TrafficRedirectionBackendNftables,
+		},
+		{
+			name:            "pod annotation overrides MeshConfig",
+			annotations:     map[string]string{trafficRedirectionBackendAnnotation: "iptables"},
+			meshConfig:      configv1alpha2.TrafficRedirectionBackendNftables,
+			expectedBackend: configv1alpha2.TrafficRedirectionBackendIptables,
+		},
+		{
+			name:        "invalid pod annotation",
+			annotations: map[string]string{trafficRedirectionBackendAnnotation: "ebpf"},
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			meshConfig := configv1alpha2.MeshConfig{
+				Spec: configv1alpha2.MeshConfigSpec{
+					Sidecar: configv1alpha2.SidecarSpec{TrafficRedirectionBackend: tc.meshConfig},
+				},
+			}
+
+			backend, err := getTrafficRedirectionBackend(pod, meshConfig)
+			a.Equal(tc.expectErr, err != nil)
+			a.Equal(tc.expectedBackend, backend)
+		})
+	}
+}
@@ -58,10 +58,27 @@ func rewriteProbe(containerName string, probe *corev1.Probe, probeType, path str
 		definedPort = &probe.HTTPGet.Port
 		originalProbe.IsHTTP = len(probe.HTTPGet.Scheme) == 0 || probe.HTTPGet.Scheme == corev1.URISchemeHTTP
 		originalProbe.Path = probe.HTTPGet.Path
-		if originalProbe.IsHTTP {
-			probe.HTTPGet.Path = fmt.Sprintf("%s/%s", path, containerName)
-			newPath = probe.HTTPGet.Path
+		if !originalProbe.IsHTTP {
+			// Transform the HTTPS probe into a HTTP probe, the proxy sends it to the container over TLS
+			probe.HTTPGet.Scheme = corev1.URISchemeHTTP
 		}
+		probe.HTTPGet.Path = fmt.Sprintf("%s/%s", path, containerName)
+		newPath = probe.HTTPGet.Path
+	} else if probe.GRPC != nil {
+		// Transform the GRPC probe into a HttpGet probe served by osm-healthcheck
+		originalProbe.IsGRPC = true
+		probe.HTTPGet = &corev1.HTTPGetAction{
+			Port:        intstr.FromInt(int(probe.GRPC.Port)),
+			Path:        constants.GRPCHealthcheckPath,
+			HTTPHeaders: []corev1.HTTPHeader{},
+		}
+		if probe.GRPC.Service != nil && *probe.GRPC.Service != "" {
+			probe.HTTPGet.HTTPHeaders = append(probe.HTTPGet.HTTPHeaders, corev1.HTTPHeader{Name: "Original-Grpc-Service", Value: *probe.GRPC.Service})
+		}
+		newPath = probe.HTTPGet.Path
+		definedPort = &probe.HTTPGet.Port
+		port = constants.HealthcheckPort
+		probe.GRPC = nil
 	} else if probe.TCPSocket != nil {
 		// Transform the TCPSocket probe into a HttpGet probe
 		originalProbe.IsTCPSocket = true
@@ -86,6 +103,9 @@ func rewriteProbe(containerName string, probe *corev1.Probe, probeType, path str
 	if originalProbe.IsTCPSocket {
 		probe.HTTPGet.HTTPHeaders = append(probe.HTTPGet.HTTPHeaders, corev1.HTTPHeader{Name: "Original-Tcp-Port", Value: fmt.Sprint(originalProbe.Port)})
 	}
+	if originalProbe.IsGRPC {
+		probe.HTTPGet.HTTPHeaders = append(probe.HTTPGet.HTTPHeaders, corev1.HTTPHeader{Name: "Original-Grpc-Port", Value: fmt.Sprint(originalProbe.Port)})
+	}
 	*definedPort = intstr.IntOrString{Type: intstr.Int, IntVal: port}
 	originalProbe.Timeout = time.Duration(probe.TimeoutSeconds) * time.Second
 
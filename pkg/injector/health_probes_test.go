@@ -8,6 +8,7 @@ import (
 	tassert "github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
 
 	"github.com/openservicemesh/osm/pkg/models"
 )
@@ -70,6 +71,22 @@ func TestRewriteProbe(t *testing.T) {
 		}
 	}
 
+	makeGRPCProbe := func(port int32, service *string) *v1.Probe {
+		return &v1.Probe{
+			ProbeHandler: v1.ProbeHandler{
+				GRPC: &v1.GRPCAction{
+					Port:    port,
+					Service: service,
+				},
+			},
+			InitialDelaySeconds: 1,
+			TimeoutSeconds:      probeTimeoutSeconds,
+			PeriodSeconds:       3,
+			SuccessThreshold:    4,
+			FailureThreshold:    5,
+		}
+	}
+
 	makeOriginalTCPPortHeader := func(port int32) v1.HTTPHeader {
 		return v1.HTTPHeader{
 			Name:  "Original-Tcp-Port",
@@ -191,6 +208,7 @@ func TestRewriteProbe(t *testing.T) {
 			newPath       string
 			originalPort  int32
 			newPort       int32
+			grpcService   string
 			expected      *models.HealthProbe
 		}{
 			{
@@ -231,7 +249,7 @@ func TestRewriteProbe(t *testing.T) {
 				name:          "https",
 				containerName: "b",
 				probe:         makeHTTPSProbe("/x/y/z", 3456),
-				newPath:       "/x/y/z",
+				newPath:       "/x",
 				newPort:       3465,
 				expected: &models.HealthProbe{
 					Path:    "/x/y/z",
@@ -253,6 +271,33 @@ func TestRewriteProbe(t *testing.T) {
 					Timeout:     probeTimeoutDuration,
 				},
 			},
+			{
+				name:         "grpc",
+				probe:        makeGRPCProbe(3456, pointer.String("my-service")),
+				originalPort: 3456,
+				grpcService:  "my-service",
+				newPath:      "/osm-grpc-healthcheck",
+				newPort:      15904,
+				expected: &models.HealthProbe{
+					Port:    3456,
+					IsHTTP:  false,
+					IsGRPC:  true,
+					Timeout: probeTimeoutDuration,
+				},
+			},
+			{
+				name:         "grpc without service",
+				probe:        makeGRPCProbe(3456, nil),
+				originalPort: 3456,
+				newPath:      "/osm-grpc-healthcheck",
+				newPort:      15904,
+				expected: &models.HealthProbe{
+					Port:    3456,
+					IsHTTP:  false,
+					IsGRPC:  true,
+					Timeout: probeTimeoutDuration,
+				},
+			},
 		}
 
 		for _, test := range tests {
@@ -269,18 +314,27 @@ func TestRewriteProbe(t *testing.T) {
 				if test.probe != nil {
 					if test.probe.ProbeHandler.HTTPGet != nil {
 						assert.Equal(intstr.FromInt(int(test.newPort)), test.probe.ProbeHandler.HTTPGet.Port)
-						if test.probe.ProbeHandler.HTTPGet.Scheme == v1.URISchemeHTTPS || actual.IsTCPSocket {
+						if actual.IsTCPSocket || actual.IsGRPC {
 							assert.Equal(test.newPath, test.probe.ProbeHandler.HTTPGet.Path)
 						} else {
 							assert.Equal(fmt.Sprintf("%s/%s", test.newPath, test.containerName), test.probe.ProbeHandler.HTTPGet.Path)
 						}
+						// After rewrite there should be no HTTPS probes
+						assert.NotEqual(v1.URISchemeHTTPS, test.probe.ProbeHandler.HTTPGet.Scheme)
 					}
-					// After rewrite there should be no TCPSocket probes
+					// After rewrite there should be no TCPSocket or GRPC probes
 					assert.Nil(test.probe.ProbeHandler.TCPSocket)
+					assert.Nil(test.probe.ProbeHandler.GRPC)
 					if actual != nil && actual.IsTCPSocket {
 						expectedHeader := makeOriginalTCPPortHeader(test.originalPort)
 						assert.Contains(test.probe.ProbeHandler.HTTPGet.HTTPHeaders, expectedHeader)
 					}
+					if actual != nil && actual.IsGRPC {
+						assert.Contains(test.probe.ProbeHandler.HTTPGet.HTTPHeaders, v1.HTTPHeader{Name: "Original-Grpc-Port", Value: fmt.Sprint(test.originalPort)})
+						if test.grpcService != "" {
+							assert.Contains(test.probe.ProbeHandler.HTTPGet.HTTPHeaders, v1.HTTPHeader{Name: "Original-Grpc-Service", Value: test.grpcService})
+						}
+					}
 				}
 			})
 		}
@@ -10,9 +10,10 @@ import (
 
 func getInitContainerSpec(containerName string, meshConfig v1alpha2.MeshConfig, outboundIPRangeExclusionList []string,
 	outboundIPRangeInclusionList []string, outboundPortExclusionList []int,
-	inboundPortExclusionList []int, enablePrivilegedInitContainer bool, pullPolicy corev1.PullPolicy, networkInterfaceExclusionList []string) corev1.Container {
+	inboundPortExclusionList []int, enablePrivilegedInitContainer bool, pullPolicy corev1.PullPolicy, networkInterfaceExclusionList []string,
+	backend v1alpha2.TrafficRedirectionBackend) corev1.Container {
 	proxyMode := meshConfig.Spec.Sidecar.LocalProxyMode
-	iptablesInitCommand := generateIptablesCommands(proxyMode, outboundIPRangeExclusionList, outboundIPRangeInclusionList, outboundPortExclusionList, inboundPortExclusionList, networkInterfaceExclusionList)
+	iptablesInitCommand := generateTrafficRedirectionCommands(backend, proxyMode, outboundIPRangeExclusionList, outboundIPRangeInclusionList, outboundPortExclusionList, inboundPortExclusionList, networkInterfaceExclusionList)
 
 	return corev1.Container{
 		Name:            containerName,
@@ -36,11 +37,11 @@ func getInitContainerSpec(containerName string, meshConfig v1alpha2.MeshConfig,
 		},
 		Env: []corev1.EnvVar{
 			{
-				Name: "POD_IP",
+				Name: "POD_IPS",
 				ValueFrom: &corev1.EnvVarSource{
 					FieldRef: &corev1.ObjectFieldSelector{
 						APIVersion: "v1",
-						FieldPath:  "status.podIP",
+						FieldPath:  "status.podIPs",
 					},
 				},
 			},
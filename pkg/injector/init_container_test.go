@@ -1,6 +1,9 @@
 package injector
 
 import (
+	"os"
+	"path/filepath"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -29,7 +32,10 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 					},
 				},
 			}
-			actual := getInitContainerSpec(containerName, mc, nil, nil, nil, nil, false, corev1.PullAlways, nil)
+			actual := getInitContainerSpec(containerName, mc, nil, nil, nil, nil, false, corev1.PullAlways, nil, v1alpha2.TrafficRedirectionBackendIptables)
+
+			expectedScript, err := os.ReadFile(filepath.Join("test_fixtures", "expected_iptables_localhost.txt"))
+			Expect(err).ToNot(HaveOccurred())
 
 			expected := corev1.Container{
 				Name:            "-container-name-",
@@ -38,32 +44,7 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 				Command:         []string{"/bin/sh"},
 				Args: []string{
 					"-c",
-					`iptables-restore --noflush <<EOF
-# OSM sidecar interception rules
-*nat
-:OSM_PROXY_INBOUND - [0:0]
-:OSM_PROXY_IN_REDIRECT - [0:0]
-:OSM_PROXY_OUTBOUND - [0:0]
-:OSM_PROXY_OUT_REDIRECT - [0:0]
--A OSM_PROXY_IN_REDIRECT -p tcp -j REDIRECT --to-port 15003
--A PREROUTING -p tcp -j OSM_PROXY_INBOUND
--A OSM_PROXY_INBOUND -p tcp --dport 15010 -j RETURN
--A OSM_PROXY_INBOUND -p tcp --dport 15901 -j RETURN
--A OSM_PROXY_INBOUND -p tcp --dport 15902 -j RETURN
--A OSM_PROXY_INBOUND -p tcp --dport 15903 -j RETURN
--A OSM_PROXY_INBOUND -p tcp --dport 15904 -j RETURN
--A OSM_PROXY_INBOUND -p tcp -j OSM_PROXY_IN_REDIRECT
--A OSM_PROXY_OUT_REDIRECT -p tcp -j REDIRECT --to-port 15001
--A OSM_PROXY_OUT_REDIRECT -p tcp --dport 15000 -j ACCEPT
--A OUTPUT -p tcp -j OSM_PROXY_OUTBOUND
--A OSM_PROXY_OUTBOUND -o lo ! -d 127.0.0.1/32 -m owner --uid-owner 1500 -j OSM_PROXY_IN_REDIRECT
--A OSM_PROXY_OUTBOUND -o lo -m owner ! --uid-owner 1500 -j RETURN
--A OSM_PROXY_OUTBOUND -m owner --uid-owner 1500 -j RETURN
--A OSM_PROXY_OUTBOUND -d 127.0.0.1/32 -j RETURN
--A OSM_PROXY_OUTBOUND -j OSM_PROXY_OUT_REDIRECT
-COMMIT
-EOF
-`,
+					string(expectedScript),
 				},
 				WorkingDir: "",
 				Resources:  corev1.ResourceRequirements{},
@@ -79,11 +60,11 @@ EOF
 				},
 				Env: []corev1.EnvVar{
 					{
-						Name: "POD_IP",
+						Name: "POD_IPS",
 						ValueFrom: &corev1.EnvVarSource{
 							FieldRef: &corev1.ObjectFieldSelector{
 								APIVersion: "v1",
-								FieldPath:  "status.podIP",
+								FieldPath:  "status.podIPs",
 							},
 						},
 					},
@@ -104,7 +85,10 @@ EOF
 					},
 				},
 			}
-			actual := getInitContainerSpec(containerName, mc, nil, nil, nil, nil, false, corev1.PullAlways, nil)
+			actual := getInitContainerSpec(containerName, mc, nil, nil, nil, nil, false, corev1.PullAlways, nil, v1alpha2.TrafficRedirectionBackendIptables)
+
+			expectedScript, err := os.ReadFile(filepath.Join("test_fixtures", "expected_iptables_pod_ip.txt"))
+			Expect(err).ToNot(HaveOccurred())
 
 			expected := corev1.Container{
 				Name:            "-container-name-",
@@ -113,33 +97,7 @@ EOF
 				Command:         []string{"/bin/sh"},
 				Args: []string{
 					"-c",
-					`iptables-restore --noflush <<EOF
-# OSM sidecar interception rules
-*nat
-:OSM_PROXY_INBOUND - [0:0]
-:OSM_PROXY_IN_REDIRECT - [0:0]
-:OSM_PROXY_OUTBOUND - [0:0]
-:OSM_PROXY_OUT_REDIRECT - [0:0]
--A OSM_PROXY_IN_REDIRECT -p tcp -j REDIRECT --to-port 15003
--A PREROUTING -p tcp -j OSM_PROXY_INBOUND
--A OSM_PROXY_INBOUND -p tcp --dport 15010 -j RETURN
--A OSM_PROXY_INBOUND -p tcp --dport 15901 -j RETURN
--A OSM_PROXY_INBOUND -p tcp --dport 15902 -j RETURN
--A OSM_PROXY_INBOUND -p tcp --dport 15903 -j RETURN
--A OSM_PROXY_INBOUND -p tcp --dport 15904 -j RETURN
--A OSM_PROXY_INBOUND -p tcp -j OSM_PROXY_IN_REDIRECT
--A OSM_PROXY_OUT_REDIRECT -p tcp -j REDIRECT --to-port 15001
--A OSM_PROXY_OUT_REDIRECT -p tcp --dport 15000 -j ACCEPT
--A OUTPUT -p tcp -j OSM_PROXY_OUTBOUND
--A OSM_PROXY_OUTBOUND -o lo ! -d 127.0.0.1/32 -m owner --uid-owner 1500 -j OSM_PROXY_IN_REDIRECT
--A OSM_PROXY_OUTBOUND -o lo -m owner ! --uid-owner 1500 -j RETURN
--A OSM_PROXY_OUTBOUND -m owner --uid-owner 1500 -j RETURN
--A OSM_PROXY_OUTBOUND -d 127.0.0.1/32 -j RETURN
--I OUTPUT -p tcp -o lo -d 127.0.0.1/32 -m owner --uid-owner 1500 -j DNAT --to-destination $POD_IP
--A OSM_PROXY_OUTBOUND -j OSM_PROXY_OUT_REDIRECT
-COMMIT
-EOF
-`,
+					string(expectedScript),
 				},
 				WorkingDir: "",
 				Resources:  corev1.ResourceRequirements{},
@@ -155,11 +113,11 @@ EOF
 				},
 				Env: []corev1.EnvVar{
 					{
-						Name: "POD_IP",
+						Name: "POD_IPS",
 						ValueFrom: &corev1.EnvVarSource{
 							FieldRef: &corev1.ObjectFieldSelector{
 								APIVersion: "v1",
-								FieldPath:  "status.podIP",
+								FieldPath:  "status.podIPs",
 							},
 						},
 					},
@@ -171,5 +129,21 @@ EOF
 
 			Expect(actual).To(Equal(expected))
 		})
+		It("Sets up nftables rules if the nftables backend is selected", func() {
+			mc := v1alpha2.MeshConfig{
+				Spec: v1alpha2.MeshConfigSpec{
+					Sidecar: v1alpha2.SidecarSpec{
+						InitContainerImage: containerImage,
+						LocalProxyMode:     v1alpha2.LocalProxyModeLocalhost,
+					},
+				},
+			}
+			actual := getInitContainerSpec(containerName, mc, nil, nil, nil, nil, false, corev1.PullAlways, nil, v1alpha2.TrafficRedirectionBackendNftables)
+
+			Expect(actual.Args).To(Equal([]string{
+				"-c",
+				generateNftablesCommands(v1alpha2.LocalProxyModeLocalhost, nil, nil, nil, nil, nil),
+			}))
+		})
 	})
 })
@@ -2,6 +2,7 @@ package injector
 
 import (
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
 
@@ -10,31 +11,55 @@ import (
 	"github.com/openservicemesh/osm/pkg/constants"
 )
 
-// iptablesOutboundStaticRules is the list of iptables rules related to outbound traffic interception and redirection
-var iptablesOutboundStaticRules = []string{
-	// Redirects outbound TCP traffic hitting OSM_PROXY_OUT_REDIRECT chain to Envoy's outbound listener port
-	fmt.Sprintf("-A OSM_PROXY_OUT_REDIRECT -p tcp -j REDIRECT --to-port %d", constants.EnvoyOutboundListenerPort),
+// ipFamily describes how the traffic of an IP address family is intercepted with iptables or nftables
+type ipFamily struct {
+	// restoreCmd is the command restoring the iptables rules of the family
+	restoreCmd string
 
-	// Traffic to the Proxy Admin port flows to the Proxy -- not redirected
-	fmt.Sprintf("-A OSM_PROXY_OUT_REDIRECT -p tcp --dport %d -j ACCEPT", constants.EnvoyAdminPort),
+	// nftFamily is the nftables address family of the table holding the nftables rules of the family, which is also
+	// the nftables keyword matching the headers of the family
+	nftFamily string
 
-	// For outbound TCP traffic jump from OUTPUT chain to OSM_PROXY_OUTBOUND chain
-	"-A OUTPUT -p tcp -j OSM_PROXY_OUTBOUND",
+	// localhostCIDR is the CIDR of the localhost address of the family
+	localhostCIDR string
 
-	// Outbound traffic from Envoy to the local app over the loopback interface should jump to the inbound proxy redirect chain.
-	// So when an app directs traffic to itself via the k8s service, traffic flows as follows:
-	// app -> local envoy's outbound listener -> iptables -> local envoy's inbound listener -> app
-	fmt.Sprintf("-A OSM_PROXY_OUTBOUND -o lo ! -d 127.0.0.1/32 -m owner --uid-owner %d -j OSM_PROXY_IN_REDIRECT", constants.EnvoyUID),
+	// podIPVar is the shell variable holding the pod IP address of the family
+	podIPVar string
+}
+
+var (
+	ipv4Family = ipFamily{restoreCmd: "iptables-restore", nftFamily: "ip", localhostCIDR: "127.0.0.1/32", podIPVar: "POD_IPV4"}
+	ipv6Family = ipFamily{restoreCmd: "ip6tables-restore", nftFamily: "ip6", localhostCIDR: "::1/128", podIPVar: "POD_IPV6"}
+)
+
+// iptablesOutboundStaticRules returns the list of iptables rules related to outbound traffic interception and
+// redirection for the given IP address family
+func iptablesOutboundStaticRules(family ipFamily) []string {
+	return []string{
+		// Redirects outbound TCP traffic hitting OSM_PROXY_OUT_REDIRECT chain to Envoy's outbound listener port
+		fmt.Sprintf("-A OSM_PROXY_OUT_REDIRECT -p tcp -j REDIRECT --to-port %d", constants.EnvoyOutboundListenerPort),
+
+		// Traffic to the Proxy Admin port flows to the Proxy -- not redirected
+		fmt.Sprintf("-A OSM_PROXY_OUT_REDIRECT -p tcp --dport %d -j ACCEPT", constants.EnvoyAdminPort),
+
+		// For outbound TCP traffic jump from OUTPUT chain to OSM_PROXY_OUTBOUND chain
+		"-A OUTPUT -p tcp -j OSM_PROXY_OUTBOUND",
+
+		// Outbound traffic from Envoy to the local app over the loopback interface should jump to the inbound proxy redirect chain.
+		// So when an app directs traffic to itself via the k8s service, traffic flows as follows:
+		// app -> local envoy's outbound listener -> iptables -> local envoy's inbound listener -> app
+		fmt.Sprintf("-A OSM_PROXY_OUTBOUND -o lo ! -d %s -m owner --uid-owner %d -j OSM_PROXY_IN_REDIRECT", family.localhostCIDR, constants.EnvoyUID),
 
-	// Outbound traffic from the app to itself over the loopback interface is not be redirected via the proxy.
-	// E.g. when app sends traffic to itself via the pod IP.
-	fmt.Sprintf("-A OSM_PROXY_OUTBOUND -o lo -m owner ! --uid-owner %d -j RETURN", constants.EnvoyUID),
+		// Outbound traffic from the app to itself over the loopback interface is not be redirected via the proxy.
+		// E.g. when app sends traffic to itself via the pod IP.
+		fmt.Sprintf("-A OSM_PROXY_OUTBOUND -o lo -m owner ! --uid-owner %d -j RETURN", constants.EnvoyUID),
 
-	// Don't redirect Envoy traffic back to itself, return it to the next chain for processing
-	fmt.Sprintf("-A OSM_PROXY_OUTBOUND -m owner --uid-owner %d -j RETURN", constants.EnvoyUID),
+		// Don't redirect Envoy traffic back to itself, return it to the next chain for processing
+		fmt.Sprintf("-A OSM_PROXY_OUTBOUND -m owner --uid-owner %d -j RETURN", constants.EnvoyUID),
 
-	// Skip localhost traffic, doesn't need to be routed via the proxy
-	"-A OSM_PROXY_OUTBOUND -d 127.0.0.1/32 -j RETURN",
+		// Skip localhost traffic, doesn't need to be routed via the proxy
+		fmt.Sprintf("-A OSM_PROXY_OUTBOUND -d %s -j RETURN", family.localhostCIDR),
+	}
 }
 
 // iptablesInboundStaticRules is the list of iptables rules related to inbound traffic interception and redirection
@@ -56,13 +81,54 @@ var iptablesInboundStaticRules = []string{
 	fmt.Sprintf("-A OSM_PROXY_INBOUND -p tcp --dport %d -j RETURN", constants.StartupProbePort),
 	// Skip inbound health probes (originally TCPSocket health probes); requests handled by osm-healthcheck
 	fmt.Sprintf("-A OSM_PROXY_INBOUND -p tcp --dport %d -j RETURN", constants.HealthcheckPort),
+	// Skip the proxy readiness probe of native sidecar containers; requests handled by the proxy's ready listener
+	fmt.Sprintf("-A OSM_PROXY_INBOUND -p tcp --dport %d -j RETURN", constants.EnvoyReadyPort),
 
 	// Redirect remaining inbound traffic to Envoy
 	"-A OSM_PROXY_INBOUND -p tcp -j OSM_PROXY_IN_REDIRECT",
 }
 
-// generateIptablesCommands generates a list of iptables commands to set up sidecar interception and redirection
+// podIPFamiliesCommand sets the POD_IPV4 and POD_IPV6 shell variables to the pod IP address of each family, from
+// the comma separated list of pod IP addresses in the POD_IPS environment variable
+const podIPFamiliesCommand = `for ip in $(echo "$POD_IPS" | tr ',' ' '); do
+case "$ip" in *:*) POD_IPV6="$ip" ;; *) POD_IPV4="$ip" ;; esac
+done
+`
+
+// generateIptablesCommands generates a list of iptables commands to set up sidecar interception and redirection.
+// The traffic of an IP address family is only intercepted when the pod has an IP address of this family, as the
+// proxy only listens on the IPv6 wildcard address when the pod has an IPv6 address.
 func generateIptablesCommands(proxyMode configv1alpha2.LocalProxyMode, outboundIPRangeExclusionList []string, outboundIPRangeInclusionList []string, outboundPortExclusionList []int, inboundPortExclusionList []int, networkInterfaceExclusionList []string) string {
+	return generatePodIPFamiliesCommands(func(family ipFamily) string {
+		return generateIptablesRestoreCommand(family, proxyMode, outboundIPRangeExclusionList, outboundIPRangeInclusionList, outboundPortExclusionList, inboundPortExclusionList, networkInterfaceExclusionList)
+	})
+}
+
+// generatePodIPFamiliesCommands generates the commands returned by familyCommand for each IP address family the pod
+// has an IP address of
+func generatePodIPFamiliesCommands(familyCommand func(ipFamily) string) string {
+	var cmd strings.Builder
+	fmt.Fprint(&cmd, podIPFamiliesCommand)
+	for _, family := range []ipFamily{ipv4Family, ipv6Family} {
+		fmt.Fprintf(&cmd, "if [ -n \"$%s\" ]; then\n", family.podIPVar)
+		fmt.Fprint(&cmd, familyCommand(family))
+		fmt.Fprintln(&cmd, "fi")
+	}
+	return cmd.String()
+}
+
+// generateTrafficRedirectionCommands generates the commands to set up sidecar interception and redirection with the
+// given backend
+func generateTrafficRedirectionCommands(backend configv1alpha2.TrafficRedirectionBackend, proxyMode configv1alpha2.LocalProxyMode, outboundIPRangeExclusionList []string, outboundIPRangeInclusionList []string, outboundPortExclusionList []int, inboundPortExclusionList []int, networkInterfaceExclusionList []string) string {
+	if backend == configv1alpha2.TrafficRedirectionBackendNftables {
+		return generateNftablesCommands(proxyMode, outboundIPRangeExclusionList, outboundIPRangeInclusionList, outboundPortExclusionList, inboundPortExclusionList, networkInterfaceExclusionList)
+	}
+	return generateIptablesCommands(proxyMode, outboundIPRangeExclusionList, outboundIPRangeInclusionList, outboundPortExclusionList, inboundPortExclusionList, networkInterfaceExclusionList)
+}
+
+// generateIptablesRestoreCommand generates the command restoring the iptables rules to set up sidecar interception
+// and redirection for the given IP address family. IP ranges of the other family are ignored.
+func generateIptablesRestoreCommand(family ipFamily, proxyMode configv1alpha2.LocalProxyMode, outboundIPRangeExclusionList []string, outboundIPRangeInclusionList []string, outboundPortExclusionList []int, inboundPortExclusionList []int, networkInterfaceExclusionList []string) string {
 	var rules strings.Builder
 
 	fmt.Fprintln(&rules, `# OSM sidecar interception rules
@@ -95,13 +161,14 @@ func generateIptablesCommands(proxyMode configv1alpha2.LocalProxyMode, outboundI
 	}
 
 	// 3. Create outbound rules
-	cmds = append(cmds, iptablesOutboundStaticRules...)
+	cmds = append(cmds, iptablesOutboundStaticRules(family)...)
 
 	if proxyMode == configv1alpha2.LocalProxyModePodIP {
 		// For envoy -> local service container proxying, send traffic to pod IP instead of localhost
 		// *Note: it is important to use the insert option '-I' instead of the append option '-A' to ensure the
-		// DNAT to the pod ip for envoy -> localhost traffic happens before the rule that redirects traffic to the proxy
-		cmds = append(cmds, fmt.Sprintf("-I OUTPUT -p tcp -o lo -d 127.0.0.1/32 -m owner --uid-owner %d -j DNAT --to-destination $POD_IP", constants.EnvoyUID))
+		// DNAT to the pod ip for envoy -> localhost traffic happens before the rule that redirects traffic to the proxy.
+		// Traffic to the Proxy Admin port, listening on localhost, is not sent to the pod IP.
+		cmds = append(cmds, fmt.Sprintf("-I OUTPUT -p tcp -o lo -d %s ! --dport %d -m owner --uid-owner %d -j DNAT --to-destination $%s", family.localhostCIDR, constants.EnvoyAdminPort, constants.EnvoyUID, family.podIPVar))
 	}
 
 	// Ignore outbound traffic in specified interfaces
@@ -115,7 +182,7 @@ func generateIptablesCommands(proxyMode configv1alpha2.LocalProxyMode, outboundI
 	//
 
 	// 4. Create dynamic outbound IP range exclusion rules
-	for _, cidr := range outboundIPRangeExclusionList {
+	for _, cidr := range filterIPRangesByFamily(outboundIPRangeExclusionList, family) {
 		// *Note: it is important to use the insert option '-I' instead of the append option '-A' to ensure the exclusion
 		// rules take precedence over the static redirection rules. Iptables rules are evaluated in order.
 		rule := fmt.Sprintf("-A OSM_PROXY_OUTBOUND -d %s -j RETURN", cidr)
@@ -135,8 +202,9 @@ func generateIptablesCommands(proxyMode configv1alpha2.LocalProxyMode, outboundI
 
 	// 6. Create dynamic outbound IP range inclusion rules
 	if len(outboundIPRangeInclusionList) > 0 {
-		// Redirect specified IP ranges to the proxy
-		for _, cidr := range outboundIPRangeInclusionList {
+		// Redirect specified IP ranges to the proxy. When only IP ranges of the other family are specified, no traffic
+		// of this family is redirected.
+		for _, cidr := range filterIPRangesByFamily(outboundIPRangeInclusionList, family) {
 			rule := fmt.Sprintf("-A OSM_PROXY_OUTBOUND -d %s -j OSM_PROXY_OUT_REDIRECT", cidr)
 			cmds = append(cmds, rule)
 		}
@@ -153,10 +221,39 @@ func generateIptablesCommands(proxyMode configv1alpha2.LocalProxyMode, outboundI
 
 	fmt.Fprint(&rules, "COMMIT")
 
-	cmd := fmt.Sprintf(`iptables-restore --noflush <<EOF
+	cmd := fmt.Sprintf(`%s --noflush <<EOF
 %s
 EOF
-`, rules.String())
+`, family.restoreCmd, rules.String())
+
+	return cmd
+}
 
+// filterIPRangesByFamily returns the IP ranges of the given IP address family
+func filterIPRangesByFamily(ipRanges []string, family ipFamily) []string {
+	var filtered []string
+	for _, cidr := range ipRanges {
+		ip, _, err := net.ParseCIDR(cidr)
+		if err != nil {
+			// IP ranges are validated beforehand, keep the rule for iptables to report the error
+			filtered = append(filtered, cidr)
+			continue
+		}
+		if (ip.To4() == nil) == (family == ipv6Family) {
+			filtered = append(filtered, cidr)
+		}
+	}
+	return filtered
+}
+
+// GenerateWorkloadIptablesCommands generates the iptables commands to set up the interception and redirection of the
+// traffic of a workload outside of Kubernetes to its proxy. The proxy runs on the host of the workload as the
+// constants.EnvoyUID user, and forwards inbound traffic to the workload over localhost. IPv6 traffic is only
+// intercepted when the workload has an IPv6 address, as the proxy only listens on the IPv6 wildcard address then.
+func GenerateWorkloadIptablesCommands(ipv6 bool, outboundIPRangeExclusionList []string, outboundPortExclusionList []int, inboundPortExclusionList []int) string {
+	cmd := generateIptablesRestoreCommand(ipv4Family, configv1alpha2.LocalProxyModeLocalhost, outboundIPRangeExclusionList, nil, outboundPortExclusionList, inboundPortExclusionList, nil)
+	if ipv6 {
+		cmd += generateIptablesRestoreCommand(ipv6Family, configv1alpha2.LocalProxyModeLocalhost, outboundIPRangeExclusionList, nil, outboundPortExclusionList, inboundPortExclusionList, nil)
+	}
 	return cmd
 }
@@ -1,6 +1,10 @@
 package injector
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -8,9 +12,10 @@ import (
 	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
 )
 
-func TestGenerateIptablesCommands(t *testing.T) {
+func TestGenerateIptablesRestoreCommand(t *testing.T) {
 	testCases := []struct {
 		name                       string
+		family                     ipFamily
 		proxyMode                  configv1alpha2.LocalProxyMode
 		outboundIPRangeExclusions  []string
 		outboundIPRangeInclusions  []string
@@ -20,7 +25,8 @@ func TestGenerateIptablesCommands(t *testing.T) {
 		expected                   string
 	}{
 		{
-			name: "no exclusions or inclusions",
+			name:   "no exclusions or inclusions",
+			family: ipv4Family,
 			expected: `iptables-restore --noflush <<EOF
 # OSM sidecar interception rules
 *nat
@@ -35,6 +41,7 @@ func TestGenerateIptablesCommands(t *testing.T) {
 -A OSM_PROXY_INBOUND -p tcp --dport 15902 -j RETURN
 -A OSM_PROXY_INBOUND -p tcp --dport 15903 -j RETURN
 -A OSM_PROXY_INBOUND -p tcp --dport 15904 -j RETURN
+-A OSM_PROXY_INBOUND -p tcp --dport 15905 -j RETURN
 -A OSM_PROXY_INBOUND -p tcp -j OSM_PROXY_IN_REDIRECT
 -A OSM_PROXY_OUT_REDIRECT -p tcp -j REDIRECT --to-port 15001
 -A OSM_PROXY_OUT_REDIRECT -p tcp --dport 15000 -j ACCEPT
@@ -50,6 +57,7 @@ EOF
 		},
 		{
 			name:                       "with exclusions and inclusions",
+			family:                     ipv4Family,
 			outboundIPRangeExclusions:  []string{"1.1.1.1/32", "2.2.2.2/32"},
 			outboundIPRangeInclusions:  []string{"3.3.3.3/32", "4.4.4.4/32"},
 			outboundPortExclusions:     []int{10, 20},
@@ -69,6 +77,7 @@ EOF
 -A OSM_PROXY_INBOUND -p tcp --dport 15902 -j RETURN
 -A OSM_PROXY_INBOUND -p tcp --dport 15903 -j RETURN
 -A OSM_PROXY_INBOUND -p tcp --dport 15904 -j RETURN
+-A OSM_PROXY_INBOUND -p tcp --dport 15905 -j RETURN
 -A OSM_PROXY_INBOUND -p tcp -j OSM_PROXY_IN_REDIRECT
 -I OSM_PROXY_INBOUND -i eth0 -j RETURN
 -I OSM_PROXY_INBOUND -i eth1 -j RETURN
@@ -94,6 +103,7 @@ EOF
 		},
 		{
 			name:      "proxy mode pod ip",
+			family:    ipv4Family,
 			proxyMode: configv1alpha2.LocalProxyModePodIP,
 			expected: `iptables-restore --noflush <<EOF
 # OSM sidecar interception rules
@@ -109,6 +119,7 @@ EOF
 -A OSM_PROXY_INBOUND -p tcp --dport 15902 -j RETURN
 -A OSM_PROXY_INBOUND -p tcp --dport 15903 -j RETURN
 -A OSM_PROXY_INBOUND -p tcp --dport 15904 -j RETURN
+-A OSM_PROXY_INBOUND -p tcp --dport 15905 -j RETURN
 -A OSM_PROXY_INBOUND -p tcp -j OSM_PROXY_IN_REDIRECT
 -A OSM_PROXY_OUT_REDIRECT -p tcp -j REDIRECT --to-port 15001
 -A OSM_PROXY_OUT_REDIRECT -p tcp --dport 15000 -j ACCEPT
@@ -117,10 +128,46 @@ EOF
 -A OSM_PROXY_OUTBOUND -o lo -m owner ! --uid-owner 1500 -j RETURN
 -A OSM_PROXY_OUTBOUND -m owner --uid-owner 1500 -j RETURN
 -A OSM_PROXY_OUTBOUND -d 127.0.0.1/32 -j RETURN
--I OUTPUT -p tcp -o lo -d 127.0.0.1/32 -m owner --uid-owner 1500 -j DNAT --to-destination $POD_IP
+-I OUTPUT -p tcp -o lo -d 127.0.0.1/32 ! --dport 15000 -m owner --uid-owner 1500 -j DNAT --to-destination $POD_IPV4
 -A OSM_PROXY_OUTBOUND -j OSM_PROXY_OUT_REDIRECT
 COMMIT
 EOF
+`,
+		},
+		{
+			name:                      "ipv6 with exclusions and inclusions of both families",
+			family:                    ipv6Family,
+			proxyMode:                 configv1alpha2.LocalProxyModePodIP,
+			outboundIPRangeExclusions: []string{"1.1.1.1/32", "fd00::1/128"},
+			outboundIPRangeInclusions: []string{"3.3.3.3/32"},
+			expected: `ip6tables-restore --noflush <<EOF
+# OSM sidecar interception rules
+*nat
+:OSM_PROXY_INBOUND - [0:0]
+:OSM_PROXY_IN_REDIRECT - [0:0]
+:OSM_PROXY_OUTBOUND - [0:0]
+:OSM_PROXY_OUT_REDIRECT - [0:0]
+-A OSM_PROXY_IN_REDIRECT -p tcp -j REDIRECT --to-port 15003
+-A PREROUTING -p tcp -j OSM_PROXY_INBOUND
+-A OSM_PROXY_INBOUND -p tcp --dport 15010 -j RETURN
+-A OSM_PROXY_INBOUND -p tcp --dport 15901 -j RETURN
+-A OSM_PROXY_INBOUND -p tcp --dport 15902 -j RETURN
+-A OSM_PROXY_INBOUND -p tcp --dport 15903 -j RETURN
+-A OSM_PROXY_INBOUND -p tcp --dport 15904 -j RETURN
+-A OSM_PROXY_INBOUND -p tcp --dport 15905 -j RETURN
+-A OSM_PROXY_INBOUND -p tcp -j OSM_PROXY_IN_REDIRECT
+-A OSM_PROXY_OUT_REDIRECT -p tcp -j REDIRECT --to-port 15001
+-A OSM_PROXY_OUT_REDIRECT -p tcp --dport 15000 -j ACCEPT
+-A OUTPUT -p tcp -j OSM_PROXY_OUTBOUND
+-A OSM_PROXY_OUTBOUND -o lo ! -d ::1/128 -m owner --uid-owner 1500 -j OSM_PROXY_IN_REDIRECT
+-A OSM_PROXY_OUTBOUND -o lo -m owner ! --uid-owner 1500 -j RETURN
+-A OSM_PROXY_OUTBOUND -m owner --uid-owner 1500 -j RETURN
+-A OSM_PROXY_OUTBOUND -d ::1/128 -j RETURN
+-I OUTPUT -p tcp -o lo -d ::1/128 ! --dport 15000 -m owner --uid-owner 1500 -j DNAT --to-destination $POD_IPV6
+-A OSM_PROXY_OUTBOUND -d fd00::1/128 -j RETURN
+-A OSM_PROXY_OUTBOUND -j RETURN
+COMMIT
+EOF
 `,
 		},
 	}
@@ -129,8 +176,114 @@ EOF
 		t.Run(tc.name, func(t *testing.T) {
 			a := assert.New(t)
 
-			actual := generateIptablesCommands(tc.proxyMode, tc.outboundIPRangeExclusions, tc.outboundIPRangeInclusions, tc.outboundPortExclusions, tc.inboundPortExclusions, tc.networkInterfaceExclusions)
+			actual := generateIptablesRestoreCommand(tc.family, tc.proxyMode, tc.outboundIPRangeExclusions, tc.outboundIPRangeInclusions, tc.outboundPortExclusions, tc.inboundPortExclusions, tc.networkInterfaceExclusions)
 			a.Equal(tc.expected, actual)
 		})
 	}
 }
+
+func TestGenerateIptablesCommands(t *testing.T) {
+	testCases := []struct {
+		proxyMode configv1alpha2.LocalProxyMode
+		golden    string
+	}{
+		{
+			proxyMode: configv1alpha2.LocalProxyModeLocalhost,
+			golden:    "expected_iptables_localhost.txt",
+		},
+		{
+			proxyMode: configv1alpha2.LocalProxyModePodIP,
+			golden:    "expected_iptables_pod_ip.txt",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.proxyMode), func(t *testing.T) {
+			a := assert.New(t)
+
+			expected, err := os.ReadFile(filepath.Join("test_fixtures", tc.golden))
+			a.NoError(err)
+
+			actual := generateIptablesCommands(tc.proxyMode, nil, nil, nil, nil, nil)
+			a.Equal(string(expected), actual)
+		})
+	}
+}
+
+// TestIptablesCommandsPodIPFamilies runs the init container script with the restore commands stubbed, to verify that
+// the rules of an IP address family are only restored when the pod has an IP address of this family
+func TestIptablesCommandsPodIPFamilies(t *testing.T) {
+	script, err := os.ReadFile(filepath.Join("test_fixtures", "expected_iptables_pod_ip.txt"))
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name                 string
+		podIPs               string
+		expectedRestoreCmds  []string
+		expectedDestinations []string
+	}{
+		{
+			name:                 "ipv4 only",
+			podIPs:               "10.0.0.1",
+			expectedRestoreCmds:  []string{"iptables-restore"},
+			expectedDestinations: []string{"--to-destination 10.0.0.1"},
+		},
+		{
+			name:                 "dual-stack",
+			podIPs:               "10.0.0.1,fd00::1",
+			expectedRestoreCmds:  []string{"iptables-restore", "ip6tables-restore"},
+			expectedDestinations: []string{"--to-destination 10.0.0.1", "--to-destination fd00::1"},
+		},
+		{
+			name:                 "ipv6 only",
+			podIPs:               "fd00::1",
+			expectedRestoreCmds:  []string{"ip6tables-restore"},
+			expectedDestinations: []string{"--to-destination fd00::1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+
+			// Each stubbed restore command appends its name and the rules it is given to the output file
+			binDir := t.TempDir()
+			output := filepath.Join(t.TempDir(), "output")
+			for _, restoreCmd := range []string{"iptables-restore", "ip6tables-restore"} {
+				stub := "#!/bin/sh\necho " + restoreCmd + " >> \"$OUTPUT\"\ncat >> \"$OUTPUT\"\n"
+				a.NoError(os.WriteFile(filepath.Join(binDir, restoreCmd), []byte(stub), 0700)) //#nosec G306
+			}
+
+			cmd := exec.Command("/bin/sh", "-c", string(script)) //#nosec G204
+			cmd.Env = []string{"PATH=" + binDir + ":/usr/bin:/bin", "OUTPUT=" + output, "POD_IPS=" + tc.podIPs}
+			out, err := cmd.CombinedOutput()
+			a.NoError(err, string(out))
+
+			restored, err := os.ReadFile(output)
+			a.NoError(err)
+			var restoreCmds, destinations []string
+			for _, line := range strings.Split(string(restored), "\n") {
+				if strings.HasSuffix(line, "tables-restore") {
+					restoreCmds = append(restoreCmds, line)
+				}
+				if i := strings.Index(line, "--to-destination"); i >= 0 {
+					destinations = append(destinations, line[i:])
+				}
+			}
+			a.Equal(tc.expectedRestoreCmds, restoreCmds)
+			a.Equal(tc.expectedDestinations, destinations)
+		})
+	}
+}
+
+func TestGenerateWorkloadIptablesCommands(t *testing.T) {
+	a := assert.New(t)
+
+	ipv4Only := GenerateWorkloadIptablesCommands(false, nil, nil, []int{22})
+	a.Contains(ipv4Only, "iptables-restore --noflush")
+	a.NotContains(ipv4Only, "ip6tables-restore")
+
+	dualStack := GenerateWorkloadIptablesCommands(true, nil, nil, []int{22})
+	a.True(strings.HasPrefix(dualStack, ipv4Only))
+	a.Contains(dualStack, "ip6tables-restore --noflush")
+}
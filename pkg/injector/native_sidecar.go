@@ -0,0 +1,132 @@
+package injector
+
+import (
+	"encoding/json"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+const (
+	// nativeSidecarRestartPolicy is the restart policy of init containers running as native sidecar containers
+	nativeSidecarRestartPolicy = "Always"
+
+	// envoyReadyPortName is the name of the container port serving the readiness of the proxy
+	envoyReadyPortName = "proxy-ready"
+
+	// envoyStartupProbeFailureThreshold is the number of failed startup probes of the proxy, probed every second,
+	// after which the proxy is restarted
+	envoyStartupProbeFailureThreshold = 120
+)
+
+// nativeSidecarMinVersion is the Kubernetes version enabling native sidecar containers by default. Native sidecar
+// containers are alpha in Kubernetes 1.28, and require the SidecarContainers feature gate to be enabled.
+var nativeSidecarMinVersion = utilversion.MustParseGeneric("1.29")
+
+// isNativeSidecarSupported returns whether the Kubernetes API server enables native sidecar containers by default
+func isNativeSidecarSupported(kubeClient kubernetes.Interface) bool {
+	serverVersion, err := kubeClient.Discovery().ServerVersion()
+	if err != nil {
+		log.Error().Err(err).Msg("Error fetching the Kubernetes API server version, sidecars are injected as regular containers unless configured otherwise")
+		return false
+	}
+
+	version, err := utilversion.ParseGeneric(serverVersion.GitVersion)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error parsing the Kubernetes API server version %s, sidecars are injected as regular containers unless configured otherwise", serverVersion.GitVersion)
+		return false
+	}
+
+	return version.AtLeast(nativeSidecarMinVersion)
+}
+
+// useNativeSidecar returns whether the sidecar of a pod running on the given OS is injected as a native sidecar
+// container, as configured by the MeshConfig or else detected from the Kubernetes API server version.
+// Windows pods always get regular sidecar containers.
+func (wh *mutatingWebhook) useNativeSidecar(meshConfig v1alpha2.MeshConfig, podOS string) bool {
+	if strings.EqualFold(podOS, constants.OSWindows) {
+		return false
+	}
+
+	switch meshConfig.Spec.Sidecar.ContainerMode {
+	case v1alpha2.SidecarContainerModeNative:
+		return true
+	case v1alpha2.SidecarContainerModeRegular:
+		return false
+	default:
+		return wh.nativeSidecarsSupported
+	}
+}
+
+// getEnvoyStartupProbe returns the startup probe of the proxy injected as a native sidecar container, which holds the
+// start of the application containers until the proxy is ready, i.e. until it received its initial configuration
+func getEnvoyStartupProbe() *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path:   constants.EnvoyReadyPath,
+				Port:   intstr.FromInt(int(constants.EnvoyReadyPort)),
+				Scheme: corev1.URISchemeHTTP,
+			},
+		},
+		PeriodSeconds:    1,
+		TimeoutSeconds:   1,
+		FailureThreshold: envoyStartupProbeFailureThreshold,
+	}
+}
+
+// setInitContainerRestartPolicies returns the given pod JSON with the restart policy of its init containers set.
+// The restartPolicy field of containers is not known to the vendored Kubernetes API and is dropped when the pod is
+// decoded, so the restart policies of the init containers of the original pod JSON are set again, and the restart
+// policy of the given native sidecar containers is set to Always.
+func setInitContainerRestartPolicies(original, current []byte, nativeSidecars []string) ([]byte, error) {
+	restartPolicies := make(map[string]string)
+	if len(original) > 0 {
+		var originalPod struct {
+			Spec struct {
+				InitContainers []struct {
+					Name          string `json:"name"`
+					RestartPolicy string `json:"restartPolicy"`
+				} `json:"initContainers"`
+			} `json:"spec"`
+		}
+		if err := json.Unmarshal(original, &originalPod); err != nil {
+			return nil, err
+		}
+		for _, container := range originalPod.Spec.InitContainers {
+			if container.RestartPolicy != "" {
+				restartPolicies[container.Name] = container.RestartPolicy
+			}
+		}
+	}
+	for _, name := range nativeSidecars {
+		restartPolicies[name] = nativeSidecarRestartPolicy
+	}
+	if len(restartPolicies) == 0 {
+		return current, nil
+	}
+
+	var pod map[string]interface{}
+	if err := json.Unmarshal(current, &pod); err != nil {
+		return nil, err
+	}
+	spec, _ := pod["spec"].(map[string]interface{})
+	initContainers, _ := spec["initContainers"].([]interface{})
+	for _, c := range initContainers {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := container["name"].(string)
+		if policy, ok := restartPolicies[name]; ok {
+			container["restartPolicy"] = policy
+		}
+	}
+	return json.Marshal(pod)
+}
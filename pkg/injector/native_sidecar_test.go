@@ -0,0 +1,165 @@
+package injector
+
+import (
+	"errors"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func TestIsNativeSidecarSupported(t *testing.T) {
+	testCases := []struct {
+		name       string
+		gitVersion string
+		err        error
+		expected   bool
+	}{
+		{
+			name:       "Kubernetes 1.28",
+			gitVersion: "v1.28.3",
+			expected:   false,
+		},
+		{
+			name:       "Kubernetes 1.29",
+			gitVersion: "v1.29.0",
+			expected:   true,
+		},
+		{
+			name:       "Kubernetes 1.30 distribution",
+			gitVersion: "v1.30.2-eks-1552ad0",
+			expected:   true,
+		},
+		{
+			name:       "invalid version",
+			gitVersion: "invalid",
+			expected:   false,
+		},
+		{
+			name:     "error fetching version",
+			err:      errors.New("connection refused"),
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			client := fake.NewSimpleClientset()
+			discovery := client.Discovery().(*fakediscovery.FakeDiscovery)
+			discovery.FakedServerVersion = &version.Info{GitVersion: tc.gitVersion}
+			if tc.err != nil {
+				client.PrependReactor("get", "version", func(k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tc.err
+				})
+			}
+
+			assert.Equal(tc.expected, isNativeSidecarSupported(client))
+		})
+	}
+}
+
+func TestUseNativeSidecar(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		containerMode           v1alpha2.SidecarContainerMode
+		nativeSidecarsSupported bool
+		podOS                   string
+		expected                bool
+	}{
+		{
+			name:                    "auto with native sidecars supported",
+			nativeSidecarsSupported: true,
+			podOS:                   constants.OSLinux,
+			expected:                true,
+		},
+		{
+			name:          "auto with native sidecars not supported",
+			containerMode: v1alpha2.SidecarContainerModeAuto,
+			podOS:         constants.OSLinux,
+			expected:      false,
+		},
+		{
+			name:          "native",
+			containerMode: v1alpha2.SidecarContainerModeNative,
+			podOS:         constants.OSLinux,
+			expected:      true,
+		},
+		{
+			name:                    "regular",
+			containerMode:           v1alpha2.SidecarContainerModeRegular,
+			nativeSidecarsSupported: true,
+			podOS:                   constants.OSLinux,
+			expected:                false,
+		},
+		{
+			name:          "windows",
+			containerMode: v1alpha2.SidecarContainerModeNative,
+			podOS:         constants.OSWindows,
+			expected:      false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			wh := &mutatingWebhook{nativeSidecarsSupported: tc.nativeSidecarsSupported}
+			meshConfig := v1alpha2.MeshConfig{
+				Spec: v1alpha2.MeshConfigSpec{
+					Sidecar: v1alpha2.SidecarSpec{ContainerMode: tc.containerMode},
+				},
+			}
+
+			assert.Equal(tc.expected, wh.useNativeSidecar(meshConfig, tc.podOS))
+		})
+	}
+}
+
+func TestSetInitContainerRestartPolicies(t *testing.T) {
+	testCases := []struct {
+		name           string
+		original       string
+		current        string
+		nativeSidecars []string
+		expected       string
+	}{
+		{
+			name:     "no restart policy",
+			original: `{"spec":{"initContainers":[{"name":"init"}]}}`,
+			current:  `{"spec":{"initContainers":[{"name":"init"},{"name":"osm-init"}],"containers":[{"name":"envoy"}]}}`,
+			expected: `{"spec":{"initContainers":[{"name":"init"},{"name":"osm-init"}],"containers":[{"name":"envoy"}]}}`,
+		},
+		{
+			name:           "native sidecar",
+			current:        `{"spec":{"initContainers":[{"name":"osm-init"},{"name":"envoy"}]}}`,
+			nativeSidecars: []string{"envoy"},
+			expected:       `{"spec":{"initContainers":[{"name":"osm-init"},{"name":"envoy","restartPolicy":"Always"}]}}`,
+		},
+		{
+			name:           "restart policy of the original init containers kept",
+			original:       `{"spec":{"initContainers":[{"name":"log-shipper","restartPolicy":"Always"}]}}`,
+			current:        `{"spec":{"initContainers":[{"name":"log-shipper"},{"name":"osm-init"},{"name":"envoy"}]}}`,
+			nativeSidecars: []string{"envoy"},
+			expected:       `{"spec":{"initContainers":[{"name":"log-shipper","restartPolicy":"Always"},{"name":"osm-init"},{"name":"envoy","restartPolicy":"Always"}]}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			actual, err := setInitContainerRestartPolicies([]byte(tc.original), []byte(tc.current), tc.nativeSidecars)
+			assert.NoError(err)
+			assert.JSONEq(tc.expected, string(actual))
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package injector
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+// nftablesTable is the name of the nftables table holding the sidecar interception rules of an IP address family
+const nftablesTable = "osm_proxy"
+
+// nftablesInboundStaticRules is the list of nftables rules of the osm_proxy_inbound chain related to inbound traffic
+// interception and redirection, equivalent to the rules of the OSM_PROXY_INBOUND chain in iptablesInboundStaticRules
+var nftablesInboundStaticRules = []string{
+	// Skip metrics query traffic being directed to Envoy's inbound prometheus listener port
+	fmt.Sprintf("tcp dport %d return", constants.EnvoyPrometheusInboundListenerPort),
+
+	// Skip inbound health probes
+	fmt.Sprintf("tcp dport %d return", constants.LivenessProbePort),
+	fmt.Sprintf("tcp dport %d return", constants.ReadinessProbePort),
+	fmt.Sprintf("tcp dport %d return", constants.StartupProbePort),
+	fmt.Sprintf("tcp dport %d return", constants.HealthcheckPort),
+
+	// Skip the proxy readiness probe of native sidecar containers
+	fmt.Sprintf("tcp dport %d return", constants.EnvoyReadyPort),
+
+	// Redirect remaining inbound traffic to Envoy
+	"meta l4proto tcp jump osm_proxy_in_redirect",
+}
+
+// nftablesOutboundStaticRules returns the nftables rules of the osm_proxy_outbound chain related to outbound traffic
+// interception and redirection for the given IP address family, equivalent to the rules of the OSM_PROXY_OUTBOUND
+// chain in iptablesOutboundStaticRules
+func nftablesOutboundStaticRules(family ipFamily) []string {
+	return []string{
+		// Outbound traffic from Envoy to the local app over the loopback interface jumps to the inbound proxy redirect chain
+		fmt.Sprintf("oifname \"lo\" %s daddr != %s meta skuid %d jump osm_proxy_in_redirect", family.nftFamily, family.localhostCIDR, constants.EnvoyUID),
+
+		// Outbound traffic from the app to itself over the loopback interface is not redirected via the proxy
+		fmt.Sprintf("oifname \"lo\" meta skuid != %d return", constants.EnvoyUID),
+
+		// Don't redirect Envoy traffic back to itself
+		fmt.Sprintf("meta skuid %d return", constants.EnvoyUID),
+
+		// Skip localhost traffic, doesn't need to be routed via the proxy
+		fmt.Sprintf("%s daddr %s return", family.nftFamily, family.localhostCIDR),
+	}
+}
+
+// generateNftablesCommands generates a list of nftables commands to set up sidecar interception and redirection,
+// equivalent to the iptables commands generated by generateIptablesCommands.
+func generateNftablesCommands(proxyMode configv1alpha2.LocalProxyMode, outboundIPRangeExclusionList []string, outboundIPRangeInclusionList []string, outboundPortExclusionList []int, inboundPortExclusionList []int, networkInterfaceExclusionList []string) string {
+	return generatePodIPFamiliesCommands(func(family ipFamily) string {
+		return generateNftablesRestoreCommand(family, proxyMode, outboundIPRangeExclusionList, outboundIPRangeInclusionList, outboundPortExclusionList, inboundPortExclusionList, networkInterfaceExclusionList)
+	})
+}
+
+// generateNftablesRestoreCommand generates the command loading the nftables table to set up sidecar interception
+// and redirection for the given IP address family. IP ranges of the other family are ignored.
+// The table is deleted before being loaded, so that the command can be run again.
+func generateNftablesRestoreCommand(family ipFamily, proxyMode configv1alpha2.LocalProxyMode, outboundIPRangeExclusionList []string, outboundIPRangeInclusionList []string, outboundPortExclusionList []int, inboundPortExclusionList []int, networkInterfaceExclusionList []string) string {
+	// 1. Create inbound rules
+	prerouting := []string{"meta l4proto tcp jump osm_proxy_inbound"}
+
+	// Ignore inbound traffic on specified interfaces and to excluded ports before redirecting it to the proxy
+	var inbound []string
+	for _, iface := range networkInterfaceExclusionList {
+		inbound = append(inbound, fmt.Sprintf("iifname %s return", nftablesInterfaceName(iface)))
+	}
+
+	// 2. Create dynamic inbound ports exclusion rules
+	if len(inboundPortExclusionList) > 0 {
+		inbound = append(inbound, fmt.Sprintf("tcp dport %s return", nftablesPortSet(inboundPortExclusionList)))
+	}
+	inbound = append(inbound, nftablesInboundStaticRules...)
+
+	inRedirect := []string{fmt.Sprintf("meta l4proto tcp redirect to :%d", constants.EnvoyInboundListenerPort)}
+
+	// 3. Create outbound rules
+	var output []string
+	if proxyMode == configv1alpha2.LocalProxyModePodIP {
+		// For envoy -> local service container proxying, send traffic to pod IP instead of localhost, except traffic to
+		// the Proxy Admin port listening on localhost
+		output = append(output, fmt.Sprintf("meta l4proto tcp oifname \"lo\" %s daddr %s tcp dport != %d meta skuid %d dnat to $%s", family.nftFamily, family.localhostCIDR, constants.EnvoyAdminPort, constants.EnvoyUID, family.podIPVar))
+	}
+	output = append(output, "meta l4proto tcp jump osm_proxy_outbound")
+
+	outbound := nftablesOutboundStaticRules(family)
+
+	// Ignore outbound traffic in specified interfaces
+	for _, iface := range networkInterfaceExclusionList {
+		outbound = append(outbound, fmt.Sprintf("oifname %s return", nftablesInterfaceName(iface)))
+	}
+
+	// 4. Create dynamic outbound IP range exclusion rules
+	for _, cidr := range filterIPRangesByFamily(outboundIPRangeExclusionList, family) {
+		outbound = append(outbound, fmt.Sprintf("%s daddr %s return", family.nftFamily, cidr))
+	}
+
+	// 5. Create dynamic outbound ports exclusion rules
+	if len(outboundPortExclusionList) > 0 {
+		outbound = append(outbound, fmt.Sprintf("tcp dport %s return", nftablesPortSet(outboundPortExclusionList)))
+	}
+
+	// 6. Create dynamic outbound IP range inclusion rules
+	if len(outboundIPRangeInclusionList) > 0 {
+		// Redirect specified IP ranges to the proxy. When only IP ranges of the other family are specified, no traffic
+		// of this family is redirected.
+		for _, cidr := range filterIPRangesByFamily(outboundIPRangeInclusionList, family) {
+			outbound = append(outbound, fmt.Sprintf("%s daddr %s jump osm_proxy_out_redirect", family.nftFamily, cidr))
+		}
+		// Remaining traffic not belonging to specified inclusion IP ranges are not redirected
+		outbound = append(outbound, "return")
+	} else {
+		// Redirect remaining outbound traffic to the proxy
+		outbound = append(outbound, "jump osm_proxy_out_redirect")
+	}
+
+	outRedirect := []string{
+		// Redirects outbound TCP traffic hitting the osm_proxy_out_redirect chain to Envoy's outbound listener port
+		fmt.Sprintf("meta l4proto tcp redirect to :%d", constants.EnvoyOutboundListenerPort),
+
+		// Traffic to the Proxy Admin port flows to the Proxy -- not redirected
+		fmt.Sprintf("tcp dport %d accept", constants.EnvoyAdminPort),
+	}
+
+	var table strings.Builder
+	fmt.Fprintln(&table, "# OSM sidecar interception rules")
+	fmt.Fprintf(&table, "add table %s %s\n", family.nftFamily, nftablesTable)
+	fmt.Fprintf(&table, "delete table %s %s\n", family.nftFamily, nftablesTable)
+	fmt.Fprintf(&table, "table %s %s {\n", family.nftFamily, nftablesTable)
+	writeNftablesChain(&table, "prerouting", "type nat hook prerouting priority dstnat; policy accept;", prerouting)
+	writeNftablesChain(&table, "output", "type nat hook output priority dstnat; policy accept;", output)
+	writeNftablesChain(&table, "osm_proxy_inbound", "", inbound)
+	writeNftablesChain(&table, "osm_proxy_in_redirect", "", inRedirect)
+	writeNftablesChain(&table, "osm_proxy_outbound", "", outbound)
+	writeNftablesChain(&table, "osm_proxy_out_redirect", "", outRedirect)
+	fmt.Fprint(&table, "}")
+
+	return fmt.Sprintf(`nft -f - <<EOF
+%s
+EOF
+`, table.String())
+}
+
+// writeNftablesChain writes the definition of the chain with the given name, hook and rules
+func writeNftablesChain(table *strings.Builder, name string, hook string, rules []string) {
+	fmt.Fprintf(table, "\tchain %s {\n", name)
+	if hook != "" {
+		fmt.Fprintf(table, "\t\t%s\n", hook)
+	}
+	for _, rule := range rules {
+		fmt.Fprintf(table, "\t\t%s\n", rule)
+	}
+	fmt.Fprintln(table, "\t}")
+}
+
+// nftablesPortSet returns the anonymous nftables set matching the given ports
+func nftablesPortSet(ports []int) string {
+	var portsStr []string
+	for _, port := range ports {
+		portsStr = append(portsStr, strconv.Itoa(port))
+	}
+	return fmt.Sprintf("{ %s }", strings.Join(portsStr, ", "))
+}
+
+// nftablesInterfaceName returns the quoted nftables interface name matching the given iptables interface name, whose
+// '+' suffix matches any interface name with the given prefix
+func nftablesInterfaceName(iface string) string {
+	if strings.HasSuffix(iface, "+") {
+		iface = strings.TrimSuffix(iface, "+") + "*"
+	}
+	return strconv.Quote(iface)
+}
@@ -0,0 +1,302 @@
+package injector
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+)
+
+func TestGenerateNftablesRestoreCommand(t *testing.T) {
+	testCases := []struct {
+		name   string
+		family ipFamily
+		golden string
+	}{
+		{
+			name:   "ipv4",
+			family: ipv4Family,
+			golden: "expected_nftables_ipv4.txt",
+		},
+		{
+			name:   "ipv6",
+			family: ipv6Family,
+			golden: "expected_nftables_ipv6.txt",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+
+			expected, err := os.ReadFile(filepath.Join("test_fixtures", tc.golden))
+			a.NoError(err)
+
+			actual := generateNftablesRestoreCommand(tc.family, configv1alpha2.LocalProxyModePodIP,
+				[]string{"1.1.1.1/32", "fd00::1/128"}, []string{"10.0.0.0/8", "fd00::/8"}, []int{6060, 6061}, []int{7070}, []string{"eth1", "veth+"})
+			a.Equal(string(expected), actual)
+		})
+	}
+}
+
+func TestGenerateNftablesCommands(t *testing.T) {
+	a := assert.New(t)
+
+	actual := generateNftablesCommands(configv1alpha2.LocalProxyModeLocalhost, nil, nil, nil, nil, nil)
+
+	a.True(strings.HasPrefix(actual, podIPFamiliesCommand))
+	a.Contains(actual, "if [ -n \"$POD_IPV4\" ]; then\n"+generateNftablesRestoreCommand(ipv4Family, configv1alpha2.LocalProxyModeLocalhost, nil, nil, nil, nil, nil)+"fi\n")
+	a.Contains(actual, "if [ -n \"$POD_IPV6\" ]; then\n"+generateNftablesRestoreCommand(ipv6Family, configv1alpha2.LocalProxyModeLocalhost, nil, nil, nil, nil, nil)+"fi\n")
+}
+
+// TestNftablesMatchIptablesSemantics verifies that the nftables rules intercept and redirect the same traffic as the
+// iptables rules, by comparing the rules of both backends normalized into the same form chain by chain
+func TestNftablesMatchIptablesSemantics(t *testing.T) {
+	testCases := []struct {
+		name                       string
+		proxyMode                  configv1alpha2.LocalProxyMode
+		outboundIPRangeExclusions  []string
+		outboundIPRangeInclusions  []string
+		outboundPortExclusions     []int
+		inboundPortExclusions      []int
+		networkInterfaceExclusions []string
+	}{
+		{
+			name: "no exclusions or inclusions",
+		},
+		{
+			name:                      "outbound exclusions",
+			outboundIPRangeExclusions: []string{"1.1.1.1/32", "2.2.2.2/24", "fd00::1/128"},
+			outboundPortExclusions:    []int{6060, 6061},
+		},
+		{
+			name:                  "inbound exclusions",
+			inboundPortExclusions: []int{7070, 7071},
+		},
+		{
+			name:                      "outbound inclusions",
+			outboundIPRangeInclusions: []string{"10.0.0.0/8", "fd00::/8"},
+		},
+		{
+			name:                      "outbound inclusions of the other family only",
+			outboundIPRangeInclusions: []string{"10.0.0.0/8"},
+		},
+		{
+			name:                       "network interface exclusions",
+			networkInterfaceExclusions: []string{"eth1", "veth+"},
+		},
+		{
+			name:                       "all exclusions and inclusions in proxy mode pod ip",
+			proxyMode:                  configv1alpha2.LocalProxyModePodIP,
+			outboundIPRangeExclusions:  []string{"1.1.1.1/32", "fd00::1/128"},
+			outboundIPRangeInclusions:  []string{"10.0.0.0/8", "fd00::/8"},
+			outboundPortExclusions:     []int{6060},
+			inboundPortExclusions:      []int{7070, 7071},
+			networkInterfaceExclusions: []string{"eth1", "eth2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, family := range []ipFamily{ipv4Family, ipv6Family} {
+			t.Run(tc.name+" "+family.nftFamily, func(t *testing.T) {
+				a := assert.New(t)
+
+				iptables := generateIptablesRestoreCommand(family, tc.proxyMode, tc.outboundIPRangeExclusions, tc.outboundIPRangeInclusions, tc.outboundPortExclusions, tc.inboundPortExclusions, tc.networkInterfaceExclusions)
+				nftables := generateNftablesRestoreCommand(family, tc.proxyMode, tc.outboundIPRangeExclusions, tc.outboundIPRangeInclusions, tc.outboundPortExclusions, tc.inboundPortExclusions, tc.networkInterfaceExclusions)
+
+				a.Equal(normalizeChains(parseIptablesRules(t, iptables)), normalizeChains(parseNftablesRules(t, nftables)))
+			})
+		}
+	}
+}
+
+// semanticRule is a rule of iptables or nftables normalized into the same form
+type semanticRule struct {
+	proto    string
+	dports   string
+	daddr    string
+	iifname  string
+	oifname  string
+	skuid    string
+	verdict  string
+	negation string
+}
+
+// parseIptablesRules returns the rules of each chain of the given iptables-restore command
+func parseIptablesRules(t *testing.T, cmd string) map[string][]semanticRule {
+	chains := map[string][]semanticRule{}
+	for _, line := range strings.Split(cmd, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || (fields[0] != "-A" && fields[0] != "-I") {
+			continue
+		}
+
+		var rule semanticRule
+		negate := false
+		for i := 2; i < len(fields); i++ {
+			value := ""
+			if i+1 < len(fields) {
+				value = fields[i+1]
+			}
+			switch fields[i] {
+			case "!":
+				negate = true
+				continue
+			case "-p":
+				rule.proto = value
+			case "--dport", "--dports":
+				rule.dports = value
+			case "-d":
+				rule.daddr = value
+			case "-i":
+				rule.iifname = strings.Replace(value, "+", "*", 1)
+			case "-o":
+				rule.oifname = strings.Replace(value, "+", "*", 1)
+			case "--uid-owner":
+				rule.skuid = value
+			case "-j":
+				rule.verdict = strings.ToLower(value)
+				if strings.HasPrefix(value, "OSM_PROXY") {
+					rule.verdict = "jump " + rule.verdict
+				}
+			case "--to-port", "--to-destination":
+				rule.verdict += " " + value
+			case "--match", "-m":
+			default:
+				t.Fatalf("unexpected iptables option %s in rule %s", fields[i], line)
+			}
+			if negate {
+				rule.negation = fields[i]
+				negate = false
+			}
+			i++
+		}
+
+		chain := strings.ToLower(fields[1])
+		if fields[0] == "-I" {
+			chains[chain] = append([]semanticRule{rule}, chains[chain]...)
+		} else {
+			chains[chain] = append(chains[chain], rule)
+		}
+	}
+	return chains
+}
+
+// parseNftablesRules returns the rules of each chain of the given nft command
+func parseNftablesRules(t *testing.T, cmd string) map[string][]semanticRule {
+	chains := map[string][]semanticRule{}
+	chain := ""
+	for _, line := range strings.Split(cmd, "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 3 && fields[0] == "chain":
+			chain = fields[1]
+			continue
+		case len(fields) > 0 && fields[0] == "}":
+			chain = ""
+			continue
+		case chain == "" || len(fields) == 0 || fields[0] == "type":
+			continue
+		}
+
+		var rule semanticRule
+		for i := 0; i < len(fields); i++ {
+			negate := false
+			next := func() string {
+				i++
+				if fields[i] == "!=" {
+					negate = true
+					i++
+				}
+				return fields[i]
+			}
+			field := fields[i]
+			switch field {
+			case "meta":
+				switch next() {
+				case "l4proto":
+					rule.proto = next()
+				case "skuid":
+					rule.skuid = next()
+					field = "--uid-owner"
+				}
+			case "tcp":
+				next()
+				rule.proto = "tcp"
+				if fields[i+1] == "{" {
+					i += 2
+					var ports []string
+					for ; fields[i] != "}"; i++ {
+						ports = append(ports, strings.TrimSuffix(fields[i], ","))
+					}
+					rule.dports = strings.Join(ports, ",")
+				} else {
+					rule.dports = next()
+				}
+				field = "--dport"
+			case "ip", "ip6":
+				next()
+				rule.daddr = next()
+				field = "-d"
+			case "iifname":
+				rule.iifname = strings.Trim(next(), `"`)
+			case "oifname":
+				rule.oifname = strings.Trim(next(), `"`)
+			case "return", "accept":
+				rule.verdict = strings.ToUpper(field)
+			case "jump":
+				rule.verdict = "jump " + next()
+			case "redirect":
+				rule.verdict = "REDIRECT " + strings.TrimPrefix(fields[i+2], ":")
+				i += 2
+			case "dnat":
+				rule.verdict = "DNAT " + fields[i+2]
+				i += 2
+			default:
+				t.Fatalf("unexpected nftables expression %s in rule %s", field, line)
+			}
+			if negate {
+				rule.negation = field
+			}
+		}
+		chains[chain] = append(chains[chain], rule)
+	}
+	return chains
+}
+
+// normalizeChains returns the given chains with the rules matching only TCP traffic normalized, as only TCP traffic
+// jumps to the interception chains, and consecutive rules with the same verdict sorted, as their order doesn't change
+// the verdict of a packet
+func normalizeChains(chains map[string][]semanticRule) map[string][]semanticRule {
+	for name, rules := range chains {
+		for i := range rules {
+			rules[i].verdict = strings.ToUpper(rules[i].verdict)
+			if name != "prerouting" && name != "output" {
+				rules[i].proto = ""
+			}
+		}
+
+		for start := 0; start < len(rules); {
+			end := start
+			for end < len(rules) && rules[end].verdict == rules[start].verdict {
+				end++
+			}
+			run := rules[start:end]
+			sort.Slice(run, func(i, j int) bool {
+				return strings.Join(semanticRuleFields(run[i]), "|") < strings.Join(semanticRuleFields(run[j]), "|")
+			})
+			start = end
+		}
+	}
+	return chains
+}
+
+// semanticRuleFields returns the fields of the given rule, used to sort rules
+func semanticRuleFields(rule semanticRule) []string {
+	return []string{rule.proto, rule.dports, rule.daddr, rule.iifname, rule.oifname, rule.skuid, rule.negation, rule.verdict}
+}
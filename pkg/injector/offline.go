@@ -0,0 +1,267 @@
+package injector
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	mapset "github.com/deckarep/golang-set"
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/google/uuid"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/models"
+)
+
+// podTemplatePaths are the paths of the pod templates of the workloads injected offline, by kind. Pods are injected
+// as a whole.
+var podTemplatePaths = map[schema.GroupKind][]string{
+	{Kind: "Pod"}:                        nil,
+	{Group: "apps", Kind: "Deployment"}:  {"spec", "template"},
+	{Group: "apps", Kind: "StatefulSet"}: {"spec", "template"},
+	{Group: "apps", Kind: "DaemonSet"}:   {"spec", "template"},
+	{Group: "apps", Kind: "ReplicaSet"}:  {"spec", "template"},
+	{Group: "batch", Kind: "Job"}:        {"spec", "template"},
+	{Group: "batch", Kind: "CronJob"}:    {"spec", "jobTemplate", "spec", "template"},
+}
+
+// OfflineInjector injects the sidecar into workload manifests rather than into the pods created in the cluster, so
+// that the injected manifests can be reviewed before they are applied.
+//
+// The bootstrap config of the sidecar holds a certificate issued to each pod, so it is not rendered: the pods of
+// injected workloads reference a bootstrap config Secret which osm-injector creates when the pods are admitted,
+// along with a proxy UUID unique to each pod.
+type OfflineInjector struct {
+	wh *mutatingWebhook
+}
+
+// NewOfflineInjector returns an OfflineInjector resolving the mesh configuration from the given controller. The given
+// Kubernetes client, if any, is used to detect whether the cluster supports native sidecar containers. The given CNI
+// validation image is required when the traffic of pods is redirected by the OSM CNI plugin.
+func NewOfflineInjector(kubeClient kubernetes.Interface, kubeController k8s.Controller, meshName, osmNamespace string, osmContainerPullPolicy corev1.PullPolicy, enableCNI bool, cniValidationImage string) *OfflineInjector {
+	wh := &mutatingWebhook{
+		kubeController:         kubeController,
+		osmNamespace:           osmNamespace,
+		meshName:               meshName,
+		osmContainerPullPolicy: osmContainerPullPolicy,
+		enableCNI:              enableCNI,
+		cniValidationImage:     cniValidationImage,
+
+		// Envoy sidecars should never be injected in these namespaces
+		nonInjectNamespaces: mapset.NewSet(
+			metav1.NamespaceSystem,
+			metav1.NamespacePublic,
+			osmNamespace,
+		),
+	}
+	if kubeClient != nil {
+		wh.nativeSidecarsSupported = isNativeSidecarSupported(kubeClient)
+	}
+
+	return &OfflineInjector{wh: wh}
+}
+
+// Inject writes the manifests read from the given reader to the given writer, with the sidecar injected into the pods
+// and pod templates of the workloads the mesh injects. Other manifests are written as is. Manifests without a namespace
+// are injected as manifests of the given namespace.
+func (i *OfflineInjector) Inject(r io.Reader, w io.Writer, defaultNamespace string) error {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(r))
+	first := true
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading manifests: %w", err)
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		injected, err := i.injectManifest(doc, defaultNamespace)
+		if err != nil {
+			return err
+		}
+
+		if !first {
+			if _, err := io.WriteString(w, "---\n"); err != nil {
+				return err
+			}
+		}
+		first = false
+		if _, err := w.Write(injected); err != nil {
+			return err
+		}
+	}
+}
+
+// injectManifest returns the given manifest with the sidecar injected into its pod or pod template, or the manifest
+// as is when it does not describe a workload injected by the mesh
+func (i *OfflineInjector) injectManifest(doc []byte, defaultNamespace string) ([]byte, error) {
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(doc, &obj.Object); err != nil {
+		return nil, fmt.Errorf("error decoding manifest: %w", err)
+	}
+	if obj.Object == nil {
+		return doc, nil
+	}
+	path, ok := podTemplatePaths[obj.GroupVersionKind().GroupKind()]
+	if !ok {
+		return doc, nil
+	}
+
+	kind, name := obj.GetKind(), obj.GetName()
+	namespace := obj.GetNamespace()
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+
+	template := obj.Object
+	if len(path) > 0 {
+		var found bool
+		var err error
+		if template, found, err = unstructured.NestedMap(obj.Object, path...); err != nil || !found {
+			return nil, fmt.Errorf("%s %s/%s has no pod template", kind, namespace, name)
+		}
+	}
+
+	injected, err := i.injectPodTemplate(template, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if injected == nil {
+		return doc, nil
+	}
+
+	if len(path) > 0 {
+		if err := unstructured.SetNestedMap(obj.Object, injected, path...); err != nil {
+			return nil, err
+		}
+	} else {
+		obj.Object["metadata"], obj.Object["spec"] = injected["metadata"], injected["spec"]
+	}
+
+	return yaml.Marshal(obj.Object)
+}
+
+// injectPodTemplate returns the given pod template with the sidecar injected, or nil when the pods of the template are
+// not injected by the mesh
+func (i *OfflineInjector) injectPodTemplate(template map[string]interface{}, kind, namespace, name string) (map[string]interface{}, error) {
+	raw, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   template["metadata"],
+		"spec":       template["spec"],
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var pod corev1.Pod
+	if err := json.Unmarshal(raw, &pod); err != nil {
+		return nil, fmt.Errorf("error decoding the pod template of %s %s/%s: %w", kind, namespace, name, err)
+	}
+	// The probes rewritten by the injection are recorded, for the bootstrap config to be created when pods are admitted
+	originalHealthProbes := rewriteHealthProbes(pod.DeepCopy())
+
+	// The proxy UUID is derived from the workload for the same manifests to be injected identically. It is replaced
+	// by a UUID unique to each pod when pods are admitted.
+	proxyUUID := uuid.NewSHA1(uuid.NameSpaceOID, []byte(fmt.Sprintf("%s/%s/%s", kind, namespace, name)))
+
+	// The request is a dry-run, so that the bootstrap config is not created
+	req := &admissionv1.AdmissionRequest{
+		UID:       types.UID(proxyUUID.String()),
+		Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "Pod"},
+		Namespace: namespace,
+		Operation: admissionv1.Create,
+		Object:    runtime.RawExtension{Raw: raw},
+		DryRun:    pointer.Bool(true),
+	}
+	resp := i.wh.mutate(req, proxyUUID)
+	if resp.Result != nil {
+		return nil, fmt.Errorf("error injecting %s %s/%s: %s", kind, namespace, name, resp.Result.Message)
+	}
+	if len(resp.Patch) == 0 {
+		return nil, nil
+	}
+
+	patch, err := jsonpatch.DecodePatch(resp.Patch)
+	if err != nil {
+		return nil, err
+	}
+	patched, err := patch.Apply(raw)
+	if err != nil {
+		return nil, fmt.Errorf("error injecting %s %s/%s: %w", kind, namespace, name, err)
+	}
+	injected := make(map[string]interface{})
+	if err := json.Unmarshal(patched, &injected); err != nil {
+		return nil, err
+	}
+
+	// Pods are always admitted by osm-injector, which creates their bootstrap config
+	annotations := map[string]string{
+		constants.SidecarInjectionAnnotation: "enabled",
+	}
+	if kind, _ := getProxyKind(&pod); kind == models.KindSidecar && len(originalHealthProbes) > 0 {
+		probes, err := json.Marshal(originalHealthProbes)
+		if err != nil {
+			return nil, err
+		}
+		annotations[constants.OriginalHealthProbesAnnotation] = string(probes)
+	}
+	for key, value := range annotations {
+		if err := unstructured.SetNestedField(injected, value, "metadata", "annotations", key); err != nil {
+			return nil, err
+		}
+	}
+	unstructured.RemoveNestedField(injected, "metadata", "creationTimestamp")
+
+	return map[string]interface{}{
+		"metadata": injected["metadata"],
+		"spec":     injected["spec"],
+	}, nil
+}
+
+// createOfflineEnvoyBootstrapConfig creates the bootstrap config of the given pod injected offline, from the health
+// probes recorded on the pod when it was injected
+func (wh *mutatingWebhook) createOfflineEnvoyBootstrapConfig(proxyUUID uuid.UUID, pod *corev1.Pod, namespace string, cert *certificate.Certificate) (*corev1.Secret, error) {
+	var originalHealthProbes map[string]models.HealthProbes
+	if probes, ok := pod.Annotations[constants.OriginalHealthProbesAnnotation]; ok {
+		if err := json.Unmarshal([]byte(probes), &originalHealthProbes); err != nil {
+			return nil, fmt.Errorf("invalid annotation %s: %w", constants.OriginalHealthProbesAnnotation, err)
+		}
+	}
+
+	// The proxy was injected as a native sidecar container when it is among the init containers
+	var nativeSidecar bool
+	for _, container := range pod.Spec.InitContainers {
+		if container.Name == constants.EnvoyContainerName {
+			nativeSidecar = true
+			break
+		}
+	}
+
+	statsTags, err := getSidecarStatsTagsForPod(pod)
+	if err != nil {
+		return nil, err
+	}
+
+	return wh.createEnvoyBootstrapConfig(proxyUUID, namespace, cert, originalHealthProbes, nativeSidecar, statsTags)
+}
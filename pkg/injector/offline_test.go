@@ -0,0 +1,193 @@
+package injector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	mapset "github.com/deckarep/golang-set"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	tresorFake "github.com/openservicemesh/osm/pkg/certificate/providers/tresor/fake"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy/bootstrap"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/models"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+const offlineManifests = `# The bookstore service
+apiVersion: v1
+kind: Service
+metadata:
+  name: bookstore
+spec:
+  ports:
+  - port: 14001
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: bookstore
+spec:
+  selector:
+    matchLabels:
+      app: bookstore
+  template:
+    metadata:
+      labels:
+        app: bookstore
+    spec:
+      serviceAccountName: bookstore
+      containers:
+      - name: bookstore
+        image: bookstore:v1
+        ports:
+        - containerPort: 14001
+        readinessProbe:
+          httpGet:
+            path: /ready
+            port: 14001
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: bookbuyer
+  namespace: bookbuyer
+  annotations:
+    openservicemesh.io/sidecar-injection: disabled
+spec:
+  containers:
+  - name: bookbuyer
+    image: bookbuyer:v1
+`
+
+func newOfflineTestController(t *testing.T) *k8s.MockController {
+	mockCtrl := gomock.NewController(t)
+	mockController := k8s.NewMockController(mockCtrl)
+
+	meshConfig := v1alpha2.MeshConfig{
+		Spec: v1alpha2.MeshConfigSpec{
+			Sidecar: v1alpha2.SidecarSpec{
+				EnvoyImage:         "envoy-image",
+				InitContainerImage: "init-container-image",
+			},
+		},
+	}
+	mockController.EXPECT().IsMonitoredNamespace(gomock.Any()).Return(true).AnyTimes()
+	mockController.EXPECT().GetNamespace(gomock.Any()).DoAndReturn(func(name string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{constants.SidecarInjectionAnnotation: "enabled"},
+			},
+		}
+	}).AnyTimes()
+	mockController.EXPECT().GetMeshConfig().Return(meshConfig).AnyTimes()
+	mockController.EXPECT().GetSidecarConfig(gomock.Any(), gomock.Any()).Return(models.SidecarConfig{Sidecar: meshConfig.Spec.Sidecar}, nil).AnyTimes()
+
+	return mockController
+}
+
+func TestOfflineInject(t *testing.T) {
+	assert := tassert.New(t)
+
+	injector := NewOfflineInjector(nil, newOfflineTestController(t), "osm", tests.OsmNamespace, corev1.PullIfNotPresent, false, "")
+
+	out := new(bytes.Buffer)
+	assert.NoError(injector.Inject(strings.NewReader(offlineManifests), out, "bookstore"))
+
+	docs := strings.Split(out.String(), "---\n")
+	assert.Len(docs, 3)
+
+	// Manifests of other kinds are written as is
+	assert.Equal(strings.Split(offlineManifests, "---\n")[0], docs[0])
+
+	// Pods which are not injected are written as is
+	assert.Equal(strings.Split(offlineManifests, "---\n")[2], docs[2])
+
+	var deployment appsv1.Deployment
+	assert.NoError(yaml.UnmarshalStrict([]byte(docs[1]), &deployment))
+	template := deployment.Spec.Template
+
+	proxyUUID := uuid.NewSHA1(uuid.NameSpaceOID, []byte("Deployment/bookstore/bookstore"))
+	assert.Equal(proxyUUID.String(), template.Labels[constants.EnvoyUniqueIDLabelName])
+	assert.Equal("bookstore", template.Labels["app"])
+	assert.Equal("enabled", template.Annotations[constants.SidecarInjectionAnnotation])
+
+	var originalHealthProbes map[string]models.HealthProbes
+	assert.NoError(json.Unmarshal([]byte(template.Annotations[constants.OriginalHealthProbesAnnotation]), &originalHealthProbes))
+	assert.Equal(map[string]models.HealthProbes{
+		"bookstore": {Readiness: &models.HealthProbe{Path: "/ready", Port: 14001, IsHTTP: true}},
+	}, originalHealthProbes)
+
+	assert.Len(template.Spec.InitContainers, 1)
+	assert.Equal(constants.InitContainerName, template.Spec.InitContainers[0].Name)
+	assert.Len(template.Spec.Containers, 2)
+	assert.Equal(constants.EnvoyContainerName, template.Spec.Containers[1].Name)
+	assert.Equal(int32(constants.ReadinessProbePort), template.Spec.Containers[0].ReadinessProbe.HTTPGet.Port.IntVal)
+	assert.Equal(getVolumeSpec(bootstrapConfigName(proxyUUID)), template.Spec.Volumes[0])
+
+	// The same manifests are injected identically
+	again := new(bytes.Buffer)
+	assert.NoError(injector.Inject(strings.NewReader(offlineManifests), again, "bookstore"))
+	assert.Equal(out.String(), again.String())
+}
+
+func TestOfflineInjectedPodAdmission(t *testing.T) {
+	assert := tassert.New(t)
+
+	kubeController := newOfflineTestController(t)
+	injector := NewOfflineInjector(nil, kubeController, "osm", tests.OsmNamespace, corev1.PullIfNotPresent, false, "")
+
+	out := new(bytes.Buffer)
+	assert.NoError(injector.Inject(strings.NewReader(offlineManifests), out, "bookstore"))
+	var deployment appsv1.Deployment
+	assert.NoError(yaml.Unmarshal([]byte(strings.Split(out.String(), "---\n")[1]), &deployment))
+
+	// The pods of the injected deployment are admitted by osm-injector, whose bootstrap config does not exist
+	pod := &corev1.Pod{
+		ObjectMeta: deployment.Spec.Template.ObjectMeta,
+		Spec:       deployment.Spec.Template.Spec,
+	}
+	raw, err := json.Marshal(pod)
+	assert.NoError(err)
+
+	client := fake.NewSimpleClientset()
+	wh := &mutatingWebhook{
+		kubeClient:          client,
+		kubeController:      kubeController,
+		certManager:         tresorFake.NewFake(1 * time.Hour),
+		nonInjectNamespaces: mapset.NewSet(),
+	}
+	req := &admissionv1.AdmissionRequest{
+		Namespace: "bookstore",
+		Object:    runtime.RawExtension{Raw: raw},
+	}
+	proxyUUID := uuid.New()
+	_, err = wh.createPatch(pod, req, proxyUUID)
+	assert.NoError(err)
+
+	// Only the proxy UUID is replaced, and the bootstrap config is created with the recorded health probes
+	assert.Equal(proxyUUID.String(), pod.Labels[constants.EnvoyUniqueIDLabelName])
+	assert.Equal(getVolumeSpec(bootstrapConfigName(proxyUUID)), pod.Spec.Volumes[0])
+	assert.Len(pod.Spec.Containers, 2)
+	assert.Equal(deployment.Spec.Template.Spec.Containers[0].ReadinessProbe, pod.Spec.Containers[0].ReadinessProbe)
+
+	secret, err := client.CoreV1().Secrets("bookstore").Get(context.Background(), bootstrapConfigName(proxyUUID), metav1.GetOptions{})
+	assert.NoError(err)
+	assert.Contains(string(secret.Data[bootstrap.EnvoyBootstrapConfigFile]), "readiness_listener")
+}
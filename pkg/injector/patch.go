@@ -13,8 +13,10 @@ import (
 	"gomodules.xyz/jsonpatch/v2"
 	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/errcode"
@@ -22,33 +24,81 @@ import (
 	"github.com/openservicemesh/osm/pkg/metricsstore"
 	"github.com/openservicemesh/osm/pkg/models"
 	"github.com/openservicemesh/osm/pkg/utils"
+	"github.com/openservicemesh/osm/pkg/version"
 )
 
 func (wh *mutatingWebhook) createPatch(pod *corev1.Pod, req *admissionv1.AdmissionRequest, proxyUUID uuid.UUID) ([]byte, error) {
 	// pod.Namespace is unset in the API request to the webhook so namespace is derived from req.Namespace
 	namespace := req.Namespace
 
-	// Issue a certificate for the proxy sidecar - used for Envoy to connect to XDS (not Envoy-to-Envoy connections)
-	cnPrefix := models.NewXDSCertCNPrefix(proxyUUID, models.KindSidecar, identity.New(pod.Spec.ServiceAccountName, namespace))
-	log.Debug().Msgf("Patching POD spec: service-account=%s, namespace=%s with certificate CN prefix=%s", pod.Spec.ServiceAccountName, namespace, cnPrefix)
-	startTime := time.Now()
-	bootstrapCertificate, err := wh.certManager.IssueCertificate(certificate.ForCommonNamePrefix(cnPrefix))
+	kind, err := getProxyKind(pod)
 	if err != nil {
-		log.Error().Err(err).Msgf("Error issuing bootstrap certificate for Envoy with CN prefix=%s", cnPrefix)
 		return nil, err
 	}
-	elapsed := time.Since(startTime)
 
-	metricsstore.DefaultMetricsStore.CertIssuedCount.Inc()
-	metricsstore.DefaultMetricsStore.CertIssuedTime.
-		WithLabelValues().Observe(elapsed.Seconds())
-	originalHealthProbes := rewriteHealthProbes(pod)
+	// The settings of the sidecar are overridden by the SidecarProfile selecting the pod, if any, and by the
+	// annotations of the pod, which are validated before any side effect of the webhook
+	var meshConfig v1alpha2.MeshConfig
+	var sidecarSettings podSidecarSettings
+	var proxyLifecycle proxyLifecycle
+	if kind != models.KindProxylessGRPC {
+		sidecarConfig, err := wh.kubeController.GetSidecarConfig(namespace, pod.Labels)
+		if err != nil {
+			return nil, err
+		}
+		meshConfig = wh.kubeController.GetMeshConfig()
+		meshConfig.Spec.Sidecar = sidecarConfig.Sidecar
 
-	// Create the bootstrap configuration for the Envoy proxy for the given pod
-	envoyBootstrapConfigName := bootstrapConfigName(proxyUUID)
+		if sidecarSettings, err = getSidecarSettingsForPod(pod, sidecarConfig); err != nil {
+			return nil, err
+		}
+		if proxyLifecycle, err = getProxyLifecycle(pod, meshConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	// Issue a certificate for the proxy sidecar - used for Envoy to connect to XDS (not Envoy-to-Envoy connections)
+	cnPrefix := models.NewXDSCertCNPrefix(proxyUUID, kind, identity.New(pod.Spec.ServiceAccountName, namespace))
+	log.Debug().Msgf("Patching POD spec: service-account=%s, namespace=%s with certificate CN prefix=%s", pod.Spec.ServiceAccountName, namespace, cnPrefix)
+
+	// The certificate is only used by the bootstrap config, which is not created for dry-run requests
+	var bootstrapCertificate *certificate.Certificate
+	if req.DryRun == nil || !*req.DryRun {
+		startTime := time.Now()
+		bootstrapCertificate, err = wh.certManager.IssueCertificate(certificate.ForCommonNamePrefix(cnPrefix))
+		if err != nil {
+			log.Error().Err(err).Msgf("Error issuing bootstrap certificate for Envoy with CN prefix=%s", cnPrefix)
+			return nil, err
+		}
+		elapsed := time.Since(startTime)
+
+		metricsstore.DefaultMetricsStore.CertIssuedCount.Inc()
+		metricsstore.DefaultMetricsStore.CertIssuedTime.
+			WithLabelValues().Observe(elapsed.Seconds())
+	}
+
+	if kind == models.KindProxylessGRPC {
+		return wh.createProxylessGRPCPatch(pod, req, proxyUUID, bootstrapCertificate)
+	}
 
 	// This needs to occur before replacing the label below.
 	originalUUID, alreadyInjected := getProxyUUID(pod)
+
+	// The health probes of already-injected pods were rewritten when they were injected
+	var originalHealthProbes map[string]models.HealthProbes
+	if !alreadyInjected {
+		originalHealthProbes = rewriteHealthProbes(pod)
+	}
+
+	// On Windows we cannot use init containers to program HNS because it requires elevated privileges
+	// As a result we assume that the HNS redirection policies are already programmed via a CNI plugin.
+	// Skip adding the init container and only patch the pod spec with sidecar container.
+	podOS := pod.Spec.NodeSelector["kubernetes.io/os"]
+	nativeSidecar := wh.useNativeSidecar(meshConfig, podOS)
+
+	// Create the bootstrap configuration for the Envoy proxy for the given pod
+	envoyBootstrapConfigName := bootstrapConfigName(proxyUUID)
+
 	switch {
 	case req.DryRun != nil && *req.DryRun:
 		// The webhook has a side effect (making out-of-band changes) of creating k8s secret
@@ -62,12 +112,18 @@ func (wh *mutatingWebhook) createPatch(pod *corev1.Pod, req *admissionv1.Admissi
 		// with the same UUID, so instead we change the UUID, and create a new bootstrap config, copied from the original,
 		// with the proxy UUID changed.
 		oldConfigName := bootstrapSecretPrefix + originalUUID
-		if _, err := wh.createEnvoyBootstrapFromExisting(proxyUUID, oldConfigName, namespace, bootstrapCertificate); err != nil {
+		_, err := wh.createEnvoyBootstrapFromExisting(proxyUUID, oldConfigName, namespace, bootstrapCertificate)
+		if apierrors.IsNotFound(err) {
+			// Pods injected offline, e.g. by `osm inject`, reference a bootstrap config that was never created
+			log.Debug().Msgf("Bootstrap config %s of already-injected pod not found, creating a new one: service-account=%s, namespace=%s", oldConfigName, pod.Spec.ServiceAccountName, namespace)
+			_, err = wh.createOfflineEnvoyBootstrapConfig(proxyUUID, pod, namespace, bootstrapCertificate)
+		}
+		if err != nil {
 			log.Error().Err(err).Msgf("Failed to create Envoy bootstrap config for already-injected pod: service-account=%s, namespace=%s, certificate CN prefix=%s", pod.Spec.ServiceAccountName, namespace, cnPrefix)
 			return nil, err
 		}
 	default:
-		if _, err = wh.createEnvoyBootstrapConfig(proxyUUID, namespace, bootstrapCertificate, originalHealthProbes); err != nil {
+		if _, err = wh.createEnvoyBootstrapConfig(proxyUUID, namespace, bootstrapCertificate, originalHealthProbes, nativeSidecar, sidecarSettings.statsTags); err != nil {
 			log.Error().Err(err).Msgf("Failed to create Envoy bootstrap config for pod: service-account=%s, namespace=%s, certificate CN prefix=%s", pod.Spec.ServiceAccountName, namespace, cnPrefix)
 			return nil, err
 		}
@@ -106,31 +162,37 @@ func (wh *mutatingWebhook) createPatch(pod *corev1.Pod, req *admissionv1.Admissi
 		return json.Marshal(makePatches(req, pod))
 	}
 
+	// The version of the injector is recorded, for the sidecars injected by other versions to be detected as stale
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[constants.InjectorVersionAnnotation] = version.Version
+
 	// Create volume for the envoy bootstrap config Secret
 	pod.Spec.Volumes = append(pod.Spec.Volumes, getVolumeSpec(envoyBootstrapConfigName))
 
-	// On Windows we cannot use init containers to program HNS because it requires elevated privileges
-	// As a result we assume that the HNS redirection policies are already programmed via a CNI plugin.
-	// Skip adding the init container and only patch the pod spec with sidecar container.
-	podOS := pod.Spec.NodeSelector["kubernetes.io/os"]
-	if err := wh.verifyPrerequisites(podOS); err != nil {
+	if err := wh.verifyPrerequisites(podOS, meshConfig); err != nil {
 		return nil, err
 	}
 
-	err = wh.configurePodInit(podOS, pod, namespace)
+	err = wh.configurePodInit(podOS, pod, namespace, meshConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	var usesTCP bool
+	// nativeSidecars are the names of the containers injected as native sidecar containers
+	var nativeSidecars []string
+
+	// TCPSocket and GRPC probes are served by the osm-healthcheck container
+	var usesHealthcheck bool
 	for _, probes := range originalHealthProbes {
-		if probes.UsesTCP() {
-			usesTCP = true
+		if probes.UsesTCP() || probes.UsesGRPC() {
+			usesHealthcheck = true
 			break
 		}
 	}
 
-	if usesTCP {
+	if usesHealthcheck {
 		healthcheckContainer := corev1.Container{
 			Name:            "osm-healthcheck",
 			Image:           os.Getenv("OSM_DEFAULT_HEALTHCHECK_CONTAINER_IMAGE"),
@@ -147,21 +209,47 @@ func (wh *mutatingWebhook) createPatch(pod *corev1.Pod, req *admissionv1.Admissi
 				},
 			},
 		}
-		pod.Spec.Containers = append(pod.Spec.Containers, healthcheckContainer)
+		if nativeSidecar {
+			pod.Spec.InitContainers = append(pod.Spec.InitContainers, healthcheckContainer)
+			nativeSidecars = append(nativeSidecars, healthcheckContainer.Name)
+		} else {
+			pod.Spec.Containers = append(pod.Spec.Containers, healthcheckContainer)
+		}
 	}
 
 	// Add the Envoy sidecar
-	sidecar := getEnvoySidecarContainerSpec(pod, namespace, wh.kubeController.GetMeshConfig(), originalHealthProbes, podOS)
-	pod.Spec.Containers = append(pod.Spec.Containers, sidecar)
+	sidecar := getEnvoySidecarContainerSpec(pod, namespace, meshConfig, sidecarSettings, originalHealthProbes, podOS)
+	if nativeSidecar {
+		// The native sidecar container is started after the init container redirecting the pod's traffic, and the
+		// application containers are started once its startup probe succeeds
+		sidecar.Ports = append(sidecar.Ports, corev1.ContainerPort{
+			Name:          envoyReadyPortName,
+			ContainerPort: constants.EnvoyReadyPort,
+		})
+		sidecar.StartupProbe = getEnvoyStartupProbe()
+	}
+	if err := wh.configureProxyLifecycle(pod, &sidecar, meshConfig, proxyLifecycle, nativeSidecar, podOS); err != nil {
+		return nil, err
+	}
+	switch {
+	case nativeSidecar:
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, sidecar)
+		nativeSidecars = append(nativeSidecars, sidecar.Name)
+	case sidecar.Lifecycle != nil && sidecar.Lifecycle.PostStart != nil:
+		// The application containers are started once the postStart hook of the proxy completed
+		setDefaultContainer(pod)
+		pod.Spec.Containers = append([]corev1.Container{sidecar}, pod.Spec.Containers...)
+	default:
+		pod.Spec.Containers = append(pod.Spec.Containers, sidecar)
+	}
 
-	return json.Marshal(makePatches(req, pod))
+	return json.Marshal(makePatches(req, pod, nativeSidecars...))
 }
 
 // verifyPrerequisites verifies if the prerequisites to patch the request are met by returning an error if unmet
-func (wh *mutatingWebhook) verifyPrerequisites(podOS string) error {
+func (wh *mutatingWebhook) verifyPrerequisites(podOS string, mc v1alpha2.MeshConfig) error {
 	isWindows := strings.EqualFold(podOS, constants.OSWindows)
 
-	mc := wh.kubeController.GetMeshConfig()
 	// Verify that the required images are configured
 	if image := utils.GetEnvoyImage(mc); !isWindows && image == "" {
 		// Linux pods require Envoy Linux image
@@ -171,67 +259,62 @@ func (wh *mutatingWebhook) verifyPrerequisites(podOS string) error {
 		// Windows pods require Envoy Windows image
 		return fmt.Errorf("MeshConfig sidecar.envoyWindowsImage not set")
 	}
-	if image := utils.GetInitContainerImage(mc); !isWindows && image == "" {
-		// Linux pods require init container image
+	if image := utils.GetInitContainerImage(mc); !isWindows && !wh.enableCNI && image == "" {
+		// Linux pods require init container image, unless their traffic is redirected by the OSM CNI plugin
 		return fmt.Errorf("MeshConfig sidecar.initContainerImage not set")
 	}
+	if !isWindows && wh.enableCNI && wh.cniValidationImage == "" {
+		// Linux pods whose traffic is redirected by the OSM CNI plugin require the CNI validation image
+		return fmt.Errorf("OSM CNI validation image not set")
+	}
 
 	return nil
 }
 
-func (wh *mutatingWebhook) configurePodInit(podOS string, pod *corev1.Pod, namespace string) error {
+func (wh *mutatingWebhook) configurePodInit(podOS string, pod *corev1.Pod, namespace string, meshConfig v1alpha2.MeshConfig) error {
 	if strings.EqualFold(podOS, constants.OSWindows) {
 		// No init container for Windows
 		return nil
 	}
 
-	// Build outbound port exclusion list
-	podOutboundPortExclusionList, err := getPortExclusionListForPod(pod, namespace, outboundPortExclusionListAnnotation)
-	if err != nil {
-		return err
-	}
-	globalOutboundPortExclusionList := wh.kubeController.GetMeshConfig().Spec.Traffic.OutboundPortExclusionList
-	outboundPortExclusionList := mergePortExclusionLists(podOutboundPortExclusionList, globalOutboundPortExclusionList)
-
-	// Build inbound port exclusion list
-	podInboundPortExclusionList, err := getPortExclusionListForPod(pod, namespace, inboundPortExclusionListAnnotation)
+	lists, err := getInterceptionLists(pod, namespace, meshConfig)
 	if err != nil {
 		return err
 	}
-	globalInboundPortExclusionList := wh.kubeController.GetMeshConfig().Spec.Traffic.InboundPortExclusionList
-	inboundPortExclusionList := mergePortExclusionLists(podInboundPortExclusionList, globalInboundPortExclusionList)
 
-	// Build the outbound IP range exclusion list
-	podOutboundIPRangeExclusionList, err := getOutboundIPRangeListForPod(pod, namespace, outboundIPRangeExclusionListAnnotation)
-	if err != nil {
-		return err
+	if wh.enableCNI {
+		// Traffic is redirected to the sidecar by the OSM CNI plugin when the pod's network is set up, which the
+		// validation container verifies before the other containers are started
+		validationContainer, err := getCNIValidationContainerSpec(wh.cniValidationImage, wh.osmContainerPullPolicy, lists)
+		if err != nil {
+			return err
+		}
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, validationContainer)
+		return nil
 	}
-	globalOutboundIPRangeExclusionList := wh.kubeController.GetMeshConfig().Spec.Traffic.OutboundIPRangeExclusionList
-	outboundIPRangeExclusionList := mergeIPRangeLists(podOutboundIPRangeExclusionList, globalOutboundIPRangeExclusionList)
-
-	// Build the outbound IP range inclusion list
-	podOutboundIPRangeInclusionList, err := getOutboundIPRangeListForPod(pod, namespace, outboundIPRangeInclusionListAnnotation)
+	backend, err := getTrafficRedirectionBackend(pod, meshConfig)
 	if err != nil {
 		return err
 	}
-	globalOutboundIPRangeInclusionList := wh.kubeController.GetMeshConfig().Spec.Traffic.OutboundIPRangeInclusionList
-	outboundIPRangeInclusionList := mergeIPRangeLists(podOutboundIPRangeInclusionList, globalOutboundIPRangeInclusionList)
-
-	networkInterfaceExclusionList := wh.kubeController.GetMeshConfig().Spec.Traffic.NetworkInterfaceExclusionList
 
 	// Add the init container to the pod spec
-	initContainer := getInitContainerSpec(constants.InitContainerName, wh.kubeController.GetMeshConfig(), outboundIPRangeExclusionList, outboundIPRangeInclusionList, outboundPortExclusionList, inboundPortExclusionList, wh.kubeController.GetMeshConfig().Spec.Sidecar.EnablePrivilegedInitContainer, wh.osmContainerPullPolicy, networkInterfaceExclusionList)
+	initContainer := getInitContainerSpec(constants.InitContainerName, meshConfig, lists.outboundIPRangeExclusionList, lists.outboundIPRangeInclusionList, lists.outboundPortExclusionList, lists.inboundPortExclusionList, meshConfig.Spec.Sidecar.EnablePrivilegedInitContainer, wh.osmContainerPullPolicy, lists.networkInterfaceExclusionList, backend)
 	pod.Spec.InitContainers = append(pod.Spec.InitContainers, initContainer)
 
 	return nil
 }
 
-func makePatches(req *admissionv1.AdmissionRequest, pod *corev1.Pod) []jsonpatch.JsonPatchOperation {
+func makePatches(req *admissionv1.AdmissionRequest, pod *corev1.Pod, nativeSidecars ...string) []jsonpatch.JsonPatchOperation {
 	original := req.Object.Raw
 	current, err := json.Marshal(pod)
 	if err != nil {
 		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingKubernetesResource)).
 			Msgf("Error marshaling Pod with UID=%s", pod.ObjectMeta.UID)
+	} else if withRestartPolicies, err := setInitContainerRestartPolicies(original, current, nativeSidecars); err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingKubernetesResource)).
+			Msgf("Error setting the restart policy of the init containers of Pod with UID=%s", pod.ObjectMeta.UID)
+	} else {
+		current = withRestartPolicies
 	}
 	admissionResponse := admission.PatchResponseFromRaw(original, current)
 	return admissionResponse.Patches
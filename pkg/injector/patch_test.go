@@ -16,12 +16,16 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
 
 	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
 	tresorFake "github.com/openservicemesh/osm/pkg/certificate/providers/tresor/fake"
 	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy/bootstrap"
 	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/models"
 	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/version"
 )
 
 func TestCreatePatch(t *testing.T) {
@@ -36,9 +40,16 @@ func TestCreatePatch(t *testing.T) {
 	testCases := []struct {
 		name            string
 		os              string
+		containerMode   v1alpha2.SidecarContainerMode
+		holdApplication bool
+		drain           bool
+		profiles        []*v1alpha2.SidecarProfile
+		podAnnotations  map[string]string
 		namespace       *corev1.Namespace
 		dryRun          bool
 		expectedPatches []string
+		// expectedBootstrap are substrings of the bootstrap config created when not in dry run
+		expectedBootstrap []string
 	}{
 		{
 			name: "creates a patch for a unix worker",
@@ -63,6 +74,114 @@ func TestCreatePatch(t *testing.T) {
 				`"command":["envoy"]`,
 			},
 		},
+		{
+			name:          "creates a patch with a native sidecar container",
+			os:            constants.OSLinux,
+			containerMode: v1alpha2.SidecarContainerModeNative,
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: namespace,
+				},
+			},
+			expectedPatches: []string{
+				// Add Envoy UID Label
+				`"path":"/metadata/labels"`,
+				fmt.Sprintf(`"value":{"osm-proxy-uuid":"%v"`, proxyUUID),
+				// Add Init Container followed by the Envoy native sidecar container
+				`"path":"/spec/initContainers"`,
+				`"command":["/bin/sh"]`,
+				`"command":["envoy"]`,
+				`"restartPolicy":"Always"`,
+				`"httpGet":{"path":"/ready","port":15905,"scheme":"HTTP"}`,
+			},
+		},
+		{
+			name:            "creates a patch holding the application and draining the proxy",
+			os:              constants.OSLinux,
+			containerMode:   v1alpha2.SidecarContainerModeRegular,
+			holdApplication: true,
+			drain:           true,
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: namespace,
+				},
+			},
+			expectedPatches: []string{
+				// Add the proxy utilities volume and init container
+				`{"emptyDir":{},"name":"osm-proxy-utils"}`,
+				`"command":["cp","/bin/busybox.static","/osm-proxy-utils/busybox"]`,
+				// Add the Envoy Container hooks
+				`"command":["envoy"]`,
+				`"postStart":{"exec":{"command":["/osm-proxy-utils/busybox","sh","-c"`,
+				`"preStop":{"exec":{"command":["/osm-proxy-utils/busybox","sh","-c"`,
+				// Raise the termination grace period to cover the draining
+				`{"op":"add","path":"/spec/terminationGracePeriodSeconds","value":45}`,
+			},
+		},
+		{
+			name: "creates a patch with the settings of the sidecar profile",
+			os:   constants.OSLinux,
+			profiles: []*v1alpha2.SidecarProfile{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "debug",
+						Namespace: namespace,
+					},
+					Spec: v1alpha2.SidecarProfileSpec{
+						Sidecar:     &runtime.RawExtension{Raw: []byte(`{"logLevel":"debug"}`)},
+						Concurrency: pointer.Int32(2),
+					},
+				},
+			},
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: namespace,
+				},
+			},
+			expectedPatches: []string{
+				// Add Envoy Container with the profile's log level and concurrency
+				`"path":"/spec/containers"`,
+				`"args":["--log-level","debug",`,
+				`"--concurrency","2"]`,
+			},
+		},
+		{
+			name: "creates a patch with the settings of the pod annotations",
+			os:   constants.OSLinux,
+			profiles: []*v1alpha2.SidecarProfile{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "debug",
+						Namespace: namespace,
+					},
+					Spec: v1alpha2.SidecarProfileSpec{
+						Concurrency: pointer.Int32(2),
+					},
+				},
+			},
+			podAnnotations: map[string]string{
+				"openservicemesh.io/sidecar-cpu-request":         "100m",
+				"openservicemesh.io/sidecar-memory-limit":        "256Mi",
+				"openservicemesh.io/sidecar-concurrency":         "4",
+				"openservicemesh.io/sidecar-component-log-level": "upstream:debug,connection:trace",
+				"openservicemesh.io/sidecar-stats-tags":          "team=payments",
+			},
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: namespace,
+				},
+			},
+			expectedPatches: []string{
+				// Add Envoy Container with the pod's resources, concurrency and component log levels
+				`"path":"/spec/containers"`,
+				`"--concurrency","4","--component-log-level","upstream:debug,connection:trace"]`,
+				`"resources":{"limits":{"memory":"256Mi"},"requests":{"cpu":"100m"}}`,
+			},
+			expectedBootstrap: []string{
+				"tag_name: team",
+				"fixed_value: payments",
+			},
+		},
 		{
 			name: "creates a patch for a windows worker",
 			os:   constants.OSWindows,
@@ -96,9 +215,9 @@ func TestCreatePatch(t *testing.T) {
 				// Add Envoy UID Label
 				`"path":"/metadata/labels"`,
 				fmt.Sprintf(`"value":{"osm-proxy-uuid":"%v"`, proxyUUID),
-				// Add metrics Annotations
+				// Add injector version and metrics Annotations
 				`"path":"/metadata/annotations"`,
-				`"value":{"prometheus.io/path":"/stats/prometheus","prometheus.io/port":"15010","prometheus.io/scrape":"true"}`,
+				fmt.Sprintf(`"value":{"openservicemesh.io/injector-version":%q,"prometheus.io/path":"/stats/prometheus","prometheus.io/port":"15010","prometheus.io/scrape":"true"}`, version.Version),
 				// Add Volumes
 				`"path":"/spec/volumes"`,
 				fmt.Sprintf(`"value":[{"name":"envoy-bootstrap-config-volume","secret":{"secretName":"envoy-bootstrap-config-%v"}}]}`, proxyUUID),
@@ -140,18 +259,28 @@ func TestCreatePatch(t *testing.T) {
 				nonInjectNamespaces: mapset.NewSet(),
 			}
 
-			mockNsController.EXPECT().GetMeshConfig().Return(v1alpha2.MeshConfig{
+			meshConfig := v1alpha2.MeshConfig{
 				Spec: v1alpha2.MeshConfigSpec{
 					Sidecar: v1alpha2.SidecarSpec{
-						EnvoyWindowsImage:  "envoy-linux-image",
-						EnvoyImage:         "envoy-windows-image",
-						InitContainerImage: "init-container-image",
-						Resources:          corev1.ResourceRequirements{},
+						EnvoyWindowsImage:               "envoy-linux-image",
+						EnvoyImage:                      "envoy-windows-image",
+						InitContainerImage:              "init-container-image",
+						Resources:                       corev1.ResourceRequirements{},
+						ContainerMode:                   tc.containerMode,
+						HoldApplicationUntilProxyStarts: tc.holdApplication,
+						Drain: v1alpha2.ProxyDrainSpec{
+							Enable: tc.drain,
+						},
 					},
 				},
+			}
+			mockNsController.EXPECT().GetMeshConfig().Return(meshConfig).AnyTimes()
+			mockNsController.EXPECT().GetSidecarConfig(namespace, gomock.Any()).DoAndReturn(func(_ string, podLabels map[string]string) (models.SidecarConfig, error) {
+				return k8s.ResolveSidecarConfig(meshConfig, tc.profiles, tests.OsmNamespace, tc.namespace, podLabels)
 			}).AnyTimes()
 
 			pod := tests.NewOsSpecificPodFixture(namespace, podName, tests.BookstoreServiceAccountName, nil, tc.os)
+			pod.Annotations = tc.podAnnotations
 
 			raw, err := json.Marshal(pod)
 			assert.NoError(err)
@@ -177,6 +306,9 @@ func TestCreatePatch(t *testing.T) {
 			} else {
 				assert.NoError(err)
 				assert.NotNil(conf)
+				for _, expected := range tc.expectedBootstrap {
+					assert.Contains(string(conf.Data[bootstrap.EnvoyBootstrapConfigFile]), expected)
+				}
 			}
 
 			// Now we try to reinject, and ensure the only patch is the updated UUID. We also verify the config was
@@ -243,6 +375,7 @@ func TestCreatePatch(t *testing.T) {
 			},
 		}).AnyTimes()
 		mockNsController.EXPECT().GetMeshConfig().Return(v1alpha2.MeshConfig{}).AnyTimes()
+		mockNsController.EXPECT().GetSidecarConfig(namespace, gomock.Any()).Return(models.SidecarConfig{}, nil).AnyTimes()
 
 		pod := tests.NewOsSpecificPodFixture(namespace, podName, tests.BookstoreServiceAccountName, nil, constants.OSLinux)
 
@@ -265,6 +398,8 @@ func TestVerifyPrerequisites(t *testing.T) {
 		linuxImage   string
 		windowsImage string
 		initImage    string
+		enableCNI    bool
+		cniImage     string
 		expectErr    bool
 	}{
 		{
@@ -278,6 +413,20 @@ func TestVerifyPrerequisites(t *testing.T) {
 			linuxImage: "envoy",
 			expectErr:  true,
 		},
+		{
+			name:       "prereqs met for linux pod when init container image is missing with the CNI plugin",
+			linuxImage: "envoy",
+			enableCNI:  true,
+			cniImage:   "osm-cni",
+			expectErr:  false,
+		},
+		{
+			name:       "prereqs not met for linux pod when CNI validation image is missing with the CNI plugin",
+			linuxImage: "envoy",
+			initImage:  "init",
+			enableCNI:  true,
+			expectErr:  true,
+		},
 		{
 			name:      "prereqs not met for linux pod when envoy container image is missing",
 			initImage: "init",
@@ -305,17 +454,14 @@ func TestVerifyPrerequisites(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			mockCtrl := gomock.NewController(t)
-			defer mockCtrl.Finish()
-
 			assert := tassert.New(t)
-			mockK8s := k8s.NewMockController(mockCtrl)
 
 			wh := &mutatingWebhook{
-				kubeController: mockK8s,
+				enableCNI:          tc.enableCNI,
+				cniValidationImage: tc.cniImage,
 			}
 
-			mockK8s.EXPECT().GetMeshConfig().Return(v1alpha2.MeshConfig{
+			meshConfig := v1alpha2.MeshConfig{
 				Spec: v1alpha2.MeshConfigSpec{
 					Sidecar: v1alpha2.SidecarSpec{
 						EnvoyWindowsImage:  tc.windowsImage,
@@ -323,9 +469,9 @@ func TestVerifyPrerequisites(t *testing.T) {
 						InitContainerImage: tc.initImage,
 					},
 				},
-			}).AnyTimes()
+			}
 
-			err := wh.verifyPrerequisites(tc.podOS)
+			err := wh.verifyPrerequisites(tc.podOS, meshConfig)
 			assert.Equal(tc.expectErr, err != nil)
 		})
 	}
@@ -0,0 +1,268 @@
+package injector
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/utils"
+)
+
+const (
+	// holdApplicationUntilProxyStartsAnnotation is the annotation used to hold the start of the application containers
+	// of a pod until its proxy received its initial configuration
+	holdApplicationUntilProxyStartsAnnotation = "openservicemesh.io/hold-application-until-proxy-starts"
+
+	// proxyDrainAnnotation is the annotation used to drain the connections of the proxy before it is stopped
+	proxyDrainAnnotation = "openservicemesh.io/proxy-drain"
+
+	// proxyDrainTimeoutAnnotation is the annotation used for the maximum duration of the draining of the proxy
+	proxyDrainTimeoutAnnotation = "openservicemesh.io/proxy-drain-timeout"
+
+	// proxyDrainMaxActiveConnectionsAnnotation is the annotation used for the number of active connections of the
+	// proxy at or below which it is stopped
+	proxyDrainMaxActiveConnectionsAnnotation = "openservicemesh.io/proxy-drain-max-active-connections"
+
+	// defaultContainerAnnotation is the annotation used by kubectl to select the default container of a pod
+	defaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+
+	// proxyUtilsContainerName is the name of the init container installing the utilities run by the proxy's hooks
+	proxyUtilsContainerName = "osm-proxy-utils"
+
+	// proxyUtilsVolume is the name of the volume holding the utilities run by the proxy's hooks
+	proxyUtilsVolume = "osm-proxy-utils"
+
+	// proxyUtilsPath is the path the utilities run by the proxy's hooks are mounted at. The Envoy image is distroless,
+	// so the hooks are run by a statically linked busybox installed from the init container image.
+	proxyUtilsPath = "/osm-proxy-utils"
+
+	// proxyUtilsBusybox is the busybox binary run by the proxy's hooks
+	proxyUtilsBusybox = proxyUtilsPath + "/busybox"
+
+	// initContainerImageBusybox is the statically linked busybox binary of the init container image
+	initContainerImageBusybox = "/bin/busybox.static"
+
+	// defaultProxyDrainTimeout is the default maximum duration of the draining of the proxy
+	defaultProxyDrainTimeout = 30 * time.Second
+
+	// proxyDrainExitPeriod is the time left to the proxy and the application containers to exit once the draining of
+	// the proxy completed, within the termination grace period of the pod
+	proxyDrainExitPeriod = 15 * time.Second
+)
+
+// proxyLifecycle is the configuration of the start and stop of the proxy sidecar of a pod
+type proxyLifecycle struct {
+	// holdApplication indicates whether the application containers are started once the proxy is ready
+	holdApplication bool
+
+	// drain indicates whether the connections of the proxy are drained before it is stopped
+	drain bool
+
+	// drainTimeout is the maximum duration of the draining of the proxy
+	drainTimeout time.Duration
+
+	// drainMaxActiveConnections is the number of active connections of the proxy at or below which it is stopped
+	drainMaxActiveConnections int
+}
+
+// terminationGracePeriodSeconds returns the minimum termination grace period of the pod, in seconds, for the proxy to
+// be drained and the application containers to exit before they are killed
+func (l proxyLifecycle) terminationGracePeriodSeconds() int64 {
+	return int64(math.Ceil((l.drainTimeout + proxyDrainExitPeriod).Seconds()))
+}
+
+// usesProxyUtils returns whether the hooks of the proxy run the proxy utilities
+func (l proxyLifecycle) usesProxyUtils(nativeSidecar bool) bool {
+	return (l.holdApplication && !nativeSidecar) || l.drain
+}
+
+// getProxyLifecycle returns the configuration of the start and stop of the proxy sidecar of the given pod. The pod's
+// annotations override the MeshConfig.
+func getProxyLifecycle(pod *corev1.Pod, meshConfig v1alpha2.MeshConfig) (proxyLifecycle, error) {
+	lifecycle := proxyLifecycle{
+		holdApplication:           meshConfig.Spec.Sidecar.HoldApplicationUntilProxyStarts,
+		drain:                     meshConfig.Spec.Sidecar.Drain.Enable,
+		drainTimeout:              defaultProxyDrainTimeout,
+		drainMaxActiveConnections: meshConfig.Spec.Sidecar.Drain.MaxActiveConnections,
+	}
+
+	var err error
+	if timeout := meshConfig.Spec.Sidecar.Drain.Timeout; timeout != "" {
+		if lifecycle.drainTimeout, err = parseProxyDrainTimeout(timeout); err != nil {
+			return proxyLifecycle{}, fmt.Errorf("Invalid MeshConfig sidecar.drain.timeout: %w", err)
+		}
+	}
+
+	if value, ok := pod.Annotations[holdApplicationUntilProxyStartsAnnotation]; ok {
+		if lifecycle.holdApplication, err = parseBoolAnnotation(holdApplicationUntilProxyStartsAnnotation, value); err != nil {
+			return proxyLifecycle{}, err
+		}
+	}
+	if value, ok := pod.Annotations[proxyDrainAnnotation]; ok {
+		if lifecycle.drain, err = parseBoolAnnotation(proxyDrainAnnotation, value); err != nil {
+			return proxyLifecycle{}, err
+		}
+	}
+	if value, ok := pod.Annotations[proxyDrainTimeoutAnnotation]; ok {
+		if lifecycle.drainTimeout, err = parseProxyDrainTimeout(value); err != nil {
+			return proxyLifecycle{}, fmt.Errorf("Invalid annotation value for key %q: %w", proxyDrainTimeoutAnnotation, err)
+		}
+	}
+	if value, ok := pod.Annotations[proxyDrainMaxActiveConnectionsAnnotation]; ok {
+		maxActiveConnections, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || maxActiveConnections < 0 {
+			return proxyLifecycle{}, fmt.Errorf("Invalid annotation value for key %q: %s", proxyDrainMaxActiveConnectionsAnnotation, value)
+		}
+		lifecycle.drainMaxActiveConnections = maxActiveConnections
+	}
+
+	return lifecycle, nil
+}
+
+// parseBoolAnnotation returns the boolean value of the given annotation
+func parseBoolAnnotation(annotation string, value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "enabled", "yes", "true":
+		return true, nil
+	case "disabled", "no", "false":
+		return false, nil
+	default:
+		return false, fmt.Errorf("Invalid annotation value for key %q: %s", annotation, value)
+	}
+}
+
+// parseProxyDrainTimeout returns the given maximum duration of the draining of the proxy
+func parseProxyDrainTimeout(value string) (time.Duration, error) {
+	timeout, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return 0, err
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("duration %s is not positive", value)
+	}
+	return timeout, nil
+}
+
+// configureProxyLifecycle configures the hooks of the given proxy container holding the start of the application
+// containers of the pod and draining the connections of the proxy, and installs the utilities they run.
+// When the start of the application containers is held and the proxy is a regular container, it is moved to be the
+// first container of the pod: the kubelet starts the containers in order, and starts the next container only once
+// the postStart hook of the previous one completed.
+// When the proxy is drained, the termination grace period of the pod is raised to cover the draining and leave time to
+// the containers to exit afterwards.
+func (wh *mutatingWebhook) configureProxyLifecycle(pod *corev1.Pod, sidecar *corev1.Container, meshConfig v1alpha2.MeshConfig, lifecycle proxyLifecycle, nativeSidecar bool, podOS string) error {
+	if strings.EqualFold(podOS, constants.OSWindows) || !lifecycle.usesProxyUtils(nativeSidecar) {
+		// The proxy utilities are not available on Windows
+		return nil
+	}
+
+	if utils.GetInitContainerImage(meshConfig) == "" {
+		return fmt.Errorf("MeshConfig sidecar.initContainerImage not set")
+	}
+
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name: proxyUtilsVolume,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+	pod.Spec.InitContainers = append(pod.Spec.InitContainers, getProxyUtilsContainerSpec(meshConfig, wh.osmContainerPullPolicy))
+	sidecar.VolumeMounts = append(sidecar.VolumeMounts, corev1.VolumeMount{
+		Name:      proxyUtilsVolume,
+		ReadOnly:  true,
+		MountPath: proxyUtilsPath,
+	})
+
+	sidecar.Lifecycle = &corev1.Lifecycle{}
+	if lifecycle.holdApplication && !nativeSidecar {
+		sidecar.Lifecycle.PostStart = getProxyStartHook()
+	}
+	if lifecycle.drain {
+		sidecar.Lifecycle.PreStop = getProxyDrainHook(lifecycle.drainTimeout, lifecycle.drainMaxActiveConnections)
+
+		// The preStop hook runs within the termination grace period of the pod, after which the containers are killed
+		if gracePeriod := lifecycle.terminationGracePeriodSeconds(); pod.Spec.TerminationGracePeriodSeconds == nil || *pod.Spec.TerminationGracePeriodSeconds < gracePeriod {
+			log.Debug().Msgf("Raising the termination grace period of pod %s/%s to %ds to drain its proxy", pod.Namespace, pod.Name, gracePeriod)
+			pod.Spec.TerminationGracePeriodSeconds = pointer.Int64(gracePeriod)
+		}
+	}
+
+	return nil
+}
+
+// getProxyUtilsContainerSpec returns the init container installing the utilities run by the proxy's hooks
+func getProxyUtilsContainerSpec(meshConfig v1alpha2.MeshConfig, pullPolicy corev1.PullPolicy) corev1.Container {
+	return corev1.Container{
+		Name:            proxyUtilsContainerName,
+		Image:           utils.GetInitContainerImage(meshConfig),
+		ImagePullPolicy: pullPolicy,
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: pointer.BoolPtr(false),
+			RunAsUser:                pointer.Int64Ptr(constants.EnvoyUID),
+		},
+		Command: []string{"cp", initContainerImageBusybox, proxyUtilsBusybox},
+		VolumeMounts: []corev1.VolumeMount{{
+			Name:      proxyUtilsVolume,
+			MountPath: proxyUtilsPath,
+		}},
+	}
+}
+
+// getProxyStartHook returns the postStart hook of the proxy waiting until it received its initial configuration.
+// The hook fails, and the proxy is restarted, when the proxy isn't ready after as long as the startup probe of native
+// sidecar containers waits for it.
+func getProxyStartHook() *corev1.LifecycleHandler {
+	script := fmt.Sprintf(`for i in $(%[1]s seq %[2]d); do
+  %[1]s wget -q -O /dev/null http://%[3]s:%[4]d%[5]s && exit 0
+  %[1]s sleep 1
+done
+exit 1`, proxyUtilsBusybox, envoyStartupProbeFailureThreshold, constants.LocalhostIPAddress, constants.EnvoyAdminPort, constants.EnvoyReadyPath)
+
+	return &corev1.LifecycleHandler{
+		Exec: &corev1.ExecAction{
+			Command: []string{proxyUtilsBusybox, "sh", "-c", script},
+		},
+	}
+}
+
+// getProxyDrainHook returns the preStop hook of the proxy failing its health checks and draining its listeners, and
+// waiting until its active downstream connections dropped to the given number or the given timeout elapsed. The
+// kubelet stops the proxy once the hook completed.
+func getProxyDrainHook(timeout time.Duration, maxActiveConnections int) *corev1.LifecycleHandler {
+	admin := fmt.Sprintf("http://%s:%d", constants.LocalhostIPAddress, constants.EnvoyAdminPort)
+	script := fmt.Sprintf(`%[1]s wget -q -O /dev/null --post-data '' %[2]s/healthcheck/fail
+%[1]s wget -q -O /dev/null --post-data '' '%[2]s/drain_listeners?graceful'
+for i in $(%[1]s seq %[3]d); do
+  active=$(%[1]s wget -q -O - '%[2]s/stats?filter=^server\.total_connections$' | %[1]s awk '{print $2}')
+  [ "${active:-0}" -le %[4]d ] && exit 0
+  %[1]s sleep 1
+done`, proxyUtilsBusybox, admin, int(math.Ceil(timeout.Seconds())), maxActiveConnections)
+
+	return &corev1.LifecycleHandler{
+		Exec: &corev1.ExecAction{
+			Command: []string{proxyUtilsBusybox, "sh", "-c", script},
+		},
+	}
+}
+
+// setDefaultContainer sets the default container of the given pod selected by kubectl to its first application
+// container, unless already set, as the proxy is moved to be the first container of the pod
+func setDefaultContainer(pod *corev1.Pod) {
+	if len(pod.Spec.Containers) == 0 {
+		return
+	}
+	if _, ok := pod.Annotations[defaultContainerAnnotation]; ok {
+		return
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[defaultContainerAnnotation] = pod.Spec.Containers[0].Name
+}
@@ -0,0 +1,302 @@
+package injector
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func TestGetProxyLifecycle(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		sidecar     v1alpha2.SidecarSpec
+		expected    proxyLifecycle
+		expectErr   bool
+	}{
+		{
+			name: "defaults",
+			expected: proxyLifecycle{
+				drainTimeout: defaultProxyDrainTimeout,
+			},
+		},
+		{
+			name: "MeshConfig",
+			sidecar: v1alpha2.SidecarSpec{
+				HoldApplicationUntilProxyStarts: true,
+				Drain: v1alpha2.ProxyDrainSpec{
+					Enable:               true,
+					Timeout:              "1m",
+					MaxActiveConnections: 2,
+				},
+			},
+			expected: proxyLifecycle{
+				holdApplication:           true,
+				drain:                     true,
+				drainTimeout:              time.Minute,
+				drainMaxActiveConnections: 2,
+			},
+		},
+		{
+			name: "annotations override MeshConfig",
+			annotations: map[string]string{
+				holdApplicationUntilProxyStartsAnnotation: "false",
+				proxyDrainAnnotation:                      "enabled",
+				proxyDrainTimeoutAnnotation:               "45s",
+				proxyDrainMaxActiveConnectionsAnnotation:  "5",
+			},
+			sidecar: v1alpha2.SidecarSpec{
+				HoldApplicationUntilProxyStarts: true,
+				Drain: v1alpha2.ProxyDrainSpec{
+					Timeout: "1m",
+				},
+			},
+			expected: proxyLifecycle{
+				drain:                     true,
+				drainTimeout:              45 * time.Second,
+				drainMaxActiveConnections: 5,
+			},
+		},
+		{
+			name: "invalid hold annotation",
+			annotations: map[string]string{
+				holdApplicationUntilProxyStartsAnnotation: "maybe",
+			},
+			expectErr: true,
+		},
+		{
+			name: "invalid drain timeout annotation",
+			annotations: map[string]string{
+				proxyDrainTimeoutAnnotation: "-1s",
+			},
+			expectErr: true,
+		},
+		{
+			name: "invalid drain max active connections annotation",
+			annotations: map[string]string{
+				proxyDrainMaxActiveConnectionsAnnotation: "-1",
+			},
+			expectErr: true,
+		},
+		{
+			name: "invalid MeshConfig drain timeout",
+			sidecar: v1alpha2.SidecarSpec{
+				Drain: v1alpha2.ProxyDrainSpec{
+					Timeout: "soon",
+				},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			meshConfig := v1alpha2.MeshConfig{Spec: v1alpha2.MeshConfigSpec{Sidecar: tc.sidecar}}
+
+			actual, err := getProxyLifecycle(pod, meshConfig)
+			assert.Equal(tc.expectErr, err != nil)
+			assert.Equal(tc.expected, actual)
+		})
+	}
+}
+
+func TestConfigureProxyLifecycle(t *testing.T) {
+	testCases := []struct {
+		name               string
+		lifecycle          proxyLifecycle
+		nativeSidecar      bool
+		podOS              string
+		initContainerImage string
+		expectedPostStart  bool
+		expectedPreStop    bool
+		gracePeriod        *int64
+		expectGracePeriod  *int64
+		expectErr          bool
+	}{
+		{
+			name:               "neither held nor drained",
+			podOS:              constants.OSLinux,
+			initContainerImage: "init-container-image",
+		},
+		{
+			name:               "held regular sidecar",
+			lifecycle:          proxyLifecycle{holdApplication: true},
+			podOS:              constants.OSLinux,
+			initContainerImage: "init-container-image",
+			expectedPostStart:  true,
+		},
+		{
+			name:               "held native sidecar",
+			lifecycle:          proxyLifecycle{holdApplication: true},
+			nativeSidecar:      true,
+			podOS:              constants.OSLinux,
+			initContainerImage: "init-container-image",
+		},
+		{
+			name:               "drained native sidecar",
+			lifecycle:          proxyLifecycle{drain: true, drainTimeout: time.Minute},
+			nativeSidecar:      true,
+			podOS:              constants.OSLinux,
+			initContainerImage: "init-container-image",
+			expectedPreStop:    true,
+			expectGracePeriod:  pointer.Int64(75),
+		},
+		{
+			name:               "drained sidecar with the default termination grace period",
+			lifecycle:          proxyLifecycle{drain: true, drainTimeout: defaultProxyDrainTimeout},
+			podOS:              constants.OSLinux,
+			initContainerImage: "init-container-image",
+			expectedPreStop:    true,
+			gracePeriod:        pointer.Int64(30),
+			expectGracePeriod:  pointer.Int64(45),
+		},
+		{
+			name:               "drained sidecar with a longer termination grace period",
+			lifecycle:          proxyLifecycle{drain: true, drainTimeout: 1500 * time.Millisecond},
+			podOS:              constants.OSLinux,
+			initContainerImage: "init-container-image",
+			expectedPreStop:    true,
+			gracePeriod:        pointer.Int64(120),
+			expectGracePeriod:  pointer.Int64(120),
+		},
+		{
+			name:               "held and drained windows sidecar",
+			lifecycle:          proxyLifecycle{holdApplication: true, drain: true},
+			podOS:              constants.OSWindows,
+			initContainerImage: "init-container-image",
+		},
+		{
+			name:      "init container image not set",
+			lifecycle: proxyLifecycle{drain: true},
+			podOS:     constants.OSLinux,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			meshConfig := v1alpha2.MeshConfig{
+				Spec: v1alpha2.MeshConfigSpec{
+					Sidecar: v1alpha2.SidecarSpec{
+						InitContainerImage: tc.initContainerImage,
+					},
+				},
+			}
+			wh := &mutatingWebhook{}
+
+			pod := &corev1.Pod{Spec: corev1.PodSpec{TerminationGracePeriodSeconds: tc.gracePeriod}}
+			sidecar := corev1.Container{Name: constants.EnvoyContainerName}
+			err := wh.configureProxyLifecycle(pod, &sidecar, meshConfig, tc.lifecycle, tc.nativeSidecar, tc.podOS)
+			assert.Equal(tc.expectErr, err != nil)
+			assert.Equal(tc.expectGracePeriod, pod.Spec.TerminationGracePeriodSeconds)
+
+			if !tc.expectedPostStart && !tc.expectedPreStop {
+				assert.Nil(sidecar.Lifecycle)
+				assert.Empty(pod.Spec.InitContainers)
+				assert.Empty(pod.Spec.Volumes)
+				return
+			}
+
+			assert.Equal(tc.expectedPostStart, sidecar.Lifecycle.PostStart != nil)
+			assert.Equal(tc.expectedPreStop, sidecar.Lifecycle.PreStop != nil)
+			assert.Len(pod.Spec.InitContainers, 1)
+			assert.Equal(proxyUtilsContainerName, pod.Spec.InitContainers[0].Name)
+			assert.Equal("init-container-image", pod.Spec.InitContainers[0].Image)
+			assert.Len(pod.Spec.Volumes, 1)
+			assert.Equal(proxyUtilsVolume, pod.Spec.Volumes[0].Name)
+			assert.Contains(sidecar.VolumeMounts, corev1.VolumeMount{Name: proxyUtilsVolume, ReadOnly: true, MountPath: proxyUtilsPath})
+		})
+	}
+}
+
+func TestGetProxyDrainHook(t *testing.T) {
+	assert := tassert.New(t)
+
+	hook := getProxyDrainHook(1500*time.Millisecond, 3)
+
+	assert.Equal([]string{proxyUtilsBusybox, "sh", "-c"}, hook.Exec.Command[:3])
+	script := hook.Exec.Command[3]
+	assert.True(strings.HasPrefix(script, proxyUtilsBusybox+" wget -q -O /dev/null --post-data '' http://127.0.0.1:15000/healthcheck/fail\n"))
+	assert.Contains(script, "'http://127.0.0.1:15000/drain_listeners?graceful'")
+	// The timeout is rounded up to whole seconds
+	assert.Contains(script, "seq 2)")
+	assert.Contains(script, `[ "${active:-0}" -le 3 ] && exit 0`)
+}
+
+func TestSetDefaultContainer(t *testing.T) {
+	testCases := []struct {
+		name     string
+		pod      *corev1.Pod
+		expected map[string]string
+	}{
+		{
+			name: "no containers",
+			pod:  &corev1.Pod{},
+		},
+		{
+			name: "first container",
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "app"}, {Name: "other"}},
+				},
+			},
+			expected: map[string]string{defaultContainerAnnotation: "app"},
+		},
+		{
+			name: "default container already set",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{defaultContainerAnnotation: "other"},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "app"}, {Name: "other"}},
+				},
+			},
+			expected: map[string]string{defaultContainerAnnotation: "other"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			setDefaultContainer(tc.pod)
+			assert.Equal(tc.expected, tc.pod.Annotations)
+		})
+	}
+}
+
+// TestProxyAdminPortNotDNATed verifies that in the pod IP local proxy mode, the connections of the lifecycle hooks of
+// the proxy to its admin port, which only listens on localhost, are not sent to the pod IP
+func TestProxyAdminPortNotDNATed(t *testing.T) {
+	for _, family := range []ipFamily{ipv4Family, ipv6Family} {
+		t.Run(family.restoreCmd, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			iptables := generateIptablesRestoreCommand(family, v1alpha2.LocalProxyModePodIP, nil, nil, nil, nil, nil)
+			assert.Contains(iptables, fmt.Sprintf("-I OUTPUT -p tcp -o lo -d %s ! --dport %d -m owner --uid-owner %d -j DNAT --to-destination $%s",
+				family.localhostCIDR, constants.EnvoyAdminPort, constants.EnvoyUID, family.podIPVar))
+
+			nftables := generateNftablesRestoreCommand(family, v1alpha2.LocalProxyModePodIP, nil, nil, nil, nil, nil)
+			assert.Contains(nftables, fmt.Sprintf("%s daddr %s tcp dport != %d meta skuid %d dnat to $%s",
+				family.nftFamily, family.localhostCIDR, constants.EnvoyAdminPort, constants.EnvoyUID, family.podIPVar))
+
+			// Nothing is sent to the pod IP in the localhost mode
+			assert.NotContains(generateIptablesRestoreCommand(family, v1alpha2.LocalProxyModeLocalhost, nil, nil, nil, nil, nil), "DNAT")
+		})
+	}
+}
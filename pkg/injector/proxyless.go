@@ -0,0 +1,138 @@
+package injector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/models"
+	"github.com/openservicemesh/osm/pkg/proxyless"
+	"github.com/openservicemesh/osm/pkg/version"
+)
+
+// getProxyKind returns the kind of proxy to inject into the given pod, selected by its proxy kind annotation
+func getProxyKind(pod *corev1.Pod) (models.ProxyKind, error) {
+	switch kind := models.ProxyKind(pod.Annotations[constants.ProxyKindAnnotation]); kind {
+	case "", models.KindSidecar:
+		return models.KindSidecar, nil
+	case models.KindProxylessGRPC:
+		return kind, nil
+	default:
+		return "", fmt.Errorf("%w %q for annotation %s", errUnsupportedProxyKind, kind, constants.ProxyKindAnnotation)
+	}
+}
+
+// createProxylessGRPCPatch patches the given pod to consume the mesh config through gRPC's xDS client. Only the gRPC
+// bootstrap config and the certificates it references are injected: no sidecar, nor init container redirecting
+// the traffic, is added to the pod.
+func (wh *mutatingWebhook) createProxylessGRPCPatch(pod *corev1.Pod, req *admissionv1.AdmissionRequest, proxyUUID uuid.UUID, bootstrapCertificate *certificate.Certificate) ([]byte, error) {
+	namespace := req.Namespace
+	si := identity.New(pod.Spec.ServiceAccountName, namespace)
+	bootstrapSecretName := bootstrapConfigName(proxyUUID)
+
+	if req.DryRun != nil && *req.DryRun {
+		log.Debug().Msgf("Skipping gRPC bootstrap config creation for dry-run request: service-account=%s, namespace=%s", pod.Spec.ServiceAccountName, namespace)
+	} else if _, err := wh.createProxylessGRPCBootstrapConfig(proxyUUID, si, namespace, bootstrapCertificate); err != nil {
+		log.Error().Err(err).Msgf("Failed to create gRPC bootstrap config for pod: service-account=%s, namespace=%s", pod.Spec.ServiceAccountName, namespace)
+		return nil, err
+	}
+
+	if pod.Labels == nil {
+		pod.Labels = make(map[string]string)
+	}
+	pod.Labels[constants.EnvoyUniqueIDLabelName] = proxyUUID.String()
+
+	// Pods copied by 'kubectl debug' already have the volume, which is pointed to the new bootstrap config
+	if _, alreadyInjected := getProxyUUID(pod); alreadyInjected {
+		for i, volume := range pod.Spec.Volumes {
+			if volume.Name == envoyBootstrapConfigVolume {
+				pod.Spec.Volumes[i] = getVolumeSpec(bootstrapSecretName)
+				break
+			}
+		}
+		return json.Marshal(makePatches(req, pod))
+	}
+
+	pod.Spec.Volumes = append(pod.Spec.Volumes, getVolumeSpec(bootstrapSecretName))
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      envoyBootstrapConfigVolume,
+			MountPath: proxyless.BootstrapConfigPath,
+			ReadOnly:  true,
+		})
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  proxyless.BootstrapEnvVar,
+			Value: proxyless.BootstrapConfigPath + "/" + proxyless.BootstrapConfigFile,
+		})
+	}
+
+	return json.Marshal(makePatches(req, pod))
+}
+
+// createProxylessGRPCBootstrapConfig creates the secret holding the gRPC bootstrap config, the certificate used to
+// connect to the xDS server and the service certificate used for mTLS with peers.
+func (wh *mutatingWebhook) createProxylessGRPCBootstrapConfig(proxyUUID uuid.UUID, si identity.ServiceIdentity, namespace string, cert *certificate.Certificate) (*corev1.Secret, error) {
+	builder := proxyless.Builder{
+		NodeID:  proxyUUID.String(),
+		XDSHost: fmt.Sprintf("%s.%s.svc.cluster.local", constants.OSMControllerName, wh.osmNamespace),
+		XDSPort: constants.ADSServerPort,
+	}
+	bootstrapConfig, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	serviceCert, err := wh.certManager.IssueCertificate(certificate.ForServiceIdentity(si))
+	if err != nil {
+		log.Error().Err(err).Msgf("Error issuing service certificate for proxyless gRPC pod with identity %s", si)
+		return nil, err
+	}
+
+	name := bootstrapConfigName(proxyUUID)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				constants.OSMAppNameLabelKey:     constants.OSMAppNameLabelValue,
+				constants.OSMAppInstanceLabelKey: wh.meshName,
+				constants.OSMAppVersionLabelKey:  version.Version,
+			},
+		},
+		Data: map[string][]byte{
+			proxyless.BootstrapConfigFile: bootstrapConfig,
+			proxyless.XDSCACertFile:       cert.GetTrustedCAs(),
+			proxyless.XDSCertFile:         cert.GetCertificateChain(),
+			proxyless.XDSKeyFile:          cert.GetPrivateKey(),
+			signingIssuerIDKey:            []byte(cert.GetSigningIssuerID()),
+			validatingIssuerIDKey:         []byte(cert.GetValidatingIssuerID()),
+			proxyless.ServiceIdentityKey:  []byte(si.String()),
+		},
+	}
+	setProxylessServiceCertificate(secret.Data, serviceCert)
+
+	log.Debug().Msgf("Creating bootstrap config for proxyless gRPC: name=%s, namespace=%s", name, namespace)
+	return wh.kubeClient.CoreV1().Secrets(namespace).Create(context.Background(), secret, metav1.CreateOptions{})
+}
+
+// setProxylessServiceCertificate sets the given service certificate in the given bootstrap secret data, and returns
+// whether it differs from the one previously set
+func setProxylessServiceCertificate(data map[string][]byte, cert *certificate.Certificate) bool {
+	if bytes.Equal(data[proxyless.ServiceCertFile], cert.GetCertificateChain()) &&
+		bytes.Equal(data[proxyless.ServiceCACertFile], cert.GetTrustedCAs()) {
+		return false
+	}
+	data[proxyless.ServiceCACertFile] = cert.GetTrustedCAs()
+	data[proxyless.ServiceCertFile] = cert.GetCertificateChain()
+	data[proxyless.ServiceKeyFile] = cert.GetPrivateKey()
+	return true
+}
@@ -0,0 +1,144 @@
+package injector
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	tresorFake "github.com/openservicemesh/osm/pkg/certificate/providers/tresor/fake"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/models"
+	"github.com/openservicemesh/osm/pkg/proxyless"
+)
+
+func TestGetProxyKind(t *testing.T) {
+	testCases := []struct {
+		name         string
+		annotations  map[string]string
+		expectedKind models.ProxyKind
+		expectErr    bool
+	}{
+		{
+			name:         "no annotation",
+			expectedKind: models.KindSidecar,
+		},
+		{
+			name:         "sidecar kind",
+			annotations:  map[string]string{constants.ProxyKindAnnotation: "sidecar"},
+			expectedKind: models.KindSidecar,
+		},
+		{
+			name:         "proxyless gRPC kind",
+			annotations:  map[string]string{constants.ProxyKindAnnotation: "grpc"},
+			expectedKind: models.KindProxylessGRPC,
+		},
+		{
+			name:        "unsupported kind",
+			annotations: map[string]string{constants.ProxyKindAnnotation: "gateway"},
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			kind, err := getProxyKind(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}})
+			assert.Equal(tc.expectErr, err != nil)
+			assert.Equal(tc.expectedKind, kind)
+		})
+	}
+}
+
+func TestCreateProxylessGRPCPatch(t *testing.T) {
+	assert := tassert.New(t)
+
+	const namespace = "ns"
+	proxyUUID := uuid.New()
+	client := fake.NewSimpleClientset()
+	certManager := tresorFake.NewFake(1 * time.Hour)
+	wh := &mutatingWebhook{
+		kubeClient:   client,
+		certManager:  certManager,
+		osmNamespace: "osm-system",
+		meshName:     "osm",
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "greeter",
+			Namespace:   namespace,
+			Annotations: map[string]string{constants.ProxyKindAnnotation: "grpc"},
+		},
+		Spec: corev1.PodSpec{
+			ServiceAccountName: "greeter",
+			Containers:         []corev1.Container{{Name: "app"}},
+		},
+	}
+	req := &admissionv1.AdmissionRequest{Namespace: namespace}
+
+	bootstrapCert, err := certManager.IssueCertificate(certificate.ForCommonName(proxyUUID.String()))
+	assert.NoError(err)
+
+	patch, err := wh.createProxylessGRPCPatch(pod, req, proxyUUID, bootstrapCert)
+	assert.NoError(err)
+	assert.NotEmpty(patch)
+
+	var patches []map[string]interface{}
+	assert.NoError(json.Unmarshal(patch, &patches))
+
+	// No sidecar nor init container is injected
+	assert.Len(pod.Spec.Containers, 1)
+	assert.Empty(pod.Spec.InitContainers)
+	assert.Equal(proxyUUID.String(), pod.Labels[constants.EnvoyUniqueIDLabelName])
+
+	assert.Len(pod.Spec.Volumes, 1)
+	assert.Equal(bootstrapConfigName(proxyUUID), pod.Spec.Volumes[0].Secret.SecretName)
+	assert.Contains(pod.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      envoyBootstrapConfigVolume,
+		MountPath: proxyless.BootstrapConfigPath,
+		ReadOnly:  true,
+	})
+	assert.Contains(pod.Spec.Containers[0].Env, corev1.EnvVar{
+		Name:  proxyless.BootstrapEnvVar,
+		Value: "/etc/osm/grpc/bootstrap.json",
+	})
+
+	secret, err := client.CoreV1().Secrets(namespace).Get(context.Background(), bootstrapConfigName(proxyUUID), metav1.GetOptions{})
+	assert.NoError(err)
+	for _, key := range []string{
+		proxyless.BootstrapConfigFile,
+		proxyless.XDSCACertFile,
+		proxyless.XDSCertFile,
+		proxyless.XDSKeyFile,
+		proxyless.ServiceCACertFile,
+		proxyless.ServiceCertFile,
+		proxyless.ServiceKeyFile,
+	} {
+		assert.NotEmpty(secret.Data[key], key)
+	}
+	assert.Equal("greeter.ns", string(secret.Data[proxyless.ServiceIdentityKey]))
+}
+
+func TestSetProxylessServiceCertificate(t *testing.T) {
+	assert := tassert.New(t)
+
+	certManager := tresorFake.NewFake(1 * time.Hour)
+	cert, err := certManager.IssueCertificate(certificate.ForServiceIdentity(identity.New("greeter", "ns")))
+	assert.NoError(err)
+	data := map[string][]byte{}
+
+	assert.True(setProxylessServiceCertificate(data, cert))
+	assert.Equal([]byte(cert.GetCertificateChain()), data[proxyless.ServiceCertFile])
+	assert.False(setProxylessServiceCertificate(data, cert))
+}
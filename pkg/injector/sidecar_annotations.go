@@ -0,0 +1,216 @@
+package injector
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/openservicemesh/osm/pkg/models"
+)
+
+const (
+	// sidecarCPURequestAnnotation is the annotation used to override the CPU request of the sidecar
+	sidecarCPURequestAnnotation = "openservicemesh.io/sidecar-cpu-request"
+
+	// sidecarCPULimitAnnotation is the annotation used to override the CPU limit of the sidecar
+	sidecarCPULimitAnnotation = "openservicemesh.io/sidecar-cpu-limit"
+
+	// sidecarMemoryRequestAnnotation is the annotation used to override the memory request of the sidecar
+	sidecarMemoryRequestAnnotation = "openservicemesh.io/sidecar-memory-request"
+
+	// sidecarMemoryLimitAnnotation is the annotation used to override the memory limit of the sidecar
+	sidecarMemoryLimitAnnotation = "openservicemesh.io/sidecar-memory-limit"
+
+	// sidecarConcurrencyAnnotation is the annotation used to override the number of worker threads of the sidecar
+	sidecarConcurrencyAnnotation = "openservicemesh.io/sidecar-concurrency"
+
+	// sidecarComponentLogLevelAnnotation is the annotation used to set the log levels of the components of the
+	// sidecar, as a comma separated list of <component>:<level> pairs
+	sidecarComponentLogLevelAnnotation = "openservicemesh.io/sidecar-component-log-level"
+
+	// sidecarStatsTagsAnnotation is the annotation used to add tags to the stats of the sidecar, as a comma
+	// separated list of <name>=<value> pairs
+	sidecarStatsTagsAnnotation = "openservicemesh.io/sidecar-stats-tags"
+)
+
+var (
+	// envoyLogLevels are the log levels supported by Envoy
+	envoyLogLevels = map[string]bool{
+		"trace":    true,
+		"debug":    true,
+		"info":     true,
+		"warning":  true,
+		"warn":     true,
+		"error":    true,
+		"critical": true,
+		"off":      true,
+	}
+
+	envoyLogComponentRegex = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+	// statsTagNameRegex matches the stats tag names which are valid Prometheus label names
+	statsTagNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// podSidecarSettings are the settings of the Envoy sidecar of a pod
+type podSidecarSettings struct {
+	// resources are the compute resources of the sidecar container
+	resources corev1.ResourceRequirements
+
+	// concurrency is the number of worker threads of the sidecar, or 0 for the number of CPU cores of the node
+	concurrency int32
+
+	// componentLogLevels is the value of the --component-log-level flag of the sidecar, or empty when unset
+	componentLogLevels string
+
+	// statsTags are the tags added to the stats of the sidecar, keyed by tag name
+	statsTags map[string]string
+}
+
+// getSidecarSettingsForPod returns the settings of the Envoy sidecar of the given pod, from the given sidecar
+// configuration overridden by the annotations of the pod.
+//
+// The function returns an error when the value of an annotation is invalid.
+func getSidecarSettingsForPod(pod *corev1.Pod, sidecarConfig models.SidecarConfig) (podSidecarSettings, error) {
+	var settings podSidecarSettings
+	var err error
+
+	if settings.resources, err = getSidecarResourcesForPod(pod, sidecarConfig.Sidecar.Resources); err != nil {
+		return podSidecarSettings{}, err
+	}
+	if settings.concurrency, err = getSidecarConcurrencyForPod(pod, sidecarConfig.Concurrency); err != nil {
+		return podSidecarSettings{}, err
+	}
+	if settings.componentLogLevels, err = getSidecarComponentLogLevelsForPod(pod); err != nil {
+		return podSidecarSettings{}, err
+	}
+	if settings.statsTags, err = getSidecarStatsTagsForPod(pod); err != nil {
+		return podSidecarSettings{}, err
+	}
+
+	return settings, nil
+}
+
+// getSidecarResourcesForPod returns the given compute resources of the sidecar with the requests and limits
+// overridden by the annotations of the given pod.
+//
+// The function returns an error when an annotated quantity is invalid, or when a request exceeds its limit.
+func getSidecarResourcesForPod(pod *corev1.Pod, defaults corev1.ResourceRequirements) (corev1.ResourceRequirements, error) {
+	// The defaults are shared with the other pods
+	resources := *defaults.DeepCopy()
+
+	overrides := []struct {
+		annotation string
+		name       corev1.ResourceName
+		list       *corev1.ResourceList
+	}{
+		{sidecarCPURequestAnnotation, corev1.ResourceCPU, &resources.Requests},
+		{sidecarCPULimitAnnotation, corev1.ResourceCPU, &resources.Limits},
+		{sidecarMemoryRequestAnnotation, corev1.ResourceMemory, &resources.Requests},
+		{sidecarMemoryLimitAnnotation, corev1.ResourceMemory, &resources.Limits},
+	}
+	for _, override := range overrides {
+		quantityStr, ok := pod.Annotations[override.annotation]
+		if !ok {
+			continue
+		}
+
+		quantity, err := resource.ParseQuantity(strings.TrimSpace(quantityStr))
+		if err != nil || quantity.Sign() <= 0 {
+			return corev1.ResourceRequirements{}, fmt.Errorf("Invalid quantity '%s' specified for annotation '%s'", quantityStr, override.annotation)
+		}
+		if *override.list == nil {
+			*override.list = make(corev1.ResourceList)
+		}
+		(*override.list)[override.name] = quantity
+	}
+
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		request, hasRequest := resources.Requests[name]
+		limit, hasLimit := resources.Limits[name]
+		if hasRequest && hasLimit && request.Cmp(limit) > 0 {
+			return corev1.ResourceRequirements{}, fmt.Errorf("Sidecar %s request '%s' exceeds its limit '%s'", name, request.String(), limit.String())
+		}
+	}
+
+	return resources, nil
+}
+
+// getSidecarConcurrencyForPod returns the number of worker threads of the sidecar of the given pod, from the
+// annotation of the pod or the given default.
+//
+// The function returns an error when the annotated value is not a positive integer.
+func getSidecarConcurrencyForPod(pod *corev1.Pod, defaultConcurrency int32) (int32, error) {
+	concurrencyStr, ok := pod.Annotations[sidecarConcurrencyAnnotation]
+	if !ok {
+		return defaultConcurrency, nil
+	}
+
+	concurrency, err := strconv.ParseInt(strings.TrimSpace(concurrencyStr), 10, 32)
+	if err != nil || concurrency <= 0 {
+		return 0, fmt.Errorf("Invalid concurrency value '%s' specified for annotation '%s', must be a positive integer", concurrencyStr, sidecarConcurrencyAnnotation)
+	}
+
+	return int32(concurrency), nil
+}
+
+// getSidecarComponentLogLevelsForPod returns the log levels of the components of the sidecar of the given pod, in
+// the format of the --component-log-level flag of Envoy, or empty when the pod is not annotated.
+//
+// The function returns an error when a component or log level is invalid.
+func getSidecarComponentLogLevelsForPod(pod *corev1.Pod) (string, error) {
+	logLevelsStr, ok := pod.Annotations[sidecarComponentLogLevelAnnotation]
+	if !ok {
+		return "", nil
+	}
+
+	var logLevels []string
+	for _, logLevelStr := range strings.Split(logLevelsStr, ",") {
+		logLevelStr := strings.TrimSpace(logLevelStr)
+		component, level, found := strings.Cut(logLevelStr, ":")
+		if !found || !envoyLogComponentRegex.MatchString(component) {
+			return "", fmt.Errorf("Invalid component log level '%s' specified for annotation '%s', must be of the form <component>:<level>", logLevelStr, sidecarComponentLogLevelAnnotation)
+		}
+		if !envoyLogLevels[level] {
+			return "", fmt.Errorf("Invalid log level '%s' specified for component '%s' in annotation '%s'", level, component, sidecarComponentLogLevelAnnotation)
+		}
+		logLevels = append(logLevels, logLevelStr)
+	}
+
+	return strings.Join(logLevels, ","), nil
+}
+
+// getSidecarStatsTagsForPod returns the tags added to the stats of the sidecar of the given pod, keyed by tag name,
+// or nil when the pod is not annotated.
+//
+// The function returns an error when a tag name is not a valid Prometheus label name, is reserved for the tags
+// extracted by Envoy, or is specified more than once, or when a tag value is empty.
+func getSidecarStatsTagsForPod(pod *corev1.Pod) (map[string]string, error) {
+	statsTagsStr, ok := pod.Annotations[sidecarStatsTagsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	statsTags := make(map[string]string)
+	for _, statsTagStr := range strings.Split(statsTagsStr, ",") {
+		statsTagStr := strings.TrimSpace(statsTagStr)
+		name, value, found := strings.Cut(statsTagStr, "=")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !found || !statsTagNameRegex.MatchString(name) || value == "" {
+			return nil, fmt.Errorf("Invalid stats tag '%s' specified for annotation '%s', must be of the form <name>=<value>", statsTagStr, sidecarStatsTagsAnnotation)
+		}
+		if strings.HasPrefix(name, "envoy_") {
+			return nil, fmt.Errorf("Stats tag name '%s' specified for annotation '%s' is reserved by Envoy", name, sidecarStatsTagsAnnotation)
+		}
+		if _, ok := statsTags[name]; ok {
+			return nil, fmt.Errorf("Stats tag '%s' specified more than once for annotation '%s'", name, sidecarStatsTagsAnnotation)
+		}
+		statsTags[name] = value
+	}
+
+	return statsTags, nil
+}
@@ -0,0 +1,155 @@
+package injector
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	"github.com/openservicemesh/osm/pkg/models"
+)
+
+func TestGetSidecarSettingsForPod(t *testing.T) {
+	sidecarConfig := models.SidecarConfig{
+		Sidecar: configv1alpha2.SidecarSpec{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			},
+		},
+		Concurrency: 2,
+	}
+
+	testCases := []struct {
+		name             string
+		podAnnotations   map[string]string
+		expectedSettings podSidecarSettings
+		expectedErr      string
+	}{
+		{
+			name: "no annotations",
+			expectedSettings: podSidecarSettings{
+				resources:   sidecarConfig.Sidecar.Resources,
+				concurrency: 2,
+			},
+		},
+		{
+			name: "all annotations",
+			podAnnotations: map[string]string{
+				sidecarCPURequestAnnotation:        "200m",
+				sidecarCPULimitAnnotation:          "500m",
+				sidecarMemoryRequestAnnotation:     "64Mi",
+				sidecarMemoryLimitAnnotation:       "128Mi",
+				sidecarConcurrencyAnnotation:       "4",
+				sidecarComponentLogLevelAnnotation: "upstream:debug, connection:trace",
+				sidecarStatsTagsAnnotation:         "team=payments, cost_center = 1234",
+			},
+			expectedSettings: podSidecarSettings{
+				resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("200m"),
+						corev1.ResourceMemory: resource.MustParse("64Mi"),
+					},
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("128Mi"),
+					},
+				},
+				concurrency:        4,
+				componentLogLevels: "upstream:debug,connection:trace",
+				statsTags:          map[string]string{"team": "payments", "cost_center": "1234"},
+			},
+		},
+		{
+			name:           "invalid quantity",
+			podAnnotations: map[string]string{sidecarMemoryLimitAnnotation: "lots"},
+			expectedErr:    "Invalid quantity 'lots' specified for annotation 'openservicemesh.io/sidecar-memory-limit'",
+		},
+		{
+			name:           "zero quantity",
+			podAnnotations: map[string]string{sidecarCPURequestAnnotation: "0"},
+			expectedErr:    "Invalid quantity '0' specified for annotation 'openservicemesh.io/sidecar-cpu-request'",
+		},
+		{
+			name:           "request exceeds the limit of the sidecar config",
+			podAnnotations: map[string]string{sidecarCPURequestAnnotation: "2"},
+			expectedErr:    "Sidecar cpu request '2' exceeds its limit '1'",
+		},
+		{
+			name: "request exceeds the annotated limit",
+			podAnnotations: map[string]string{
+				sidecarMemoryRequestAnnotation: "1Gi",
+				sidecarMemoryLimitAnnotation:   "512Mi",
+			},
+			expectedErr: "Sidecar memory request '1Gi' exceeds its limit '512Mi'",
+		},
+		{
+			name:           "invalid concurrency",
+			podAnnotations: map[string]string{sidecarConcurrencyAnnotation: "two"},
+			expectedErr:    "Invalid concurrency value 'two' specified for annotation 'openservicemesh.io/sidecar-concurrency', must be a positive integer",
+		},
+		{
+			name:           "zero concurrency",
+			podAnnotations: map[string]string{sidecarConcurrencyAnnotation: "0"},
+			expectedErr:    "Invalid concurrency value '0' specified for annotation 'openservicemesh.io/sidecar-concurrency', must be a positive integer",
+		},
+		{
+			name:           "component log level without level",
+			podAnnotations: map[string]string{sidecarComponentLogLevelAnnotation: "upstream"},
+			expectedErr:    "Invalid component log level 'upstream' specified for annotation 'openservicemesh.io/sidecar-component-log-level', must be of the form <component>:<level>",
+		},
+		{
+			name:           "invalid log level",
+			podAnnotations: map[string]string{sidecarComponentLogLevelAnnotation: "upstream:verbose"},
+			expectedErr:    "Invalid log level 'verbose' specified for component 'upstream' in annotation 'openservicemesh.io/sidecar-component-log-level'",
+		},
+		{
+			name:           "stats tag without value",
+			podAnnotations: map[string]string{sidecarStatsTagsAnnotation: "team="},
+			expectedErr:    "Invalid stats tag 'team=' specified for annotation 'openservicemesh.io/sidecar-stats-tags', must be of the form <name>=<value>",
+		},
+		{
+			name:           "invalid stats tag name",
+			podAnnotations: map[string]string{sidecarStatsTagsAnnotation: "cost-center=1234"},
+			expectedErr:    "Invalid stats tag 'cost-center=1234' specified for annotation 'openservicemesh.io/sidecar-stats-tags', must be of the form <name>=<value>",
+		},
+		{
+			name:           "reserved stats tag name",
+			podAnnotations: map[string]string{sidecarStatsTagsAnnotation: "envoy_cluster_name=foo"},
+			expectedErr:    "Stats tag name 'envoy_cluster_name' specified for annotation 'openservicemesh.io/sidecar-stats-tags' is reserved by Envoy",
+		},
+		{
+			name:           "duplicate stats tag",
+			podAnnotations: map[string]string{sidecarStatsTagsAnnotation: "team=payments,team=orders"},
+			expectedErr:    "Stats tag 'team' specified more than once for annotation 'openservicemesh.io/sidecar-stats-tags'",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pod-test",
+					Annotations: tc.podAnnotations,
+				},
+			}
+
+			settings, err := getSidecarSettingsForPod(pod, sidecarConfig)
+			if tc.expectedErr != "" {
+				assert.EqualError(err, tc.expectedErr)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.expectedSettings, settings)
+
+			// The resources of the sidecar config are shared with the other pods
+			assert.Equal(resource.MustParse("100m"), sidecarConfig.Sidecar.Resources.Requests[corev1.ResourceCPU])
+			assert.NotContains(sidecarConfig.Sidecar.Resources.Requests, corev1.ResourceMemory)
+		})
+	}
+}
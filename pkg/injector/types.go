@@ -29,6 +29,18 @@ type mutatingWebhook struct {
 	meshName               string
 	osmContainerPullPolicy corev1.PullPolicy
 
+	// enableCNI indicates whether the traffic of pods is redirected to their sidecar by the OSM CNI plugin,
+	// in which case no init container is injected
+	enableCNI bool
+
+	// cniValidationImage is the image of the init container verifying that the traffic of pods was redirected by the
+	// OSM CNI plugin
+	cniValidationImage string
+
+	// nativeSidecarsSupported indicates whether the Kubernetes API server enables native sidecar containers by
+	// default, in which case sidecars are injected as native sidecar containers unless configured otherwise
+	nativeSidecarsSupported bool
+
 	nonInjectNamespaces mapset.Set
 }
 
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 
@@ -35,7 +36,7 @@ const (
 )
 
 // NewMutatingWebhook starts a new web server handling requests from the injector MutatingWebhookConfiguration
-func NewMutatingWebhook(ctx context.Context, kubeClient kubernetes.Interface, certManager *certificate.Manager, kubeController k8s.Controller, meshName, osmNamespace, webhookConfigName, osmVersion string, webhookTimeout int32, enableReconciler bool, osmContainerPullPolicy corev1.PullPolicy) error {
+func NewMutatingWebhook(ctx context.Context, kubeClient kubernetes.Interface, certManager *certificate.Manager, kubeController k8s.Controller, meshName, osmNamespace, webhookConfigName, osmVersion string, webhookTimeout int32, enableReconciler bool, osmContainerPullPolicy corev1.PullPolicy, enableCNI bool) error {
 	wh := mutatingWebhook{
 		kubeClient:             kubeClient,
 		certManager:            certManager,
@@ -43,6 +44,10 @@ func NewMutatingWebhook(ctx context.Context, kubeClient kubernetes.Interface, ce
 		osmNamespace:           osmNamespace,
 		meshName:               meshName,
 		osmContainerPullPolicy: osmContainerPullPolicy,
+		enableCNI:              enableCNI,
+		cniValidationImage:     os.Getenv("OSM_DEFAULT_CNI_VALIDATION_IMAGE"),
+
+		nativeSidecarsSupported: isNativeSidecarSupported(kubeClient),
 
 		// Envoy sidecars should never be injected in these namespaces
 		nonInjectNamespaces: mapset.NewSet(
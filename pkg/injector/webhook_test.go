@@ -18,6 +18,7 @@ import (
 	mapset "github.com/deckarep/golang-set"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	tassert "github.com/stretchr/testify/assert"
 	admissionv1 "k8s.io/api/admission/v1"
 	admissionregv1 "k8s.io/api/admissionregistration/v1"
@@ -32,6 +33,8 @@ import (
 	tresorFake "github.com/openservicemesh/osm/pkg/certificate/providers/tresor/fake"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+	"github.com/openservicemesh/osm/pkg/models"
 	"github.com/openservicemesh/osm/pkg/webhook"
 )
 
@@ -577,7 +580,7 @@ var _ = Describe("Testing Injector Functions", func() {
 
 		_, err := kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.Background(), webhookName, metav1.GetOptions{})
 		Expect(err).NotTo(HaveOccurred())
-		actualErr := NewMutatingWebhook(context.Background(), kubeClient, certManager, kubeController, meshName, osmNamespace, webhookName, osmVersion, webhookTimeout, enableReconciler, "")
+		actualErr := NewMutatingWebhook(context.Background(), kubeClient, certManager, kubeController, meshName, osmNamespace, webhookName, osmVersion, webhookTimeout, enableReconciler, "", false)
 		Expect(actualErr).NotTo(HaveOccurred())
 		close(stop)
 	})
@@ -593,7 +596,7 @@ var _ = Describe("Testing Injector Functions", func() {
 
 		kubeController.EXPECT().GetMeshConfig().AnyTimes()
 
-		actualErr := NewMutatingWebhook(context.Background(), kubeClient, certManager, kubeController, meshName, osmNamespace, webhookName, osmVersion, webhookTimeout, enableReconciler, "")
+		actualErr := NewMutatingWebhook(context.Background(), kubeClient, certManager, kubeController, meshName, osmNamespace, webhookName, osmVersion, webhookTimeout, enableReconciler, "", false)
 		Expect(actualErr).NotTo(HaveOccurred())
 		Eventually(func() error {
 			_, err := kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.Background(), webhookName, metav1.GetOptions{})
@@ -842,7 +845,7 @@ func TestWebhookMutate(t *testing.T) {
 		kubeController.EXPECT().GetNamespace(namespace).Return(nil).Times(1)
 		kubeController.EXPECT().IsMonitoredNamespace(namespace).Return(true)
 
-		kubeController.EXPECT().GetMeshConfig().Return(v1alpha2.MeshConfig{
+		meshConfig := v1alpha2.MeshConfig{
 			Spec: v1alpha2.MeshConfigSpec{
 				Sidecar: v1alpha2.SidecarSpec{
 					EnvoyImage:         "envoy-linux-image",
@@ -850,7 +853,9 @@ func TestWebhookMutate(t *testing.T) {
 					InitContainerImage: "init-container-image",
 				},
 			},
-		}).AnyTimes()
+		}
+		kubeController.EXPECT().GetMeshConfig().Return(meshConfig).AnyTimes()
+		kubeController.EXPECT().GetSidecarConfig(namespace, gomock.Any()).Return(models.SidecarConfig{Sidecar: meshConfig.Spec.Sidecar}, nil).AnyTimes()
 
 		wh := &mutatingWebhook{
 			nonInjectNamespaces: mapset.NewSet(),
@@ -878,6 +883,96 @@ func TestWebhookMutate(t *testing.T) {
 		assert.Contains(res.Result.Message, errNamespaceNotFound.Error())
 	})
 
+	t.Run("invalid sidecar annotation", func(t *testing.T) {
+		namespace := "ns"
+
+		meshConfig := v1alpha2.MeshConfig{
+			Spec: v1alpha2.MeshConfigSpec{
+				Sidecar: v1alpha2.SidecarSpec{
+					EnvoyImage:         "envoy-linux-image",
+					EnvoyWindowsImage:  "envoy-windows-image",
+					InitContainerImage: "init-container-image",
+				},
+			},
+		}
+
+		testCases := []struct {
+			annotation      string
+			value           string
+			expectedMessage string
+		}{
+			{
+				annotation:      "openservicemesh.io/sidecar-concurrency",
+				value:           "-1",
+				expectedMessage: "Invalid concurrency value '-1' specified for annotation 'openservicemesh.io/sidecar-concurrency', must be a positive integer",
+			},
+			{
+				annotation:      "openservicemesh.io/proxy-drain-timeout",
+				value:           "forever",
+				expectedMessage: `Invalid annotation value for key "openservicemesh.io/proxy-drain-timeout"`,
+			},
+			{
+				annotation:      "openservicemesh.io/proxy-drain-max-active-connections",
+				value:           "-1",
+				expectedMessage: `Invalid annotation value for key "openservicemesh.io/proxy-drain-max-active-connections": -1`,
+			},
+			{
+				annotation:      "openservicemesh.io/hold-application-until-proxy-starts",
+				value:           "maybe",
+				expectedMessage: "openservicemesh.io/hold-application-until-proxy-starts",
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.annotation, func(t *testing.T) {
+				assert := tassert.New(t)
+
+				mockCtrl := gomock.NewController(t)
+				kubeController := k8s.NewMockController(mockCtrl)
+				kubeController.EXPECT().GetNamespace(namespace).Return(&corev1.Namespace{}).AnyTimes()
+				kubeController.EXPECT().IsMonitoredNamespace(namespace).Return(true)
+				kubeController.EXPECT().GetMeshConfig().Return(meshConfig).AnyTimes()
+				kubeController.EXPECT().GetSidecarConfig(namespace, gomock.Any()).Return(models.SidecarConfig{Sidecar: meshConfig.Spec.Sidecar}, nil).AnyTimes()
+
+				client := fake.NewSimpleClientset()
+				wh := &mutatingWebhook{
+					nonInjectNamespaces: mapset.NewSet(),
+					kubeController:      kubeController,
+					certManager:         tresorFake.NewFake(1 * time.Hour),
+					kubeClient:          client,
+				}
+
+				req := &admissionv1.AdmissionRequest{
+					Namespace: namespace,
+					Object: runtime.RawExtension{
+						Raw: []byte(fmt.Sprintf(`{
+							"apiVersion": "v1",
+							"kind": "Pod",
+							"metadata": {
+								"annotations": {
+									"openservicemesh.io/sidecar-injection": "true",
+									%q: %q
+								}
+							}
+						}`, tc.annotation, tc.value)),
+					},
+				}
+
+				certsIssued := testutil.ToFloat64(metricsstore.DefaultMetricsStore.CertIssuedCount)
+				res := wh.mutate(req, uuid.New())
+				assert.False(res.Allowed)
+				assert.Nil(res.Patch)
+				assert.Contains(res.Result.Message, tc.expectedMessage)
+
+				// Neither the bootstrap certificate nor the bootstrap config are created for rejected pods
+				assert.Equal(certsIssued, testutil.ToFloat64(metricsstore.DefaultMetricsStore.CertIssuedCount))
+				secrets, err := client.CoreV1().Secrets(namespace).List(context.TODO(), metav1.ListOptions{})
+				assert.NoError(err)
+				assert.Empty(secrets.Items)
+			})
+		}
+	})
+
 	t.Run("will inject", func(t *testing.T) {
 		assert := tassert.New(t)
 
@@ -888,7 +983,7 @@ func TestWebhookMutate(t *testing.T) {
 		kubeController.EXPECT().GetNamespace(namespace).Return(&corev1.Namespace{}).Times(2)
 		kubeController.EXPECT().IsMonitoredNamespace(namespace).Return(true)
 
-		kubeController.EXPECT().GetMeshConfig().Return(v1alpha2.MeshConfig{
+		meshConfig := v1alpha2.MeshConfig{
 			Spec: v1alpha2.MeshConfigSpec{
 				Sidecar: v1alpha2.SidecarSpec{
 					EnvoyImage:         "envoy-linux-image",
@@ -896,7 +991,9 @@ func TestWebhookMutate(t *testing.T) {
 					InitContainerImage: "init-container-image",
 				},
 			},
-		}).AnyTimes()
+		}
+		kubeController.EXPECT().GetMeshConfig().Return(meshConfig).AnyTimes()
+		kubeController.EXPECT().GetSidecarConfig(namespace, gomock.Any()).Return(models.SidecarConfig{Sidecar: meshConfig.Spec.Sidecar}, nil).AnyTimes()
 
 		wh := &mutatingWebhook{
 			nonInjectNamespaces: mapset.NewSet(),
@@ -0,0 +1,118 @@
+package injector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/constants"
+	policyClientset "github.com/openservicemesh/osm/pkg/gen/client/policy/clientset/versioned"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/messaging"
+	"github.com/openservicemesh/osm/pkg/models"
+)
+
+// WatchWorkloadEntries assigns a proxy UUID to the WorkloadEntries of the mesh as they are added, and creates the
+// bootstrap config of their proxy, which `osm vm bootstrap` exports to the workloads outside of Kubernetes. The
+// bootstrap configs are owned by their WorkloadEntry, and their certificates are rotated along with the ones of pods.
+func WatchWorkloadEntries(kubeClient kubernetes.Interface, policyClient policyClientset.Interface, certManager *certificate.Manager,
+	kubeController k8s.Controller, meshName, osmNamespace string, msgBroker *messaging.Broker, stop <-chan struct{}) {
+	wh := &mutatingWebhook{
+		kubeClient:     kubeClient,
+		certManager:    certManager,
+		kubeController: kubeController,
+		osmNamespace:   osmNamespace,
+		meshName:       meshName,
+	}
+
+	workloadEntryChan, unsub := msgBroker.SubscribeKubeEvents(events.WorkloadEntry.Added(), events.WorkloadEntry.Updated())
+	defer unsub()
+
+	for {
+		select {
+		case <-stop:
+			log.Info().Msg("Received stop signal, exiting WorkloadEntry bootstrap routine")
+			return
+
+		case msg := <-workloadEntryChan:
+			psubMessage, ok := msg.(events.PubSubMessage)
+			if !ok {
+				log.Error().Msgf("Error casting to events.PubSubMessage, got type %T", msg)
+				continue
+			}
+			we, ok := psubMessage.NewObj.(*policyv1alpha1.WorkloadEntry)
+			if !ok {
+				log.Error().Msgf("Error casting to *WorkloadEntry, got type %T", psubMessage.NewObj)
+				continue
+			}
+			if err := wh.bootstrapWorkloadEntry(context.Background(), policyClient, we); err != nil {
+				log.Error().Err(err).Msgf("Error creating the proxy bootstrap config of WorkloadEntry %s/%s", we.Namespace, we.Name)
+			}
+		}
+	}
+}
+
+// bootstrapWorkloadEntry ensures the given WorkloadEntry has a proxy UUID, and that the bootstrap config of its proxy
+// exists. The UUID is assigned by updating the WorkloadEntry, upon which the bootstrap config is created, so that
+// concurrent replicas of osm-injector agree on it.
+func (wh *mutatingWebhook) bootstrapWorkloadEntry(ctx context.Context, policyClient policyClientset.Interface, we *policyv1alpha1.WorkloadEntry) error {
+	uuidLabel, ok := we.Labels[constants.EnvoyUniqueIDLabelName]
+	if !ok {
+		we = we.DeepCopy()
+		if we.Labels == nil {
+			we.Labels = make(map[string]string)
+		}
+		we.Labels[constants.EnvoyUniqueIDLabelName] = uuid.New().String()
+		_, err := policyClient.PolicyV1alpha1().WorkloadEntries(we.Namespace).Update(ctx, we, metav1.UpdateOptions{})
+		if apierrors.IsConflict(err) {
+			// The WorkloadEntry was changed concurrently, the update will be handled as its own event
+			return nil
+		}
+		return err
+	}
+
+	proxyUUID, err := uuid.Parse(uuidLabel)
+	if err != nil {
+		return fmt.Errorf("invalid proxy UUID %q: %w", uuidLabel, err)
+	}
+	secretName := bootstrapConfigName(proxyUUID)
+	if wh.kubeController.GetSecret(secretName, we.Namespace) != nil {
+		return nil
+	}
+
+	cnPrefix := models.NewXDSCertCNPrefix(proxyUUID, models.KindSidecar, identity.New(we.Spec.ServiceAccount, we.Namespace))
+	bootstrapCertificate, err := wh.certManager.IssueCertificate(certificate.ForCommonNamePrefix(cnPrefix))
+	if err != nil {
+		return fmt.Errorf("error issuing bootstrap certificate with CN prefix %s: %w", cnPrefix, err)
+	}
+
+	secret, err := wh.createEnvoyBootstrapConfig(proxyUUID, we.Namespace, bootstrapCertificate, nil, false, nil)
+	if apierrors.IsAlreadyExists(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// The bootstrap config is deleted along with its WorkloadEntry
+	secret.OwnerReferences = append(secret.OwnerReferences, metav1.OwnerReference{
+		APIVersion: policyv1alpha1.SchemeGroupVersion.String(),
+		Kind:       "WorkloadEntry",
+		Name:       we.Name,
+		UID:        we.UID,
+	})
+	if _, err := wh.kubeClient.CoreV1().Secrets(we.Namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil && !apierrors.IsConflict(err) {
+		return err
+	}
+
+	log.Info().Msgf("Created bootstrap config %s/%s for the proxy of WorkloadEntry %s/%s", we.Namespace, secretName, we.Namespace, we.Name)
+	return nil
+}
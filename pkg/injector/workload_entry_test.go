@@ -0,0 +1,70 @@
+package injector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	tresorFake "github.com/openservicemesh/osm/pkg/certificate/providers/tresor/fake"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy/bootstrap"
+	policyFake "github.com/openservicemesh/osm/pkg/gen/client/policy/clientset/versioned/fake"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/models"
+)
+
+func TestBootstrapWorkloadEntry(t *testing.T) {
+	a := tassert.New(t)
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+
+	we := &policyv1alpha1.WorkloadEntry{
+		ObjectMeta: metav1.ObjectMeta{Name: "vm-1", Namespace: "legacy", UID: "vm-1-uid"},
+		Spec:       policyv1alpha1.WorkloadEntrySpec{Address: "10.0.0.5", ServiceAccount: "inventory"},
+	}
+	kubeClient := fake.NewSimpleClientset()
+	policyClient := policyFake.NewSimpleClientset(we)
+	kubeController := k8s.NewMockController(mockCtrl)
+	kubeController.EXPECT().GetMeshConfig().Return(configv1alpha2.MeshConfig{}).AnyTimes()
+	wh := &mutatingWebhook{
+		kubeClient:     kubeClient,
+		certManager:    tresorFake.NewFake(time.Hour),
+		kubeController: kubeController,
+		osmNamespace:   "osm-system",
+		meshName:       "osm",
+	}
+
+	// A proxy UUID is assigned to the WorkloadEntry first
+	a.NoError(wh.bootstrapWorkloadEntry(ctx, policyClient, we))
+	updated, err := policyClient.PolicyV1alpha1().WorkloadEntries("legacy").Get(ctx, "vm-1", metav1.GetOptions{})
+	a.NoError(err)
+	proxyUUID, err := uuid.Parse(updated.Labels[constants.EnvoyUniqueIDLabelName])
+	a.NoError(err)
+	secrets, err := kubeClient.CoreV1().Secrets("legacy").List(ctx, metav1.ListOptions{})
+	a.NoError(err)
+	a.Empty(secrets.Items)
+
+	// The bootstrap config is then created for the UUID, with a certificate for the service account of the workload
+	kubeController.EXPECT().GetSecret(bootstrapConfigName(proxyUUID), "legacy").Return(nil)
+	a.NoError(wh.bootstrapWorkloadEntry(ctx, policyClient, updated))
+	secret, err := kubeClient.CoreV1().Secrets("legacy").Get(ctx, bootstrapConfigName(proxyUUID), metav1.GetOptions{})
+	a.NoError(err)
+	a.Equal("WorkloadEntry", secret.OwnerReferences[0].Kind)
+	a.Equal(we.UID, secret.OwnerReferences[0].UID)
+	a.Contains(string(secret.Data[bootstrap.EnvoyBootstrapConfigFile]), proxyUUID.String())
+
+	// Existing bootstrap configs are kept
+	kubeController.EXPECT().GetSecret(bootstrapConfigName(proxyUUID), "legacy").Return(&models.Secret{})
+	a.NoError(wh.bootstrapWorkloadEntry(ctx, policyClient, updated))
+
+	updated.Labels[constants.EnvoyUniqueIDLabelName] = "invalid"
+	a.Error(wh.bootstrapWorkloadEntry(ctx, policyClient, updated))
+}
@@ -2,11 +2,13 @@ package k8s
 
 import (
 	"context"
+	"sort"
 
 	smiAccess "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
 	smiSpecs "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
 	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
@@ -86,6 +88,7 @@ func (c *Client) GetSecret(name, namespace string) *models.Secret {
 			Name:      corev1Secret.Name,
 			Namespace: corev1Secret.Namespace,
 			Data:      corev1Secret.Data,
+			Labels:    corev1Secret.Labels,
 		}
 	}
 	return nil
@@ -108,6 +111,7 @@ func (c *Client) ListSecrets() []*models.Secret {
 			Name:      secret.Name,
 			Namespace: secret.Namespace,
 			Data:      secret.Data,
+			Labels:    secret.Labels,
 		})
 	}
 
@@ -181,29 +185,41 @@ func (c *Client) ListPods() []*corev1.Pod {
 	return pods
 }
 
-// GetEndpoints returns the endpoint for a given service, otherwise returns nil if not found
-// or error if the API errored out.
-func (c *Client) GetEndpoints(name, namespace string) (*corev1.Endpoints, error) {
-	ep, exists, err := c.getByKey(informerKeyEndpoints, key(name, namespace))
-	if err != nil {
-		return nil, err
-	}
-	if exists {
-		return ep.(*corev1.Endpoints), nil
+// ListEndpointSlicesForService returns the EndpointSlices of the given service, sorted by name
+func (c *Client) ListEndpointSlicesForService(name, namespace string) []*discoveryv1.EndpointSlice {
+	var slices []*discoveryv1.EndpointSlice
+	for _, sliceIface := range c.byIndex(informerKeyEndpointSlice, endpointSliceServiceIndex, key(name, namespace)) {
+		slices = append(slices, sliceIface.(*discoveryv1.EndpointSlice))
 	}
-	return nil, nil
+	sort.Slice(slices, func(i, j int) bool {
+		return slices[i].Name < slices[j].Name
+	})
+	return slices
 }
 
 // UpdateIngressBackendStatus updates the status for the provided IngressBackend.
 func (c *Client) UpdateIngressBackendStatus(obj *policyv1alpha1.IngressBackend) (*policyv1alpha1.IngressBackend, error) {
+	if !c.writesResources() {
+		return obj, nil
+	}
 	return c.policyClient.PolicyV1alpha1().IngressBackends(obj.Namespace).UpdateStatus(context.Background(), obj, metav1.UpdateOptions{})
 }
 
 // UpdateUpstreamTrafficSettingStatus updates the status for the provided UpstreamTrafficSetting.
 func (c *Client) UpdateUpstreamTrafficSettingStatus(obj *policyv1alpha1.UpstreamTrafficSetting) (*policyv1alpha1.UpstreamTrafficSetting, error) {
+	if !c.writesResources() {
+		return obj, nil
+	}
 	return c.policyClient.PolicyV1alpha1().UpstreamTrafficSettings(obj.Namespace).UpdateStatus(context.Background(), obj, metav1.UpdateOptions{})
 }
 
+// writesResources returns whether the resources reconciled by the control plane, such as resource statuses, are
+// written by this replica. With leader election, they are only written by the leader, and the updates of other
+// replicas are skipped. The leader writes them again when it is elected.
+func (c *Client) writesResources() bool {
+	return c.isLeader == nil || c.isLeader()
+}
+
 // IsHeadlessService determines whether or not a corev1.Service is a headless service
 func IsHeadlessService(svc corev1.Service) bool {
 	return len(svc.Spec.ClusterIP) == 0 || svc.Spec.ClusterIP == corev1.ClusterIPNone
@@ -304,6 +320,22 @@ func (c *Client) ListTelemetryPolicies() []*policyv1alpha1.Telemetry {
 	return telemetryPolicies
 }
 
+// ListWorkloadEntries returns the WorkloadEntries in the monitored namespaces
+func (c *Client) ListWorkloadEntries() []*policyv1alpha1.WorkloadEntry {
+	var workloadEntries []*policyv1alpha1.WorkloadEntry
+
+	for _, resource := range c.list(informerKeyWorkloadEntry) {
+		we, ok := resource.(*policyv1alpha1.WorkloadEntry)
+		if !ok || !c.IsMonitoredNamespace(we.Namespace) {
+			continue
+		}
+
+		workloadEntries = append(workloadEntries, we)
+	}
+
+	return workloadEntries
+}
+
 // ListUpstreamTrafficSettings returns the all UpstreamTrafficSetting resources
 func (c *Client) ListUpstreamTrafficSettings() []*policyv1alpha1.UpstreamTrafficSetting {
 	var settings []*policyv1alpha1.UpstreamTrafficSetting
@@ -386,11 +418,17 @@ func (c *Client) GetHTTPRouteGroup(namespacedName string) *smiSpecs.HTTPRouteGro
 
 // UpdateMeshRootCertificate updates a MeshRootCertificate.
 func (c *Client) UpdateMeshRootCertificate(obj *configv1alpha2.MeshRootCertificate) (*configv1alpha2.MeshRootCertificate, error) {
+	if !c.writesResources() {
+		return obj, nil
+	}
 	return c.configClient.ConfigV1alpha2().MeshRootCertificates(c.osmNamespace).Update(context.Background(), obj, metav1.UpdateOptions{})
 }
 
 // UpdateMeshRootCertificateStatus updates the status of a MeshRootCertificate.
 func (c *Client) UpdateMeshRootCertificateStatus(obj *configv1alpha2.MeshRootCertificate) (*configv1alpha2.MeshRootCertificate, error) {
+	if !c.writesResources() {
+		return obj, nil
+	}
 	return c.configClient.ConfigV1alpha2().MeshRootCertificates(c.osmNamespace).UpdateStatus(context.Background(), obj, metav1.UpdateOptions{})
 }
 
@@ -413,6 +451,43 @@ func (c *Client) ListMeshRootCertificates() ([]*configv1alpha2.MeshRootCertifica
 	return mrcs, nil
 }
 
+// ListTrustDomainFederations returns the TrustDomainFederations stored in the informerCollection's store
+func (c *Client) ListTrustDomainFederations() []*configv1alpha2.TrustDomainFederation {
+	var federations []*configv1alpha2.TrustDomainFederation
+	for _, federationIface := range c.list(informerKeyTrustDomainFederation) {
+		federation, ok := federationIface.(*configv1alpha2.TrustDomainFederation)
+		if !ok {
+			continue
+		}
+		federations = append(federations, federation)
+	}
+
+	return federations
+}
+
+// ListSidecarProfiles returns the SidecarProfiles of the OSM namespace and of the monitored namespaces
+func (c *Client) ListSidecarProfiles() []*configv1alpha2.SidecarProfile {
+	var profiles []*configv1alpha2.SidecarProfile
+	for _, profileIface := range c.list(informerKeySidecarProfile) {
+		profile, ok := profileIface.(*configv1alpha2.SidecarProfile)
+		if !ok {
+			continue
+		}
+		if profile.Namespace != c.osmNamespace && !c.IsMonitoredNamespace(profile.Namespace) {
+			continue
+		}
+		profiles = append(profiles, profile)
+	}
+
+	return profiles
+}
+
+// GetSidecarConfig returns the effective sidecar configuration of the pods with the given labels in the given
+// namespace, resolved from the MeshConfig and the SidecarProfiles selecting them
+func (c *Client) GetSidecarConfig(namespace string, podLabels map[string]string) (models.SidecarConfig, error) {
+	return ResolveSidecarConfig(c.GetMeshConfig(), c.ListSidecarProfiles(), c.osmNamespace, c.GetNamespace(namespace), podLabels)
+}
+
 // ListTCPTrafficSpecs lists SMI TCPRoute resources
 func (c *Client) ListTCPTrafficSpecs() []*smiSpecs.TCPRoute {
 	var tcpRouteSpec []*smiSpecs.TCPRoute
@@ -17,10 +17,12 @@ import (
 	tassert "github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/pointer"
 
 	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
 	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
@@ -289,10 +291,12 @@ func TestListSecrets(t *testing.T) {
 				{
 					Namespace: "ns1",
 					Name:      "s1",
+					Labels:    map[string]string{constants.OSMAppNameLabelKey: constants.OSMAppNameLabelValue},
 				},
 				{
 					Namespace: "ns2",
 					Name:      "s2",
+					Labels:    map[string]string{constants.OSMAppNameLabelKey: constants.OSMAppNameLabelValue},
 				},
 			},
 		},
@@ -311,6 +315,7 @@ func TestListSecrets(t *testing.T) {
 				{
 					Namespace: "ns1",
 					Name:      "s1",
+					Labels:    map[string]string{constants.OSMAppNameLabelKey: constants.OSMAppNameLabelValue},
 				},
 			},
 		},
@@ -356,6 +361,7 @@ func TestGetSecret(t *testing.T) {
 			expSecret: &models.Secret{
 				Name:      "foo",
 				Namespace: "ns1",
+				Labels:    map[string]string{constants.OSMAppNameLabelKey: constants.OSMAppNameLabelValue},
 			},
 		},
 		{
@@ -605,39 +611,40 @@ func TestListPods(t *testing.T) {
 	}
 }
 
-func TestGetEndpoints(t *testing.T) {
+func TestListEndpointSlicesForService(t *testing.T) {
+	newSlice := func(name, namespace, svcName string) *discoveryv1.EndpointSlice {
+		return &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{discoveryv1.LabelServiceName: svcName},
+			},
+		}
+	}
+
 	testCases := []struct {
 		name         string
-		endpoints    *corev1.Endpoints
+		slices       []runtime.Object
 		svcName      string
 		svcNamespace string
-		expected     *corev1.Endpoints
+		expected     []*discoveryv1.EndpointSlice
 	}{
 		{
-			name: "gets the service from the cache given its key",
-			endpoints: &corev1.Endpoints{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "foo",
-					Namespace: "ns1",
-				},
+			name: "gets the slices of the service from the cache",
+			slices: []runtime.Object{
+				newSlice("foo-b", "ns1", "foo"),
+				newSlice("foo-a", "ns1", "foo"),
+				newSlice("foo-a", "ns2", "foo"),
+				newSlice("bar-a", "ns1", "bar"),
+				&discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "ns1"}},
 			},
 			svcName:      "foo",
 			svcNamespace: "ns1",
-			expected: &corev1.Endpoints{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "foo",
-					Namespace: "ns1",
-				},
-			},
+			expected:     []*discoveryv1.EndpointSlice{newSlice("foo-a", "ns1", "foo"), newSlice("foo-b", "ns1", "foo")},
 		},
 		{
-			name: "returns nil if the service is not found in the cache",
-			endpoints: &corev1.Endpoints{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "foo",
-					Namespace: "ns1",
-				},
-			},
+			name:         "returns nil if the service has no slices in the cache",
+			slices:       []runtime.Object{newSlice("foo-a", "ns1", "foo")},
 			svcName:      "invalid",
 			svcNamespace: "ns1",
 			expected:     nil,
@@ -650,12 +657,10 @@ func TestGetEndpoints(t *testing.T) {
 			stop := make(chan struct{})
 			broker := messaging.NewBroker(stop)
 
-			c, err := NewClient("osm", tests.OsmMeshConfigName, broker, WithKubeClient(fake.NewSimpleClientset(tc.endpoints), testMeshName))
+			c, err := NewClient("osm", tests.OsmMeshConfigName, broker, WithKubeClient(fake.NewSimpleClientset(tc.slices...), testMeshName))
 			a.NoError(err)
 
-			actual, err := c.GetEndpoints(tc.svcName, tc.svcNamespace)
-			a.Nil(err)
-			a.Equal(tc.expected, actual)
+			a.Equal(tc.expected, c.ListEndpointSlicesForService(tc.svcName, tc.svcNamespace))
 		})
 	}
 }
@@ -772,6 +777,77 @@ func TestPolicyUpdateStatus(t *testing.T) {
 	}
 }
 
+func TestUpdateStatusWithLeaderElection(t *testing.T) {
+	testCases := []struct {
+		name           string
+		isLeader       bool
+		expectedStatus string
+		expectedRole   configv1alpha2.MeshRootCertificateRole
+	}{
+		{
+			name:           "leader writes the status",
+			isLeader:       true,
+			expectedStatus: "valid",
+			expectedRole:   configv1alpha2.PassiveRole,
+		},
+		{
+			name:           "other replicas skip the status update",
+			isLeader:       false,
+			expectedStatus: "",
+			expectedRole:   configv1alpha2.ActiveRole,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := tassert.New(t)
+			ingressBackend := &policyv1alpha1.IngressBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress-backend-1",
+					Namespace: "test",
+				},
+			}
+			mrc := &configv1alpha2.MeshRootCertificate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "osm-mesh-root-certificate",
+					Namespace: "osm",
+				},
+				Spec: configv1alpha2.MeshRootCertificateSpec{
+					Role: configv1alpha2.ActiveRole,
+				},
+			}
+			kubeClient := fake.NewSimpleClientset()
+			policyClient := fakePolicyClient.NewSimpleClientset(ingressBackend)
+			configClient := fakeConfigClient.NewSimpleClientset(mrc)
+
+			stop := make(chan struct{})
+			defer close(stop)
+			broker := messaging.NewBroker(stop)
+
+			c, err := NewClient("osm", tests.OsmMeshConfigName, broker, WithKubeClient(kubeClient, testMeshName), WithPolicyClient(policyClient),
+				WithConfigClient(configClient), WithLeaderElection(func() bool { return tc.isLeader }))
+			a.NoError(err)
+
+			updatedMRC := mrc.DeepCopy()
+			updatedMRC.Spec.Role = configv1alpha2.PassiveRole
+			_, err = c.UpdateMeshRootCertificate(updatedMRC)
+			a.NoError(err)
+			actualMRC, err := configClient.ConfigV1alpha2().MeshRootCertificates("osm").Get(context.Background(), mrc.Name, metav1.GetOptions{})
+			a.NoError(err)
+			a.Equal(tc.expectedRole, actualMRC.Spec.Role)
+
+			updated := ingressBackend.DeepCopy()
+			updated.Status.CurrentStatus = "valid"
+			_, err = c.UpdateIngressBackendStatus(updated)
+			a.NoError(err)
+
+			actual, err := policyClient.PolicyV1alpha1().IngressBackends("test").Get(context.Background(), "ingress-backend-1", metav1.GetOptions{})
+			a.NoError(err)
+			a.Equal(tc.expectedStatus, actual.Status.CurrentStatus)
+		})
+	}
+}
+
 func TestConfigUpdateStatus(t *testing.T) {
 	testCases := []struct {
 		name             string
@@ -1204,6 +1280,50 @@ func TestListUpstreamTrafficSetting(t *testing.T) {
 	}
 }
 
+func TestListWorkloadEntries(t *testing.T) {
+	a := assert.New(t)
+
+	nsObj := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: testNs,
+			Labels: map[string]string{
+				constants.OSMKubeResourceMonitorAnnotation: testMeshName,
+			},
+		},
+	}
+	inMeshResource := &policyv1alpha1.WorkloadEntry{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vm1",
+			Namespace: testNs,
+		},
+		Spec: policyv1alpha1.WorkloadEntrySpec{
+			Address:        "10.0.0.1",
+			ServiceAccount: "sa1",
+		},
+	}
+	outMeshResource := &policyv1alpha1.WorkloadEntry{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vm1",
+			Namespace: "wrong-ns",
+		},
+		Spec: policyv1alpha1.WorkloadEntrySpec{
+			Address:        "10.0.0.2",
+			ServiceAccount: "sa1",
+		},
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	broker := messaging.NewBroker(stop)
+
+	c, err := NewClient("osm", tests.OsmMeshConfigName, broker,
+		WithPolicyClient(fakePolicyClient.NewSimpleClientset(inMeshResource, outMeshResource)),
+		WithKubeClient(fake.NewSimpleClientset(nsObj), testMeshName))
+	a.NoError(err)
+
+	a.Equal([]*policyv1alpha1.WorkloadEntry{inMeshResource}, c.ListWorkloadEntries())
+}
+
 func TestGetMeshRootCertificate(t *testing.T) {
 	testCases := []struct {
 		name                string
@@ -1289,6 +1409,77 @@ func TestListMeshRootCertificates(t *testing.T) {
 	a.Len(mrcList, 1)
 }
 
+func TestListTrustDomainFederations(t *testing.T) {
+	a := assert.New(t)
+
+	federation := &configv1alpha2.TrustDomainFederation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other-domain",
+			Namespace: "osm-system",
+		},
+		Spec: configv1alpha2.TrustDomainFederationSpec{
+			TrustDomain: "other-domain",
+			TrustBundle: "-----BEGIN CERTIFICATE-----",
+		},
+	}
+	configClient := fakeConfigClient.NewSimpleClientset(federation)
+	stop := make(chan struct{})
+	broker := messaging.NewBroker(stop)
+
+	c, err := NewClient(tests.OsmNamespace, tests.OsmMeshConfigName, broker, WithConfigClient(configClient))
+	a.NoError(err)
+
+	federations := c.ListTrustDomainFederations()
+	a.Contains(federations, federation)
+	a.Len(federations, 1)
+}
+
+func TestListSidecarProfiles(t *testing.T) {
+	a := assert.New(t)
+
+	nsObj := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: testNs,
+			Labels: map[string]string{
+				constants.OSMKubeResourceMonitorAnnotation: testMeshName,
+			},
+		},
+	}
+	meshProfile := &configv1alpha2.SidecarProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mesh",
+			Namespace: "osm",
+		},
+		Spec: configv1alpha2.SidecarProfileSpec{
+			Concurrency: pointer.Int32(2),
+		},
+	}
+	inMeshProfile := &configv1alpha2.SidecarProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "debug",
+			Namespace: testNs,
+		},
+	}
+	outMeshProfile := &configv1alpha2.SidecarProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "debug",
+			Namespace: "wrong-ns",
+		},
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	broker := messaging.NewBroker(stop)
+
+	c, err := NewClient("osm", tests.OsmMeshConfigName, broker,
+		WithConfigClient(fakeConfigClient.NewSimpleClientset(meshProfile, inMeshProfile, outMeshProfile)),
+		WithKubeClient(fake.NewSimpleClientset(nsObj), testMeshName))
+	a.NoError(err)
+
+	profiles := c.ListSidecarProfiles()
+	a.ElementsMatch([]*configv1alpha2.SidecarProfile{meshProfile, inMeshProfile}, profiles)
+}
+
 func TestListHTTPTrafficSpecs(t *testing.T) {
 	nsObj := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1,8 +1,11 @@
 package k8s
 
 import (
+	"context"
 	"reflect"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -11,12 +14,14 @@ import (
 	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
 	"github.com/openservicemesh/osm/pkg/k8s/events"
 	"github.com/openservicemesh/osm/pkg/metricsstore"
+	"github.com/openservicemesh/osm/pkg/tracing"
 )
 
 // Function to filter K8s meta Objects by OSM's isMonitoredNamespace
 func (c *Client) shouldObserve(obj interface{}) bool {
 	switch v := obj.(type) {
-	case *corev1.Namespace, *configv1alpha2.MeshConfig, *configv1alpha2.MeshRootCertificate, *configv1alpha2.ExtensionService:
+	case *corev1.Namespace, *configv1alpha2.MeshConfig, *configv1alpha2.MeshRootCertificate, *configv1alpha2.ExtensionService,
+		*configv1alpha2.TrustDomainFederation, *configv1alpha2.SidecarProfile:
 		return true
 	case metav1.Object:
 		return c.IsMonitoredNamespace(v.GetNamespace())
@@ -58,8 +63,17 @@ func (c *Client) handleEvent(event events.EventType, oldObj, newObj interface{})
 		NewObj: newObj,
 		OldObj: oldObj,
 	}
-	logResourceEvent(msg.Topic(), obj)
 	ns := getNamespace(obj)
+	_, span := tracing.Tracer().Start(context.Background(), "k8s.event", trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("k8s.event.kind", msg.Kind.String()),
+			attribute.String("k8s.event.type", string(msg.Type)),
+			attribute.String("k8s.namespace.name", ns),
+		))
+	defer span.End()
+	msg.Span = tracing.NewEventSpan(span)
+
+	logResourceEvent(msg.Topic(), obj)
 	metricsstore.DefaultMetricsStore.K8sAPIEventCounter.WithLabelValues(msg.Topic(), ns).Inc()
 	c.msgBroker.GetQueue().AddRateLimited(msg)
 }
@@ -68,6 +68,12 @@ func GenericEventRecorder() *EventRecorder {
 	return genericEventRecorder
 }
 
+// NewRecorder returns a Kubernetes event recorder that records the events of any object in the given namespace, or
+// in the namespace of the object if the given namespace is metav1.NamespaceAll
+func NewRecorder(kubeClient kubernetes.Interface, namespace string) record.EventRecorder {
+	return eventRecorder(kubeClient, namespace)
+}
+
 // eventRecorder returns an EventRecorder that can be used to post Kubernetes events
 func eventRecorder(kubeClient kubernetes.Interface, namespace string) record.EventRecorder {
 	eventBroadcaster := record.NewBroadcaster()
@@ -142,6 +148,10 @@ func (e *EventRecorder) FatalEvent(err error, reason string, messageFmt string,
 
 // waitForFatalEvent waits until a fatal event has been seen before a wait timeout
 func (e *EventRecorder) waitForFatalEvent() {
+	if e.watcher == nil {
+		// The recorder is uninitialized, so no event was recorded
+		return
+	}
 	timeout := time.After(fatalEventWaitTimeout)
 
 WaitOnEvent:
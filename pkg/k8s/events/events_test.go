@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	tassert "github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 
@@ -125,6 +126,10 @@ func TestEventKinds(t *testing.T) {
 			obj:          &policyv1alpha1.Retry{},
 			expectedKind: RetryPolicy,
 		},
+		{
+			obj:          &policyv1alpha1.WorkloadEntry{},
+			expectedKind: WorkloadEntry,
+		},
 		{
 			obj:          &corev1.Pod{},
 			expectedKind: Pod,
@@ -138,8 +143,8 @@ func TestEventKinds(t *testing.T) {
 			expectedKind: Service,
 		},
 		{
-			obj:          &corev1.Endpoints{},
-			expectedKind: Endpoint,
+			obj:          &discoveryv1.EndpointSlice{},
+			expectedKind: EndpointSlice,
 		},
 		{
 			obj:          &smiAccess.TrafficTarget{},
@@ -9,11 +9,13 @@ import (
 	smiSpecs "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
 	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 
 	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
 	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/tracing"
 )
 
 var (
@@ -41,6 +43,12 @@ const (
 const (
 	// CertificateRotationFailure signifies that a certificate failed to rotate
 	CertificateRotationFailure = "CertificateRotationFailure"
+
+	// ProxyConfigRolloutHalted signifies that the rollout of a configuration change to proxies was halted
+	ProxyConfigRolloutHalted = "ProxyConfigRolloutHalted"
+
+	// ProxyConfigNACKed signifies that a proxy rejected its configuration
+	ProxyConfigNACKed = "ProxyConfigNACKed"
 )
 
 // PubSubMessage represents a common messages abstraction to pass through the PubSub interface
@@ -49,6 +57,10 @@ type PubSubMessage struct {
 	Type   EventType
 	OldObj interface{}
 	NewObj interface{}
+
+	// Span is the span of the arrival of the event when its processing is traced, a pointer as messages are used as
+	// keys by the workqueue
+	Span *tracing.EventSpan
 }
 
 // Topic returns the PubSub Topic for the given message.
@@ -101,8 +113,8 @@ const (
 	// Pod is a Kind for Kubernetes pod events.
 	Pod Kind = "pod"
 
-	// Endpoint is the Kind for Kubernetes events.
-	Endpoint Kind = "endpoint"
+	// EndpointSlice is the Kind for Kubernetes EndpointSlice events.
+	EndpointSlice Kind = "endpointslice"
 
 	// Namespace is the Kind for Kubernetes namespace events.
 	Namespace Kind = "namespace"
@@ -152,8 +164,17 @@ const (
 	// Telemetry is the Kind for Kubernetes Telemetry events.
 	Telemetry Kind = "telemetry"
 
+	// WorkloadEntry is the Kind for Kubernetes WorkloadEntry events.
+	WorkloadEntry Kind = "workloadentry"
+
 	// ExtensionService is the Kind for Kubernetes ExtensionService events.
 	ExtensionService Kind = "extensionservice"
+
+	// TrustDomainFederation is the Kind for Kubernetes TrustDomainFederation events.
+	TrustDomainFederation Kind = "trustdomainfederation"
+
+	// SidecarProfile is the Kind for Kubernetes SidecarProfile events.
+	SidecarProfile Kind = "sidecarprofile"
 )
 
 // GetKind returns the Kind for the given k8s object.
@@ -161,8 +182,8 @@ func GetKind(obj interface{}) Kind {
 	switch obj.(type) {
 	case *corev1.Pod:
 		return Pod
-	case *corev1.Endpoints:
-		return Endpoint
+	case *discoveryv1.EndpointSlice:
+		return EndpointSlice
 	case *corev1.Namespace:
 		return Namespace
 	case *corev1.Service:
@@ -195,8 +216,14 @@ func GetKind(obj interface{}) Kind {
 		return UpstreamTrafficSetting
 	case *policyv1alpha1.Telemetry:
 		return Telemetry
+	case *policyv1alpha1.WorkloadEntry:
+		return WorkloadEntry
 	case *configv1alpha2.ExtensionService:
 		return ExtensionService
+	case *configv1alpha2.TrustDomainFederation:
+		return TrustDomainFederation
+	case *configv1alpha2.SidecarProfile:
+		return SidecarProfile
 	default:
 		log.Error().Msgf("Unknown kind: %v", obj)
 		return ""
@@ -11,6 +11,7 @@ import (
 	smiTrafficSpecInformers "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/specs/informers/externalversions"
 	smiTrafficSplitClient "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/split/clientset/versioned"
 	smiTrafficSplitInformers "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/split/informers/externalversions"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/client-go/informers"
@@ -36,8 +37,8 @@ const (
 	informerKeyService informerKey = "Service"
 	// informerKeyPod is the informerKey for a Pod informer
 	informerKeyPod informerKey = "Pod"
-	// informerKeyEndpoints is the informerKey for a Endpoints informer
-	informerKeyEndpoints informerKey = "Endpoints"
+	// informerKeyEndpointSlice is the informerKey for an EndpointSlice informer
+	informerKeyEndpointSlice informerKey = "EndpointSlice"
 	// informerKeyServiceAccount is the informerKey for a ServiceAccount informer
 	informerKeyServiceAccount informerKey = "ServiceAccount"
 	// informerKeySecret is the informerKey for a Secret informer
@@ -56,6 +57,10 @@ const (
 	informerKeyMeshConfig informerKey = "MeshConfig"
 	// informerKeyMeshRootCertificate is the informerKey for a MeshRootCertificate informer
 	informerKeyMeshRootCertificate informerKey = "MeshRootCertificate"
+	// informerKeyTrustDomainFederation is the informerKey for a TrustDomainFederation informer
+	informerKeyTrustDomainFederation informerKey = "TrustDomainFederation"
+	// informerKeySidecarProfile is the informerKey for a SidecarProfile informer
+	informerKeySidecarProfile informerKey = "SidecarProfile"
 
 	// informerKeyEgress is the informerKey for a Egress informer
 	informerKeyEgress informerKey = "Egress"
@@ -67,6 +72,8 @@ const (
 	informerKeyRetry informerKey = "Retry"
 	// informerKeyTelemetry lookup identifier
 	informerKeyTelemetry informerKey = "Telemetry"
+	// informerKeyWorkloadEntry is the informerKey for a WorkloadEntry informer
+	informerKeyWorkloadEntry informerKey = "WorkloadEntry"
 	// informerKeyExtensionService is the informerKey for an ExtensionService informer
 	informerKeyExtensionService informerKey = "ExtensionService"
 	// informerKeyServiceImport is the informerKey for a ServiceImport informer
@@ -75,6 +82,11 @@ const (
 	informerKeyServiceExport informerKey = "ServiceExport"
 )
 
+const (
+	// endpointSliceServiceIndex is the name of the index of EndpointSlices by the namespaced name of their service
+	endpointSliceServiceIndex = "service"
+)
+
 const (
 	// DefaultKubeEventResyncInterval is the default resync interval for k8s events
 	// This is set to 0 because we do not need resyncs from k8s client, and have our
@@ -116,7 +128,11 @@ func WithKubeClient(kubeClient kubernetes.Interface, meshName string) ClientOpti
 		c.informers[informerKeyService] = v1api.Services().Informer()
 		c.informers[informerKeyServiceAccount] = v1api.ServiceAccounts().Informer()
 		c.informers[informerKeyPod] = v1api.Pods().Informer()
-		c.informers[informerKeyEndpoints] = v1api.Endpoints().Informer()
+		endpointSliceInformer := informerFactory.Discovery().V1().EndpointSlices().Informer()
+		if err := endpointSliceInformer.AddIndexers(cache.Indexers{endpointSliceServiceIndex: endpointSliceServiceIndexFunc}); err != nil {
+			log.Error().Err(err).Msg("Error adding the service index of EndpointSlices")
+		}
+		c.informers[informerKeyEndpointSlice] = endpointSliceInformer
 		c.informers[informerKeySecret] = secretInformerFactory.Core().V1().Secrets().Informer()
 	}
 }
@@ -149,7 +165,9 @@ func WithConfigClient(configClient configClientset.Interface) ClientOption {
 
 		c.informers[informerKeyMeshConfig] = meshConfiginformerFactory.Config().V1alpha2().MeshConfigs().Informer()
 		c.informers[informerKeyMeshRootCertificate] = mrcInformerFactory.Config().V1alpha2().MeshRootCertificates().Informer()
+		c.informers[informerKeyTrustDomainFederation] = mrcInformerFactory.Config().V1alpha2().TrustDomainFederations().Informer()
 		c.informers[informerKeyExtensionService] = informerFactory.Config().V1alpha2().ExtensionServices().Informer()
+		c.informers[informerKeySidecarProfile] = informerFactory.Config().V1alpha2().SidecarProfiles().Informer()
 	}
 }
 
@@ -164,6 +182,14 @@ func WithMCSClient(client mcsClient.Interface) ClientOption {
 	}
 }
 
+// WithLeaderElection restricts the writes of resource statuses and of MeshRootCertificates to the replica for which the given function returns
+// true, i.e. the elected leader, to avoid conflicting writes from multiple replicas.
+func WithLeaderElection(isLeader func() bool) ClientOption {
+	return func(c *Client) {
+		c.isLeader = isLeader
+	}
+}
+
 // WithPolicyClient sets the policy client for the Client
 func WithPolicyClient(policyClient policyClientset.Interface) ClientOption {
 	return func(c *Client) {
@@ -176,6 +202,7 @@ func WithPolicyClient(policyClient policyClientset.Interface) ClientOption {
 		c.informers[informerKeyUpstreamTrafficSetting] = informerFactory.Policy().V1alpha1().UpstreamTrafficSettings().Informer()
 		c.informers[informerKeyRetry] = informerFactory.Policy().V1alpha1().Retries().Informer()
 		c.informers[informerKeyTelemetry] = informerFactory.Policy().V1alpha1().Telemetries().Informer()
+		c.informers[informerKeyWorkloadEntry] = informerFactory.Policy().V1alpha1().WorkloadEntries().Informer()
 	}
 }
 
@@ -223,6 +250,35 @@ func (c *Client) getByKey(informerKey informerKey, objectKey string) (interface{
 	return informer.GetStore().GetByKey(objectKey)
 }
 
+// byIndex returns the items of the store of the informer indexed by the given informerKey, whose given index
+// matches the given value
+func (c *Client) byIndex(informerKey informerKey, indexName, indexedValue string) []interface{} {
+	informer, ok := c.informers[informerKey]
+	if !ok {
+		return nil
+	}
+
+	items, err := informer.GetIndexer().ByIndex(indexName, indexedValue)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error listing %s items by index %s", informerKey, indexName)
+		return nil
+	}
+	return items
+}
+
+// endpointSliceServiceIndexFunc indexes EndpointSlices by the namespaced name of the service they belong to
+func endpointSliceServiceIndexFunc(obj interface{}) ([]string, error) {
+	slice, ok := obj.(*discoveryv1.EndpointSlice)
+	if !ok {
+		return nil, nil
+	}
+	svcName, ok := slice.Labels[discoveryv1.LabelServiceName]
+	if !ok {
+		return nil, nil
+	}
+	return []string{key(svcName, slice.Namespace)}, nil
+}
+
 // list returns the contents of the store of the informer indexed by the given informerKey
 func (c *Client) list(informerKey informerKey) []interface{} {
 	informer, ok := c.informers[informerKey]
@@ -16,6 +16,7 @@ import (
 	v1alpha4 "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
 	v1alpha20 "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
 	v1 "k8s.io/api/core/v1"
+	v10 "k8s.io/api/discovery/v1"
 	types "k8s.io/apimachinery/pkg/types"
 	cache "k8s.io/client-go/tools/cache"
 	v1alpha10 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
@@ -58,21 +59,6 @@ func (mr *MockControllerMockRecorder) AddMeshRootCertificateEventHandler(arg0 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMeshRootCertificateEventHandler", reflect.TypeOf((*MockController)(nil).AddMeshRootCertificateEventHandler), arg0)
 }
 
-// GetEndpoints mocks base method.
-func (m *MockController) GetEndpoints(arg0, arg1 string) (*v1.Endpoints, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEndpoints", arg0, arg1)
-	ret0, _ := ret[0].(*v1.Endpoints)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// GetEndpoints indicates an expected call of GetEndpoints.
-func (mr *MockControllerMockRecorder) GetEndpoints(arg0, arg1 interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEndpoints", reflect.TypeOf((*MockController)(nil).GetEndpoints), arg0, arg1)
-}
-
 // GetExtensionService mocks base method.
 func (m *MockController) GetExtensionService(arg0 v1alpha1.ExtensionServiceRef) *v1alpha2.ExtensionService {
 	m.ctrl.T.Helper()
@@ -171,6 +157,21 @@ func (mr *MockControllerMockRecorder) GetSecret(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecret", reflect.TypeOf((*MockController)(nil).GetSecret), arg0, arg1)
 }
 
+// GetSidecarConfig mocks base method.
+func (m *MockController) GetSidecarConfig(arg0 string, arg1 map[string]string) (models.SidecarConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSidecarConfig", arg0, arg1)
+	ret0, _ := ret[0].(models.SidecarConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSidecarConfig indicates an expected call of GetSidecarConfig.
+func (mr *MockControllerMockRecorder) GetSidecarConfig(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSidecarConfig", reflect.TypeOf((*MockController)(nil).GetSidecarConfig), arg0, arg1)
+}
+
 // GetService mocks base method.
 func (m *MockController) GetService(arg0, arg1 string) *v1.Service {
 	m.ctrl.T.Helper()
@@ -241,6 +242,20 @@ func (mr *MockControllerMockRecorder) ListEgressPolicies() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEgressPolicies", reflect.TypeOf((*MockController)(nil).ListEgressPolicies))
 }
 
+// ListEndpointSlicesForService mocks base method.
+func (m *MockController) ListEndpointSlicesForService(arg0, arg1 string) []*v10.EndpointSlice {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEndpointSlicesForService", arg0, arg1)
+	ret0, _ := ret[0].([]*v10.EndpointSlice)
+	return ret0
+}
+
+// ListEndpointSlicesForService indicates an expected call of ListEndpointSlicesForService.
+func (mr *MockControllerMockRecorder) ListEndpointSlicesForService(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEndpointSlicesForService", reflect.TypeOf((*MockController)(nil).ListEndpointSlicesForService), arg0, arg1)
+}
+
 // ListHTTPTrafficSpecs mocks base method.
 func (m *MockController) ListHTTPTrafficSpecs() []*v1alpha4.HTTPRouteGroup {
 	m.ctrl.T.Helper()
@@ -397,6 +412,20 @@ func (mr *MockControllerMockRecorder) ListServices() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServices", reflect.TypeOf((*MockController)(nil).ListServices))
 }
 
+// ListSidecarProfiles mocks base method.
+func (m *MockController) ListSidecarProfiles() []*v1alpha2.SidecarProfile {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSidecarProfiles")
+	ret0, _ := ret[0].([]*v1alpha2.SidecarProfile)
+	return ret0
+}
+
+// ListSidecarProfiles indicates an expected call of ListSidecarProfiles.
+func (mr *MockControllerMockRecorder) ListSidecarProfiles() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSidecarProfiles", reflect.TypeOf((*MockController)(nil).ListSidecarProfiles))
+}
+
 // ListTCPTrafficSpecs mocks base method.
 func (m *MockController) ListTCPTrafficSpecs() []*v1alpha4.TCPRoute {
 	m.ctrl.T.Helper()
@@ -453,6 +482,20 @@ func (mr *MockControllerMockRecorder) ListTrafficTargets() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrafficTargets", reflect.TypeOf((*MockController)(nil).ListTrafficTargets))
 }
 
+// ListTrustDomainFederations mocks base method.
+func (m *MockController) ListTrustDomainFederations() []*v1alpha2.TrustDomainFederation {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrustDomainFederations")
+	ret0, _ := ret[0].([]*v1alpha2.TrustDomainFederation)
+	return ret0
+}
+
+// ListTrustDomainFederations indicates an expected call of ListTrustDomainFederations.
+func (mr *MockControllerMockRecorder) ListTrustDomainFederations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrustDomainFederations", reflect.TypeOf((*MockController)(nil).ListTrustDomainFederations))
+}
+
 // ListUpstreamTrafficSettings mocks base method.
 func (m *MockController) ListUpstreamTrafficSettings() []*v1alpha1.UpstreamTrafficSetting {
 	m.ctrl.T.Helper()
@@ -467,6 +510,20 @@ func (mr *MockControllerMockRecorder) ListUpstreamTrafficSettings() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUpstreamTrafficSettings", reflect.TypeOf((*MockController)(nil).ListUpstreamTrafficSettings))
 }
 
+// ListWorkloadEntries mocks base method.
+func (m *MockController) ListWorkloadEntries() []*v1alpha1.WorkloadEntry {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWorkloadEntries")
+	ret0, _ := ret[0].([]*v1alpha1.WorkloadEntry)
+	return ret0
+}
+
+// ListWorkloadEntries indicates an expected call of ListWorkloadEntries.
+func (mr *MockControllerMockRecorder) ListWorkloadEntries() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkloadEntries", reflect.TypeOf((*MockController)(nil).ListWorkloadEntries))
+}
+
 // UpdateIngressBackendStatus mocks base method.
 func (m *MockController) UpdateIngressBackendStatus(arg0 *v1alpha1.IngressBackend) (*v1alpha1.IngressBackend, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,155 @@
+package k8s
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	"github.com/openservicemesh/osm/pkg/models"
+)
+
+// sidecarProfileLevel is the specificity of a SidecarProfile matching a pod. More specific profiles take precedence.
+type sidecarProfileLevel int
+
+const (
+	// sidecarProfileMeshLevel is the level of the profiles of the OSM namespace
+	sidecarProfileMeshLevel sidecarProfileLevel = iota
+
+	// sidecarProfileNamespaceLevel is the level of the profiles of the pod's namespace without a pod selector
+	sidecarProfileNamespaceLevel
+
+	// sidecarProfileWorkloadLevel is the level of the profiles of the pod's namespace with a pod selector
+	sidecarProfileWorkloadLevel
+)
+
+// ResolveSidecarConfig returns the effective sidecar configuration of the pods with the given labels in the given
+// namespace. The SidecarProfile taking precedence among the profiles selecting the pods overrides the settings of the
+// MeshConfig, in the following order of precedence:
+// 1. profiles of the pod's namespace with a pod selector matching the pod
+// 2. profiles of the pod's namespace without a pod selector
+// 3. profiles of the OSM namespace with a namespace selector and a pod selector matching the pod, when set
+// Among the profiles of the same level, the profile with the highest priority takes precedence, followed by the
+// profile whose name sorts first.
+func ResolveSidecarConfig(meshConfig configv1alpha2.MeshConfig, profiles []*configv1alpha2.SidecarProfile, osmNamespace string, namespace *corev1.Namespace, podLabels map[string]string) (models.SidecarConfig, error) {
+	config := models.SidecarConfig{
+		Sidecar: meshConfig.Spec.Sidecar,
+	}
+
+	profile := selectSidecarProfile(profiles, osmNamespace, namespace, podLabels)
+	if profile == nil {
+		return config, nil
+	}
+
+	sidecar, err := applySidecarProfile(meshConfig.Spec.Sidecar, profile)
+	if err != nil {
+		return config, fmt.Errorf("Invalid SidecarProfile %s/%s: %w", profile.Namespace, profile.Name, err)
+	}
+	config.Sidecar = sidecar
+	if profile.Spec.Concurrency != nil {
+		config.Concurrency = *profile.Spec.Concurrency
+	}
+	config.Profile = profile
+
+	return config, nil
+}
+
+// selectSidecarProfile returns the SidecarProfile taking precedence among the profiles selecting the pods with the
+// given labels in the given namespace, or nil when no profile selects them
+func selectSidecarProfile(profiles []*configv1alpha2.SidecarProfile, osmNamespace string, namespace *corev1.Namespace, podLabels map[string]string) *configv1alpha2.SidecarProfile {
+	var namespaceName string
+	var namespaceLabels map[string]string
+	if namespace != nil {
+		namespaceName, namespaceLabels = namespace.Name, namespace.Labels
+	}
+
+	type candidate struct {
+		profile *configv1alpha2.SidecarProfile
+		level   sidecarProfileLevel
+	}
+	var candidates []candidate
+
+	for _, profile := range profiles {
+		var level sidecarProfileLevel
+		switch {
+		case profile.Namespace == namespaceName && profile.Spec.PodSelector != nil:
+			level = sidecarProfileWorkloadLevel
+		case profile.Namespace == namespaceName:
+			level = sidecarProfileNamespaceLevel
+		case profile.Namespace == osmNamespace:
+			level = sidecarProfileMeshLevel
+			if !matchesLabelSelector(profile, profile.Spec.NamespaceSelector, namespaceLabels) {
+				continue
+			}
+		default:
+			// Profiles only apply to the pods of other namespaces when they belong to the OSM namespace
+			continue
+		}
+		if !matchesLabelSelector(profile, profile.Spec.PodSelector, podLabels) {
+			continue
+		}
+		candidates = append(candidates, candidate{profile: profile, level: level})
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].level != candidates[j].level {
+			return candidates[i].level > candidates[j].level
+		}
+		if candidates[i].profile.Spec.Priority != candidates[j].profile.Spec.Priority {
+			return candidates[i].profile.Spec.Priority > candidates[j].profile.Spec.Priority
+		}
+		return candidates[i].profile.Name < candidates[j].profile.Name
+	})
+
+	return candidates[0].profile
+}
+
+// matchesLabelSelector returns whether the given labels match the given selector of the given profile. A nil
+// selector matches all labels, and an invalid selector matches none.
+func matchesLabelSelector(profile *configv1alpha2.SidecarProfile, selector *metav1.LabelSelector, set map[string]string) bool {
+	if selector == nil {
+		return true
+	}
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		log.Error().Err(err).Msgf("Ignoring SidecarProfile %s/%s with an invalid label selector", profile.Namespace, profile.Name)
+		return false
+	}
+	return sel.Matches(labels.Set(set))
+}
+
+// applySidecarProfile returns the given sidecar section of the MeshConfig with the settings overridden by the given
+// profile. The settings are merged like a JSON merge patch: objects are merged, and lists are replaced.
+func applySidecarProfile(sidecar configv1alpha2.SidecarSpec, profile *configv1alpha2.SidecarProfile) (configv1alpha2.SidecarSpec, error) {
+	if profile.Spec.Sidecar == nil || len(profile.Spec.Sidecar.Raw) == 0 {
+		return sidecar, nil
+	}
+
+	original, err := json.Marshal(sidecar)
+	if err != nil {
+		return sidecar, err
+	}
+	merged, err := strategicpatch.StrategicMergePatch(original, profile.Spec.Sidecar.Raw, configv1alpha2.SidecarSpec{})
+	if err != nil {
+		return sidecar, err
+	}
+
+	var result configv1alpha2.SidecarSpec
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&result); err != nil {
+		return sidecar, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,169 @@
+package k8s
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+func TestResolveSidecarConfig(t *testing.T) {
+	meshConfig := configv1alpha2.MeshConfig{
+		Spec: configv1alpha2.MeshConfigSpec{
+			Sidecar: configv1alpha2.SidecarSpec{
+				LogLevel:              "error",
+				EnvoyImage:            "envoy:v1",
+				TLSMinProtocolVersion: "TLSv1_2",
+				CipherSuites:          []string{"A", "B"},
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("1"),
+					},
+				},
+			},
+		},
+	}
+
+	newProfile := func(namespace, name string, spec configv1alpha2.SidecarProfileSpec) *configv1alpha2.SidecarProfile {
+		return &configv1alpha2.SidecarProfile{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec:       spec,
+		}
+	}
+	sidecar := func(raw string) *runtime.RawExtension {
+		return &runtime.RawExtension{Raw: []byte(raw)}
+	}
+
+	meshProfile := newProfile(tests.OsmNamespace, "mesh", configv1alpha2.SidecarProfileSpec{
+		Sidecar: sidecar(`{"logLevel":"warn"}`),
+	})
+	prodProfile := newProfile(tests.OsmNamespace, "prod", configv1alpha2.SidecarProfileSpec{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+		Priority:          1,
+		Sidecar:           sidecar(`{"logLevel":"critical"}`),
+	})
+	namespaceProfile := newProfile("app", "namespace", configv1alpha2.SidecarProfileSpec{
+		Sidecar: sidecar(`{"logLevel":"info","cipherSuites":["C"]}`),
+	})
+	workloadProfile := newProfile("app", "workload", configv1alpha2.SidecarProfileSpec{
+		PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		Sidecar:     sidecar(`{"logLevel":"debug","tlsMinProtocolVersion":"TLSv1_3","resources":{"limits":{"memory":"1Gi"}}}`),
+		Concurrency: pointer.Int32(2),
+	})
+	otherNamespaceProfile := newProfile("other", "other", configv1alpha2.SidecarProfileSpec{
+		Sidecar: sidecar(`{"logLevel":"trace"}`),
+	})
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "app"}}
+	prodNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "app", Labels: map[string]string{"env": "prod"}}}
+
+	testCases := []struct {
+		name            string
+		profiles        []*configv1alpha2.SidecarProfile
+		namespace       *corev1.Namespace
+		podLabels       map[string]string
+		expectedProfile *configv1alpha2.SidecarProfile
+		expectedSidecar configv1alpha2.SidecarSpec
+		expectedConc    int32
+		expectErr       bool
+	}{
+		{
+			name:            "no profile",
+			namespace:       namespace,
+			expectedSidecar: meshConfig.Spec.Sidecar,
+		},
+		{
+			name:            "profile of another namespace",
+			profiles:        []*configv1alpha2.SidecarProfile{otherNamespaceProfile},
+			namespace:       namespace,
+			expectedSidecar: meshConfig.Spec.Sidecar,
+		},
+		{
+			name:            "mesh profile",
+			profiles:        []*configv1alpha2.SidecarProfile{otherNamespaceProfile, meshProfile},
+			namespace:       namespace,
+			expectedProfile: meshProfile,
+			expectedSidecar: func() configv1alpha2.SidecarSpec {
+				s := *meshConfig.Spec.Sidecar.DeepCopy()
+				s.LogLevel = "warn"
+				return s
+			}(),
+		},
+		{
+			name:            "mesh profile not selecting the namespace",
+			profiles:        []*configv1alpha2.SidecarProfile{prodProfile},
+			namespace:       namespace,
+			expectedSidecar: meshConfig.Spec.Sidecar,
+		},
+		{
+			name:            "mesh profile with the highest priority",
+			profiles:        []*configv1alpha2.SidecarProfile{meshProfile, prodProfile},
+			namespace:       prodNamespace,
+			expectedProfile: prodProfile,
+			expectedSidecar: func() configv1alpha2.SidecarSpec {
+				s := *meshConfig.Spec.Sidecar.DeepCopy()
+				s.LogLevel = "critical"
+				return s
+			}(),
+		},
+		{
+			name:            "namespace profile takes precedence over mesh profiles",
+			profiles:        []*configv1alpha2.SidecarProfile{meshProfile, prodProfile, namespaceProfile},
+			namespace:       prodNamespace,
+			podLabels:       map[string]string{"app": "api"},
+			expectedProfile: namespaceProfile,
+			expectedSidecar: func() configv1alpha2.SidecarSpec {
+				s := *meshConfig.Spec.Sidecar.DeepCopy()
+				s.LogLevel = "info"
+				s.CipherSuites = []string{"C"}
+				return s
+			}(),
+		},
+		{
+			name:            "workload profile takes precedence over namespace profiles",
+			profiles:        []*configv1alpha2.SidecarProfile{meshProfile, namespaceProfile, workloadProfile},
+			namespace:       namespace,
+			podLabels:       map[string]string{"app": "web"},
+			expectedProfile: workloadProfile,
+			expectedSidecar: func() configv1alpha2.SidecarSpec {
+				s := *meshConfig.Spec.Sidecar.DeepCopy()
+				s.LogLevel = "debug"
+				s.TLSMinProtocolVersion = "TLSv1_3"
+				s.Resources.Limits[corev1.ResourceMemory] = resource.MustParse("1Gi")
+				return s
+			}(),
+			expectedConc: 2,
+		},
+		{
+			name:            "invalid profile",
+			profiles:        []*configv1alpha2.SidecarProfile{newProfile("app", "invalid", configv1alpha2.SidecarProfileSpec{Sidecar: sidecar(`{"unknown":"field"}`)})},
+			namespace:       namespace,
+			expectedSidecar: meshConfig.Spec.Sidecar,
+			expectErr:       true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			actual, err := ResolveSidecarConfig(meshConfig, tc.profiles, tests.OsmNamespace, tc.namespace, tc.podLabels)
+			assert.Equal(tc.expectErr, err != nil)
+			assert.Equal(tc.expectedProfile, actual.Profile)
+			assert.Equal(tc.expectedConc, actual.Concurrency)
+			assert.Equal(tc.expectedSidecar.LogLevel, actual.Sidecar.LogLevel)
+			assert.Equal(tc.expectedSidecar.EnvoyImage, actual.Sidecar.EnvoyImage)
+			assert.Equal(tc.expectedSidecar.TLSMinProtocolVersion, actual.Sidecar.TLSMinProtocolVersion)
+			assert.Equal(tc.expectedSidecar.CipherSuites, actual.Sidecar.CipherSuites)
+			assert.True(tc.expectedSidecar.Resources.Limits.Cpu().Equal(*actual.Sidecar.Resources.Limits.Cpu()))
+			assert.True(tc.expectedSidecar.Resources.Limits.Memory().Equal(*actual.Sidecar.Resources.Limits.Memory()))
+		})
+	}
+}
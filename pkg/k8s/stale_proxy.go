@@ -0,0 +1,56 @@
+package k8s
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/models"
+)
+
+// GetStaleProxyReasons returns the reasons the Envoy sidecar of the given pod differs from the sidecar the given
+// version of OSM injects with the given sidecar settings, or nil when the sidecar is up to date or the pod has no
+// Envoy sidecar. The bootstrapVersion is the version of OSM which created the bootstrap config of the sidecar, and is
+// not compared when unknown. Envoy images are not compared when the settings have no image for the OS of the pod.
+func GetStaleProxyReasons(pod *corev1.Pod, sidecar configv1alpha2.SidecarSpec, bootstrapVersion, osmVersion string) []models.StaleProxyReason {
+	envoyContainer := getEnvoyContainer(pod)
+	if envoyContainer == nil {
+		return nil
+	}
+
+	var reasons []models.StaleProxyReason
+
+	envoyImage := sidecar.EnvoyImage
+	if strings.EqualFold(pod.Spec.NodeSelector["kubernetes.io/os"], constants.OSWindows) {
+		envoyImage = sidecar.EnvoyWindowsImage
+	}
+	if envoyImage != "" && envoyContainer.Image != envoyImage {
+		reasons = append(reasons, models.StaleProxyReasonEnvoyImage)
+	}
+
+	if bootstrapVersion != "" && bootstrapVersion != osmVersion {
+		reasons = append(reasons, models.StaleProxyReasonBootstrapVersion)
+	}
+
+	// Pods injected before the injector version was recorded are stale as well
+	if pod.Annotations[constants.InjectorVersionAnnotation] != osmVersion {
+		reasons = append(reasons, models.StaleProxyReasonInjectorVersion)
+	}
+
+	return reasons
+}
+
+// getEnvoyContainer returns the Envoy sidecar container of the given pod, injected either as a container or as a
+// native sidecar container, or nil when the pod has none
+func getEnvoyContainer(pod *corev1.Pod) *corev1.Container {
+	for _, containers := range [][]corev1.Container{pod.Spec.Containers, pod.Spec.InitContainers} {
+		for i := range containers {
+			if containers[i].Name == constants.EnvoyContainerName {
+				return &containers[i]
+			}
+		}
+	}
+	return nil
+}
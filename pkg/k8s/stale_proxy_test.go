@@ -0,0 +1,107 @@
+package k8s
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/models"
+)
+
+func TestGetStaleProxyReasons(t *testing.T) {
+	sidecar := configv1alpha2.SidecarSpec{
+		EnvoyImage:        "envoy:v2",
+		EnvoyWindowsImage: "envoy-windows:v2",
+	}
+
+	newPod := func(injectorVersion string, nodeOS string, containers, initContainers []corev1.Container) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "app"},
+			Spec: corev1.PodSpec{
+				Containers:     containers,
+				InitContainers: initContainers,
+			},
+		}
+		if injectorVersion != "" {
+			pod.Annotations = map[string]string{constants.InjectorVersionAnnotation: injectorVersion}
+		}
+		if nodeOS != "" {
+			pod.Spec.NodeSelector = map[string]string{"kubernetes.io/os": nodeOS}
+		}
+		return pod
+	}
+	app := corev1.Container{Name: "app", Image: "app:v1"}
+	envoy := func(image string) corev1.Container {
+		return corev1.Container{Name: constants.EnvoyContainerName, Image: image}
+	}
+
+	testCases := []struct {
+		name             string
+		pod              *corev1.Pod
+		sidecar          configv1alpha2.SidecarSpec
+		bootstrapVersion string
+		expected         []models.StaleProxyReason
+	}{
+		{
+			name:             "up to date",
+			pod:              newPod("v1.3.0", "", []corev1.Container{app, envoy("envoy:v2")}, nil),
+			sidecar:          sidecar,
+			bootstrapVersion: "v1.3.0",
+		},
+		{
+			name:     "no envoy sidecar",
+			pod:      newPod("", "", []corev1.Container{app}, nil),
+			sidecar:  sidecar,
+			expected: nil,
+		},
+		{
+			name:             "envoy image changed",
+			pod:              newPod("v1.3.0", "", []corev1.Container{app, envoy("envoy:v1")}, nil),
+			sidecar:          sidecar,
+			bootstrapVersion: "v1.3.0",
+			expected:         []models.StaleProxyReason{models.StaleProxyReasonEnvoyImage},
+		},
+		{
+			name:             "native sidecar envoy image changed",
+			pod:              newPod("v1.3.0", "", []corev1.Container{app}, []corev1.Container{envoy("envoy:v1")}),
+			sidecar:          sidecar,
+			bootstrapVersion: "v1.3.0",
+			expected:         []models.StaleProxyReason{models.StaleProxyReasonEnvoyImage},
+		},
+		{
+			name:             "windows envoy image",
+			pod:              newPod("v1.3.0", constants.OSWindows, []corev1.Container{app, envoy("envoy-windows:v2")}, nil),
+			sidecar:          sidecar,
+			bootstrapVersion: "v1.3.0",
+		},
+		{
+			name:             "envoy image unknown",
+			pod:              newPod("v1.3.0", "", []corev1.Container{app, envoy("envoy:v1")}, nil),
+			bootstrapVersion: "v1.3.0",
+		},
+		{
+			name:             "bootstrap and injector of another version",
+			pod:              newPod("v1.2.0", "", []corev1.Container{app, envoy("envoy:v2")}, nil),
+			sidecar:          sidecar,
+			bootstrapVersion: "v1.2.0",
+			expected:         []models.StaleProxyReason{models.StaleProxyReasonBootstrapVersion, models.StaleProxyReasonInjectorVersion},
+		},
+		{
+			name:     "injector version not recorded and bootstrap version unknown",
+			pod:      newPod("", "", []corev1.Container{app, envoy("envoy:v1")}, nil),
+			sidecar:  sidecar,
+			expected: []models.StaleProxyReason{models.StaleProxyReasonEnvoyImage, models.StaleProxyReasonInjectorVersion},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expected, GetStaleProxyReasons(tc.pod, tc.sidecar, tc.bootstrapVersion, "v1.3.0"))
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -58,6 +59,10 @@ type Client struct {
 	msgBroker      *messaging.Broker
 	osmNamespace   string
 	meshConfigName string
+
+	// isLeader returns whether the replica is the leader, which alone writes resource statuses and
+	// MeshRootCertificates. It is nil when the replica runs without leader election.
+	isLeader func() bool
 }
 
 // Controller is the controller interface for K8s services
@@ -93,11 +98,21 @@ type Controller interface {
 	// ListPods returns a list of pods part of the mesh
 	ListPods() []*corev1.Pod
 
-	// GetEndpoints returns the endpoints for a given service, if found
-	GetEndpoints(name, namespace string) (*corev1.Endpoints, error)
+	// ListEndpointSlicesForService returns the EndpointSlices of the given service
+	ListEndpointSlicesForService(name, namespace string) []*discoveryv1.EndpointSlice
 
 	// ListTelemetryPolices returns all the telemetry policies.
 	ListTelemetryPolicies() []*policyv1alpha1.Telemetry
+
+	// ListWorkloadEntries returns the WorkloadEntries of the workloads outside of Kubernetes that are part of the mesh
+	ListWorkloadEntries() []*policyv1alpha1.WorkloadEntry
+
+	// ListSidecarProfiles returns the SidecarProfiles of the OSM namespace and of the monitored namespaces
+	ListSidecarProfiles() []*configv1alpha2.SidecarProfile
+
+	// GetSidecarConfig returns the effective sidecar configuration of the pods with the given labels in the given
+	// namespace, resolved from the MeshConfig and the SidecarProfiles selecting them
+	GetSidecarConfig(namespace string, podLabels map[string]string) (models.SidecarConfig, error)
 }
 
 // PassthroughInterface is the interface for methods that are implemented by the k8s.Client, but are not considered
@@ -118,6 +133,9 @@ type PassthroughInterface interface {
 	AddMeshRootCertificateEventHandler(handler cache.ResourceEventHandler) error
 
 	ListMeshRootCertificates() ([]*configv1alpha2.MeshRootCertificate, error)
+
+	// ListTrustDomainFederations returns the foreign trust domains federated with the mesh
+	ListTrustDomainFederations() []*configv1alpha2.TrustDomainFederation
 	UpdateMeshRootCertificate(obj *configv1alpha2.MeshRootCertificate) (*configv1alpha2.MeshRootCertificate, error)
 	UpdateMeshRootCertificateStatus(obj *configv1alpha2.MeshRootCertificate) (*configv1alpha2.MeshRootCertificate, error)
 	GetOSMNamespace() string
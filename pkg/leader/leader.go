@@ -0,0 +1,144 @@
+// Package leader implements leader election among the replicas of a control plane component, so that the duties
+// that must only be performed by a single replica, such as writing resource statuses, run on the elected leader.
+package leader
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var log = logger.New("leader-election")
+
+const (
+	// defaultLeaseDuration is the duration for which non-leader replicas wait before attempting to acquire the lease
+	defaultLeaseDuration = 15 * time.Second
+
+	// defaultRenewDeadline is the duration for which the leader retries renewing the lease before giving it up
+	defaultRenewDeadline = 10 * time.Second
+
+	// defaultRetryPeriod is the duration replicas wait between attempts to acquire or renew the lease
+	defaultRetryPeriod = 2 * time.Second
+)
+
+// Duty is a function run by the leader for as long as it holds the lease. The given context is canceled when the
+// leadership is lost.
+type Duty func(ctx context.Context)
+
+// Elector elects a leader among the replicas sharing a Lease, and runs the registered duties on the leader
+type Elector struct {
+	kubeClient kubernetes.Interface
+	namespace  string
+	name       string
+	identity   string
+
+	leaseDuration time.Duration
+	renewDeadline time.Duration
+	retryPeriod   time.Duration
+
+	leading atomic.Bool
+
+	mu     sync.Mutex
+	duties []Duty
+}
+
+// NewElector returns an Elector for the Lease with the given namespace and name, on which the calling replica is
+// identified by the given identity, typically its pod name.
+func NewElector(kubeClient kubernetes.Interface, namespace, name, identity string) *Elector {
+	return &Elector{
+		kubeClient:    kubeClient,
+		namespace:     namespace,
+		name:          name,
+		identity:      identity,
+		leaseDuration: defaultLeaseDuration,
+		renewDeadline: defaultRenewDeadline,
+		retryPeriod:   defaultRetryPeriod,
+	}
+}
+
+// IsLeader returns whether the calling replica currently holds the lease
+func (e *Elector) IsLeader() bool {
+	return e.leading.Load()
+}
+
+// AddDuty registers a duty to run whenever the calling replica becomes the leader. Duties must be registered before
+// Run is called.
+func (e *Elector) AddDuty(duty Duty) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.duties = append(e.duties, duty)
+}
+
+// Run campaigns for the lease until the given context is canceled. A replica losing the lease campaigns for it again.
+func (e *Elector) Run(ctx context.Context) error {
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Namespace: e.namespace,
+			Name:      e.name,
+		},
+		Client: e.kubeClient.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: e.identity,
+		},
+	}
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   e.leaseDuration,
+		RenewDeadline:   e.renewDeadline,
+		RetryPeriod:     e.retryPeriod,
+		ReleaseOnCancel: true,
+		Name:            e.name,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: e.lead,
+			OnStoppedLeading: func() {
+				e.leading.Store(false)
+				log.Info().Msgf("%s stopped leading %s/%s", e.identity, e.namespace, e.name)
+			},
+			OnNewLeader: func(identity string) {
+				log.Info().Msgf("%s is the leader of %s/%s", identity, e.namespace, e.name)
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Run returns when the lease is lost or the context is canceled
+		elector.Run(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+	}
+}
+
+// lead runs the registered duties until the given context is canceled
+func (e *Elector) lead(ctx context.Context) {
+	log.Info().Msgf("%s started leading %s/%s", e.identity, e.namespace, e.name)
+	e.leading.Store(true)
+
+	e.mu.Lock()
+	duties := e.duties
+	e.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, duty := range duties {
+		wg.Add(1)
+		go func(duty Duty) {
+			defer wg.Done()
+			duty(ctx)
+		}(duty)
+	}
+	wg.Wait()
+}
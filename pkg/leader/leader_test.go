@@ -0,0 +1,80 @@
+package leader
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestElector(kubeClient *fake.Clientset, identity string, duty Duty) *Elector {
+	e := NewElector(kubeClient, "osm-system", "osm-controller-leader", identity)
+	e.leaseDuration = time.Second
+	e.renewDeadline = 500 * time.Millisecond
+	e.retryPeriod = 100 * time.Millisecond
+	e.AddDuty(duty)
+	return e
+}
+
+func TestElector(t *testing.T) {
+	assert := tassert.New(t)
+	kubeClient := fake.NewSimpleClientset()
+
+	started := make(chan string, 2)
+	stopped := make(chan string, 2)
+	dutyFor := func(identity string) Duty {
+		return func(ctx context.Context) {
+			started <- identity
+			<-ctx.Done()
+			stopped <- identity
+		}
+	}
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	defer cancel1()
+	e1 := newTestElector(kubeClient, "replica-1", dutyFor("replica-1"))
+	done1 := make(chan error)
+	go func() {
+		done1 <- e1.Run(ctx1)
+	}()
+
+	// The first replica acquires the lease
+	assert.Equal("replica-1", <-started)
+	assert.True(e1.IsLeader())
+
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+	e2 := newTestElector(kubeClient, "replica-2", dutyFor("replica-2"))
+	done2 := make(chan error)
+	go func() {
+		done2 <- e2.Run(ctx2)
+	}()
+
+	// The second replica does not run its duties while the first one leads
+	select {
+	case identity := <-started:
+		assert.Failf("unexpected leader", "%s started leading", identity)
+	case <-time.After(2 * time.Second):
+	}
+	assert.False(e2.IsLeader())
+
+	// Stopping the first replica releases the lease, which the second replica acquires
+	cancel1()
+	assert.Equal("replica-1", <-stopped)
+	assert.NoError(<-done1)
+	assert.False(e1.IsLeader())
+
+	select {
+	case identity := <-started:
+		assert.Equal("replica-2", identity)
+	case <-time.After(5 * time.Second):
+		assert.Fail("second replica did not acquire the lease")
+	}
+	assert.True(e2.IsLeader())
+
+	cancel2()
+	assert.Equal("replica-2", <-stopped)
+	assert.NoError(<-done2)
+}
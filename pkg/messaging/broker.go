@@ -1,11 +1,14 @@
 package messaging
 
 import (
+	"context"
 	"fmt"
 	"sync/atomic"
 	"time"
 
 	"github.com/cskr/pubsub"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/workqueue"
@@ -15,6 +18,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/k8s/events"
 	"github.com/openservicemesh/osm/pkg/metricsstore"
+	"github.com/openservicemesh/osm/pkg/tracing"
 )
 
 const (
@@ -32,7 +36,8 @@ func NewBroker(stopCh <-chan struct{}) *Broker {
 	b := &Broker{
 		queue:             workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
 		proxyUpdatePubSub: pubsub.New(0),
-		proxyUpdateCh:     make(chan string),
+		proxyUpdateCh:     make(chan proxyUpdate),
+		dependencies:      newDependencyIndex(),
 		kubeEventPubSub:   pubsub.New(0),
 		stop:              stopCh,
 	}
@@ -117,7 +122,8 @@ func (b *Broker) runWorkqueueProcessor() {
 // 1. Sliding window timer that resets when a proxy update event is received
 // 2. Max window timer that caps the max duration a sliding window can be reset to
 // When either of the above timers expire, the proxy update event is published
-// on the dedicated pub-sub instance.
+// on the dedicated pub-sub instance, either as a broadcast or to the proxies
+// that depend on the resources affected by the batched events.
 func (b *Broker) runProxyUpdateDispatcher() {
 	// batchTimer and maxTimer are updated by the dispatcher routine
 	// when events are processed and timeouts expire. They are initialized
@@ -142,6 +148,12 @@ func (b *Broker) runProxyUpdateDispatcher() {
 	dispatchPending := false
 	batchCount := 0 // number of proxy update events batched per dispatch
 
+	// broadcastPending indicates whether any of the batched events must update all proxies,
+	// otherwise pendingDeps holds the dependencies affected by the batched events.
+	broadcastPending := false
+	pendingDeps := make(map[Dependency]struct{})
+	pendingUpdate := &ProxyUpdate{}
+
 	var msgName string
 	for {
 		select {
@@ -150,7 +162,14 @@ func (b *Broker) runProxyUpdateDispatcher() {
 				log.Warn().Msgf("Proxy update event chan closed, exiting dispatcher")
 				return
 			}
-			msgName = e
+			msgName = e.name
+			if e.deps == nil {
+				broadcastPending = true
+			}
+			for _, dep := range e.deps {
+				pendingDeps[dep] = struct{}{}
+			}
+			pendingUpdate.addTrigger(e.trigger)
 
 			if !dispatchPending {
 				// No proxy update events are pending send on the pub-sub.
@@ -185,12 +204,13 @@ func (b *Broker) runProxyUpdateDispatcher() {
 				<-maxTimer.C
 			}
 			maxTimer.Reset(noTimeout)
-			b.proxyUpdatePubSub.Pub(msgName, ProxyUpdateTopic)
-			atomic.AddUint64(&b.totalDispatchedProxyEventCount, 1)
-			metricsstore.DefaultMetricsStore.ProxyBroadcastEventCount.Inc()
+			b.dispatchProxyUpdate(msgName, *pendingUpdate, broadcastPending, pendingDeps)
 			log.Trace().Msgf("Sliding window expired, msg kind %s, batch size %d", msgName, batchCount)
 			dispatchPending = false
 			batchCount = 0
+			broadcastPending = false
+			pendingDeps = make(map[Dependency]struct{})
+			pendingUpdate = &ProxyUpdate{}
 
 		case <-maxTimer.C:
 			maxTimer.Reset(noTimeout) // 'maxTimer' drained in this case statement
@@ -200,12 +220,13 @@ func (b *Broker) runProxyUpdateDispatcher() {
 				<-slidingTimer.C
 			}
 			slidingTimer.Reset(noTimeout)
-			b.proxyUpdatePubSub.Pub(msgName, ProxyUpdateTopic)
-			atomic.AddUint64(&b.totalDispatchedProxyEventCount, 1)
-			metricsstore.DefaultMetricsStore.ProxyBroadcastEventCount.Inc()
+			b.dispatchProxyUpdate(msgName, *pendingUpdate, broadcastPending, pendingDeps)
 			log.Trace().Msgf("Max window expired, msg kind %s, batch size %d", msgName, batchCount)
 			dispatchPending = false
 			batchCount = 0
+			broadcastPending = false
+			pendingDeps = make(map[Dependency]struct{})
+			pendingUpdate = &ProxyUpdate{}
 
 		case <-b.stop:
 			log.Info().Msg("Proxy update dispatcher received stop signal, exiting")
@@ -214,6 +235,49 @@ func (b *Broker) runProxyUpdateDispatcher() {
 	}
 }
 
+// dispatchProxyUpdate publishes a batched proxy update to all proxies if broadcast is set, otherwise
+// only to the proxies that depend on any of the given dependencies.
+func (b *Broker) dispatchProxyUpdate(msgName string, update ProxyUpdate, broadcast bool, deps map[Dependency]struct{}) {
+	// The dispatch descends from the first event of the batch, and is linked to the others
+	var parent trace.SpanContext
+	spanContexts := make([]trace.SpanContext, 0, len(update.Triggers))
+	for _, trigger := range update.Triggers {
+		if !parent.IsValid() {
+			parent = trigger.SpanContext
+		}
+		spanContexts = append(spanContexts, trigger.SpanContext)
+	}
+	_, span := tracing.Start(trace.ContextWithSpanContext(context.Background(), parent), "broker.dispatch",
+		trace.WithLinks(tracing.Links(spanContexts...)...),
+		trace.WithAttributes(
+			attribute.Int("osm.dispatch.event_count", update.TriggerCount),
+			attribute.Bool("osm.dispatch.broadcast", broadcast),
+		))
+	defer span.End()
+	update.SpanContext = span.SpanContext()
+
+	if broadcast {
+		b.proxyUpdatePubSub.Pub(update, ProxyUpdateTopic)
+		atomic.AddUint64(&b.totalDispatchedProxyEventCount, 1)
+		metricsstore.DefaultMetricsStore.ProxyBroadcastEventCount.Inc()
+		return
+	}
+
+	uuids := b.dependencies.lookup(deps)
+	if len(uuids) == 0 {
+		log.Trace().Msgf("No proxies depend on msg kind %s, skipping dispatch", msgName)
+		return
+	}
+	topics := make([]string, 0, len(uuids))
+	for uuid := range uuids {
+		topics = append(topics, GetPubSubTopicForProxyUUID(uuid))
+	}
+	span.SetAttributes(attribute.Int("osm.dispatch.proxy_count", len(topics)))
+	b.proxyUpdatePubSub.Pub(update, topics...)
+	atomic.AddUint64(&b.totalDispatchedProxyEventCount, 1)
+	log.Trace().Msgf("Dispatched msg kind %s to %d proxies", msgName, len(topics))
+}
+
 // BroadcastProxyUpdate enqueues a broadcast to update all proxies.
 func (b *Broker) BroadcastProxyUpdate() {
 	b.queue.Add(events.PubSubMessage{Kind: events.ProxyUpdate, Type: events.Added})
@@ -224,22 +288,33 @@ func (b *Broker) BroadcastProxyUpdate() {
 // 1. If the event must update a proxy, it publishes a proxy update message
 // 2. Processes other internal control plane events
 // 3. Updates metrics associated with the event
-func (b *Broker) processEvent(msg events.PubSubMessage) {
+func (b *Broker) processEvent(ctx context.Context, msg events.PubSubMessage) {
 	log.Trace().Msgf("Processing msg kind: %s", msg.Kind)
 	// Update proxies if applicable
 	publish, uuid := shouldPublish(msg)
 
+	_, span := tracing.Start(ctx, "broker.processEvent", trace.WithAttributes(
+		attribute.String("osm.event.topic", msg.Topic()),
+		attribute.Bool("osm.event.updates_proxies", publish),
+	))
+	defer span.End()
+
 	if publish {
 		log.Trace().Msgf("Msg kind %s will update proxies", msg.Kind)
 		atomic.AddUint64(&b.totalQProxyEventCount, 1)
+		trigger := newUpdateTrigger(msg)
+		trigger.SpanContext = span.SpanContext()
 		if uuid == "" {
-			// Pass the broadcast event to the dispatcher routine, that coalesces
-			// multiple broadcasts received in close proximity.
-			b.proxyUpdateCh <- msg.Topic()
+			// Pass the event to the dispatcher routine, that coalesces multiple
+			// events received in close proximity. The event is only published to the
+			// proxies that depend on the resources it affects, if they can be determined.
+			b.proxyUpdateCh <- proxyUpdate{name: msg.Topic(), deps: getDependencies(msg), trigger: trigger}
 		} else {
 			// This is not a broadcast event, so it cannot be coalesced with
 			// other events as the event is specific to one or more proxies.
-			b.proxyUpdatePubSub.Pub(msg, GetPubSubTopicForProxyUUID(uuid))
+			update := ProxyUpdate{SpanContext: trigger.SpanContext}
+			update.addTrigger(trigger)
+			b.proxyUpdatePubSub.Pub(update, GetPubSubTopicForProxyUUID(uuid))
 			atomic.AddUint64(&b.totalDispatchedProxyEventCount, 1)
 		}
 	}
@@ -277,10 +352,10 @@ func (b *Broker) Unsub(pubSub *pubsub.PubSub, ch chan interface{}) {
 func shouldPublish(msg events.PubSubMessage) (bool, string) {
 	switch msg.Kind {
 	case
-		events.Endpoint, events.Ingress,
+		events.EndpointSlice, events.Ingress,
 		events.Egress, events.IngressBackend, events.RetryPolicy, events.UpstreamTrafficSetting,
 		events.RouteGroup, events.TCPRoute, events.TrafficSplit, events.TrafficTarget, events.Telemetry,
-		events.ProxyUpdate:
+		events.WorkloadEntry, events.TrustDomainFederation, events.SidecarProfile, events.ProxyUpdate:
 		return true, ""
 
 	case events.MeshConfig:
@@ -1,6 +1,7 @@
 package messaging
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -9,7 +10,12 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/workqueue"
 
@@ -17,6 +23,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/k8s/events"
 	"github.com/openservicemesh/osm/pkg/metricsstore"
+	"github.com/openservicemesh/osm/pkg/tracing"
 )
 
 func TestAllEvents(t *testing.T) {
@@ -37,9 +44,9 @@ func TestAllEvents(t *testing.T) {
 	defer unsubPodCH()
 
 	endpointsChan, unsubEpsCh := c.SubscribeKubeEvents(
-		events.Endpoint.Added(),
-		events.Endpoint.Updated(),
-		events.Endpoint.Deleted(),
+		events.EndpointSlice.Added(),
+		events.EndpointSlice.Updated(),
+		events.EndpointSlice.Deleted(),
 	)
 	defer unsubEpsCh()
 
@@ -47,7 +54,7 @@ func TestAllEvents(t *testing.T) {
 	defer unsubMshCfg()
 
 	numEventTriggers := 50
-	// Endpoints add/update/delete will result in proxy update events
+	// EndpointSlice add/update/delete will result in proxy update events
 	numProxyUpdatesPerEventTrigger := 3
 	// MeshConfig update events not related to proxy changes and pod events do not trigger proxy update events
 	numNonProxyUpdatesPerEventTrigger := 4
@@ -76,21 +83,21 @@ func TestAllEvents(t *testing.T) {
 			c.GetQueue().Add(podUpdate)
 
 			epAdd := events.PubSubMessage{
-				Kind:   events.Endpoint,
+				Kind:   events.EndpointSlice,
 				Type:   events.Added,
 				NewObj: i,
 			}
 			c.GetQueue().Add(epAdd)
 
 			epDel := events.PubSubMessage{
-				Kind:   events.Endpoint,
+				Kind:   events.EndpointSlice,
 				Type:   events.Deleted,
 				OldObj: i,
 			}
 			c.GetQueue().Add(epDel)
 
 			epUpdate := events.PubSubMessage{
-				Kind:   events.Endpoint,
+				Kind:   events.EndpointSlice,
 				Type:   events.Updated,
 				OldObj: i,
 				NewObj: i,
@@ -347,7 +354,7 @@ func TestRunProxyUpdateDispatcher(t *testing.T) {
 	defer b.Unsub(b.proxyUpdatePubSub, proxyUpdateChan)
 
 	// Verify sliding window expiry
-	b.proxyUpdateCh <- ProxyUpdateTopic
+	b.proxyUpdateCh <- proxyUpdate{name: ProxyUpdateTopic}
 
 	time.Sleep(proxyUpdateSlidingWindow + 10*time.Millisecond)
 	<-proxyUpdateChan
@@ -367,7 +374,7 @@ func TestRunProxyUpdateDispatcher(t *testing.T) {
 		// via the 1s sleep.
 		for i := 0; i < numEvents; i++ {
 			log.Trace().Msg("Dispatching event")
-			b.proxyUpdateCh <- ProxyUpdateTopic
+			b.proxyUpdateCh <- proxyUpdate{name: ProxyUpdateTopic}
 			time.Sleep(1 * time.Second)
 		}
 		// Verify channel close
@@ -489,3 +496,79 @@ func TestQueueLenMetric(t *testing.T) {
 		return metricsstore.DefaultMetricsStore.Contains(`osm_events_queued ` + strconv.Itoa(numEvents) + "\n")
 	}, 100*time.Millisecond, 10*time.Millisecond)
 }
+
+func TestProxyUpdateTriggers(t *testing.T) {
+	a := assert.New(t)
+
+	update := ProxyUpdate{}
+	for i := 0; i < maxUpdateTriggers+2; i++ {
+		update.addTrigger(newUpdateTrigger(events.PubSubMessage{
+			Kind:   events.EndpointSlice,
+			Type:   events.Updated,
+			OldObj: &discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "old", Labels: map[string]string{discoveryv1.LabelServiceName: "old"}}},
+			NewObj: &discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "new", Labels: map[string]string{discoveryv1.LabelServiceName: "new"}}},
+		}))
+	}
+	a.Len(update.Triggers, maxUpdateTriggers)
+	a.Equal(maxUpdateTriggers+2, update.TriggerCount)
+	a.Equal(UpdateTrigger{Kind: "endpointslice", Type: "updated", Namespace: "ns", Name: "new"}, update.Triggers[0])
+
+	// Events without an object only have a kind and type
+	a.Equal(UpdateTrigger{Kind: events.ProxyUpdate.String(), Type: string(events.Added)},
+		newUpdateTrigger(events.PubSubMessage{Kind: events.ProxyUpdate, Type: events.Added}))
+
+	ctx := WithUpdateTriggers(context.Background(), update.Triggers[0])
+	a.Equal([]UpdateTrigger{update.Triggers[0]}, UpdateTriggersFromContext(ctx))
+	a.Nil(UpdateTriggersFromContext(context.Background()))
+}
+
+func TestProxyUpdateTracing(t *testing.T) {
+	a := assert.New(t)
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	b := NewBroker(stopCh)
+	proxyUpdateChan := b.GetProxyUpdatePubSub().Sub(ProxyUpdateTopic)
+	defer b.Unsub(b.proxyUpdatePubSub, proxyUpdateChan)
+
+	_, eventSpan := tracing.Tracer().Start(context.Background(), "k8s.event")
+	eventSpan.End()
+	b.GetQueue().Add(events.PubSubMessage{
+		Kind:   events.EndpointSlice,
+		Type:   events.Added,
+		NewObj: &discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc", Labels: map[string]string{discoveryv1.LabelServiceName: "svc"}}},
+		Span:   tracing.NewEventSpan(eventSpan),
+	})
+	// Events whose arrival isn't traced aren't traced either
+	b.GetQueue().Add(events.PubSubMessage{Kind: events.EndpointSlice, Type: events.Deleted})
+
+	var update ProxyUpdate
+	select {
+	case msg := <-proxyUpdateChan:
+		update = msg.(ProxyUpdate)
+	case <-time.After(proxyUpdateMaxWindow):
+		a.FailNow("proxy update not dispatched")
+	}
+	a.Equal(2, update.TriggerCount)
+	a.Equal(eventSpan.SpanContext().TraceID(), update.SpanContext.TraceID())
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		a.Equal(eventSpan.SpanContext().TraceID(), span.SpanContext().TraceID())
+		spans[span.Name()] = span
+	}
+	a.Len(spans, 4)
+	a.Equal(eventSpan.SpanContext().SpanID(), spans["broker.workqueue"].Parent().SpanID())
+	a.Equal(eventSpan.SpanContext().SpanID(), spans["broker.processEvent"].Parent().SpanID())
+	a.Equal(spans["broker.processEvent"].SpanContext(), update.Triggers[0].SpanContext)
+	a.False(update.Triggers[1].SpanContext.IsValid())
+
+	dispatch := spans["broker.dispatch"]
+	a.Equal(update.SpanContext, dispatch.SpanContext())
+	a.Equal(spans["broker.processEvent"].SpanContext().SpanID(), dispatch.Parent().SpanID())
+	a.Len(dispatch.Links(), 1)
+}
@@ -0,0 +1,197 @@
+package messaging
+
+import (
+	"fmt"
+	"sync"
+
+	smiAccess "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+)
+
+// Dependency is a key identifying a resource that the config of a proxy is generated from
+type Dependency string
+
+// ServiceDependency returns the Dependency on the Kubernetes service with the given namespace and name
+func ServiceDependency(namespace, name string) Dependency {
+	return Dependency(fmt.Sprintf("service:%s/%s", namespace, name))
+}
+
+// NamespaceDependency returns the Dependency on the policies in the given namespace
+func NamespaceDependency(namespace string) Dependency {
+	return Dependency(fmt.Sprintf("namespace:%s", namespace))
+}
+
+// IdentityDependency returns the Dependency on the policies that reference the given service identity
+func IdentityDependency(si identity.ServiceIdentity) Dependency {
+	return Dependency(fmt.Sprintf("identity:%s", si))
+}
+
+// dependencyIndex maps dependencies to the UUIDs of the proxies whose config references them
+type dependencyIndex struct {
+	sync.RWMutex
+	proxies map[Dependency]map[string]struct{}
+	deps    map[string][]Dependency
+	// wildcard holds the proxies whose dependencies are unknown, which are updated on every event
+	wildcard map[string]struct{}
+}
+
+func newDependencyIndex() *dependencyIndex {
+	return &dependencyIndex{
+		proxies:  make(map[Dependency]map[string]struct{}),
+		deps:     make(map[string][]Dependency),
+		wildcard: make(map[string]struct{}),
+	}
+}
+
+func (i *dependencyIndex) set(uuid string, deps []Dependency) {
+	i.Lock()
+	defer i.Unlock()
+	i.removeLocked(uuid)
+
+	if deps == nil {
+		i.wildcard[uuid] = struct{}{}
+		return
+	}
+	i.deps[uuid] = deps
+	for _, dep := range deps {
+		if i.proxies[dep] == nil {
+			i.proxies[dep] = make(map[string]struct{})
+		}
+		i.proxies[dep][uuid] = struct{}{}
+	}
+}
+
+func (i *dependencyIndex) remove(uuid string) {
+	i.Lock()
+	defer i.Unlock()
+	i.removeLocked(uuid)
+}
+
+func (i *dependencyIndex) removeLocked(uuid string) {
+	delete(i.wildcard, uuid)
+	for _, dep := range i.deps[uuid] {
+		delete(i.proxies[dep], uuid)
+		if len(i.proxies[dep]) == 0 {
+			delete(i.proxies, dep)
+		}
+	}
+	delete(i.deps, uuid)
+}
+
+// lookup returns the UUIDs of the proxies that depend on any of the given dependencies
+func (i *dependencyIndex) lookup(deps map[Dependency]struct{}) map[string]struct{} {
+	i.RLock()
+	defer i.RUnlock()
+
+	uuids := make(map[string]struct{}, len(i.wildcard))
+	for uuid := range i.wildcard {
+		uuids[uuid] = struct{}{}
+	}
+	for dep := range deps {
+		for uuid := range i.proxies[dep] {
+			uuids[uuid] = struct{}{}
+		}
+	}
+	return uuids
+}
+
+// SetProxyDependencies records the dependencies of the config generated for the proxy with the given UUID, replacing
+// any previously recorded ones. Events affecting none of the dependencies are not published to the proxy, other than
+// broadcasts. A nil slice marks the dependencies of the proxy as unknown, so that it receives every update.
+func (b *Broker) SetProxyDependencies(uuid string, deps []Dependency) {
+	b.dependencies.set(uuid, deps)
+}
+
+// RemoveProxyDependencies removes the dependencies recorded for the proxy with the given UUID
+func (b *Broker) RemoveProxyDependencies(uuid string) {
+	b.dependencies.remove(uuid)
+}
+
+// getDependencies returns the dependencies affected by the given event. A nil slice is returned when the proxies
+// affected by the event cannot be determined, in which case all proxies must be updated.
+func getDependencies(msg events.PubSubMessage) []Dependency {
+	objs := make([]interface{}, 0, 2)
+	for _, obj := range []interface{}{msg.OldObj, msg.NewObj} {
+		if obj != nil {
+			objs = append(objs, obj)
+		}
+	}
+	if len(objs) == 0 {
+		return nil
+	}
+
+	var deps []Dependency
+	for _, obj := range objs {
+		objDeps := getObjectDependencies(msg.Kind, msg.Type, obj)
+		if objDeps == nil {
+			return nil
+		}
+		deps = append(deps, objDeps...)
+	}
+	return deps
+}
+
+func getObjectDependencies(kind events.Kind, eventType events.EventType, obj interface{}) []Dependency {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil
+	}
+	namespace := accessor.GetNamespace()
+
+	switch kind {
+	case events.EndpointSlice:
+		// A new service may be an upstream of proxies that don't depend on it yet
+		slice, ok := obj.(*discoveryv1.EndpointSlice)
+		if !ok || eventType == events.Added {
+			return nil
+		}
+		return []Dependency{ServiceDependency(namespace, slice.Labels[discoveryv1.LabelServiceName])}
+
+	case events.TrafficTarget:
+		// Traffic targets reside in the namespace of their destination, and allow the outbound traffic of their
+		// sources which may reside in any namespace.
+		tt, ok := obj.(*smiAccess.TrafficTarget)
+		if !ok {
+			return nil
+		}
+		deps := []Dependency{NamespaceDependency(namespace)}
+		for _, source := range tt.Spec.Sources {
+			deps = append(deps, IdentityDependency(identity.New(source.Name, source.Namespace)))
+		}
+		return deps
+
+	case events.Egress:
+		egress, ok := obj.(*policyv1alpha1.Egress)
+		if !ok {
+			return nil
+		}
+		deps := []Dependency{NamespaceDependency(namespace)}
+		for _, source := range egress.Spec.Sources {
+			deps = append(deps, IdentityDependency(identity.New(source.Name, source.Namespace)))
+		}
+		return deps
+
+	case events.RetryPolicy:
+		retry, ok := obj.(*policyv1alpha1.Retry)
+		if !ok {
+			return nil
+		}
+		return []Dependency{
+			NamespaceDependency(namespace),
+			IdentityDependency(identity.New(retry.Spec.Source.Name, retry.Spec.Source.Namespace)),
+		}
+
+	case events.IngressBackend, events.UpstreamTrafficSetting, events.TrafficSplit, events.Ingress:
+		// These policies apply to the services in their namespace, on both the proxies backing the services and the
+		// proxies they are an upstream of.
+		return []Dependency{NamespaceDependency(namespace)}
+
+	default:
+		return nil
+	}
+}
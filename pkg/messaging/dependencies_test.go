@@ -0,0 +1,220 @@
+package messaging
+
+import (
+	"testing"
+	"time"
+
+	smiAccess "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	tassert "github.com/stretchr/testify/assert"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+)
+
+func TestDependencyIndex(t *testing.T) {
+	assert := tassert.New(t)
+	i := newDependencyIndex()
+
+	svc := ServiceDependency("ns", "svc")
+	ns := NamespaceDependency("ns")
+
+	i.set("p1", []Dependency{svc, ns})
+	i.set("p2", []Dependency{ns})
+	i.set("p3", nil)
+
+	assert.Equal(map[string]struct{}{"p1": {}, "p3": {}}, i.lookup(map[Dependency]struct{}{svc: {}}))
+	assert.Equal(map[string]struct{}{"p1": {}, "p2": {}, "p3": {}}, i.lookup(map[Dependency]struct{}{ns: {}}))
+	assert.Equal(map[string]struct{}{"p3": {}}, i.lookup(map[Dependency]struct{}{NamespaceDependency("other"): {}}))
+
+	// Setting the dependencies of a proxy replaces the previous ones
+	i.set("p1", []Dependency{ns})
+	i.set("p3", []Dependency{svc})
+	assert.Equal(map[string]struct{}{"p3": {}}, i.lookup(map[Dependency]struct{}{svc: {}}))
+
+	i.remove("p1")
+	i.remove("p2")
+	i.remove("p3")
+	assert.Empty(i.lookup(map[Dependency]struct{}{svc: {}, ns: {}}))
+	assert.Empty(i.proxies)
+	assert.Empty(i.deps)
+	assert.Empty(i.wildcard)
+}
+
+func TestGetDependencies(t *testing.T) {
+	testCases := []struct {
+		name         string
+		msg          events.PubSubMessage
+		expectedDeps []Dependency
+	}{
+		{
+			name: "endpoint slice updated",
+			msg: events.PubSubMessage{
+				Kind:   events.EndpointSlice,
+				Type:   events.Updated,
+				OldObj: &discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc-1", Labels: map[string]string{discoveryv1.LabelServiceName: "svc"}}},
+				NewObj: &discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc-1", Labels: map[string]string{discoveryv1.LabelServiceName: "svc"}}},
+			},
+			expectedDeps: []Dependency{ServiceDependency("ns", "svc"), ServiceDependency("ns", "svc")},
+		},
+		{
+			name: "endpoint slice deleted",
+			msg: events.PubSubMessage{
+				Kind:   events.EndpointSlice,
+				Type:   events.Deleted,
+				OldObj: &discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc-1", Labels: map[string]string{discoveryv1.LabelServiceName: "svc"}}},
+			},
+			expectedDeps: []Dependency{ServiceDependency("ns", "svc")},
+		},
+		{
+			name: "endpoint slice added",
+			msg: events.PubSubMessage{
+				Kind:   events.EndpointSlice,
+				Type:   events.Added,
+				NewObj: &discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc-1", Labels: map[string]string{discoveryv1.LabelServiceName: "svc"}}},
+			},
+			expectedDeps: nil,
+		},
+		{
+			name: "traffic target updated",
+			msg: events.PubSubMessage{
+				Kind: events.TrafficTarget,
+				Type: events.Updated,
+				OldObj: &smiAccess.TrafficTarget{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "tt"},
+					Spec: smiAccess.TrafficTargetSpec{
+						Sources: []smiAccess.IdentityBindingSubject{{Kind: "ServiceAccount", Name: "sa1", Namespace: "ns1"}},
+					},
+				},
+				NewObj: &smiAccess.TrafficTarget{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "tt"},
+					Spec: smiAccess.TrafficTargetSpec{
+						Sources: []smiAccess.IdentityBindingSubject{{Kind: "ServiceAccount", Name: "sa2", Namespace: "ns2"}},
+					},
+				},
+			},
+			expectedDeps: []Dependency{
+				NamespaceDependency("ns"), IdentityDependency(identity.New("sa1", "ns1")),
+				NamespaceDependency("ns"), IdentityDependency(identity.New("sa2", "ns2")),
+			},
+		},
+		{
+			name: "egress added",
+			msg: events.PubSubMessage{
+				Kind: events.Egress,
+				Type: events.Added,
+				NewObj: &policyv1alpha1.Egress{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "egress"},
+					Spec: policyv1alpha1.EgressSpec{
+						Sources: []policyv1alpha1.EgressSourceSpec{{Kind: "ServiceAccount", Name: "sa", Namespace: "ns"}},
+					},
+				},
+			},
+			expectedDeps: []Dependency{NamespaceDependency("ns"), IdentityDependency(identity.New("sa", "ns"))},
+		},
+		{
+			name: "retry policy deleted",
+			msg: events.PubSubMessage{
+				Kind: events.RetryPolicy,
+				Type: events.Deleted,
+				OldObj: &policyv1alpha1.Retry{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "retry"},
+					Spec: policyv1alpha1.RetrySpec{
+						Source: policyv1alpha1.RetrySrcDstSpec{Kind: "ServiceAccount", Name: "sa", Namespace: "ns"},
+					},
+				},
+			},
+			expectedDeps: []Dependency{NamespaceDependency("ns"), IdentityDependency(identity.New("sa", "ns"))},
+		},
+		{
+			name: "upstream traffic setting added",
+			msg: events.PubSubMessage{
+				Kind:   events.UpstreamTrafficSetting,
+				Type:   events.Added,
+				NewObj: &policyv1alpha1.UpstreamTrafficSetting{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "uts"}},
+			},
+			expectedDeps: []Dependency{NamespaceDependency("ns")},
+		},
+		{
+			name: "mesh config updated",
+			msg: events.PubSubMessage{
+				Kind:   events.MeshConfig,
+				Type:   events.Updated,
+				OldObj: &configv1alpha2.MeshConfig{},
+				NewObj: &configv1alpha2.MeshConfig{},
+			},
+			expectedDeps: nil,
+		},
+		{
+			name:         "resync",
+			msg:          events.PubSubMessage{Kind: events.ProxyUpdate, Type: events.Added},
+			expectedDeps: nil,
+		},
+		{
+			name: "unexpected object type",
+			msg: events.PubSubMessage{
+				Kind:   events.EndpointSlice,
+				Type:   events.Updated,
+				OldObj: 1,
+				NewObj: 1,
+			},
+			expectedDeps: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expectedDeps, getDependencies(tc.msg))
+		})
+	}
+}
+
+func TestTargetedProxyUpdate(t *testing.T) {
+	assert := tassert.New(t)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	b := NewBroker(stopCh)
+	b.SetProxyDependencies("p1", []Dependency{ServiceDependency("ns", "svc")})
+	b.SetProxyDependencies("p2", []Dependency{ServiceDependency("ns", "other")})
+	defer b.RemoveProxyDependencies("p1")
+	defer b.RemoveProxyDependencies("p2")
+
+	p1Chan := b.GetProxyUpdatePubSub().Sub(ProxyUpdateTopic, GetPubSubTopicForProxyUUID("p1"))
+	defer b.Unsub(b.proxyUpdatePubSub, p1Chan)
+	p2Chan := b.GetProxyUpdatePubSub().Sub(ProxyUpdateTopic, GetPubSubTopicForProxyUUID("p2"))
+	defer b.Unsub(b.proxyUpdatePubSub, p2Chan)
+
+	b.GetQueue().Add(events.PubSubMessage{
+		Kind:   events.EndpointSlice,
+		Type:   events.Deleted,
+		OldObj: &discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc-1", Labels: map[string]string{discoveryv1.LabelServiceName: "svc"}}},
+	})
+
+	select {
+	case msg := <-p1Chan:
+		// The update carries the event that triggered it
+		assert.Equal(ProxyUpdate{
+			Triggers:     []UpdateTrigger{{Kind: events.EndpointSlice.String(), Type: string(events.Deleted), Namespace: "ns", Name: "svc-1"}},
+			TriggerCount: 1,
+		}, msg)
+	case <-time.After(proxyUpdateMaxWindow):
+		assert.Fail("proxy p1 was not updated")
+	}
+	select {
+	case <-p2Chan:
+		assert.Fail("proxy p2 was updated")
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.EqualValues(1, b.GetTotalDispatchedProxyEventCount())
+
+	// Broadcasts update all proxies
+	b.BroadcastProxyUpdate()
+	<-p1Chan
+	<-p2Chan
+	assert.EqualValues(2, b.GetTotalDispatchedProxyEventCount())
+}
@@ -0,0 +1,86 @@
+package messaging
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+)
+
+const (
+	// maxUpdateTriggers is the maximum number of triggers kept per proxy update, the other triggers of a batch of
+	// events are only counted
+	maxUpdateTriggers = 10
+
+	// TriggerProxyConnected is the kind of the trigger of the update of a proxy that just connected
+	TriggerProxyConnected = "ProxyConnected"
+
+	// TriggerCertificateRotated is the kind of the trigger of the update of a proxy whose certificate was rotated
+	TriggerCertificateRotated = "CertificateRotated"
+)
+
+// UpdateTrigger is an event that triggered a proxy update
+type UpdateTrigger struct {
+	Kind      string `json:"kind"`
+	Type      string `json:"type,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+
+	// SpanContext is the context of the span of the processing of the event, which is invalid when it isn't traced
+	SpanContext trace.SpanContext `json:"-"`
+}
+
+// ProxyUpdate is the message published on the proxy update pub-sub, along with the events that triggered it
+type ProxyUpdate struct {
+	// Triggers are the first events of the batch of events that triggered the update
+	Triggers []UpdateTrigger
+
+	// TriggerCount is the number of events that triggered the update, which may exceed the number of Triggers
+	TriggerCount int
+
+	// SpanContext is the context of the span of the dispatch of the update, which the updates of the proxies
+	// descend from
+	SpanContext trace.SpanContext
+}
+
+// newUpdateTrigger returns the trigger of a proxy update for the given event
+func newUpdateTrigger(msg events.PubSubMessage) UpdateTrigger {
+	trigger := UpdateTrigger{
+		Kind: msg.Kind.String(),
+		Type: string(msg.Type),
+	}
+	for _, obj := range []interface{}{msg.NewObj, msg.OldObj} {
+		if obj == nil {
+			continue
+		}
+		if accessor, err := meta.Accessor(obj); err == nil {
+			trigger.Namespace = accessor.GetNamespace()
+			trigger.Name = accessor.GetName()
+			break
+		}
+	}
+	return trigger
+}
+
+// addTrigger adds the given trigger to the update, unless it already holds the maximum number of triggers
+func (u *ProxyUpdate) addTrigger(trigger UpdateTrigger) {
+	u.TriggerCount++
+	if len(u.Triggers) < maxUpdateTriggers {
+		u.Triggers = append(u.Triggers, trigger)
+	}
+}
+
+type updateTriggersKey struct{}
+
+// WithUpdateTriggers returns a context holding the triggers of the proxy update it is used for
+func WithUpdateTriggers(ctx context.Context, triggers ...UpdateTrigger) context.Context {
+	return context.WithValue(ctx, updateTriggersKey{}, triggers)
+}
+
+// UpdateTriggersFromContext returns the triggers of the proxy update held by the given context, if any
+func UpdateTriggersFromContext(ctx context.Context) []UpdateTrigger {
+	triggers, _ := ctx.Value(updateTriggersKey{}).([]UpdateTrigger)
+	return triggers
+}
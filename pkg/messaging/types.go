@@ -17,9 +17,9 @@ var (
 type Broker struct {
 	queue             workqueue.RateLimitingInterface
 	proxyUpdatePubSub *pubsub.PubSub
-	// channel used to send proxy updates. The messages are coalesced when sent in a tight loop. The string value
-	// is only used for logging.
-	proxyUpdateCh                  chan string
+	// channel used to send proxy updates. The messages are coalesced when sent in a tight loop.
+	proxyUpdateCh                  chan proxyUpdate
+	dependencies                   *dependencyIndex
 	kubeEventPubSub                *pubsub.PubSub
 	totalQEventCount               uint64
 	totalQProxyEventCount          uint64
@@ -28,6 +28,18 @@ type Broker struct {
 	stop <-chan struct{}
 }
 
+// proxyUpdate is a proxy update sent to the dispatcher
+type proxyUpdate struct {
+	// name is only used for logging
+	name string
+
+	// deps are the dependencies affected by the update, or nil if all proxies must be updated
+	deps []Dependency
+
+	// trigger is the event that triggered the update
+	trigger UpdateTrigger
+}
+
 const (
 	// ProxyUpdateTopic is the topic used to send proxy updates
 	ProxyUpdateTopic = "proxy-update"
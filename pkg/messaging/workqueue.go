@@ -1,11 +1,14 @@
 package messaging
 
 import (
+	"context"
 	"sync/atomic"
 
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/client-go/util/workqueue"
 
 	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/tracing"
 )
 
 // GetQueue returns the workqueue instance
@@ -42,7 +45,15 @@ func (b *Broker) processNextItem() bool {
 		return true
 	}
 
-	b.processEvent(msg)
+	ctx := context.Background()
+	if msg.Span != nil {
+		ctx = trace.ContextWithSpanContext(ctx, msg.Span.SpanContext)
+		// Records the time the event spent in the workqueue
+		_, span := tracing.Tracer().Start(ctx, "broker.workqueue", trace.WithTimestamp(msg.Span.QueuedAt))
+		span.End()
+	}
+
+	b.processEvent(ctx, msg)
 	b.queue.Forget(item)
 
 	return true
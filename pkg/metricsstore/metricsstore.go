@@ -54,10 +54,20 @@ type MetricsStore struct {
 	// ProxyXDSRequestCount counts XDS requests made by proxies
 	ProxyXDSRequestCount *prometheus.CounterVec
 
+	// ProxyXDSNACKCount counts the XDS responses rejected by proxies
+	ProxyXDSNACKCount *prometheus.CounterVec
+
+	// ProxyConfigRolloutCount counts the staged rollouts of proxy configuration changes by their result
+	ProxyConfigRolloutCount *prometheus.CounterVec
+
 	// ProxyMaxConnectionsRejected counts the number of proxy connections
 	// rejected due to the max connections limit being reached
 	ProxyMaxConnectionsRejected prometheus.Counter
 
+	// ProxyStale represents the meshed pods whose sidecar differs from the sidecar the mesh currently injects, by
+	// reason. The gauge is set to 1 for each stale pod and reason.
+	ProxyStale *prometheus.GaugeVec
+
 	// AdmissionWebhookResponseTotal counts the number of webhook responses
 	// generated for both validating and mutating webhooks
 	AdmissionWebhookResponseTotal *prometheus.CounterVec
@@ -192,6 +202,20 @@ func init() {
 		Help:      "Represents the number of XDS requests made by proxies",
 	}, []string{"proxy_uuid", "identity", "type"})
 
+	defaultMetricsStore.ProxyXDSNACKCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsRootNamespace,
+		Subsystem: "proxy",
+		Name:      "xds_nack_count",
+		Help:      "Represents the number of XDS responses rejected by proxies",
+	}, []string{"proxy_uuid", "identity", "type"})
+
+	defaultMetricsStore.ProxyConfigRolloutCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsRootNamespace,
+		Subsystem: "proxy",
+		Name:      "config_rollout_count",
+		Help:      "Represents the number of staged rollouts of proxy configuration changes, by result",
+	}, []string{"result"})
+
 	defaultMetricsStore.ProxyMaxConnectionsRejected = prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace: metricsRootNamespace,
 		Subsystem: "proxy",
@@ -199,6 +223,13 @@ func init() {
 		Help:      "Represents the number of proxy connections rejected due to the configured max connections limit",
 	})
 
+	defaultMetricsStore.ProxyStale = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsRootNamespace,
+		Subsystem: "proxy",
+		Name:      "stale",
+		Help:      "Represents the meshed pods whose sidecar differs from the sidecar the mesh currently injects, by reason",
+	}, []string{"namespace", "pod", "reason"})
+
 	defaultMetricsStore.AdmissionWebhookResponseTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Namespace: metricsRootNamespace,
 		Name:      "admission_webhook_response_total",
@@ -5,22 +5,27 @@ import "time"
 
 // HealthProbe represents a health probe.
 type HealthProbe struct {
-	Path    string
-	Port    int32
-	Timeout time.Duration
+	Path    string        `json:"path,omitempty"`
+	Port    int32         `json:"port,omitempty"`
+	Timeout time.Duration `json:"timeout,omitempty"`
 
 	// isHTTP corresponds to an httpGet probe with a scheme of HTTP or undefined.
 	// This helps inform what kind of Envoy config to add to the pod. A HealthProbe
-	// that is neither HTTP nor TCPSocket is assumed to be HTTPS
-	IsHTTP bool
+	// that is neither HTTP, TCPSocket nor GRPC is assumed to be HTTPS
+	IsHTTP bool `json:"http,omitempty"`
 
 	// isTCPSocket indicates if the probe defines a TCPSocketAction.
-	IsTCPSocket bool
+	IsTCPSocket bool `json:"tcpSocket,omitempty"`
+
+	// isGRPC indicates if the probe defines a GRPCAction.
+	IsGRPC bool `json:"grpc,omitempty"`
 }
 
 // HealthProbes is to serve as an indication of whether the given healthProbe has been rewritten
 type HealthProbes struct {
-	Liveness, Readiness, Startup *HealthProbe
+	Liveness  *HealthProbe `json:"liveness,omitempty"`
+	Readiness *HealthProbe `json:"readiness,omitempty"`
+	Startup   *HealthProbe `json:"startup,omitempty"`
 }
 
 // UsesTCP returns true if any of the configured probes uses a TCP probe.
@@ -29,3 +34,10 @@ func (probes *HealthProbes) UsesTCP() bool {
 		(probes.Readiness != nil && probes.Readiness.IsTCPSocket) ||
 		(probes.Startup != nil && probes.Startup.IsTCPSocket)
 }
+
+// UsesGRPC returns true if any of the configured probes uses a gRPC probe.
+func (probes *HealthProbes) UsesGRPC() bool {
+	return (probes.Liveness != nil && probes.Liveness.IsGRPC) ||
+		(probes.Readiness != nil && probes.Readiness.IsGRPC) ||
+		(probes.Startup != nil && probes.Startup.IsGRPC)
+}
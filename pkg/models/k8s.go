@@ -5,4 +5,5 @@ type Secret struct {
 	Name      string
 	Namespace string
 	Data      map[string][]byte
+	Labels    map[string]string
 }
@@ -0,0 +1,18 @@
+package models
+
+import (
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+)
+
+// SidecarConfig defines the effective sidecar configuration of a workload, resolved from the MeshConfig and the
+// SidecarProfile selecting the workload
+type SidecarConfig struct {
+	// Sidecar is the sidecar section of the MeshConfig, with the settings overridden by the profile
+	Sidecar configv1alpha2.SidecarSpec
+
+	// Concurrency is the number of worker threads of the sidecar, or 0 for the number of CPU cores of the node
+	Concurrency int32
+
+	// Profile is the SidecarProfile selecting the workload, or nil when the MeshConfig applies as is
+	Profile *configv1alpha2.SidecarProfile
+}
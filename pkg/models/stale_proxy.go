@@ -0,0 +1,29 @@
+package models
+
+// StaleProxyReason is the reason a sidecar differs from the sidecar the mesh currently injects
+type StaleProxyReason string
+
+const (
+	// StaleProxyReasonEnvoyImage is the reason of a sidecar running another Envoy image than the configured image
+	StaleProxyReasonEnvoyImage StaleProxyReason = "envoy-image"
+
+	// StaleProxyReasonBootstrapVersion is the reason of a sidecar whose bootstrap config was created by another
+	// version of OSM
+	StaleProxyReasonBootstrapVersion StaleProxyReason = "bootstrap-version"
+
+	// StaleProxyReasonInjectorVersion is the reason of a sidecar injected by another version of OSM
+	StaleProxyReasonInjectorVersion StaleProxyReason = "injector-version"
+)
+
+// StaleProxy is a meshed pod whose sidecar differs from the sidecar the mesh currently injects, which is updated when
+// the pod is recreated
+type StaleProxy struct {
+	// Namespace is the namespace of the pod
+	Namespace string `json:"namespace"`
+
+	// Pod is the name of the pod
+	Pod string `json:"pod"`
+
+	// Reasons are the reasons the sidecar is stale
+	Reasons []StaleProxyReason `json:"reasons"`
+}
@@ -6,4 +6,7 @@ type ProxyKind string
 const (
 	// KindSidecar implies the proxy is a sidecar
 	KindSidecar ProxyKind = "sidecar"
+
+	// KindProxylessGRPC implies the proxy is a gRPC application consuming xDS directly, without a sidecar
+	KindProxylessGRPC ProxyKind = "grpc"
 )
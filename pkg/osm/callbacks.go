@@ -10,6 +10,9 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/peer"
@@ -22,6 +25,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/messaging"
 	"github.com/openservicemesh/osm/pkg/metricsstore"
 	"github.com/openservicemesh/osm/pkg/models"
+	"github.com/openservicemesh/osm/pkg/tracing"
 	"github.com/openservicemesh/osm/pkg/utils"
 )
 
@@ -55,20 +59,26 @@ func (cp *ControlPlane[T]) ProxyConnected(ctx context.Context, connectionID int6
 		proxyUpdatePubSub := cp.msgBroker.GetProxyUpdatePubSub()
 		proxyUpdateChan := proxyUpdatePubSub.Sub(messaging.ProxyUpdateTopic, messaging.GetPubSubTopicForProxyUUID(proxy.UUID.String()))
 		defer cp.msgBroker.Unsub(proxyUpdatePubSub, proxyUpdateChan)
+		defer cp.msgBroker.RemoveProxyDependencies(proxy.UUID.String())
 
 		certRotations, unsubRotations := cp.certManager.SubscribeRotations(proxy.Identity.String())
 		defer unsubRotations()
 
 		// schedule one update for this proxy initially.
-		cp.scheduleUpdate(ctx, proxy)
+		cp.scheduleUpdate(messaging.WithUpdateTriggers(ctx, messaging.UpdateTrigger{Kind: messaging.TriggerProxyConnected}), proxy)
 		for {
 			select {
-			case <-proxyUpdateChan:
-				log.Debug().Str("proxy", proxy.String()).Msg("Broadcast update received")
-				cp.scheduleUpdate(ctx, proxy)
+			case msg := <-proxyUpdateChan:
+				log.Debug().Str("proxy", proxy.String()).Msg("Proxy update received")
+				update, _ := msg.(messaging.ProxyUpdate)
+				updateCtx := trace.ContextWithSpanContext(messaging.WithUpdateTriggers(ctx, update.Triggers...), update.SpanContext)
+				cp.scheduleUpdate(updateCtx, proxy)
 			case <-certRotations:
 				log.Debug().Str("proxy", proxy.String()).Msg("Certificate has been updated for proxy")
-				cp.scheduleUpdate(ctx, proxy)
+				cp.scheduleUpdate(messaging.WithUpdateTriggers(ctx, messaging.UpdateTrigger{
+					Kind: messaging.TriggerCertificateRotated,
+					Name: proxy.Identity.String(),
+				}), proxy)
 			case <-ctx.Done():
 				return
 			}
@@ -80,8 +90,13 @@ func (cp *ControlPlane[T]) ProxyConnected(ctx context.Context, connectionID int6
 func (cp *ControlPlane[T]) scheduleUpdate(ctx context.Context, proxy *models.Proxy) {
 	var wg sync.WaitGroup
 	wg.Add(1)
+	queuedAt := time.Now()
 	cp.workqueues.AddJob(
 		func() {
+			// Records the time the update spent in the workqueue
+			_, span := tracing.Start(ctx, "ControlPlane.workqueue", trace.WithTimestamp(queuedAt))
+			span.End()
+
 			t := time.Now()
 			log.Debug().Str("proxy", proxy.String()).Msg("Starting update for proxy")
 
@@ -95,17 +110,64 @@ func (cp *ControlPlane[T]) scheduleUpdate(ctx context.Context, proxy *models.Pro
 }
 
 func (cp *ControlPlane[T]) update(ctx context.Context, proxy *models.Proxy) error {
+	// The update is linked to every event that triggered it
+	var spanContexts []trace.SpanContext
+	for _, trigger := range messaging.UpdateTriggersFromContext(ctx) {
+		spanContexts = append(spanContexts, trigger.SpanContext)
+	}
+	ctx, span := tracing.Start(ctx, "ControlPlane.update",
+		trace.WithLinks(tracing.Links(spanContexts...)...),
+		trace.WithAttributes(
+			tracing.ProxyUUIDKey.String(proxy.UUID.String()),
+			tracing.ProxyIdentityKey.String(proxy.Identity.String()),
+			attribute.String("osm.proxy.kind", string(proxy.Kind())),
+		))
+	defer span.End()
+
+	// The dependencies are recorded before generating the config, so that events received while it is generated
+	// are not missed.
+	cp.msgBroker.SetProxyDependencies(proxy.UUID.String(), cp.getDependencies(proxy))
+
 	resources, err := cp.configGenerator.GenerateConfig(ctx, proxy)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, "error generating the configuration")
 		return err
 	}
 	if err := cp.configServer.UpdateProxy(ctx, proxy, resources); err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, "error updating the configuration")
 		return err
 	}
 	log.Debug().Str("proxy", proxy.String()).Msg("successfully updated resources for proxy")
 	return nil
 }
 
+// getDependencies returns the resources that the config of the given proxy is generated from, or nil if they can't
+// be determined.
+func (cp *ControlPlane[T]) getDependencies(proxy *models.Proxy) []messaging.Dependency {
+	switch proxy.Kind() {
+	case models.KindSidecar, models.KindProxylessGRPC:
+	default:
+		return nil
+	}
+	services, err := cp.catalog.ListServicesForProxy(proxy)
+	if err != nil {
+		log.Warn().Err(err).Str("proxy", proxy.String()).Msg("Error listing services for proxy, proxy will be updated on every event")
+		return nil
+	}
+	services = append(services, cp.catalog.ListOutboundServicesForIdentity(proxy.Identity)...)
+
+	deps := []messaging.Dependency{
+		messaging.IdentityDependency(proxy.Identity),
+		messaging.NamespaceDependency(proxy.Identity.ToK8sServiceAccount().Namespace),
+	}
+	for _, svc := range services {
+		deps = append(deps, messaging.ServiceDependency(svc.Namespace, svc.Name), messaging.NamespaceDependency(svc.Namespace))
+	}
+	return deps
+}
+
 // ProxyDisconnected is called on stream closed
 func (cp *ControlPlane[T]) ProxyDisconnected(connectionID int64) {
 	log.Debug().Msgf("OnStreamClosed id: %d", connectionID)
@@ -196,5 +258,12 @@ func getCertificateCommonNameMeta(cn string) (models.ProxyKind, uuid.UUID, ident
 		return "", uuid.UUID{}, "", errInvalidCertificateCN
 	}
 
-	return models.ProxyKind(chunks[1]), proxyUUID, identity.New(chunks[2], chunks[3]), nil
+	kind := models.ProxyKind(chunks[1])
+	switch kind {
+	case models.KindSidecar, models.KindProxylessGRPC:
+	default:
+		return "", uuid.UUID{}, "", errUnsupportedProxyKind
+	}
+
+	return kind, proxyUUID, identity.New(chunks[2], chunks[3]), nil
 }
@@ -16,42 +16,60 @@ func TestGetCertificateCommonNameMeta(t *testing.T) {
 	testCases := []struct {
 		name     string
 		uuid     uuid.UUID
+		kind     models.ProxyKind
 		identity identity.ServiceIdentity
 		err      error
 	}{
 		{
 			name:     "valid cn",
 			uuid:     uuid.New(),
+			kind:     models.KindSidecar,
 			identity: identity.New("foo", "bar"),
 		},
+		{
+			name:     "proxyless gRPC kind",
+			uuid:     uuid.New(),
+			kind:     models.KindProxylessGRPC,
+			identity: identity.New("foo", "bar"),
+		},
+		{
+			name:     "unsupported kind",
+			uuid:     uuid.New(),
+			kind:     models.ProxyKind("gateway"),
+			identity: identity.New("foo", "bar"),
+			err:      errUnsupportedProxyKind,
+		},
 		{
 			name:     "invalid uuid",
 			uuid:     uuid.Nil,
+			kind:     models.KindSidecar,
 			identity: identity.New("foo", "bar"),
 		},
 		{
 			name:     "invalid identity",
 			uuid:     uuid.New(),
+			kind:     models.KindSidecar,
 			identity: identity.New("foo", ""),
 			err:      errInvalidCertificateCN,
 		},
 		{
 			name: "no identity",
 			uuid: uuid.New(),
+			kind: models.KindSidecar,
 			err:  errInvalidCertificateCN,
 		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			assert := tassert.New(t)
-			cn := fmt.Sprintf("%s.%s.%s", tc.uuid, models.KindSidecar, tc.identity)
+			cn := fmt.Sprintf("%s.%s.%s", tc.uuid, tc.kind, tc.identity)
 
 			kind, uuid, si, err := getCertificateCommonNameMeta(cn)
 
 			assert.Equal(tc.err, err)
 
 			if err == nil {
-				assert.Equal(models.KindSidecar, kind)
+				assert.Equal(tc.kind, kind)
 				assert.Equal(tc.uuid, uuid)
 				assert.Equal(tc.identity, si)
 			}
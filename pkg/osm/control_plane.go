@@ -2,6 +2,7 @@ package osm
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/openservicemesh/osm/pkg/catalog"
 	"github.com/openservicemesh/osm/pkg/certificate"
@@ -58,3 +59,17 @@ func NewControlPlane[T any](server ProxyUpdater[T],
 		msgBroker:       msgBroker,
 	}
 }
+
+// KindConfigGenerator is a ProxyConfigGenerator that generates the config of each proxy with the ProxyConfigGenerator
+// of the proxy's kind.
+type KindConfigGenerator[T any] map[models.ProxyKind]ProxyConfigGenerator[T]
+
+// GenerateConfig generates the config of the given proxy with the ProxyConfigGenerator of its kind.
+func (g KindConfigGenerator[T]) GenerateConfig(ctx context.Context, proxy *models.Proxy) (T, error) {
+	generator, ok := g[proxy.Kind()]
+	if !ok {
+		var config T
+		return config, fmt.Errorf("%w: %s", errUnsupportedProxyKind, proxy.Kind())
+	}
+	return generator.GenerateConfig(ctx, proxy)
+}
@@ -14,6 +14,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/peer"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/openservicemesh/osm/pkg/catalog"
 	"github.com/openservicemesh/osm/pkg/certificate"
@@ -21,8 +23,10 @@ import (
 	"github.com/openservicemesh/osm/pkg/compute"
 	"github.com/openservicemesh/osm/pkg/envoy/registry"
 	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
 	"github.com/openservicemesh/osm/pkg/messaging"
 	"github.com/openservicemesh/osm/pkg/models"
+	"github.com/openservicemesh/osm/pkg/service"
 )
 
 type fakeConfig string
@@ -92,6 +96,7 @@ func TestControlLoop(t *testing.T) {
 
 	provider.EXPECT().GetMeshConfig().AnyTimes()
 	provider.EXPECT().VerifyProxy(gomock.Any()).AnyTimes()
+	provider.EXPECT().ListTrafficTargets().AnyTimes()
 
 	meshCatalog := catalog.NewMeshCatalog(
 		provider,
@@ -115,6 +120,14 @@ func TestControlLoop(t *testing.T) {
 
 	uuid2 := uuid.New()
 	id2 := identity.New("p2", "ns2")
+
+	// Only p1 backs a service, so that it alone depends on the service's endpoints
+	provider.EXPECT().ListServicesForProxy(gomock.Any()).DoAndReturn(func(p *models.Proxy) ([]service.MeshService, error) {
+		if p.UUID == uuid1 {
+			return []service.MeshService{{Namespace: "ns3", Name: "svc"}}, nil
+		}
+		return nil, nil
+	}).AnyTimes()
 	cert2, err := certManager.IssueCertificate(certificate.ForCommonNamePrefix(models.NewXDSCertCNPrefix(uuid2, models.KindSidecar, id2)))
 	tassert.NoError(err)
 
@@ -206,4 +219,43 @@ func TestControlLoop(t *testing.T) {
 	tassert.Equal(3, g.getCallCount(p1.UUID.String()))
 	tassert.Equal(3, server.getCallCount(p1.UUID.String()))
 	tassert.Equal(fakeConfig(p1.UUID.String()+": 3"), server.getConfig(p1.UUID.String()))
+
+	// An endpoints update for the service only updates p1, which depends on it
+	cp.msgBroker.GetQueue().Add(events.PubSubMessage{
+		Kind:   events.EndpointSlice,
+		Type:   events.Updated,
+		OldObj: &discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{Namespace: "ns3", Name: "svc", Labels: map[string]string{discoveryv1.LabelServiceName: "svc"}}},
+		NewObj: &discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{Namespace: "ns3", Name: "svc", Labels: map[string]string{discoveryv1.LabelServiceName: "svc"}}},
+	})
+	time.Sleep(time.Second * 3)
+
+	tassert.Equal(4, g.getCallCount(p1.UUID.String()))
+	tassert.Equal(2, g.getCallCount(p2.UUID.String()))
+}
+
+func TestKindConfigGenerator(t *testing.T) {
+	tassert := assert.New(t)
+
+	sidecarGenerator := &fakeGenerator{callCount: map[string]int{}}
+	grpcGenerator := &fakeGenerator{callCount: map[string]int{}}
+	g := KindConfigGenerator[fakeConfig]{
+		models.KindSidecar:       sidecarGenerator,
+		models.KindProxylessGRPC: grpcGenerator,
+	}
+
+	sidecar := models.NewProxy(models.KindSidecar, uuid.New(), identity.New("sa", "ns"), nil, 1)
+	grpc := models.NewProxy(models.KindProxylessGRPC, uuid.New(), identity.New("sa", "ns"), nil, 1)
+	gateway := models.NewProxy(models.ProxyKind("gateway"), uuid.New(), identity.New("sa", "ns"), nil, 1)
+
+	_, err := g.GenerateConfig(context.Background(), sidecar)
+	tassert.NoError(err)
+	_, err = g.GenerateConfig(context.Background(), grpc)
+	tassert.NoError(err)
+
+	tassert.Equal(1, sidecarGenerator.getCallCount(sidecar.UUID.String()))
+	tassert.Equal(0, sidecarGenerator.getCallCount(grpc.UUID.String()))
+	tassert.Equal(1, grpcGenerator.getCallCount(grpc.UUID.String()))
+
+	_, err = g.GenerateConfig(context.Background(), gateway)
+	tassert.ErrorIs(err, errUnsupportedProxyKind)
 }
@@ -4,3 +4,4 @@ import "fmt"
 
 var errTooManyConnections = fmt.Errorf("too many connections")
 var errInvalidCertificateCN = fmt.Errorf("invalid cn")
+var errUnsupportedProxyKind = fmt.Errorf("unsupported proxy kind")
@@ -0,0 +1,154 @@
+// Package proxyless implements functionality related to proxyless gRPC applications, which consume the mesh config
+// directly from the xDS server through gRPC's built-in xDS client instead of through an Envoy sidecar.
+package proxyless
+
+import (
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"strconv"
+
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var log = logger.New("proxyless")
+
+const (
+	// BootstrapConfigPath is the path at which the bootstrap config and certificates are mounted in the containers of
+	// proxyless gRPC pods
+	BootstrapConfigPath = "/etc/osm/grpc"
+
+	// BootstrapConfigFile is the name of the gRPC xDS bootstrap config file
+	BootstrapConfigFile = "bootstrap.json"
+
+	// BootstrapEnvVar is the environment variable through which gRPC locates its xDS bootstrap config
+	BootstrapEnvVar = "GRPC_XDS_BOOTSTRAP"
+
+	// XDSCACertFile is the name of the file containing the CA certificate used to verify the xDS server. The xDS
+	// certificate files are named as in the bootstrap secrets of Envoy sidecars, so that both are rotated alike.
+	XDSCACertFile = "cacert.pem"
+
+	// XDSCertFile is the name of the file containing the certificate used to connect to the xDS server
+	XDSCertFile = "sds_cert.pem"
+
+	// XDSKeyFile is the name of the file containing the private key used to connect to the xDS server
+	XDSKeyFile = "sds_key.pem"
+
+	// ServiceCACertFile is the name of the file containing the mesh's trusted CAs, used to verify peers
+	ServiceCACertFile = "service_cacert.pem"
+
+	// ServiceCertFile is the name of the file containing the service certificate used for mTLS with peers
+	ServiceCertFile = "service_cert.pem"
+
+	// ServiceKeyFile is the name of the file containing the private key of the service certificate
+	ServiceKeyFile = "service_key.pem"
+
+	// ServiceIdentityKey is the key of the secret data holding the service identity of the pod
+	ServiceIdentityKey = "service_identity"
+
+	// CertificateProviderInstance is the name of the certificate provider instance through which gRPC loads the
+	// service certificate and the mesh's trusted CAs
+	CertificateProviderInstance = "osm"
+
+	// InboundListenerNamePrefix is the prefix of the names of the listeners requested by gRPC servers, suffixed with
+	// the address the server listens on
+	InboundListenerNamePrefix = "osm/grpc/inbound/"
+
+	// certificateRefreshInterval is the interval at which gRPC reloads the certificate files, which are updated when
+	// the certificates are rotated
+	certificateRefreshInterval = "60s"
+)
+
+// Builder is the type used to build the gRPC xDS bootstrap config.
+type Builder struct {
+	// XDSHost is the hostname of the xDS server to connect to
+	XDSHost string
+
+	// XDSPort is the port of the xDS server to connect to
+	XDSPort int
+
+	// NodeID is the proxy's node ID
+	NodeID string
+}
+
+// bootstrap is the gRPC xDS bootstrap config, documented in gRFC A27
+type bootstrap struct {
+	XDSServers                         []xdsServer                    `json:"xds_servers"`
+	Node                               node                           `json:"node"`
+	CertificateProviders               map[string]certificateProvider `json:"certificate_providers"`
+	ServerListenerResourceNameTemplate string                         `json:"server_listener_resource_name_template"`
+}
+
+type xdsServer struct {
+	ServerURI      string         `json:"server_uri"`
+	ChannelCreds   []channelCreds `json:"channel_creds"`
+	ServerFeatures []string       `json:"server_features"`
+}
+
+// channelCreds configures the credentials used to connect to the xDS server. The 'tls' type with certificate files
+// is documented in gRFC A65.
+type channelCreds struct {
+	Type   string      `json:"type"`
+	Config certificate `json:"config"`
+}
+
+type node struct {
+	ID string `json:"id"`
+}
+
+// certificateProvider configures the provider of the certificates used for mTLS with peers, documented in gRFC A29
+type certificateProvider struct {
+	PluginName string      `json:"plugin_name"`
+	Config     certificate `json:"config"`
+}
+
+type certificate struct {
+	CACertificateFile string `json:"ca_certificate_file"`
+	CertificateFile   string `json:"certificate_file"`
+	PrivateKeyFile    string `json:"private_key_file"`
+	RefreshInterval   string `json:"refresh_interval,omitempty"`
+}
+
+// Build builds and returns the gRPC xDS bootstrap config in JSON.
+func (b *Builder) Build() ([]byte, error) {
+	config := bootstrap{
+		XDSServers: []xdsServer{
+			{
+				ServerURI: net.JoinHostPort(b.XDSHost, strconv.Itoa(b.XDSPort)),
+				ChannelCreds: []channelCreds{
+					{
+						Type: "tls",
+						Config: certificate{
+							CACertificateFile: filepath.Join(BootstrapConfigPath, XDSCACertFile),
+							CertificateFile:   filepath.Join(BootstrapConfigPath, XDSCertFile),
+							PrivateKeyFile:    filepath.Join(BootstrapConfigPath, XDSKeyFile),
+						},
+					},
+				},
+				ServerFeatures: []string{"xds_v3"},
+			},
+		},
+		Node: node{
+			ID: b.NodeID,
+		},
+		CertificateProviders: map[string]certificateProvider{
+			CertificateProviderInstance: {
+				PluginName: "file_watcher",
+				Config: certificate{
+					CACertificateFile: filepath.Join(BootstrapConfigPath, ServiceCACertFile),
+					CertificateFile:   filepath.Join(BootstrapConfigPath, ServiceCertFile),
+					PrivateKeyFile:    filepath.Join(BootstrapConfigPath, ServiceKeyFile),
+					RefreshInterval:   certificateRefreshInterval,
+				},
+			},
+		},
+		ServerListenerResourceNameTemplate: InboundListenerNamePrefix + "%s",
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		log.Error().Err(err).Msg("Error marshaling gRPC xDS bootstrap config")
+		return nil, err
+	}
+	return data, nil
+}
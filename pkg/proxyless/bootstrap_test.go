@@ -0,0 +1,35 @@
+package proxyless
+
+import (
+	"encoding/json"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestBuild(t *testing.T) {
+	assert := tassert.New(t)
+
+	b := &Builder{
+		XDSHost: "osm-controller.osm-system.svc.cluster.local",
+		XDSPort: 15128,
+		NodeID:  "d9d4b1a6-5bd5-4c43-9d5e-5cbe3bc0b0b1",
+	}
+	data, err := b.Build()
+	assert.NoError(err)
+
+	var config bootstrap
+	assert.NoError(json.Unmarshal(data, &config))
+
+	assert.Len(config.XDSServers, 1)
+	assert.Equal("osm-controller.osm-system.svc.cluster.local:15128", config.XDSServers[0].ServerURI)
+	assert.Equal("tls", config.XDSServers[0].ChannelCreds[0].Type)
+	assert.Equal("/etc/osm/grpc/sds_cert.pem", config.XDSServers[0].ChannelCreds[0].Config.CertificateFile)
+	assert.Equal(b.NodeID, config.Node.ID)
+
+	provider, ok := config.CertificateProviders[CertificateProviderInstance]
+	assert.True(ok)
+	assert.Equal("file_watcher", provider.PluginName)
+	assert.Equal("/etc/osm/grpc/service_cert.pem", provider.Config.CertificateFile)
+	assert.Equal("osm/grpc/inbound/%s", config.ServerListenerResourceNameTemplate)
+}
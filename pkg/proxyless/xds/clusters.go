@@ -0,0 +1,130 @@
+package xds
+
+import (
+	"context"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/openservicemesh/osm/pkg/endpoint"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/models"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+const (
+	localZone             = "local"
+	localClusterPriority  = uint32(0)
+	remoteClusterPriority = uint32(1)
+
+	// localLocalityWeight is the weight of the locality of the local endpoints. gRPC ignores the localities without
+	// a weight.
+	localLocalityWeight = uint32(1)
+)
+
+// generateCDS returns the clusters of the upstream services the proxy is allowed to connect to.
+func (g *Generator) generateCDS(_ context.Context, proxy *models.Proxy) ([]types.Resource, error) {
+	issuers := g.certManager.GetIssuersInfo()
+
+	var clusters []types.Resource
+	for _, config := range g.catalog.GetOutboundMeshClusterConfigs(proxy.Identity) {
+		upstreamIdentities, err := g.catalog.ListServiceIdentitiesForService(config.Service.Name, config.Service.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		transportSocket, err := getUpstreamTransportSocket(upstreamIdentities, issuers)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error marshalling UpstreamTlsContext for upstream cluster %s", config.Name)
+			return nil, err
+		}
+		clusters = append(clusters, getUpstreamCluster(config, transportSocket))
+	}
+	return clusters, nil
+}
+
+// getUpstreamCluster returns the cluster for the given upstream cluster config
+func getUpstreamCluster(config *trafficpolicy.MeshClusterConfig, transportSocket *xds_core.TransportSocket) *xds_cluster.Cluster {
+	cluster := &xds_cluster.Cluster{
+		Name:                 config.Name,
+		ClusterDiscoveryType: &xds_cluster.Cluster_Type{Type: xds_cluster.Cluster_EDS},
+		EdsClusterConfig: &xds_cluster.Cluster_EdsClusterConfig{
+			EdsConfig:   envoy.GetADSConfigSource(),
+			ServiceName: config.Name,
+		},
+		LbPolicy:        xds_cluster.Cluster_ROUND_ROBIN,
+		TransportSocket: transportSocket,
+	}
+
+	// gRPC only supports the max_requests circuit breaker
+	if config.UpstreamTrafficSetting != nil && config.UpstreamTrafficSetting.Spec.ConnectionSettings != nil &&
+		config.UpstreamTrafficSetting.Spec.ConnectionSettings.HTTP != nil &&
+		config.UpstreamTrafficSetting.Spec.ConnectionSettings.HTTP.MaxRequests != nil {
+		cluster.CircuitBreakers = &xds_cluster.CircuitBreakers{
+			Thresholds: []*xds_cluster.CircuitBreakers_Thresholds{
+				{
+					MaxRequests: wrapperspb.UInt32(*config.UpstreamTrafficSetting.Spec.ConnectionSettings.HTTP.MaxRequests),
+				},
+			},
+		}
+	}
+	return cluster
+}
+
+// generateEDS returns the endpoints of the upstream services the proxy is allowed to connect to.
+func (g *Generator) generateEDS(_ context.Context, proxy *models.Proxy) ([]types.Resource, error) {
+	var loadAssignments []types.Resource
+	zone := g.catalog.GetZoneForProxy(proxy)
+	for _, svc := range g.catalog.ListOutboundServicesForIdentity(proxy.Identity) {
+		endpoints := endpoint.FilterByZoneHints(g.catalog.ListAllowedUpstreamEndpointsForService(proxy.Identity, svc), zone)
+		loadAssignments = append(loadAssignments, getClusterLoadAssignment(svc.EnvoyClusterName(), endpoints))
+	}
+	return loadAssignments, nil
+}
+
+// getClusterLoadAssignment returns the load assignment of the given cluster. Endpoints without a weight belong to the
+// local cluster, and the others to remote clusters with a lower priority.
+func getClusterLoadAssignment(clusterName string, endpoints []endpoint.Endpoint) *xds_endpoint.ClusterLoadAssignment {
+	local := &xds_endpoint.LocalityLbEndpoints{
+		Locality:            &xds_core.Locality{Zone: localZone},
+		LoadBalancingWeight: wrapperspb.UInt32(localLocalityWeight),
+		Priority:            localClusterPriority,
+	}
+	cla := &xds_endpoint.ClusterLoadAssignment{
+		ClusterName: clusterName,
+		Endpoints:   []*xds_endpoint.LocalityLbEndpoints{local},
+	}
+
+	for _, ep := range endpoints {
+		lbEndpoint := &xds_endpoint.LbEndpoint{
+			HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
+				Endpoint: &xds_endpoint.Endpoint{
+					Address: envoy.GetAddress(ep.IP.String(), uint32(ep.Port)),
+				},
+			},
+		}
+		// Terminating endpoints don't receive new requests, but keep serving the connections established to them
+		if ep.Terminating {
+			lbEndpoint.HealthStatus = xds_core.HealthStatus_DRAINING
+		}
+
+		if ep.Weight == 0 {
+			local.LbEndpoints = append(local.LbEndpoints, lbEndpoint)
+			continue
+		}
+
+		remote := &xds_endpoint.LocalityLbEndpoints{
+			Locality:            &xds_core.Locality{Zone: ep.Zone},
+			LbEndpoints:         []*xds_endpoint.LbEndpoint{lbEndpoint},
+			LoadBalancingWeight: wrapperspb.UInt32(uint32(ep.Weight)),
+			Priority:            remoteClusterPriority,
+		}
+		if ep.Priority != 0 {
+			remote.Priority = uint32(ep.Priority)
+		}
+		cla.Endpoints = append(cla.Endpoints, remote)
+	}
+	return cla
+}
@@ -0,0 +1,37 @@
+package xds
+
+import (
+	"net"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/endpoint"
+)
+
+func TestGetClusterLoadAssignment(t *testing.T) {
+	assert := tassert.New(t)
+
+	cla := getClusterLoadAssignment("ns/greeter|50051", []endpoint.Endpoint{
+		{IP: net.ParseIP("10.0.0.1"), Port: 50051},
+		{IP: net.ParseIP("10.0.0.2"), Port: 50051},
+		{IP: net.ParseIP("10.1.0.1"), Port: 50051, Weight: 10, Zone: "remote"},
+		{IP: net.ParseIP("10.2.0.1"), Port: 50051, Weight: 20, Priority: 3, Zone: "other"},
+	})
+
+	assert.Equal("ns/greeter|50051", cla.ClusterName)
+	assert.Len(cla.Endpoints, 3)
+
+	// gRPC ignores the localities without a weight, so that the local one must have one
+	local := cla.Endpoints[0]
+	assert.Len(local.LbEndpoints, 2)
+	assert.Equal(uint32(localLocalityWeight), local.LoadBalancingWeight.GetValue())
+	assert.Equal(uint32(localClusterPriority), local.Priority)
+
+	assert.Equal("remote", cla.Endpoints[1].Locality.Zone)
+	assert.Equal(uint32(10), cla.Endpoints[1].LoadBalancingWeight.GetValue())
+	assert.Equal(uint32(remoteClusterPriority), cla.Endpoints[1].Priority)
+
+	assert.Equal("other", cla.Endpoints[2].Locality.Zone)
+	assert.Equal(uint32(3), cla.Endpoints[2].Priority)
+}
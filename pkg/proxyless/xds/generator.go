@@ -0,0 +1,79 @@
+// Package xds implements the generation of the xDS resources consumed by proxyless gRPC applications. gRPC supports a
+// subset of the xDS API: the listeners are API listeners for clients and server listeners for servers, and the mTLS
+// certificates are loaded by gRPC from files through certificate provider instances rather than through SDS.
+package xds
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/errcode"
+	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+	"github.com/openservicemesh/osm/pkg/models"
+	"github.com/openservicemesh/osm/pkg/tracing"
+)
+
+var log = logger.New("proxyless/xds")
+
+// Generator is used to generate the xDS resources of proxyless gRPC applications.
+type Generator struct {
+	catalog     catalog.MeshCataloger
+	certManager *certificate.Manager
+	generators  map[envoy.TypeURI]func(context.Context, *models.Proxy) ([]types.Resource, error)
+}
+
+// NewGenerator creates a new instance of Generator.
+func NewGenerator(catalog catalog.MeshCataloger, certManager *certificate.Manager) *Generator {
+	g := &Generator{
+		catalog:     catalog,
+		certManager: certManager,
+	}
+	g.generators = map[envoy.TypeURI]func(context.Context, *models.Proxy) ([]types.Resource, error){
+		envoy.TypeCDS: g.generateCDS,
+		envoy.TypeEDS: g.generateEDS,
+		envoy.TypeLDS: g.generateLDS,
+		envoy.TypeRDS: g.generateRDS,
+	}
+	return g
+}
+
+// GenerateConfig generates and returns the resources for the given proxy.
+func (g *Generator) GenerateConfig(ctx context.Context, proxy *models.Proxy) (map[string][]types.Resource, error) {
+	resources := map[string][]types.Resource{}
+	for typeURI, handler := range g.generators {
+		log.Trace().Str("proxy", proxy.String()).Msgf("Getting resources for type %s", typeURI.Short())
+
+		startedAt := time.Now()
+		typeCtx, span := tracing.Start(ctx, "generate"+typeURI.Short(), trace.WithAttributes(
+			tracing.ProxyUUIDKey.String(proxy.UUID.String()),
+			tracing.TypeURLKey.String(typeURI.String()),
+		))
+		typeResources, err := handler(typeCtx, proxy)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "error generating the resources")
+		}
+		span.SetAttributes(attribute.Int("osm.xds.resource_count", len(typeResources)))
+		span.End()
+		metricsstore.DefaultMetricsStore.ProxyConfigUpdateTime.
+			WithLabelValues(typeURI.String(), strconv.FormatBool(err == nil)).
+			Observe(time.Since(startedAt).Seconds())
+		if err != nil {
+			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGeneratingReqResource)).Str("proxy", proxy.String()).
+				Msgf("Error generating response for typeURI: %s", typeURI.Short())
+			return nil, err
+		}
+		resources[typeURI.String()] = typeResources
+	}
+	return resources, nil
+}
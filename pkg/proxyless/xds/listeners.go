@@ -0,0 +1,203 @@
+package xds
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/golang/protobuf/ptypes/any"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/errcode"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/models"
+	"github.com/openservicemesh/osm/pkg/proxyless"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+const (
+	// inboundVirtualHostName is the name of the virtual host of the server listeners
+	inboundVirtualHostName = "inbound"
+
+	// inboundStatPrefix is the stat prefix of the HTTP connection manager of the server listeners
+	inboundStatPrefix = "inbound"
+)
+
+// serverListenAddresses are the wildcard addresses gRPC servers listen on, ex. when listening on ':port'
+var serverListenAddresses = []string{"0.0.0.0", "::"}
+
+// outboundListenerName returns the name of the API listener of the given upstream service, which is the target gRPC
+// clients dial with the 'xds:///' scheme
+func outboundListenerName(svc service.MeshService) string {
+	return fmt.Sprintf("%s:%d", svc.FQDN(), svc.Port)
+}
+
+// inboundListenerName returns the name of the listener requested by gRPC servers listening on the given address
+func inboundListenerName(address string, port uint16) string {
+	return proxyless.InboundListenerNamePrefix + net.JoinHostPort(address, strconv.Itoa(int(port)))
+}
+
+// generateLDS returns the API listeners of the upstream services the proxy is allowed to connect to, and the server
+// listeners of the ports of the proxy's services.
+func (g *Generator) generateLDS(_ context.Context, proxy *models.Proxy) ([]types.Resource, error) {
+	var listeners []types.Resource
+
+	names := make(map[string]struct{})
+	for _, svc := range g.catalog.ListOutboundServicesForIdentity(proxy.Identity) {
+		if svc.Protocol == constants.ProtocolTCP || svc.Protocol == constants.ProtocolTCPServerFirst {
+			continue
+		}
+		name := outboundListenerName(svc)
+		if _, ok := names[name]; ok {
+			continue
+		}
+		names[name] = struct{}{}
+
+		listener, err := getOutboundListener(name)
+		if err != nil {
+			return nil, err
+		}
+		listeners = append(listeners, listener)
+	}
+
+	inbound, err := g.getInboundListeners(proxy)
+	if err != nil {
+		return nil, err
+	}
+	return append(listeners, inbound...), nil
+}
+
+// getOutboundListener returns the API listener with the given name, whose routes are fetched over RDS
+func getOutboundListener(name string) (*xds_listener.Listener, error) {
+	hcm, err := anypb.New(&xds_hcm.HttpConnectionManager{
+		HttpFilters: []*xds_hcm.HttpFilter{getRouterFilter()},
+		RouteSpecifier: &xds_hcm.HttpConnectionManager_Rds{
+			Rds: &xds_hcm.Rds{
+				ConfigSource:    envoy.GetADSConfigSource(),
+				RouteConfigName: name,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &xds_listener.Listener{
+		Name:        name,
+		ApiListener: &xds_listener.ApiListener{ApiListener: hcm},
+	}, nil
+}
+
+// getInboundListeners returns the server listeners of the target ports of the proxy's services. No listener is
+// returned when no downstream is allowed to connect, which keeps gRPC servers from serving.
+func (g *Generator) getInboundListeners(proxy *models.Proxy) ([]types.Resource, error) {
+	services, err := g.catalog.ListServicesForProxy(proxy)
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrFetchingServiceList)).
+			Str("proxy", proxy.String()).Msg("Error looking up MeshServices associated with proxy")
+		return nil, err
+	}
+
+	// In permissive mode any mesh downstream is allowed, so that no identity is matched
+	var downstreamIdentities []identity.ServiceIdentity
+	if !g.catalog.GetMeshConfig().Spec.Traffic.EnablePermissiveTrafficPolicyMode {
+		downstreamIdentities = g.catalog.ListInboundServiceIdentities(proxy.Identity)
+		if len(downstreamIdentities) == 0 {
+			return nil, nil
+		}
+	}
+
+	transportSocket, err := getDownstreamTransportSocket(downstreamIdentities, g.certManager.GetIssuersInfo())
+	if err != nil {
+		log.Error().Err(err).Str("proxy", proxy.String()).Msg("Error marshalling DownstreamTlsContext")
+		return nil, err
+	}
+
+	var listeners []types.Resource
+	ports := make(map[uint16]struct{})
+	for _, svc := range services {
+		if _, ok := ports[svc.TargetPort]; ok {
+			continue
+		}
+		ports[svc.TargetPort] = struct{}{}
+
+		for _, address := range serverListenAddresses {
+			listener, err := getInboundListener(address, svc.TargetPort, transportSocket)
+			if err != nil {
+				return nil, err
+			}
+			listeners = append(listeners, listener)
+		}
+	}
+	return listeners, nil
+}
+
+// getInboundListener returns the server listener of the given address and port, which accepts the mTLS connections
+// of the downstreams and serves them locally
+func getInboundListener(address string, port uint16, transportSocket *xds_core.TransportSocket) (*xds_listener.Listener, error) {
+	hcm, err := anypb.New(&xds_hcm.HttpConnectionManager{
+		StatPrefix:  inboundStatPrefix,
+		HttpFilters: []*xds_hcm.HttpFilter{getRouterFilter()},
+		RouteSpecifier: &xds_hcm.HttpConnectionManager_RouteConfig{
+			RouteConfig: &xds_route.RouteConfiguration{
+				Name: inboundVirtualHostName,
+				VirtualHosts: []*xds_route.VirtualHost{
+					{
+						Name:    inboundVirtualHostName,
+						Domains: []string{"*"},
+						Routes: []*xds_route.Route{
+							{
+								Match: &xds_route.RouteMatch{
+									PathSpecifier: &xds_route.RouteMatch_Prefix{Prefix: "/"},
+								},
+								// gRPC servers require routes not to forward the requests
+								Action: &xds_route.Route_NonForwardingAction{
+									NonForwardingAction: &xds_route.NonForwardingAction{},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &xds_listener.Listener{
+		Name:    inboundListenerName(address, port),
+		Address: envoy.GetAddress(address, uint32(port)),
+		FilterChains: []*xds_listener.FilterChain{
+			{
+				Name: inboundListenerName(address, port),
+				Filters: []*xds_listener.Filter{
+					{
+						Name:       envoy.HTTPConnectionManagerFilterName,
+						ConfigType: &xds_listener.Filter_TypedConfig{TypedConfig: hcm},
+					},
+				},
+				TransportSocket: transportSocket,
+			},
+		},
+	}, nil
+}
+
+// getRouterFilter returns the router filter, the only HTTP filter gRPC requires
+func getRouterFilter() *xds_hcm.HttpFilter {
+	return &xds_hcm.HttpFilter{
+		Name: envoy.HTTPRouterFilterName,
+		ConfigType: &xds_hcm.HttpFilter_TypedConfig{
+			TypedConfig: &any.Any{
+				TypeUrl: envoy.HTTPRouterFilterTypeURL,
+			},
+		},
+	}
+}
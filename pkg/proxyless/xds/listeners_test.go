@@ -0,0 +1,91 @@
+package xds
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	catalogFake "github.com/openservicemesh/osm/pkg/catalog/fake"
+	tresorFake "github.com/openservicemesh/osm/pkg/certificate/providers/tresor/fake"
+	"github.com/openservicemesh/osm/pkg/compute"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/models"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+func TestListenerNames(t *testing.T) {
+	assert := tassert.New(t)
+
+	svc := service.MeshService{Name: "greeter", Namespace: "ns", Port: 50051}
+	assert.Equal("greeter.ns.svc.cluster.local:50051", outboundListenerName(svc))
+	assert.Equal("osm/grpc/inbound/0.0.0.0:50051", inboundListenerName("0.0.0.0", 50051))
+	assert.Equal("osm/grpc/inbound/[::]:50051", inboundListenerName("::", 50051))
+}
+
+func TestGenerateLDS(t *testing.T) {
+	proxy := models.NewProxy(models.KindProxylessGRPC, uuid.New(), identity.New(tests.BookstoreServiceAccountName, tests.Namespace), nil, 1)
+
+	testCases := []struct {
+		name              string
+		permissive        bool
+		outbound          []service.MeshService
+		expectedListeners []string
+	}{
+		{
+			name:       "permissive mode",
+			permissive: true,
+			outbound: []service.MeshService{
+				{Name: "greeter", Namespace: tests.Namespace, Port: 50051, Protocol: constants.ProtocolGRPC},
+				{Name: "greeter", Namespace: tests.Namespace, Port: 50051, Protocol: constants.ProtocolGRPC},
+				{Name: "db", Namespace: tests.Namespace, Port: 5432, Protocol: constants.ProtocolTCP},
+			},
+			expectedListeners: []string{
+				"greeter.default.svc.cluster.local:50051",
+				"osm/grpc/inbound/0.0.0.0:8080",
+				"osm/grpc/inbound/[::]:8080",
+			},
+		},
+		{
+			name:              "no inbound listener without allowed downstreams",
+			permissive:        false,
+			expectedListeners: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			provider := compute.NewMockInterface(mockCtrl)
+
+			provider.EXPECT().GetMeshConfig().Return(configv1alpha2.MeshConfig{
+				Spec: configv1alpha2.MeshConfigSpec{
+					Traffic: configv1alpha2.TrafficSpec{EnablePermissiveTrafficPolicyMode: tc.permissive},
+				},
+			}).AnyTimes()
+			provider.EXPECT().ListServicesForProxy(proxy).Return([]service.MeshService{
+				{Name: tests.BookstoreServiceName, Namespace: tests.Namespace, Port: 80, TargetPort: 8080},
+			}, nil).AnyTimes()
+			provider.EXPECT().ListTrafficTargets().Return(nil).AnyTimes()
+			provider.EXPECT().ListServices().Return(tc.outbound).AnyTimes()
+
+			g := NewGenerator(catalogFake.NewFakeMeshCatalog(provider), tresorFake.NewFake(time.Hour))
+			resources, err := g.generateLDS(context.Background(), proxy)
+			assert.NoError(err)
+
+			var names []string
+			for _, r := range resources {
+				names = append(names, r.(*xds_listener.Listener).Name)
+			}
+			assert.Equal(tc.expectedListeners, names)
+		})
+	}
+}
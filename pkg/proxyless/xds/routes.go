@@ -0,0 +1,170 @@
+package xds
+
+import (
+	"context"
+	"sort"
+
+	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	xds_matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/openservicemesh/osm/pkg/models"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// generateRDS returns the route configs of the API listeners of the upstream services the proxy is allowed to
+// connect to. The server listeners have inline route configs.
+func (g *Generator) generateRDS(_ context.Context, proxy *models.Proxy) ([]types.Resource, error) {
+	policiesPerPort := g.catalog.GetOutboundMeshHTTPRouteConfigsPerPort(proxy.Identity)
+
+	var routeConfigs []types.Resource
+	names := make(map[string]struct{})
+	for _, svc := range g.catalog.ListOutboundServicesForIdentity(proxy.Identity) {
+		name := outboundListenerName(svc)
+		if _, ok := names[name]; ok {
+			continue
+		}
+		policy := getOutboundTrafficPolicy(policiesPerPort[int(svc.Port)], svc)
+		if policy == nil {
+			continue
+		}
+		names[name] = struct{}{}
+		routeConfigs = append(routeConfigs, getOutboundRouteConfig(name, policy))
+	}
+	return routeConfigs, nil
+}
+
+// getOutboundTrafficPolicy returns the outbound traffic policy of the given service among the given policies, or nil
+// if the service has none, ex. because it is a TCP service
+func getOutboundTrafficPolicy(policies []*trafficpolicy.OutboundTrafficPolicy, svc service.MeshService) *trafficpolicy.OutboundTrafficPolicy {
+	for _, policy := range policies {
+		if policy.Name == svc.FQDN() {
+			return policy
+		}
+	}
+	return nil
+}
+
+// getOutboundRouteConfig returns the route config with the given name for the given outbound traffic policy
+func getOutboundRouteConfig(name string, policy *trafficpolicy.OutboundTrafficPolicy) *xds_route.RouteConfiguration {
+	// gRPC selects the virtual host matching the authority of the target, which is the name of the listener
+	domains := append([]string{name}, policy.Hostnames...)
+
+	var routes []*xds_route.Route
+	for _, rwc := range policy.Routes {
+		if route := getOutboundRoute(rwc); route != nil {
+			routes = append(routes, route)
+		}
+	}
+
+	return &xds_route.RouteConfiguration{
+		Name: name,
+		VirtualHosts: []*xds_route.VirtualHost{
+			{
+				Name:    name,
+				Domains: domains,
+				Routes:  routes,
+			},
+		},
+	}
+}
+
+// getOutboundRoute returns the route for the given route and weighted clusters. The method matches are ignored, as
+// gRPC requests are all POST requests.
+func getOutboundRoute(rwc *trafficpolicy.RouteWeightedClusters) *xds_route.Route {
+	weightedClusters := getWeightedClusters(rwc.WeightedClusters.ToSlice())
+	if weightedClusters == nil {
+		return nil
+	}
+
+	route := &xds_route.Route{
+		Match: &xds_route.RouteMatch{
+			Headers: getHeaderMatchers(rwc.HTTPRouteMatch.Headers),
+		},
+		Action: &xds_route.Route_Route{
+			Route: &xds_route.RouteAction{
+				ClusterSpecifier: &xds_route.RouteAction_WeightedClusters{
+					WeightedClusters: weightedClusters,
+				},
+				RetryPolicy: getRetryPolicy(rwc),
+			},
+		},
+	}
+
+	switch {
+	case rwc.HTTPRouteMatch.Path == trafficpolicy.WildCardRouteMatch.Path:
+		route.Match.PathSpecifier = &xds_route.RouteMatch_Prefix{Prefix: "/"}
+	case rwc.HTTPRouteMatch.PathMatchType == trafficpolicy.PathMatchExact:
+		route.Match.PathSpecifier = &xds_route.RouteMatch_Path{Path: rwc.HTTPRouteMatch.Path}
+	case rwc.HTTPRouteMatch.PathMatchType == trafficpolicy.PathMatchPrefix:
+		route.Match.PathSpecifier = &xds_route.RouteMatch_Prefix{Prefix: rwc.HTTPRouteMatch.Path}
+	default:
+		route.Match.PathSpecifier = &xds_route.RouteMatch_SafeRegex{
+			SafeRegex: &xds_matcher.RegexMatcher{
+				EngineType: &xds_matcher.RegexMatcher_GoogleRe2{GoogleRe2: &xds_matcher.RegexMatcher_GoogleRE2{}},
+				Regex:      rwc.HTTPRouteMatch.Path,
+			},
+		}
+	}
+	return route
+}
+
+// getWeightedClusters returns the given weighted clusters sorted by name, or nil if their total weight is 0
+func getWeightedClusters(clusters []interface{}) *xds_route.WeightedCluster {
+	var wc xds_route.WeightedCluster
+	var total int
+	for _, c := range clusters {
+		cluster := c.(service.WeightedCluster)
+		total += cluster.Weight
+		wc.Clusters = append(wc.Clusters, &xds_route.WeightedCluster_ClusterWeight{
+			Name:   cluster.ClusterName.String(),
+			Weight: wrapperspb.UInt32(uint32(cluster.Weight)),
+		})
+	}
+	if total < 1 {
+		log.Error().Msgf("Total weight of weighted cluster must be >= 1, got %d", total)
+		return nil
+	}
+	wc.TotalWeight = wrapperspb.UInt32(uint32(total))
+	sort.Slice(wc.Clusters, func(i, j int) bool {
+		return wc.Clusters[i].Name < wc.Clusters[j].Name
+	})
+	return &wc
+}
+
+// getHeaderMatchers returns the regex matchers of the given headers
+func getHeaderMatchers(headers map[string]string) []*xds_route.HeaderMatcher {
+	var matchers []*xds_route.HeaderMatcher
+	for name, value := range headers {
+		matchers = append(matchers, &xds_route.HeaderMatcher{
+			Name: name,
+			HeaderMatchSpecifier: &xds_route.HeaderMatcher_SafeRegexMatch{
+				SafeRegexMatch: &xds_matcher.RegexMatcher{
+					EngineType: &xds_matcher.RegexMatcher_GoogleRe2{GoogleRe2: &xds_matcher.RegexMatcher_GoogleRE2{}},
+					Regex:      value,
+				},
+			},
+		})
+	}
+	sort.Slice(matchers, func(i, j int) bool {
+		return matchers[i].Name < matchers[j].Name
+	})
+	return matchers
+}
+
+// getRetryPolicy returns the retry policy of the given route. gRPC only retries on the gRPC status codes listed in
+// retryOn, ex. 'unavailable', and ignores the other conditions.
+func getRetryPolicy(rwc *trafficpolicy.RouteWeightedClusters) *xds_route.RetryPolicy {
+	if rwc.RetryPolicy == nil {
+		return nil
+	}
+	policy := &xds_route.RetryPolicy{
+		RetryOn: rwc.RetryPolicy.RetryOn,
+	}
+	if rwc.RetryPolicy.NumRetries != nil {
+		policy.NumRetries = wrapperspb.UInt32(*rwc.RetryPolicy.NumRetries)
+	}
+	return policy
+}
@@ -0,0 +1,84 @@
+package xds
+
+import (
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	xds_matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/proxyless"
+)
+
+// transportSocketName is the name of the TLS transport socket, which gRPC requires
+const transportSocketName = "envoy.transport_sockets.tls"
+
+// getCommonTLSContext returns the TLS context presenting the service certificate and validating the peer certificate
+// against the mesh's trusted CAs, and against the given identities if any. Both are loaded by gRPC through the
+// certificate provider instance configured in the bootstrap config.
+func getCommonTLSContext(allowedIdentities []identity.ServiceIdentity, issuers certificate.IssuerInfo) *xds_auth.CommonTlsContext {
+	return &xds_auth.CommonTlsContext{
+		TlsCertificateProviderInstance: &xds_auth.CertificateProviderPluginInstance{
+			InstanceName: proxyless.CertificateProviderInstance,
+		},
+		ValidationContextType: &xds_auth.CommonTlsContext_ValidationContext{
+			ValidationContext: &xds_auth.CertificateValidationContext{
+				CaCertificateProviderInstance: &xds_auth.CertificateProviderPluginInstance{
+					InstanceName: proxyless.CertificateProviderInstance,
+				},
+				MatchSubjectAltNames: getSubjectAltNameMatchers(allowedIdentities, issuers),
+			},
+		},
+	}
+}
+
+// getSubjectAltNameMatchers returns the matchers of the SANs of the given identities in the trust domains of the
+// signing and validating issuers
+func getSubjectAltNameMatchers(identities []identity.ServiceIdentity, issuers certificate.IssuerInfo) []*xds_matcher.StringMatcher {
+	var matchers []*xds_matcher.StringMatcher
+	for _, si := range identities {
+		principals := []string{si.AsPrincipal(issuers.Signing.TrustDomain, issuers.Signing.SpiffeEnabled)}
+		if issuers.AreDifferent() {
+			principals = append(principals, si.AsPrincipal(issuers.Validating.TrustDomain, issuers.Validating.SpiffeEnabled))
+		}
+		for _, principal := range principals {
+			matchers = append(matchers, &xds_matcher.StringMatcher{
+				MatchPattern: &xds_matcher.StringMatcher_Exact{Exact: principal},
+			})
+		}
+	}
+	return matchers
+}
+
+// getUpstreamTransportSocket returns the transport socket of the clusters of the upstream services with the given
+// identities
+func getUpstreamTransportSocket(upstreamIdentities []identity.ServiceIdentity, issuers certificate.IssuerInfo) (*xds_core.TransportSocket, error) {
+	tlsContext, err := anypb.New(&xds_auth.UpstreamTlsContext{
+		CommonTlsContext: getCommonTLSContext(upstreamIdentities, issuers),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &xds_core.TransportSocket{
+		Name:       transportSocketName,
+		ConfigType: &xds_core.TransportSocket_TypedConfig{TypedConfig: tlsContext},
+	}, nil
+}
+
+// getDownstreamTransportSocket returns the transport socket of the server listeners accepting connections from the
+// downstreams with the given identities. No identity is matched if none is given.
+func getDownstreamTransportSocket(downstreamIdentities []identity.ServiceIdentity, issuers certificate.IssuerInfo) (*xds_core.TransportSocket, error) {
+	tlsContext, err := anypb.New(&xds_auth.DownstreamTlsContext{
+		CommonTlsContext:         getCommonTLSContext(downstreamIdentities, issuers),
+		RequireClientCertificate: wrapperspb.Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &xds_core.TransportSocket{
+		Name:       transportSocketName,
+		ConfigType: &xds_core.TransportSocket_TypedConfig{TypedConfig: tlsContext},
+	}, nil
+}
@@ -18,7 +18,7 @@ import (
 )
 
 // NewReconcilerClient implements a client to reconcile osm managed resources
-func NewReconcilerClient(kubeClient kubernetes.Interface, apiServerClient clientset.Interface, meshName, osmVersion string, stop chan struct{}, selectInformers ...InformerKey) error {
+func NewReconcilerClient(kubeClient kubernetes.Interface, apiServerClient clientset.Interface, meshName, osmVersion string, stop <-chan struct{}, selectInformers ...InformerKey) error {
 	// Initialize client object
 	c := client{
 		kubeClient:      kubeClient,
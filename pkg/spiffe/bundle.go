@@ -0,0 +1,74 @@
+// Package spiffe implements the SPIFFE bundle endpoint through which the mesh exposes its trust bundle to foreign
+// trust domains, and the parsing of the trust bundles of the foreign trust domains federated with the mesh.
+package spiffe
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/spiffe/go-spiffe/v2/bundle/spiffebundle"
+	"github.com/spiffe/go-spiffe/v2/bundle/x509bundle"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	"github.com/openservicemesh/osm/pkg/certificate/pem"
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var log = logger.New("spiffe")
+
+// ParseTrustBundle parses the trust bundle of the given trust domain. The bundle is either a set of PEM encoded
+// X.509 CA certificates, or a SPIFFE bundle in JWK Set format.
+func ParseTrustBundle(trustDomain string, data []byte) (*spiffebundle.Bundle, error) {
+	td, err := spiffeid.TrustDomainFromString(trustDomain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trust domain %q: %w", trustDomain, err)
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return spiffebundle.Parse(td, data)
+	}
+
+	x509Bundle, err := x509bundle.Parse(td, data)
+	if err != nil {
+		return nil, err
+	}
+	return spiffebundle.FromX509Bundle(x509Bundle), nil
+}
+
+// ParseFederations returns the trust bundles of the given federated trust domains. Federations with an invalid
+// trust domain or trust bundle, or without any X.509 authority, are logged and skipped so they do not affect the others.
+func ParseFederations(federations []*configv1alpha2.TrustDomainFederation) []*spiffebundle.Bundle {
+	var bundles []*spiffebundle.Bundle
+	for _, federation := range federations {
+		bundle, err := ParseTrustBundle(federation.Spec.TrustDomain, []byte(federation.Spec.TrustBundle))
+		if err != nil {
+			log.Error().Err(err).Msgf("Error parsing trust bundle of TrustDomainFederation %s/%s, skipping",
+				federation.Namespace, federation.Name)
+			continue
+		}
+		if len(bundle.X509Authorities()) == 0 {
+			log.Warn().Msgf("Trust bundle of TrustDomainFederation %s/%s has no X.509 authorities, skipping",
+				federation.Namespace, federation.Name)
+			continue
+		}
+		bundles = append(bundles, bundle)
+	}
+	return bundles
+}
+
+// GetFederatedTrustBundles returns the PEM encoded X.509 authorities of the given federated trust domains, keyed by
+// trust domain. The CAs of a trust domain must only be trusted to authenticate the SPIFFE IDs of that trust domain.
+func GetFederatedTrustBundles(federations []*configv1alpha2.TrustDomainFederation) map[string]pem.RootCertificate {
+	trustBundles := make(map[string]pem.RootCertificate)
+	for _, bundle := range ParseFederations(federations) {
+		pemCAs, err := bundle.X509Bundle().Marshal()
+		if err != nil {
+			log.Error().Err(err).Msgf("Error encoding trust bundle of trust domain %s, skipping", bundle.TrustDomain())
+			continue
+		}
+		trustDomain := bundle.TrustDomain().String()
+		trustBundles[trustDomain] = append(trustBundles[trustDomain], pemCAs...)
+	}
+	return trustBundles
+}
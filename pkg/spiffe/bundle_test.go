@@ -0,0 +1,182 @@
+package spiffe
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/certificate/pem"
+	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
+	tresorFake "github.com/openservicemesh/osm/pkg/certificate/providers/tresor/fake"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func newRootCertificate(t *testing.T, cn certificate.CommonName) pem.RootCertificate {
+	t.Helper()
+	ca, err := tresor.NewCA(cn, time.Hour, "US", "Seattle", "Open Service Mesh")
+	tassert.NoError(t, err)
+	return pem.RootCertificate(ca.GetCertificateChain())
+}
+
+func TestParseTrustBundle(t *testing.T) {
+	assert := tassert.New(t)
+	ca := newRootCertificate(t, "other-domain")
+
+	// PEM encoded CA certificates
+	bundle, err := ParseTrustBundle("other-domain", ca)
+	assert.NoError(err)
+	assert.Equal("other-domain", bundle.TrustDomain().String())
+	assert.Len(bundle.X509Authorities(), 1)
+
+	// SPIFFE bundle in JWK Set format
+	jwks, err := bundle.Marshal()
+	assert.NoError(err)
+	bundle, err = ParseTrustBundle("other-domain", jwks)
+	assert.NoError(err)
+	assert.Len(bundle.X509Authorities(), 1)
+
+	_, err = ParseTrustBundle("Not A Trust Domain", ca)
+	assert.Error(err)
+
+	_, err = ParseTrustBundle("other-domain", []byte("not a bundle"))
+	assert.Error(err)
+
+	_, err = ParseTrustBundle("other-domain", []byte(`{"keys": "invalid"}`))
+	assert.Error(err)
+}
+
+func TestGetFederatedTrustBundles(t *testing.T) {
+	assert := tassert.New(t)
+	ca := newRootCertificate(t, "other-domain")
+
+	federation := func(name, trustDomain string, trustBundle []byte) *configv1alpha2.TrustDomainFederation {
+		return &configv1alpha2.TrustDomainFederation{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "osm-system"},
+			Spec: configv1alpha2.TrustDomainFederationSpec{
+				TrustDomain: trustDomain,
+				TrustBundle: string(trustBundle),
+			},
+		}
+	}
+
+	assert.Empty(GetFederatedTrustBundles(nil))
+
+	otherCA := newRootCertificate(t, "third-domain")
+	trustBundles := GetFederatedTrustBundles([]*configv1alpha2.TrustDomainFederation{
+		federation("valid", "other-domain", ca),
+		federation("other-valid", "third-domain", otherCA),
+		federation("invalid-bundle", "another-domain", []byte("not a bundle")),
+		federation("no-x509-authorities", "empty-domain", []byte(`{"keys": []}`)),
+	})
+	assert.Equal(map[string]pem.RootCertificate{"other-domain": ca, "third-domain": otherCA}, trustBundles)
+}
+
+type fakeTrustBundleProvider struct {
+	trustDomain string
+	cas         pem.RootCertificate
+}
+
+func (p *fakeTrustBundleProvider) GetTrustBundle() (string, pem.RootCertificate) {
+	return p.trustDomain, p.cas
+}
+
+func TestBundleHandler(t *testing.T) {
+	assert := tassert.New(t)
+
+	provider := &fakeTrustBundleProvider{trustDomain: "cluster.local", cas: newRootCertificate(t, "osm-ca.openservicemesh.io")}
+	handler := GetBundleHandler(provider)
+
+	get := func() map[string]interface{} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/spiffe/bundle", nil))
+		assert.Equal(http.StatusOK, rr.Code)
+		assert.Equal("application/json", rr.Header().Get("Content-Type"))
+
+		bundle, err := ParseTrustBundle("cluster.local", rr.Body.Bytes())
+		assert.NoError(err)
+		assert.NotEmpty(bundle.X509Authorities())
+
+		var doc map[string]interface{}
+		assert.NoError(json.Unmarshal(rr.Body.Bytes(), &doc))
+		return doc
+	}
+
+	doc := get()
+	assert.EqualValues(1, doc["spiffe_sequence"])
+	assert.EqualValues(bundleRefreshHint.Seconds(), doc["spiffe_refresh_hint"])
+
+	// The sequence number only changes along with the bundle
+	doc = get()
+	assert.EqualValues(1, doc["spiffe_sequence"])
+
+	provider.cas = append(provider.cas, newRootCertificate(t, "osm-ca-2.openservicemesh.io")...)
+	doc = get()
+	assert.EqualValues(2, doc["spiffe_sequence"])
+	assert.Len(doc["keys"], 2)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/spiffe/bundle", nil))
+	assert.Equal(http.StatusMethodNotAllowed, rr.Code)
+
+	provider.trustDomain = "Invalid Trust Domain"
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/spiffe/bundle", nil))
+	assert.Equal(http.StatusInternalServerError, rr.Code)
+}
+
+func TestServeBundleEndpoint(t *testing.T) {
+	assert := tassert.New(t)
+
+	certManager := tresorFake.NewFake(time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(ServeBundleEndpoint(ctx, "osm-system", certManager))
+
+	// Foreign trust domains authenticate the endpoint with the mesh's own trust bundle
+	trustDomain, cas := certManager.GetTrustBundle()
+	roots := x509.NewCertPool()
+	assert.True(roots.AppendCertsFromPEM(cas))
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				RootCAs:    roots,
+				ServerName: "osm-controller.osm-system.svc",
+				MinVersion: constants.MinTLSVersion,
+			},
+		},
+	}
+	url := fmt.Sprintf("https://localhost:%d%s", constants.SpiffeBundleEndpointPort, constants.OSMControllerSpiffeBundlePath)
+
+	var body []byte
+	assert.Eventually(func() bool {
+		resp, err := client.Get(url) //#nosec G107: Potential HTTP request made with variable url
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close() //nolint: errcheck,gosec
+		body, err = io.ReadAll(resp.Body)
+		return err == nil && resp.StatusCode == http.StatusOK
+	}, 5*time.Second, 50*time.Millisecond)
+
+	bundle, err := ParseTrustBundle(trustDomain, body)
+	assert.NoError(err)
+	assert.NotEmpty(bundle.X509Authorities())
+
+	// The endpoint is not served over plain HTTP
+	resp, err := http.Get(fmt.Sprintf("http://localhost:%d%s", constants.SpiffeBundleEndpointPort, constants.OSMControllerSpiffeBundlePath)) //#nosec G107: Potential HTTP request made with variable url
+	assert.NoError(err)
+	defer resp.Body.Close() //nolint: errcheck,gosec
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,89 @@
+package spiffe
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/certificate/pem"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/webhook"
+)
+
+// bundleRefreshHint is the interval at which foreign trust domains are advised to poll the bundle endpoint.
+// It is kept well below the validity of a root certificate rotation stage.
+const bundleRefreshHint = 5 * time.Minute
+
+// TrustBundleProvider provides the mesh's trust domain and the PEM encoded root certificates it currently trusts.
+type TrustBundleProvider interface {
+	GetTrustBundle() (string, pem.RootCertificate)
+}
+
+// bundleHandler serves the mesh's trust bundle in the SPIFFE bundle format, bumping the bundle's sequence
+// number each time the root certificates change.
+type bundleHandler struct {
+	provider TrustBundleProvider
+
+	mu       sync.Mutex
+	lastCAs  pem.RootCertificate
+	sequence uint64
+}
+
+// ServeBundleEndpoint serves the mesh's trust bundle over HTTPS on its own port until the context is cancelled. The
+// server's certificate is issued by the mesh and is an X509-SVID of the mesh's trust domain when SPIFFE is enabled, so
+// foreign trust domains can authenticate the endpoint with the https_spiffe profile.
+func ServeBundleEndpoint(ctx context.Context, osmNamespace string, certManager *certificate.Manager) error {
+	srv := webhook.NewServer(constants.OSMControllerName, osmNamespace, constants.SpiffeBundleEndpointPort, certManager, map[string]http.HandlerFunc{
+		constants.OSMControllerSpiffeBundlePath: GetBundleHandler(certManager).ServeHTTP,
+	}, func(*certificate.Certificate) error {
+		return nil
+	})
+	return srv.Run(ctx)
+}
+
+// GetBundleHandler returns an HTTP handler that serves the mesh's trust bundle as a SPIFFE bundle endpoint
+func GetBundleHandler(provider TrustBundleProvider) http.Handler {
+	return &bundleHandler{provider: provider}
+}
+
+func (h *bundleHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	trustDomain, cas := h.provider.GetTrustBundle()
+	bundle, err := ParseTrustBundle(trustDomain, cas)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error building SPIFFE bundle for trust domain %s", trustDomain)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	bundle.SetRefreshHint(bundleRefreshHint)
+	bundle.SetSequenceNumber(h.sequenceFor(cas))
+
+	jwks, err := bundle.Marshal()
+	if err != nil {
+		log.Error().Err(err).Msgf("Error marshaling SPIFFE bundle for trust domain %s", trustDomain)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(jwks)
+}
+
+// sequenceFor returns the sequence number of the bundle made of the given root certificates
+func (h *bundleHandler) sequenceFor(cas pem.RootCertificate) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.sequence == 0 || !bytes.Equal(h.lastCAs, cas) {
+		h.sequence++
+		h.lastCAs = cas
+	}
+	return h.sequence
+}
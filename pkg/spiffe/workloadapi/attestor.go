@@ -0,0 +1,125 @@
+package workloadapi
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
+)
+
+// Attestor determines the identity of the workload making a Workload API request
+type Attestor interface {
+	Attest(ctx context.Context) (identity.ServiceIdentity, error)
+}
+
+const podUIDIndex = "podUID"
+
+// podUIDPattern matches the pod UID in the cgroup paths of a container, for both the cgroupfs
+// (.../pod<uid>/<container>) and systemd (...-pod<uid with underscores>.slice/...) cgroup drivers
+var podUIDPattern = regexp.MustCompile(`pod([0-9a-f]{8}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{12})`)
+
+// KubernetesAttestor attests callers as the service account of the pod they run in. The pod is found from the
+// cgroup of the calling process, among the pods scheduled to the node in namespaces monitored by the mesh.
+type KubernetesAttestor struct {
+	procRoot   string
+	pods       cache.Indexer
+	namespaces corev1listers.NamespaceLister
+}
+
+// NewKubernetesAttestor returns a KubernetesAttestor for the pods scheduled to the given node, once its caches are synced
+func NewKubernetesAttestor(kubeClient kubernetes.Interface, nodeName, meshName string, stop <-chan struct{}) (*KubernetesAttestor, error) {
+	podInformerFactory := informers.NewSharedInformerFactoryWithOptions(kubeClient, k8s.DefaultKubeEventResyncInterval,
+		informers.WithTweakListOptions(func(opt *metav1.ListOptions) {
+			opt.FieldSelector = fields.OneTermEqualSelector("spec.nodeName", nodeName).String()
+		}))
+	nsInformerFactory := informers.NewSharedInformerFactoryWithOptions(kubeClient, k8s.DefaultKubeEventResyncInterval,
+		informers.WithTweakListOptions(func(opt *metav1.ListOptions) {
+			opt.LabelSelector = fields.OneTermEqualSelector(constants.OSMKubeResourceMonitorAnnotation, meshName).String()
+		}))
+
+	podInformer := podInformerFactory.Core().V1().Pods().Informer()
+	if err := podInformer.AddIndexers(cache.Indexers{podUIDIndex: func(obj interface{}) ([]string, error) {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok {
+			return nil, nil
+		}
+		return []string{string(pod.UID)}, nil
+	}}); err != nil {
+		return nil, err
+	}
+	nsInformer := nsInformerFactory.Core().V1().Namespaces()
+	nsInformer.Informer()
+
+	podInformerFactory.Start(stop)
+	nsInformerFactory.Start(stop)
+	if !cache.WaitForCacheSync(stop, podInformer.HasSynced, nsInformer.Informer().HasSynced) {
+		return nil, fmt.Errorf("error syncing pod and namespace caches of node %s", nodeName)
+	}
+
+	return &KubernetesAttestor{
+		procRoot:   "/proc",
+		pods:       podInformer.GetIndexer(),
+		namespaces: nsInformer.Lister(),
+	}, nil
+}
+
+// Attest returns the identity of the pod running the calling process
+func (a *KubernetesAttestor) Attest(ctx context.Context) (identity.ServiceIdentity, error) {
+	pid, err := getCallerPID(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	podUID, err := a.getPodUID(pid)
+	if err != nil {
+		return "", err
+	}
+
+	objs, err := a.pods.ByIndex(podUIDIndex, podUID)
+	if err != nil {
+		return "", err
+	}
+	if len(objs) != 1 {
+		return "", fmt.Errorf("pod with UID %s of process %d not found on this node", podUID, pid)
+	}
+	pod := objs[0].(*corev1.Pod)
+
+	if _, err := a.namespaces.Get(pod.Namespace); err != nil {
+		return "", fmt.Errorf("pod %s/%s is not in a namespace monitored by the mesh: %w", pod.Namespace, pod.Name, err)
+	}
+
+	serviceAccount := pod.Spec.ServiceAccountName
+	if serviceAccount == "" {
+		serviceAccount = "default"
+	}
+	return identity.New(serviceAccount, pod.Namespace), nil
+}
+
+// getPodUID returns the UID of the pod the process with the given PID runs in
+func (a *KubernetesAttestor) getPodUID(pid int32) (string, error) {
+	cgroups, err := os.ReadFile(filepath.Join(a.procRoot, strconv.Itoa(int(pid)), "cgroup"))
+	if err != nil {
+		return "", fmt.Errorf("error reading cgroups of process %d: %w", pid, err)
+	}
+
+	match := podUIDPattern.FindStringSubmatch(string(cgroups))
+	if match == nil {
+		return "", fmt.Errorf("process %d does not run in a pod", pid)
+	}
+	return strings.ReplaceAll(match[1], "_", "-"), nil
+}
@@ -0,0 +1,150 @@
+package workloadapi
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/peer"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/identity"
+)
+
+func TestGetPodUID(t *testing.T) {
+	testCases := []struct {
+		name        string
+		cgroup      string
+		expectedUID string
+		expectErr   bool
+	}{
+		{
+			name:        "cgroupfs driver",
+			cgroup:      "12:memory:/kubepods/burstable/pod0d6f5d6a-2f8f-4f0e-9a36-1c1b5a4c6f7e/3a5b0c1d\n",
+			expectedUID: "0d6f5d6a-2f8f-4f0e-9a36-1c1b5a4c6f7e",
+		},
+		{
+			name:        "systemd driver",
+			cgroup:      "0::/kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod0d6f5d6a_2f8f_4f0e_9a36_1c1b5a4c6f7e.slice/cri-containerd-3a5b0c1d.scope\n",
+			expectedUID: "0d6f5d6a-2f8f-4f0e-9a36-1c1b5a4c6f7e",
+		},
+		{
+			name:      "process is not in a pod",
+			cgroup:    "0::/system.slice/kubelet.service\n",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			a := &KubernetesAttestor{procRoot: writeCgroup(t, 42, tc.cgroup)}
+
+			uid, err := a.getPodUID(42)
+			assert.Equal(tc.expectErr, err != nil)
+			assert.Equal(tc.expectedUID, uid)
+		})
+	}
+}
+
+func TestKubernetesAttestorAttest(t *testing.T) {
+	const podUID = "0d6f5d6a-2f8f-4f0e-9a36-1c1b5a4c6f7e"
+
+	testCases := []struct {
+		name             string
+		pid              int32
+		pod              *corev1.Pod
+		namespaceLabels  map[string]string
+		expectedIdentity identity.ServiceIdentity
+		expectErr        bool
+	}{
+		{
+			name: "pod in a monitored namespace",
+			pid:  42,
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns", UID: podUID},
+				Spec:       corev1.PodSpec{ServiceAccountName: "sa"},
+			},
+			namespaceLabels:  map[string]string{constants.OSMKubeResourceMonitorAnnotation: "osm"},
+			expectedIdentity: identity.New("sa", "ns"),
+		},
+		{
+			name: "pod without a service account",
+			pid:  42,
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns", UID: podUID},
+			},
+			namespaceLabels:  map[string]string{constants.OSMKubeResourceMonitorAnnotation: "osm"},
+			expectedIdentity: identity.New("default", "ns"),
+		},
+		{
+			name: "pod in a namespace monitored by another mesh",
+			pid:  42,
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns", UID: podUID},
+				Spec:       corev1.PodSpec{ServiceAccountName: "sa"},
+			},
+			namespaceLabels: map[string]string{constants.OSMKubeResourceMonitorAnnotation: "other"},
+			expectErr:       true,
+		},
+		{
+			name: "pod not found",
+			pid:  42,
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns", UID: types.UID("another-uid")},
+				Spec:       corev1.PodSpec{ServiceAccountName: "sa"},
+			},
+			namespaceLabels: map[string]string{constants.OSMKubeResourceMonitorAnnotation: "osm"},
+			expectErr:       true,
+		},
+		{
+			name:      "caller PID unknown",
+			pid:       0,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			kubeClient := fake.NewSimpleClientset()
+			if tc.pod != nil {
+				_, err := kubeClient.CoreV1().Pods(tc.pod.Namespace).Create(context.Background(), tc.pod, metav1.CreateOptions{})
+				assert.NoError(err)
+				_, err = kubeClient.CoreV1().Namespaces().Create(context.Background(), &corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{Name: tc.pod.Namespace, Labels: tc.namespaceLabels},
+				}, metav1.CreateOptions{})
+				assert.NoError(err)
+			}
+
+			stop := make(chan struct{})
+			defer close(stop)
+			a, err := NewKubernetesAttestor(kubeClient, "node", "osm", stop)
+			assert.NoError(err)
+			a.procRoot = writeCgroup(t, 42, "0::/kubepods/burstable/pod"+podUID+"/3a5b0c1d\n")
+
+			ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: peerAuthInfo{pid: tc.pid}})
+			si, err := a.Attest(ctx)
+			assert.Equal(tc.expectErr, err != nil)
+			assert.Equal(tc.expectedIdentity, si)
+		})
+	}
+}
+
+// writeCgroup writes the cgroup file of the process with the given PID to a fake proc filesystem and returns its root
+func writeCgroup(t *testing.T, pid int, cgroup string) string {
+	t.Helper()
+	procRoot := t.TempDir()
+	dir := filepath.Join(procRoot, strconv.Itoa(pid))
+	tassert.NoError(t, os.MkdirAll(dir, 0750))
+	tassert.NoError(t, os.WriteFile(filepath.Join(dir, "cgroup"), []byte(cgroup), 0600))
+	return procRoot
+}
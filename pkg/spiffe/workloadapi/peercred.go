@@ -0,0 +1,77 @@
+package workloadapi
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+var (
+	errNotUnixConn = errors.New("connection is not a unix domain socket connection")
+	errNoPeerPID   = errors.New("PID of the caller is unknown")
+)
+
+// peerCredentials are gRPC transport credentials that record the PID of the process connecting over a unix domain
+// socket, so that the caller can be attested. The connection itself is not secured, it never leaves the node.
+type peerCredentials struct{}
+
+// peerAuthInfo is the credentials.AuthInfo of a connection accepted with peerCredentials
+type peerAuthInfo struct {
+	credentials.CommonAuthInfo
+	pid int32
+}
+
+// AuthType returns the type of the authentication info
+func (peerAuthInfo) AuthType() string {
+	return "peercred"
+}
+
+// ServerHandshake records the PID of the connecting process
+func (peerCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	pid, err := getPeerPID(conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, nil, err
+	}
+	return conn, peerAuthInfo{
+		CommonAuthInfo: credentials.CommonAuthInfo{SecurityLevel: credentials.NoSecurity},
+		pid:            pid,
+	}, nil
+}
+
+// ClientHandshake is not supported, peerCredentials are only used by the server
+func (peerCredentials) ClientHandshake(_ context.Context, _ string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return nil, nil, errors.New("peer credentials are server-side only")
+}
+
+// Info returns the protocol info of the credentials
+func (peerCredentials) Info() credentials.ProtocolInfo {
+	return credentials.ProtocolInfo{SecurityProtocol: "peercred"}
+}
+
+// Clone returns a copy of the credentials
+func (c peerCredentials) Clone() credentials.TransportCredentials {
+	return c
+}
+
+// OverrideServerName is a no-op, there is no server name to verify
+func (peerCredentials) OverrideServerName(string) error {
+	return nil
+}
+
+// getCallerPID returns the PID of the process that made the request with the given context
+func getCallerPID(ctx context.Context) (int32, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return 0, errNoPeerPID
+	}
+	authInfo, ok := p.AuthInfo.(peerAuthInfo)
+	if !ok || authInfo.pid <= 0 {
+		// A PID of 0 means the caller is in a PID namespace the server cannot see
+		return 0, errNoPeerPID
+	}
+	return authInfo.pid, nil
+}
@@ -0,0 +1,32 @@
+package workloadapi
+
+import (
+	"net"
+	"syscall"
+)
+
+// getPeerPID returns the PID of the process at the other end of the given unix domain socket connection
+func getPeerPID(conn net.Conn) (int32, error) {
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return 0, errNotUnixConn
+	}
+
+	rawConn, err := unixConn.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+
+	var cred *syscall.Ucred
+	var credErr error
+	if err := rawConn.Control(func(fd uintptr) {
+		cred, credErr = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	}); err != nil {
+		return 0, err
+	}
+	if credErr != nil {
+		return 0, credErr
+	}
+
+	return cred.Pid, nil
+}
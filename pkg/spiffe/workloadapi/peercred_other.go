@@ -0,0 +1,13 @@
+//go:build !linux
+
+package workloadapi
+
+import (
+	"errors"
+	"net"
+)
+
+// getPeerPID is only supported on Linux, where the Workload API node agent runs
+func getPeerPID(_ net.Conn) (int32, error) {
+	return 0, errors.New("peer credentials are only supported on linux")
+}
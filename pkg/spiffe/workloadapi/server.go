@@ -0,0 +1,231 @@
+// Package workloadapi implements a SPIFFE Workload API server that issues X.509-SVIDs from the mesh's certificate
+// manager to workloads that consume mesh identity certificates directly rather than through an Envoy sidecar.
+package workloadapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	workload "github.com/spiffe/go-spiffe/v2/proto/spiffe/workload"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/certificate/pem"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/spiffe"
+)
+
+var log = logger.New("spiffe/workload-api")
+
+const (
+	// securityHeaderKey is the gRPC metadata key every Workload API request must carry, as a defense against
+	// server-side request forgery
+	securityHeaderKey = "workload.spiffe.io"
+
+	// securityHeaderValue is the only accepted value of the security header
+	securityHeaderValue = "true"
+
+	// defaultRefreshInterval is the interval at which streams check for trust bundle changes that are not
+	// accompanied by a certificate rotation, such as an update to a TrustDomainFederation
+	defaultRefreshInterval = 30 * time.Second
+)
+
+// CertificateManager issues the certificates served as X.509-SVIDs and provides the mesh's trust bundle
+type CertificateManager interface {
+	IssueCertificate(...certificate.IssueOption) (*certificate.Certificate, error)
+	SubscribeRotations(key string) (chan interface{}, func())
+	GetTrustBundle() (string, pem.RootCertificate)
+}
+
+// FederationLister lists the foreign trust domains federated with the mesh
+type FederationLister interface {
+	ListTrustDomainFederations() []*configv1alpha2.TrustDomainFederation
+}
+
+// Server is a SPIFFE Workload API server. X.509-SVIDs are issued for the identity determined by the Attestor,
+// and are rotated along with the certificates of Envoy sidecars of the same identity.
+type Server struct {
+	workload.UnimplementedSpiffeWorkloadAPIServer
+
+	certManager     CertificateManager
+	attestor        Attestor
+	federations     FederationLister
+	refreshInterval time.Duration
+}
+
+// NewServer returns a Workload API server
+func NewServer(certManager CertificateManager, attestor Attestor, federations FederationLister) *Server {
+	return &Server{
+		certManager:     certManager,
+		attestor:        attestor,
+		federations:     federations,
+		refreshInterval: defaultRefreshInterval,
+	}
+}
+
+// Serve serves the Workload API on a unix domain socket at the given path until the context is canceled
+func (s *Server) Serve(ctx context.Context, socketPath string) error {
+	if err := os.MkdirAll(filepath.Dir(socketPath), 0755); err != nil {
+		return fmt.Errorf("error creating directory for Workload API socket %s: %w", socketPath, err)
+	}
+	// Remove the socket left behind by a previous instance
+	if err := os.Remove(socketPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error removing stale Workload API socket %s: %w", socketPath, err)
+	}
+
+	lis, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("error listening on Workload API socket %s: %w", socketPath, err)
+	}
+	// Workloads running as any user must be able to connect, they are authorized through attestation
+	if err := os.Chmod(socketPath, 0777); err != nil { // #nosec G302
+		_ = lis.Close()
+		return fmt.Errorf("error setting permissions of Workload API socket %s: %w", socketPath, err)
+	}
+
+	grpcServer := grpc.NewServer(grpc.Creds(peerCredentials{}))
+	workload.RegisterSpiffeWorkloadAPIServer(grpcServer, s)
+
+	go func() {
+		<-ctx.Done()
+		// Streams never complete on their own, so there is no point in a graceful stop
+		grpcServer.Stop()
+	}()
+
+	log.Info().Msgf("Serving SPIFFE Workload API on %s", socketPath)
+	return grpcServer.Serve(lis)
+}
+
+// FetchX509SVID streams the X.509-SVID of the calling workload, sending a new response each time the SVID is
+// rotated or the trust bundles change.
+func (s *Server) FetchX509SVID(_ *workload.X509SVIDRequest, stream workload.SpiffeWorkloadAPI_FetchX509SVIDServer) error {
+	si, err := s.attest(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	rotations, unsubscribe := s.certManager.SubscribeRotations(si.String())
+	defer unsubscribe()
+
+	return s.stream(stream.Context(), rotations, func() (proto.Message, error) {
+		return s.x509SVIDResponse(si)
+	}, stream.SendMsg)
+}
+
+// FetchX509Bundles streams the trust bundles of the mesh and of the federated trust domains, sending a new
+// response each time they change.
+func (s *Server) FetchX509Bundles(_ *workload.X509BundlesRequest, stream workload.SpiffeWorkloadAPI_FetchX509BundlesServer) error {
+	if _, err := s.attest(stream.Context()); err != nil {
+		return err
+	}
+
+	return s.stream(stream.Context(), nil, func() (proto.Message, error) {
+		return s.x509BundlesResponse()
+	}, stream.SendMsg)
+}
+
+// stream sends the response built by next whenever it differs from the previously sent one. Responses are rebuilt
+// on each rotation and every refresh interval, until the stream's context is done.
+func (s *Server) stream(ctx context.Context, rotations <-chan interface{}, next func() (proto.Message, error), send func(interface{}) error) error {
+	ticker := time.NewTicker(s.refreshInterval)
+	defer ticker.Stop()
+
+	var last proto.Message
+	for {
+		resp, err := next()
+		if err != nil {
+			return err
+		}
+		if last == nil || !proto.Equal(last, resp) {
+			if err := send(resp); err != nil {
+				return err
+			}
+			last = resp
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-rotations:
+		case <-ticker.C:
+		}
+	}
+}
+
+// attest validates the request's security header and returns the identity of the calling workload
+func (s *Server) attest(ctx context.Context) (identity.ServiceIdentity, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(securityHeaderKey); len(values) != 1 || values[0] != securityHeaderValue {
+		return "", status.Errorf(codes.InvalidArgument, "security header %q is missing", securityHeaderKey)
+	}
+
+	si, err := s.attestor.Attest(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error attesting Workload API caller")
+		return "", status.Error(codes.PermissionDenied, "no identity issued")
+	}
+	return si, nil
+}
+
+func (s *Server) x509SVIDResponse(si identity.ServiceIdentity) (*workload.X509SVIDResponse, error) {
+	cert, err := s.certManager.IssueCertificate(certificate.ForServiceIdentity(si))
+	if err != nil {
+		log.Error().Err(err).Msgf("Error issuing X.509-SVID for identity %s", si)
+		return nil, status.Error(codes.Unavailable, "error issuing X.509-SVID")
+	}
+
+	svid, err := toX509SVID(cert)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error encoding certificate of identity %s as an X.509-SVID", si)
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	return &workload.X509SVIDResponse{
+		Svids:            []*workload.X509SVID{svid},
+		FederatedBundles: s.federatedBundles(),
+	}, nil
+}
+
+func (s *Server) x509BundlesResponse() (*workload.X509BundlesResponse, error) {
+	trustDomain, cas := s.certManager.GetTrustBundle()
+	td, err := spiffeid.TrustDomainFromString(trustDomain)
+	if err != nil {
+		log.Error().Err(err).Msgf("Mesh trust domain %s is not a valid SPIFFE trust domain", trustDomain)
+		return nil, status.Error(codes.FailedPrecondition, "invalid trust domain")
+	}
+	bundle, err := pemToDER(cas)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error encoding trust bundle of trust domain %s", trustDomain)
+		return nil, status.Error(codes.Internal, "error encoding trust bundle")
+	}
+
+	bundles := s.federatedBundles()
+	bundles[td.IDString()] = bundle
+
+	return &workload.X509BundlesResponse{Bundles: bundles}, nil
+}
+
+// federatedBundles returns the ASN.1 DER encoded bundles of the federated trust domains, keyed by trust domain ID
+func (s *Server) federatedBundles() map[string][]byte {
+	bundles := make(map[string][]byte)
+	for _, bundle := range spiffe.ParseFederations(s.federations.ListTrustDomainFederations()) {
+		var der []byte
+		for _, authority := range bundle.X509Authorities() {
+			der = append(der, authority.Raw...)
+		}
+		bundles[bundle.TrustDomain().IDString()] = der
+	}
+	return bundles
+}
@@ -0,0 +1,258 @@
+package workloadapi
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	workload "github.com/spiffe/go-spiffe/v2/proto/spiffe/workload"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/workloadapi"
+	tassert "github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/certificate/pem"
+	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
+	"github.com/openservicemesh/osm/pkg/identity"
+)
+
+const testTrustDomain = "cluster.local"
+
+// fakeCertManager issues certificates for a single identity from a tresor CA
+type fakeCertManager struct {
+	ca            *certificate.Certificate
+	issuer        *tresor.CertManager
+	spiffeEnabled bool
+	err           error
+	rotations     chan interface{}
+}
+
+func newFakeCertManager(t *testing.T, spiffeEnabled bool) *fakeCertManager {
+	t.Helper()
+	ca, err := tresor.NewCA("osm-ca", time.Hour, "US", "Seattle", "Open Service Mesh")
+	tassert.NoError(t, err)
+	issuer, err := tresor.New(ca, "Open Service Mesh", 2048)
+	tassert.NoError(t, err)
+	return &fakeCertManager{
+		ca:            ca,
+		issuer:        issuer,
+		spiffeEnabled: spiffeEnabled,
+		rotations:     make(chan interface{}),
+	}
+}
+
+func (m *fakeCertManager) IssueCertificate(_ ...certificate.IssueOption) (*certificate.Certificate, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.issuer.IssueCertificate(certificate.NewCertOptionsWithTrustDomain("sa.ns", testTrustDomain, time.Hour, m.spiffeEnabled))
+}
+
+func (m *fakeCertManager) SubscribeRotations(_ string) (chan interface{}, func()) {
+	return m.rotations, func() {}
+}
+
+func (m *fakeCertManager) GetTrustBundle() (string, pem.RootCertificate) {
+	return testTrustDomain, pem.RootCertificate(m.ca.GetCertificateChain())
+}
+
+type fakeAttestor struct {
+	err error
+}
+
+func (a fakeAttestor) Attest(_ context.Context) (identity.ServiceIdentity, error) {
+	return identity.New("sa", "ns"), a.err
+}
+
+type fakeFederationLister []*configv1alpha2.TrustDomainFederation
+
+func (l fakeFederationLister) ListTrustDomainFederations() []*configv1alpha2.TrustDomainFederation {
+	return l
+}
+
+func newFederations(t *testing.T) (fakeFederationLister, *certificate.Certificate) {
+	t.Helper()
+	ca, err := tresor.NewCA("other-ca", time.Hour, "US", "Seattle", "Open Service Mesh")
+	tassert.NoError(t, err)
+	return fakeFederationLister{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "osm-system"},
+			Spec: configv1alpha2.TrustDomainFederationSpec{
+				TrustDomain: "other.domain",
+				TrustBundle: string(ca.GetCertificateChain()),
+			},
+		},
+	}, ca
+}
+
+func withSecurityHeader(ctx context.Context) context.Context {
+	return metadata.NewIncomingContext(ctx, metadata.Pairs(securityHeaderKey, securityHeaderValue))
+}
+
+func TestAttest(t *testing.T) {
+	testCases := []struct {
+		name         string
+		ctx          context.Context
+		attestErr    error
+		expectedCode codes.Code
+	}{
+		{
+			name:         "security header is missing",
+			ctx:          context.Background(),
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "security header has an invalid value",
+			ctx:          metadata.NewIncomingContext(context.Background(), metadata.Pairs(securityHeaderKey, "false")),
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "attestation fails",
+			ctx:          withSecurityHeader(context.Background()),
+			attestErr:    errors.New("unknown caller"),
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "attestation succeeds",
+			ctx:          withSecurityHeader(context.Background()),
+			expectedCode: codes.OK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			s := NewServer(nil, fakeAttestor{err: tc.attestErr}, nil)
+
+			si, err := s.attest(tc.ctx)
+			assert.Equal(tc.expectedCode, status.Code(err))
+			if err == nil {
+				assert.Equal(identity.New("sa", "ns"), si)
+			}
+		})
+	}
+}
+
+func TestX509SVIDResponse(t *testing.T) {
+	assert := tassert.New(t)
+	federations, otherCA := newFederations(t)
+
+	certManager := newFakeCertManager(t, true)
+	s := NewServer(certManager, fakeAttestor{}, federations)
+
+	resp, err := s.x509SVIDResponse(identity.New("sa", "ns"))
+	assert.NoError(err)
+	assert.Len(resp.Svids, 1)
+	assert.Equal("spiffe://cluster.local/sa/ns", resp.Svids[0].SpiffeId)
+	assert.NotEmpty(resp.Svids[0].X509Svid)
+	assert.NotEmpty(resp.Svids[0].X509SvidKey)
+
+	caDER, err := pemToDER(certManager.ca.GetCertificateChain())
+	assert.NoError(err)
+	assert.Equal(caDER, resp.Svids[0].Bundle)
+
+	otherDER, err := pemToDER(otherCA.GetCertificateChain())
+	assert.NoError(err)
+	assert.Equal(map[string][]byte{"spiffe://other.domain": otherDER}, resp.FederatedBundles)
+
+	// Certificates without a SPIFFE ID are not X.509-SVIDs
+	certManager.spiffeEnabled = false
+	_, err = s.x509SVIDResponse(identity.New("sa", "ns"))
+	assert.Equal(codes.FailedPrecondition, status.Code(err))
+
+	certManager.err = errors.New("issuer unavailable")
+	_, err = s.x509SVIDResponse(identity.New("sa", "ns"))
+	assert.Equal(codes.Unavailable, status.Code(err))
+}
+
+func TestX509BundlesResponse(t *testing.T) {
+	assert := tassert.New(t)
+	federations, otherCA := newFederations(t)
+
+	certManager := newFakeCertManager(t, true)
+	s := NewServer(certManager, fakeAttestor{}, federations)
+
+	resp, err := s.x509BundlesResponse()
+	assert.NoError(err)
+
+	caDER, err := pemToDER(certManager.ca.GetCertificateChain())
+	assert.NoError(err)
+	otherDER, err := pemToDER(otherCA.GetCertificateChain())
+	assert.NoError(err)
+	assert.Equal(map[string][]byte{
+		"spiffe://cluster.local": caDER,
+		"spiffe://other.domain":  otherDER,
+	}, resp.Bundles)
+}
+
+func TestStream(t *testing.T) {
+	assert := tassert.New(t)
+	s := &Server{refreshInterval: time.Millisecond}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The response only changes every 5 builds, so the ticker triggers many more builds than sends
+	var builds int
+	var sent []proto.Message
+	err := s.stream(ctx, nil, func() (proto.Message, error) {
+		builds++
+		return &workload.X509BundlesResponse{Bundles: map[string][]byte{"spiffe://cluster.local": {byte(builds / 5)}}}, nil
+	}, func(msg interface{}) error {
+		sent = append(sent, msg.(proto.Message))
+		if len(sent) == 3 {
+			cancel()
+		}
+		return nil
+	})
+	assert.NoError(err)
+	assert.Len(sent, 3)
+	assert.GreaterOrEqual(builds, 10)
+
+	// Errors building a response end the stream
+	expectedErr := status.Error(codes.Unavailable, "unavailable")
+	err = s.stream(context.Background(), nil, func() (proto.Message, error) {
+		return nil, expectedErr
+	}, nil)
+	assert.Equal(expectedErr, err)
+}
+
+func TestServe(t *testing.T) {
+	assert := tassert.New(t)
+	federations, _ := newFederations(t)
+
+	certManager := newFakeCertManager(t, true)
+	s := NewServer(certManager, fakeAttestor{}, federations)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	socketPath := filepath.Join(t.TempDir(), "workload-api", "agent.sock")
+	serveErr := make(chan error)
+	go func() {
+		serveErr <- s.Serve(ctx, socketPath)
+	}()
+
+	fetchCtx, fetchCancel := context.WithTimeout(ctx, 10*time.Second)
+	defer fetchCancel()
+
+	svid, err := workloadapi.FetchX509SVID(fetchCtx, workloadapi.WithAddr("unix://"+socketPath))
+	assert.NoError(err)
+	assert.Equal("spiffe://cluster.local/sa/ns", svid.ID.String())
+
+	bundles, err := workloadapi.FetchX509Bundles(fetchCtx, workloadapi.WithAddr("unix://"+socketPath))
+	assert.NoError(err)
+	assert.True(bundles.Has(spiffeid.RequireTrustDomainFromString("cluster.local")))
+	assert.True(bundles.Has(spiffeid.RequireTrustDomainFromString("other.domain")))
+
+	cancel()
+	assert.NoError(<-serveErr)
+}
@@ -0,0 +1,83 @@
+package workloadapi
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	workload "github.com/spiffe/go-spiffe/v2/proto/spiffe/workload"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+)
+
+// errNoSpiffeID is returned for certificates issued without a SPIFFE ID, when the MeshRootCertificate in use
+// does not have spiffeEnabled set
+var errNoSpiffeID = errors.New("certificate does not have a SPIFFE ID, spiffeEnabled must be set on the MeshRootCertificate")
+
+// toX509SVID converts a certificate issued by the certificate manager to an X.509-SVID
+func toX509SVID(cert *certificate.Certificate) (*workload.X509SVID, error) {
+	chain, err := pemToDER(cert.GetCertificateChain())
+	if err != nil {
+		return nil, fmt.Errorf("error decoding certificate chain: %w", err)
+	}
+	leaf, err := x509.ParseCertificate(firstBlock(cert.GetCertificateChain()))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing leaf certificate: %w", err)
+	}
+	if len(leaf.URIs) != 1 {
+		return nil, errNoSpiffeID
+	}
+	id, err := spiffeid.FromURI(leaf.URIs[0])
+	if err != nil {
+		return nil, errNoSpiffeID
+	}
+
+	// The certificate manager encodes private keys in PKCS #8, as the Workload API requires
+	key := firstBlock(cert.GetPrivateKey())
+	if key == nil {
+		return nil, errors.New("error decoding private key")
+	}
+
+	bundle, err := pemToDER(cert.GetTrustedCAs())
+	if err != nil {
+		return nil, fmt.Errorf("error decoding trusted CAs: %w", err)
+	}
+
+	return &workload.X509SVID{
+		SpiffeId:    id.String(),
+		X509Svid:    chain,
+		X509SvidKey: key,
+		Bundle:      bundle,
+	}, nil
+}
+
+// pemToDER returns the concatenated ASN.1 DER bytes of the certificates in the given PEM data
+func pemToDER(data []byte) ([]byte, error) {
+	var der []byte
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != certificate.TypeCertificate {
+			return nil, fmt.Errorf("unexpected PEM block of type %q", block.Type)
+		}
+		der = append(der, block.Bytes...)
+	}
+	if len(der) == 0 {
+		return nil, errors.New("no certificates found")
+	}
+	return der, nil
+}
+
+// firstBlock returns the bytes of the first PEM block in the given data
+func firstBlock(data []byte) []byte {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil
+	}
+	return block.Bytes
+}
@@ -0,0 +1,47 @@
+package workloadapi
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestPEMToDER(t *testing.T) {
+	assert := tassert.New(t)
+	certManager := newFakeCertManager(t, true)
+
+	cert, err := certManager.IssueCertificate()
+	assert.NoError(err)
+
+	// A chain of certificates is concatenated
+	der, err := pemToDER(append(cert.GetCertificateChain(), certManager.ca.GetCertificateChain()...))
+	assert.NoError(err)
+	leaf := firstBlock(cert.GetCertificateChain())
+	ca := firstBlock(certManager.ca.GetCertificateChain())
+	assert.Equal(append(append([]byte{}, leaf...), ca...), der)
+
+	// Private keys must never end up in a certificate chain
+	_, err = pemToDER(cert.GetPrivateKey())
+	assert.Error(err)
+
+	_, err = pemToDER([]byte("not PEM"))
+	assert.Error(err)
+}
+
+func TestToX509SVID(t *testing.T) {
+	assert := tassert.New(t)
+	certManager := newFakeCertManager(t, true)
+
+	cert, err := certManager.IssueCertificate()
+	assert.NoError(err)
+	svid, err := toX509SVID(cert)
+	assert.NoError(err)
+	assert.Equal("spiffe://cluster.local/sa/ns", svid.SpiffeId)
+	assert.Equal(firstBlock(cert.GetPrivateKey()), svid.X509SvidKey)
+
+	certManager.spiffeEnabled = false
+	cert, err = certManager.IssueCertificate()
+	assert.NoError(err)
+	_, err = toX509SVID(cert)
+	assert.ErrorIs(err, errNoSpiffeID)
+}
@@ -0,0 +1,138 @@
+// Package tracing traces the processing of the events received by the control plane with OpenTelemetry, from the
+// arrival of a Kubernetes event to the proxies acknowledging the configuration it resulted in. The spans are exported
+// to an OTLP collector when tracing is enabled, and discarded otherwise.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var log = logger.New("tracing")
+
+const (
+	// instrumentationName is the name of the tracer of the control plane
+	instrumentationName = "github.com/openservicemesh/osm"
+
+	// maxLinks is the maximum number of links of a span
+	maxLinks = 10
+)
+
+// Attribute keys of the spans
+const (
+	// ProxyUUIDKey is the UUID of the proxy a span is about
+	ProxyUUIDKey = attribute.Key("osm.proxy.uuid")
+
+	// ProxyIdentityKey is the service identity of the proxy a span is about
+	ProxyIdentityKey = attribute.Key("osm.proxy.identity")
+
+	// TypeURLKey is the xDS type URL a span is about
+	TypeURLKey = attribute.Key("osm.xds.type_url")
+)
+
+// Options are the options of the export of the spans
+type Options struct {
+	// Endpoint is the address of the OTLP gRPC collector the spans are exported to, ex. 'otel-collector:4317'.
+	// Tracing is disabled when empty.
+	Endpoint string
+
+	// Insecure disables the transport security of the connection to the collector
+	Insecure bool
+
+	// SamplingRatio is the ratio of the events whose processing is traced, between 0 and 1
+	SamplingRatio float64
+}
+
+// Setup sets up the export of the spans of the given service to the collector given by the options, if any. It returns
+// a function flushing the pending spans and stopping the export, to be called on shutdown.
+func Setup(ctx context.Context, serviceName string, opts Options) (func(context.Context) error, error) {
+	if opts.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	if opts.SamplingRatio < 0 || opts.SamplingRatio > 1 {
+		return nil, fmt.Errorf("invalid tracing sampling ratio %v, must be between 0 and 1", opts.SamplingRatio)
+	}
+
+	clientOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.Endpoint)}
+	if opts.Insecure {
+		clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating the OTLP exporter for %s: %w", opts.Endpoint, err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SamplingRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		log.Error().Err(err).Msg("Error exporting spans")
+	}))
+	log.Info().Msgf("Exporting spans to %s with a sampling ratio of %v", opts.Endpoint, opts.SamplingRatio)
+
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer of the spans of the control plane
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start starts a span descending from the span held by the given context, if any. Otherwise the returned span is a
+// non-recording span, so that only the processing of the events whose arrival is traced is traced.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	return Tracer().Start(ctx, name, opts...)
+}
+
+// EventSpan is the span of the arrival of an event, which the spans of its processing descend from or link to
+type EventSpan struct {
+	// SpanContext is the context of the span
+	SpanContext trace.SpanContext
+
+	// QueuedAt is when the event was queued for processing
+	QueuedAt time.Time
+}
+
+// NewEventSpan returns the EventSpan of the given span, or nil if the span is not sampled, so that the processing of
+// events which are not traced doesn't incur any cost.
+func NewEventSpan(span trace.Span) *EventSpan {
+	if !span.SpanContext().IsSampled() {
+		return nil
+	}
+	return &EventSpan{
+		SpanContext: span.SpanContext(),
+		QueuedAt:    time.Now(),
+	}
+}
+
+// Links returns the links to the given span contexts, ignoring the invalid ones, up to a maximum number of links
+func Links(spanContexts ...trace.SpanContext) []trace.Link {
+	var links []trace.Link
+	for _, sc := range spanContexts {
+		if !sc.IsValid() {
+			continue
+		}
+		if len(links) == maxLinks {
+			break
+		}
+		links = append(links, trace.Link{SpanContext: sc})
+	}
+	return links
+}
@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSetup(t *testing.T) {
+	a := tassert.New(t)
+
+	// Tracing is disabled without an endpoint
+	shutdown, err := Setup(context.Background(), "osm-controller", Options{})
+	a.NoError(err)
+	a.NoError(shutdown(context.Background()))
+
+	_, err = Setup(context.Background(), "osm-controller", Options{Endpoint: "collector:4317", SamplingRatio: 2})
+	a.Error(err)
+}
+
+func TestStart(t *testing.T) {
+	a := tassert.New(t)
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	// No span is started without a parent span
+	ctx, span := Start(context.Background(), "child")
+	a.False(span.SpanContext().IsValid())
+	a.False(span.IsRecording())
+	a.Nil(NewEventSpan(span))
+	span.End()
+	a.Equal(context.Background(), ctx)
+
+	ctx, parent := Tracer().Start(context.Background(), "parent")
+	eventSpan := NewEventSpan(parent)
+	a.NotNil(eventSpan)
+	a.Equal(parent.SpanContext(), eventSpan.SpanContext)
+
+	_, span = Start(ctx, "child")
+	span.End()
+	parent.End()
+
+	ended := recorder.Ended()
+	a.Len(ended, 2)
+	a.Equal("child", ended[0].Name())
+	a.Equal(parent.SpanContext().SpanID(), ended[0].Parent().SpanID())
+}
+
+func TestLinks(t *testing.T) {
+	a := tassert.New(t)
+
+	a.Nil(Links(trace.SpanContext{}))
+
+	var spanContexts []trace.SpanContext
+	for i := 0; i < maxLinks+2; i++ {
+		spanContexts = append(spanContexts, trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: trace.TraceID{1},
+			SpanID:  trace.SpanID{byte(i + 1)},
+		}), trace.SpanContext{})
+	}
+	links := Links(spanContexts...)
+	a.Len(links, maxLinks)
+	a.Equal(spanContexts[0], links[0].SpanContext)
+}
@@ -750,6 +750,8 @@ func (td *OsmTestData) LoadOSMImagesIntoKind() error {
 		constants.OSMBootstrapName,
 		"osm-preinstall",
 		"osm-healthcheck",
+		"osm-workload-api",
+		"osm-cni",
 	}
 
 	return td.LoadImagesToKind(imageNames)